@@ -31,13 +31,24 @@ func generateFeatureMatrix() error {
 			{"Registrar", "The provider has registrar capabilities to set nameservers for zones"},
 			{"ALIAS", "Provider supports some kind of ALIAS, ANAME or flattened CNAME record type"},
 			{"CAA", "Provider can manage CAA records"},
+			{"CSYNC", "Provider can manage CSYNC records"},
+			{"DHCID", "Provider can manage DHCID records"},
 			{"PTR", "Provider supports adding PTR records for reverse lookup zones"},
+			{"RP", "Provider can manage RP records"},
 			{"NAPTR", "Provider can manage NAPTR records"},
+			{"OPENPGPKEY", "Provider can manage OPENPGPKEY records"},
 			{"SRV", "Driver has explicitly implemented SRV record management"},
+			{"SMIMEA", "Provider can manage SMIMEA records"},
+			{"SOA", "Provider can manage the SOA record of a zone"},
 			{"SSHFP", "Provider can manage SSHFP records"},
 			{"TLSA", "Provider can manage TLSA records"},
 			{"TXTMulti", "Provider can manage TXT records with multiple strings"},
+			{"UNKNOWN", "Provider can manage records of any type, declared with UNKNOWN()"},
+			{"URI", "Provider can manage URI records"},
 			{"R53_ALIAS", "Provider supports Route 53 limited ALIAS"},
+			{"DNAME", "Provider can manage DNAME records"},
+			{"CERT", "Provider can manage CERT records"},
+			{"ROUTING", "Provider supports weighted, geo and failover record sets, declared with ROUTING()"},
 
 			{"dual host", "This provider is recommended for use in 'dual hosting' scenarios. Usually this means the provider allows full control over the apex NS records"},
 			{"create-domains", "This means the provider can automatically create domains that do not currently exist on your account. The 'dnscontrol create-domains' command will initialize any missing domains"},
@@ -74,13 +85,24 @@ func generateFeatureMatrix() error {
 		fm.SetSimple("Registrar", false, func() bool { return providers.RegistrarTypes[p] != nil })
 		setCap("ALIAS", providers.CanUseAlias)
 		setCap("CAA", providers.CanUseCAA)
+		setCap("CERT", providers.CanUseCERT)
+		setCap("CSYNC", providers.CanUseCSYNC)
+		setCap("DHCID", providers.CanUseDHCID)
+		setCap("DNAME", providers.CanUseDNAME)
 		setCap("NAPTR", providers.CanUseNAPTR)
+		setCap("OPENPGPKEY", providers.CanUseOPENPGPKEY)
 		setCap("PTR", providers.CanUsePTR)
 		setCap("R53_ALIAS", providers.CanUseRoute53Alias)
+		setCap("RP", providers.CanUseRP)
+		setCap("ROUTING", providers.CanUseRoutingPolicy)
+		setCap("SMIMEA", providers.CanUseSMIMEA)
+		setCap("SOA", providers.CanUseSOA)
 		setCap("SRV", providers.CanUseSRV)
 		setCap("SSHFP", providers.CanUseSSHFP)
 		setCap("TLSA", providers.CanUseTLSA)
 		setCap("TXTMulti", providers.CanUseTXTMulti)
+		setCap("UNKNOWN", providers.CanUseUNKNOWN)
+		setCap("URI", providers.CanUseURI)
 		setDoc("dual host", providers.DocDualHost, false)
 		setDoc("create-domains", providers.DocCreateDomains, true)
 
@@ -20,4 +20,7 @@ func main() {
 	if err := generateFeatureMatrix(); err != nil {
 		log.Fatal(err)
 	}
+	if err := generateTypeScript(); err != nil {
+		log.Fatal(err)
+	}
 }
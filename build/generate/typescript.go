@@ -0,0 +1,487 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// generateTypeScript writes types/dnscontrol.d.ts, the TypeScript
+// definitions of the functions of dnsconfig.js. They are made from
+// pkg/js/helpers.js, the functions that pkg/js/js.go adds to it, and the
+// documentation of docs/_functions.
+func generateTypeScript() error {
+	helpers, err := ioutil.ReadFile("pkg/js/helpers.js")
+	if err != nil {
+		return err
+	}
+	jsGo, err := ioutil.ReadFile("pkg/js/js.go")
+	if err != nil {
+		return err
+	}
+	docs, err := readFunctionDocs()
+	if err != nil {
+		return err
+	}
+
+	decls := helperDecls(string(helpers), docs)
+	for _, m := range regexp.MustCompile(`vm\.Set\("(\w+)", `).FindAllStringSubmatch(string(jsGo), -1) {
+		sig, ok := goBuiltins[m[1]]
+		if !ok {
+			return fmt.Errorf("pkg/js/js.go sets %s, which has no TypeScript signature in goBuiltins", m[1])
+		}
+		decls = append(decls, tsDecl{name: m[1], code: sig})
+	}
+	sort.Slice(decls, func(i, j int) bool { return decls[i].name < decls[j].name })
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(tsHeader)
+	for _, d := range decls {
+		doc := d.doc
+		if fd, ok := docs[d.name]; ok {
+			doc = fd.summary
+		}
+		buf.WriteString("\n")
+		buf.WriteString(d.types)
+		if doc != "" {
+			fmt.Fprintf(buf, "/** %s */\n", doc)
+		}
+		buf.WriteString(d.code)
+		buf.WriteString("\n")
+	}
+	return ioutil.WriteFile("types/dnscontrol.d.ts", buf.Bytes(), 0644)
+}
+
+const tsHeader = `// Code generated by "go generate"; DO NOT EDIT.
+
+// TypeScript definitions of the functions of dnsconfig.js. See
+// https://stackexchange.github.io/dnscontrol/typescript
+
+/** A record or a setting of a domain, given to D(). */
+type DomainModifier = object;
+
+/** A setting of a record, given after the arguments of a record function such as A(). */
+type RecordModifier = object;
+
+/** underscore.js, which dnsconfig.js can use. */
+declare const _: any;
+`
+
+// goBuiltins are the signatures of the functions that pkg/js/js.go adds
+// to the javascript interpreter.
+var goBuiltins = map[string]string{
+	"require":         "declare function require(path: string): any;",
+	"REV":             "declare function REV(address: string): string;",
+	"IP_ADD":          "declare function IP_ADD(address: string, n: number): string;",
+	"CIDR_HOSTS":      "declare function CIDR_HOSTS(cidr: string): string[];",
+	"IPV6_EXPAND":     "declare function IPV6_EXPAND(address: string): string;",
+	"OPENPGPKEY_NAME": "declare function OPENPGPKEY_NAME(address: string): string;",
+	"SMIMEA_NAME":     "declare function SMIMEA_NAME(address: string): string;",
+	"TLSA_HASH":       "declare function TLSA_HASH(file: string, selector: number, matchingtype: number): string;",
+	"SSHFP_HASH":      "declare function SSHFP_HASH(file: string, host: string, types: number[]): string[][];",
+	"MTA_STS_POLICY":  "declare function MTA_STS_POLICY(mode: string, mx: string[], max_age: number): { policy: string; id: string };",
+	"FETCH":           "declare function FETCH(url: string): string;",
+	"DKIM_KEY":        "declare function DKIM_KEY(key: string): { k: string; p: string };",
+	"ZONE_RECORDS":    "declare function ZONE_RECORDS(file: string, origin: string): any[];",
+	"ENV":             "/** The environment variables allowed with --allow-env. */\ndeclare const ENV: { readonly [name: string]: string | undefined };",
+	"CLI":             "/** The variables given with -v key=value, and CLI_DEFAULTS(). */\ndeclare const CLI: { [key: string]: any };",
+}
+
+// builderFieldTypes are the types of the options of the builders. Those
+// not listed are any.
+var builderFieldTypes = map[string]string{
+	"SPF_BUILDER.parts":      "string[]",
+	"SPF_BUILDER.flatten":    "string[]",
+	"SPF_BUILDER.maxLookups": "number",
+
+	"CAA_BUILDER.iodef_critical":     "boolean",
+	"CAA_BUILDER.issue":              "string | string[]",
+	"CAA_BUILDER.issuewild":          "string | string[]",
+	"CAA_BUILDER.issue_critical":     "boolean",
+	"CAA_BUILDER.issuewild_critical": "boolean",
+
+	"TLSA_BUILDER.file":         "string | string[]",
+	"TLSA_BUILDER.usage":        "number",
+	"TLSA_BUILDER.selector":     "number",
+	"TLSA_BUILDER.matchingtype": "number",
+
+	"SSHFP_BUILDER.file": "string | string[]",
+	"SSHFP_BUILDER.type": "number | number[]",
+
+	"DMARC_BUILDER.policy":           "'none' | 'quarantine' | 'reject'",
+	"DMARC_BUILDER.subdomain_policy": "'none' | 'quarantine' | 'reject'",
+	"DMARC_BUILDER.alignment_dkim":   "'strict' | 'relaxed'",
+	"DMARC_BUILDER.alignment_spf":    "'strict' | 'relaxed'",
+	"DMARC_BUILDER.pct":              "number",
+	"DMARC_BUILDER.rua":              "string | string[]",
+	"DMARC_BUILDER.ruf":              "string | string[]",
+	"DMARC_BUILDER.failure_options":  "string | string[]",
+	"DMARC_BUILDER.report_interval":  "number | string",
+
+	"MTA_STS_BUILDER.mode":    "'enforce' | 'testing' | 'none'",
+	"MTA_STS_BUILDER.mx":      "string | string[]",
+	"MTA_STS_BUILDER.m365":    "boolean",
+	"MTA_STS_BUILDER.max_age": "number | string",
+	"MTA_STS_BUILDER.host":    "string | string[]",
+	"MTA_STS_BUILDER.rua":     "string | string[]",
+
+	"ttl": "number | string",
+}
+
+// paramTypes are the types of the parameters of the functions of
+// helpers.js that aren't made with recordBuilder(). Those not listed are
+// any.
+var paramTypes = map[string]string{
+	"APPLY_TEMPLATE.name":          "string",
+	"APPLY_TEMPLATE.params":        "{ [param: string]: string | number }",
+	"ASSERT_EQUAL.message":         "string",
+	"ASSERT_RECORDS.domain":        "string",
+	"ASSERT_THROWS.fn":             "() => any",
+	"ASSERT_THROWS.text":           "string",
+	"CLASSLESS_DELEGATE.cidr":      "string",
+	"DKIM.selector":                "string",
+	"DKIM.key":                     "string",
+	"DELEGATE.name":                "string",
+	"IMPORT_ZONE.file":             "string",
+	"CLI_DEFAULTS.defaults":        "{ [key: string]: any }",
+	"D.name":                       "string",
+	"D.registrar":                  "string",
+	"D_EXTEND.name":                "string",
+	"DefaultTTL.v":                 "number | string",
+	"DefaultTTL.types":             "string | string[]",
+	"DnsProvider.name":             "string",
+	"DnsProvider.nsCount":          "number",
+	"HEALTH_CHECK.check":           "string",
+	"HEALTH_CHECK.timeout":         "number | string",
+	"IGNORE.name":                  "string",
+	"IGNORE_NAME.pattern":          "string",
+	"IGNORE_NAME.types":            "string | string[]",
+	"IGNORE_TARGET.pattern":        "string",
+	"IGNORE_TARGET.types":          "string | string[]",
+	"IP.dot":                       "string",
+	"MAX_CHANGES.n":                "number",
+	"MAX_DELETES.n":                "number",
+	"NAMESERVER.name":              "string",
+	"NAMESERVER_TTL.v":             "number | string",
+	"NewDnsProvider.name":          "string",
+	"NewDnsProvider.type":          "string",
+	"NewDnsProvider.meta":          "object",
+	"NewRegistrar.name":            "string",
+	"NewRegistrar.type":            "string",
+	"NewRegistrar.meta":            "object",
+	"OWNER.name":                   "string",
+	"PENDING_VERIFICATION.service": "string",
+	"PENDING_VERIFICATION.token":   "string",
+	"PRIORITY_HINT.v":              "'first' | 'last'",
+	"MAX_CNAME_CHAIN.n":            "number",
+	"NOTE.text":                    "string",
+	"PROVIDERS.names":              "string",
+	"R53_ZONE.zone_id":             "string",
+	"REGISTRAR_DS.keytag":          "number",
+	"REGISTRAR_DS.algorithm":       "number",
+	"REGISTRAR_DS.digesttype":      "number",
+	"REGISTRAR_DS.digest":          "string",
+	"REGISTRAR_LOCK.state":         "'on' | 'off'",
+	"REPLICATE_FROM.name":          "string",
+	"ROUTING.options":              "{ set: string; weight?: number; geo?: string; failover?: 'primary' | 'secondary'; health_check?: string }",
+	"TEMPLATE.name":                "string",
+	"TTL.v":                        "number | string",
+	"TTL_POLICY.policy":            "{ ns_ttl?: number | string; negative_ttl?: number | string }",
+}
+
+// globalReturnTypes are the return types of the functions of
+// docs/_functions/global that don't give one.
+var globalReturnTypes = map[string]string{
+	"ASSERT_EQUAL":   "void",
+	"ASSERT_RECORDS": "void",
+	"ASSERT_THROWS":  "void",
+	"CLI_DEFAULTS":   "void",
+	"D":              "void",
+	"D_EXTEND":       "void",
+	"DEFAULTS":       "void",
+	"IP":             "number",
+	"POP_DEFAULTS":   "void",
+	"PUSH_DEFAULTS":  "void",
+	"TEMPLATE":       "void",
+	"TTL_POLICY":     "void",
+	"WITH_DEFAULTS":  "void",
+}
+
+type tsDecl struct {
+	name  string
+	doc   string // Used if the function has no documentation.
+	types string // Declarations that code uses, such as an interface.
+	code  string
+}
+
+var (
+	reFunction      = regexp.MustCompile(`(?m)^function ([A-Z]\w*)\(([^)]*)\) \{\n((?:.*\n)*?)\}\n`)
+	reRecordBuilder = regexp.MustCompile(`(?m)^var ([A-Z]\w*) = recordBuilder\('\w+'(, \{)?`)
+	reObject        = regexp.MustCompile(`(?m)^var ([A-Z]\w*) = (\{.*\});(?: // (.*))?`)
+	reOtherVar      = regexp.MustCompile(`(?m)^var ([A-Z]\w*) = `)
+	reArg           = regexp.MustCompile(`\['(\w+)'(?:,\s*([\w.]+))?\]`)
+	reBuilderOpt    = regexp.MustCompile(`^// (\w+(?:, \w+)*): (.*)`)
+	reValueField    = regexp.MustCompile(`value\.(\w+)`)
+)
+
+// argTypes are the types of the arguments that recordBuilder() checks
+// with these functions.
+var argTypes = map[string]string{
+	"_.isString":      "string",
+	"_.isNumber":      "number",
+	"isStringOrArray": "string | string[]",
+}
+
+// helperDecls returns the declarations of the functions and variables
+// of helpers.js whose names start with a capital letter.
+func helperDecls(js string, docs map[string]*functionDoc) []tsDecl {
+	var decls []tsDecl
+	seen := map[string]bool{}
+	for _, m := range reFunction.FindAllStringSubmatchIndex(js, -1) {
+		name, params, body := js[m[2]:m[3]], js[m[4]:m[5]], js[m[6]:m[7]]
+		seen[name] = true
+		if strings.HasSuffix(name, "_BUILDER") {
+			// The comment of a builder is followed by a blank line.
+			decls = append(decls, builderDecl(name, commentAbove(js, m[0]-1), body))
+			continue
+		}
+		d := tsDecl{name: name, doc: joinComment(commentAbove(js, m[0]))}
+		fd := docs[name]
+		if fd == nil {
+			fd = &functionDoc{}
+		}
+		if fd.kind == "domain" && len(fd.params) == 0 {
+			// A modifier that is used without calling it, like NO_PURGE.
+			d.code = fmt.Sprintf("declare const %s: DomainModifier;", name)
+			decls = append(decls, d)
+			continue
+		}
+		var args []string
+		for _, p := range strings.Split(params, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				t, ok := paramTypes[name+"."+p]
+				if !ok {
+					t = "any"
+				}
+				args = append(args, p+"?: "+t)
+			}
+		}
+		if strings.Contains(body, "arguments") {
+			rest := "modifiers"
+			if n := len(fd.params); n != 0 && strings.HasSuffix(fd.params[n-1], "...") {
+				rest = strings.TrimSuffix(fd.params[n-1], "...")
+			}
+			t, ok := paramTypes[name+"."+rest]
+			if !ok {
+				t = "any"
+			}
+			args = append(args, "..."+rest+": "+t+"[]")
+		}
+		d.code = fmt.Sprintf("declare function %s(%s): %s;", name, strings.Join(args, ", "), fd.returnType(name))
+		decls = append(decls, d)
+	}
+	for _, m := range reRecordBuilder.FindAllStringSubmatchIndex(js, -1) {
+		name := js[m[2]:m[3]]
+		seen[name] = true
+		args := []string{"name: string", "target: any"}
+		if m[4] != -1 {
+			if a := recordBuilderArgs(js[m[1]:]); a != nil {
+				args = a
+			}
+		}
+		args = append(args, "...modifiers: RecordModifier[]")
+		decls = append(decls, tsDecl{name: name, doc: joinComment(commentAbove(js, m[0])),
+			code: fmt.Sprintf("declare function %s(%s): DomainModifier;", name, strings.Join(args, ", "))})
+	}
+	for _, m := range reObject.FindAllStringSubmatchIndex(js, -1) {
+		name := js[m[2]:m[3]]
+		seen[name] = true
+		d := tsDecl{name: name, code: fmt.Sprintf("declare const %s: %s;", name, js[m[4]:m[5]])}
+		if m[6] != -1 {
+			d.doc = js[m[6]:m[7]]
+		}
+		decls = append(decls, d)
+	}
+	for _, m := range reOtherVar.FindAllStringSubmatchIndex(js, -1) {
+		if name := js[m[2]:m[3]]; !seen[name] {
+			decls = append(decls, tsDecl{name: name, doc: joinComment(commentAbove(js, m[0])),
+				code: fmt.Sprintf("declare const %s: RecordModifier;", name)})
+		}
+	}
+	return decls
+}
+
+// recordBuilderArgs returns the arguments listed by "args: [...]" at the
+// start of the options of a recordBuilder(), or nil if there are none.
+func recordBuilderArgs(opts string) []string {
+	i := strings.Index(opts, "args: [")
+	if i == -1 || strings.Contains(opts[:i], "\n});") {
+		return nil
+	}
+	depth, end := 0, 0
+	for j, c := range opts[i+len("args: "):] {
+		if c == '[' {
+			depth++
+		} else if c == ']' {
+			depth--
+		}
+		if depth == 0 {
+			end = i + len("args: ") + j
+			break
+		}
+	}
+	var args []string
+	for _, a := range reArg.FindAllStringSubmatch(opts[i:end], -1) {
+		t, ok := argTypes[a[2]]
+		if !ok {
+			t = "any"
+		}
+		args = append(args, a[1]+": "+t)
+	}
+	return args
+}
+
+// builderDecl declares the builder name, with an interface of its
+// options made of its comment, "NAME takes an object:" followed by a
+// line for each option, and of the options that its body uses.
+func builderDecl(name string, comment []string, body string) tsDecl {
+	iface := ""
+	for _, w := range strings.Split(strings.ToLower(name), "_") {
+		iface += strings.ToUpper(w[:1]) + w[1:]
+	}
+	iface += "Options"
+	var fields, notes []string
+	seen := map[string]bool{}
+	addField := func(f, doc string) {
+		if seen[f] {
+			return
+		}
+		seen[f] = true
+		t, ok := builderFieldTypes[name+"."+f]
+		if !ok {
+			if t, ok = builderFieldTypes[f]; !ok {
+				t = "any"
+			}
+		}
+		if doc != "" {
+			fields = append(fields, fmt.Sprintf("    /** %s */", strings.Replace(doc, "*/", "* /", -1)))
+		}
+		fields = append(fields, fmt.Sprintf("    %s?: %s;", f, t))
+	}
+	for i := 0; i < len(comment); i++ {
+		m := reBuilderOpt.FindStringSubmatch(comment[i])
+		if m == nil {
+			if !strings.HasSuffix(comment[i], "takes an object:") {
+				notes = append(notes, strings.TrimSpace(strings.TrimPrefix(comment[i], "//")))
+			}
+			continue
+		}
+		doc := m[2]
+		// Indented lines continue the description.
+		for i+1 < len(comment) && strings.HasPrefix(comment[i+1], "//  ") {
+			i++
+			doc += " " + strings.TrimSpace(strings.TrimPrefix(comment[i], "//"))
+		}
+		for _, f := range strings.Split(m[1], ", ") {
+			addField(f, doc)
+		}
+	}
+	for _, m := range reValueField.FindAllStringSubmatch(body, -1) {
+		addField(m[1], "")
+	}
+	doc := fmt.Sprintf("The options of %s().", name)
+	if len(notes) != 0 {
+		doc += " " + strings.Join(notes, " ")
+	}
+	return tsDecl{name: name, doc: fmt.Sprintf("%s returns the records that options describe.", name),
+		types: fmt.Sprintf("/** %s */\ninterface %s {\n%s\n}\n", doc, iface, strings.Join(fields, "\n")),
+		code:  fmt.Sprintf("declare function %s(options: %s): DomainModifier[];", name, iface)}
+}
+
+// commentAbove returns the lines of the // comment that ends right
+// before offset i of js.
+func commentAbove(js string, i int) []string {
+	lines := strings.Split(js[:i], "\n")
+	lines = lines[:len(lines)-1] // The start of the line at i.
+	start := len(lines)
+	for start > 0 && strings.HasPrefix(lines[start-1], "//") {
+		start--
+	}
+	return lines[start:]
+}
+
+func joinComment(lines []string) string {
+	var words []string
+	for _, l := range lines {
+		words = append(words, strings.TrimSpace(strings.TrimPrefix(l, "//")))
+	}
+	return strings.Join(words, " ")
+}
+
+type functionDoc struct {
+	kind    string // domain, global or record.
+	params  []string
+	ret     string // The "return:" of the front matter.
+	summary string // The first paragraph.
+}
+
+func (fd *functionDoc) returnType(name string) string {
+	switch {
+	case fd.ret != "":
+		return fd.ret
+	case fd.kind == "domain":
+		return "DomainModifier"
+	case fd.kind == "record":
+		return "RecordModifier"
+	case globalReturnTypes[name] != "":
+		return globalReturnTypes[name]
+	}
+	return "any"
+}
+
+var reMarkdownLink = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+
+// readFunctionDocs reads docs/_functions/*/*.md, by function name.
+func readFunctionDocs() (map[string]*functionDoc, error) {
+	files, err := filepath.Glob("docs/_functions/*/*.md")
+	if err != nil {
+		return nil, err
+	}
+	docs := map[string]*functionDoc{}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		parts := strings.SplitN(strings.Replace(string(data), "\r\n", "\n", -1), "---\n", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("%s: no front matter", file)
+		}
+		fd := &functionDoc{kind: filepath.Base(filepath.Dir(file))}
+		name := ""
+		for _, l := range strings.Split(parts[1], "\n") {
+			if strings.HasPrefix(l, "name: ") {
+				name = strings.TrimPrefix(l, "name: ")
+			} else if strings.HasPrefix(l, "return: ") {
+				fd.ret = strings.TrimPrefix(l, "return: ")
+			} else if strings.HasPrefix(l, "  - ") {
+				fd.params = append(fd.params, strings.TrimPrefix(l, "  - "))
+			}
+		}
+		var para []string
+		for _, l := range strings.Split(strings.TrimSpace(parts[2]), "\n") {
+			if strings.TrimSpace(l) == "" || strings.HasPrefix(l, "{%") {
+				break
+			}
+			para = append(para, strings.TrimSpace(l))
+		}
+		fd.summary = reMarkdownLink.ReplaceAllString(strings.Join(para, " "), "$1")
+		fd.summary = strings.Replace(fd.summary, "*/", "* /", -1)
+		docs[name] = fd
+	}
+	return docs, nil
+}
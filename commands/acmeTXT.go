@@ -0,0 +1,170 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/normalize"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/StackExchange/dnscontrol/providers"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var _ = cmd(catUtils, func() *cli.Command {
+	var args AcmeTXTArgs
+	return &cli.Command{
+		Name:      "acme-txt",
+		Usage:     "create or remove an _acme-challenge TXT record with the DNS providers of its domain, for ACME DNS-01 challenges",
+		ArgsUsage: "set|clear fqdn value",
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() != 3 {
+				return cli.NewExitError("Expected 3 arguments: set or clear, the name, and the value of the challenge", 1)
+			}
+			args.Action, args.FQDN, args.Value = ctx.Args().Get(0), ctx.Args().Get(1), ctx.Args().Get(2)
+			return exit(AcmeTXT(args))
+		},
+		Flags: args.flags(),
+	}
+}())
+
+// AcmeTXTArgs contains all data/flags needed to run acme-txt, independently of CLI.
+type AcmeTXTArgs struct {
+	GetDNSConfigArgs
+	GetCredentialsArgs
+	Providers string
+	TTL       int
+
+	Action string // "set" or "clear".
+	FQDN   string // With or without the _acme-challenge label.
+	Value  string
+}
+
+func (args *AcmeTXTArgs) flags() []cli.Flag {
+	flags := args.GetDNSConfigArgs.flags()
+	flags = append(flags, args.GetCredentialsArgs.flags()...)
+	flags = append(flags, cli.StringFlag{
+		Name:        "providers",
+		Destination: &args.Providers,
+		Usage:       `DNS providers to use (comma separated list of names or types); default is all the DNS providers of the domain`,
+	})
+	flags = append(flags, cli.IntFlag{
+		Name:        "ttl",
+		Destination: &args.TTL,
+		Value:       120,
+		Usage:       "TTL of the TXT record",
+	})
+	return flags
+}
+
+// acmeLabel is the label of the TXT records of DNS-01 challenges.
+const acmeLabel = "_acme-challenge"
+
+// AcmeTXT implements the acme-txt subcommand. It adds or removes one
+// value of the _acme-challenge TXT records of a name with each DNS
+// provider of its domain, leaving the other records as they are, even if
+// they differ from dnsconfig.js.
+//
+// Providers that can list the records of a zone are given those records,
+// with the challenge added or removed. Others must leave the records to
+// the differ, which patches the records of the zone; those that read
+// dc.Records themselves, such as to upload the whole zone, would lose the
+// others.
+func AcmeTXT(args AcmeTXTArgs) error {
+	if args.Action != "set" && args.Action != "clear" {
+		return errors.Errorf("unknown action %q: want set or clear", args.Action)
+	}
+	fqdn := strings.ToLower(strings.TrimSuffix(args.FQDN, "."))
+	if !strings.HasPrefix(fqdn, acmeLabel+".") {
+		fqdn = acmeLabel + "." + fqdn
+	}
+
+	cfg, err := GetDNSConfig(args.GetDNSConfigArgs)
+	if err != nil {
+		return err
+	}
+	errs := normalize.NormalizeAndValidateConfig(cfg)
+	if PrintValidationErrors(errs) {
+		return errors.Errorf("Exiting due to validation errors")
+	}
+	domain := cfg.DomainContainingFQDN(fqdn)
+	if domain == nil {
+		return errors.Errorf("no domain of %s contains %s", args.JSFile, fqdn)
+	}
+	if _, err := InitializeProviders(args.CredsFile, cfg, false); err != nil {
+		return err
+	}
+
+	txt := &models.RecordConfig{Type: "TXT", TTL: uint32(args.TTL), Metadata: map[string]string{}}
+	txt.SetLabelFromFQDN(fqdn, domain.Name)
+	txt.SetTargetTXT(args.Value)
+
+	filter := FilterArgs{Providers: args.Providers}
+	ran := 0
+	for _, provider := range domain.DNSProviderInstances {
+		if !filter.shouldRunProvider(provider.Name, provider.ProviderType, domain) {
+			continue
+		}
+		lister, canList := provider.Driver.(providers.ZoneRecordLister)
+		if !canList && !providers.ProviderHasCabability(provider.ProviderType, providers.CanPatchRecords) {
+			return errors.Errorf("acme-txt can't use %s: %s can't change a record without sending the whole zone", provider.Name, provider.ProviderType)
+		}
+		ran++
+		dc, err := domain.Copy()
+		if err != nil {
+			return err
+		}
+		// Only the challenge changes: not the records that differ from
+		// dnsconfig.js, nor those of other owners. The challenge may be one
+		// that IGNORE_NAME() hides from push.
+		dc.Records, dc.Owner, dc.KeepUnknown = nil, "", false
+		dc.IgnoredLabels, dc.IgnoredNames, dc.IgnoredTargets = nil, nil, nil
+		if canList {
+			existing, err := lister.GetZoneRecords(dc.Name)
+			if err != nil {
+				return errors.Wrapf(err, "%s", provider.Name)
+			}
+			dc.Records = patchChallenge(existing, txt, args.Action == "set")
+		} else if args.Action == "set" {
+			dc.Patch, dc.Records = true, models.Records{txt}
+		} else {
+			dc.Patch, dc.DeleteRecords = true, models.Records{txt}
+		}
+		corrections, err := provider.Driver.GetDomainCorrections(dc)
+		if err != nil {
+			return errors.Wrapf(err, "%s", provider.Name)
+		}
+		if len(corrections) == 0 {
+			printer.Printf("%s: nothing to change\n", provider.Name)
+		}
+		for _, c := range corrections {
+			printer.Printf("%s: %s\n", provider.Name, strings.TrimSpace(c.Msg))
+			if c.F == nil {
+				continue
+			}
+			if err := c.F(); err != nil {
+				return errors.Wrapf(err, "%s", provider.Name)
+			}
+		}
+	}
+	if ran == 0 {
+		return errors.Errorf("no DNS provider of %s matches --providers %q", domain.Name, args.Providers)
+	}
+	return nil
+}
+
+// patchChallenge returns the records of a zone with the challenge txt
+// added (if set) or removed, as the differ does for the Patch of a domain.
+func patchChallenge(existing models.Records, txt *models.RecordConfig, set bool) models.Records {
+	var records models.Records
+	for _, r := range existing {
+		if r.Key() == txt.Key() && r.GetTargetCombined() == txt.GetTargetCombined() {
+			continue
+		}
+		records = append(records, r)
+	}
+	if set {
+		records = append(records, txt)
+	}
+	return records
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/models"
+)
+
+func TestPatchChallenge(t *testing.T) {
+	rec := func(typ, label, target string) *models.RecordConfig {
+		r := &models.RecordConfig{Type: typ, TTL: 300}
+		r.SetLabel(label, "example.com")
+		if typ == "TXT" {
+			r.SetTargetTXT(target)
+		} else {
+			r.SetTarget(target)
+		}
+		return r
+	}
+	www := rec("A", "www", "192.0.2.1")
+	wildcard := rec("TXT", "_acme-challenge.www", "wildcard")
+	txt := rec("TXT", "_acme-challenge.www", "apex")
+	existing := models.Records{www, wildcard}
+
+	show := func(records models.Records) string {
+		var list []string
+		for _, r := range records {
+			list = append(list, r.GetLabel()+" "+r.GetTargetField())
+		}
+		return strings.Join(list, ", ")
+	}
+	if got, want := show(patchChallenge(existing, txt, true)), "www 192.0.2.1, _acme-challenge.www wildcard, _acme-challenge.www apex"; got != want {
+		t.Errorf("set: got %s, want %s", got, want)
+	}
+	if got, want := show(patchChallenge(models.Records{www, wildcard, txt}, txt, false)), "www 192.0.2.1, _acme-challenge.www wildcard"; got != want {
+		t.Errorf("clear: got %s, want %s", got, want)
+	}
+	if got, want := show(patchChallenge(existing, txt, false)), "www 192.0.2.1, _acme-challenge.www wildcard"; got != want {
+		t.Errorf("clear when already cleared: got %s, want %s", got, want)
+	}
+}
+
+func TestAcmeTXTUnpatchable(t *testing.T) {
+	pargs, cleanup := writeConfig(t, daemonConfig, nil)
+	defer cleanup()
+	args := AcmeTXTArgs{GetDNSConfigArgs: pargs.GetDNSConfigArgs, GetCredentialsArgs: pargs.GetCredentialsArgs,
+		Action: "set", FQDN: "www.example.com", Value: "challenge", TTL: 120}
+	// FAKE can neither list the records of a zone nor be patched.
+	err := AcmeTXT(args)
+	if err == nil || !strings.Contains(err.Error(), "can't change a record without sending the whole zone") {
+		t.Errorf("err is %v", err)
+	}
+	if got := registeredFake.ran.String(); got != "" {
+		t.Errorf("ran %q", got)
+	}
+}
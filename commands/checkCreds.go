@@ -0,0 +1,211 @@
+package commands
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/StackExchange/dnscontrol/providers"
+	"github.com/StackExchange/dnscontrol/providers/config"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var _ = cmd(catUtils, func() *cli.Command {
+	var args CheckCredsArgs
+	return &cli.Command{
+		Name:      "check-creds",
+		Usage:     "check that the credentials of providers work, without changing anything",
+		ArgsUsage: "[provider...]",
+		Action: func(ctx *cli.Context) error {
+			args.Names = ctx.Args()
+			return exit(CheckCreds(args))
+		},
+		Flags: args.flags(),
+	}
+}())
+
+// CheckCredsArgs contains all data/flags needed to run check-creds, independently of CLI.
+type CheckCredsArgs struct {
+	GetDNSConfigArgs
+	GetCredentialsArgs
+	Names []string // Providers to check, as named in dnsconfig.js; default all.
+}
+
+func (args *CheckCredsArgs) flags() []cli.Flag {
+	flags := args.GetDNSConfigArgs.flags()
+	flags = append(flags, args.GetCredentialsArgs.flags()...)
+	return flags
+}
+
+// The results of a credential check.
+const (
+	credsOK        = "OK"
+	credsMissing   = "MISSING"   // No creds.json entry, and the provider needs one.
+	credsInvalid   = "INVALID"   // Rejected: wrong, expired or revoked.
+	credsForbidden = "FORBIDDEN" // Accepted, but not allowed to read DNS.
+	credsError     = "ERROR"     // Anything else, for example a network error.
+	credsUnchecked = "UNCHECKED" // Created, but the provider has no cheap call to check.
+)
+
+// credsCheck is a provider to check, with the call that checks it.
+type credsCheck struct {
+	name, kind, ptype string
+	check             func() error // Returns errUnchecked if there is no cheap call.
+}
+
+// CheckCreds implements the check-creds subcommand. For each provider of
+// dnsconfig.js, it creates the provider with its creds.json entry and
+// makes a call that only reads (see checkDNSProvider). Registrars are
+// only created.
+func CheckCreds(args CheckCredsArgs) error {
+	cfg, err := GetDNSConfig(args.GetDNSConfigArgs)
+	if err != nil {
+		return err
+	}
+	creds, err := config.LoadProviderConfigs(args.CredsFile)
+	if err != nil {
+		return err
+	}
+
+	firstDomain := map[string]string{}
+	for _, d := range cfg.Domains {
+		for name := range d.DNSProviderNames {
+			if _, ok := firstDomain[name]; !ok {
+				firstDomain[name] = d.Name
+			}
+		}
+	}
+	seen := map[string]bool{}
+	want := func(name string) bool {
+		seen[name] = true
+		return len(args.Names) == 0 || contains(args.Names, name)
+	}
+	checks := []*credsCheck{}
+	for _, p := range cfg.DNSProviders {
+		if !want(p.Name) {
+			continue
+		}
+		p := p
+		c := &credsCheck{name: p.Name, kind: "DNS provider", ptype: p.Type}
+		c.check = func() error {
+			prov, err := providers.CreateDNSProvider(p.Type, creds[p.Name], p.Metadata)
+			if err != nil {
+				return err
+			}
+			return checkDNSProvider(prov, p.Type, firstDomain[p.Name])
+		}
+		checks = append(checks, c)
+	}
+	for _, r := range cfg.Registrars {
+		if !want(r.Name) {
+			continue
+		}
+		r := r
+		checks = append(checks, &credsCheck{name: r.Name, kind: "registrar", ptype: r.Type, check: func() error {
+			if _, err := providers.CreateRegistrar(r.Type, creds[r.Name]); err != nil {
+				return err
+			}
+			return errUnchecked
+		}})
+	}
+	unknown := []string{}
+	for _, n := range args.Names {
+		if !seen[n] {
+			unknown = append(unknown, n)
+		}
+	}
+	if len(unknown) != 0 {
+		sort.Strings(unknown)
+		return errors.Errorf("no provider named %s in %s", strings.Join(unknown, ", "), args.JSFile)
+	}
+
+	failed := 0
+	for _, c := range checks {
+		status, detail := credsStatus(c.check(), creds[c.name] != nil)
+		if status != credsOK && status != credsUnchecked {
+			failed++
+		}
+		printer.Printf("%-10s %s %s (%s)", status, c.kind, c.name, c.ptype)
+		if detail != "" {
+			printer.Printf(": %s", detail)
+		}
+		printer.Printf("\n")
+	}
+	if failed != 0 {
+		return errors.Errorf("%d of %d providers failed the credentials check", failed, len(checks))
+	}
+	return nil
+}
+
+var errUnchecked = errors.New("unchecked")
+
+// checkDNSProvider makes the call that checks the credentials of prov, of
+// type ptype: ListZones if it has it, else GetZoneRecords of domain, the
+// first domain that uses it. Most providers return the nameservers they
+// always use without calling their API, so GetNameservers of domain only
+// counts for those that are CanCheckCredsWithNameservers. It returns
+// errUnchecked if there is no such call.
+func checkDNSProvider(prov providers.DNSServiceProvider, ptype, domain string) error {
+	if lister, ok := prov.(providers.ZoneLister); ok {
+		_, err := lister.ListZones()
+		return err
+	}
+	if domain == "" {
+		return errUnchecked
+	}
+	if lister, ok := prov.(providers.ZoneRecordLister); ok {
+		_, err := lister.GetZoneRecords(domain)
+		return err
+	}
+	if providers.ProviderHasCabability(ptype, providers.CanCheckCredsWithNameservers) {
+		_, err := prov.GetNameservers(domain)
+		return err
+	}
+	return errUnchecked
+}
+
+// credsStatus returns the result of a check that returned err, and the
+// details to print with it.
+func credsStatus(err error, haveEntry bool) (status, detail string) {
+	switch err {
+	case nil:
+		return credsOK, ""
+	case errUnchecked:
+		return credsUnchecked, "created, but there is no read-only call to check the credentials with"
+	}
+	return classifyCredsError(err, haveEntry), err.Error()
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyCredsError guesses from the error of a provider why its
+// credentials were refused. Providers don't return typed errors, so this
+// goes by the HTTP status codes and wording APIs commonly use.
+func classifyCredsError(err error, haveEntry bool) string {
+	msg := strings.ToLower(err.Error())
+	has := func(words ...string) bool {
+		for _, w := range words {
+			if strings.Contains(msg, w) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case !haveEntry:
+		return credsMissing
+	case has("403", "forbidden", "permission", "not authorized to", "scope", "accessdenied", "access denied"):
+		return credsForbidden
+	case has("401", "unauthorized", "unauthenticated", "expired", "invalid token", "invalid api", "invalid credentials", "invalidclienttokenid", "signaturedoesnotmatch", "authentication"):
+		return credsInvalid
+	}
+	return credsError
+}
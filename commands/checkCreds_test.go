@@ -0,0 +1,156 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/providers"
+	"github.com/pkg/errors"
+)
+
+func init() {
+	providers.RegisterDomainServiceProviderType("FAKECREDS", nil)
+	providers.RegisterDomainServiceProviderType("FAKECREDSNS", nil, providers.CanCheckCredsWithNameservers)
+}
+
+// credsProvider is a DNS provider whose calls return err, and note that
+// they were made.
+type credsProvider struct {
+	err   error
+	calls []string
+}
+
+func (p *credsProvider) GetNameservers(domain string) ([]*models.Nameserver, error) {
+	p.calls = append(p.calls, "GetNameservers "+domain)
+	return nil, p.err
+}
+
+func (p *credsProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	p.calls = append(p.calls, "GetDomainCorrections")
+	return nil, p.err
+}
+
+type credsZoneLister struct{ credsProvider }
+
+func (p *credsZoneLister) ListZones() ([]string, error) {
+	p.calls = append(p.calls, "ListZones")
+	return nil, p.err
+}
+
+type credsRecordLister struct{ credsProvider }
+
+func (p *credsRecordLister) GetZoneRecords(domain string) (models.Records, error) {
+	p.calls = append(p.calls, "GetZoneRecords "+domain)
+	return nil, p.err
+}
+
+// newCredsProvider returns a provider of the kind given, and its calls.
+func newCredsProvider(kind string, err error) (providers.DNSServiceProvider, *credsProvider) {
+	switch kind {
+	case "zones":
+		p := &credsZoneLister{credsProvider{err: err}}
+		return p, &p.credsProvider
+	case "records":
+		p := &credsRecordLister{credsProvider{err: err}}
+		return p, &p.credsProvider
+	}
+	p := &credsProvider{err: err}
+	return p, p
+}
+
+func TestCheckDNSProvider(t *testing.T) {
+	refused := errors.New("401 Unauthorized")
+	tests := []struct {
+		name   string
+		kind   string // "zones", "records" or "" for a credsProvider.
+		ptype  string
+		domain string
+		call   string // The call expected, or "" for errUnchecked.
+	}{
+		{
+			name:   "zone lister",
+			kind:   "zones",
+			ptype:  "FAKECREDS",
+			domain: "example.com",
+			call:   "ListZones",
+		},
+		{
+			name:  "zone lister without domains",
+			kind:  "zones",
+			ptype: "FAKECREDS",
+			call:  "ListZones",
+		},
+		{
+			name:   "record lister",
+			kind:   "records",
+			ptype:  "FAKECREDSNS",
+			domain: "example.com",
+			call:   "GetZoneRecords example.com",
+		},
+		{
+			name:  "record lister without domains",
+			kind:  "records",
+			ptype: "FAKECREDS",
+		},
+		{
+			name:   "nameservers from the API",
+			ptype:  "FAKECREDSNS",
+			domain: "example.com",
+			call:   "GetNameservers example.com",
+		},
+		{
+			name:   "nameservers it knows",
+			ptype:  "FAKECREDS",
+			domain: "example.com",
+		},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			for _, want := range []error{nil, refused} {
+				prov, calls := newCredsProvider(tst.kind, want)
+				err := checkDNSProvider(prov, tst.ptype, tst.domain)
+				if tst.call == "" {
+					want = errUnchecked
+				}
+				if err != want {
+					t.Errorf("got error %v, want %v", err, want)
+				}
+				var made string
+				if len(calls.calls) > 0 {
+					made = calls.calls[0]
+				}
+				if len(calls.calls) > 1 || made != tst.call {
+					t.Errorf("made the calls %q, want %q", calls.calls, tst.call)
+				}
+			}
+		})
+	}
+}
+
+func TestCredsStatus(t *testing.T) {
+	tests := []struct {
+		err       error
+		haveEntry bool
+		status    string
+	}{
+		{nil, true, credsOK},
+		{nil, false, credsOK},
+		{errUnchecked, true, credsUnchecked},
+		{errors.New("missing api_token"), false, credsMissing},
+		{errors.New("401 Unauthorized"), true, credsInvalid},
+		{errors.New("InvalidClientTokenId: The security token included in the request is invalid."), true, credsInvalid},
+		{errors.New("the token has expired"), true, credsInvalid},
+		{errors.New("403 Forbidden"), true, credsForbidden},
+		{errors.New("AccessDenied: not allowed to route53:ListHostedZones"), true, credsForbidden},
+		{errors.New("dial tcp: i/o timeout"), true, credsError},
+	}
+	for _, tst := range tests {
+		status, detail := credsStatus(tst.err, tst.haveEntry)
+		if status != tst.status {
+			t.Errorf("%v (entry: %v): got %s, want %s", tst.err, tst.haveEntry, status, tst.status)
+		}
+		if tst.status != credsOK && tst.status != credsUnchecked && detail != tst.err.Error() {
+			t.Errorf("%v: the details are %q", tst.err, detail)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/StackExchange/dnscontrol/providers"
+	"github.com/StackExchange/dnscontrol/providers/config"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var _ = cmd(catUtils, func() *cli.Command {
+	var args CheckExpiryArgs
+	return &cli.Command{
+		Name:  "check-expiry",
+		Usage: "check with the registrars that domains are locked and don't expire soon",
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() != 0 {
+				return cli.NewExitError("Unexpected arguments", 1)
+			}
+			return exit(CheckExpiry(args))
+		},
+		Flags: args.flags(),
+	}
+}())
+
+// CheckExpiryArgs contains all data/flags needed to run check-expiry, independently of CLI.
+type CheckExpiryArgs struct {
+	GetDNSConfigArgs
+	GetCredentialsArgs
+	Domains string
+	Days    int
+}
+
+func (args *CheckExpiryArgs) flags() []cli.Flag {
+	flags := args.GetDNSConfigArgs.flags()
+	flags = append(flags, args.GetCredentialsArgs.flags()...)
+	flags = append(flags, cli.StringFlag{
+		Name:        "domains",
+		Destination: &args.Domains,
+		Usage:       `Comma separated list of domain names to include; * and ? match like in file names, for example "*.example.com,foo.org"`,
+	})
+	flags = append(flags, cli.IntFlag{
+		Name:        "days",
+		Destination: &args.Days,
+		Value:       30,
+		Usage:       "Warn about domains that expire within this many days",
+	})
+	return flags
+}
+
+// The results of an expiry check.
+const (
+	expiryOK        = "OK"
+	expiryExpiring  = "EXPIRING"  // Expires within --days.
+	expiryExpired   = "EXPIRED"   // Expired already.
+	expiryUnlocked  = "UNLOCKED"  // Can be transferred away.
+	expiryError     = "ERROR"     // The registrar failed.
+	expiryUnchecked = "UNCHECKED" // The registrar can't tell.
+)
+
+// expiryStatus returns the result of the check of s, and its details, at
+// time now. unlockOK is true for domains with REGISTRAR_LOCK("off"), which
+// may be unlocked.
+func expiryStatus(s *models.DomainStatus, now time.Time, days int, unlockOK bool) (string, string) {
+	var details []string
+	status := expiryOK
+	if !s.Expires.IsZero() {
+		left := int(s.Expires.Sub(now).Hours() / 24)
+		details = append(details, fmt.Sprintf("expires %s (%d days)", s.Expires.Format("2006-01-02"), left))
+		if !s.Expires.After(now) {
+			status = expiryExpired
+		} else if left < days {
+			status = expiryExpiring
+		}
+	} else {
+		details = append(details, "expiration unknown")
+	}
+	if s.Locked != nil {
+		if *s.Locked {
+			details = append(details, "locked")
+		} else {
+			details = append(details, "unlocked")
+			if status == expiryOK && !unlockOK {
+				status = expiryUnlocked
+			}
+		}
+	}
+	if s.AutoRenew != nil {
+		if *s.AutoRenew {
+			details = append(details, "auto-renew on")
+		} else {
+			details = append(details, "auto-renew off")
+		}
+	}
+	return status, strings.Join(details, ", ")
+}
+
+// checkDomainExpiry returns the result of the check of the domain of dc
+// at registrar r, of type rtype, and its details.
+func checkDomainExpiry(r providers.Registrar, rtype string, dc *models.DomainConfig, now time.Time, days int) (string, string) {
+	unchecked := fmt.Sprintf("registrar %s (%s) can't tell when domains expire", dc.RegistrarName, rtype)
+	g, ok := r.(providers.DomainStatusGetter)
+	if !ok {
+		return expiryUnchecked, unchecked
+	}
+	s, err := g.GetDomainStatus(dc.Name)
+	if errors.Cause(err) == providers.ErrNotSupported {
+		return expiryUnchecked, unchecked
+	}
+	if err != nil {
+		return expiryError, err.Error()
+	}
+	return expiryStatus(s, now, days, dc.RegistrarLock == "off")
+}
+
+// CheckExpiry implements the check-expiry subcommand. It asks the
+// registrar of each domain when the domain expires and whether it is
+// locked, and fails if any domain expires within args.Days, is unlocked
+// without REGISTRAR_LOCK("off"), or couldn't be checked. Registrars that
+// can't tell are skipped.
+func CheckExpiry(args CheckExpiryArgs) error {
+	cfg, err := GetDNSConfig(args.GetDNSConfigArgs)
+	if err != nil {
+		return err
+	}
+	creds, err := config.LoadProviderConfigs(args.CredsFile)
+	if err != nil {
+		return err
+	}
+	filter := FilterArgs{Domains: args.Domains}
+
+	types := map[string]string{}
+	for _, r := range cfg.Registrars {
+		types[r.Name] = r.Type
+	}
+	registrars := map[string]providers.Registrar{}
+	registrarErrs := map[string]error{}
+	registrar := func(name string) (providers.Registrar, error) {
+		if r, ok := registrars[name]; ok || registrarErrs[name] != nil {
+			return r, registrarErrs[name]
+		}
+		r, err := providers.CreateRegistrar(types[name], creds[name])
+		registrars[name], registrarErrs[name] = r, err
+		return r, err
+	}
+
+	now := time.Now()
+	checked, failed := 0, 0
+	for _, dc := range cfg.Domains {
+		if !filter.shouldRunDomain(dc.UniqueName()) || types[dc.RegistrarName] == "NONE" {
+			continue
+		}
+		checked++
+		var status, detail string
+		if r, err := registrar(dc.RegistrarName); err != nil {
+			status, detail = expiryError, err.Error()
+		} else {
+			status, detail = checkDomainExpiry(r, types[dc.RegistrarName], dc, now, args.Days)
+		}
+		if status != expiryOK && status != expiryUnchecked {
+			failed++
+		}
+		printer.Printf("%-9s %s: %s\n", status, dc.Name, detail)
+	}
+	if failed != 0 {
+		return errors.Errorf("%d of %d domains need attention", failed, checked)
+	}
+	return nil
+}
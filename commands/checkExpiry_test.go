@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/providers"
+	"github.com/pkg/errors"
+)
+
+func TestExpiryStatus(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	yes, no := true, false
+	tests := []struct {
+		name     string
+		status   models.DomainStatus
+		unlockOK bool
+		want     string
+		detail   string
+	}{
+		{
+			name:   "ok",
+			status: models.DomainStatus{Expires: now.AddDate(1, 0, 0), Locked: &yes, AutoRenew: &yes},
+			want:   expiryOK,
+			detail: "expires 2021-01-01 (366 days), locked, auto-renew on",
+		},
+		{
+			name:   "expiring",
+			status: models.DomainStatus{Expires: now.AddDate(0, 0, 10), AutoRenew: &no},
+			want:   expiryExpiring,
+			detail: "expires 2020-01-11 (10 days), auto-renew off",
+		},
+		{
+			name:   "on the last day",
+			status: models.DomainStatus{Expires: now.AddDate(0, 0, 30)},
+			want:   expiryOK,
+			detail: "expires 2020-01-31 (30 days)",
+		},
+		{
+			name:   "expired",
+			status: models.DomainStatus{Expires: now, Locked: &no},
+			want:   expiryExpired,
+			detail: "expires 2020-01-01 (0 days), unlocked",
+		},
+		{
+			name:   "unlocked",
+			status: models.DomainStatus{Expires: now.AddDate(1, 0, 0), Locked: &no},
+			want:   expiryUnlocked,
+			detail: "expires 2021-01-01 (366 days), unlocked",
+		},
+		{
+			name:     "unlocked on purpose",
+			status:   models.DomainStatus{Expires: now.AddDate(1, 0, 0), Locked: &no},
+			unlockOK: true,
+			want:     expiryOK,
+			detail:   "expires 2021-01-01 (366 days), unlocked",
+		},
+		{
+			name:   "nothing known",
+			status: models.DomainStatus{},
+			want:   expiryOK,
+			detail: "expiration unknown",
+		},
+	}
+	for _, tst := range tests {
+		status, detail := expiryStatus(&tst.status, now, 30, tst.unlockOK)
+		if status != tst.want || detail != tst.detail {
+			t.Errorf("%s: got %s %q, want %s %q", tst.name, status, detail, tst.want, tst.detail)
+		}
+	}
+}
+
+// statusRegistrar is a registrar whose GetDomainStatus returns its fields.
+type statusRegistrar struct {
+	providers.None
+	status *models.DomainStatus
+	err    error
+}
+
+func (r statusRegistrar) GetDomainStatus(domain string) (*models.DomainStatus, error) {
+	return r.status, r.err
+}
+
+func TestCheckDomainExpiry(t *testing.T) {
+	now := time.Now()
+	yes := true
+	tests := []struct {
+		name      string
+		registrar providers.Registrar
+		want      string
+	}{
+		{"can't tell", providers.None{}, expiryUnchecked},
+		{"plugin that can't tell", statusRegistrar{err: errors.Wrap(providers.ErrNotSupported, "plugin p can't tell when domains expire")}, expiryUnchecked},
+		{"failed", statusRegistrar{err: errors.New("401 Unauthorized")}, expiryError},
+		{"checked", statusRegistrar{status: &models.DomainStatus{Expires: now.AddDate(1, 0, 0), Locked: &yes}}, expiryOK},
+	}
+	dc := &models.DomainConfig{Name: "example.com", RegistrarName: "reg"}
+	for _, tst := range tests {
+		status, detail := checkDomainExpiry(tst.registrar, "FAKE", dc, now, 30)
+		if status != tst.want {
+			t.Errorf("%s: got %s (%s), want %s", tst.name, status, detail, tst.want)
+		}
+		if status == expiryUnchecked && detail != "registrar reg (FAKE) can't tell when domains expire" {
+			t.Errorf("%s: the details are %q", tst.name, detail)
+		}
+	}
+}
@@ -4,11 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"sort"
 	"strings"
 
 	"github.com/StackExchange/dnscontrol/models"
 	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/StackExchange/dnscontrol/pkg/redact"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli"
 )
@@ -55,6 +57,7 @@ func Run(v string) int {
 	sort.Sort(cli.CommandsByName(commands))
 	app.Commands = commands
 	app.EnableBashCompletion = true
+	app.ErrWriter = redact.Writer(os.Stderr)
 	if err := app.Run(os.Args); err != nil {
 		return 1
 	}
@@ -89,21 +92,33 @@ func (args *GetDNSConfigArgs) flags() []cli.Flag {
 // GetDNSConfig reads the json-formatted IR file. Or executes javascript. All depending on flags provided.
 func GetDNSConfig(args GetDNSConfigArgs) (*models.DNSConfig, error) {
 	if args.JSONFile != "" {
-		f, err := os.Open(args.JSONFile)
-		if err != nil {
-			return nil, err
-		}
-		defer f.Close()
-		dec := json.NewDecoder(f)
-		cfg := &models.DNSConfig{}
-		if err = dec.Decode(cfg); err != nil {
-			return nil, err
-		}
-		return preloadProviders(cfg, nil)
+		return preloadProviders(readIR(args.JSONFile, false))
 	}
 	return preloadProviders(ExecuteDSL(args.ExecuteDSLArgs))
 }
 
+// readIR reads a json-formatted IR file, such as the output of print-ir.
+// If strict, fields that the IR doesn't have are errors.
+func readIR(file string, strict bool) (*models.DNSConfig, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dec := json.NewDecoder(f)
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+	cfg := &models.DNSConfig{}
+	if err = dec.Decode(cfg); err != nil {
+		return nil, errors.Wrapf(err, "reading %s", file)
+	}
+	if cfg.SchemaVersion > models.IRSchemaVersion {
+		return nil, errors.Errorf("reading %s: it has schema_version %d, and this dnscontrol reads up to %d; upgrade dnscontrol", file, cfg.SchemaVersion, models.IRSchemaVersion)
+	}
+	return cfg, nil
+}
+
 // the json only contains provider names inside domains. This denormalizes the data for more
 // convenient access patterns. Does everything we need to prepare for the validation phase, but
 // cannot do anything that requires the credentials file yet.
@@ -123,6 +138,7 @@ func preloadProviders(cfg *models.DNSConfig, err error) (*models.DNSConfig, erro
 	// make registrar and dns provider shims. Include name, type, and other metadata, but can't instantiate
 	// driver until we load creds in later
 	for _, d := range cfg.Domains {
+		d.SplitTag()
 		reg, ok := cfg.RegistrarsByName[d.RegistrarName]
 		if !ok {
 			return nil, errors.Errorf("Registrar named %s expected for %s, but never registered", d.RegistrarName, d.Name)
@@ -156,9 +172,14 @@ func preloadProviders(cfg *models.DNSConfig, err error) (*models.DNSConfig, erro
 
 // ExecuteDSLArgs are used anytime we need to read and execute dnscontrol DSL
 type ExecuteDSLArgs struct {
-	JSFile   string
-	JSONFile string
-	DevMode  bool
+	JSFile     string
+	JSONFile   string
+	DevMode    bool
+	AllowFetch bool
+	FetchCache string
+	FetchHosts string
+	AllowEnv   string
+	Variables  cli.StringSlice // key=value, for CLI in dnsconfig.js.
 }
 
 func (args *ExecuteDSLArgs) flags() []cli.Flag {
@@ -167,7 +188,7 @@ func (args *ExecuteDSLArgs) flags() []cli.Flag {
 			Name:        "config",
 			Value:       "dnsconfig.js",
 			Destination: &args.JSFile,
-			Usage:       "File containing dns config in javascript DSL",
+			Usage:       "File containing dns config in javascript DSL, in TypeScript if it ends in .ts, in YAML if it ends in .yaml, or IR (json) if it ends in .json",
 		},
 		cli.StringFlag{
 			Name:        "js",
@@ -181,6 +202,31 @@ func (args *ExecuteDSLArgs) flags() []cli.Flag {
 			Destination: &args.DevMode,
 			Usage:       "Use helpers.js from disk instead of embedded copy",
 		},
+		cli.BoolFlag{
+			Name:        "allow-fetch",
+			Destination: &args.AllowFetch,
+			Usage:       "Allow FETCH() in the javascript DSL to download URLs",
+		},
+		cli.StringFlag{
+			Name:        "fetch-cache",
+			Destination: &args.FetchCache,
+			Usage:       "JSON file that keeps what FETCH() downloads, used when a URL can't be fetched",
+		},
+		cli.StringFlag{
+			Name:        "fetch-hosts",
+			Destination: &args.FetchHosts,
+			Usage:       "Comma separated hosts that FETCH() may download from; default is any host",
+		},
+		cli.StringFlag{
+			Name:        "allow-env",
+			Destination: &args.AllowEnv,
+			Usage:       "Comma separated environment variables that the javascript DSL can read as ENV.NAME",
+		},
+		cli.StringSliceFlag{
+			Name:  "variable, v",
+			Value: &args.Variables,
+			Usage: "Set CLI.KEY to VALUE in the javascript DSL, as key=value; may be repeated",
+		},
 	}
 }
 
@@ -232,19 +278,21 @@ func (args *FilterArgs) flags() []cli.Flag {
 		cli.StringFlag{
 			Name:        "providers",
 			Destination: &args.Providers,
-			Usage:       `Providers to enable (comma separated list); default is all. Can exclude individual providers from default by adding '"_exclude_from_defaults": "true"' to the credentials file for a provider`,
+			Usage:       `Providers to enable (comma separated list of names or types, such as CLOUDFLAREAPI); default is all. Can exclude individual providers from default by adding '"_exclude_from_defaults": "true"' to the credentials file for a provider`,
 			Value:       "",
 		},
 		cli.StringFlag{
 			Name:        "domains",
 			Destination: &args.Domains,
-			Usage:       `Comma separated list of domain names to include`,
+			Usage:       `Comma separated list of domain names to include; * and ? match like in file names, for example "*.example.com,foo.org"`,
 			Value:       "",
 		},
 	}
 }
 
-func (args *FilterArgs) shouldRunProvider(name string, dc *models.DomainConfig) bool {
+// shouldRunProvider reports whether the provider called name, of type
+// ptype, is selected for dc. --providers may list either.
+func (args *FilterArgs) shouldRunProvider(name, ptype string, dc *models.DomainConfig) bool {
 	if args.Providers == "all" {
 		return true
 	}
@@ -256,8 +304,8 @@ func (args *FilterArgs) shouldRunProvider(name string, dc *models.DomainConfig)
 		}
 		return true
 	}
-	for _, prov := range strings.Split(args.Providers, ",") {
-		if prov == name {
+	for _, prov := range splitList(args.Providers) {
+		if prov == name || strings.EqualFold(prov, ptype) {
 			return true
 		}
 	}
@@ -268,10 +316,64 @@ func (args *FilterArgs) shouldRunDomain(d string) bool {
 	if args.Domains == "" {
 		return true
 	}
-	for _, dom := range strings.Split(args.Domains, ",") {
-		if dom == d {
+	for _, pattern := range splitList(args.Domains) {
+		if matchDomain(pattern, d) {
 			return true
 		}
 	}
 	return false
 }
+
+// matchDomain reports whether the domain name d matches pattern, which
+// is a domain name or a glob. Patterns without a view, such as
+// "example.com", match all the views of d, such as "example.com!internal".
+func matchDomain(pattern, d string) bool {
+	if !strings.Contains(pattern, "!") {
+		d = strings.SplitN(d, "!", 2)[0]
+	}
+	ok, err := path.Match(strings.ToLower(pattern), strings.ToLower(d))
+	return ok && err == nil
+}
+
+// unmatchedFilters returns the patterns of --domains that match none of
+// domains, and the names of --providers that name no provider of cfg.
+// They are most likely typos.
+func (args *FilterArgs) unmatchedFilters(cfg *models.DNSConfig) (domains, providers []string) {
+	for _, pattern := range splitList(args.Domains) {
+		found := false
+		for _, d := range cfg.Domains {
+			found = found || matchDomain(pattern, d.UniqueName())
+		}
+		if !found {
+			domains = append(domains, pattern)
+		}
+	}
+	if args.Providers == "all" {
+		return domains, nil
+	}
+	for _, prov := range splitList(args.Providers) {
+		found := false
+		for _, p := range cfg.DNSProviders {
+			found = found || p.Name == prov || strings.EqualFold(p.Type, prov)
+		}
+		for _, r := range cfg.Registrars {
+			found = found || r.Name == prov || strings.EqualFold(r.Type, prov)
+		}
+		if !found {
+			providers = append(providers, prov)
+		}
+	}
+	return domains, providers
+}
+
+// splitList splits a comma separated flag value, ignoring spaces and
+// empty items.
+func splitList(s string) []string {
+	list := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
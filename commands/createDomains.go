@@ -3,6 +3,7 @@ package commands
 import (
 	"fmt"
 
+	"github.com/StackExchange/dnscontrol/pkg/printer"
 	"github.com/StackExchange/dnscontrol/providers"
 	"github.com/urfave/cli"
 )
@@ -48,7 +49,7 @@ func CreateDomains(args CreateDomainsArgs) error {
 				fmt.Println("  -", provider.Name)
 				err := creator.EnsureDomainExists(domain.Name)
 				if err != nil {
-					fmt.Printf("Error creating domain: %s\n", err)
+					printer.Printf("Error creating domain: %s\n", err)
 				}
 			}
 		}
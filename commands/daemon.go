@@ -0,0 +1,270 @@
+package commands
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/StackExchange/dnscontrol/pkg/redact"
+	"github.com/StackExchange/dnscontrol/pkg/report"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var _ = cmd(catMain, func() *cli.Command {
+	var args DaemonArgs
+	return &cli.Command{
+		Name:  "daemon",
+		Usage: "preview (or push, with --apply) again and again, and serve health endpoints",
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() != 0 {
+				return cli.NewExitError("Unexpected arguments", 1)
+			}
+			return exit(Daemon(args))
+		},
+		Flags: args.flags(),
+	}
+}())
+
+// DaemonArgs contains all data/flags needed to run daemon, independently of CLI.
+type DaemonArgs struct {
+	PushArgs
+	Interval      time.Duration
+	Apply         bool
+	Listen        string
+	WebhookSecret string
+	WebhookPath   string
+}
+
+// daemonSkipFlags are the flags of push that make no sense for a run
+// nobody watches.
+var daemonSkipFlags = map[string]bool{
+	"i": true, "expect-no-changes": true, "template": true, "json": true, "pr-comment": true, "run-report": true, "report": true,
+}
+
+func (args *DaemonArgs) flags() []cli.Flag {
+	var flags []cli.Flag
+	for _, f := range args.PushArgs.flags() {
+		if !daemonSkipFlags[f.GetName()] {
+			flags = append(flags, f)
+		}
+	}
+	return append(flags,
+		cli.DurationFlag{
+			Name:        "interval",
+			Destination: &args.Interval,
+			Usage:       "Time between the start of two runs",
+			Value:       15 * time.Minute,
+		},
+		cli.BoolFlag{
+			Name:        "apply",
+			Destination: &args.Apply,
+			Usage:       "Push the corrections; without it, drift is only reported",
+		},
+		cli.StringFlag{
+			Name:        "listen",
+			Destination: &args.Listen,
+			Usage:       `Address to serve /healthz, /readyz and /status on; "" to not serve them`,
+			Value:       ":8080",
+		},
+		cli.StringFlag{
+			Name:        "webhook-secret",
+			Destination: &args.WebhookSecret,
+			EnvVar:      "DNSCONTROL_WEBHOOK_SECRET",
+			Usage:       "Accept GitHub and GitLab push events signed with this secret: pull the git checkout of dnsconfig.js and run the domains it changes",
+		},
+		cli.StringFlag{
+			Name:        "webhook-path",
+			Destination: &args.WebhookPath,
+			Usage:       "Path to accept push events on",
+			Value:       "/webhook",
+		},
+	)
+}
+
+// daemonRun is the result of one run of the daemon.
+type daemonRun struct {
+	Trigger     string      `json:"trigger"` // "interval", or "push <commit>" for a webhook.
+	Start       time.Time   `json:"start"`
+	Duration    string      `json:"duration"`
+	Corrections int         `json:"corrections"`
+	Error       string      `json:"error,omitempty"`
+	Run         *report.Run `json:"run,omitempty"`
+}
+
+// daemonStatus is what /status serves.
+type daemonStatus struct {
+	mu          sync.Mutex
+	Apply       bool       `json:"apply"`
+	Interval    string     `json:"interval"`
+	Runs        int        `json:"runs"`
+	LastRun     *daemonRun `json:"last_run,omitempty"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	NextRun     time.Time  `json:"next_run"`
+}
+
+// ready reports whether the last run succeeded, and the last success is
+// recent enough that the daemon is not stuck.
+func (s *daemonStatus) ready(interval time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.LastRun != nil && s.LastRun.Error == "" && s.LastSuccess != nil && time.Since(*s.LastSuccess) < 2*interval+time.Minute
+}
+
+// record makes result the last run.
+func (s *daemonStatus) record(result *daemonRun) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Runs++
+	s.LastRun = result
+	if result.Error == "" {
+		s.LastSuccess = &result.Start
+	}
+}
+
+func (s *daemonStatus) handler(interval time.Duration, webhook *gitopsWebhook, webhookPath string) http.Handler {
+	mux := http.NewServeMux()
+	if webhook != nil {
+		mux.Handle(webhookPath, webhook)
+	}
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok\n"))
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !s.ready(interval) {
+			http.Error(w, "the last run failed or is too old; see /status", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok\n"))
+	})
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		b, err := json.MarshalIndent(s, "", "  ")
+		s.mu.Unlock()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(redact.String(string(b)) + "\n"))
+	})
+	return mux
+}
+
+// Daemon implements the daemon subcommand: a preview, or a push with
+// --apply, every args.Interval. dnsconfig.js and creds.json are read
+// again for each run, so that changes to them are picked up. SIGINT and
+// SIGTERM stop the daemon once the current run is done.
+//
+// With args.WebhookSecret, push events pull the git checkout of
+// dnsconfig.js and run the domains the pull changed, and each periodic
+// run pulls first.
+func Daemon(args DaemonArgs) error {
+	if args.Interval <= 0 {
+		return errors.Errorf("--interval must be positive")
+	}
+	status := &daemonStatus{Apply: args.Apply, Interval: args.Interval.String()}
+	triggers := make(chan string, 1)
+	var webhook *gitopsWebhook
+	if args.WebhookSecret != "" {
+		if args.Listen == "" {
+			return errors.Errorf("--webhook-secret needs --listen")
+		}
+		redact.Add(args.WebhookSecret)
+		repo := gitopsRepo(args.GetDNSConfigArgs)
+		if _, err := gitopsBranch(repo); err != nil {
+			return errors.Wrap(err, "--webhook-secret needs dnsconfig.js to be in a git checkout")
+		}
+		webhook = &gitopsWebhook{secret: args.WebhookSecret, branch: func() (string, error) { return gitopsBranch(repo) }, trigger: triggers}
+	}
+	if args.Listen != "" {
+		l, err := net.Listen("tcp", args.Listen)
+		if err != nil {
+			return errors.Wrap(err, "--listen")
+		}
+		go func() {
+			defer redact.RecoverPanic()
+			printer.Warnf("The health endpoints stopped: %s\n", http.Serve(l, status.handler(args.Interval, webhook, args.WebhookPath)))
+		}()
+		printer.Printf("Serving /healthz, /readyz and /status on %s\n", l.Addr())
+		if webhook != nil {
+			printer.Printf("Accepting push events on %s\n", args.WebhookPath)
+		}
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	ticker := time.NewTicker(args.Interval)
+	defer ticker.Stop()
+	trigger := "interval"
+	for {
+		status.mu.Lock()
+		status.NextRun = time.Now().Add(args.Interval)
+		status.mu.Unlock()
+		daemonOnce(args, status, trigger, webhook != nil)
+		select {
+		case <-ticker.C:
+			trigger = "interval"
+		case commit := <-triggers:
+			trigger = "push " + commit
+		case sig := <-stop:
+			printer.Printf("Stopping on %s\n", sig)
+			return nil
+		}
+	}
+}
+
+// daemonOnce runs a preview or push and records its result in status.
+// With pull, it pulls the git checkout of dnsconfig.js first. Runs
+// triggered by a push event only run the domains the pull changed.
+func daemonOnce(args DaemonArgs, status *daemonStatus, trigger string, pull bool) {
+	start := time.Now()
+	result := &daemonRun{Trigger: trigger, Start: start}
+	fail := func(err error) {
+		result.Error = err.Error()
+		result.Duration = time.Since(start).String()
+		printer.Warnf("The run failed: %s\n", err)
+		status.record(result)
+	}
+	if pull {
+		changed, err := gitopsPull(args.GetDNSConfigArgs)
+		if err != nil {
+			fail(err)
+			return
+		}
+		if trigger != "interval" {
+			domains := []string{}
+			for _, d := range changed {
+				if args.shouldRunDomain(d) {
+					domains = append(domains, d)
+				}
+			}
+			if len(domains) == 0 {
+				printer.Printf("%s changed no domain\n", trigger)
+				return
+			}
+			printer.Printf("%s changed %s\n", trigger, strings.Join(domains, ", "))
+			args.Domains = strings.Join(domains, ",")
+		}
+	}
+	rec := report.NewRecorder(printer.DefaultPrinter, args.Apply)
+	if err := run(args.PreviewArgs, args.Apply, false, args.BreakGlass, rec); err != nil {
+		result.Corrections, result.Run = rec.Run.Corrections(), &rec.Run
+		fail(err)
+		return
+	}
+	result.Duration = time.Since(start).String()
+	result.Corrections = rec.Run.Corrections()
+	result.Run = &rec.Run
+	if result.Corrections != 0 && !args.Apply {
+		printer.Warnf("DNS has drifted from dnsconfig.js: %d corrections pending\n", result.Corrections)
+	}
+	status.record(result)
+}
@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const daemonConfig = `
+D("example.com", NewRegistrar("none", "NONE"), DnsProvider(NewDnsProvider("fake", "FAKE")),
+	A("@", "192.0.2.1")
+);
+`
+
+// get returns the status code and body of a GET of url.
+func get(t *testing.T, url string) (int, string) {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(b)
+}
+
+func TestDaemonOnce(t *testing.T) {
+	pargs, cleanup := writeConfig(t, daemonConfig, map[string]int{"example.com": 2})
+	defer cleanup()
+	args := DaemonArgs{PushArgs: PushArgs{PreviewArgs: pargs}, Interval: time.Minute}
+	status := &daemonStatus{}
+	srv := httptest.NewServer(status.handler(args.Interval, nil, ""))
+	defer srv.Close()
+
+	if code, _ := get(t, srv.URL+"/healthz"); code != http.StatusOK {
+		t.Errorf("/healthz: %d", code)
+	}
+	if code, _ := get(t, srv.URL+"/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("/readyz before the first run: %d", code)
+	}
+
+	// Without --apply, the drift is only reported.
+	daemonOnce(args, status, "interval", false)
+	if status.LastRun.Error != "" || status.LastRun.Corrections != 2 || registeredFake.ran.String() != "" {
+		t.Errorf("preview: error %q, %d corrections, ran %q", status.LastRun.Error, status.LastRun.Corrections, registeredFake.ran.String())
+	}
+	if code, _ := get(t, srv.URL+"/readyz"); code != http.StatusOK {
+		t.Errorf("/readyz after a preview: %d", code)
+	}
+
+	args.Apply = true
+	daemonOnce(args, status, "interval", false)
+	if got := registeredFake.ran.String(); status.LastRun.Error != "" || got != "fake:example.com#1 fake:example.com#2" {
+		t.Errorf("push: error %q, ran %q", status.LastRun.Error, got)
+	}
+
+	// dnsconfig.js is read again for each run.
+	if err := ioutil.WriteFile(args.JSFile, []byte("D("), 0644); err != nil {
+		t.Fatal(err)
+	}
+	daemonOnce(args, status, "interval", false)
+	if status.LastRun.Error == "" || status.LastSuccess == nil || *status.LastSuccess == status.LastRun.Start {
+		t.Errorf("a broken dnsconfig.js didn't fail the run: %+v", status.LastRun)
+	}
+	if code, _ := get(t, srv.URL+"/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("/readyz after a failed run: %d", code)
+	}
+
+	code, body := get(t, srv.URL+"/status")
+	var got struct {
+		Runs    int
+		LastRun struct{ Error string } `json:"last_run"`
+	}
+	if err := json.Unmarshal([]byte(body), &got); err != nil || code != http.StatusOK {
+		t.Fatalf("/status: %d %v %s", code, err, body)
+	}
+	if got.Runs != 3 || got.LastRun.Error != status.LastRun.Error {
+		t.Errorf("/status: %s", body)
+	}
+}
+
+func TestDaemonReady(t *testing.T) {
+	interval := time.Minute
+	old := time.Now().Add(-2*interval - 2*time.Minute)
+	tests := []struct {
+		name   string
+		result *daemonRun
+		ready  bool
+	}{
+		{"success", &daemonRun{Start: time.Now()}, true},
+		{"failure", &daemonRun{Start: time.Now(), Error: "boom"}, false},
+		{"stuck", &daemonRun{Start: old}, false},
+	}
+	for _, tst := range tests {
+		s := &daemonStatus{}
+		s.record(tst.result)
+		if got := s.ready(interval); got != tst.ready {
+			t.Errorf("%s: ready is %v", tst.name, got)
+		}
+	}
+}
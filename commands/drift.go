@@ -0,0 +1,185 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/pkg/notifications"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/StackExchange/dnscontrol/pkg/redact"
+	"github.com/StackExchange/dnscontrol/pkg/report"
+	"github.com/StackExchange/dnscontrol/providers/config"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var _ = cmd(catMain, func() *cli.Command {
+	var args DriftArgs
+	return &cli.Command{
+		Name:  "drift",
+		Usage: "preview, and print how many records differ from dnsconfig.js in each domain. Exits with 2 on drift, for cron jobs",
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() != 0 {
+				return cli.NewExitError("Unexpected arguments", 1)
+			}
+			return exit(Drift(args))
+		},
+		Flags: args.flags(),
+	}
+}())
+
+// DriftArgs contains all data/flags needed to run drift, independently of CLI.
+type DriftArgs struct {
+	PreviewArgs
+	Full bool
+}
+
+// driftSkipFlags are the flags of preview that drift replaces with its summary.
+var driftSkipFlags = map[string]bool{
+	"expect-no-changes": true, "template": true, "json": true, "pr-comment": true, "report": true, "snapshot": true, "run-report": true,
+}
+
+func (args *DriftArgs) flags() []cli.Flag {
+	var flags []cli.Flag
+	for _, f := range args.PreviewArgs.flags() {
+		if !driftSkipFlags[f.GetName()] {
+			flags = append(flags, f)
+		}
+	}
+	return append(flags, cli.BoolFlag{
+		Name:        "full",
+		Destination: &args.Full,
+		Usage:       "Print the output of preview before the summary. Without it, it is only printed if the run fails",
+	})
+}
+
+// driftDomain is the summary of a domain.
+type driftDomain struct {
+	name        string
+	differences int      // Records that differ, or corrections for providers that don't say.
+	changes     []string // What differs, one per line.
+	errors      []string // The providers that failed, with their error.
+}
+
+// summarizeDrift returns the summary of each domain of run.
+func summarizeDrift(run *report.Run) []*driftDomain {
+	var domains []*driftDomain
+	for _, d := range run.Domains {
+		dd := &driftDomain{name: d.Name}
+		for _, p := range d.Providers {
+			if p.Error != "" {
+				dd.errors = append(dd.errors, p.Name+": "+p.Error)
+			}
+			if len(p.Changes) != 0 {
+				dd.differences += len(p.Changes)
+				for _, pc := range printerChanges(p.Changes) {
+					dd.changes = append(dd.changes, fmt.Sprintf("%s %s %s %s", strings.ToUpper(pc.Action), pc.Type, pc.Name, strings.TrimSpace(pc.Before+" -> "+pc.After)))
+				}
+				continue
+			}
+			dd.differences += len(p.Corrections)
+			for _, c := range p.Corrections {
+				dd.changes = append(dd.changes, strings.SplitN(c.Msg, "\n", 2)[0])
+			}
+		}
+		domains = append(domains, dd)
+	}
+	return domains
+}
+
+func differences(n int) string {
+	if n == 1 {
+		return "1 difference"
+	}
+	return fmt.Sprintf("%d differences", n)
+}
+
+// notifyDrift sends a notification for each domain that drifted or
+// failed.
+func notifyDrift(n notifications.Notifier, domains []*driftDomain) {
+	const maxLines = 20
+	for _, d := range domains {
+		for _, e := range d.errors {
+			n.Notify(d.name, "drift", "could not check for drift", errors.New(e), false)
+		}
+		if d.differences == 0 {
+			continue
+		}
+		lines := d.changes
+		if len(lines) > maxLines {
+			lines = append(lines[:maxLines:maxLines], fmt.Sprintf("... and %d more", len(d.changes)-maxLines))
+		}
+		msg := fmt.Sprintf("%s from dnsconfig.js:\n%s", differences(d.differences), strings.Join(lines, "\n"))
+		n.Notify(d.name, "drift", msg, nil, true)
+	}
+	n.Done()
+}
+
+// Drift implements the drift subcommand: a preview that only prints, and
+// with --notify sends, the number of records that differ in each domain.
+// It exits with code 2 if anything differs, and 1 if the preview failed.
+func Drift(args DriftArgs) error {
+	var n notifications.Notifier
+	if args.Notify {
+		cfg, err := config.LoadProviderConfigs(args.CredsFile)
+		if err != nil {
+			return err
+		}
+		n = notifications.Init(cfg["notifications"])
+	}
+	// The notifications of drift replace those of each correction.
+	preview := args.PreviewArgs
+	preview.Notify = false
+
+	console(preview, os.Stdout)
+	out := redact.Writer(os.Stdout)
+	var log bytes.Buffer
+	if !args.Full {
+		// Keep the output of preview in case the run fails.
+		stdout, writer := os.Stdout, printer.DefaultPrinter.Writer
+		os.Stdout = os.Stderr
+		printer.DefaultPrinter.Writer = redact.Writer(&log)
+		printer.DefaultPrinter.Color = false
+		defer func() {
+			os.Stdout = stdout
+			printer.DefaultPrinter.Writer = writer
+		}()
+	}
+	rec := report.NewRecorder(printer.DefaultPrinter, false)
+	runErr := run(preview, false, false, false, rec)
+	domains := summarizeDrift(&rec.Run)
+	if !args.Full && runErr != nil {
+		io.Copy(os.Stderr, &log)
+	}
+	drifted, total := 0, 0
+	for _, d := range domains {
+		switch {
+		case len(d.errors) != 0:
+			fmt.Fprintf(out, "%s: error: %s\n", d.name, strings.Join(d.errors, "; "))
+		case d.differences != 0:
+			fmt.Fprintf(out, "%s: %s\n", d.name, differences(d.differences))
+		default:
+			fmt.Fprintf(out, "%s: in sync\n", d.name)
+		}
+		if d.differences != 0 {
+			drifted++
+			total += d.differences
+		}
+	}
+	if len(domains) != 0 {
+		fmt.Fprintf(out, "%d of %d domains drifted (%s)\n", drifted, len(domains), differences(total))
+	}
+	if n != nil {
+		notifyDrift(n, domains)
+	}
+	if runErr != nil {
+		return runErr
+	}
+	if drifted != 0 {
+		return errPendingChanges
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/pkg/report"
+)
+
+func TestSummarizeDrift(t *testing.T) {
+	www := &report.Record{Name: "www.a.com", Type: "A", TTL: 300, Value: "192.0.2.1"}
+	run := &report.Run{Domains: []*report.Domain{
+		{Name: "a.com", Providers: []*report.Provider{
+			{Name: "p1", Changes: []*report.Change{
+				{Action: "create", Record: www},
+				{Action: "modify", Record: &report.Record{Name: "a.com", Type: "MX", TTL: 300, Value: "10 mx2.a.com."}, Old: &report.Record{Name: "a.com", Type: "MX", TTL: 300, Value: "10 mx1.a.com."}},
+			}, Corrections: []*report.Correction{{Msg: "only counted without changes"}}},
+			{Name: "p2", Corrections: []*report.Correction{{Msg: "Update NS\nns1 -> ns2"}}},
+		}},
+		{Name: "b.com", Providers: []*report.Provider{{Name: "p1", Error: "p1 is down"}}},
+		{Name: "c.com", Providers: []*report.Provider{{Name: "p1"}}},
+	}}
+	domains := summarizeDrift(run)
+	if len(domains) != 3 {
+		t.Fatalf("got %d domains, want 3", len(domains))
+	}
+	a, b, c := domains[0], domains[1], domains[2]
+	want := "CREATE A www.a.com -> 192.0.2.1 ttl=300|MODIFY MX a.com 10 mx1.a.com. ttl=300 -> 10 mx2.a.com. ttl=300|Update NS"
+	if got := strings.Join(a.changes, "|"); a.differences != 3 || got != want || len(a.errors) != 0 {
+		t.Errorf("a.com: %d differences %q, errors %q", a.differences, got, a.errors)
+	}
+	if b.differences != 0 || len(b.errors) != 1 || b.errors[0] != "p1: p1 is down" {
+		t.Errorf("b.com: %d differences, errors %q", b.differences, b.errors)
+	}
+	if c.differences != 0 || len(c.changes) != 0 || len(c.errors) != 0 {
+		t.Errorf("c.com: %+v", c)
+	}
+}
+
+// notifyLog is a notifier that keeps what it is told.
+type notifyLog struct {
+	notified []string
+	done     int
+}
+
+func (l *notifyLog) Notify(domain, provider, message string, err error, preview bool) {
+	l.notified = append(l.notified, fmt.Sprintf("%s %s %v %v: %s", domain, provider, err, preview, message))
+}
+
+func (l *notifyLog) Done() { l.done++ }
+
+func TestNotifyDrift(t *testing.T) {
+	many := &driftDomain{name: "many.com", differences: 25}
+	for i := 1; i <= 25; i++ {
+		many.changes = append(many.changes, fmt.Sprintf("change %d", i))
+	}
+	domains := []*driftDomain{
+		{name: "a.com", differences: 1, changes: []string{"CREATE A www.a.com -> 192.0.2.1 ttl=300"}},
+		{name: "b.com", errors: []string{"p1: p1 is down"}},
+		{name: "c.com"},
+		many,
+	}
+	n := &notifyLog{}
+	notifyDrift(n, domains)
+	if n.done != 1 || len(n.notified) != 3 {
+		t.Fatalf("Done called %d times, notified %q", n.done, n.notified)
+	}
+	if want := "a.com drift <nil> true: 1 difference from dnsconfig.js:\nCREATE A www.a.com -> 192.0.2.1 ttl=300"; n.notified[0] != want {
+		t.Errorf("notified %q, want %q", n.notified[0], want)
+	}
+	if want := "b.com drift p1: p1 is down false: could not check for drift"; n.notified[1] != want {
+		t.Errorf("notified %q, want %q", n.notified[1], want)
+	}
+	lines := strings.Split(n.notified[2], "\n")
+	if len(lines) != 22 || lines[0] != "many.com drift <nil> true: 25 differences from dnsconfig.js:" || lines[20] != "change 20" || lines[21] != "... and 5 more" {
+		t.Errorf("notified %q", n.notified[2])
+	}
+}
+
+// driftOutput runs Drift, and returns what it printed to stdout.
+func driftOutput(t *testing.T, args DriftArgs) (string, error) {
+	f, err := ioutil.TempFile("", "dnscontrol-drift")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	stdout := os.Stdout
+	os.Stdout = f
+	err = Drift(args)
+	os.Stdout = stdout
+	out, rerr := ioutil.ReadFile(f.Name())
+	if rerr != nil {
+		t.Fatal(rerr)
+	}
+	return string(out), err
+}
+
+func TestDrift(t *testing.T) {
+	pargs, cleanup := writeConfig(t, daemonConfig, map[string]int{"example.com": 2})
+	defer cleanup()
+	args := DriftArgs{PreviewArgs: pargs}
+
+	out, err := driftOutput(t, args)
+	if err != errPendingChanges {
+		t.Errorf("err is %v, want %v", err, errPendingChanges)
+	}
+	if want := "example.com: 2 differences\n1 of 1 domains drifted (2 differences)\n"; out != want {
+		t.Errorf("printed %q, want %q", out, want)
+	}
+	if got := registeredFake.ran.String(); got != "" {
+		t.Errorf("ran %q", got)
+	}
+
+	registeredFake.corrections = map[string]int{}
+	out, err = driftOutput(t, args)
+	if err != nil || out != "example.com: in sync\n0 of 1 domains drifted (0 differences)\n" {
+		t.Errorf("in sync: err %v, printed %q", err, out)
+	}
+
+	if err := ioutil.WriteFile(args.JSFile, []byte("D("), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if out, err = driftOutput(t, args); err == nil || err == errPendingChanges || out != "" {
+		t.Errorf("broken dnsconfig.js: err %v, printed %q", err, out)
+	}
+}
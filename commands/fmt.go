@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/js"
+	"github.com/StackExchange/dnscontrol/pkg/jsfmt"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var _ = cmd(catUtils, func() *cli.Command {
+	var args FmtArgs
+	return &cli.Command{
+		Name:  "fmt",
+		Usage: "rewrite dnsconfig.js with canonical indentation and quoting",
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() != 0 {
+				return cli.NewExitError("Unexpected arguments", 1)
+			}
+			return exit(Fmt(args))
+		},
+		Flags: args.flags(),
+	}
+}())
+
+// FmtArgs contains all data/flags needed to run fmt, independently of CLI.
+type FmtArgs struct {
+	ExecuteDSLArgs
+	Output      string
+	Check       bool
+	SortRecords bool
+	Indent      int
+	Quote       string
+}
+
+func (args *FmtArgs) flags() []cli.Flag {
+	return append(args.ExecuteDSLArgs.flags(),
+		cli.StringFlag{
+			Name:        "out",
+			Destination: &args.Output,
+			Usage:       "File to write the formatted config to (default: rewrite the config)",
+		},
+		cli.BoolFlag{
+			Name:        "check",
+			Destination: &args.Check,
+			Usage:       "Don't write anything; fail if the config is not formatted",
+		},
+		cli.BoolFlag{
+			Name:        "sort-records",
+			Destination: &args.SortRecords,
+			Usage:       "Sort the records of each domain by label and type",
+		},
+		cli.IntFlag{
+			Name:        "indent",
+			Destination: &args.Indent,
+			Usage:       "Number of spaces to indent by; 0 indents with tabs",
+			Value:       4,
+		},
+		cli.StringFlag{
+			Name:        "quote",
+			Destination: &args.Quote,
+			Usage:       `Quote strings with "single" or "double" quotes`,
+			Value:       "single",
+		},
+	)
+}
+
+// Fmt implements the fmt subcommand. Before writing anything, it checks
+// that the formatted config produces the same configuration.
+func Fmt(args FmtArgs) error {
+	opts := jsfmt.Options{SortRecords: args.SortRecords, Indent: strings.Repeat(" ", args.Indent)}
+	if args.Indent == 0 {
+		opts.Indent = "\t"
+	}
+	switch args.Quote {
+	case "single":
+		opts.Quote = '\''
+	case "double":
+		opts.Quote = '"'
+	default:
+		return errors.Errorf("--quote must be single or double, not %q", args.Quote)
+	}
+
+	src, err := ioutil.ReadFile(args.JSFile)
+	if err != nil {
+		return err
+	}
+	formatted, err := jsfmt.Format(src, opts)
+	if err != nil {
+		return errors.Wrapf(err, "formatting %s", args.JSFile)
+	}
+	if args.Check {
+		if !bytes.Equal(src, formatted) {
+			return errors.Errorf("%s is not formatted; run dnscontrol fmt", args.JSFile)
+		}
+		return nil
+	}
+	if bytes.Equal(src, formatted) && args.Output == "" {
+		return nil
+	}
+
+	if err := args.setupJS(); err != nil {
+		return err
+	}
+	before, err := fmtFingerprint(args.JSFile, src, args.DevMode, args.SortRecords)
+	if err != nil {
+		return err
+	}
+	after, err := fmtFingerprint(args.JSFile, formatted, args.DevMode, args.SortRecords)
+	if err != nil {
+		return errors.Wrap(err, "the formatted config does not run; please report this as a bug")
+	}
+	if before != after {
+		return errors.Errorf("formatting would change what %s configures; please report this as a bug", args.JSFile)
+	}
+
+	out := args.Output
+	if out == "" {
+		out = args.JSFile
+	}
+	if err := ioutil.WriteFile(out, formatted, 0644); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %s\n", out)
+	return nil
+}
+
+// fmtFingerprint runs script and returns the configuration it produces,
+// as JSON. The order of records is ignored if they are being sorted.
+func fmtFingerprint(file string, script []byte, devMode, unordered bool) (string, error) {
+	cfg, err := js.ExecuteJavascriptSource(file, script, devMode)
+	if err != nil {
+		return "", err
+	}
+	if unordered {
+		for _, d := range cfg.Domains {
+			sortRecordsByJSON(d.Records)
+		}
+	}
+	b, err := json.Marshal(cfg)
+	return string(b), err
+}
+
+func sortRecordsByJSON(records models.Records) {
+	keys := make(map[*models.RecordConfig]string, len(records))
+	for _, r := range records {
+		b, _ := json.Marshal(r)
+		keys[r] = string(b)
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return keys[records[i]] < keys[records[j]]
+	})
+}
@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/StackExchange/dnscontrol/pkg/freeze"
+	"github.com/urfave/cli"
+)
+
+var _ = cmd(catUtils, func() *cli.Command {
+	var args FreezeCmdArgs
+	return &cli.Command{
+		Name:      "freeze",
+		Usage:     "freeze domains: push will refuse to change them until they are unfrozen",
+		ArgsUsage: "domain...",
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() == 0 {
+				return cli.NewExitError("At least one domain is required", 1)
+			}
+			args.Domains = ctx.Args()
+			return exit(Freeze(args))
+		},
+		Flags: args.flags(),
+	}
+}())
+
+var _ = cmd(catUtils, func() *cli.Command {
+	var args FreezeCmdArgs
+	return &cli.Command{
+		Name:      "unfreeze",
+		Usage:     "unfreeze domains frozen with the freeze command",
+		ArgsUsage: "domain...",
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() == 0 {
+				return cli.NewExitError("At least one domain is required", 1)
+			}
+			args.Domains = ctx.Args()
+			return exit(Unfreeze(args))
+		},
+		Flags: args.FreezeArgs.flags(),
+	}
+}())
+
+// FreezeArgs encapsulates the flags/args for sub-commands that read or write freeze markers.
+type FreezeArgs struct {
+	FreezeDir string
+}
+
+func (args *FreezeArgs) flags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:        "freeze-dir",
+			Destination: &args.FreezeDir,
+			Usage:       "Directory holding the freeze markers",
+			Value:       freeze.DefaultDir,
+		},
+	}
+}
+
+// FreezeCmdArgs contains all data/flags needed to run freeze and unfreeze, independently of CLI.
+type FreezeCmdArgs struct {
+	FreezeArgs
+	Domains []string
+	Reason  string
+	By      string
+}
+
+func (args *FreezeCmdArgs) flags() []cli.Flag {
+	return append(args.FreezeArgs.flags(),
+		cli.StringFlag{
+			Name:        "reason",
+			Destination: &args.Reason,
+			Usage:       "Why the domains are frozen (for example an incident number)",
+		},
+		cli.StringFlag{
+			Name:        "by",
+			Destination: &args.By,
+			Usage:       "Who froze the domains",
+			Value:       os.Getenv("USER"),
+		},
+	)
+}
+
+// Freeze implements the freeze subcommand.
+func Freeze(args FreezeCmdArgs) error {
+	for _, d := range args.Domains {
+		m := &freeze.Marker{Domain: d, Reason: args.Reason, By: args.By}
+		if err := freeze.Freeze(args.FreezeDir, m); err != nil {
+			return err
+		}
+		fmt.Println(m)
+	}
+	return nil
+}
+
+// Unfreeze implements the unfreeze subcommand.
+func Unfreeze(args FreezeCmdArgs) error {
+	for _, d := range args.Domains {
+		if err := freeze.Unfreeze(args.FreezeDir, d); err != nil {
+			return err
+		}
+		fmt.Printf("%s is no longer frozen\n", d)
+	}
+	return nil
+}
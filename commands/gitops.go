@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// gitopsEvent is the part of a GitHub or GitLab push event the daemon
+// uses. Both name the fields the same.
+type gitopsEvent struct {
+	Ref   string `json:"ref"`   // refs/heads/<branch>
+	After string `json:"after"` // The commit pushed.
+}
+
+// gitopsWebhook receives the push events of GitHub and GitLab, and asks
+// the daemon to pull and run by sending the commit on trigger.
+type gitopsWebhook struct {
+	secret  string
+	branch  func() (string, error) // The branch checked out.
+	trigger chan<- string
+}
+
+func (h *gitopsWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST a push event", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, 25<<20))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.authentic(r, body) {
+		http.Error(w, "bad signature or token", http.StatusUnauthorized)
+		return
+	}
+	event := r.Header.Get("X-GitHub-Event") + r.Header.Get("X-Gitlab-Event")
+	if event != "push" && event != "Push Hook" {
+		w.Write([]byte("ignored: not a push event\n"))
+		return
+	}
+	var e gitopsEvent
+	if err := json.Unmarshal(body, &e); err != nil {
+		http.Error(w, "bad push event: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	branch, err := h.branch()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if e.Ref != "refs/heads/"+branch {
+		w.Write([]byte("ignored: not a push to " + branch + "\n"))
+		return
+	}
+	select {
+	case h.trigger <- e.After:
+	default:
+		// A pull is already pending; it will get this commit too.
+	}
+	w.WriteHeader(http.StatusAccepted)
+	w.Write([]byte("accepted\n"))
+}
+
+// authentic checks the signature of GitHub (X-Hub-Signature-256, or
+// X-Hub-Signature) or the token of GitLab (X-Gitlab-Token).
+func (h *gitopsWebhook) authentic(r *http.Request, body []byte) bool {
+	if token := r.Header.Get("X-Gitlab-Token"); token != "" {
+		return subtle.ConstantTimeCompare([]byte(token), []byte(h.secret)) == 1
+	}
+	sig, newHash := r.Header.Get("X-Hub-Signature-256"), sha256.New
+	if sig == "" {
+		sig, newHash = r.Header.Get("X-Hub-Signature"), sha1.New
+	}
+	parts := strings.SplitN(sig, "=", 2)
+	if len(parts) != 2 {
+		return false
+	}
+	got, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(func() hash.Hash { return newHash() }, []byte(h.secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// gitIn runs git in dir, and returns its output.
+func gitIn(dir string, args ...string) (string, error) {
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		return "", errors.Errorf("git %s: %s", strings.Join(args, " "), strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// gitopsRepo is the git checkout dnsconfig.js is in.
+func gitopsRepo(args GetDNSConfigArgs) string {
+	return filepath.Dir(args.JSFile)
+}
+
+// gitopsBranch returns the branch checked out in dir.
+func gitopsBranch(dir string) (string, error) {
+	branch, err := gitIn(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err == nil && branch == "HEAD" {
+		err = errors.Errorf("%s has no branch checked out", dir)
+	}
+	return branch, err
+}
+
+// domainFingerprints returns the JSON of each domain dnsconfig.js
+// configures, to compare before and after a pull.
+func domainFingerprints(args GetDNSConfigArgs) (map[string]string, error) {
+	cfg, err := GetDNSConfig(args)
+	if err != nil {
+		return nil, err
+	}
+	prints := map[string]string{}
+	for _, d := range cfg.Domains {
+		b, err := json.Marshal(d)
+		if err != nil {
+			return nil, err
+		}
+		prints[d.UniqueName()] = string(b)
+	}
+	return prints, nil
+}
+
+// gitopsPull pulls the checkout of dnsconfig.js, and returns the domains
+// the pull changed or added. Domains removed from dnsconfig.js are left
+// alone, as dnscontrol never deletes zones.
+func gitopsPull(args GetDNSConfigArgs) ([]string, error) {
+	before, err := domainFingerprints(args)
+	if err != nil {
+		// Run all the domains, once the pull fixes dnsconfig.js.
+		before = map[string]string{}
+	}
+	if _, err := gitIn(gitopsRepo(args), "pull", "--ff-only"); err != nil {
+		return nil, err
+	}
+	after, err := domainFingerprints(args)
+	if err != nil {
+		return nil, err
+	}
+	changed := []string{}
+	for name, p := range after {
+		if before[name] != p {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
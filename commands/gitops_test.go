@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// sign returns the X-Hub-Signature(-256) GitHub sends for body.
+func sign(newHash func() hash.Hash, prefix, secret, body string) string {
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write([]byte(body))
+	return prefix + "=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestGitopsAuthentic(t *testing.T) {
+	const body = `{"ref":"refs/heads/main"}`
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		ok     bool
+	}{
+		{"sha256", "X-Hub-Signature-256", sign(sha256.New, "sha256", "s3cret", body), true},
+		{"sha1", "X-Hub-Signature", sign(sha1.New, "sha1", "s3cret", body), true},
+		{"sha256 with another secret", "X-Hub-Signature-256", sign(sha256.New, "sha256", "other", body), false},
+		{"sha1 with another secret", "X-Hub-Signature", sign(sha1.New, "sha1", "other", body), false},
+		{"not hex", "X-Hub-Signature-256", "sha256=zz", false},
+		{"no =", "X-Hub-Signature-256", "sha256", false},
+		{"unsigned", "", "", false},
+		{"gitlab token", "X-Gitlab-Token", "s3cret", true},
+		{"wrong gitlab token", "X-Gitlab-Token", "s3cre", false},
+	}
+	h := &gitopsWebhook{secret: "s3cret"}
+	for _, tst := range tests {
+		r := httptest.NewRequest("POST", "/webhook", strings.NewReader(body))
+		if tst.header != "" {
+			r.Header.Set(tst.header, tst.value)
+		}
+		if got := h.authentic(r, []byte(body)); got != tst.ok {
+			t.Errorf("%s: authentic is %v", tst.name, got)
+		}
+	}
+}
+
+func TestGitopsWebhook(t *testing.T) {
+	const push = `{"ref":"refs/heads/main","after":"abc123"}`
+	trigger := make(chan string, 1)
+	branchErr := error(nil)
+	h := &gitopsWebhook{
+		secret:  "s3cret",
+		branch:  func() (string, error) { return "main", branchErr },
+		trigger: trigger,
+	}
+	post := func(method, event, body string) (int, string) {
+		r := httptest.NewRequest(method, "/webhook", strings.NewReader(body))
+		r.Header.Set("X-GitHub-Event", event)
+		r.Header.Set("X-Hub-Signature-256", sign(sha256.New, "sha256", "s3cret", body))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code, w.Body.String()
+	}
+
+	if code, _ := post("GET", "push", push); code != http.StatusMethodNotAllowed {
+		t.Errorf("GET: %d", code)
+	}
+	r := httptest.NewRequest("POST", "/webhook", strings.NewReader(push))
+	r.Header.Set("X-GitHub-Event", "push")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("unsigned: %d", w.Code)
+	}
+	if code, body := post("POST", "ping", push); code != http.StatusOK || !strings.HasPrefix(body, "ignored") {
+		t.Errorf("ping: %d %s", code, body)
+	}
+	if code, body := post("POST", "push", `{"ref":"refs/heads/dev","after":"def456"}`); code != http.StatusOK || !strings.HasPrefix(body, "ignored") {
+		t.Errorf("push to another branch: %d %s", code, body)
+	}
+	if code, _ := post("POST", "push", `{"ref":`); code != http.StatusBadRequest {
+		t.Errorf("bad JSON: %d", code)
+	}
+	if len(trigger) != 0 {
+		t.Fatalf("triggered a pull for %s", <-trigger)
+	}
+
+	if code, _ := post("POST", "push", push); code != http.StatusAccepted {
+		t.Errorf("push: %d", code)
+	}
+	// A second push while the pull is pending doesn't wait for it.
+	if code, _ := post("POST", "push", strings.Replace(push, "abc123", "def456", 1)); code != http.StatusAccepted {
+		t.Errorf("second push: %d", code)
+	}
+	if got := <-trigger; got != "abc123" || len(trigger) != 0 {
+		t.Errorf("triggered a pull for %s", got)
+	}
+
+	branchErr = errors.New("no branch checked out")
+	if code, _ := post("POST", "push", push); code != http.StatusInternalServerError {
+		t.Errorf("without a branch: %d", code)
+	}
+}
+
+// git runs git in dir, as a user that can commit.
+func git(t *testing.T, dir string, args ...string) string {
+	out, err := gitIn(dir, append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestGitopsPull(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir, err := ioutil.TempDir("", "dnscontrol-gitops")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	upstream, checkout := filepath.Join(dir, "upstream"), filepath.Join(dir, "checkout")
+	commit := func(js string) {
+		if err := ioutil.WriteFile(filepath.Join(upstream, "dnsconfig.js"), []byte(js), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git(t, upstream, "add", "dnsconfig.js")
+		git(t, upstream, "commit", "-q", "-m", "update")
+	}
+	domain := func(name, ip string) string {
+		return `D("` + name + `", NewRegistrar("none", "NONE"), A("@", "` + ip + `"));` + "\n"
+	}
+
+	if err := os.Mkdir(upstream, 0755); err != nil {
+		t.Fatal(err)
+	}
+	git(t, upstream, "init", "-q")
+	commit(domain("a.com", "192.0.2.1") + domain("b.com", "192.0.2.2") + domain("c.com", "192.0.2.3"))
+	git(t, dir, "clone", "-q", upstream, checkout)
+	args := GetDNSConfigArgs{ExecuteDSLArgs: ExecuteDSLArgs{JSFile: filepath.Join(checkout, "dnsconfig.js")}}
+
+	branch, err := gitopsBranch(checkout)
+	if err != nil || branch != git(t, upstream, "rev-parse", "--abbrev-ref", "HEAD") {
+		t.Errorf("branch %q, err %v", branch, err)
+	}
+
+	// b.com changes, c.com is removed, and d.com is added.
+	commit(domain("a.com", "192.0.2.1") + domain("b.com", "192.0.2.22") + domain("d.com", "192.0.2.4"))
+	changed, err := gitopsPull(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(changed, " "); got != "b.com d.com" {
+		t.Errorf("changed %q, want b.com d.com", got)
+	}
+
+	// Once a broken dnsconfig.js is fixed, every domain runs.
+	commit("D(")
+	if _, err := gitopsPull(args); err == nil {
+		t.Error("pulled a broken dnsconfig.js without an error")
+	}
+	commit(domain("a.com", "192.0.2.1") + domain("b.com", "192.0.2.22") + domain("d.com", "192.0.2.4"))
+	changed, err = gitopsPull(args)
+	if got := strings.Join(changed, " "); err != nil || got != "a.com b.com d.com" {
+		t.Errorf("changed %q (err %v), want a.com b.com d.com", got, err)
+	}
+
+	// A checkout that can't fast-forward isn't pulled.
+	if err := ioutil.WriteFile(args.JSFile, []byte(domain("a.com", "192.0.2.9")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(t, checkout, "commit", "-q", "-a", "-m", "local")
+	commit(domain("a.com", "192.0.2.1"))
+	if _, err := gitopsPull(args); err == nil || !strings.Contains(err.Error(), "git pull") {
+		t.Errorf("err is %v, want a failed git pull", err)
+	}
+}
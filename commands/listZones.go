@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/StackExchange/dnscontrol/providers"
+	"github.com/StackExchange/dnscontrol/providers/config"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var _ = cmd(catUtils, func() *cli.Command {
+	var args ListZonesArgs
+	return &cli.Command{
+		Name:      "list-zones",
+		Usage:     "lists all zones a DNS provider has, one per line",
+		ArgsUsage: "credkey providertype",
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() != 2 {
+				return cli.NewExitError("Arguments should be: credkey providertype (Ex: cpanel CPANEL)", 1)
+			}
+			args.CredName = ctx.Args().Get(0)
+			args.ProviderType = ctx.Args().Get(1)
+			return exit(ListZones(args))
+		},
+		Flags: args.flags(),
+	}
+}())
+
+// ListZonesArgs args required for the list-zones subcommand.
+type ListZonesArgs struct {
+	GetCredentialsArgs
+	CredName     string // key in creds.json
+	ProviderType string // provider type, as used in NewDnsProvider()
+}
+
+func (args *ListZonesArgs) flags() []cli.Flag {
+	return args.GetCredentialsArgs.flags()
+}
+
+// ListZones contains all data/flags needed to run list-zones, independently of CLI.
+func ListZones(args ListZonesArgs) error {
+	providerConfigs, err := config.LoadProviderConfigs(args.CredsFile)
+	if err != nil {
+		return err
+	}
+	provider, err := providers.CreateDNSProvider(args.ProviderType, providerConfigs[args.CredName], nil)
+	if err != nil {
+		return err
+	}
+	lister, ok := provider.(providers.ZoneLister)
+	if !ok {
+		return errors.Errorf("provider type %s can not list zones", args.ProviderType)
+	}
+	zones, err := lister.ListZones()
+	if err != nil {
+		return err
+	}
+	for _, z := range zones {
+		fmt.Println(z)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package commands
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/notifications"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/StackExchange/dnscontrol/pkg/redact"
+	"github.com/StackExchange/dnscontrol/pkg/report"
+	"github.com/StackExchange/dnscontrol/pkg/zonehash"
+)
+
+// runner runs the domains of a preview or push.
+type runner struct {
+	args        PreviewArgs
+	push        bool
+	interactive bool
+	breakGlass  bool
+	notifier    notifications.Notifier
+
+	// With limits, push plans every domain before running any correction
+	// (see planAll), and aborted is set if they are exceeded.
+	plans   map[*models.DomainConfig]*domainPlan
+	aborted bool
+
+	// With --parallel, caps limits how many domains may call each
+	// provider at once (see acquire), and hashesMu guards the zone hash
+	// history.
+	caps     map[string]chan struct{}
+	hashesMu sync.Mutex
+}
+
+// runDomains runs domains, args.Parallel at a time, and returns the total
+// number of corrections and whether any of them failed.
+func (r *runner) runDomains(domains []*models.DomainConfig, out printer.CLI) (totalCorrections int, anyErrors bool, err error) {
+	if r.args.Parallel <= 1 {
+		for _, domain := range domains {
+			n, failed, err := r.runDomain(domain, out)
+			totalCorrections += n
+			anyErrors = anyErrors || failed
+			if err != nil {
+				return totalCorrections, anyErrors, err
+			}
+		}
+		return totalCorrections, anyErrors, nil
+	}
+
+	r.caps = map[string]chan struct{}{}
+	for _, domain := range domains {
+		r.addCap(domain.RegistrarInstance.ProviderBase)
+		for _, p := range domain.DNSProviderInstances {
+			r.addCap(p.ProviderBase)
+		}
+	}
+	r.notifier = &lockedNotifier{Notifier: r.notifier}
+
+	// Each domain prints to its own buffer; the buffers are passed on in
+	// the order of the domains, so the output reads as if they ran one
+	// after the other. The domains start in order too, and once one
+	// fails with an error, those that haven't started yet are skipped.
+	type result struct {
+		out         printer.CLI
+		flush       func()
+		corrections int
+		anyErrors   bool
+		err         error
+		skipped     bool
+		done        chan struct{}
+	}
+	results := make([]*result, len(domains))
+	slots := make(chan struct{}, r.args.Parallel)
+	stop := make(chan struct{})
+	var stopOnce sync.Once
+	for i, domain := range domains {
+		res := &result{done: make(chan struct{})}
+		res.out, res.flush = forkOutput(out)
+		results[i] = res
+		slots <- struct{}{}
+		select {
+		case <-stop:
+			<-slots
+			res.skipped = true
+			close(res.done)
+			continue
+		default:
+		}
+		go func(domain *models.DomainConfig) {
+			defer redact.RecoverPanic()
+			defer close(res.done)
+			defer func() { <-slots }()
+			res.corrections, res.anyErrors, res.err = r.runDomain(domain, res.out)
+			if res.err != nil {
+				stopOnce.Do(func() { close(stop) })
+			}
+		}(domain)
+	}
+	for _, res := range results {
+		<-res.done
+		if res.skipped {
+			continue
+		}
+		res.flush()
+		totalCorrections += res.corrections
+		anyErrors = anyErrors || res.anyErrors
+		if res.err != nil && err == nil {
+			err = res.err
+		}
+	}
+	return totalCorrections, anyErrors, err
+}
+
+// addCap adds the cap of provider p: the MaxParallel of its credentials,
+// or 1, as not all providers can be used for several domains at once.
+func (r *runner) addCap(p models.ProviderBase) {
+	if p.Name == "" || r.caps[p.Name] != nil {
+		return
+	}
+	n := p.MaxParallel
+	if n < 1 {
+		n = 1
+	}
+	r.caps[p.Name] = make(chan struct{}, n)
+}
+
+// acquire waits until the providers named can be called for one more
+// domain, and returns the function that releases them.
+func (r *runner) acquire(names ...string) (release func()) {
+	if r.caps == nil {
+		return func() {}
+	}
+	// Always in the same order, so domains can't deadlock each other.
+	sort.Strings(names)
+	var held []chan struct{}
+	for i, name := range names {
+		c := r.caps[name]
+		if c == nil || (i > 0 && name == names[i-1]) {
+			continue
+		}
+		c <- struct{}{}
+		held = append(held, c)
+	}
+	return func() {
+		for _, c := range held {
+			<-c
+		}
+	}
+}
+
+// providerNames returns the names of the DNS providers of domain.
+func providerNames(domain *models.DomainConfig) []string {
+	var names []string
+	for _, p := range domain.DNSProviderInstances {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// appendHashes adds e to the zone hash history.
+func (r *runner) appendHashes(e *zonehash.Entry) error {
+	r.hashesMu.Lock()
+	defer r.hashesMu.Unlock()
+	return zonehash.Append(r.args.ZoneHashHistory, e)
+}
+
+// forkOutput returns a printer.CLI that keeps what out would print for one
+// of the domains run in parallel, and the function that passes it on to
+// out. out prints to printer.DefaultPrinter, and may be a report.Recorder.
+func forkOutput(out printer.CLI) (printer.CLI, func()) {
+	buf := &bytes.Buffer{}
+	d := printer.DefaultPrinter
+	var fork printer.CLI = printer.ConsolePrinter{Writer: buf, Verbose: d.Verbose, Color: d.Color, Width: d.Width}
+	rec, ok := out.(*report.Recorder)
+	var child *report.Recorder
+	if ok {
+		child = report.NewRecorder(fork, rec.Run.Push)
+		fork = child
+	}
+	return fork, func() {
+		printer.DefaultPrinter.Writer.Write(buf.Bytes())
+		if child != nil {
+			rec.Merge(child)
+		}
+	}
+}
+
+// lockedNotifier lets the domains run in parallel share a notifier.
+type lockedNotifier struct {
+	sync.Mutex
+	notifications.Notifier
+}
+
+// Notify passes a notification on, one at a time.
+func (n *lockedNotifier) Notify(domain, provider, message string, err error, preview bool) {
+	n.Lock()
+	defer n.Unlock()
+	n.Notifier.Notify(domain, provider, message, err, preview)
+}
+
+// NotifyError passes an error on, one at a time, if the notifier wants it.
+func (n *lockedNotifier) NotifyError(domain, provider, message string, err error) {
+	if en, ok := n.Notifier.(notifications.ErrorNotifier); ok {
+		n.Lock()
+		defer n.Unlock()
+		en.NotifyError(domain, provider, message, err)
+	}
+}
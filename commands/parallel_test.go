@@ -0,0 +1,185 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/notifications"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/StackExchange/dnscontrol/pkg/report"
+	"github.com/pkg/errors"
+)
+
+// captureOutput makes printer.DefaultPrinter, where the domains run in
+// parallel print to, print to the buffer returned until restore is called.
+func captureOutput() (buf *bytes.Buffer, restore func()) {
+	buf = &bytes.Buffer{}
+	old := printer.DefaultPrinter.Writer
+	printer.DefaultPrinter.Writer = buf
+	return buf, func() { printer.DefaultPrinter.Writer = old }
+}
+
+// delays returns a fakeProvider.before that waits as long as given for
+// each domain.
+func delays(d map[string]time.Duration) func(string) {
+	return func(domain string) {
+		time.Sleep(d[domain])
+	}
+}
+
+func TestRunDomainsConcurrency(t *testing.T) {
+	tests := []struct {
+		name        string
+		parallel    int
+		maxParallel int // Of the provider all the domains use.
+		want        int // Calls to the provider at once.
+	}{
+		{"sequential", 1, 5, 1},
+		{"provider without max_parallel", 3, 0, 1},
+		{"provider limit", 3, 2, 2},
+		{"parallel limit", 3, 5, 3},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			_, restore := captureOutput()
+			defer restore()
+			var mu sync.Mutex
+			now, most := 0, 0
+			ran := &runLog{}
+			p := &fakeProvider{name: "p", ran: ran, corrections: map[string]int{}, before: func(string) {
+				mu.Lock()
+				now++
+				if now > most {
+					most = now
+				}
+				mu.Unlock()
+				time.Sleep(20 * time.Millisecond)
+				mu.Lock()
+				now--
+				mu.Unlock()
+			}}
+			var domains []*models.DomainConfig
+			for _, name := range []string{"a.com", "b.com", "c.com", "d.com", "e.com", "f.com"} {
+				p.corrections[name] = 1
+				dc := fakeDomain(name, p)
+				dc.DNSProviderInstances[0].MaxParallel = tst.maxParallel
+				domains = append(domains, dc)
+			}
+			_, _, anyErrors, err := fakeRun(PreviewArgs{Parallel: tst.parallel}, true, domains...)
+			if err != nil || anyErrors {
+				t.Fatalf("err %v, anyErrors %v", err, anyErrors)
+			}
+			if most != tst.want {
+				t.Errorf("%d domains called the provider at once, want %d", most, tst.want)
+			}
+			if n := len(strings.Fields(ran.String())); n != len(domains) {
+				t.Errorf("ran %d corrections, want %d", n, len(domains))
+			}
+		})
+	}
+}
+
+func TestAcquire(t *testing.T) {
+	r := &runner{caps: map[string]chan struct{}{
+		"a": make(chan struct{}, 1),
+		"b": make(chan struct{}, 2),
+	}}
+	release := r.acquire("b", "a", "a", "unknown")
+	if len(r.caps["a"]) != 1 || len(r.caps["b"]) != 1 {
+		t.Fatalf("holding a %d times and b %d times, want once each", len(r.caps["a"]), len(r.caps["b"]))
+	}
+	releaseB := r.acquire("b")
+	if len(r.caps["b"]) != 2 {
+		t.Fatalf("b, which allows 2, was not acquired a second time")
+	}
+	acquired := make(chan struct{})
+	go func() {
+		r.acquire("a")()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("a, which allows 1, was acquired twice")
+	case <-time.After(20 * time.Millisecond):
+	}
+	release()
+	<-acquired
+	releaseB()
+	if len(r.caps["a"]) != 0 || len(r.caps["b"]) != 0 {
+		t.Errorf("still holding a %d times and b %d times", len(r.caps["a"]), len(r.caps["b"]))
+	}
+
+	// Without --parallel, there is nothing to wait for.
+	(&runner{}).acquire("a", "b")()
+}
+
+func TestForkOutputOrder(t *testing.T) {
+	buf, restore := captureOutput()
+	defer restore()
+	ran := &runLog{}
+	before := delays(map[string]time.Duration{"a.com": 40 * time.Millisecond, "c.com": 20 * time.Millisecond})
+	var domains []*models.DomainConfig
+	for _, name := range []string{"a.com", "b.com", "c.com"} {
+		p := &fakeProvider{name: "p-" + name, corrections: map[string]int{name: 1}, ran: ran, before: before}
+		domains = append(domains, fakeDomain(name, p))
+	}
+	rec := report.NewRecorder(printer.DefaultPrinter, true)
+	r := &runner{args: PreviewArgs{Parallel: 3}, push: true, notifier: notifications.Multi()}
+	if _, _, err := r.runDomains(domains, rec); err != nil {
+		t.Fatal(err)
+	}
+
+	// They ran in parallel...
+	if got, want := ran.String(), "p-b.com:b.com#1 p-c.com:c.com#1 p-a.com:a.com#1"; got != want {
+		t.Errorf("ran %q, want %q", got, want)
+	}
+	// ...but are printed and recorded in order.
+	out := buf.String()
+	last := -1
+	for _, s := range []string{"Domain: a.com", "p-a.com:a.com#1", "Domain: b.com", "p-b.com:b.com#1", "Domain: c.com", "p-c.com:c.com#1"} {
+		i := strings.Index(out, s)
+		if i < last {
+			t.Errorf("%q is out of order:\n%s", s, out)
+		}
+		last = i
+	}
+	var names []string
+	for _, d := range rec.Run.Domains {
+		names = append(names, d.Name)
+	}
+	if got := strings.Join(names, " "); got != "a.com b.com c.com" {
+		t.Errorf("recorded %s, want a.com b.com c.com", got)
+	}
+}
+
+func TestStopOnError(t *testing.T) {
+	buf, restore := captureOutput()
+	defer restore()
+	ran := &runLog{}
+	bad := &fakeProvider{name: "bad", nsErr: errors.New("no nameservers"), ran: ran}
+	slow := &fakeProvider{name: "slow", corrections: map[string]int{"slow.com": 1}, ran: ran, before: delays(map[string]time.Duration{"slow.com": 50 * time.Millisecond})}
+	other := &fakeProvider{name: "other", corrections: map[string]int{"c.com": 1, "d.com": 1}, ran: ran}
+	domains := []*models.DomainConfig{
+		fakeDomain("bad.com", bad),
+		fakeDomain("slow.com", slow),
+		fakeDomain("c.com", other),
+		fakeDomain("d.com", other),
+	}
+	domains[0].DNSProviderInstances[0].NumberOfNameservers = 1
+	_, _, _, err := fakeRun(PreviewArgs{Parallel: 2}, true, domains...)
+	if err == nil || err.Error() != "no nameservers" {
+		t.Errorf("err is %v, want no nameservers", err)
+	}
+	// slow.com had started, so it finishes; the others are skipped.
+	if got := ran.String(); got != "slow:slow.com#1" {
+		t.Errorf("ran %q, want slow:slow.com#1", got)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "Domain: slow.com") || strings.Contains(out, "Domain: c.com") || strings.Contains(out, "Domain: d.com") {
+		t.Errorf("wrong domains printed:\n%s", out)
+	}
+}
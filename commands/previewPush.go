@@ -1,17 +1,33 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
 
 	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/freeze"
+	"github.com/StackExchange/dnscontrol/pkg/healthcheck"
 	"github.com/StackExchange/dnscontrol/pkg/nameservers"
 	"github.com/StackExchange/dnscontrol/pkg/normalize"
 	"github.com/StackExchange/dnscontrol/pkg/notifications"
+	"github.com/StackExchange/dnscontrol/pkg/prcomment"
 	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/StackExchange/dnscontrol/pkg/propagation"
+	"github.com/StackExchange/dnscontrol/pkg/redact"
+	"github.com/StackExchange/dnscontrol/pkg/report"
+	"github.com/StackExchange/dnscontrol/pkg/runreport"
+	"github.com/StackExchange/dnscontrol/pkg/verification"
+	"github.com/StackExchange/dnscontrol/pkg/zonehash"
 	"github.com/StackExchange/dnscontrol/providers"
 	"github.com/StackExchange/dnscontrol/providers/config"
+	"github.com/StackExchange/dnscontrol/providers/diff"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli"
 )
@@ -22,9 +38,16 @@ var _ = cmd(catMain, func() *cli.Command {
 		Name:  "preview",
 		Usage: "read live configuration and identify changes to be made, without applying them",
 		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() != 0 {
+				return cli.NewExitError("Unexpected arguments. To read IR from a file, use --ir (--json is the JSON output)", 1)
+			}
 			return exit(Preview(args))
 		},
-		Flags: args.flags(),
+		Flags: append(args.flags(), cli.StringFlag{
+			Name:        "snapshot",
+			Destination: &args.Snapshot,
+			Usage:       `compare with a saved copy of the zones instead of the providers: a directory of zone files (see write-zones) or a print-ir JSON file`,
+		}),
 	}
 }())
 
@@ -33,14 +56,42 @@ type PreviewArgs struct {
 	GetDNSConfigArgs
 	GetCredentialsArgs
 	FilterArgs
-	Notify bool
-	WarnChanges bool
+	FreezeArgs
+	VerificationArgs
+	Notify          bool
+	WarnChanges     bool
+	Template        string
+	JSON            bool
+	PRComment       bool
+	ZoneHashes      bool
+	ZoneHashHistory string
+	Failover        bool
+	RunReport       string
+	Report          string
+	NoColor         bool
+	Parallel        int
+	Snapshot        string // Only for preview.
+	// Set by the flags of push. Domains can set their own with metadata.
+	MaxChanges      int
+	MaxDeletes      int
+	AuditLog        string
+	Verify          bool
+	VerifyResolvers string
+	VerifyTimeout   time.Duration
 }
 
 func (args *PreviewArgs) flags() []cli.Flag {
-	flags := args.GetDNSConfigArgs.flags()
+	var flags []cli.Flag
+	for _, f := range args.GetDNSConfigArgs.flags() {
+		// --json used to be a hidden alias of --ir. preview and push use it for their output.
+		if f.GetName() != "json" {
+			flags = append(flags, f)
+		}
+	}
 	flags = append(flags, args.GetCredentialsArgs.flags()...)
 	flags = append(flags, args.FilterArgs.flags()...)
+	flags = append(flags, args.FreezeArgs.flags()...)
+	flags = append(flags, args.VerificationArgs.flags()...)
 	flags = append(flags, cli.BoolFlag{
 		Name:        "notify",
 		Destination: &args.Notify,
@@ -49,7 +100,58 @@ func (args *PreviewArgs) flags() []cli.Flag {
 	flags = append(flags, cli.BoolFlag{
 		Name:        "expect-no-changes",
 		Destination: &args.WarnChanges,
-		Usage:       `set to true for non-zero return code if there are changes`,
+		Usage:       `set to true to exit with code 2 if there are changes (errors exit with 1)`,
+	})
+	flags = append(flags, cli.StringFlag{
+		Name:        "template",
+		Destination: &args.Template,
+		Usage:       `Go text/template file to render the results with. The rendered results go to stdout, everything else to stderr`,
+	})
+	flags = append(flags, cli.BoolFlag{
+		Name:        "json",
+		Destination: &args.JSON,
+		Usage:       `print the results as JSON (see docs/json-output.md) to stdout, everything else to stderr`,
+	})
+	flags = append(flags, cli.BoolFlag{
+		Name:        "pr-comment",
+		Destination: &args.PRComment,
+		Usage:       `post the results as a comment on the GitHub pull request or GitLab merge request being built`,
+	})
+	flags = append(flags, cli.BoolFlag{
+		Name:        "zone-hashes",
+		Destination: &args.ZoneHashes,
+		Usage:       `print (and record in --template output) hashes of the desired and actual records of each zone`,
+	})
+	flags = append(flags, cli.StringFlag{
+		Name:        "zone-hash-history",
+		Destination: &args.ZoneHashHistory,
+		Usage:       `append the zone hashes to this file, one JSON object per line (implies --zone-hashes)`,
+	})
+	flags = append(flags, cli.BoolFlag{
+		Name:        "failover",
+		Destination: &args.Failover,
+		Usage:       `if a DNS provider of a domain fails, go on with the domain's other DNS providers instead of skipping the domain`,
+	})
+	flags = append(flags, cli.IntFlag{
+		Name:        "parallel",
+		Destination: &args.Parallel,
+		Usage:       `run this many domains at once. Each provider is used for one domain at a time, unless its creds.json entry sets "_max_parallel"`,
+		Value:       1,
+	})
+	flags = append(flags, cli.StringFlag{
+		Name:        "run-report",
+		Destination: &args.RunReport,
+		Usage:       `write versions, flags, providers, counts, durations and error categories of the run (no secrets) to this file, to attach to bug reports`,
+	})
+	flags = append(flags, cli.StringFlag{
+		Name:        "report",
+		Destination: &args.Report,
+		Usage:       `write the changes of every domain, with the before and after values of records, to this .md or .html file, to attach to change tickets`,
+	})
+	flags = append(flags, cli.BoolFlag{
+		Name:        "no-color",
+		Destination: &args.NoColor,
+		Usage:       `don't color the changes, even on a terminal (setting NO_COLOR does the same)`,
 	})
 	return flags
 }
@@ -60,6 +162,9 @@ var _ = cmd(catMain, func() *cli.Command {
 		Name:  "push",
 		Usage: "identify changes to be made, and perform them",
 		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() != 0 {
+				return cli.NewExitError("Unexpected arguments. To read IR from a file, use --ir (--json is the JSON output)", 1)
+			}
 			return exit(Push(args))
 		},
 		Flags: args.flags(),
@@ -70,6 +175,7 @@ var _ = cmd(catMain, func() *cli.Command {
 type PushArgs struct {
 	PreviewArgs
 	Interactive bool
+	BreakGlass  bool
 }
 
 func (args *PushArgs) flags() []cli.Flag {
@@ -79,104 +185,796 @@ func (args *PushArgs) flags() []cli.Flag {
 		Destination: &args.Interactive,
 		Usage:       "Interactive. Confirm or Exclude each correction before they run",
 	})
+	flags = append(flags, cli.BoolFlag{
+		Name:        "break-glass",
+		Destination: &args.BreakGlass,
+		Usage:       "Push changes to frozen domains too",
+	})
+	flags = append(flags, cli.IntFlag{
+		Name:        "max-changes",
+		Destination: &args.MaxChanges,
+		Usage:       "Abort if a domain would have more than this many records changed at a DNS provider; 0 is no limit (MAX_CHANGES() overrides it)",
+	})
+	flags = append(flags, cli.IntFlag{
+		Name:        "max-deletes",
+		Destination: &args.MaxDeletes,
+		Usage:       "Abort if a domain would have more than this many records deleted at a DNS provider; 0 is no limit (MAX_DELETES() overrides it)",
+	})
+	flags = append(flags, cli.StringFlag{
+		Name:        "audit-log",
+		Destination: &args.AuditLog,
+		EnvVar:      "DNSCONTROL_AUDIT_LOG",
+		Usage:       "Append a JSON line for each correction pushed to this file",
+	})
+	flags = append(flags, cli.BoolFlag{
+		Name:        "verify",
+		Destination: &args.Verify,
+		Usage:       "After pushing, check that the authoritative nameservers of each domain serve the changes",
+	})
+	flags = append(flags, cli.StringFlag{
+		Name:        "verify-resolvers",
+		Destination: &args.VerifyResolvers,
+		Usage:       "After pushing, check that these resolvers (comma separated, such as 1.1.1.1,8.8.8.8) serve the changes",
+	})
+	flags = append(flags, cli.DurationFlag{
+		Name:        "verify-timeout",
+		Destination: &args.VerifyTimeout,
+		Value:       2 * time.Minute,
+		Usage:       "How long --verify and --verify-resolvers wait for the changes to be served",
+	})
 	return flags
 }
 
 // Preview implements the preview subcommand.
 func Preview(args PreviewArgs) error {
-	return run(args, false, false, printer.DefaultPrinter)
+	return runAndRender(args, false, false, false)
 }
 
 // Push implements the push subcommand.
 func Push(args PushArgs) error {
-	return run(args.PreviewArgs, true, args.Interactive, printer.DefaultPrinter)
+	return runAndRender(args.PreviewArgs, true, args.Interactive, args.BreakGlass)
+}
+
+// runAndRender calls run, renders its results with args.Template if given
+// and posts them as a pull request comment or writes them as a report if
+// asked to.
+func runAndRender(args PreviewArgs, push bool, interactive bool, breakGlass bool) error {
+	console(args, os.Stdout)
+	if args.Template == "" && !args.JSON && !args.PRComment && args.RunReport == "" && args.Report == "" {
+		return run(args, push, interactive, breakGlass, printer.DefaultPrinter)
+	}
+	if args.Template != "" && args.JSON {
+		return errors.Errorf("--template and --json can't be used together")
+	}
+	var tmpl *template.Template
+	var poster prcomment.Poster
+	var err error
+	if args.Template != "" {
+		if tmpl, err = report.ParseTemplateFile(args.Template); err != nil {
+			return err
+		}
+	}
+	results := redact.Writer(os.Stdout)
+	if args.Template != "" || args.JSON {
+		// Only the results go to stdout. Everything else goes to stderr,
+		// including what providers print with fmt.Printf.
+		stdout, writer := os.Stdout, printer.DefaultPrinter.Writer
+		os.Stdout = os.Stderr
+		printer.DefaultPrinter.Writer = redact.Writer(os.Stderr)
+		console(args, os.Stderr)
+		defer func() {
+			os.Stdout = stdout
+			printer.DefaultPrinter.Writer = writer
+		}()
+	}
+	if args.Report != "" {
+		if err := report.CheckDocumentName(args.Report); err != nil {
+			return errors.Wrap(err, "--report")
+		}
+	}
+	if args.PRComment {
+		if poster, err = prcomment.FromEnv(); err != nil {
+			return errors.Wrap(err, "--pr-comment")
+		}
+	}
+	rec := report.NewRecorder(printer.DefaultPrinter, push)
+	start := time.Now()
+	runErr := run(args, push, interactive, breakGlass, rec)
+	if args.RunReport != "" {
+		command := "preview"
+		if push {
+			command = "push"
+		}
+		if err := runreport.New(version, command, os.Args[1:], rec, time.Since(start), runErr).Write(args.RunReport); err != nil {
+			printer.Warnf("Could not write the run report: %s\n", err)
+		}
+	}
+	if args.Report != "" {
+		if err := report.WriteDocument(args.Report, &report.Document{Run: &rec.Run, Generated: time.Now()}); err != nil {
+			return err
+		}
+	}
+	if tmpl != nil {
+		if err := tmpl.Execute(results, &rec.Run); err != nil {
+			return errors.Wrap(err, "rendering template")
+		}
+	}
+	if args.JSON {
+		b, err := json.MarshalIndent(rec.Run, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(results, string(b))
+	}
+	if poster != nil {
+		body, err := prcomment.Render(&rec.Run)
+		if err != nil {
+			return err
+		}
+		if err := poster.Post(redact.String(body)); err != nil {
+			return errors.Wrap(err, "posting the pull request comment")
+		}
+	}
+	return runErr
+}
+
+// console sets up printer.DefaultPrinter for printing to f.
+func console(args PreviewArgs, f *os.File) {
+	printer.DefaultPrinter.Color = !args.NoColor && printer.UseColor(f)
+	printer.DefaultPrinter.Width = printer.TerminalWidth(f)
 }
 
 // run is the main routine common to preview/push
-func run(args PreviewArgs, push bool, interactive bool, out printer.CLI) error {
+func run(args PreviewArgs, push bool, interactive bool, breakGlass bool, out printer.CLI) error {
 	// TODO: make truly CLI independent. Perhaps return results on a channel as they occur
 	cfg, err := GetDNSConfig(args.GetDNSConfigArgs)
 	if err != nil {
 		return err
 	}
 	errs := normalize.NormalizeAndValidateConfig(cfg)
-	if PrintValidationErrors(errs) {
+	fatal := PrintValidationErrors(errs)
+	if cr, ok := out.(report.ConfigRecorder); ok {
+		cr.Config(cfg, !fatal)
+	}
+	if fatal {
 		return errors.Errorf("Exiting due to validation errors")
 	}
+	if args.Snapshot != "" {
+		return previewSnapshot(cfg, args, out)
+	}
 	// TODO:
 	notifier, err := InitializeProviders(args.CredsFile, cfg, args.Notify)
 	if err != nil {
 		return err
 	}
-	anyErrors := false
-	totalCorrections := 0
-DomainLoop:
+	if push && args.AuditLog != "" {
+		audit, err := notifications.NewAuditLog(args.AuditLog)
+		if err != nil {
+			return err
+		}
+		notifier = notifications.Multi(notifier, audit)
+	}
+	// The batched notifiers send what they have even if the run fails.
+	defer notifier.Done()
+	if args.Parallel > 1 && interactive {
+		return errors.Errorf("-i can't be used with --parallel")
+	}
+	unmatchedDomains, unmatchedProviders := args.unmatchedFilters(cfg)
+	for _, d := range unmatchedDomains {
+		out.Warnf("--domains: %s matches no domain\n", d)
+	}
+	for _, p := range unmatchedProviders {
+		out.Warnf("--providers: there is no provider named %s or of that type\n", p)
+	}
+	var domains []*models.DomainConfig
 	for _, domain := range cfg.Domains {
-		if !args.shouldRunDomain(domain.Name) {
-			continue
+		if args.shouldRunDomain(domain.UniqueName()) {
+			domains = append(domains, domain)
 		}
-		out.StartDomain(domain.Name)
-		nsList, err := nameservers.DetermineNameservers(domain)
-		if err != nil {
+	}
+	r := &runner{args: args, push: push, interactive: interactive, breakGlass: breakGlass, notifier: notifier}
+	if push && limited(args, domains) {
+		// Check the limits of every domain before any of them is pushed.
+		if _, err := r.planAll(domains); err != nil {
 			return err
 		}
-		domain.Nameservers = nsList
-		nameservers.AddNSRecords(domain)
-		for _, provider := range domain.DNSProviderInstances {
-			dc, err := domain.Copy()
-			if err != nil {
-				return err
-			}
-			shouldrun := args.shouldRunProvider(provider.Name, dc)
-			out.StartDNSProvider(provider.Name, !shouldrun)
-			if !shouldrun {
-				continue
-			}
-			corrections, err := provider.Driver.GetDomainCorrections(dc)
-			out.EndProvider(len(corrections), err)
-			if err != nil {
-				anyErrors = true
-				continue DomainLoop
-			}
-			totalCorrections += len(corrections)
-			anyErrors = printOrRunCorrections(domain.Name, provider.Name, corrections, out, push, interactive, notifier) || anyErrors
+	}
+	totalCorrections, anyErrors, err := r.runDomains(domains, out)
+	if err != nil {
+		return err
+	}
+	if os.Getenv("TEAMCITY_VERSION") != "" {
+		fmt.Fprintf(os.Stderr, "##teamcity[buildStatus status='SUCCESS' text='%d corrections']", totalCorrections)
+	}
+	out.Printf("Done. %d corrections.\n", totalCorrections)
+	if r.aborted {
+		return errors.Errorf("Aborting push: the changes exceed the limits, so none were made")
+	}
+	if anyErrors {
+		return errors.Errorf("Completed with errors")
+	}
+	if totalCorrections != 0 && args.WarnChanges {
+		return errPendingChanges
+	}
+	return nil
+}
+
+// errPendingChanges is returned by --expect-no-changes if there are
+// corrections. exit turns it into exit code 2, so that CI can tell
+// drift from a failed run.
+var errPendingChanges = errors.New("There are pending changes")
+
+// fail tells the notifiers that want it about an error that kept the
+// corrections of domain (at provider, if given) from running.
+func (r *runner) fail(domain, provider, msg string, err error) {
+	if en, ok := r.notifier.(notifications.ErrorNotifier); ok && r.push {
+		en.NotifyError(domain, provider, msg, err)
+	}
+}
+
+// domainPlan is what runDomain works out for a domain before it runs any
+// of its corrections.
+type domainPlan struct {
+	push      bool   // Whether the corrections may run; false if the domain is frozen.
+	notes     *notes // What to print before the DNS providers.
+	failed    bool   // The records of REPLICATE_FROM couldn't be read.
+	hashes    *zonehash.Entry
+	providers []*providerPlan
+}
+
+// providerPlan holds the corrections of one DNS provider of a domain.
+type providerPlan struct {
+	provider    *models.DNSProviderInstance
+	skip        bool // The provider is the REPLICATE_FROM source, or --providers leaves it out.
+	corrections []*models.Correction
+	changes     *diff.Result
+	err         error
+	limit       error // Set if the changes exceed the limits of checkThresholds.
+}
+
+// notes keeps what is printed while a domain is planned, so that it can be
+// printed with the rest of the domain.
+type notes []func(printer.Printer)
+
+// Debugf keeps a debug message.
+func (n *notes) Debugf(format string, args ...interface{}) {
+	*n = append(*n, func(out printer.Printer) { out.Debugf(format, args...) })
+}
+
+// Printf keeps a message.
+func (n *notes) Printf(format string, args ...interface{}) {
+	*n = append(*n, func(out printer.Printer) { out.Printf(format, args...) })
+}
+
+// Warnf keeps a warning.
+func (n *notes) Warnf(format string, args ...interface{}) {
+	*n = append(*n, func(out printer.Printer) { out.Warnf(format, args...) })
+}
+
+// replay prints what was kept to out.
+func (n *notes) replay(out printer.Printer) {
+	for _, f := range *n {
+		f(out)
+	}
+}
+
+// limited reports whether push has to check the changes of domains against
+// MAX_CHANGES(), MAX_DELETES(), --max-changes or --max-deletes.
+func limited(args PreviewArgs, domains []*models.DomainConfig) bool {
+	if args.MaxChanges > 0 || args.MaxDeletes > 0 {
+		return true
+	}
+	for _, domain := range domains {
+		if domain.Metadata["max_changes"] != "" || domain.Metadata["max_deletes"] != "" {
+			return true
 		}
-		run := args.shouldRunProvider(domain.RegistrarName, domain)
-		out.StartRegistrar(domain.RegistrarName, !run)
-		if !run {
-			continue
+	}
+	return false
+}
+
+// planAll plans all of domains before any of their corrections run, and
+// reports whether the changes of a domain that would be pushed exceed its
+// limits. If so, no domain is pushed: runDomain only prints what would
+// have been done.
+func (r *runner) planAll(domains []*models.DomainConfig) (aborted bool, err error) {
+	r.plans = map[*models.DomainConfig]*domainPlan{}
+	for _, domain := range domains {
+		plan, err := r.planDomain(domain)
+		if err != nil {
+			r.fail(domain.UniqueName(), "", "push stopped", err)
+			return false, err
+		}
+		r.plans[domain] = plan
+		for _, p := range plan.providers {
+			aborted = aborted || (plan.push && p.limit != nil)
+		}
+	}
+	if aborted {
+		for _, plan := range r.plans {
+			plan.push = false
+		}
+	}
+	r.aborted = aborted
+	return aborted, nil
+}
+
+// planDomain prepares the records of domain and gets the corrections of
+// each of its DNS providers, without running them.
+func (r *runner) planDomain(domain *models.DomainConfig) (*domainPlan, error) {
+	plan := &domainPlan{notes: &notes{}}
+	var err error
+	plan.push, err = checkFrozen(r.args.FreezeDir, domain.UniqueName(), r.push, r.breakGlass, plan.notes)
+	if err != nil {
+		return nil, err
+	}
+	release := r.acquire(providerNames(domain)...)
+	nsList, err := nameservers.DetermineNameservers(domain)
+	release()
+	if err != nil {
+		return nil, err
+	}
+	domain.Nameservers = nsList
+	nameservers.AddNSRecords(domain)
+	if domain.ReplicateFrom != "" {
+		release := r.acquire(domain.ReplicateFrom)
+		n, err := replicateRecords(domain)
+		release()
+		if err != nil {
+			plan.notes.Printf("ERROR: Could not read the records of %s from %s: %s\n", domain.Name, domain.ReplicateFrom, err)
+			r.fail(domain.UniqueName(), domain.ReplicateFrom, "could not read the records to replicate", err)
+			plan.failed = true
+			return plan, nil
 		}
-		if len(domain.Nameservers) == 0 && domain.Metadata["no_ns"] != "true" {
-			out.Warnf("No nameservers declared; skipping registrar. Add {no_ns:'true'} to force.\n")
+		plan.notes.Printf("----- Replicating %d records from %s\n", n, domain.ReplicateFrom)
+	}
+	for _, msg := range healthcheck.Prune(domain) {
+		plan.notes.Warnf("%s\n", msg)
+	}
+	if r.args.VerificationDir != "" {
+		msgs, err := verification.Prune(r.args.VerificationDir, domain, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range msgs {
+			plan.notes.Printf("%s\n", msg)
+		}
+	}
+	if r.args.ZoneHashes || r.args.ZoneHashHistory != "" {
+		plan.hashes = &zonehash.Entry{Time: time.Now().UTC(), Domain: domain.UniqueName(), Desired: zonehash.Hash(domain.Records), Actual: map[string]string{}}
+	}
+	for _, provider := range domain.DNSProviderInstances {
+		p := &providerPlan{provider: provider}
+		plan.providers = append(plan.providers, p)
+		if provider.Name == domain.ReplicateFrom {
+			// The source of the records is never changed.
+			p.skip = true
 			continue
 		}
 		dc, err := domain.Copy()
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
+		}
+		if !r.args.shouldRunProvider(provider.Name, provider.ProviderType, dc) {
+			p.skip = true
+			continue
 		}
-		corrections, err := domain.RegistrarInstance.Driver.GetRegistrarCorrections(dc)
-		out.EndProvider(len(corrections), err)
+		name := provider.Name
+		dc.Filter(func(r *models.RecordConfig) bool { return r.ForProvider(name) })
+		release := r.acquire(provider.Name)
+		diff.Watch(dc)
+		p.corrections, p.err = provider.Driver.GetDomainCorrections(dc)
+		p.changes = diff.Unwatch(dc)
+		release()
+		if !providers.ProviderHasCabability(provider.ProviderType, providers.CantReorderCorrections) {
+			diff.SortCorrections(p.corrections, p.changes)
+		}
+		if p.err != nil {
+			if !r.args.Failover {
+				break
+			}
+			continue
+		}
+		p.limit = checkThresholds(r.args, domain, provider.Name, p.changes, len(p.corrections))
+	}
+	return plan, nil
+}
+
+// runDomain previews or pushes domain. It returns the number of
+// corrections and whether any of them failed; an error stops the run.
+func (r *runner) runDomain(domain *models.DomainConfig, out printer.CLI) (totalCorrections int, anyErrors bool, err error) {
+	out.StartDomain(domain.UniqueName())
+	defer func() {
 		if err != nil {
+			r.fail(domain.UniqueName(), "", "push stopped", err)
+		}
+	}()
+	plan := r.plans[domain]
+	if plan == nil {
+		if plan, err = r.planDomain(domain); err != nil {
+			return totalCorrections, anyErrors, err
+		}
+	}
+	plan.notes.replay(out)
+	if plan.failed {
+		return totalCorrections, true, nil
+	}
+	domainPush := plan.push
+	hashes := plan.hashes
+	if hashes != nil {
+		out.Printf("Desired zone hash: %s\n", hashes.Desired)
+		if hr, ok := out.(report.HashRecorder); ok {
+			hr.DesiredHash(hashes.Desired)
+		}
+	}
+	var failed, pushed []string
+	var changed []models.RecordKey // The record sets pushed.
+	unreachable := false
+	for _, p := range plan.providers {
+		provider := p.provider
+		out.StartDNSProvider(provider.Name, p.skip)
+		if p.skip {
+			continue
+		}
+		corrections, changes := p.corrections, p.changes
+		rchanges := reportChanges(changes)
+		if cr, ok := out.(report.ChangeRecorder); ok {
+			cr.Changes(rchanges)
+		}
+		out.EndProvider(len(corrections), p.err)
+		if cp, ok := out.(printer.ChangePrinter); ok && p.err == nil {
+			cp.PrintChanges(printerChanges(rchanges))
+		}
+		if p.err != nil {
+			// Without --failover, this is the last provider planned.
+			r.fail(domain.UniqueName(), provider.Name, "could not get the corrections", p.err)
 			anyErrors = true
+			failed = append(failed, provider.Name)
+			unreachable = true
 			continue
 		}
+		if hashes != nil {
+			// Before push runs the corrections.
+			release := r.acquire(provider.Name)
+			hashActual(provider, domain.Name, hashes, out)
+			release()
+		}
+		if p.limit != nil {
+			if r.aborted {
+				out.Warnf("%s. Not pushing any changes.\n", p.limit)
+				r.fail(domain.UniqueName(), provider.Name, "push aborted", p.limit)
+			} else {
+				out.Warnf("%s. push would abort.\n", p.limit)
+			}
+		}
 		totalCorrections += len(corrections)
-		anyErrors = printOrRunCorrections(domain.Name, domain.RegistrarName, corrections, out, push, interactive, notifier) || anyErrors
+		release := r.acquire(provider.Name)
+		failedRun := printOrRunCorrections(domain.UniqueName(), provider.Name, corrections, out, domainPush, r.interactive, r.notifier)
+		release()
+		if failedRun {
+			anyErrors = true
+			failed = append(failed, provider.Name)
+		} else if domainPush && len(corrections) > 0 {
+			pushed = append(pushed, provider.Name)
+			changed = append(changed, changedKeys(changes)...)
+		}
+		anyErrors = anyErrors || (r.push && !domainPush && len(corrections) > 0)
 	}
-	if os.Getenv("TEAMCITY_VERSION") != "" {
-		fmt.Fprintf(os.Stderr, "##teamcity[buildStatus status='SUCCESS' text='%d corrections']", totalCorrections)
+	if hashes != nil && r.args.ZoneHashHistory != "" {
+		if err := r.appendHashes(hashes); err != nil {
+			return totalCorrections, anyErrors, err
+		}
 	}
-	notifier.Done()
-	out.Printf("Done. %d corrections.\n", totalCorrections)
-	if anyErrors {
-		return errors.Errorf("Completed with errors")
+	if len(changed) != 0 && (r.args.Verify || r.args.VerifyResolvers != "") {
+		verifyPropagation(r.args, domain, changed, out)
 	}
-	if totalCorrections != 0 && args.WarnChanges {
-		return errors.Errorf("There are pending changes")
+	if len(failed) != 0 && len(pushed) != 0 {
+		out.Warnf("%s was only partly pushed: %s changed, %s failed. When the failed providers work again, run `dnscontrol push --domains %s` to bring them in line.\n",
+			domain.UniqueName(), strings.Join(pushed, ", "), strings.Join(failed, ", "), domain.UniqueName())
+		if pr, ok := out.(report.PartialRecorder); ok {
+			pr.Partial()
+		}
+	}
+	if unreachable {
+		out.Warnf("Skipping the registrar of %s because not all of its DNS providers could be read.\n", domain.Name)
+		return totalCorrections, anyErrors, nil
+	}
+	run := r.args.shouldRunProvider(domain.RegistrarName, domain.RegistrarInstance.ProviderType, domain)
+	out.StartRegistrar(domain.RegistrarName, !run)
+	if !run {
+		return totalCorrections, anyErrors, nil
+	}
+	if len(domain.Nameservers) == 0 && domain.Metadata["no_ns"] != "true" {
+		out.Warnf("No nameservers declared; skipping registrar. Add {no_ns:'true'} to force.\n")
+		return totalCorrections, anyErrors, nil
+	}
+	dc, err := domain.Copy()
+	if err != nil {
+		log.Fatal(err)
+	}
+	// planDS asks the DNS providers for their keys.
+	release := r.acquire(providerNames(dc)...)
+	err = planDS(dc, out)
+	release()
+	if err != nil {
+		out.EndProvider(0, err)
+		r.fail(domain.UniqueName(), domain.RegistrarName, "could not get the DS records", err)
+		return totalCorrections, true, nil
+	}
+	release = r.acquire(domain.RegistrarName)
+	corrections, err := domain.RegistrarInstance.Driver.GetRegistrarCorrections(dc)
+	for _, more := range []func(providers.Registrar, *models.DomainConfig) ([]*models.Correction, error){
+		providers.RegistrarLockCorrections, providers.ContactCorrections, providers.DSCorrections,
+	} {
+		if err == nil {
+			var cs []*models.Correction
+			cs, err = more(domain.RegistrarInstance.Driver, dc)
+			corrections = append(corrections, cs...)
+		}
+	}
+	release()
+	out.EndProvider(len(corrections), err)
+	if err != nil {
+		r.fail(domain.UniqueName(), domain.RegistrarName, "could not get the registrar corrections", err)
+		return totalCorrections, true, nil
+	}
+	totalCorrections += len(corrections)
+	release = r.acquire(domain.RegistrarName)
+	anyErrors = printOrRunCorrections(domain.UniqueName(), domain.RegistrarName, corrections, out, domainPush, r.interactive, r.notifier) || anyErrors
+	release()
+	anyErrors = anyErrors || (r.push && !domainPush && len(corrections) > 0)
+	return totalCorrections, anyErrors, nil
+}
+
+// planDS compares the DS records dc declares for its registrar with the
+// keys its DNS providers sign it with, and warns if they don't match: a
+// DS record without a matching key makes resolvers that validate DNSSEC
+// fail to resolve the domain. With REGISTRAR_DS_AUTO, it sets the DS
+// records of dc to those of the keys. It only removes all of them if every
+// DNS provider of dc can tell that it doesn't sign dc.
+func planDS(dc *models.DomainConfig, out printer.CLI) error {
+	if !dc.AutoDS && len(dc.RegistrarDS) == 0 {
+		return nil
+	}
+	signed, signers, err := providers.SignerDSRecords(dc)
+	if err != nil {
+		return err
+	}
+	if len(signers) == 0 {
+		if dc.AutoDS {
+			return errors.Errorf("%s uses REGISTRAR_DS_AUTO, but none of its DNS providers can tell which keys sign it", dc.Name)
+		}
+		return nil
+	}
+	if dc.AutoDS {
+		if len(signed) == 0 {
+			if len(signers) < len(dc.DNSProviderInstances) {
+				// Another provider may sign it, with keys that only the DS
+				// records at the registrar tell about.
+				out.Warnf("DNSSEC: %s is not signed by %s, and its other DNS providers can't tell whether they sign it, so its DS records are left alone.\n", dc.Name, strings.Join(signers, ", "))
+				return nil
+			}
+			out.Warnf("DNSSEC: %s is not signed by %s, so its registrar must not publish DS records.\n", dc.Name, strings.Join(signers, ", "))
+			dc.NoRegistrarDS = true
+		}
+		dc.RegistrarDS = signed
+		return nil
+	}
+	if dsStrings(dc.RegistrarDS) != dsStrings(signed) {
+		out.Warnf("DNSSEC MISMATCH: the DS records of REGISTRAR_DS() for %s don't match the keys %s signs it with. Resolvers that validate DNSSEC will fail to resolve %s.\n  declared: %s\n  signed:   %s\nUse REGISTRAR_DS_AUTO to publish the DS records of the keys.\n",
+			dc.Name, strings.Join(signers, ", "), dc.Name, dsStrings(dc.RegistrarDS), dsStrings(signed))
 	}
 	return nil
 }
 
+// dsStrings returns records as a sorted, comma separated list.
+func dsStrings(records []*models.DSRecord) string {
+	list := make([]string, 0, len(records))
+	for _, ds := range records {
+		list = append(list, ds.String())
+	}
+	sort.Strings(list)
+	if len(list) == 0 {
+		return "none"
+	}
+	return strings.Join(list, ", ")
+}
+
+// checkFrozen reports whether the corrections of domain may be run, given
+// the freeze marker (if any) in dir.
+func checkFrozen(dir, domain string, push, breakGlass bool, out printer.Printer) (bool, error) {
+	if dir == "" {
+		return push, nil
+	}
+	m, err := freeze.Get(dir, domain)
+	if err != nil || m == nil {
+		return push, err
+	}
+	switch {
+	case !push:
+		out.Warnf("%s. push will not change it.\n", m)
+	case breakGlass:
+		out.Warnf("%s. Pushing anyway because of --break-glass.\n", m)
+	default:
+		out.Warnf("%s. Not pushing any changes (use --break-glass to override).\n", m)
+		return false, nil
+	}
+	return push, nil
+}
+
+// checkThresholds returns an error if the changes provider would make to
+// domain exceed the domain's MAX_CHANGES() or MAX_DELETES(), or else
+// --max-changes or --max-deletes. Providers that don't use the diff
+// package don't say what their corrections change, so each of their
+// corrections counts as a change and as a delete.
+func checkThresholds(args PreviewArgs, domain *models.DomainConfig, provider string, r *diff.Result, corrections int) error {
+	changes, deletes := len(r.Create)+len(r.Delete)+len(r.Modify), len(r.Delete)
+	if changes == 0 {
+		changes, deletes = corrections, corrections
+	}
+	check := func(what string, n int, key string, max int) error {
+		if s, ok := domain.Metadata[key]; ok {
+			max, _ = strconv.Atoi(s) // Checked by normalize.
+		}
+		if max > 0 && n > max {
+			return errors.Errorf("%s would %s %d records of %s, more than the limit of %d", provider, what, n, domain.Name, max)
+		}
+		return nil
+	}
+	if err := check("change", changes, "max_changes", args.MaxChanges); err != nil {
+		return err
+	}
+	return check("delete", deletes, "max_deletes", args.MaxDeletes)
+}
+
+// reportChanges converts the changes the diffs of a provider found for the report.
+func reportChanges(r *diff.Result) []*report.Change {
+	var changes []*report.Change
+	for _, c := range r.Create {
+		changes = append(changes, &report.Change{Action: "create", Record: report.NewRecord(c.Desired)})
+	}
+	for _, c := range r.Delete {
+		changes = append(changes, &report.Change{Action: "delete", Record: report.NewRecord(c.Existing)})
+	}
+	for _, c := range r.Modify {
+		changes = append(changes, &report.Change{Action: "modify", Record: report.NewRecord(c.Desired), Old: report.NewRecord(c.Existing)})
+	}
+	return changes
+}
+
+// changedKeys returns the record sets changes changes.
+func changedKeys(r *diff.Result) []models.RecordKey {
+	var keys []models.RecordKey
+	for _, c := range r.Create {
+		keys = append(keys, c.Desired.Key())
+	}
+	for _, c := range r.Delete {
+		keys = append(keys, c.Existing.Key())
+	}
+	for _, c := range r.Modify {
+		keys = append(keys, c.Desired.Key(), c.Existing.Key())
+	}
+	return keys
+}
+
+// verifyPropagation waits for the nameservers of domain, and the
+// resolvers of --verify-resolvers, to serve the record sets changed, and
+// warns about those they don't serve by --verify-timeout.
+func verifyPropagation(args PreviewArgs, domain *models.DomainConfig, changed []models.RecordKey, out printer.CLI) {
+	checks := propagation.NewChecks(changed, domain.Records)
+	if len(checks) == 0 {
+		return
+	}
+	var servers []propagation.Server
+	if args.Verify {
+		var names []string
+		for _, ns := range domain.Nameservers {
+			names = append(names, ns.Name)
+		}
+		if len(names) == 0 {
+			out.Warnf("--verify: %s has no nameservers to check\n", domain.Name)
+		}
+		servers = append(servers, propagation.NameServers(names, false)...)
+	}
+	servers = append(servers, propagation.NameServers(splitList(args.VerifyResolvers), true)...)
+	if len(servers) == 0 {
+		return
+	}
+	names := make([]string, len(servers))
+	for i, s := range servers {
+		names[i] = s.String()
+	}
+	out.Printf("----- Verifying %d record sets at %s\n", len(checks), strings.Join(names, ", "))
+	start := time.Now()
+	pending := propagation.Wait(checks, servers, args.VerifyTimeout, 5*time.Second)
+	if len(pending) == 0 {
+		out.Printf("All changes are served, after %s\n", time.Since(start).Round(time.Second))
+		return
+	}
+	for _, r := range pending {
+		out.Warnf("Not served after %s: %s\n", args.VerifyTimeout, r)
+	}
+}
+
+// printerChanges returns changes as printer.PrintChanges shows them.
+func printerChanges(changes []*report.Change) []printer.Change {
+	value := func(r *report.Record) string {
+		if r == nil {
+			return ""
+		}
+		return fmt.Sprintf("%s ttl=%d", r.Value, r.TTL)
+	}
+	var pcs []printer.Change
+	for _, c := range changes {
+		pc := printer.Change{Action: c.Action, Name: c.Record.Name, Type: c.Record.Type}
+		pc.Before, pc.After = value(c.Before()), value(c.After())
+		pcs = append(pcs, pc)
+	}
+	return pcs
+}
+
+// hashActual adds the hash of the records provider serves for domain to e,
+// if the provider can list them.
+func hashActual(provider *models.DNSProviderInstance, domain string, e *zonehash.Entry, out printer.CLI) {
+	lister, ok := provider.Driver.(providers.ZoneRecordLister)
+	if !ok {
+		out.Printf("Actual zone hash: unknown, provider type %s can't list its records\n", provider.ProviderType)
+		return
+	}
+	records, err := lister.GetZoneRecords(domain)
+	if err != nil {
+		out.Warnf("Could not hash the records of %s: %s\n", provider.Name, err)
+		return
+	}
+	h := zonehash.Hash(records)
+	e.Actual[provider.Name] = h
+	drift := ""
+	if h != e.Desired {
+		drift = " (differs from the desired hash)"
+	}
+	out.Printf("Actual zone hash: %s%s\n", h, drift)
+	if hr, ok := out.(report.HashRecorder); ok {
+		hr.ActualHash(h)
+	}
+}
+
+// replicateRecords adds the records currently served by the domain's
+// REPLICATE_FROM provider to the domain. The apex NS and SOA records of the
+// source are not copied: each provider serves its own. It returns the number
+// of records copied.
+func replicateRecords(domain *models.DomainConfig) (int, error) {
+	var source *models.DNSProviderInstance
+	for _, p := range domain.DNSProviderInstances {
+		if p.Name == domain.ReplicateFrom {
+			source = p
+		}
+	}
+	if source == nil {
+		return 0, errors.Errorf("%s is not a DNS provider of the domain", domain.ReplicateFrom)
+	}
+	lister, ok := source.Driver.(providers.ZoneRecordLister)
+	if !ok {
+		return 0, errors.Errorf("provider type %s can not be used with REPLICATE_FROM", source.ProviderType)
+	}
+	records, err := lister.GetZoneRecords(domain.Name)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, r := range records {
+		if r.Type == "SOA" || (r.Type == "NS" && r.GetLabel() == "@") {
+			continue
+		}
+		// Provider specific data can't be copied (or used) by the targets.
+		r.Original = nil
+		if r.Metadata == nil {
+			r.Metadata = map[string]string{}
+		}
+		domain.Records = append(domain.Records, r)
+		n++
+	}
+	return n, nil
+}
+
 // InitializeProviders takes a creds file path and a DNSConfig object. Creates all providers with the proper types, and returns them.
 // nonDefaultProviders is a list of providers that should not be run unless explicitly asked for by flags.
 func InitializeProviders(credsFile string, cfg *models.DNSConfig, notifyFlag bool) (notify notifications.Notifier, err error) {
@@ -193,12 +991,21 @@ func InitializeProviders(credsFile string, cfg *models.DNSConfig, notifyFlag boo
 		notificationCfg = providerConfigs["notifications"]
 	}
 	isNonDefault := map[string]bool{}
+	maxParallel := map[string]int{}
 	for name, vals := range providerConfigs {
 		// add "_exclude_from_defaults":"true" to a provider to exclude it from being run unless
 		// -providers=all or -providers=name
 		if vals["_exclude_from_defaults"] == "true" {
 			isNonDefault[name] = true
 		}
+		// add "_max_parallel":"N" to let --parallel use a provider for N domains at once
+		if s, ok := vals["_max_parallel"]; ok {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 1 {
+				return nil, errors.Errorf("_max_parallel of %s is %q, it must be a positive integer", name, s)
+			}
+			maxParallel[name] = n
+		}
 	}
 	registrars := map[string]providers.Registrar{}
 	dnsProviders := map[string]providers.DNSServiceProvider{}
@@ -213,6 +1020,7 @@ func InitializeProviders(credsFile string, cfg *models.DNSConfig, notifyFlag boo
 		}
 		d.RegistrarInstance.Driver = registrars[d.RegistrarName]
 		d.RegistrarInstance.IsDefault = !isNonDefault[d.RegistrarName]
+		d.RegistrarInstance.MaxParallel = maxParallel[d.RegistrarName]
 		for _, pInst := range d.DNSProviderInstances {
 			if dnsProviders[pInst.Name] == nil {
 				dCfg := cfg.DNSProvidersByName[pInst.Name]
@@ -224,6 +1032,7 @@ func InitializeProviders(credsFile string, cfg *models.DNSConfig, notifyFlag boo
 			}
 			pInst.Driver = dnsProviders[pInst.Name]
 			pInst.IsDefault = !isNonDefault[pInst.Name]
+			pInst.MaxParallel = maxParallel[pInst.Name]
 		}
 	}
 	return
@@ -0,0 +1,425 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/notifications"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/StackExchange/dnscontrol/providers"
+)
+
+// fakeProvider is a DNS provider and registrar with a set number of
+// corrections for each domain. The corrections it runs are added to ran.
+type fakeProvider struct {
+	name        string
+	corrections map[string]int // By domain.
+	err         error          // Returned by GetDomainCorrections.
+	fail        bool           // Whether the corrections fail.
+	nsErr       error          // Returned by GetNameservers.
+	before      func(domain string)
+	ran         *runLog
+}
+
+// runLog is the list of the corrections run, in order.
+type runLog struct {
+	sync.Mutex
+	list []string
+}
+
+func (l *runLog) add(s string) {
+	l.Lock()
+	defer l.Unlock()
+	l.list = append(l.list, s)
+}
+
+func (l *runLog) String() string {
+	l.Lock()
+	defer l.Unlock()
+	return strings.Join(l.list, " ")
+}
+
+func (p *fakeProvider) GetNameservers(domain string) ([]*models.Nameserver, error) {
+	return nil, p.nsErr
+}
+
+func (p *fakeProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	if p.before != nil {
+		p.before(dc.Name)
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	var corrections []*models.Correction
+	for i := 1; i <= p.corrections[dc.Name]; i++ {
+		msg := fmt.Sprintf("%s:%s#%d", p.name, dc.Name, i)
+		corrections = append(corrections, &models.Correction{Msg: msg, F: func() error {
+			p.ran.add(msg)
+			if p.fail {
+				return fmt.Errorf("%s failed", msg)
+			}
+			return nil
+		}})
+	}
+	return corrections, nil
+}
+
+func (p *fakeProvider) GetRegistrarCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	return nil, nil
+}
+
+// registeredFake is the provider that the dnsconfig.js files of the tests
+// get for NewDnsProvider("...", "FAKE").
+var registeredFake *fakeProvider
+
+func init() {
+	providers.RegisterDomainServiceProviderType("FAKE", func(map[string]string, json.RawMessage) (providers.DNSServiceProvider, error) {
+		return registeredFake, nil
+	})
+}
+
+// writeConfig writes js as the dnsconfig.js of a new directory, and
+// returns the arguments that read it. registeredFake gets the
+// corrections given.
+func writeConfig(t *testing.T, js string, corrections map[string]int) (args PreviewArgs, cleanup func()) {
+	dir, err := ioutil.TempDir("", "dnscontrol-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	args.JSFile = filepath.Join(dir, "dnsconfig.js")
+	args.CredsFile = filepath.Join(dir, "creds.json")
+	if err := ioutil.WriteFile(args.JSFile, []byte(js), 0644); err != nil {
+		t.Fatal(err)
+	}
+	registeredFake = &fakeProvider{name: "fake", corrections: corrections, ran: &runLog{}}
+	return args, func() { os.RemoveAll(dir) }
+}
+
+// fakeDomain returns a domain served by ps, registered with a registrar
+// that has nothing to change.
+func fakeDomain(name string, ps ...*fakeProvider) *models.DomainConfig {
+	dc := &models.DomainConfig{
+		Name:              name,
+		Metadata:          map[string]string{},
+		RegistrarName:     "reg",
+		RegistrarInstance: &models.RegistrarInstance{ProviderBase: models.ProviderBase{Name: "reg", ProviderType: "FAKE"}, Driver: &fakeProvider{name: "reg"}},
+	}
+	for _, p := range ps {
+		dc.DNSProviderInstances = append(dc.DNSProviderInstances, &models.DNSProviderInstance{
+			ProviderBase: models.ProviderBase{Name: p.name, ProviderType: "FAKE", IsDefault: true},
+			Driver:       p,
+		})
+	}
+	return dc
+}
+
+// fakeRun pushes (or previews) domains like run does, and returns the
+// runner, what was printed and whether any correction failed.
+func fakeRun(args PreviewArgs, push bool, domains ...*models.DomainConfig) (*runner, string, bool, error) {
+	buf := &bytes.Buffer{}
+	out := printer.ConsolePrinter{Writer: buf}
+	r := &runner{args: args, push: push, notifier: notifications.Multi()}
+	if push && limited(args, domains) {
+		if _, err := r.planAll(domains); err != nil {
+			return r, buf.String(), false, err
+		}
+	}
+	_, anyErrors, err := r.runDomains(domains, out)
+	return r, buf.String(), anyErrors, err
+}
+
+func TestThresholds(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxChanges int
+		meta       string // max_changes of b.com
+		p1, p2     map[string]int
+		aborted    bool
+		ran        string
+	}{
+		{
+			name: "no limits",
+			p1:   map[string]int{"a.com": 1, "b.com": 3},
+			p2:   map[string]int{"a.com": 3},
+			ran:  "p1:a.com#1 p2:a.com#1 p2:a.com#2 p2:a.com#3 p1:b.com#1 p1:b.com#2 p1:b.com#3",
+		},
+		{
+			name:       "under the limits",
+			maxChanges: 3,
+			p1:         map[string]int{"a.com": 1, "b.com": 3},
+			p2:         map[string]int{"a.com": 3},
+			ran:        "p1:a.com#1 p2:a.com#1 p2:a.com#2 p2:a.com#3 p1:b.com#1 p1:b.com#2 p1:b.com#3",
+		},
+		{
+			name:       "second provider over the limit",
+			maxChanges: 2,
+			p1:         map[string]int{"a.com": 1},
+			p2:         map[string]int{"a.com": 3},
+			aborted:    true,
+		},
+		{
+			name:       "second domain over the limit",
+			maxChanges: 2,
+			p1:         map[string]int{"a.com": 1, "b.com": 3},
+			aborted:    true,
+		},
+		{
+			name:       "metadata overrides the flag",
+			maxChanges: 2,
+			meta:       "3",
+			p1:         map[string]int{"a.com": 1, "b.com": 3},
+			ran:        "p1:a.com#1 p1:b.com#1 p1:b.com#2 p1:b.com#3",
+		},
+		{
+			name: "metadata alone",
+			meta: "2",
+			p1:   map[string]int{"a.com": 1, "b.com": 3},
+			p2:   map[string]int{"a.com": 3},
+			// a.com has no limit, but isn't pushed either.
+			aborted: true,
+		},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			ran := &runLog{}
+			p1 := &fakeProvider{name: "p1", corrections: tst.p1, ran: ran}
+			p2 := &fakeProvider{name: "p2", corrections: tst.p2, ran: ran}
+			b := fakeDomain("b.com", p1)
+			if tst.meta != "" {
+				b.Metadata["max_changes"] = tst.meta
+			}
+			r, out, _, err := fakeRun(PreviewArgs{MaxChanges: tst.maxChanges}, true, fakeDomain("a.com", p1, p2), b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.aborted != tst.aborted {
+				t.Errorf("aborted is %v, want %v", r.aborted, tst.aborted)
+			}
+			if got := ran.String(); got != tst.ran {
+				t.Errorf("ran %q, want %q", got, tst.ran)
+			}
+			if tst.aborted && !strings.Contains(out, "Not pushing any changes") {
+				t.Errorf("the output doesn't say why nothing was pushed:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestThresholdsPreview(t *testing.T) {
+	ran := &runLog{}
+	p1 := &fakeProvider{name: "p1", corrections: map[string]int{"a.com": 3}, ran: ran}
+	r, out, _, err := fakeRun(PreviewArgs{MaxChanges: 2}, false, fakeDomain("a.com", p1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.aborted || ran.String() != "" {
+		t.Errorf("preview ran %q", ran.String())
+	}
+	if !strings.Contains(out, "more than the limit of 2. push would abort") {
+		t.Errorf("preview doesn't warn about the limit:\n%s", out)
+	}
+}
+
+func TestFailover(t *testing.T) {
+	tests := []struct {
+		name     string
+		failover bool
+		fail     string // The provider whose GetDomainCorrections fails.
+		ran      string
+		out      string
+	}{
+		{
+			name: "first fails",
+			fail: "p1",
+			ran:  "",
+			out:  "p1 is down",
+		},
+		{
+			name:     "first fails with failover",
+			failover: true,
+			fail:     "p1",
+			ran:      "p2:a.com#1 p3:a.com#1",
+			out:      "a.com was only partly pushed: p2, p3 changed, p1 failed",
+		},
+		{
+			name: "second fails",
+			fail: "p2",
+			ran:  "p1:a.com#1",
+			out:  "a.com was only partly pushed: p1 changed, p2 failed",
+		},
+		{
+			name:     "second fails with failover",
+			failover: true,
+			fail:     "p2",
+			ran:      "p1:a.com#1 p3:a.com#1",
+			out:      "a.com was only partly pushed: p1, p3 changed, p2 failed",
+		},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			ran := &runLog{}
+			var ps []*fakeProvider
+			for _, name := range []string{"p1", "p2", "p3"} {
+				p := &fakeProvider{name: name, corrections: map[string]int{"a.com": 1}, ran: ran}
+				if name == tst.fail {
+					p.err = fmt.Errorf("%s is down", name)
+				}
+				ps = append(ps, p)
+			}
+			_, out, anyErrors, err := fakeRun(PreviewArgs{Failover: tst.failover}, true, fakeDomain("a.com", ps...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !anyErrors {
+				t.Errorf("the failure of %s isn't reported", tst.fail)
+			}
+			if got := ran.String(); got != tst.ran {
+				t.Errorf("ran %q, want %q", got, tst.ran)
+			}
+			if !strings.Contains(out, tst.out) {
+				t.Errorf("the output doesn't contain %q:\n%s", tst.out, out)
+			}
+			if !strings.Contains(out, "Skipping the registrar of a.com") {
+				t.Errorf("the registrar ran although %s failed:\n%s", tst.fail, out)
+			}
+		})
+	}
+}
+
+// errorLog is a notifier that keeps what NotifyError is told.
+type errorLog struct {
+	runLog
+}
+
+func (l *errorLog) Notify(domain, provider, message string, err error, preview bool) {}
+
+func (l *errorLog) Done() {}
+
+func (l *errorLog) NotifyError(domain, provider, message string, err error) {
+	l.add(fmt.Sprintf("[%s %s %s: %s]", domain, provider, message, err))
+}
+
+func TestNotifyErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		push bool
+		args PreviewArgs
+		want string
+	}{
+		{
+			name: "preview",
+			want: "",
+		},
+		{
+			name: "push",
+			push: true,
+			want: "[a.com p2 could not get the corrections: p2 is down]",
+		},
+		{
+			name: "aborted",
+			push: true,
+			args: PreviewArgs{Failover: true, MaxChanges: 2},
+			want: "[a.com p2 could not get the corrections: p2 is down] [b.com p1 push aborted: p1 would change 3 records of b.com, more than the limit of 2]",
+		},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			ran := &runLog{}
+			p1 := &fakeProvider{name: "p1", corrections: map[string]int{"a.com": 1, "b.com": 3}, ran: ran}
+			p2 := &fakeProvider{name: "p2", err: fmt.Errorf("p2 is down"), ran: ran}
+			errs := &errorLog{}
+			r := &runner{args: tst.args, push: tst.push, notifier: errs}
+			domains := []*models.DomainConfig{fakeDomain("a.com", p1, p2), fakeDomain("b.com", p1)}
+			if tst.push && limited(tst.args, domains) {
+				if _, err := r.planAll(domains); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, _, err := r.runDomains(domains, printer.ConsolePrinter{Writer: &bytes.Buffer{}}); err != nil {
+				t.Fatal(err)
+			}
+			if got := errs.String(); got != tst.want {
+				t.Errorf("notified %s, want %s", got, tst.want)
+			}
+		})
+	}
+
+	errs := &errorLog{}
+	r := &runner{push: true, notifier: errs}
+	bad := &fakeProvider{name: "bad", nsErr: fmt.Errorf("no nameservers")}
+	dc := fakeDomain("a.com", bad)
+	dc.DNSProviderInstances[0].NumberOfNameservers = 1
+	if _, _, err := r.runDomains([]*models.DomainConfig{dc}, printer.ConsolePrinter{Writer: &bytes.Buffer{}}); err == nil {
+		t.Fatal("no error")
+	}
+	if got, want := errs.String(), "[a.com  push stopped: no nameservers]"; got != want {
+		t.Errorf("notified %s, want %s", got, want)
+	}
+}
+
+// fakeSigner is a DNS provider that signs zones with the keys of ds.
+type fakeSigner struct {
+	fakeProvider
+	ds []*models.DSRecord
+}
+
+func (p *fakeSigner) GetZoneDSRecords(domain string) ([]*models.DSRecord, error) {
+	if p.before != nil {
+		p.before(domain)
+	}
+	return p.ds, nil
+}
+
+func TestPlanDS(t *testing.T) {
+	key := &models.DSRecord{KeyTag: 2371, Algorithm: 13, DigestType: 2, Digest: "C988EC423E3880EB8DD8A46E0C5AC4DA81A2A0D09D4D84F23A4A2F0A35A6A3F3"}
+	signer := func(ds ...*models.DSRecord) *models.DNSProviderInstance {
+		return &models.DNSProviderInstance{ProviderBase: models.ProviderBase{Name: "signer"}, Driver: &fakeSigner{ds: ds}}
+	}
+	other := &models.DNSProviderInstance{ProviderBase: models.ProviderBase{Name: "other"}, Driver: &fakeProvider{name: "other"}}
+	tests := []struct {
+		name      string
+		providers []*models.DNSProviderInstance
+		ds        string
+		none      bool
+	}{
+		{"signed", []*models.DNSProviderInstance{signer(key)}, key.String(), false},
+		{"signed, with another provider", []*models.DNSProviderInstance{signer(key), other}, key.String(), false},
+		{"unsigned", []*models.DNSProviderInstance{signer()}, "none", true},
+		{"maybe signed by another provider", []*models.DNSProviderInstance{signer(), other}, "none", false},
+	}
+	for _, tst := range tests {
+		dc := &models.DomainConfig{Name: "example.com", AutoDS: true, DNSProviderInstances: tst.providers}
+		if err := planDS(dc, printer.ConsolePrinter{Writer: &bytes.Buffer{}}); err != nil {
+			t.Fatalf("%s: %v", tst.name, err)
+		}
+		if got := dsStrings(dc.RegistrarDS); got != tst.ds || dc.NoRegistrarDS != tst.none {
+			t.Errorf("%s: DS records %s (none %v), want %s (none %v)", tst.name, got, dc.NoRegistrarDS, tst.ds, tst.none)
+		}
+	}
+
+	dc := &models.DomainConfig{Name: "example.com", AutoDS: true, DNSProviderInstances: []*models.DNSProviderInstance{other}}
+	if err := planDS(dc, printer.ConsolePrinter{Writer: &bytes.Buffer{}}); err == nil {
+		t.Error("REGISTRAR_DS_AUTO without a provider that can tell its keys worked")
+	}
+
+	// With --parallel, the keys are asked for within the cap of the provider.
+	r := &runner{caps: map[string]chan struct{}{"signer": make(chan struct{}, 1)}, notifier: notifications.Multi()}
+	held := -1
+	dc = fakeDomain("example.com")
+	dc.AutoDS = true
+	dc.Metadata["no_ns"] = "true"
+	dc.DNSProviderInstances = []*models.DNSProviderInstance{signer(key)}
+	dc.DNSProviderInstances[0].Driver.(*fakeSigner).before = func(string) { held = len(r.caps["signer"]) }
+	r.runDomain(dc, printer.ConsolePrinter{Writer: &bytes.Buffer{}})
+	if held != 1 || len(r.caps["signer"]) != 0 {
+		t.Errorf("the keys were asked for holding the cap %d times, and it is still held %d times", held, len(r.caps["signer"]))
+	}
+}
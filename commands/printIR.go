@@ -4,10 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/StackExchange/dnscontrol/models"
 	"github.com/StackExchange/dnscontrol/pkg/js"
 	"github.com/StackExchange/dnscontrol/pkg/normalize"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/StackExchange/dnscontrol/pkg/yamlconfig"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli"
 )
@@ -29,6 +33,7 @@ var _ = cmd(catDebug, func() *cli.Command {
 	// This is the same as print-ir with the following changes:
 	// - output defaults to /dev/null.
 	// - prints "No errors." if there were no errors.
+	// - has --strict.
 	return &cli.Command{
 		Name:  "check",
 		Usage: "Check and validate dnsconfig.js. Do not access providers.",
@@ -42,7 +47,11 @@ var _ = cmd(catDebug, func() *cli.Command {
 			}
 			return err
 		},
-		Flags: args.flags(),
+		Flags: append(args.flags(), cli.BoolFlag{
+			Name:        "strict",
+			Usage:       "Fail on warnings too, such as MX records that point at CNAMEs",
+			Destination: &args.Strict,
+		}),
 	}
 }())
 
@@ -50,7 +59,8 @@ var _ = cmd(catDebug, func() *cli.Command {
 type PrintIRArgs struct {
 	GetDNSConfigArgs
 	PrintJSONArgs
-	Raw bool
+	Raw    bool
+	Strict bool // Warnings are errors.
 }
 
 func (args *PrintIRArgs) flags() []cli.Flag {
@@ -71,7 +81,7 @@ func PrintIR(args PrintIRArgs) error {
 	}
 	if !args.Raw {
 		errs := normalize.NormalizeAndValidateConfig(cfg)
-		if PrintValidationErrors(errs) {
+		if PrintValidationErrors(errs) || (args.Strict && len(errs) != 0) {
 			return errors.Errorf("Exiting due to validation errors")
 		}
 	}
@@ -83,13 +93,13 @@ func PrintValidationErrors(errs []error) (fatal bool) {
 	if len(errs) == 0 {
 		return false
 	}
-	fmt.Printf("%d Validation errors:\n", len(errs))
+	printer.Printf("%d Validation errors:\n", len(errs))
 	for _, err := range errs {
 		if _, ok := err.(normalize.Warning); ok {
-			fmt.Printf("WARNING: %s\n", err)
+			printer.Printf("WARNING: %s\n", err)
 		} else {
 			fatal = true
-			fmt.Printf("ERROR: %s\n", err)
+			printer.Printf("ERROR: %s\n", err)
 		}
 	}
 	return
@@ -101,6 +111,17 @@ func ExecuteDSL(args ExecuteDSLArgs) (*models.DNSConfig, error) {
 		return nil, errors.Errorf("No config specified")
 	}
 
+	switch filepath.Ext(args.JSFile) {
+	case ".yaml", ".yml":
+		return yamlconfig.Load(args.JSFile)
+	case ".json":
+		// The IR, as made by other tools than dnsconfig.js.
+		return readIR(args.JSFile, true)
+	}
+
+	if err := args.setupJS(); err != nil {
+		return nil, err
+	}
 	dnsConfig, err := js.ExecuteJavascript(args.JSFile, args.DevMode)
 	if err != nil {
 		return nil, errors.Errorf("Executing javascript in %s: %s", args.JSFile, err)
@@ -108,13 +129,42 @@ func ExecuteDSL(args ExecuteDSLArgs) (*models.DNSConfig, error) {
 	return dnsConfig, nil
 }
 
+// setupJS configures the javascript DSL for running args.JSFile.
+func (args *ExecuteDSLArgs) setupJS() error {
+	js.AllowFetch = args.AllowFetch
+	js.FetchCacheFile = args.FetchCache
+	js.FetchHosts = nil
+	for _, host := range strings.Split(args.FetchHosts, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			js.FetchHosts = append(js.FetchHosts, host)
+		}
+	}
+	js.AllowedEnv = nil
+	for _, name := range strings.Split(args.AllowEnv, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			js.AllowedEnv = append(js.AllowedEnv, name)
+		}
+	}
+	js.CLIVariables = map[string]string{}
+	for _, v := range args.Variables {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return errors.Errorf("-v %s: variables must be given as key=value", v)
+		}
+		js.CLIVariables[kv[0]] = kv[1]
+	}
+	return nil
+}
+
 // PrintJSON outputs/prettyprints the IR data.
 func PrintJSON(args PrintJSONArgs, config *models.DNSConfig) (err error) {
+	versioned := *config
+	versioned.SchemaVersion = models.IRSchemaVersion
 	var dat []byte
 	if args.Pretty {
-		dat, err = json.MarshalIndent(config, "", "  ")
+		dat, err = json.MarshalIndent(&versioned, "", "  ")
 	} else {
-		dat, err = json.Marshal(config)
+		dat, err = json.Marshal(&versioned)
 	}
 	if err != nil {
 		return err
@@ -132,9 +182,18 @@ func PrintJSON(args PrintJSONArgs, config *models.DNSConfig) (err error) {
 	return nil
 }
 
+// The exit codes of dnscontrol.
+const (
+	exitError          = 1 // Something failed.
+	exitPendingChanges = 2 // --expect-no-changes, and there are changes.
+)
+
 func exit(err error) error {
 	if err == nil {
 		return nil
 	}
-	return cli.NewExitError(err, 1)
+	if errors.Cause(err) == errPendingChanges {
+		return cli.NewExitError(err, exitPendingChanges)
+	}
+	return cli.NewExitError(err, exitError)
 }
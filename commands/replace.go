@@ -0,0 +1,158 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/js"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/StackExchange/dnscontrol/pkg/replace"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var _ = cmd(catUtils, func() *cli.Command {
+	var args ReplaceArgs
+	return &cli.Command{
+		Name:  "replace",
+		Usage: "replace a record target throughout dnsconfig.js (or write a patch that does), then preview the result",
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() != 0 {
+				return cli.NewExitError("Unexpected arguments", 1)
+			}
+			if args.Match == "" || args.With == "" {
+				return cli.NewExitError("--match and --with are required", 1)
+			}
+			return exit(Replace(args))
+		},
+		Flags: args.flags(),
+	}
+}())
+
+// ReplaceArgs contains all data/flags needed to run replace, independently of CLI
+type ReplaceArgs struct {
+	PreviewArgs
+	Match string
+	With  string
+	Types string
+	Patch string
+}
+
+func (args *ReplaceArgs) flags() []cli.Flag {
+	return append(args.PreviewArgs.flags(),
+		cli.StringFlag{
+			Name:        "match",
+			Destination: &args.Match,
+			Usage:       "The record target to replace",
+		},
+		cli.StringFlag{
+			Name:        "with",
+			Destination: &args.With,
+			Usage:       "The new record target",
+		},
+		cli.StringFlag{
+			Name:        "types",
+			Destination: &args.Types,
+			Usage:       "Comma separated list of the record types to change; default is all",
+		},
+		cli.StringFlag{
+			Name:        "patch",
+			Destination: &args.Patch,
+			Usage:       "Write the changes to dnsconfig.js as a patch to this file, instead of making them",
+		},
+	)
+}
+
+// Replace implements the replace subcommand.
+func Replace(args ReplaceArgs) error {
+	if args.JSONFile != "" {
+		return errors.Errorf("replace rewrites dnsconfig.js, it can't be used with --ir")
+	}
+	file := args.JSFile
+	src, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var types []string
+	if args.Types != "" {
+		types = strings.Split(args.Types, ",")
+	}
+	if err := args.setupJS(); err != nil {
+		return err
+	}
+	res, err := replace.Rewrite(string(src), args.Match, args.With, types, func(s string) (*models.DNSConfig, error) {
+		return js.ExecuteJavascriptSource(file, []byte(s), args.DevMode)
+	})
+	if err != nil {
+		return errors.Errorf("Executing javascript in %s: %s", file, err)
+	}
+	for _, s := range res.Skipped {
+		printer.Warnf("%s: not replaced on %s\n", file, s)
+	}
+	if len(res.Changes) == 0 {
+		return errors.Errorf("no record targets to replace with %s in %s", args.Match, file)
+	}
+	lines := "line"
+	if len(res.Lines) > 1 {
+		lines += "s"
+	}
+	fmt.Printf("Replaced %s with %s on %s %s of %s, changing:\n", args.Match, args.With, lines, joinInts(res.Lines), file)
+	for _, c := range res.Changes {
+		fmt.Printf("  %s\n", c)
+	}
+
+	if args.Patch == "" {
+		if err := ioutil.WriteFile(file, []byte(res.Source), 0644); err != nil {
+			return err
+		}
+		return Preview(args.PreviewArgs)
+	}
+
+	if err := ioutil.WriteFile(args.Patch, []byte(replace.Patch(filepath.ToSlash(file), string(src), res.Source)), 0644); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote the patch to %s\n", args.Patch)
+	// Preview the patched file next to the original, so require() finds
+	// the same files.
+	tmp, err := tempJSFile(filepath.Dir(file), filepath.Ext(file))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.WriteString(res.Source)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	args.JSFile = tmp.Name()
+	return Preview(args.PreviewArgs)
+}
+
+// tempJSFile creates a new, empty file in dir, whose name ends in ext.
+// ioutil.TempFile can't be given a suffix before Go 1.11, and the file
+// must end in .js, or .ts for TypeScript.
+func tempJSFile(dir, ext string) (*os.File, error) {
+	for i := 0; ; i++ {
+		name := filepath.Join(dir, fmt.Sprintf(".dnsconfig-replace-%d-%d%s", os.Getpid(), time.Now().UnixNano(), ext))
+		f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+		if os.IsExist(err) && i < 100 {
+			continue
+		}
+		return f, err
+	}
+}
+
+func joinInts(ns []int) string {
+	s := make([]string, len(ns))
+	for i, n := range ns {
+		s[i] = fmt.Sprint(n)
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/nameservers"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/StackExchange/dnscontrol/providers"
+	"github.com/StackExchange/dnscontrol/providers/diff"
+	"github.com/pkg/errors"
+)
+
+// snapshot is a saved copy of the zones, that preview --snapshot
+// compares dnsconfig.js with instead of what the providers serve.
+type snapshot struct {
+	zones   providers.ZoneRecordLister // A directory of zone files, or
+	domains map[string]models.Records  // the domains of a print-ir JSON file.
+}
+
+// loadSnapshot reads the snapshot at path: a directory of BIND zone
+// files (as written by write-zones) or a JSON file written by print-ir.
+func loadSnapshot(path string) (*snapshot, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if fi.IsDir() {
+		p, err := providers.CreateDNSProvider("BIND", map[string]string{"directory": path}, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &snapshot{zones: p.(providers.ZoneRecordLister)}, nil
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &models.DNSConfig{}
+	if err := json.Unmarshal(b, cfg); err != nil {
+		return nil, errors.Wrapf(err, "reading snapshot %s", path)
+	}
+	s := &snapshot{domains: map[string]models.Records{}}
+	for _, d := range cfg.Domains {
+		// The JSON only has the short names.
+		for _, r := range d.Records {
+			r.SetLabel(r.GetLabel(), d.Name)
+		}
+		// Like dnsconfig.js, it has NAMESERVER()s rather than their NS records.
+		nameservers.AddNSRecords(d)
+		s.domains[strings.ToLower(d.Name)] = d.Records
+	}
+	return s, nil
+}
+
+// records returns the records of domain in the snapshot, and whether
+// the snapshot has the domain at all.
+func (s *snapshot) records(domain string) (models.Records, bool, error) {
+	if s.zones == nil {
+		recs, ok := s.domains[strings.ToLower(domain)]
+		return recs, ok, nil
+	}
+	recs, err := s.zones.GetZoneRecords(domain)
+	if os.IsNotExist(errors.Cause(err)) {
+		return nil, false, nil
+	}
+	return recs, err == nil, err
+}
+
+// previewSnapshot shows the changes dnsconfig.js makes to the snapshot
+// at args.Snapshot. No provider is contacted, so no credentials are
+// needed. The diff is the generic one: the provider-specific details
+// of a real preview, such as which record types a provider supports,
+// are not taken into account.
+func previewSnapshot(cfg *models.DNSConfig, args PreviewArgs, out printer.CLI) error {
+	snap, err := loadSnapshot(args.Snapshot)
+	if err != nil {
+		return err
+	}
+	totalCorrections := 0
+	for _, domain := range cfg.Domains {
+		if !args.shouldRunDomain(domain.UniqueName()) {
+			continue
+		}
+		out.StartDomain(domain.UniqueName())
+		existing, found, err := snap.records(domain.Name)
+		if err != nil {
+			return err
+		}
+		if !found {
+			out.Warnf("%s is not in the snapshot, so all its records are new\n", domain.Name)
+		}
+		nameservers.AddNSRecords(domain)
+		desired, existing := snapshotComparable(domain.Records, existing)
+		domain.Records = desired
+
+		out.StartDNSProvider("snapshot", false)
+		_, create, del, mod := diff.New(domain).IncrementalDiff(existing)
+		corrections := []*models.Correction{}
+		for _, cs := range []diff.Changeset{del, create, mod} {
+			for _, c := range cs {
+				corrections = append(corrections, &models.Correction{Msg: c.String()})
+			}
+		}
+		for i, c := range corrections {
+			out.PrintCorrection(i, c)
+		}
+		out.EndProvider(len(corrections), nil)
+		totalCorrections += len(corrections)
+	}
+	out.Printf("Done. %d corrections.\n", totalCorrections)
+	if totalCorrections != 0 && args.WarnChanges {
+		return errPendingChanges
+	}
+	return nil
+}
+
+// snapshotComparable drops what the snapshot can't be compared on. The
+// SOA is managed by the providers. The apex NS records come from the
+// providers too, unless dnsconfig.js sets them with NAMESERVER() or NS().
+func snapshotComparable(desired, existing models.Records) (models.Records, models.Records) {
+	apexNS := false
+	for _, r := range desired {
+		if r.Type == "NS" && r.GetLabel() == "@" {
+			apexNS = true
+		}
+	}
+	filter := func(recs models.Records) models.Records {
+		kept := models.Records{}
+		for _, r := range recs {
+			if r.Type == "SOA" || (r.Type == "NS" && r.GetLabel() == "@" && !apexNS) {
+				continue
+			}
+			kept = append(kept, r)
+		}
+		return kept
+	}
+	return filter(desired), filter(existing)
+}
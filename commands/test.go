@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/pkg/js"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var _ = cmd(catUtils, func() *cli.Command {
+	var args TestArgs
+	return &cli.Command{
+		Name:      "test",
+		Usage:     "run the ASSERT_* of javascript test files, such as those of shared functions",
+		ArgsUsage: "[file...]",
+		Action: func(ctx *cli.Context) error {
+			args.Files = ctx.Args()
+			return exit(Test(args))
+		},
+		Flags: args.flags(),
+	}
+}())
+
+// TestArgs contains all data/flags needed to run test, independently of CLI.
+type TestArgs struct {
+	ExecuteDSLArgs
+	Files []string // Files or globs; default the *_test.js next to the config.
+}
+
+func (args *TestArgs) flags() []cli.Flag {
+	return args.ExecuteDSLArgs.flags()
+}
+
+// Test implements the test subcommand. Each file is run on its own, like
+// dnsconfig.js, and passes if none of its assertions fail.
+func Test(args TestArgs) error {
+	patterns := args.Files
+	if len(patterns) == 0 {
+		patterns = []string{filepath.Join(filepath.Dir(args.JSFile), "*_test.js")}
+	}
+	var files []string
+	for _, p := range patterns {
+		matches, err := filepath.Glob(p)
+		if err != nil {
+			return errors.Wrapf(err, "bad pattern %s", p)
+		}
+		if len(matches) == 0 {
+			return errors.Errorf("no test files match %s", p)
+		}
+		files = append(files, matches...)
+	}
+	if err := args.setupJS(); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, f := range files {
+		passed, err := js.RunTest(f, args.DevMode)
+		if err != nil {
+			failed++
+			printer.Printf("FAIL %s (%d assertions passed)\n", f, passed)
+			printer.Printf("    %s\n", strings.Replace(err.Error(), "\n", "\n    ", -1))
+			continue
+		}
+		printer.Printf("ok   %s (%d assertions)\n", f, passed)
+	}
+	if failed != 0 {
+		return errors.Errorf("%d of %d test files failed", failed, len(files))
+	}
+	return nil
+}
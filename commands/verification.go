@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/pkg/verification"
+	"github.com/urfave/cli"
+)
+
+var _ = cmd(catUtils, func() *cli.Command {
+	var args VerifyCompleteArgs
+	return &cli.Command{
+		Name:      "verify-complete",
+		Usage:     "mark a PENDING_VERIFICATION as done: push removes its TXT record after a grace period",
+		ArgsUsage: "domain service",
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() != 2 {
+				return cli.NewExitError("A domain and a service are required", 1)
+			}
+			args.Domain = ctx.Args().Get(0)
+			args.Service = ctx.Args().Get(1)
+			return exit(VerifyComplete(args))
+		},
+		Flags: args.flags(),
+	}
+}())
+
+// VerificationArgs encapsulates the flags/args for sub-commands that read or write verification completions.
+type VerificationArgs struct {
+	VerificationDir string
+}
+
+func (args *VerificationArgs) flags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:        "verification-dir",
+			Destination: &args.VerificationDir,
+			Usage:       "Directory holding the completed PENDING_VERIFICATION records",
+			Value:       verification.DefaultDir,
+		},
+	}
+}
+
+// VerifyCompleteArgs contains all data/flags needed to run verify-complete, independently of CLI.
+type VerifyCompleteArgs struct {
+	VerificationArgs
+	Domain  string
+	Service string
+	Grace   time.Duration
+}
+
+func (args *VerifyCompleteArgs) flags() []cli.Flag {
+	return append(args.VerificationArgs.flags(),
+		cli.DurationFlag{
+			Name:        "grace",
+			Destination: &args.Grace,
+			Usage:       "How long to keep the TXT record before push removes it",
+			Value:       verification.DefaultGrace,
+		},
+	)
+}
+
+// VerifyComplete implements the verify-complete subcommand.
+func VerifyComplete(args VerifyCompleteArgs) error {
+	now := time.Now().UTC()
+	c := &verification.Completion{
+		Domain:      args.Domain,
+		Service:     args.Service,
+		CompletedAt: now,
+		RemoveAfter: now.Add(args.Grace),
+	}
+	if err := verification.Complete(args.VerificationDir, c); err != nil {
+		return err
+	}
+	fmt.Printf("%s verified %s; push will remove its TXT record after %s\n", c.Service, c.Domain, c.RemoveAfter.Format(time.RFC3339))
+	return nil
+}
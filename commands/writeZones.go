@@ -0,0 +1,202 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/nameservers"
+	"github.com/StackExchange/dnscontrol/pkg/normalize"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/StackExchange/dnscontrol/providers"
+	"github.com/StackExchange/dnscontrol/providers/bind"
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var _ = cmd(catUtils, func() *cli.Command {
+	var args WriteZonesArgs
+	return &cli.Command{
+		Name:  "write-zones",
+		Usage: "write the records of every domain to BIND zone files, without contacting any provider",
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() != 0 {
+				return cli.NewExitError("Unexpected arguments", 1)
+			}
+			return exit(WriteZones(args))
+		},
+		Flags: args.flags(),
+	}
+}())
+
+// WriteZonesArgs contains all data/flags needed to run write-zones, independently of CLI.
+type WriteZonesArgs struct {
+	GetDNSConfigArgs
+	FilterArgs
+	Dir         string
+	Nameservers string
+	Serial      string
+	SoaNs       string
+	SoaMbox     string
+	SoaRefresh  uint
+	SoaRetry    uint
+	SoaExpire   uint
+	SoaMinttl   uint
+}
+
+func (args *WriteZonesArgs) flags() []cli.Flag {
+	flags := args.GetDNSConfigArgs.flags()
+	return append(flags,
+		cli.StringFlag{
+			Name:        "domains",
+			Destination: &args.Domains,
+			Usage:       `Comma separated list of domain names to include; * and ? match like in file names`,
+		},
+		cli.StringFlag{
+			Name:        "dir",
+			Destination: &args.Dir,
+			Usage:       "Directory to write the zone files to",
+			Value:       "zones",
+		},
+		cli.StringFlag{
+			Name:        "ns",
+			Destination: &args.Nameservers,
+			Usage:       "Comma separated list of nameservers to add NS records for, besides those of NAMESERVER()",
+		},
+		cli.StringFlag{
+			Name:        "serial",
+			Destination: &args.Serial,
+			Usage:       `How to generate SOA serial numbers: "date" (yyyymmddvv), "increment" or "unixtime"`,
+			Value:       "date",
+		},
+		cli.StringFlag{
+			Name:        "soa-ns",
+			Destination: &args.SoaNs,
+			Usage:       "Primary nameserver of the SOA of zones without SOA()",
+		},
+		cli.StringFlag{
+			Name:        "soa-mbox",
+			Destination: &args.SoaMbox,
+			Usage:       "Contact address (as a domain name) of the SOA of zones without SOA()",
+		},
+		cli.UintFlag{
+			Name:        "soa-refresh",
+			Destination: &args.SoaRefresh,
+			Usage:       "Refresh of the SOA of zones without SOA()",
+			Value:       3600,
+		},
+		cli.UintFlag{
+			Name:        "soa-retry",
+			Destination: &args.SoaRetry,
+			Usage:       "Retry of the SOA of zones without SOA()",
+			Value:       600,
+		},
+		cli.UintFlag{
+			Name:        "soa-expire",
+			Destination: &args.SoaExpire,
+			Usage:       "Expire of the SOA of zones without SOA()",
+			Value:       604800,
+		},
+		cli.UintFlag{
+			Name:        "soa-minttl",
+			Destination: &args.SoaMinttl,
+			Usage:       "Negative caching TTL of the SOA of zones without SOA()",
+			Value:       1440,
+		},
+	)
+}
+
+// WriteZones implements the write-zones subcommand. It writes the
+// records dnsconfig.js configures with the BIND provider, as if BIND
+// were the only DNS provider of each domain. A zone file is only
+// rewritten, and its serial number only changes, if its records do.
+func WriteZones(args WriteZonesArgs) error {
+	switch args.Serial {
+	case "date", "increment", "unixtime":
+	default:
+		return errors.Errorf("--serial must be date, increment or unixtime, not %q", args.Serial)
+	}
+	for _, n := range []string{args.SoaNs, args.SoaMbox} {
+		if n != "" && !strings.HasSuffix(n, ".") {
+			return errors.Errorf("%q must be a fully qualified name, ending in a dot", n)
+		}
+	}
+	cfg, err := GetDNSConfig(args.GetDNSConfigArgs)
+	if err != nil {
+		return err
+	}
+	errs := normalize.NormalizeAndValidateConfig(cfg)
+	if PrintValidationErrors(errs) {
+		return errors.Errorf("Exiting due to validation errors")
+	}
+
+	soa := bind.SoaInfo{
+		Ns:      args.SoaNs,
+		Mbox:    args.SoaMbox,
+		Refresh: uint32(args.SoaRefresh),
+		Retry:   uint32(args.SoaRetry),
+		Expire:  uint32(args.SoaExpire),
+		Minttl:  uint32(args.SoaMinttl),
+	}
+	meta, err := json.Marshal(map[string]interface{}{"default_soa": soa, "soa_serial": args.Serial})
+	if err != nil {
+		return err
+	}
+	// The zones of each view of split horizon domains go in a
+	// subdirectory named after the view.
+	zonesOfTag := map[string]providers.DNSServiceProvider{}
+	zones := func(tag string) (providers.DNSServiceProvider, error) {
+		if z, ok := zonesOfTag[tag]; ok {
+			return z, nil
+		}
+		dir := filepath.Join(args.Dir, tag)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+		z, err := providers.CreateDNSProvider("BIND", map[string]string{"directory": dir}, meta)
+		zonesOfTag[tag] = z
+		return z, err
+	}
+	extraNS := []*models.Nameserver{}
+	if args.Nameservers != "" {
+		extraNS = models.StringsToNameservers(strings.Split(args.Nameservers, ","))
+	}
+
+	for _, domain := range cfg.Domains {
+		if !args.shouldRunDomain(domain.UniqueName()) {
+			continue
+		}
+		z, err := zones(domain.Tag)
+		if err != nil {
+			return err
+		}
+		domain.Nameservers = append(domain.Nameservers, extraNS...)
+		nameservers.AddNSRecords(domain)
+		records := models.Records{}
+		for _, r := range domain.Records {
+			if _, ok := dns.StringToType[r.Type]; !ok {
+				printer.Warnf("%s: %s record %s can't be written to a zone file, skipping it\n", domain.UniqueName(), r.Type, r.GetLabelFQDN())
+				continue
+			}
+			records = append(records, r)
+		}
+		domain.Records = records
+		corrections, err := z.GetDomainCorrections(domain)
+		if err != nil {
+			return errors.Wrapf(err, "generating the zone file of %s", domain.UniqueName())
+		}
+		if len(corrections) == 0 {
+			fmt.Printf("%s: unchanged\n", domain.UniqueName())
+		}
+		for _, c := range corrections {
+			if err := c.F(); err != nil {
+				return errors.Wrapf(err, "writing the zone file of %s", domain.UniqueName())
+			}
+		}
+	}
+	return nil
+}
@@ -240,6 +240,30 @@ func r53alias(name, aliasType, target string) *rec {
 	return r
 }
 
+func cert(name string, certtype, keytag uint16, algorithm uint8, target string) *rec {
+	r := makeRec(name, target, "CERT")
+	r.CertType = certtype
+	r.CertKeyTag = keytag
+	r.CertAlgorithm = algorithm
+	return r
+}
+
+func openpgpkey(name, target string) *rec {
+	return makeRec(name, target, "OPENPGPKEY")
+}
+
+func smimea(name string, usage, selector, matchingtype uint8, target string) *rec {
+	r := makeRec(name, target, "SMIMEA")
+	r.SmimeaUsage = usage
+	r.SmimeaSelector = selector
+	r.SmimeaMatchingType = matchingtype
+	return r
+}
+
+func dname(name, target string) *rec {
+	return makeRec(name, target, "DNAME")
+}
+
 func ns(name, target string) *rec {
 	return makeRec(name, target, "NS")
 }
@@ -308,6 +332,36 @@ func tlsa(name string, usage, selector, matchingtype uint8, target string) *rec
 	return r
 }
 
+func csync(name string, serial uint32, flags uint16, types string) *rec {
+	r := makeRec(name, types, "CSYNC")
+	r.CsyncSerial = serial
+	r.CsyncFlags = flags
+	return r
+}
+
+func dhcid(name, digest string) *rec {
+	return makeRec(name, digest, "DHCID")
+}
+
+func rp(name, mbox, txt string) *rec {
+	r := makeRec(name, mbox, "RP")
+	r.RpTxt = txt
+	return r
+}
+
+func unknown(name string, rtype uint16, rdata string) *rec {
+	r := makeRec(name, "", "")
+	(*models.RecordConfig)(r).SetTargetUnknown(rtype, rdata)
+	return r
+}
+
+func uri(name string, priority, weight uint16, target string) *rec {
+	r := makeRec(name, target, "URI")
+	r.UriPriority = priority
+	r.UriWeight = weight
+	return r
+}
+
 func ignore(name string) *rec {
 	r := &rec{
 		Type: "IGNORE",
@@ -616,6 +670,112 @@ func makeTests(t *testing.T) []*TestCase {
 		)
 	}
 
+	// DNAME
+	if !providers.ProviderHasCabability(*providerToRun, providers.CanUseDNAME) {
+		t.Log("Skipping DNAME Tests because provider does not support them")
+	} else {
+		tests = append(tests, tc("Empty"),
+			tc("DNAME record", dname("foo", "example.com.")),
+			tc("DNAME change target", dname("foo", "example.net.")),
+			tc("DNAME next to other records", dname("foo", "example.net."), a("foo", "1.2.3.4")),
+		)
+	}
+
+	// CERT
+	if !providers.ProviderHasCabability(*providerToRun, providers.CanUseCERT) {
+		t.Log("Skipping CERT Tests because provider does not support them")
+	} else {
+		tests = append(tests, tc("Empty"),
+			tc("CERT record", cert("smime", 1, 0, 8, "MIIBCgKCAQEA")),
+			tc("CERT change type", cert("smime", 6, 0, 8, "MIIBCgKCAQEA")),
+			tc("CERT change keytag", cert("smime", 6, 1234, 8, "MIIBCgKCAQEA")),
+			tc("CERT change algorithm", cert("smime", 6, 1234, 13, "MIIBCgKCAQEA")),
+			tc("CERT change certificate", cert("smime", 6, 1234, 13, "MIIBCgKCAQEB")),
+		)
+	}
+
+	// SMIMEA
+	if !providers.ProviderHasCabability(*providerToRun, providers.CanUseSMIMEA) {
+		t.Log("Skipping SMIMEA Tests because provider does not support them")
+	} else {
+		sha256hash := strings.Repeat("0123456789abcdef", 4)
+		owner := "c93f1e400f26708f98cb19d936620da35eec8f72e57f9eec01c1afd6._smimecert"
+		tests = append(tests, tc("Empty"),
+			tc("SMIMEA record", smimea(owner, 3, 1, 1, sha256hash)),
+			tc("SMIMEA change usage", smimea(owner, 2, 1, 1, sha256hash)),
+			tc("SMIMEA change selector", smimea(owner, 2, 0, 1, sha256hash)),
+			tc("SMIMEA change certificate", smimea(owner, 2, 0, 1, strings.Repeat("fedcba9876543210", 4))),
+		)
+	}
+
+	// OPENPGPKEY
+	if !providers.ProviderHasCabability(*providerToRun, providers.CanUseOPENPGPKEY) {
+		t.Log("Skipping OPENPGPKEY Tests because provider does not support them")
+	} else {
+		owner := "c93f1e400f26708f98cb19d936620da35eec8f72e57f9eec01c1afd6._openpgpkey"
+		tests = append(tests, tc("Empty"),
+			tc("OPENPGPKEY record", openpgpkey(owner, "mQENBFVHm5sBCADK")),
+			tc("OPENPGPKEY change key", openpgpkey(owner, "mQINBFg0nnYBEADK")),
+		)
+	}
+
+	// URI
+	if !providers.ProviderHasCabability(*providerToRun, providers.CanUseURI) {
+		t.Log("Skipping URI Tests because provider does not support them")
+	} else {
+		tests = append(tests, tc("Empty"),
+			tc("URI record", uri("_http._tcp", 10, 1, "https://www.example.com/")),
+			tc("URI change priority", uri("_http._tcp", 20, 1, "https://www.example.com/")),
+			tc("URI change weight", uri("_http._tcp", 20, 5, "https://www.example.com/")),
+			tc("URI change target", uri("_http._tcp", 20, 5, "ftp://ftp.example.com/public")),
+			tc("URI second record", uri("_http._tcp", 20, 5, "ftp://ftp.example.com/public"), uri("_http._tcp", 30, 1, "https://mirror.example.com/")),
+		)
+	}
+
+	// CSYNC
+	if !providers.ProviderHasCabability(*providerToRun, providers.CanUseCSYNC) {
+		t.Log("Skipping CSYNC Tests because provider does not support them")
+	} else {
+		tests = append(tests, tc("Empty"),
+			tc("CSYNC record", csync("@", 66, 3, "A NS AAAA")),
+			tc("CSYNC change serial", csync("@", 67, 3, "A NS AAAA")),
+			tc("CSYNC change flags", csync("@", 67, 1, "A NS AAAA")),
+			tc("CSYNC change types", csync("@", 67, 1, "NS")),
+		)
+	}
+
+	// DHCID
+	if !providers.ProviderHasCabability(*providerToRun, providers.CanUseDHCID) {
+		t.Log("Skipping DHCID Tests because provider does not support them")
+	} else {
+		tests = append(tests, tc("Empty"),
+			tc("DHCID record", dhcid("host1", "AAIBY2/AuCccgoJbsaxcQc9TUapptP69lOjxfNuVAA2kjEA=")),
+			tc("DHCID change digest", dhcid("host1", "AAEBOSD+XR3Os/0LozeXVqcNc7FwCfQdWL3b/NaiUDlW2No=")),
+		)
+	}
+
+	// RP
+	if !providers.ProviderHasCabability(*providerToRun, providers.CanUseRP) {
+		t.Log("Skipping RP Tests because provider does not support them")
+	} else {
+		tests = append(tests, tc("Empty"),
+			tc("RP record", rp("@", "hostmaster.example.com.", ".")),
+			tc("RP add txt", rp("@", "hostmaster.example.com.", "contact.example.com.")),
+			tc("RP change mbox", rp("@", "noc.example.com.", "contact.example.com.")),
+		)
+	}
+
+	// UNKNOWN
+	if !providers.ProviderHasCabability(*providerToRun, providers.CanUseUNKNOWN) {
+		t.Log("Skipping UNKNOWN Tests because provider does not support them")
+	} else {
+		tests = append(tests, tc("Empty"),
+			tc("UNKNOWN record", unknown("private", 65280, "0a0b0c")),
+			tc("UNKNOWN change rdata", unknown("private", 65280, "0a0b0d")),
+			tc("UNKNOWN second record", unknown("private", 65280, "0a0b0d"), unknown("private", 65280, "ff")),
+		)
+	}
+
 	// Empty last
 	tc("Empty")
 	return tests
@@ -8,14 +8,20 @@ import (
 	"time"
 
 	"github.com/StackExchange/dnscontrol/commands"
+	"github.com/StackExchange/dnscontrol/pkg/redact"
 	_ "github.com/StackExchange/dnscontrol/providers/_all"
+	"github.com/StackExchange/dnscontrol/providers/plugin"
 )
 
-//go:generate go run build/generate/generate.go build/generate/featureMatrix.go
+//go:generate go run build/generate/generate.go build/generate/featureMatrix.go build/generate/typescript.go
 
 func main() {
+	defer redact.RecoverPanic()
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
-	os.Exit(commands.Run(versionString()))
+	log.SetOutput(redact.Writer(os.Stderr))
+	code := commands.Run(versionString())
+	plugin.Stop()
+	os.Exit(code)
 }
 
 // Version management. 2 Goals:
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Contact is a WHOIS contact of a domain at its registrar. Empty fields
+// are not managed: the registrar keeps what it has.
+type Contact struct {
+	FirstName    string `json:"first_name,omitempty"`
+	LastName     string `json:"last_name,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	Email        string `json:"email,omitempty"`
+	Phone        string `json:"phone,omitempty"`
+	Fax          string `json:"fax,omitempty"`
+	Address1     string `json:"address1,omitempty"`
+	Address2     string `json:"address2,omitempty"`
+	City         string `json:"city,omitempty"`
+	State        string `json:"state,omitempty"`
+	PostalCode   string `json:"postal_code,omitempty"`
+	Country      string `json:"country,omitempty"` // ISO 3166 code, such as "US".
+}
+
+// ContactRoles are the roles a domain has a contact for.
+var ContactRoles = []string{"registrant", "admin", "tech"}
+
+// contactSuffix ends the metadata keys of contacts, such as
+// "admin_contact".
+const contactSuffix = "_contact"
+
+// fields returns the names and values of the fields of c, in order.
+func (c *Contact) fields() [][2]string {
+	return [][2]string{
+		{"first_name", c.FirstName}, {"last_name", c.LastName}, {"organization", c.Organization},
+		{"email", c.Email}, {"phone", c.Phone}, {"fax", c.Fax},
+		{"address1", c.Address1}, {"address2", c.Address2}, {"city", c.City},
+		{"state", c.State}, {"postal_code", c.PostalCode}, {"country", c.Country},
+	}
+}
+
+// Diff returns the fields that want sets and c doesn't have, as
+// "field: old -> new". Case and spaces around values don't matter.
+func (c *Contact) Diff(want *Contact) []string {
+	var diffs []string
+	have := c.fields()
+	for i, f := range want.fields() {
+		w := strings.TrimSpace(f[1])
+		h := strings.TrimSpace(have[i][1])
+		if w != "" && !strings.EqualFold(w, h) {
+			diffs = append(diffs, fmt.Sprintf("%s: %q -> %q", f[0], h, w))
+		}
+	}
+	return diffs
+}
+
+// Merge returns c with the fields that want sets replaced.
+func (c *Contact) Merge(want *Contact) *Contact {
+	merged := *c
+	set := func(field *string, v string) {
+		if v = strings.TrimSpace(v); v != "" {
+			*field = v
+		}
+	}
+	set(&merged.FirstName, want.FirstName)
+	set(&merged.LastName, want.LastName)
+	set(&merged.Organization, want.Organization)
+	set(&merged.Email, want.Email)
+	set(&merged.Phone, want.Phone)
+	set(&merged.Fax, want.Fax)
+	set(&merged.Address1, want.Address1)
+	set(&merged.Address2, want.Address2)
+	set(&merged.City, want.City)
+	set(&merged.State, want.State)
+	set(&merged.PostalCode, want.PostalCode)
+	set(&merged.Country, want.Country)
+	return &merged
+}
+
+// Contacts returns the contacts declared in the metadata of dc, by role.
+// They are JSON objects in the keys "registrant_contact", "admin_contact"
+// and "tech_contact", as set by CONTACTS() in dnsconfig.js.
+func (dc *DomainConfig) Contacts() (map[string]*Contact, error) {
+	contacts := map[string]*Contact{}
+	keys := make([]string, 0, len(dc.Metadata))
+	for k := range dc.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if !strings.HasSuffix(k, contactSuffix) {
+			continue
+		}
+		role := strings.TrimSuffix(k, contactSuffix)
+		known := false
+		for _, r := range ContactRoles {
+			known = known || r == role
+		}
+		if !known {
+			return nil, errors.Errorf("%s: unknown contact role %q, want one of %s", k, role, strings.Join(ContactRoles, ", "))
+		}
+		dec := json.NewDecoder(bytes.NewBufferString(dc.Metadata[k]))
+		dec.DisallowUnknownFields()
+		c := &Contact{}
+		if err := dec.Decode(c); err != nil {
+			return nil, errors.Wrapf(err, "%s", k)
+		}
+		contacts[role] = c
+	}
+	return contacts, nil
+}
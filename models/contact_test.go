@@ -0,0 +1,50 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContacts(t *testing.T) {
+	dc := &DomainConfig{Name: "example.com", Metadata: map[string]string{
+		"admin_contact": `{"email":"hostmaster@example.com","country":"US"}`,
+		"other":         "x",
+	}}
+	contacts, err := dc.Contacts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]*Contact{"admin": {Email: "hostmaster@example.com", Country: "US"}}
+	if !reflect.DeepEqual(contacts, want) {
+		t.Errorf("got %+v, want %+v", contacts, want)
+	}
+
+	for _, meta := range []map[string]string{
+		{"owner_contact": `{}`},
+		{"tech_contact": `{"mail":"x"}`},
+		{"tech_contact": `not json`},
+	} {
+		dc.Metadata = meta
+		if _, err := dc.Contacts(); err == nil {
+			t.Errorf("%v: expected an error", meta)
+		}
+	}
+}
+
+func TestContactDiff(t *testing.T) {
+	have := &Contact{FirstName: "Ann", Email: "ann@example.com", City: "Paris"}
+	want := &Contact{Email: "ANN@example.com ", City: "Lyon", Country: "FR"}
+	diffs := have.Diff(want)
+	wantDiffs := []string{`city: "Paris" -> "Lyon"`, `country: "" -> "FR"`}
+	if !reflect.DeepEqual(diffs, wantDiffs) {
+		t.Errorf("got %q, want %q", diffs, wantDiffs)
+	}
+	merged := have.Merge(want)
+	wantMerged := &Contact{FirstName: "Ann", Email: "ANN@example.com", City: "Lyon", Country: "FR"}
+	if !reflect.DeepEqual(merged, wantMerged) {
+		t.Errorf("got %+v, want %+v", merged, wantMerged)
+	}
+	if len(merged.Diff(want)) != 0 {
+		t.Errorf("merged contact still differs: %q", merged.Diff(want))
+	}
+}
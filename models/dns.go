@@ -8,11 +8,21 @@ import (
 // DefaultTTL is applied to any DNS record without an explicit TTL.
 const DefaultTTL = uint32(300)
 
+// IRSchemaVersion is the version of the JSON encoding of DNSConfig that
+// print-ir writes, described by docs/schema/ir-v1.json. Fields may be added
+// without changing it; removing, renaming or changing the meaning of a
+// field needs a new version.
+const IRSchemaVersion = 1
+
 // DNSConfig describes the desired DNS configuration, usually loaded from dnsconfig.js.
 type DNSConfig struct {
+	// SchemaVersion is IRSchemaVersion in the output of print-ir, and 0 in
+	// configurations written by hand.
+	SchemaVersion      int                           `json:"schema_version,omitempty"`
 	Registrars         []*RegistrarConfig            `json:"registrars"`
 	DNSProviders       []*DNSProviderConfig          `json:"dns_providers"`
 	Domains            []*DomainConfig               `json:"domains"`
+	TTLPolicy          *TTLPolicy                    `json:"ttl_policy,omitempty"`
 	RegistrarsByName   map[string]*RegistrarConfig   `json:"-"`
 	DNSProvidersByName map[string]*DNSProviderConfig `json:"-"`
 }
@@ -27,6 +37,13 @@ func (config *DNSConfig) FindDomain(query string) *DomainConfig {
 	return nil
 }
 
+// TTLPolicy is the org-wide TTL policy set by TTL_POLICY(). A zero value
+// means the policy does not cover that TTL.
+type TTLPolicy struct {
+	NSTTL       uint32 `json:"ns_ttl,omitempty"`       // TTL of apex and delegation NS records.
+	NegativeTTL uint32 `json:"negative_ttl,omitempty"` // SOA minimum, used for negative caching.
+}
+
 // RegistrarConfig describes a registrar.
 type RegistrarConfig struct {
 	Name     string          `json:"name"`
@@ -63,6 +80,9 @@ func StringsToNameservers(nss []string) []*Nameserver {
 type Correction struct {
 	F   func() error `json:"-"`
 	Msg string
+	// Records are the records that F creates, modifies or deletes, if the
+	// provider tells. push orders corrections by their PRIORITY_HINT.
+	Records []*RecordConfig `json:"-"`
 }
 
 // DomainContainingFQDN finds the best domain from the dns config for the given record fqdn.
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"testing"
 )
 
@@ -88,3 +91,24 @@ func TestDowncase(t *testing.T) {
 		t.Errorf("%v: target1 expected (%v) got (%v)\n", dc.Records, "targetmx", dc.Records[1].GetTargetField())
 	}
 }
+
+// TestIRSchemaVersion makes sure the published schema is updated with IRSchemaVersion.
+func TestIRSchemaVersion(t *testing.T) {
+	b, err := ioutil.ReadFile(fmt.Sprintf("../docs/schema/ir-v%d.json", IRSchemaVersion))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema struct {
+		Properties struct {
+			SchemaVersion struct {
+				Const int
+			} `json:"schema_version"`
+		}
+	}
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if schema.Properties.SchemaVersion.Const != IRSchemaVersion {
+		t.Errorf("schema has version %d, expected %d", schema.Properties.SchemaVersion.Const, IRSchemaVersion)
+	}
+}
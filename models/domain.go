@@ -2,21 +2,37 @@ package models
 
 import (
 	"fmt"
+	"strings"
 
 	"golang.org/x/net/idna"
 )
 
 // DomainConfig describes a DNS domain (tecnically a  DNS zone).
 type DomainConfig struct {
-	Name             string         `json:"name"` // NO trailing "."
+	Name             string         `json:"name"`          // NO trailing "."
+	Tag              string         `json:"tag,omitempty"` // The view of a split horizon domain: "internal" for D("example.com!internal").
 	RegistrarName    string         `json:"registrar"`
 	DNSProviderNames map[string]int `json:"dnsProviders"`
 
-	Metadata      map[string]string `json:"meta,omitempty"`
-	Records       Records           `json:"records"`
-	Nameservers   []*Nameserver     `json:"nameservers,omitempty"`
-	KeepUnknown   bool              `json:"keepunknown,omitempty"`
-	IgnoredLabels []string          `json:"ignored_labels,omitempty"`
+	Metadata       map[string]string `json:"meta,omitempty"`
+	Records        Records           `json:"records"`
+	Nameservers    []*Nameserver     `json:"nameservers,omitempty"`
+	KeepUnknown    bool              `json:"keepunknown,omitempty"`
+	EnsureAbsent   Records           `json:"ensure_absent,omitempty"`   // From ENSURE_ABSENT(): records to delete, even with NO_PURGE.
+	IgnoredLabels  []string          `json:"ignored_labels,omitempty"`  // Older IR for IgnoredNames of all types.
+	IgnoredNames   []*Ignore         `json:"ignored_names,omitempty"`   // From IGNORE_NAME().
+	IgnoredTargets []*Ignore         `json:"ignored_targets,omitempty"` // From IGNORE_TARGET().
+	ReplicateFrom  string            `json:"replicate_from,omitempty"`  // Name of the DNS provider the records are copied from.
+	Owner          string            `json:"owner,omitempty"`           // Owner of the records, if the zone is shared (see pkg/ownership).
+	RegistrarLock  string            `json:"registrar_lock,omitempty"`  // "on" or "off" if REGISTRAR_LOCK() manages the transfer lock.
+	RegistrarDS    []*DSRecord       `json:"registrar_ds,omitempty"`    // DS records the registrar publishes, from REGISTRAR_DS().
+	NoRegistrarDS  bool              `json:"no_registrar_ds,omitempty"` // REGISTRAR_DS_NONE: the registrar publishes no DS record.
+	AutoDS         bool              `json:"auto_ds,omitempty"`         // REGISTRAR_DS_AUTO: the DS records come from the DNS providers.
+	// Patch makes the differ add Records to the records of the zone, and
+	// remove DeleteRecords from them, rather than replace them with Records.
+	// It is used by acme-txt.
+	Patch         bool    `json:"patch,omitempty"`
+	DeleteRecords Records `json:"delete_records,omitempty"`
 	//DNSSEC        bool              `json:"dnssec,omitempty"`
 
 	// These fields contain instantiated provider instances once everything is linked up.
@@ -27,6 +43,32 @@ type DomainConfig struct {
 	DNSProviderInstances []*DNSProviderInstance `json:"-"`
 }
 
+// Ignore is a rule of IGNORE_NAME() or IGNORE_TARGET(): dnscontrol
+// leaves alone the records whose name (or target) matches Pattern, a glob,
+// and whose type is one of Types.
+type Ignore struct {
+	Pattern string `json:"pattern"`
+	Types   string `json:"types,omitempty"` // Comma separated; empty or "*" for all types.
+}
+
+// SplitTag moves the view of a domain declared as "example.com!internal"
+// from its Name to its Tag.
+func (dc *DomainConfig) SplitTag() {
+	if i := strings.Index(dc.Name, "!"); i != -1 {
+		dc.Name, dc.Tag = dc.Name[:i], dc.Name[i+1:]
+	}
+}
+
+// UniqueName returns the name of the domain with its view, if it has one,
+// such as "example.com!internal". Each domain of a configuration has a
+// different UniqueName, but the views of a domain have the same Name.
+func (dc *DomainConfig) UniqueName() string {
+	if dc.Tag == "" {
+		return dc.Name
+	}
+	return dc.Name + "!" + dc.Tag
+}
+
 // Copy returns a deep copy of the DomainConfig.
 func (dc *DomainConfig) Copy() (*DomainConfig, error) {
 	newDc := &DomainConfig{}
@@ -81,16 +123,31 @@ func (dc *DomainConfig) Punycode() error {
 		}
 		rec.SetLabelFromFQDN(t, dc.Name)
 		switch rec.Type { // #rtype_variations
-		case "ALIAS", "MX", "NS", "CNAME", "PTR", "SRV", "URL", "URL301", "FRAME", "R53_ALIAS":
+		case "ALIAS", "MX", "NS", "CNAME", "DNAME", "PTR", "SRV", "URL", "URL301", "FRAME", "R53_ALIAS":
 			// These rtypes are hostnames, therefore need to be converted (unlike, for example, an AAAA record)
 			t, err := idna.ToASCII(rec.GetTargetField())
 			rec.SetTarget(t)
 			if err != nil {
 				return err
 			}
-		case "A", "AAAA", "CAA", "NAPTR", "SSHFP", "TXT", "TLSA":
+		case "RP":
+			// Both the mailbox and the TXT name are hostnames.
+			t, err := idna.ToASCII(rec.GetTargetField())
+			if err != nil {
+				return err
+			}
+			txt, err := idna.ToASCII(rec.RpTxt)
+			if err != nil {
+				return err
+			}
+			rec.SetTargetRP(t, txt)
+		case "A", "AAAA", "CAA", "CERT", "CSYNC", "DHCID", "NAPTR", "OPENPGPKEY", "SMIMEA", "SOA", "SSHFP", "TXT", "TLSA", "URI":
 			// Nothing to do.
 		default:
+			if _, ok := UnknownTypeNumber(rec.Type); ok {
+				// Opaque rdata. Nothing to do.
+				break
+			}
 			msg := fmt.Sprintf("Punycode rtype %v unimplemented", rec.Type)
 			panic(msg)
 			// We panic so that we quickly find any switch statements
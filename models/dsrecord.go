@@ -0,0 +1,40 @@
+package models
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DSRecord is a DS record that the registrar of a domain publishes in
+// the parent zone, to make the domain's DNSSEC signatures trusted.
+type DSRecord struct {
+	KeyTag     uint16 `json:"keytag"`
+	Algorithm  uint8  `json:"algorithm"`
+	DigestType uint8  `json:"digesttype"`
+	Digest     string `json:"digest"` // Hexadecimal.
+}
+
+// String returns ds as in the rdata of a DS record, with the digest in
+// upper case, so that equal DS records have equal strings.
+func (ds *DSRecord) String() string {
+	return fmt.Sprintf("%d %d %d %s", ds.KeyTag, ds.Algorithm, ds.DigestType, strings.ToUpper(ds.Digest))
+}
+
+// dsDigestLengths are the lengths in bytes of the digests of each
+// digest type: SHA-1, SHA-256, GOST and SHA-384.
+var dsDigestLengths = map[uint8]int{1: 20, 2: 32, 3: 32, 4: 48}
+
+// Validate returns an error if ds can't be a DS record.
+func (ds *DSRecord) Validate() error {
+	b, err := hex.DecodeString(ds.Digest)
+	if err != nil {
+		return errors.Errorf("DS %s: digest is not hexadecimal", ds)
+	}
+	if n, ok := dsDigestLengths[ds.DigestType]; ok && len(b) != n {
+		return errors.Errorf("DS %s: digest type %d needs a digest of %d bytes, not %d", ds, ds.DigestType, n, len(b))
+	}
+	return nil
+}
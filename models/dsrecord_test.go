@@ -0,0 +1,21 @@
+package models
+
+import "testing"
+
+func TestDSRecord(t *testing.T) {
+	ds := &DSRecord{KeyTag: 2371, Algorithm: 13, DigestType: 2, Digest: "1f987cc6583e92df0890718c42b0a7d6f5ee0e9f6ac95e1e0b5db5a2a1e18bae"}
+	if err := ds.Validate(); err != nil {
+		t.Error(err)
+	}
+	if got, want := ds.String(), "2371 13 2 1F987CC6583E92DF0890718C42B0A7D6F5EE0E9F6AC95E1E0B5DB5A2A1E18BAE"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	for _, bad := range []*DSRecord{
+		{KeyTag: 1, Algorithm: 13, DigestType: 2, Digest: "zz"},
+		{KeyTag: 1, Algorithm: 13, DigestType: 1, Digest: ds.Digest},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("%s: expected an error", bad)
+		}
+	}
+}
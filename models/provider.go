@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // DNSProvider is an interface for DNS Provider plug-ins.
 type DNSProvider interface {
 	GetNameservers(domain string) ([]*Nameserver, error)
@@ -11,11 +13,19 @@ type Registrar interface {
 	GetRegistrarCorrections(dc *DomainConfig) ([]*Correction, error)
 }
 
+// DomainStatus is what a registrar says about the registration of a domain.
+type DomainStatus struct {
+	Expires   time.Time // Zero if unknown.
+	Locked    *bool     // Whether transfers are locked, or nil if unknown.
+	AutoRenew *bool     // Whether the domain renews itself, or nil if unknown.
+}
+
 // ProviderBase describes providers.
 type ProviderBase struct {
 	Name         string
 	IsDefault    bool
 	ProviderType string
+	MaxParallel  int // How many domains preview and push --parallel may run at once with it.
 }
 
 // RegistrarInstance is a single registrar.
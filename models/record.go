@@ -18,15 +18,24 @@ import (
 //     AAAA
 //     ANAME  // Technically not an official rtype yet.
 //     CAA
+//     CERT
 //     CNAME
+//     CSYNC
+//     DHCID
+//     DNAME
 //     MX
 //     NAPTR
 //     NS
+//     OPENPGPKEY
 //     PTR
+//     RP
+//     SMIMEA
 //     SRV
 //     SSHFP
 //     TLSA
 //     TXT
+//     URI
+//     TYPEnnn (types dnscontrol doesn't know, see UNKNOWN())
 //   Pseudo-Types:
 //     ALIAS
 //     CF_REDIRECT
@@ -65,30 +74,41 @@ import (
 //  rec.Label() == "@"   // Is this record at the apex?
 //
 type RecordConfig struct {
-	Type             string            `json:"type"`   // All caps rtype name.
-	Name             string            `json:"name"`   // The short name. See above.
-	NameFQDN         string            `json:"-"`      // Must end with ".$origin". See above.
-	Target           string            `json:"target"` // If a name, must end with "."
-	TTL              uint32            `json:"ttl,omitempty"`
-	Metadata         map[string]string `json:"meta,omitempty"`
-	MxPreference     uint16            `json:"mxpreference,omitempty"`
-	SrvPriority      uint16            `json:"srvpriority,omitempty"`
-	SrvWeight        uint16            `json:"srvweight,omitempty"`
-	SrvPort          uint16            `json:"srvport,omitempty"`
-	CaaTag           string            `json:"caatag,omitempty"`
-	CaaFlag          uint8             `json:"caaflag,omitempty"`
-	NaptrOrder       uint16            `json:"naptrorder,omitempty"`
-	NaptrPreference  uint16            `json:"naptrpreference,omitempty"`
-	NaptrFlags       string            `json:"naptrflags,omitempty"`
-	NaptrService     string            `json:"naptrservice,omitempty"`
-	NaptrRegexp      string            `json:"naptrregexp,omitempty"`
-	SshfpAlgorithm   uint8             `json:"sshfpalgorithm,omitempty"`
-	SshfpFingerprint uint8             `json:"sshfpfingerprint,omitempty"`
-	TlsaUsage        uint8             `json:"tlsausage,omitempty"`
-	TlsaSelector     uint8             `json:"tlsaselector,omitempty"`
-	TlsaMatchingType uint8             `json:"tlsamatchingtype,omitempty"`
-	TxtStrings       []string          `json:"txtstrings,omitempty"` // TxtStrings stores all strings (including the first). Target stores only the first one.
-	R53Alias         map[string]string `json:"r53_alias,omitempty"`
+	Type               string            `json:"type"`   // All caps rtype name.
+	Name               string            `json:"name"`   // The short name. See above.
+	NameFQDN           string            `json:"-"`      // Must end with ".$origin". See above.
+	Target             string            `json:"target"` // If a name, must end with "."
+	TTL                uint32            `json:"ttl,omitempty"`
+	Metadata           map[string]string `json:"meta,omitempty"`
+	MxPreference       uint16            `json:"mxpreference,omitempty"`
+	SrvPriority        uint16            `json:"srvpriority,omitempty"`
+	SrvWeight          uint16            `json:"srvweight,omitempty"`
+	SrvPort            uint16            `json:"srvport,omitempty"`
+	CaaTag             string            `json:"caatag,omitempty"`
+	CaaFlag            uint8             `json:"caaflag,omitempty"`
+	CertType           uint16            `json:"certtype,omitempty"`
+	CertKeyTag         uint16            `json:"certkeytag,omitempty"`
+	CertAlgorithm      uint8             `json:"certalgorithm,omitempty"`
+	CsyncSerial        uint32            `json:"csyncserial,omitempty"`
+	CsyncFlags         uint16            `json:"csyncflags,omitempty"`
+	NaptrOrder         uint16            `json:"naptrorder,omitempty"`
+	NaptrPreference    uint16            `json:"naptrpreference,omitempty"`
+	NaptrFlags         string            `json:"naptrflags,omitempty"`
+	NaptrService       string            `json:"naptrservice,omitempty"`
+	NaptrRegexp        string            `json:"naptrregexp,omitempty"`
+	RpTxt              string            `json:"rptxt,omitempty"`
+	SmimeaUsage        uint8             `json:"smimeausage,omitempty"`
+	SmimeaSelector     uint8             `json:"smimeaselector,omitempty"`
+	SmimeaMatchingType uint8             `json:"smimeamatchingtype,omitempty"`
+	SshfpAlgorithm     uint8             `json:"sshfpalgorithm,omitempty"`
+	SshfpFingerprint   uint8             `json:"sshfpfingerprint,omitempty"`
+	TlsaUsage          uint8             `json:"tlsausage,omitempty"`
+	TlsaSelector       uint8             `json:"tlsaselector,omitempty"`
+	TlsaMatchingType   uint8             `json:"tlsamatchingtype,omitempty"`
+	UriPriority        uint16            `json:"uripriority,omitempty"`
+	UriWeight          uint16            `json:"uriweight,omitempty"`
+	TxtStrings         []string          `json:"txtstrings,omitempty"` // TxtStrings stores all strings (including the first). Target stores only the first one.
+	R53Alias           map[string]string `json:"r53_alias,omitempty"`
 
 	Original interface{} `json:"-"` // Store pointer to provider-specific record object. Used in diffing.
 }
@@ -205,6 +225,18 @@ func (rc *RecordConfig) ToDiffable(extraMaps ...map[string]string) string {
 // ToRR converts a RecordConfig to a dns.RR.
 func (rc *RecordConfig) ToRR() dns.RR {
 
+	// The DNS library doesn't know CSYNC yet.
+	if rc.Type == "CSYNC" {
+		rdata, err := rc.csyncRdata()
+		if err != nil {
+			panic(errors.Wrap(err, "ToRR"))
+		}
+		return rc.rfc3597(TypeCSYNC, rdata)
+	}
+	if n, ok := UnknownTypeNumber(rc.Type); ok {
+		return rc.rfc3597(n, rc.GetTargetField())
+	}
+
 	// Don't call this on fake types.
 	rdtype, ok := dns.StringToType[rc.Type]
 	if !ok {
@@ -229,10 +261,22 @@ func (rc *RecordConfig) ToRR() dns.RR {
 		rr.(*dns.A).A = rc.GetTargetIP()
 	case dns.TypeAAAA:
 		rr.(*dns.AAAA).AAAA = rc.GetTargetIP()
+	case dns.TypeCERT:
+		rr.(*dns.CERT).Type = rc.CertType
+		rr.(*dns.CERT).KeyTag = rc.CertKeyTag
+		rr.(*dns.CERT).Algorithm = rc.CertAlgorithm
+		rr.(*dns.CERT).Certificate = rc.GetTargetField()
 	case dns.TypeCNAME:
 		rr.(*dns.CNAME).Target = rc.GetTargetField()
+	case dns.TypeDHCID:
+		rr.(*dns.DHCID).Digest = rc.GetTargetField()
+	case dns.TypeDNAME:
+		rr.(*dns.DNAME).Target = rc.GetTargetField()
 	case dns.TypePTR:
 		rr.(*dns.PTR).Ptr = rc.GetTargetField()
+	case dns.TypeRP:
+		rr.(*dns.RP).Mbox = rc.GetTargetField()
+		rr.(*dns.RP).Txt = rc.RpTxt
 	case dns.TypeNAPTR:
 		rr.(*dns.NAPTR).Order = rc.NaptrOrder
 		rr.(*dns.NAPTR).Preference = rc.NaptrPreference
@@ -255,6 +299,13 @@ func (rc *RecordConfig) ToRR() dns.RR {
 		rr.(*dns.SOA).Retry = atou32(parts[4])
 		rr.(*dns.SOA).Expire = atou32(parts[5])
 		rr.(*dns.SOA).Minttl = atou32(parts[6])
+	case dns.TypeOPENPGPKEY:
+		rr.(*dns.OPENPGPKEY).PublicKey = rc.GetTargetField()
+	case dns.TypeSMIMEA:
+		rr.(*dns.SMIMEA).Usage = rc.SmimeaUsage
+		rr.(*dns.SMIMEA).Selector = rc.SmimeaSelector
+		rr.(*dns.SMIMEA).MatchingType = rc.SmimeaMatchingType
+		rr.(*dns.SMIMEA).Certificate = rc.GetTargetField()
 	case dns.TypeSRV:
 		rr.(*dns.SRV).Priority = rc.SrvPriority
 		rr.(*dns.SRV).Weight = rc.SrvWeight
@@ -268,6 +319,10 @@ func (rc *RecordConfig) ToRR() dns.RR {
 		rr.(*dns.CAA).Flag = rc.CaaFlag
 		rr.(*dns.CAA).Tag = rc.CaaTag
 		rr.(*dns.CAA).Value = rc.GetTargetField()
+	case dns.TypeURI:
+		rr.(*dns.URI).Priority = rc.UriPriority
+		rr.(*dns.URI).Weight = rc.UriWeight
+		rr.(*dns.URI).Target = rc.GetTargetField()
 	case dns.TypeTLSA:
 		rr.(*dns.TLSA).Usage = rc.TlsaUsage
 		rr.(*dns.TLSA).MatchingType = rc.TlsaMatchingType
@@ -284,6 +339,26 @@ func (rc *RecordConfig) ToRR() dns.RR {
 	return rr
 }
 
+// ForProvider returns whether the record goes to the DNS provider named
+// name. Records without a PROVIDERS() restriction go to all of them.
+func (rc *RecordConfig) ForProvider(name string) bool {
+	list, ok := rc.Metadata["providers"]
+	if !ok {
+		return true
+	}
+	for _, p := range strings.Split(list, ",") {
+		if strings.TrimSpace(p) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Note returns the NOTE() of the record, or "" if it has none.
+func (rc *RecordConfig) Note() string {
+	return rc.Metadata["note"]
+}
+
 // RecordKey represents a resource record in a format used by some systems.
 type RecordKey struct {
 	NameFQDN string
@@ -339,10 +414,14 @@ func downcase(recs []*RecordConfig) {
 		r.Name = strings.ToLower(r.Name)
 		r.NameFQDN = strings.ToLower(r.NameFQDN)
 		switch r.Type { // #rtype_variations
-		case "ANAME", "CNAME", "MX", "NS", "PTR", "NAPTR", "SRV":
+		case "ANAME", "CNAME", "DNAME", "MX", "NS", "PTR", "NAPTR", "SRV":
 			// These record types have a target that is case insensitive, so we downcase it.
 			r.Target = strings.ToLower(r.Target)
-		case "A", "AAAA", "ALIAS", "CAA", "IMPORT_TRANSFORM", "TLSA", "TXT", "SOA", "SSHFP", "CF_REDIRECT", "CF_TEMP_REDIRECT":
+		case "RP":
+			// Both the mailbox and the TXT name are case insensitive.
+			r.Target = strings.ToLower(r.Target)
+			r.RpTxt = strings.ToLower(r.RpTxt)
+		case "A", "AAAA", "ALIAS", "CAA", "CERT", "CSYNC", "DHCID", "IMPORT_TRANSFORM", "OPENPGPKEY", "SMIMEA", "TLSA", "TXT", "SOA", "SSHFP", "URI", "CF_REDIRECT", "CF_TEMP_REDIRECT":
 			// These record types have a target that is case sensitive, or is an IP address. We leave them alone.
 			// Do nothing.
 		default:
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Routing is the ROUTING() of a record: the set of a weighted, geo or
+// failover routing policy that it is in. The records of a name and type
+// that are in the same set are one record set of the provider, which
+// answers with one of the sets.
+type Routing struct {
+	Set         string
+	Weight      string // 0 to 255, for weighted routing.
+	Geo         string // "continent:EU", "DE", "US-CA" or "*", for geo routing.
+	Failover    string // "primary" or "secondary", for failover routing.
+	HealthCheck string // The ID of a health check of the provider.
+}
+
+// routingPrefix starts the metadata keys of the routing of a record, such
+// as "routing_set".
+const routingPrefix = "routing_"
+
+// fields returns the names and values of the fields of r, in order.
+func (r *Routing) fields() [][2]string {
+	return [][2]string{
+		{"set", r.Set}, {"weight", r.Weight}, {"geo", r.Geo},
+		{"failover", r.Failover}, {"health_check", r.HealthCheck},
+	}
+}
+
+// Routing returns the ROUTING() of the record, or nil if it has none.
+func (rc *RecordConfig) Routing() *Routing {
+	r := &Routing{
+		Set:         rc.Metadata[routingPrefix+"set"],
+		Weight:      rc.Metadata[routingPrefix+"weight"],
+		Geo:         rc.Metadata[routingPrefix+"geo"],
+		Failover:    rc.Metadata[routingPrefix+"failover"],
+		HealthCheck: rc.Metadata[routingPrefix+"health_check"],
+	}
+	if *r == (Routing{}) {
+		return nil
+	}
+	return r
+}
+
+// SetRouting sets the routing of the record, as read from a provider.
+func (rc *RecordConfig) SetRouting(r *Routing) {
+	if rc.Metadata == nil {
+		rc.Metadata = map[string]string{}
+	}
+	for _, f := range r.fields() {
+		if f[1] != "" {
+			rc.Metadata[routingPrefix+f[0]] = f[1]
+		}
+	}
+}
+
+// RoutingMetadata returns the metadata of the routing of the record, for
+// providers that compare it. It is nil if the record has none.
+func RoutingMetadata(rc *RecordConfig) map[string]string {
+	r := rc.Routing()
+	if r == nil {
+		return nil
+	}
+	m := map[string]string{}
+	for _, f := range r.fields() {
+		if f[1] != "" {
+			m[routingPrefix+f[0]] = f[1]
+		}
+	}
+	return m
+}
+
+var geoRe = regexp.MustCompile(`^(\*|continent:(AF|AN|AS|EU|NA|OC|SA)|[A-Z]{2}(-[A-Z0-9]{1,3})?)$`)
+
+// Check returns an error if r is not a set with exactly one policy.
+func (r *Routing) Check() error {
+	if r.Set == "" {
+		return errors.Errorf("ROUTING needs the name of the set")
+	}
+	policies := 0
+	if r.Weight != "" {
+		policies++
+		if n, err := strconv.Atoi(r.Weight); err != nil || n < 0 || n > 255 {
+			return errors.Errorf("ROUTING weight %q must be a number from 0 to 255", r.Weight)
+		}
+	}
+	if r.Geo != "" {
+		policies++
+		if !geoRe.MatchString(r.Geo) {
+			return errors.Errorf(`ROUTING geo %q must be "continent:" and a continent code, a country code (with a subdivision, like "US-CA"), or "*"`, r.Geo)
+		}
+	}
+	if r.Failover != "" {
+		policies++
+		if r.Failover != "primary" && r.Failover != "secondary" {
+			return errors.Errorf(`ROUTING failover %q must be "primary" or "secondary"`, r.Failover)
+		}
+	}
+	if policies != 1 {
+		return errors.Errorf("ROUTING of set %q needs one of weight, geo or failover", r.Set)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+)
+
+// SetTargetCERT sets the CERT fields.
+func (rc *RecordConfig) SetTargetCERT(certtype, keytag uint16, algorithm uint8, target string) error {
+	rc.CertType = certtype
+	rc.CertKeyTag = keytag
+	rc.CertAlgorithm = algorithm
+	rc.SetTarget(target)
+	if rc.Type == "" {
+		rc.Type = "CERT"
+	}
+	if rc.Type != "CERT" {
+		panic("assertion failed: SetTargetCERT called when .Type is not CERT")
+	}
+	return nil
+}
+
+// SetTargetCERTStrings is like SetTargetCERT but accepts strings.
+// The type and algorithm may be given as mnemonics (PKIX, RSASHA256) as
+// well as numbers, as RFC 4398 permits.
+func (rc *RecordConfig) SetTargetCERTStrings(certtype, keytag, algorithm, target string) (err error) {
+	var i64type, i64keytag, i64algorithm uint64
+	if t, ok := dns.StringToCertType[strings.ToUpper(certtype)]; ok {
+		i64type = uint64(t)
+	} else if i64type, err = strconv.ParseUint(certtype, 10, 16); err != nil {
+		return errors.Wrap(err, "CERT has value that won't fit in field")
+	}
+	if a, ok := dns.StringToAlgorithm[strings.ToUpper(algorithm)]; ok {
+		i64algorithm = uint64(a)
+	} else if i64algorithm, err = strconv.ParseUint(algorithm, 10, 8); err != nil {
+		return errors.Wrap(err, "CERT has value that won't fit in field")
+	}
+	if i64keytag, err = strconv.ParseUint(keytag, 10, 16); err != nil {
+		return errors.Wrap(err, "CERT has value that won't fit in field")
+	}
+	return rc.SetTargetCERT(uint16(i64type), uint16(i64keytag), uint8(i64algorithm), target)
+}
+
+// SetTargetCERTString is like SetTargetCERT but accepts one big string.
+// The certificate may be split into several space-separated chunks.
+func (rc *RecordConfig) SetTargetCERTString(s string) error {
+	part := strings.Fields(s)
+	if len(part) < 4 {
+		return errors.Errorf("CERT value does not contain 4 fields: (%#v)", s)
+	}
+	return rc.SetTargetCERTStrings(part[0], part[1], part[2], strings.Join(part[3:], ""))
+}
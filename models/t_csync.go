@@ -0,0 +1,157 @@
+package models
+
+import (
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+)
+
+// TypeCSYNC is the rtype number of CSYNC (RFC 7477). The DNS library
+// doesn't know CSYNC, so these records are converted to and from the
+// RFC 3597 "unknown record" format.
+const TypeCSYNC = 62
+
+// SetTargetCSYNC sets the CSYNC fields. types is the type bitmap, a
+// space-separated list of rtypes such as "A NS AAAA". It is stored in
+// Target in canonical (numeric) order.
+func (rc *RecordConfig) SetTargetCSYNC(serial uint32, flags uint16, types string) error {
+	nums, err := parseTypeBitmap(types)
+	if err != nil {
+		return err
+	}
+	rc.CsyncSerial = serial
+	rc.CsyncFlags = flags
+	rc.SetTarget(formatTypeBitmap(nums))
+	if rc.Type == "" {
+		rc.Type = "CSYNC"
+	}
+	if rc.Type != "CSYNC" {
+		panic("assertion failed: SetTargetCSYNC called when .Type is not CSYNC")
+	}
+	return nil
+}
+
+// SetTargetCSYNCStrings is like SetTargetCSYNC but accepts strings.
+func (rc *RecordConfig) SetTargetCSYNCStrings(serial, flags, types string) error {
+	i64serial, err := strconv.ParseUint(serial, 10, 32)
+	if err != nil {
+		return errors.Wrap(err, "CSYNC serial does not fit in 32 bits")
+	}
+	i64flags, err := strconv.ParseUint(flags, 10, 16)
+	if err != nil {
+		return errors.Wrap(err, "CSYNC flags does not fit in 16 bits")
+	}
+	return rc.SetTargetCSYNC(uint32(i64serial), uint16(i64flags), types)
+}
+
+// SetTargetCSYNCString is like SetTargetCSYNC but accepts one big string.
+func (rc *RecordConfig) SetTargetCSYNCString(s string) error {
+	part := strings.Fields(s)
+	if len(part) < 3 {
+		return errors.Errorf("CSYNC value does not contain at least 3 fields: (%#v)", s)
+	}
+	return rc.SetTargetCSYNCStrings(part[0], part[1], strings.Join(part[2:], " "))
+}
+
+// SetTargetCSYNCRdata sets the CSYNC fields from the hex-encoded rdata
+// of an RFC 3597 record.
+func (rc *RecordConfig) SetTargetCSYNCRdata(rdata string) error {
+	b, err := hex.DecodeString(rdata)
+	if err != nil {
+		return errors.Wrap(err, "CSYNC rdata is not hex")
+	}
+	if len(b) < 6 {
+		return errors.Errorf("CSYNC rdata too short: %s", rdata)
+	}
+	serial := uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+	flags := uint16(b[4])<<8 | uint16(b[5])
+	var nums []uint16
+	for b = b[6:]; len(b) > 0; {
+		if len(b) < 2 || int(b[1]) > 32 || len(b) < 2+int(b[1]) {
+			return errors.Errorf("CSYNC rdata has an invalid type bitmap: %s", rdata)
+		}
+		window, octets := uint16(b[0])<<8, b[2:2+int(b[1])]
+		for i, o := range octets {
+			for bit := uint16(0); bit < 8; bit++ {
+				if o&(0x80>>bit) != 0 {
+					nums = append(nums, window+uint16(i)*8+bit)
+				}
+			}
+		}
+		b = b[2+int(b[1]):]
+	}
+	return rc.SetTargetCSYNC(serial, flags, formatTypeBitmap(nums))
+}
+
+// csyncRdata returns the hex-encoded rdata of a CSYNC record.
+func (rc *RecordConfig) csyncRdata() (string, error) {
+	nums, err := parseTypeBitmap(rc.GetTargetField())
+	if err != nil {
+		return "", err
+	}
+	b := []byte{
+		byte(rc.CsyncSerial >> 24), byte(rc.CsyncSerial >> 16), byte(rc.CsyncSerial >> 8), byte(rc.CsyncSerial),
+		byte(rc.CsyncFlags >> 8), byte(rc.CsyncFlags),
+	}
+	// Type bitmap (RFC 4034 section 4.1.2): one block per window of 256
+	// types, holding only as many octets as needed.
+	for i := 0; i < len(nums); {
+		window := nums[i] >> 8
+		octets := make([]byte, 32)
+		last := 0
+		for ; i < len(nums) && nums[i]>>8 == window; i++ {
+			n := int(nums[i] & 0xff)
+			octets[n/8] |= 0x80 >> uint(n%8)
+			last = n / 8
+		}
+		b = append(b, byte(window), byte(last+1))
+		b = append(b, octets[:last+1]...)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// parseTypeBitmap parses a space-separated list of rtypes (mnemonics
+// or TYPEnnn) and returns their numbers, sorted.
+func parseTypeBitmap(s string) ([]uint16, error) {
+	var nums []uint16
+	seen := map[uint16]bool{}
+	for _, t := range strings.Fields(strings.ToUpper(s)) {
+		n, ok := dns.StringToType[t]
+		if t == "CSYNC" {
+			n, ok = TypeCSYNC, true
+		} else if !ok && strings.HasPrefix(t, "TYPE") {
+			i, err := strconv.ParseUint(t[4:], 10, 16)
+			n, ok = uint16(i), err == nil
+		}
+		if !ok {
+			return nil, errors.Errorf("unknown rtype %q in type bitmap", t)
+		}
+		if seen[n] {
+			return nil, errors.Errorf("rtype %s is listed twice in type bitmap", t)
+		}
+		seen[n] = true
+		nums = append(nums, n)
+	}
+	if len(nums) == 0 {
+		return nil, errors.Errorf("type bitmap is empty")
+	}
+	sort.Slice(nums, func(i, j int) bool { return nums[i] < nums[j] })
+	return nums, nil
+}
+
+// formatTypeBitmap is the opposite of parseTypeBitmap.
+func formatTypeBitmap(nums []uint16) string {
+	types := make([]string, len(nums))
+	for i, n := range nums {
+		if n == TypeCSYNC {
+			types[i] = "CSYNC"
+		} else {
+			types[i] = dns.Type(n).String()
+		}
+	}
+	return strings.Join(types, " ")
+}
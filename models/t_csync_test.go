@@ -0,0 +1,54 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+func TestCSYNCRoundTrip(t *testing.T) {
+	tests := []struct {
+		given, types, rdata string
+	}{
+		// Example from RFC 7477 section 2.2.
+		{"66 3 A NS AAAA", "A NS AAAA", "000000420003000460000008"},
+		{"1 0 aaaa ns", "NS AAAA", "000000010000000420000008"},
+		{"1 0 TYPE1 URI TYPE65280", "A URI TYPE65280", "000000010000000140010180ff0180"},
+	}
+	for _, tst := range tests {
+		rc := &RecordConfig{Type: "CSYNC"}
+		rc.SetLabel("@", "example.com")
+		if err := rc.SetTargetCSYNCString(tst.given); err != nil {
+			t.Errorf("%q: %s", tst.given, err)
+			continue
+		}
+		if rc.GetTargetField() != tst.types {
+			t.Errorf("%q: expected types %q, got %q", tst.given, tst.types, rc.GetTargetField())
+		}
+		rr := rc.ToRR()
+		if got := rr.(*dns.RFC3597).Rdata; got != tst.rdata {
+			t.Errorf("%q: expected rdata %s, got %s", tst.given, tst.rdata, got)
+		}
+
+		// Parse the zonefile form back.
+		parsed, err := dns.NewRR(rr.String())
+		if err != nil {
+			t.Fatalf("%q: %s", rr.String(), err)
+		}
+		back := &RecordConfig{Type: "CSYNC"}
+		if err := back.SetTargetCSYNCRdata(parsed.(*dns.RFC3597).Rdata); err != nil {
+			t.Fatal(err)
+		}
+		if back.CsyncSerial != rc.CsyncSerial || back.CsyncFlags != rc.CsyncFlags || back.GetTargetField() != rc.GetTargetField() {
+			t.Errorf("%q: round trip gave %d %d %s", tst.given, back.CsyncSerial, back.CsyncFlags, back.GetTargetField())
+		}
+	}
+}
+
+func TestCSYNCInvalid(t *testing.T) {
+	for _, s := range []string{"1 0", "1 0 A A", "1 0 BOGUS", "x 0 A", "1 65536 A"} {
+		if err := (&RecordConfig{Type: "CSYNC"}).SetTargetCSYNCString(s); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"net"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -34,14 +35,30 @@ func (r *RecordConfig) PopulateFromString(rtype, contents, origin string) error
 			return errors.Errorf("AAAA record with invalid IP: %s", contents)
 		}
 		return r.SetTargetIP(ip) // Reformat to canonical form.
-	case "ANAME", "CNAME", "NS", "PTR":
+	case "ANAME", "CNAME", "DNAME", "NS", "PTR":
 		return r.SetTarget(contents)
 	case "CAA":
 		return r.SetTargetCAAString(contents)
+	case "CERT":
+		return r.SetTargetCERTString(contents)
+	case "CSYNC":
+		return r.SetTargetCSYNCString(contents)
+	case "DHCID":
+		// The digest may be split into several whitespace-separated chunks.
+		return r.SetTarget(strings.Join(strings.Fields(contents), ""))
 	case "MX":
 		return r.SetTargetMXString(contents)
 	case "NAPTR":
 		return r.SetTargetNAPTRString(contents)
+	case "OPENPGPKEY":
+		// The key may be split into several whitespace-separated chunks.
+		return r.SetTarget(strings.Join(strings.Fields(contents), ""))
+	case "RP":
+		return r.SetTargetRPString(contents)
+	case "SMIMEA":
+		return r.SetTargetSMIMEAString(contents)
+	case "SOA":
+		return r.SetTargetSOAString(contents)
 	case "SRV":
 		return r.SetTargetSRVString(contents)
 	case "SSHFP":
@@ -50,7 +67,12 @@ func (r *RecordConfig) PopulateFromString(rtype, contents, origin string) error
 		return r.SetTargetTLSAString(contents)
 	case "TXT":
 		return r.SetTargetTXTString(contents)
+	case "URI":
+		return r.SetTargetURIString(contents)
 	default:
+		if n, ok := UnknownTypeNumber(rtype); ok {
+			return r.SetTargetUnknownString(n, contents)
+		}
 		return errors.Errorf("Unknown rtype (%s) when parsing (%s) domain=(%s)",
 			rtype, contents, origin)
 	}
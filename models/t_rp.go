@@ -0,0 +1,31 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SetTargetRP sets the RP fields. mbox (kept in Target) is the mailbox of
+// the responsible person as a domain name (hostmaster.example.com.), txt
+// is the name of a TXT record with more information, or "." for none.
+func (rc *RecordConfig) SetTargetRP(mbox, txt string) error {
+	rc.SetTarget(mbox)
+	rc.RpTxt = txt
+	if rc.Type == "" {
+		rc.Type = "RP"
+	}
+	if rc.Type != "RP" {
+		panic("assertion failed: SetTargetRP called when .Type is not RP")
+	}
+	return nil
+}
+
+// SetTargetRPString is like SetTargetRP but accepts one big string.
+func (rc *RecordConfig) SetTargetRPString(s string) error {
+	part := strings.Fields(s)
+	if len(part) != 2 {
+		return errors.Errorf("RP value does not contain 2 fields: (%#v)", s)
+	}
+	return rc.SetTargetRP(part[0], part[1])
+}
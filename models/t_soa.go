@@ -0,0 +1,72 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SetTargetSOA sets the SOA fields. They are all kept in Target, in
+// zonefile order (ns mbox serial refresh retry expire minttl).
+func (rc *RecordConfig) SetTargetSOA(ns, mbox string, serial, refresh, retry, expire, minttl uint32) error {
+	rc.SetTarget(fmt.Sprintf("%s %s %d %d %d %d %d", ns, mbox, serial, refresh, retry, expire, minttl))
+	if rc.Type == "" {
+		rc.Type = "SOA"
+	}
+	if rc.Type != "SOA" {
+		panic("assertion failed: SetTargetSOA called when .Type is not SOA")
+	}
+	return nil
+}
+
+// SetTargetSOAString is like SetTargetSOA but accepts one big string.
+func (rc *RecordConfig) SetTargetSOAString(s string) error {
+	part := strings.Fields(s)
+	if len(part) != 7 {
+		return errors.Errorf("SOA value does not contain 7 fields: (%#v)", s)
+	}
+	var nums [5]uint32
+	for i, p := range part[2:] {
+		n, err := strconv.ParseUint(p, 10, 32)
+		if err != nil {
+			return errors.Wrapf(err, "SOA field %q does not fit in 32 bits", p)
+		}
+		nums[i] = uint32(n)
+	}
+	return rc.SetTargetSOA(part[0], part[1], nums[0], nums[1], nums[2], nums[3], nums[4])
+}
+
+// SetSOASerial replaces the serial number of an SOA record.
+func (rc *RecordConfig) SetSOASerial(serial uint32) error {
+	part := strings.Fields(rc.GetTargetField())
+	if len(part) != 7 {
+		return errors.Errorf("SOA value does not contain 7 fields: (%#v)", rc.GetTargetField())
+	}
+	part[2] = strconv.FormatUint(uint64(serial), 10)
+	return rc.SetTargetSOAString(strings.Join(part, " "))
+}
+
+// SOAMinimum returns the minimum (negative caching) TTL of an SOA record.
+func (rc *RecordConfig) SOAMinimum() (uint32, error) {
+	part := strings.Fields(rc.GetTargetField())
+	if len(part) != 7 {
+		return 0, errors.Errorf("SOA value does not contain 7 fields: (%#v)", rc.GetTargetField())
+	}
+	n, err := strconv.ParseUint(part[6], 10, 32)
+	if err != nil {
+		return 0, errors.Wrapf(err, "SOA minimum %q does not fit in 32 bits", part[6])
+	}
+	return uint32(n), nil
+}
+
+// SetSOAMinimum replaces the minimum (negative caching) TTL of an SOA record.
+func (rc *RecordConfig) SetSOAMinimum(minttl uint32) error {
+	part := strings.Fields(rc.GetTargetField())
+	if len(part) != 7 {
+		return errors.Errorf("SOA value does not contain 7 fields: (%#v)", rc.GetTargetField())
+	}
+	part[6] = strconv.FormatUint(uint64(minttl), 10)
+	return rc.SetTargetSOAString(strings.Join(part, " "))
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+)
+
+// Records of types dnscontrol doesn't model are declared with UNKNOWN()
+// and handled as RFC 3597 "unknown records": their Type is TYPEnnn and
+// their Target the rdata, hex-encoded. They are compared by their rdata
+// only.
+
+// UnknownTypeNumber returns the number of an rtype written as TYPEnnn.
+func UnknownTypeNumber(rtype string) (uint16, bool) {
+	if !strings.HasPrefix(rtype, "TYPE") {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(rtype[4:], 10, 16)
+	if err != nil || n == 0 {
+		return 0, false
+	}
+	return uint16(n), true
+}
+
+// SetTargetUnknown sets the rtype and rdata of an unknown record. The rdata
+// is hex; whitespace in it is ignored.
+func (rc *RecordConfig) SetTargetUnknown(rtype uint16, rdata string) error {
+	rdata = strings.ToLower(strings.Join(strings.Fields(rdata), ""))
+	if _, err := hex.DecodeString(rdata); err != nil {
+		return errors.Wrap(err, "rdata is not hex")
+	}
+	if len(rdata) > 2*65535 {
+		return errors.Errorf("rdata is longer than 65535 octets")
+	}
+	t := "TYPE" + strconv.Itoa(int(rtype))
+	if rc.Type != "" && rc.Type != t {
+		panic("assertion failed: SetTargetUnknown called when .Type is not " + t)
+	}
+	rc.Type = t
+	return rc.SetTarget(rdata)
+}
+
+// SetTargetUnknownString is like SetTargetUnknown but accepts the zonefile
+// form of the rdata (\# length hex) as well as plain hex.
+func (rc *RecordConfig) SetTargetUnknownString(rtype uint16, s string) error {
+	part := strings.Fields(s)
+	if len(part) >= 2 && part[0] == `\#` {
+		length, err := strconv.Atoi(part[1])
+		if err != nil {
+			return errors.Errorf("invalid rdata length in %q", s)
+		}
+		rdata := strings.Join(part[2:], "")
+		if len(rdata) != 2*length {
+			return errors.Errorf("rdata length %d does not match %q", length, s)
+		}
+		return rc.SetTargetUnknown(rtype, rdata)
+	}
+	return rc.SetTargetUnknown(rtype, s)
+}
+
+// rfc3597 returns rc as an RFC 3597 "unknown record" of type rtype. If the
+// DNS library knows the type after all, its own RR is returned instead:
+// zonefile parsers don't all accept the generic format for known types.
+func (rc *RecordConfig) rfc3597(rtype uint16, rdata string) dns.RR {
+	rr := &dns.RFC3597{Rdata: rdata}
+	rr.Hdr = dns.RR_Header{
+		Name:     rc.NameFQDN + ".",
+		Rrtype:   rtype,
+		Class:    dns.ClassINET,
+		Ttl:      rc.TTL,
+		Rdlength: uint16(len(rdata) / 2),
+	}
+	if rc.TTL == 0 {
+		rr.Hdr.Ttl = DefaultTTL
+	}
+	if _, ok := dns.TypeToRR[rtype]; ok {
+		buf := make([]byte, len(rr.Hdr.Name)+len(rdata)+64)
+		if off, err := dns.PackRR(rr, buf, 0, nil, false); err == nil {
+			if known, _, err := dns.UnpackRR(buf[:off], 0); err == nil {
+				return known
+			}
+		}
+	}
+	return rr
+}
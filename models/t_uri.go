@@ -0,0 +1,45 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SetTargetURI sets the URI fields. The target may be given with or
+// without the double quotes used in zone files.
+func (rc *RecordConfig) SetTargetURI(priority, weight uint16, target string) error {
+	rc.UriPriority = priority
+	rc.UriWeight = weight
+	rc.SetTarget(StripQuotes(strings.TrimSpace(target)))
+	if rc.Type == "" {
+		rc.Type = "URI"
+	}
+	if rc.Type != "URI" {
+		panic("assertion failed: SetTargetURI called when .Type is not URI")
+	}
+	return nil
+}
+
+// SetTargetURIStrings is like SetTargetURI but accepts strings.
+func (rc *RecordConfig) SetTargetURIStrings(priority, weight, target string) error {
+	i64priority, err := strconv.ParseUint(priority, 10, 16)
+	if err != nil {
+		return errors.Wrap(err, "URI priority does not fit in 16 bits")
+	}
+	i64weight, err := strconv.ParseUint(weight, 10, 16)
+	if err != nil {
+		return errors.Wrap(err, "URI weight does not fit in 16 bits")
+	}
+	return rc.SetTargetURI(uint16(i64priority), uint16(i64weight), target)
+}
+
+// SetTargetURIString is like SetTargetURI but accepts one big string.
+func (rc *RecordConfig) SetTargetURIString(s string) error {
+	part := strings.Fields(s)
+	if len(part) != 3 {
+		return errors.Errorf("URI value does not contain 3 fields: (%#v)", s)
+	}
+	return rc.SetTargetURIStrings(part[0], part[1], part[2])
+}
@@ -52,6 +52,12 @@ func (rc *RecordConfig) GetTargetCombined() string {
 		return rc.Target
 	}
 
+	// The DNS library quotes the txt of an RP record as if it were text,
+	// but it is a domain name.
+	if rc.Type == "RP" {
+		return rc.Target + " " + rc.RpTxt
+	}
+
 	// We cheat by converting to a dns.RR and use the String() function.
 	// This combines all the data for us, and even does proper quoting.
 	// Sadly String() always includes a header, which we must strip out.
@@ -75,25 +81,38 @@ func (rc *RecordConfig) GetTargetSortable() string {
 func (rc *RecordConfig) GetTargetDebug() string {
 	content := fmt.Sprintf("%s %s %s %d", rc.Type, rc.NameFQDN, rc.Target, rc.TTL)
 	switch rc.Type { // #rtype_variations
-	case "A", "AAAA", "CNAME", "NS", "PTR", "TXT":
+	case "A", "AAAA", "CNAME", "DHCID", "DNAME", "NS", "OPENPGPKEY", "PTR", "TXT":
 		// Nothing special.
 	case "NAPTR":
 		content += fmt.Sprintf(" naptrorder=%d naptrpreference=%d naptrflags=%s naptrservice=%s naptrregexp=%s", rc.NaptrOrder, rc.NaptrPreference, rc.NaptrFlags, rc.NaptrService, rc.NaptrRegexp)
+	case "CSYNC":
+		content += fmt.Sprintf(" csyncserial=%d csyncflags=%d", rc.CsyncSerial, rc.CsyncFlags)
 	case "MX":
 		content += fmt.Sprintf(" pref=%d", rc.MxPreference)
+	case "RP":
+		content += fmt.Sprintf(" rptxt=%s", rc.RpTxt)
 	case "SOA":
 		content = fmt.Sprintf("%s %s %s %d", rc.Type, rc.Name, rc.Target, rc.TTL)
+	case "SMIMEA":
+		content += fmt.Sprintf(" smimeausage=%d smimeaselector=%d smimeamatchingtype=%d", rc.SmimeaUsage, rc.SmimeaSelector, rc.SmimeaMatchingType)
 	case "SRV":
 		content += fmt.Sprintf(" srvpriority=%d srvweight=%d srvport=%d", rc.SrvPriority, rc.SrvWeight, rc.SrvPort)
 	case "SSHFP":
 		content += fmt.Sprintf(" sshfpalgorithm=%d sshfpfingerprint=%d", rc.SshfpAlgorithm, rc.SshfpFingerprint)
+	case "URI":
+		content += fmt.Sprintf(" uripriority=%d uriweight=%d", rc.UriPriority, rc.UriWeight)
 	case "TLSA":
 		content += fmt.Sprintf(" tlsausage=%d tlsaselector=%d tlsamatchingtype=%d", rc.TlsaUsage, rc.TlsaSelector, rc.TlsaMatchingType)
 	case "CAA":
 		content += fmt.Sprintf(" caatag=%s caaflag=%d", rc.CaaTag, rc.CaaFlag)
+	case "CERT":
+		content += fmt.Sprintf(" certtype=%d certkeytag=%d certalgorithm=%d", rc.CertType, rc.CertKeyTag, rc.CertAlgorithm)
 	case "R53_ALIAS":
 		content += fmt.Sprintf(" type=%s zone_id=%s", rc.R53Alias["type"], rc.R53Alias["zone_id"])
 	default:
+		if _, ok := UnknownTypeNumber(rc.Type); ok {
+			break
+		}
 		panic(errors.Errorf("rc.String rtype %v unimplemented", rc.Type))
 		// We panic so that we quickly find any switch statements
 		// that have not been updated for a new RR type.
@@ -15,6 +15,7 @@ import (
 	"github.com/StackExchange/dnscontrol/models"
 	"github.com/StackExchange/dnscontrol/pkg/nameservers"
 	"github.com/StackExchange/dnscontrol/pkg/notifications"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
 	"github.com/xenolf/lego/acme"
 	acmelog "github.com/xenolf/lego/log"
 )
@@ -149,7 +150,7 @@ func (c *certManager) IssueOrRenewCert(cfg *CertConfig, renewUnder int, verbose
 	if err != nil {
 		return false, err
 	}
-	fmt.Printf("Obtained certificate for %s\n", cfg.CertName)
+	printer.Printf("Obtained certificate for %s\n", cfg.CertName)
 	if err = c.storage.StoreCertificate(cfg.CertName, certResource); err != nil {
 		return true, err
 	}
@@ -235,7 +236,7 @@ func (c *certManager) ensureNoPendingCorrections(d *models.DomainConfig) error {
 	if len(corrections) != 0 {
 		// TODO: maybe allow forcing through this check.
 		for _, c := range corrections {
-			fmt.Println(c.Msg)
+			printer.Printf("%s\n", c.Msg)
 		}
 		return fmt.Errorf("Found %d pending corrections for %s. Not going to proceed issuing certificates", len(corrections), d.Name)
 	}
@@ -255,6 +256,8 @@ func (c *certManager) getCorrections(d *models.DomainConfig) ([]*models.Correcti
 		if err != nil {
 			return nil, err
 		}
+		name := p.Name
+		dc.Filter(func(r *models.RecordConfig) bool { return r.ForProvider(name) })
 		corrections, err := p.Driver.GetDomainCorrections(dc)
 		if err != nil {
 			return nil, err
@@ -272,9 +275,9 @@ func (c *certManager) getAndRunCorrections(d *models.DomainConfig) error {
 	if err != nil {
 		return err
 	}
-	fmt.Printf("%d corrections\n", len(cs))
+	printer.Printf("%d corrections\n", len(cs))
 	for _, corr := range cs {
-		fmt.Printf("Running [%s]\n", corr.Msg)
+		printer.Printf("Running [%s]\n", corr.Msg)
 		err = corr.F()
 		c.notifier.Notify(d.Name, "certs", corr.Msg, err, false)
 		if err != nil {
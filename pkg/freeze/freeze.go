@@ -0,0 +1,91 @@
+// Package freeze records which domains are frozen.
+//
+// During an incident, DNS should only change when the people handling
+// the incident want it to. `dnscontrol freeze` writes a marker file for
+// a domain; `push` refuses to make changes to a domain that has one
+// (unless --break-glass is given) until `dnscontrol unfreeze` removes it.
+//
+// The markers are plain JSON files so they can be committed alongside
+// dnsconfig.js and picked up by CI systems that run push.
+package freeze
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultDir is the directory the markers are kept in, unless told otherwise.
+const DefaultDir = ".dnscontrol-freeze"
+
+// Marker describes why and since when a domain is frozen.
+type Marker struct {
+	Domain string    `json:"domain"`
+	Reason string    `json:"reason,omitempty"`
+	By     string    `json:"by,omitempty"`
+	Since  time.Time `json:"since"`
+}
+
+func (m *Marker) String() string {
+	s := fmt.Sprintf("%s is frozen since %s", m.Domain, m.Since.Format(time.RFC3339))
+	if m.By != "" {
+		s += " by " + m.By
+	}
+	if m.Reason != "" {
+		s += ": " + m.Reason
+	}
+	return s
+}
+
+func markerPath(dir, domain string) string {
+	return filepath.Join(dir, strings.ToLower(domain)+".json")
+}
+
+// Freeze writes the marker for m.Domain, replacing any existing one.
+func Freeze(dir string, m *Marker) error {
+	if m.Domain == "" {
+		return errors.Errorf("no domain to freeze")
+	}
+	if m.Since.IsZero() {
+		m.Since = time.Now().UTC()
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(markerPath(dir, m.Domain), append(b, '\n'), 0644)
+}
+
+// Unfreeze removes the marker of domain. It is an error if domain is not frozen.
+func Unfreeze(dir, domain string) error {
+	err := os.Remove(markerPath(dir, domain))
+	if os.IsNotExist(err) {
+		return errors.Errorf("%s is not frozen", domain)
+	}
+	return err
+}
+
+// Get returns the marker of domain, or nil if it is not frozen.
+func Get(dir, domain string) (*Marker, error) {
+	b, err := ioutil.ReadFile(markerPath(dir, domain))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	m := &Marker{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, errors.Wrapf(err, "invalid freeze marker for %s", domain)
+	}
+	return m, nil
+}
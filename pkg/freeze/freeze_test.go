@@ -0,0 +1,41 @@
+package freeze
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestFreezeUnfreeze(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freeze")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if m, err := Get(dir, "example.com"); m != nil || err != nil {
+		t.Fatalf("expected example.com not to be frozen, got %v %v", m, err)
+	}
+	if err := Freeze(dir, &Marker{Domain: "Example.com", Reason: "INC-42", By: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	m, err := Get(dir, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m == nil || m.Reason != "INC-42" || m.By != "alice" || m.Since.IsZero() {
+		t.Fatalf("unexpected marker %+v", m)
+	}
+	if m, _ := Get(dir, "example.net"); m != nil {
+		t.Errorf("expected example.net not to be frozen")
+	}
+	if err := Unfreeze(dir, "example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if m, _ := Get(dir, "example.com"); m != nil {
+		t.Errorf("expected example.com to be unfrozen")
+	}
+	if err := Unfreeze(dir, "example.com"); err == nil {
+		t.Errorf("expected an error unfreezing a domain that is not frozen")
+	}
+}
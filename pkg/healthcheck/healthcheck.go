@@ -0,0 +1,174 @@
+// Package healthcheck prunes unhealthy targets from round-robin pools.
+//
+// An A or AAAA record with a HEALTH_CHECK() is probed when dnscontrol
+// runs. If the probe fails, the record is left out of the zone, so the
+// other members of its pool (the records with the same name and type)
+// get the traffic. This is a poor man's failover for providers without
+// health checks: the probes only run during preview and push, so a
+// target that fails in between stays published until the next push.
+package healthcheck
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/redact"
+	"github.com/pkg/errors"
+)
+
+// DefaultTimeout is the timeout of a probe without a health_timeout.
+const DefaultTimeout = 5 * time.Second
+
+// Check is a parsed health_check: tcp:PORT, http:PORT/PATH or https:PORT/PATH.
+type Check struct {
+	Proto string
+	Port  int
+	Path  string
+}
+
+func (c *Check) String() string {
+	if c.Proto == "tcp" {
+		return fmt.Sprintf("tcp:%d", c.Port)
+	}
+	return fmt.Sprintf("%s:%d%s", c.Proto, c.Port, c.Path)
+}
+
+// Parse parses a health_check.
+func Parse(s string) (*Check, error) {
+	i := strings.Index(s, ":")
+	if i < 0 {
+		return nil, errors.Errorf("health check %q is not tcp:PORT, http:PORT/PATH or https:PORT/PATH", s)
+	}
+	c := &Check{Proto: s[:i]}
+	port := s[i+1:]
+	switch c.Proto {
+	case "tcp":
+	case "http", "https":
+		c.Path = "/"
+		if j := strings.Index(port, "/"); j >= 0 {
+			port, c.Path = port[:j], port[j:]
+		}
+	default:
+		return nil, errors.Errorf("health check %q: protocol %q is not tcp, http or https", s, c.Proto)
+	}
+	n, err := strconv.ParseUint(port, 10, 16)
+	if err != nil || n == 0 {
+		return nil, errors.Errorf("health check %q: invalid port %q", s, port)
+	}
+	c.Port = int(n)
+	return c, nil
+}
+
+// probe runs a check against addr. host is the name the target serves,
+// used for HTTP Host headers and TLS server names. Tests replace it.
+var probe = func(c *Check, addr, host string, timeout time.Duration) error {
+	hostport := net.JoinHostPort(addr, strconv.Itoa(c.Port))
+	if c.Proto == "tcp" {
+		conn, err := net.DialTimeout("tcp", hostport, timeout)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{ServerName: host},
+		},
+		// A redirect is an answer; don't follow it to another server.
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s://%s%s", c.Proto, hostport, c.Path), nil)
+	if err != nil {
+		return err
+	}
+	req.Host = host
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return errors.Errorf("HTTP status %s", resp.Status)
+	}
+	return nil
+}
+
+// Prune probes the records of dc that have a health_check, and removes
+// those that fail. It never removes all the records of a pool: if they
+// all fail, they are all kept. It returns a message for each failure.
+func Prune(dc *models.DomainConfig) (msgs []string) {
+	type result struct {
+		rec *models.RecordConfig
+		err error
+	}
+	var results []*result
+	var wg sync.WaitGroup
+	for _, rec := range dc.Records {
+		s, ok := rec.Metadata["health_check"]
+		if !ok || (rec.Type != "A" && rec.Type != "AAAA") {
+			continue
+		}
+		r := &result{rec: rec}
+		results = append(results, r)
+		c, err := Parse(s)
+		if err != nil {
+			r.err = err
+			continue
+		}
+		timeout := DefaultTimeout
+		if t, ok := rec.Metadata["health_timeout"]; ok {
+			if n, err := strconv.ParseUint(t, 10, 32); err == nil && n > 0 {
+				timeout = time.Duration(n) * time.Second
+			}
+		}
+		wg.Add(1)
+		go func() {
+			defer redact.RecoverPanic()
+			defer wg.Done()
+			r.err = probe(c, r.rec.GetTargetField(), r.rec.GetLabelFQDN(), timeout)
+		}()
+	}
+	wg.Wait()
+
+	failed := map[*models.RecordConfig]bool{}
+	healthy := map[models.RecordKey]int{}
+	for _, r := range results {
+		if r.err != nil {
+			failed[r.rec] = true
+		}
+	}
+	for _, rec := range dc.Records {
+		if !failed[rec] {
+			healthy[rec.Key()]++
+		}
+	}
+	records := models.Records{}
+	for _, r := range results {
+		if r.err == nil {
+			continue
+		}
+		if healthy[r.rec.Key()] == 0 {
+			msgs = append(msgs, fmt.Sprintf("health check of %s %s %s failed (%s); keeping it, as no other record of %s is healthy",
+				r.rec.GetLabelFQDN(), r.rec.Type, r.rec.GetTargetField(), r.err, r.rec.GetLabelFQDN()))
+			delete(failed, r.rec)
+		} else {
+			msgs = append(msgs, fmt.Sprintf("health check of %s %s %s failed (%s); leaving it out until a push finds it healthy",
+				r.rec.GetLabelFQDN(), r.rec.Type, r.rec.GetTargetField(), r.err))
+		}
+	}
+	for _, rec := range dc.Records {
+		if !failed[rec] {
+			records = append(records, rec)
+		}
+	}
+	dc.Records = records
+	return msgs
+}
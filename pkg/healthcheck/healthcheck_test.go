@@ -0,0 +1,109 @@
+package healthcheck
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/models"
+)
+
+func TestParse(t *testing.T) {
+	for s, want := range map[string]string{
+		"tcp:443":          "tcp:443",
+		"http:80":          "http:80/",
+		"https:8443/up?x=": "https:8443/up?x=",
+		"tcp":              "",
+		"udp:53":           "",
+		"tcp:0":            "",
+		"http:99999/":      "",
+	} {
+		c, err := Parse(s)
+		if want == "" {
+			if err == nil {
+				t.Errorf("%q: expected an error, got %v", s, c)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %s", s, err)
+		} else if c.String() != want {
+			t.Errorf("%q: got %s, want %s", s, c, want)
+		}
+	}
+}
+
+func TestProbe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Host != "www.example.com" {
+			t.Errorf("unexpected Host %q", r.Host)
+		}
+		if r.URL.Path != "/up" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+
+	for check, ok := range map[string]bool{
+		"tcp:" + port:            true,
+		"http:" + port + "/up":   true,
+		"http:" + port + "/down": false,
+	} {
+		c, err := Parse(check)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = probe(c, "127.0.0.1", "www.example.com", time.Second)
+		if (err == nil) != ok {
+			t.Errorf("%s: got %v", check, err)
+		}
+	}
+}
+
+func TestPrune(t *testing.T) {
+	defer func(p func(*Check, string, string, time.Duration) error) { probe = p }(probe)
+	down := map[string]bool{"192.0.2.2": true, "192.0.2.10": true, "192.0.2.11": true}
+	probe = func(c *Check, addr, host string, timeout time.Duration) error {
+		if down[addr] {
+			return fmt.Errorf("connection refused")
+		}
+		return nil
+	}
+
+	dc := &models.DomainConfig{Name: "example.com"}
+	add := func(label, typ, target string, check bool) {
+		rc := &models.RecordConfig{Type: typ, Metadata: map[string]string{}}
+		rc.SetLabel(label, dc.Name)
+		rc.SetTarget(target)
+		if check {
+			rc.Metadata["health_check"] = "tcp:443"
+		}
+		dc.Records = append(dc.Records, rc)
+	}
+	add("www", "A", "192.0.2.1", true)
+	add("www", "A", "192.0.2.2", true)  // Pruned.
+	add("api", "A", "192.0.2.10", true) // The whole pool is down: kept.
+	add("api", "A", "192.0.2.11", true)
+	add("old", "A", "192.0.2.2", false) // No check.
+
+	msgs := Prune(dc)
+	if len(msgs) != 3 {
+		t.Fatalf("expected 3 messages, got %q", msgs)
+	}
+	if !strings.Contains(msgs[0], "leaving it out") || !strings.Contains(msgs[1], "keeping it") {
+		t.Errorf("unexpected messages %q", msgs)
+	}
+	var got []string
+	for _, rc := range dc.Records {
+		got = append(got, rc.GetLabel()+" "+rc.GetTargetField())
+	}
+	want := "www 192.0.2.1,api 192.0.2.10,api 192.0.2.11,old 192.0.2.2"
+	if strings.Join(got, ",") != want {
+		t.Errorf("got %s, want %s", strings.Join(got, ","), want)
+	}
+}
@@ -0,0 +1,173 @@
+package js
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/pkg/errors"
+	"github.com/robertkrimen/otto"
+)
+
+// AllowFetch enables FETCH() in dnsconfig.js. It is off by default, so
+// that running a configuration doesn't reach the network unless asked to.
+var AllowFetch bool
+
+// FetchCacheFile, if set, is a JSON file that keeps the last response
+// to each URL given to FETCH(). It is used when the URL can't be fetched,
+// so that an endpoint being down doesn't stop a push.
+var FetchCacheFile string
+
+// FetchHosts, if set, are the only hosts that FETCH() may download from,
+// so that a configuration can't reach other hosts of the network it runs
+// in.
+var FetchHosts []string
+
+// fetchMaxSize is the largest response that FETCH() accepts.
+const fetchMaxSize = 10 << 20
+
+var fetchClient = &http.Client{
+	Timeout: 30 * time.Second,
+	// A redirect may only lead to the hosts of --fetch-hosts, too.
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return checkFetchHost(req.URL.String())
+	},
+}
+
+// fetched are the responses of this run of dnsconfig.js, by URL; newVM
+// resets them. Each URL is only fetched once per run, so that every
+// FETCH() of it sees the same data, and each run, such as those of the
+// daemon, sees the current data.
+var fetched = map[string]string{}
+
+func fetch(call otto.FunctionCall) otto.Value {
+	if len(call.ArgumentList) != 1 {
+		throw(call.Otto, "FETCH takes exactly one argument")
+	}
+	url := call.Argument(0).String()
+	if !AllowFetch {
+		throw(call.Otto, "FETCH(\""+url+"\"): FETCH is disabled; run dnscontrol with --allow-fetch to enable it")
+	}
+	body, err := fetchURL(url)
+	if err != nil {
+		throw(call.Otto, err.Error())
+	}
+	v, _ := otto.ToValue(body)
+	return v
+}
+
+// fetchURL returns the body of url, from the cache of this run, the
+// network, or the cache file, in that order.
+func fetchURL(url string) (string, error) {
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		return "", errors.Errorf("FETCH(%q): only http and https URLs can be fetched", url)
+	}
+	if err := checkFetchHost(url); err != nil {
+		return "", err
+	}
+	if body, ok := fetched[url]; ok {
+		return body, nil
+	}
+	body, err := httpGet(url)
+	if err != nil {
+		cache, cerr := readFetchCache()
+		cached, ok := cache[url]
+		if cerr != nil || !ok {
+			return "", errors.Wrapf(err, "FETCH(%q)", url)
+		}
+		printer.Warnf("FETCH(%q): %s; using the copy in %s\n", url, err, FetchCacheFile)
+		body = cached
+	} else if err := writeFetchCache(url, body); err != nil {
+		return "", err
+	}
+	fetched[url] = body
+	return body, nil
+}
+
+// checkFetchHost returns an error if FetchHosts is set, and the host of
+// rawurl isn't one of them.
+func checkFetchHost(rawurl string) error {
+	if len(FetchHosts) == 0 {
+		return nil
+	}
+	u, err := neturl.Parse(rawurl)
+	if err != nil {
+		return errors.Wrapf(err, "FETCH(%q)", rawurl)
+	}
+	for _, h := range FetchHosts {
+		if strings.EqualFold(h, u.Hostname()) || strings.EqualFold(h, u.Host) {
+			return nil
+		}
+	}
+	return errors.Errorf("FETCH(%q): %s is not one of the hosts of --fetch-hosts", rawurl, u.Host)
+}
+
+func httpGet(url string) (string, error) {
+	printer.Debugf("fetching: %s\n", url)
+	resp, err := fetchClient.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("status %s", resp.Status)
+	}
+	data, err := ioutil.ReadAll(&io.LimitedReader{R: resp.Body, N: fetchMaxSize + 1})
+	if err != nil {
+		return "", err
+	}
+	if len(data) > fetchMaxSize {
+		return "", errors.Errorf("response is larger than %d bytes", fetchMaxSize)
+	}
+	return string(data), nil
+}
+
+// readFetchCache returns the contents of FetchCacheFile, which are empty
+// if there is no such file.
+func readFetchCache() (map[string]string, error) {
+	cache := map[string]string{}
+	if FetchCacheFile == "" {
+		return cache, nil
+	}
+	data, err := ioutil.ReadFile(FetchCacheFile)
+	if os.IsNotExist(err) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, errors.Wrapf(err, "reading %s", FetchCacheFile)
+	}
+	return cache, nil
+}
+
+// writeFetchCache records body as the response to url in
+// FetchCacheFile, if it changed.
+func writeFetchCache(url, body string) error {
+	if FetchCacheFile == "" {
+		return nil
+	}
+	cache, err := readFetchCache()
+	if err != nil {
+		return err
+	}
+	if cached, ok := cache[url]; ok && cached == body {
+		return nil
+	}
+	cache[url] = body
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(FetchCacheFile, data, 0644)
+}
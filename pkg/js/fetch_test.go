@@ -0,0 +1,125 @@
+package js
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/ips" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `["10.0.0.1", "10.0.0.2"]`)
+	}))
+	defer ts.Close()
+	dir, err := ioutil.TempDir("", "fetch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func() { AllowFetch, FetchCacheFile = false, "" }()
+
+	script := fmt.Sprintf(`var ips = JSON.parse(FETCH(%q));
+D("foo.com", "none", A("a", ips[0]), A("b", JSON.parse(FETCH(%q))[1]));`, ts.URL+"/ips", ts.URL+"/ips")
+	run := func() error {
+		_, err := ExecuteJavascriptSource("dnsconfig.js", []byte(script), true)
+		return err
+	}
+
+	if err := run(); err == nil {
+		t.Fatal("FETCH worked without AllowFetch")
+	}
+	AllowFetch = true
+	FetchCacheFile = filepath.Join(dir, "fetchcache.json")
+	if err := run(); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Errorf("the URL was fetched %d times, want once", requests)
+	}
+	// Each run fetches it again.
+	if err := run(); err != nil || requests != 2 {
+		t.Errorf("the second run fetched the URL %d times in all (err %v), want twice", requests, err)
+	}
+
+	// Once the server is down, the cache file is used.
+	ts.Close()
+	if err := run(); err != nil {
+		t.Errorf("with the cache file: %s", err)
+	}
+	FetchCacheFile = ""
+	if err := run(); err == nil {
+		t.Error("FETCH of a closed server worked without the cache file")
+	}
+}
+
+func TestFetchErrors(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+	for _, url := range []string{ts.URL + "/nosuch", "file:///etc/passwd"} {
+		if _, err := fetchURL(url); err == nil {
+			t.Errorf("%s: expected an error", url)
+		}
+	}
+}
+
+func TestFetchHosts(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer ts.Close()
+	defer func() { FetchHosts = nil }()
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		hosts []string
+		ok    bool
+	}{
+		{nil, true},
+		{[]string{"ipam.example.com", u.Hostname()}, true},
+		{[]string{u.Host}, true},
+		{[]string{"ipam.example.com"}, false},
+	}
+	for _, tst := range tests {
+		FetchHosts = tst.hosts
+		if _, err := fetchURL(ts.URL); (err == nil) != tst.ok {
+			t.Errorf("hosts %v: err is %v", tst.hosts, err)
+		}
+	}
+}
+
+func TestFetchHostsRedirect(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "secret")
+	}))
+	defer other.Close()
+	ts := httptest.NewServer(http.RedirectHandler(other.URL, http.StatusFound))
+	defer ts.Close()
+	defer func() { FetchHosts = nil }()
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	FetchHosts = []string{u.Host}
+	if body, err := fetchURL(ts.URL + "/redirect"); err == nil {
+		t.Errorf("a redirect to a host not in --fetch-hosts was followed: %q", body)
+	}
+	FetchHosts = nil
+	if body, err := fetchURL(ts.URL + "/redirect"); err != nil || body != "secret" {
+		t.Errorf("without --fetch-hosts, got %q, %v", body, err)
+	}
+}
@@ -9,6 +9,12 @@ var conf = {
 
 var defaultArgs = [];
 
+// defaultsStack are the defaultArgs that PUSH_DEFAULTS() saved.
+var defaultsStack = [];
+
+// templates are the modifiers of each TEMPLATE(), by name.
+var templates = {};
+
 function initialize() {
     conf = {
         registrars: [],
@@ -16,6 +22,8 @@ function initialize() {
         domains: [],
     };
     defaultArgs = [];
+    defaultsStack = [];
+    templates = {};
 }
 
 function NewRegistrar(name, type, meta) {
@@ -44,8 +52,11 @@ function newDomain(name, registrar) {
         records: [],
         dnsProviders: {},
         defaultTTL: 0,
+        defaultTypeTTLs: {},
         nameservers: [],
-        ignored_labels: [],
+        ignored_names: [],
+        ignored_targets: [],
+        ensure_absent: [],
     };
 }
 
@@ -63,16 +74,25 @@ function processDargs(m, domain) {
     } else if (_.isObject(m)) {
         _.extend(domain.meta, m);
     } else {
-        throw 'WARNING: domain modifier type unsupported: ' +
+        throw new Error('WARNING: domain modifier type unsupported: ' +
             typeof m +
             ' Domain: ' +
-            domain.name;
+            domain.name);
     }
 }
 
 // D(name,registrar): Create a DNS Domain. Use the parameters as records and mods.
+// A name such as "example.com!internal" declares a view of a split horizon
+// domain.
 function D(name, registrar) {
-    var domain = newDomain(name, registrar);
+    var parts = name.split('!');
+    if (parts.length > 2 || (parts.length === 2 && !/^[\w-]+$/.test(parts[1]))) {
+        throw new Error('D("' + name + '"): a view must be a name of letters, digits, "-" and "_" after a single "!"');
+    }
+    var domain = newDomain(parts[0], registrar);
+    if (parts.length === 2) {
+        domain.tag = parts[1];
+    }
     for (var i = 0; i < defaultArgs.length; i++) {
         processDargs(defaultArgs[i], domain);
     }
@@ -81,12 +101,25 @@ function D(name, registrar) {
         processDargs(m, domain);
     }
     if (conf.domain_names.indexOf(name) !== -1) {
-        throw name + ' is declared more than once';
+        throw new Error(name + ' is declared more than once');
     }
     conf.domains.push(domain);
     conf.domain_names.push(name);
 }
 
+// D_EXTEND(name): Add records and modifiers to a domain declared by D()
+// before, for example in another file.
+function D_EXTEND(name) {
+    var index = conf.domain_names.indexOf(name);
+    if (index === -1) {
+        throw new Error('D_EXTEND(' + name + '): the domain must be declared with D() first');
+    }
+    var domain = conf.domains[index];
+    for (var i = 1; i < arguments.length; i++) {
+        processDargs(arguments[i], domain);
+    }
+}
+
 // DEFAULTS provides a set of default arguments to apply to all future domains.
 // Each call to DEFAULTS will clear any previous values set.
 function DEFAULTS() {
@@ -96,6 +129,197 @@ function DEFAULTS() {
     }
 }
 
+// PUSH_DEFAULTS(modifiers...): Save the current defaults, and add modifiers
+// to them until POP_DEFAULTS().
+function PUSH_DEFAULTS() {
+    defaultsStack.push(defaultArgs);
+    defaultArgs = defaultArgs.slice();
+    for (var i = 0; i < arguments.length; i++) {
+        defaultArgs.push(arguments[i]);
+    }
+}
+
+// POP_DEFAULTS(): Restore the defaults that the last PUSH_DEFAULTS() saved.
+function POP_DEFAULTS() {
+    if (defaultsStack.length === 0) {
+        throw new Error('POP_DEFAULTS() without PUSH_DEFAULTS()');
+    }
+    defaultArgs = defaultsStack.pop();
+}
+
+// WITH_DEFAULTS(modifiers..., fn): Call fn with modifiers added to the
+// defaults of the domains it declares.
+function WITH_DEFAULTS() {
+    var fn = arguments[arguments.length - 1];
+    if (!_.isFunction(fn) || fn.length !== 0) {
+        throw new Error('WITH_DEFAULTS takes modifiers and then a function without arguments that declares the domains');
+    }
+    PUSH_DEFAULTS.apply(null, Array.prototype.slice.call(arguments, 0, -1));
+    try {
+        fn();
+    } finally {
+        POP_DEFAULTS();
+    }
+}
+
+// CLI_DEFAULTS({key: value, ...}): Set the properties of CLI that were not
+// given with -v key=value on the command line.
+function CLI_DEFAULTS(defaults) {
+    if (!_.isObject(defaults) || _.isArray(defaults)) {
+        throw new Error('CLI_DEFAULTS takes an object of variables and their default values');
+    }
+    for (var key in defaults) {
+        if (!_.has(CLI, key)) {
+            CLI[key] = defaults[key];
+        }
+    }
+}
+
+// TEMPLATE(name, modifiers...): Declare a set of records and modifiers that
+// APPLY_TEMPLATE() adds to domains. Their strings can have parameters such as
+// ${tenant}, which APPLY_TEMPLATE() replaces.
+function TEMPLATE(name) {
+    if (!_.isString(name) || name === '') {
+        throw new Error('TEMPLATE needs a name');
+    }
+    if (_.has(templates, name)) {
+        throw new Error('TEMPLATE(' + JSON.stringify(name) + ') is declared more than once');
+    }
+    templates[name] = Array.prototype.slice.call(arguments, 1);
+}
+
+// APPLY_TEMPLATE(name, params): Add the records and modifiers of
+// TEMPLATE(name) to the domain, with the parameters of their strings
+// replaced by the values of params. ${domain} is the name of the domain.
+function APPLY_TEMPLATE(name, params) {
+    if (!_.has(templates, name)) {
+        throw new Error('APPLY_TEMPLATE(' + JSON.stringify(name) + '): no such TEMPLATE; declare it before applying it');
+    }
+    var mods = templates[name];
+    return function(d) {
+        var values = _.extend({ domain: d.name }, params);
+        var replace = function(v) {
+            return v.replace(/\$\{(\w+)\}/g, function(match, key) {
+                if (!_.has(values, key)) {
+                    throw new Error('APPLY_TEMPLATE(' + JSON.stringify(name) + ') in ' + d.name + ': no value for ' + match);
+                }
+                return String(values[key]);
+            });
+        };
+        var substitute = function(v) {
+            if (_.isString(v)) {
+                return replace(v);
+            }
+            if (_.isArray(v)) {
+                return _.map(v, substitute);
+            }
+            if (_.isObject(v)) {
+                var o = {};
+                for (var k in v) {
+                    o[k] = substitute(v[k]);
+                }
+                return o;
+            }
+            return v;
+        };
+        var first = d.records.length;
+        for (var i = 0; i < mods.length; i++) {
+            processDargs(mods[i], d);
+        }
+        for (var i = first; i < d.records.length; i++) {
+            d.records[i] = substitute(d.records[i]);
+        }
+    };
+}
+
+// PRIORITY_HINT(v): Make push change a record before ("first") or after
+// ("last") the other changes of the same kind in its zone.
+function PRIORITY_HINT(v) {
+    if (v !== 'first' && v !== 'last') {
+        throw new Error('PRIORITY_HINT must be "first" or "last"');
+    }
+    return {priority_hint: v};
+}
+
+// PROVIDERS(names...): Send a record only to these DNS providers of its
+// domain, instead of to all of them.
+function PROVIDERS() {
+    var names = Array.prototype.slice.call(arguments);
+    if (names.length === 0) {
+        throw new Error('PROVIDERS needs the names of DNS providers');
+    }
+    for (var i = 0; i < names.length; i++) {
+        if (!_.isString(names[i]) || names[i] === '' || names[i].indexOf(',') !== -1) {
+            throw new Error('PROVIDERS takes the names of DNS providers, not ' + JSON.stringify(names[i]));
+        }
+    }
+    return {providers: names.join(',')};
+}
+
+// NOTE(text): Say why a record exists. Providers with comments on records
+// keep it; the others show it in the corrections.
+function NOTE(text) {
+    if (!_.isString(text) || text === '') {
+        throw new Error('NOTE needs the text of the note');
+    }
+    return {note: text};
+}
+
+// HEALTH_CHECK(check, timeout): Leave an A/AAAA record out of the zone
+// if its target fails check ("tcp:PORT", "http:PORT/PATH" or
+// "https:PORT/PATH") when preview or push runs.
+function HEALTH_CHECK(check, timeout) {
+    if (!_.isString(check) || !/^(tcp:\d+|https?:\d+(\/.*)?)$/.test(check)) {
+        throw new Error('HEALTH_CHECK must be "tcp:PORT", "http:PORT/PATH" or "https:PORT/PATH"');
+    }
+    var m = {health_check: check};
+    if (timeout !== undefined) {
+        if (_.isString(timeout)) {
+            timeout = stringToDuration(timeout);
+        }
+        m.health_timeout = timeout.toString();
+    }
+    return m;
+}
+
+// ROUTING(options): Make a record one of the sets of a weighted, geo or
+// failover routing policy, on providers that have them. The options are
+// set (the name of the set), one of weight (0-255), geo ("continent:EU",
+// "DE", "US-CA" or "*") and failover ("primary" or "secondary"), and
+// health_check (the ID of a health check of the provider).
+function ROUTING(options) {
+    if (!_.isObject(options)) {
+        throw new Error('ROUTING takes an object of options');
+    }
+    var m = {};
+    for (var k in options) {
+        if (k !== 'set' && k !== 'weight' && k !== 'geo' && k !== 'failover' && k !== 'health_check') {
+            throw new Error('ROUTING: unknown option ' + k);
+        }
+        m['routing_' + k] = String(options[k]);
+    }
+    if (!options.set) {
+        throw new Error('ROUTING needs the name of the set');
+    }
+    return m;
+}
+
+// TTL_POLICY(policy): Set the org-wide TTL policy for NS records
+// (ns_ttl) and negative caching (negative_ttl, the SOA minimum).
+function TTL_POLICY(policy) {
+    conf.ttl_policy = {};
+    for (var k in policy) {
+        if (k !== 'ns_ttl' && k !== 'negative_ttl') {
+            throw new Error('TTL_POLICY: unknown setting ' + k);
+        }
+        var v = policy[k];
+        if (_.isString(v)) {
+            v = stringToDuration(v);
+        }
+        conf.ttl_policy[k] = v;
+    }
+}
+
 // TTL(v): Set the TTL for a DNS record.
 function TTL(v) {
     if (_.isString(v)) {
@@ -109,7 +333,7 @@ function TTL(v) {
 function stringToDuration(v) {
     var matches = v.match(/^(\d+)([smhdwny]?)$/);
     if (matches == null) {
-        throw v + ' is not a valid duration string';
+        throw new Error(v + ' is not a valid duration string');
     }
     unit = 's';
     if (matches[2]) {
@@ -125,16 +349,41 @@ function stringToDuration(v) {
     return v;
 }
 
-// DefaultTTL(v): Set the default TTL for the domain.
-function DefaultTTL(v) {
+// DefaultTTL(v, types): Set the default TTL for the domain, or for its
+// records of the given types ("TXT,MX" or ["TXT", "MX"]).
+function DefaultTTL(v, types) {
     if (_.isString(v)) {
         v = stringToDuration(v);
     }
+    if (_.isArray(types)) {
+        types = types.join(',');
+    }
+    if (types !== undefined && !_.isString(types)) {
+        throw new Error('DefaultTTL(' + v + '): types must be a string or a list of strings');
+    }
     return function(d) {
-        d.defaultTTL = v;
+        if (types === undefined) {
+            d.defaultTTL = v;
+            return;
+        }
+        var list = types.split(',');
+        for (var i = 0; i < list.length; i++) {
+            var t = list[i].trim().toUpperCase();
+            if (t !== '') {
+                d.defaultTypeTTLs[t] = v;
+            }
+        }
     };
 }
 
+// defaultTTLOf returns the TTL of the records of type that don't set one.
+function defaultTTLOf(d, type) {
+    if (_.has(d.defaultTypeTTLs, type)) {
+        return d.defaultTypeTTLs[type];
+    }
+    return d.defaultTTL;
+}
+
 function makeCAAFlag(value) {
     return function(record) {
         record.caaflag |= value;
@@ -165,6 +414,20 @@ var AAAA = recordBuilder('AAAA');
 // ALIAS(name,target, recordModifiers...)
 var ALIAS = recordBuilder('ALIAS');
 
+// ALIAS_FALLBACK(policy): Replace an ALIAS record with the A/AAAA records
+// of its target for DNS providers that can't use ALIAS. The target is
+// resolved on every run ("live") or the addresses are kept in
+// aliascache.json ("cached").
+function ALIAS_FALLBACK(policy) {
+    if (policy === undefined) {
+        policy = 'live';
+    }
+    if (policy !== 'live' && policy !== 'cached') {
+        throw new Error('ALIAS_FALLBACK policy must be "live" or "cached"');
+    }
+    return {alias_fallback: policy};
+}
+
 // R53_ALIAS(name, target, type, recordModifiers...)
 var R53_ALIAS = recordBuilder('R53_ALIAS', {
     args: [['name', _.isString], ['type', validateR53AliasType], ['target', _.isString]],
@@ -211,9 +474,36 @@ var CAA = recordBuilder('CAA', {
     },
 });
 
+// CERT(name,type,keytag,algorithm,certificate, recordModifiers...)
+var CERT = recordBuilder('CERT', {
+    args: [
+        ['name', _.isString],
+        ['type', _.isNumber],
+        ['keytag', _.isNumber],
+        ['algorithm', _.isNumber],
+        ['target', _.isString],
+    ],
+    transform: function(record, args, modifiers) {
+        record.name = args.name;
+        record.certtype = args.type;
+        record.certkeytag = args.keytag;
+        record.certalgorithm = args.algorithm;
+        record.target = args.target;
+    },
+});
+
 // CNAME(name,target, recordModifiers...)
 var CNAME = recordBuilder('CNAME');
 
+// DHCID(name,digest, recordModifiers...)
+var DHCID = recordBuilder('DHCID');
+
+// DNAME(name,target, recordModifiers...)
+var DNAME = recordBuilder('DNAME');
+
+// OPENPGPKEY(name,publickey, recordModifiers...)
+var OPENPGPKEY = recordBuilder('OPENPGPKEY');
+
 // PTR(name,target, recordModifiers...)
 var PTR = recordBuilder('PTR');
 
@@ -239,6 +529,35 @@ var NAPTR = recordBuilder('NAPTR', {
     },
 });
 
+// RP(name,mbox,txt, recordModifiers...)
+// mbox may be an email address; txt is "." if there is no TXT record.
+var RP = recordBuilder('RP', {
+    args: [['name', _.isString], ['mbox', _.isString], ['txt', _.isString]],
+    transform: function(record, args, modifiers) {
+        record.name = args.name;
+        record.target = args.mbox;
+        record.rptxt = args.txt;
+    },
+});
+
+// SMIMEA(name,usage,selector,matchingtype,certificate, recordModifiers...)
+var SMIMEA = recordBuilder('SMIMEA', {
+    args: [
+        ['name', _.isString],
+        ['usage', _.isNumber],
+        ['selector', _.isNumber],
+        ['matchingtype', _.isNumber],
+        ['target', _.isString],
+    ],
+    transform: function(record, args, modifiers) {
+        record.name = args.name;
+        record.smimeausage = args.usage;
+        record.smimeaselector = args.selector;
+        record.smimeamatchingtype = args.matchingtype;
+        record.target = args.target;
+    },
+});
+
 // SRV(name,priority,weight,port,target, recordModifiers...)
 var SRV = recordBuilder('SRV', {
     args: [
@@ -257,6 +576,79 @@ var SRV = recordBuilder('SRV', {
     },
 });
 
+// CSYNC(name,serial,flags,types, recordModifiers...)
+// types is a string ("A NS AAAA") or an array of rtypes.
+var CSYNC = recordBuilder('CSYNC', {
+    args: [
+        ['name', _.isString],
+        ['serial', _.isNumber],
+        ['flags', _.isNumber],
+        ['types', isStringOrArray],
+    ],
+    transform: function(record, args, modifiers) {
+        record.name = args.name;
+        record.csyncserial = args.serial;
+        record.csyncflags = args.flags;
+        record.target = _.isArray(args.types)
+            ? args.types.join(' ')
+            : args.types;
+    },
+});
+
+// SOA(name,mname,rname,refresh,retry,expire,minimum, recordModifiers...)
+// The serial number is managed by the provider.
+var SOA = recordBuilder('SOA', {
+    args: [
+        ['name', _.isString],
+        ['mname', _.isString],
+        ['rname', _.isString],
+        ['refresh', _.isNumber],
+        ['retry', _.isNumber],
+        ['expire', _.isNumber],
+        ['minimum', _.isNumber],
+    ],
+    transform: function(record, args, modifiers) {
+        record.name = args.name;
+        record.target = [
+            args.mname,
+            args.rname,
+            0,
+            args.refresh,
+            args.retry,
+            args.expire,
+            args.minimum,
+        ].join(' ');
+    },
+});
+
+// UNKNOWN(name,type,rdata, recordModifiers...)
+// A record of a type dnscontrol doesn't model: type is the rtype number
+// and rdata its hex-encoded RFC 3597 rdata.
+var UNKNOWN = recordBuilder('UNKNOWN', {
+    args: [['name', _.isString], ['type', _.isNumber], ['rdata', _.isString]],
+    transform: function(record, args, modifiers) {
+        record.type = 'TYPE' + args.type;
+        record.name = args.name;
+        record.target = args.rdata;
+    },
+});
+
+// URI(name,priority,weight,target, recordModifiers...)
+var URI = recordBuilder('URI', {
+    args: [
+        ['name', _.isString],
+        ['priority', _.isNumber],
+        ['weight', _.isNumber],
+        ['target', _.isString],
+    ],
+    transform: function(record, args, modifiers) {
+        record.name = args.name;
+        record.uripriority = args.priority;
+        record.uriweight = args.weight;
+        record.target = args.target;
+    },
+});
+
 // SSHFP(name,algorithm,type,value, recordModifiers...)
 var SSHFP = recordBuilder('SSHFP', {
     args: [
@@ -318,6 +710,22 @@ var TXT = recordBuilder('TXT', {
     },
 });
 
+// PENDING_VERIFICATION(service,token,[name], recordModifiers...)
+// A TXT record (at "@" unless name is given) that proves control of the
+// domain to service. Once `dnscontrol verify-complete` records that the
+// service verified the domain, push removes it after a grace period.
+function PENDING_VERIFICATION(service, token) {
+    if (!_.isString(service) || !/^[a-z0-9][a-z0-9._-]*$/.test(service)) {
+        throw new Error('PENDING_VERIFICATION service must be lowercase letters, digits, ".", "_" and "-"');
+    }
+    var rest = Array.prototype.slice.call(arguments, 2);
+    var name = '@';
+    if (_.isString(rest[0])) {
+        name = rest.shift();
+    }
+    return TXT.apply(null, [name, token, { verification: service }].concat(rest));
+}
+
 // MX(name,priority,target, recordModifiers...)
 var MX = recordBuilder('MX', {
     args: [
@@ -353,6 +761,25 @@ function NAMESERVER_TTL(v) {
     return {ns_ttl: v.toString()};
 }
 
+// MAX_CHANGES(n): Make push abort if it would change more than n records
+// of the domain at one DNS provider.
+function MAX_CHANGES(n) {
+    return {max_changes: maxCount('MAX_CHANGES', n)};
+}
+
+// MAX_DELETES(n): Make push abort if it would delete more than n records
+// of the domain at one DNS provider.
+function MAX_DELETES(n) {
+    return {max_deletes: maxCount('MAX_DELETES', n)};
+}
+
+function maxCount(name, n) {
+    if (!_.isNumber(n) || n < 1 || n % 1 !== 0) {
+        throw new Error(name + ' must be a positive integer');
+    }
+    return n.toString();
+}
+
 function format_tt(transform_table) {
     // Turn [[low: 1, high: 2, newBase: 3], [low: 4, high: 5, newIP: 6]]
     // into "1 ~ 2 ~ 3 ~; 4 ~ 5 ~  ~ 6"
@@ -385,13 +812,43 @@ function format_tt(transform_table) {
     return lines.join(' ; ');
 }
 
-// IGNORE(name)
+// IGNORE(name): The old name of IGNORE_NAME(name).
 function IGNORE(name) {
-    return function (d) {
-        d.ignored_labels.push(name);
+    return IGNORE_NAME(name);
+}
+
+// IGNORE_NAME(pattern, types): Leave alone the records whose label matches
+// the glob pattern, of the given types ("A,AAAA" or ["A", "AAAA"]; all types
+// by default).
+function IGNORE_NAME(pattern, types) {
+    var rule = newIgnore('IGNORE_NAME', pattern, types);
+    return function(d) {
+        d.ignored_names.push(rule);
+    };
+}
+
+// IGNORE_TARGET(pattern, types): Leave alone the records whose target
+// matches the glob pattern, of the given types (all types by default).
+function IGNORE_TARGET(pattern, types) {
+    var rule = newIgnore('IGNORE_TARGET', pattern, types);
+    return function(d) {
+        d.ignored_targets.push(rule);
     };
 }
 
+function newIgnore(name, pattern, types) {
+    if (!_.isString(pattern) || pattern === '') {
+        throw new Error(name + ' needs a pattern');
+    }
+    if (_.isArray(types)) {
+        types = types.join(',');
+    }
+    if (types !== undefined && !_.isString(types)) {
+        throw new Error(name + '(' + pattern + '): types must be a string or a list of strings');
+    }
+    return { pattern: pattern, types: types };
+}
+
 // IMPORT_TRANSFORM(translation_table, domain)
 var IMPORT_TRANSFORM = recordBuilder('IMPORT_TRANSFORM', {
     args: [['translation_table'], ['domain'], ['ttl', _.isNumber]],
@@ -413,6 +870,227 @@ function NO_PURGE(d) {
     d.KeepUnknown = true;
 }
 
+// IGNORE_TTL: In D(), or as a record modifier, the TTL that a record has at
+// the provider is kept, instead of being corrected to the one of the
+// configuration.
+var IGNORE_TTL = { ignore_ttl: 'true' }; // TTL differences of the domain, or of the record, are not corrections.
+
+// MAX_CNAME_CHAIN(n): Warn about chains of more than n CNAMEs, instead of 3.
+function MAX_CNAME_CHAIN(n) {
+    if (!_.isNumber(n) || n < 1 || Math.floor(n) !== n) {
+        throw new Error('MAX_CNAME_CHAIN(' + n + '): the number of CNAMEs must be a whole number of at least 1');
+    }
+    return {max_cname_chain: String(n)};
+}
+
+// ENSURE_ABSENT(records...): Delete these records if they exist, even with
+// NO_PURGE.
+function ENSURE_ABSENT() {
+    var records = arguments;
+    return function(d) {
+        var tmp = newDomain(d.name, d.registrar);
+        tmp.defaultTTL = d.defaultTTL;
+        tmp.defaultTypeTTLs = d.defaultTypeTTLs;
+        for (var i = 0; i < records.length; i++) {
+            processDargs(records[i], tmp);
+        }
+        d.ensure_absent = d.ensure_absent.concat(tmp.records);
+    };
+}
+
+// OWNER(name)
+function OWNER(name) {
+    return function(d) {
+        d.owner = name;
+    };
+}
+
+// CONTACTS({registrant: contact, admin: contact, tech: contact})
+function CONTACTS(contacts) {
+    return function(d) {
+        for (var role in contacts) {
+            d.meta[role + '_contact'] = JSON.stringify(contacts[role]);
+        }
+    };
+}
+
+// REGISTRAR_LOCK(state)
+function REGISTRAR_LOCK(state) {
+    return function(d) {
+        d.registrar_lock = state;
+    };
+}
+
+// REGISTRAR_DS(keytag, algorithm, digesttype, digest)
+function REGISTRAR_DS(keytag, algorithm, digesttype, digest) {
+    return function(d) {
+        if (!d.registrar_ds) {
+            d.registrar_ds = [];
+        }
+        d.registrar_ds.push({
+            keytag: keytag,
+            algorithm: algorithm,
+            digesttype: digesttype,
+            digest: digest,
+        });
+    };
+}
+
+// REGISTRAR_DS_NONE
+function REGISTRAR_DS_NONE(d) {
+    d.no_registrar_ds = true;
+}
+
+// REGISTRAR_DS_AUTO
+function REGISTRAR_DS_AUTO(d) {
+    d.auto_ds = true;
+}
+
+// REPLICATE_FROM(name)
+function REPLICATE_FROM(name) {
+    return function(d) {
+        d.replicate_from = name;
+        // The source must be instantiated, but contributes no nameservers
+        // unless it is also listed with DnsProvider().
+        if (!(name in d.dnsProviders)) {
+            d.dnsProviders[name] = 0;
+        }
+    };
+}
+
+// CLASSLESS_DELEGATE(cidr, nameservers..., modifiers...): Delegate a /25 to
+// /31 block of IPv4 addresses as RFC 2317 describes: in the zone of the /24
+// that holds the block, add the NS records of the zone REV(cidr), and a CNAME
+// into that zone for each address of the block.
+function CLASSLESS_DELEGATE(cidr) {
+    var zone = REV(cidr);
+    var m = /^(\d+)\/(\d+)\.(.*)$/.exec(zone);
+    if (!m) {
+        throw new Error('CLASSLESS_DELEGATE(' + JSON.stringify(cidr) + '): the netmask must be /25 to /31');
+    }
+    var label = m[1] + '/' + m[2];
+    var first = parseInt(m[1], 10);
+    var size = 1 << (32 - parseInt(m[2], 10));
+    var parent = m[3];
+
+    var nameservers = [];
+    var mods = [];
+    for (var i = 1; i < arguments.length; i++) {
+        if (_.isString(arguments[i])) {
+            nameservers.push(arguments[i]);
+        } else {
+            mods.push(arguments[i]);
+        }
+    }
+    if (nameservers.length === 0) {
+        throw new Error('CLASSLESS_DELEGATE(' + JSON.stringify(cidr) + '): needs at least one nameserver');
+    }
+
+    return function(d) {
+        if (d.name !== parent) {
+            throw new Error('CLASSLESS_DELEGATE(' + JSON.stringify(cidr) + ') belongs in D("' + parent + '"), not in D("' + d.name + '")');
+        }
+        for (var i = 0; i < nameservers.length; i++) {
+            NS.apply(null, [label, nameservers[i]].concat(mods))(d);
+        }
+        for (var a = first; a < first + size; a++) {
+            CNAME.apply(null, [String(a), a + '.' + zone + '.'].concat(mods))(d);
+        }
+    };
+}
+
+// DELEGATE(name, nameservers..., modifiers...): Delegate the subdomain name
+// to its own zone: add its NS records and, for the nameservers that are in
+// this domain, their glue. A nameserver is a name, or an array of a name and
+// the addresses of its glue, like ['ns1.sub', '192.0.2.1', '2001:db8::1'].
+function DELEGATE(name) {
+    var fail = function(msg) {
+        throw new Error('DELEGATE(' + JSON.stringify(name) + '): ' + msg);
+    };
+    if (!_.isString(name) || name === '' || name === '@') {
+        fail('needs the name of the subdomain');
+    }
+
+    var nameservers = [];
+    var mods = [];
+    for (var i = 1; i < arguments.length; i++) {
+        var arg = arguments[i];
+        if (_.isString(arg)) {
+            nameservers.push({ name: arg, glue: [] });
+        } else if (_.isArray(arg)) {
+            if (arg.length < 2 || !_.isString(arg[0])) {
+                fail('a nameserver with glue is an array of its name and its addresses');
+            }
+            nameservers.push({ name: arg[0], glue: arg.slice(1) });
+        } else {
+            mods.push(arg);
+        }
+    }
+    if (nameservers.length === 0) {
+        fail('needs at least one nameserver');
+    }
+
+    return function(d) {
+        for (var i = 0; i < nameservers.length; i++) {
+            var ns = nameservers[i];
+            var fqdn = ns.name;
+            if (fqdn.charAt(fqdn.length - 1) !== '.') {
+                fqdn += '.' + d.name + '.';
+            }
+            NS.apply(null, [name, fqdn, { delegate: 'true' }].concat(mods))(d);
+
+            // The glue is in this domain, so its label is relative to it.
+            var suffix = '.' + d.name + '.';
+            if (ns.glue.length && fqdn.slice(-suffix.length) !== suffix) {
+                fail('nameserver ' + fqdn + ' is not in ' + d.name + ', so it can\'t have glue here');
+            }
+            var host = fqdn.slice(0, -suffix.length);
+            for (var j = 0; j < ns.glue.length; j++) {
+                var addr = ns.glue[j];
+                var type = addr.indexOf(':') !== -1 ? 'AAAA' : 'A';
+                // Nameservers that serve several subdomains have their glue once.
+                var exists = false;
+                for (var k = 0; k < d.records.length; k++) {
+                    var r = d.records[k];
+                    exists = exists || (r.type === type && r.name === host && r.target === addr);
+                }
+                if (!exists) {
+                    (type === 'A' ? A : AAAA).apply(null, [host, addr].concat(mods))(d);
+                }
+            }
+        }
+    };
+}
+
+// IMPORT_ZONE(file, modifiers...): Add the records of a BIND zone file to
+// the domain, except its SOA and the NS records at the apex, which the DNS
+// providers of the domain have their own of. Modifiers, such as TTL(),
+// apply to all of the records.
+function IMPORT_ZONE(file) {
+    var mods = [];
+    for (var i = 1; i < arguments.length; i++) {
+        mods.push(arguments[i]);
+    }
+    return function(d) {
+        var records = ZONE_RECORDS(file, d.name);
+        for (var i = 0; i < records.length; i++) {
+            var r = records[i];
+            if (r.type === 'NS' && r.name === '@') {
+                continue;
+            }
+            r.meta = r.meta || {};
+            for (var j = 0; j < mods.length; j++) {
+                if (_.isFunction(mods[j])) {
+                    mods[j](r);
+                } else {
+                    _.extend(r.meta, mods[j]);
+                }
+            }
+            d.records.push(r);
+        }
+    };
+}
+
 /**
  * @deprecated
  */
@@ -462,7 +1140,7 @@ function recordBuilder(type, opts) {
                     }
                     _.extend(record.meta, mod);
                 } else {
-                    throw 'ERROR: Unknown modifier type';
+                    throw new Error('ERROR: Unknown modifier type');
                 }
             }
         },
@@ -478,14 +1156,14 @@ function recordBuilder(type, opts) {
                     return item[0];
                 })
                 .join(', ');
-            throw type +
+            throw new Error(type +
                 ' record requires ' +
                 opts.args.length +
                 ' arguments (' +
                 argumentsList +
                 '). Only ' +
                 arguments.length +
-                ' were supplied';
+                ' were supplied');
             return;
         }
 
@@ -496,10 +1174,10 @@ function recordBuilder(type, opts) {
             if (argDefinition.length > 1) {
                 // run validator if supplied
                 if (!argDefinition[1](value)) {
-                    throw type +
+                    throw new Error(type +
                         ' record ' +
                         argDefinition[0] +
-                        ' argument validation failed';
+                        ' argument validation failed');
                 }
             }
             parsedArgs[argDefinition[0]] = value;
@@ -514,7 +1192,7 @@ function recordBuilder(type, opts) {
             var record = {
                 type: type,
                 meta: {},
-                ttl: d.defaultTTL,
+                ttl: defaultTTLOf(d, type),
             };
 
             opts.applyModifier(record, modifiers);
@@ -538,7 +1216,7 @@ function addRecord(d, type, name, target, mods) {
         type: type,
         name: name,
         target: target,
-        ttl: d.defaultTTL,
+        ttl: defaultTTLOf(d, type),
         priority: 0,
         meta: {},
     };
@@ -650,12 +1328,21 @@ var FRAME = recordBuilder('FRAME');
 // label: The DNS label for the primary SPF record. (default: '@')
 // raw: Where (which label) to store an unaltered version of the SPF settings.
 // ttl: The time for TTL, integer or string. (default: not defined, using DefaultTTL)
-// split: The template for additional records to be created (default: '_spf%d')
+// overflow: The template for additional records to be created, such as '_spf%d'. (default: no splitting)
 // flatten: A list of domains to be flattened.
+// maxLookups: Only flatten if the record needs more DNS lookups than this. (default: always flatten)
 
 function SPF_BUILDER(value) {
     if (!value.parts || value.parts.length < 2) {
-        throw 'SPF_BUILDER requires at least 2 elements';
+        throw new Error('SPF_BUILDER requires at least 2 elements');
+    }
+    if (!_.isUndefined(value.maxLookups)) {
+        if (!_.isNumber(value.maxLookups) || value.maxLookups < 1 || value.maxLookups % 1 != 0) {
+            throw new Error('SPF_BUILDER: maxLookups must be a positive integer');
+        }
+        if (!value.flatten || value.flatten.length == 0) {
+            throw new Error('SPF_BUILDER: maxLookups requires flatten');
+        }
     }
     if (!value.label) {
         value.label = '@';
@@ -671,6 +1358,9 @@ function SPF_BUILDER(value) {
     // If flattening is requested, generate a TXT record with the raw SPF settings.
     if (value.flatten && value.flatten.length > 0) {
         p.flatten = value.flatten.join(',');
+        if (value.maxLookups) {
+            p.max_lookups = String(value.maxLookups);
+        }
         if (value.ttl) {
             r.push(TXT(value.raw, rawspf, TTL(value.ttl)));
         } else {
@@ -695,51 +1385,478 @@ function SPF_BUILDER(value) {
 
 // CAA_BUILDER takes an object:
 // label: The DNS label for the CAA record. (default: '@')
-// iodef: The contact mail address. (optional)
+// iodef: The contact URI, such as 'mailto:security@example.com'. Email addresses get mailto:. (optional)
 // iodef_critical: Boolean if sending report is required/critical. If not supported, certificate should be refused. (optional)
 // issue: List of CAs which are allowed to issue certificates for the domain (creates one record for each).
 // issuewild: Allowed CAs which can issue wildcard certificates for this domain. (creates one record for each)
+// issue_critical, issuewild_critical: Booleans to set the critical flag of the issue and issuewild records. (optional)
+// A CA is a domain name, optionally followed by parameters ('ca.example; account=123'),
+// or 'none' alone to allow no CA.
 
 function CAA_BUILDER(value) {
-    if (!value.label) {
-        value.label = '@';
+    var fail = function(msg) {
+        throw new Error('CAA_BUILDER: ' + msg);
+    };
+    var cas = function(name, v) {
+        if (_.isUndefined(v)) {
+            return [];
+        }
+        var list = _.isArray(v) ? v : [v];
+        if (list.length == 1 && (list[0] == 'none' || list[0] == ';')) {
+            return [';'];
+        }
+        var seen = {};
+        for (var i = 0; i < list.length; i++) {
+            var ca = list[i];
+            if (!_.isString(ca) || !/^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)+(\s*;.*)?$/i.test(ca)) {
+                fail(name + ' must be domain names of CAs, or "none" alone, not ' + JSON.stringify(ca));
+            }
+            var domain = ca.split(';')[0].trim().toLowerCase();
+            if (seen[domain]) {
+                fail(name + ' lists ' + domain + ' more than once');
+            }
+            seen[domain] = true;
+        }
+        return list;
+    };
+
+    var label = value.label || '@';
+    var issue = cas('issue', value.issue);
+    var issuewild = cas('issuewild', value.issuewild);
+    if (issue.length == 0 && issuewild.length == 0) {
+        throw new Error('CAA_BUILDER requires at least one entry at issue or issuewild');
     }
 
-    if (value.issue && value.issue == 'none')
-        value.issue = [ ";" ];
-    if (value.issuewild && value.issuewild == 'none')
-        value.issuewild = [ ";" ];
+    var r = []; // The list of records to return.
+    var add = function(tag, v, critical) {
+        if (critical) {
+            r.push(CAA(label, tag, v, CAA_CRITICAL));
+        } else {
+            r.push(CAA(label, tag, v));
+        }
+    };
 
-    if ( (!value.issue && !value.issuewild) || ((value.issue && value.issue.length == 0) && (value.issuewild && value.issuewild.length == 0)) ) {
-        throw 'CAA_BUILDER requires at least one entry at issue or issuewild';
+    if (value.iodef) {
+        var iodef = value.iodef;
+        if (/^[^:\s]+@[^\s]+$/.test(iodef)) {
+            iodef = 'mailto:' + iodef;
+        }
+        if (!/^(mailto:[^\s@]+@[^\s@]+\.[^\s@]+|https?:\/\/[^\s]+)$/i.test(iodef)) {
+            fail('iodef must be a mailto:, http: or https: URI, not ' + JSON.stringify(value.iodef));
+        }
+        add('iodef', iodef, value.iodef_critical);
+    }
+    for (var i = 0; i < issue.length; i++) {
+        add('issue', issue[i], value.issue_critical);
+    }
+    for (var i = 0; i < issuewild.length; i++) {
+        add('issuewild', issuewild[i], value.issuewild_critical);
     }
 
-    r = []; // The list of records to return.
+    return r;
+}
 
-    if (value.iodef) {
-        if (value.iodef_critical) {
-            r.push(CAA(value.label, "iodef", value.iodef, CAA_CRITICAL));
-        } else {
-            r.push(CAA(value.label, "iodef", value.iodef));
+// TLSA_BUILDER takes an object:
+// label: The DNS label for the TLSA records, such as '_443._tcp.www'. (default: '_443._tcp')
+// file: PEM file with the certificate or public key, or a list of them (creates one record for each).
+//       Names starting with '.' are relative to the current file, as for require().
+// usage: The certificate usage. (default: 3, DANE-EE)
+// selector: 0 for the full certificate, 1 for the public key. (default: 1)
+// matchingtype: 0 for no hash, 1 for SHA-256, 2 for SHA-512. (default: 1)
+
+function TLSA_BUILDER(value) {
+    if (!value.file || value.file.length == 0) {
+        throw new Error('TLSA_BUILDER requires a file');
+    }
+    var label = value.label || '_443._tcp';
+    var usage = _.isUndefined(value.usage) ? 3 : value.usage;
+    var selector = _.isUndefined(value.selector) ? 1 : value.selector;
+    var matchingtype = _.isUndefined(value.matchingtype) ? 1 : value.matchingtype;
+    var files = _.isArray(value.file) ? value.file : [value.file];
+
+    var r = []; // The list of records to return.
+    for (var i = 0; i < files.length; i++) {
+        r.push(TLSA(label, usage, selector, matchingtype, TLSA_HASH(files[i], selector, matchingtype)));
+    }
+    return r;
+}
+
+// SSHFP_BUILDER takes an object:
+// label: The DNS label for the SSHFP records. (default: '@')
+// file: An OpenSSH public key file or known_hosts file, or a list of them (creates one record per key).
+//       Names starting with '.' are relative to the current file, as for require().
+// host: Only use the known_hosts entries of this host. (optional)
+// type: The fingerprint type, 1 for SHA-1 and 2 for SHA-256, or a list of them. (default: 2)
+
+function SSHFP_BUILDER(value) {
+    if (!value.file || value.file.length == 0) {
+        throw new Error('SSHFP_BUILDER requires a file');
+    }
+    var label = value.label || '@';
+    var files = _.isArray(value.file) ? value.file : [value.file];
+    var types = _.isUndefined(value.type) ? [2] : (_.isArray(value.type) ? value.type : [value.type]);
+
+    var r = []; // The list of records to return.
+    for (var i = 0; i < files.length; i++) {
+        for (var j = 0; j < types.length; j++) {
+            var fps = SSHFP_HASH(files[i], value.host || null, types[j]);
+            for (var k = 0; k < fps.length; k++) {
+                r.push(SSHFP(label, fps[k].algorithm, fps[k].type, fps[k].fingerprint));
+            }
         }
     }
+    return r;
+}
 
-    if (value.issue)
-        for (var i = 0, len = value.issue.length; i < len; i++)
-            r.push(CAA(value.label, "issue", value.issue[i]));
+// DMARC_BUILDER takes an object:
+// label: The DNS label the policy is for; the record is at _dmarc.<label>. (default: '@')
+// policy: 'none', 'quarantine' or 'reject' (p=).
+// subdomain_policy: The policy of subdomains (sp=). (optional)
+// alignment_dkim, alignment_spf: 'strict' or 'relaxed' (adkim=, aspf=). (optional)
+// pct: The percentage of messages the policy applies to, 0 to 100 (pct=). (optional)
+// rua, ruf: The URIs aggregate and failure reports are sent to, or a list of them.
+//           Email addresses get mailto:. (optional)
+// failure_options: '0', '1', 'd' and 's', as a string such as '1:d' or a list (fo=). (optional)
+// failure_format: The format of failure reports (rf=). (optional)
+// report_interval: The seconds between aggregate reports, or a duration such as '1d' (ri=). (optional)
+// ttl: The time for TTL, integer or string. (default: not defined, using DefaultTTL)
 
-    if (value.issuewild)
-        for (var i = 0, len = value.issuewild.length; i < len; i++)
-            r.push(CAA(value.label, "issuewild", value.issuewild[i]));
+function DMARC_BUILDER(value) {
+    var policies = ['none', 'quarantine', 'reject'];
+    var fail = function(msg) {
+        throw new Error('DMARC_BUILDER: ' + msg);
+    };
+    var list = function(v) {
+        return _.isArray(v) ? v : [v];
+    };
+    var alignment = function(name, v) {
+        var a = { strict: 's', s: 's', relaxed: 'r', r: 'r' }[v];
+        if (!a) {
+            fail(name + ' must be "strict" or "relaxed", not ' + JSON.stringify(v));
+        }
+        return a;
+    };
+    var uris = function(name, v) {
+        return list(v).map(function(u) {
+            if (!_.isString(u)) {
+                fail(name + ' must be URIs, not ' + JSON.stringify(u));
+            }
+            if (/^[^:\s]+@[^\s]+$/.test(u)) {
+                u = 'mailto:' + u;
+            }
+            // An optional size limit, such as !10m, may follow the URI.
+            if (!/^(mailto:[^\s,;!@]+@[^\s,;!@]+\.[^\s,;!@]+|https?:\/\/[^\s,;!]+)(![0-9]+[kmgt]?)?$/i.test(u)) {
+                fail(name + ' has a bad URI: ' + JSON.stringify(u));
+            }
+            return u;
+        }).join(',');
+    };
 
+    if (policies.indexOf(value.policy) === -1) {
+        fail('policy must be "none", "quarantine" or "reject", not ' + JSON.stringify(value.policy));
+    }
+    var tags = ['v=DMARC1', 'p=' + value.policy];
+    if (!_.isUndefined(value.subdomain_policy)) {
+        if (policies.indexOf(value.subdomain_policy) === -1) {
+            fail('subdomain_policy must be "none", "quarantine" or "reject", not ' + JSON.stringify(value.subdomain_policy));
+        }
+        tags.push('sp=' + value.subdomain_policy);
+    }
+    if (!_.isUndefined(value.alignment_dkim)) {
+        tags.push('adkim=' + alignment('alignment_dkim', value.alignment_dkim));
+    }
+    if (!_.isUndefined(value.alignment_spf)) {
+        tags.push('aspf=' + alignment('alignment_spf', value.alignment_spf));
+    }
+    if (!_.isUndefined(value.pct)) {
+        if (!_.isNumber(value.pct) || value.pct % 1 !== 0 || value.pct < 0 || value.pct > 100) {
+            fail('pct must be an integer from 0 to 100, not ' + JSON.stringify(value.pct));
+        }
+        tags.push('pct=' + value.pct);
+    }
+    if (!_.isUndefined(value.rua)) {
+        tags.push('rua=' + uris('rua', value.rua));
+    }
+    if (!_.isUndefined(value.ruf)) {
+        tags.push('ruf=' + uris('ruf', value.ruf));
+    }
+    if (!_.isUndefined(value.failure_options)) {
+        var fo = _.isString(value.failure_options) ? value.failure_options.split(':') : list(value.failure_options);
+        for (var i = 0; i < fo.length; i++) {
+            if (['0', '1', 'd', 's'].indexOf(String(fo[i])) === -1) {
+                fail('failure_options must be "0", "1", "d" or "s", not ' + JSON.stringify(fo[i]));
+            }
+        }
+        tags.push('fo=' + fo.join(':'));
+    }
+    if (!_.isUndefined(value.failure_format)) {
+        if (!/^[a-z][a-z0-9.-]*$/i.test(value.failure_format)) {
+            fail('failure_format must be a format name such as "afrf", not ' + JSON.stringify(value.failure_format));
+        }
+        tags.push('rf=' + value.failure_format);
+    }
+    if (!_.isUndefined(value.report_interval)) {
+        var ri = value.report_interval;
+        if (_.isString(ri)) {
+            ri = stringToDuration(ri);
+        }
+        if (!_.isNumber(ri) || ri % 1 !== 0 || ri <= 0) {
+            fail('report_interval must be a number of seconds, not ' + JSON.stringify(value.report_interval));
+        }
+        tags.push('ri=' + ri);
+    }
+
+    var label = '_dmarc';
+    if (value.label && value.label !== '@') {
+        label = '_dmarc.' + value.label;
+    }
+    if (value.ttl) {
+        return [TXT(label, tags.join('; '), TTL(value.ttl))];
+    }
+    return [TXT(label, tags.join('; '))];
+}
+
+// BIMI_BUILDER takes an object:
+// l: The https URL of the logo, an SVG file.
+// a: The https URL of the Verified Mark Certificate, a PEM file. (optional)
+// selector: The BIMI selector; the record is at <selector>._bimi. (default: 'default')
+// label: The DNS label of the domain the record is for. (default: '@')
+// ttl: The time for TTL, integer or string. (default: not defined, using DefaultTTL)
+
+function BIMI_BUILDER(value) {
+    var fail = function(msg) {
+        throw new Error('BIMI_BUILDER: ' + msg);
+    };
+    var url = function(name, v, ext) {
+        if (!_.isString(v) || !/^https:\/\/[^\/\s;]+\/[^\s;]*$/i.test(v)) {
+            fail(name + ' must be an https URL, not ' + JSON.stringify(v));
+        }
+        if (v.split('?')[0].toLowerCase().slice(-ext.length) !== ext) {
+            fail(name + ' must be the URL of a ' + ext + ' file, not ' + JSON.stringify(v));
+        }
+        return v;
+    };
+    var selector = value.selector || 'default';
+    if (!/^[a-z0-9]([a-z0-9-]*[a-z0-9])?$/i.test(selector)) {
+        fail('selector must be a DNS label, not ' + JSON.stringify(selector));
+    }
+    var tags = ['v=BIMI1', 'l=' + url('l', value.l, '.svg')];
+    if (!_.isUndefined(value.a)) {
+        tags.push('a=' + url('a', value.a, '.pem'));
+    }
+    var label = selector + '._bimi';
+    if (value.label && value.label !== '@') {
+        label += '.' + value.label;
+    }
+    if (value.ttl) {
+        return [TXT(label, tags.join('; '), TTL(value.ttl))];
+    }
+    return [TXT(label, tags.join('; '))];
+}
+
+// MTA_STS_BUILDER takes an object:
+// label: The DNS label of the domain the policy is for. (default: '@')
+// mode: 'enforce', 'testing' or 'none'.
+// mx: The MX host names the policy allows, or patterns such as '*.example.net'.
+// m365: true to allow the MX hosts of Microsoft 365, *.mail.protection.outlook.com. (optional)
+// max_age: How long senders cache the policy, in seconds or a duration such as '1w'. (default: '1w')
+// host: Where the mta-sts host that serves the policy points: a host name (creates a CNAME)
+//       or IP addresses (create A and AAAA records). (optional)
+// rua: The URIs TLS reports are sent to, or a list of them (creates the _smtp._tls TLSRPT record).
+//      Email addresses get mailto:. (optional)
+// id: The id of the policy. (default: a hash of the policy, which changes when the policy does)
+// ttl: The time for TTL, integer or string. (default: not defined, using DefaultTTL)
+
+function MTA_STS_BUILDER(value) {
+    var fail = function(msg) {
+        throw new Error('MTA_STS_BUILDER: ' + msg);
+    };
+    var list = function(v) {
+        if (_.isUndefined(v)) {
+            return [];
+        }
+        return _.isArray(v) ? v : [v];
+    };
+    var name = function(prefix) {
+        if (!value.label || value.label === '@') {
+            return prefix;
+        }
+        return prefix + '.' + value.label;
+    };
+    var r = []; // The list of records to return.
+    var add = function(builder, label, target) {
+        if (value.ttl) {
+            r.push(builder(label, target, TTL(value.ttl)));
+        } else {
+            r.push(builder(label, target));
+        }
+    };
+
+    var mx = list(value.mx).slice();
+    if (value.m365) {
+        mx.push('*.mail.protection.outlook.com');
+    }
+    var maxAge = _.isUndefined(value.max_age) ? '1w' : value.max_age;
+    if (_.isString(maxAge)) {
+        maxAge = stringToDuration(maxAge);
+    }
+    var policy = MTA_STS_POLICY(value.mode, mx, maxAge);
+    var id = _.isUndefined(value.id) ? policy.id : value.id;
+    if (!/^[a-z0-9]{1,32}$/i.test(id)) {
+        fail('id must be 1 to 32 letters and digits, not ' + JSON.stringify(id));
+    }
+    add(TXT, name('_mta-sts'), 'v=STSv1; id=' + id);
+
+    var rua = list(value.rua).map(function(u) {
+        if (/^[^:\s]+@[^\s]+$/.test(u)) {
+            u = 'mailto:' + u;
+        }
+        if (!/^(mailto:[^\s,;!@]+@[^\s,;!@]+\.[^\s,;!@]+|https:\/\/[^\s,;!]+)$/i.test(u)) {
+            fail('rua must be mailto: or https: URIs, not ' + JSON.stringify(u));
+        }
+        return u;
+    });
+    if (rua.length) {
+        add(TXT, name('_smtp._tls'), 'v=TLSRPTv1; rua=' + rua.join(','));
+    }
+
+    var hosts = list(value.host);
+    for (var i = 0; i < hosts.length; i++) {
+        var h = hosts[i];
+        if (/^[0-9.]+$/.test(h)) {
+            add(A, name('mta-sts'), h);
+        } else if (h.indexOf(':') !== -1) {
+            add(AAAA, name('mta-sts'), h);
+        } else if (hosts.length === 1) {
+            add(CNAME, name('mta-sts'), h);
+        } else {
+            fail('host must be one host name, or IP addresses');
+        }
+    }
     return r;
 }
 
-// Split a DKIM string if it is >254 bytes.
-function DKIM(arr) {
-    chunkSize = 255;
+// DKIM(selector, key, modifiers...): Add the TXT record selector._domainkey
+// of the DKIM public key, which is a PEM file, a DKIM record or the key
+// itself, or the name of a file that has one, in strings of at most 255
+// bytes. DKIM(string) only splits string into strings of at most 255 bytes,
+// for TXT().
+function DKIM(selector, key) {
+    if (arguments.length === 1) {
+        return splitDKIM(selector);
+    }
+    if (!_.isString(selector) || !/^[a-z0-9_-]+(\.[a-z0-9_-]+)*$/i.test(selector)) {
+        throw new Error('DKIM(' + JSON.stringify(selector) + '): the selector must be a name such as "mail" or "s1.example"');
+    }
+    var k = DKIM_KEY(key);
+    var args = [selector + '._domainkey', splitDKIM('v=DKIM1; k=' + k.k + '; p=' + k.p)];
+    for (var i = 2; i < arguments.length; i++) {
+        args.push(arguments[i]);
+    }
+    return TXT.apply(null, args);
+}
+
+// splitDKIM splits a string into strings of at most 255 bytes.
+function splitDKIM(arr) {
+    var chunkSize = 255;
     var R = [];
     for (var i = 0, len = arr.length; i < len; i += chunkSize)
         R.push(arr.slice(i, i + chunkSize));
     return R;
 }
+
+// assertions is the number of ASSERT_* that passed, for dnscontrol test.
+var assertions = 0;
+
+// canonicalJSON is JSON.stringify with the keys of objects sorted, so that
+// equal values give equal strings.
+function canonicalJSON(v) {
+    if (_.isArray(v)) {
+        return '[' + _.map(v, canonicalJSON).join(',') + ']';
+    }
+    if (_.isObject(v) && !_.isFunction(v)) {
+        return '{' + _.map(_.keys(v).sort(), function(k) {
+            return JSON.stringify(k) + ':' + canonicalJSON(v[k]);
+        }).join(',') + '}';
+    }
+    return JSON.stringify(v);
+}
+
+// ASSERT_EQUAL(got, want, message): Fail the test unless got and want are
+// equal values, compared as JSON.
+function ASSERT_EQUAL(got, want, message) {
+    var g = canonicalJSON(got);
+    var w = canonicalJSON(want);
+    if (g !== w) {
+        throw new Error('ASSERT_EQUAL' + (message ? '(' + message + ')' : '') +
+            ': got ' + g + ', want ' + w);
+    }
+    assertions++;
+}
+
+// recordString returns a line for the record r, with its type, name and
+// target, then its other settings.
+function recordString(r) {
+    var s = r.type + ' ' + r.name + ' ' + JSON.stringify(r.target);
+    _.each(_.keys(r).sort(), function(k) {
+        var v = r[k];
+        if (k === 'type' || k === 'name' || k === 'target' || !v || (_.isObject(v) && _.isEmpty(v))) {
+            return;
+        }
+        s += ' ' + k + '=' + canonicalJSON(v);
+    });
+    return s;
+}
+
+// ASSERT_RECORDS(got, want, domain): Fail the test unless the modifiers got,
+// such as the records returned by a function, add the same records as the
+// modifiers want, in any order, each as many times. They are added to the
+// domain, which is example.com by default.
+function ASSERT_RECORDS(got, want, domain) {
+    var records = function(m) {
+        var d = newDomain(domain || 'example.com', 'none');
+        processDargs(m, d);
+        return _.countBy(_.map(d.records, recordString));
+    };
+    var g = records(got);
+    var w = records(want);
+    // extra returns the records that a has more times than b.
+    var extra = function(a, b) {
+        var list = [];
+        _.each(_.keys(a).sort(), function(r) {
+            for (var i = b[r] || 0; i < a[r]; i++) {
+                list.push(r);
+            }
+        });
+        return list;
+    };
+    var missing = extra(w, g);
+    var unexpected = extra(g, w);
+    if (missing.length || unexpected.length) {
+        var msg = 'ASSERT_RECORDS: the records differ';
+        _.each(missing, function(r) {
+            msg += '\n  missing:    ' + r;
+        });
+        _.each(unexpected, function(r) {
+            msg += '\n  unexpected: ' + r;
+        });
+        throw new Error(msg);
+    }
+    assertions++;
+}
+
+// ASSERT_THROWS(fn, text): Fail the test unless calling fn throws an error
+// whose message contains text.
+function ASSERT_THROWS(fn, text) {
+    try {
+        fn();
+    } catch (e) {
+        var msg = String(e && e.message !== undefined ? e.message : e);
+        if (text && msg.indexOf(text) === -1) {
+            throw new Error('ASSERT_THROWS: got the error "' + msg + '", want one with "' + text + '"');
+        }
+        assertions++;
+        return;
+    }
+    throw new Error('ASSERT_THROWS: got no error' + (text ? ', want one with "' + text + '"' : ''));
+}
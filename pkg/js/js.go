@@ -1,15 +1,19 @@
 package js
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/StackExchange/dnscontrol/models"
 	"github.com/StackExchange/dnscontrol/pkg/printer"
 	"github.com/StackExchange/dnscontrol/pkg/transform"
+	"github.com/StackExchange/dnscontrol/providers/bind"
 	"github.com/pkg/errors"
 	"github.com/robertkrimen/otto"              // load underscore js into vm by default
 	_ "github.com/robertkrimen/otto/underscore" // required by otto
@@ -23,30 +27,47 @@ import (
 // far as require() is concerned, not the actual os.Getwd().
 var currentDirectory string
 
+// AllowedEnv are the environment variables that dnsconfig.js can read,
+// as properties of ENV. Other variables are kept from it, so that the
+// configuration only depends on those the user chose.
+var AllowedEnv []string
+
+// CLIVariables are the variables given with -v key=value, which
+// dnsconfig.js reads as the properties of CLI.
+var CLIVariables map[string]string
+
 // ExecuteJavascript accepts a javascript string and runs it, returning the resulting dnsConfig.
 func ExecuteJavascript(file string, devMode bool) (*models.DNSConfig, error) {
 	script, err := ioutil.ReadFile(file)
 	if err != nil {
 		return nil, errors.Errorf("Reading js file %s: %s", file, err)
 	}
+	return ExecuteJavascriptSource(file, script, devMode)
+}
 
-	// Record the directory path leading up to this file.
-	currentDirectory = filepath.Clean(filepath.Dir(file))
-
-	vm := otto.New()
-
-	vm.Set("require", require)
-	vm.Set("REV", reverse)
-
-	helperJs := GetHelpers(devMode)
-	// run helper script to prime vm and initialize variables
-	if _, err := vm.Run(helperJs); err != nil {
+// ExecuteJavascriptSource is like ExecuteJavascript, but runs script as if
+// it were the contents of file.
+func ExecuteJavascriptSource(file string, script []byte, devMode bool) (*models.DNSConfig, error) {
+	script, err := jsSource(file, script)
+	if err != nil {
+		return nil, err
+	}
+	vm, err := newVM(file, devMode)
+	if err != nil {
 		return nil, err
 	}
 
 	// run user script
-	if _, err := vm.Run(script); err != nil {
+	if err := run(vm, file, script); err != nil {
+		return nil, err
+	}
+
+	// Defaults that were pushed and never popped would have leaked into the
+	// domains after them.
+	if n, err := vm.Run(`defaultsStack.length`); err != nil {
 		return nil, err
+	} else if depth, _ := n.ToInteger(); depth != 0 {
+		return nil, errors.Errorf("%d PUSH_DEFAULTS() without POP_DEFAULTS()", depth)
 	}
 
 	// export conf as string and unmarshal
@@ -65,6 +86,43 @@ func ExecuteJavascript(file string, devMode bool) (*models.DNSConfig, error) {
 	return conf, nil
 }
 
+// newVM returns an interpreter with the builtins and helpers.js, ready to
+// run file.
+func newVM(file string, devMode bool) (*otto.Otto, error) {
+	// Record the directory path leading up to this file.
+	currentDirectory = filepath.Clean(filepath.Dir(file))
+	fetched = map[string]string{}
+
+	vm := otto.New()
+
+	vm.Set("require", require)
+	vm.Set("REV", reverse)
+	vm.Set("IP_ADD", ipAdd)
+	vm.Set("CIDR_HOSTS", cidrHosts)
+	vm.Set("IPV6_EXPAND", ipv6Expand)
+	vm.Set("OPENPGPKEY_NAME", openpgpkeyName)
+	vm.Set("SMIMEA_NAME", smimeaName)
+	vm.Set("TLSA_HASH", tlsaHash)
+	vm.Set("SSHFP_HASH", sshfpHash)
+	vm.Set("MTA_STS_POLICY", mtaSTSPolicy)
+	vm.Set("FETCH", fetch)
+	vm.Set("DKIM_KEY", dkimKey)
+	vm.Set("ZONE_RECORDS", zoneRecords)
+	if err := setEnv(vm); err != nil {
+		return nil, err
+	}
+	if err := setCLI(vm); err != nil {
+		return nil, err
+	}
+
+	helperJs := GetHelpers(devMode)
+	// run helper script to prime vm and initialize variables
+	if err := run(vm, "helpers.js", helperJs); err != nil {
+		return nil, err
+	}
+	return vm, nil
+}
+
 // GetHelpers returns the filename of helpers.js, or the esc'ed version.
 func GetHelpers(devMode bool) string {
 	return _escFSMustString(devMode, "/helpers.js")
@@ -84,6 +142,38 @@ func require(call otto.FunctionCall) otto.Value {
 		relFile = cleanFile
 	}
 
+	if !strings.ContainsAny(file, "*?[") {
+		return requireFile(call, file, relFile, cleanFile)
+	}
+
+	// A glob requires each file it matches, in lexical order, and returns
+	// an array of what they return.
+	matches, err := filepath.Glob(relFile)
+	if err != nil {
+		throw(call.Otto, fmt.Sprintf("require(%q): %s", file, err))
+	}
+	if len(matches) == 0 {
+		throw(call.Otto, fmt.Sprintf("require(%q): no files match", file))
+	}
+	values, err := call.Otto.Object("[]")
+	if err != nil {
+		throw(call.Otto, err.Error())
+	}
+	for _, m := range matches {
+		cleanMatch := m
+		if !strings.HasPrefix(file, ".") {
+			cleanMatch = filepath.Clean(filepath.Join(currentDirectory, m))
+		}
+		if _, err := values.Call("push", requireFile(call, m, m, cleanMatch)); err != nil {
+			throw(call.Otto, err.Error())
+		}
+	}
+	return values.Value()
+}
+
+// requireFile runs, or parses if it is JSON, the file relFile. Any
+// require() in it is relative to the directory of cleanFile.
+func requireFile(call otto.FunctionCall, file, relFile, cleanFile string) otto.Value {
 	// Record the old currentDirectory so that we can return there.
 	currentDirectoryOld := currentDirectory
 	// Record the directory path leading up to the file we're about to require.
@@ -102,10 +192,17 @@ func require(call otto.FunctionCall) otto.Value {
 	if strings.HasSuffix(filepath.Ext(relFile), "json") {
 		cmd := fmt.Sprintf(`JSON.parse(JSON.stringify(%s))`, string(data))
 		value, err = call.Otto.Run(cmd)
-	} else {
-		_, err = call.Otto.Run(string(data))
+	} else if data, err = jsSource(relFile, data); err == nil {
+		var s *otto.Script
+		if s, err = call.Otto.Compile(relFile, data); err == nil {
+			_, err = call.Otto.Run(s)
+		}
 	}
 
+	if e, ok := err.(*otto.Error); ok {
+		// Keep the stack of the file, which the new error adds its own to.
+		throw(call.Otto, strings.TrimPrefix(e.String(), "Error: "))
+	}
 	if err != nil {
 		throw(call.Otto, err.Error())
 	}
@@ -116,6 +213,91 @@ func require(call otto.FunctionCall) otto.Value {
 	return value
 }
 
+// setEnv sets ENV to an object with the variables of AllowedEnv that
+// are set.
+func setEnv(vm *otto.Otto) error {
+	env, err := vm.Object("({})")
+	if err != nil {
+		return err
+	}
+	for _, name := range AllowedEnv {
+		if v, ok := os.LookupEnv(name); ok {
+			if err := env.Set(name, v); err != nil {
+				return err
+			}
+		}
+	}
+	return vm.Set("ENV", env)
+}
+
+// setCLI sets CLI to an object with CLIVariables. CLI_DEFAULTS() adds
+// the variables that weren't given.
+func setCLI(vm *otto.Otto) error {
+	cli, err := vm.Object("({})")
+	if err != nil {
+		return err
+	}
+	for k, v := range CLIVariables {
+		if err := cli.Set(k, v); err != nil {
+			return err
+		}
+	}
+	return vm.Set("CLI", cli)
+}
+
+// run compiles and runs src, named file in the stack traces of errors.
+func run(vm *otto.Otto, file string, src interface{}) error {
+	s, err := vm.Compile(file, src)
+	if err != nil {
+		return err
+	}
+	_, err = vm.Run(s)
+	return jsError(err)
+}
+
+// jsError returns err, with the place it was thrown and a stack trace
+// if it is a javascript error:
+//
+//	dnsconfig.js:12:1: A("@", "1.2.3") is not an IP address
+//	    at A (helpers.js:402:15)
+//	    at dnsconfig.js:12:1
+//
+// The place is the innermost frame outside of helpers.js, so that errors
+// of builtins point at the line that called them.
+func jsError(err error) error {
+	e, ok := err.(*otto.Error)
+	if !ok {
+		return err
+	}
+	lines := strings.Split(strings.TrimSuffix(e.String(), "\n"), "\n")
+	msg := strings.TrimPrefix(lines[0], "Error: ")
+	var stack []string
+	seen := map[string]bool{}
+	where := ""
+	for _, l := range lines[1:] {
+		if l == "" {
+			continue
+		}
+		if !strings.HasPrefix(l, "    at ") {
+			msg += "\n" + l
+			continue
+		}
+		// Skip the Go functions, and the frames that a require() repeats.
+		if seen[l] || strings.Contains(l, ".go:") {
+			continue
+		}
+		seen[l] = true
+		stack = append(stack, l)
+		if where == "" && !strings.Contains(l, "helpers.js:") {
+			where = strings.TrimSuffix(l[strings.LastIndexAny(l, " (")+1:], ")")
+		}
+	}
+	if where != "" {
+		msg = where + ": " + msg
+	}
+	return errors.New(strings.Join(append([]string{msg}, stack...), "\n"))
+}
+
 func throw(vm *otto.Otto, str string) {
 	panic(vm.MakeCustomError("Error", str))
 }
@@ -132,3 +314,200 @@ func reverse(call otto.FunctionCall) otto.Value {
 	v, _ := otto.ToValue(rev)
 	return v
 }
+
+func ipAdd(call otto.FunctionCall) otto.Value {
+	if len(call.ArgumentList) != 2 {
+		throw(call.Otto, "IP_ADD takes exactly two arguments")
+	}
+	n, err := call.Argument(1).ToInteger()
+	if err != nil {
+		throw(call.Otto, "IP_ADD: "+err.Error())
+	}
+	ip, err := transform.IPAdd(call.Argument(0).String(), n)
+	if err != nil {
+		throw(call.Otto, "IP_ADD: "+err.Error())
+	}
+	v, _ := otto.ToValue(ip)
+	return v
+}
+
+func cidrHosts(call otto.FunctionCall) otto.Value {
+	if len(call.ArgumentList) != 1 {
+		throw(call.Otto, "CIDR_HOSTS takes exactly one argument")
+	}
+	hosts, err := transform.CIDRHosts(call.Argument(0).String())
+	if err != nil {
+		throw(call.Otto, "CIDR_HOSTS: "+err.Error())
+	}
+	b, _ := json.Marshal(hosts)
+	v, err := call.Otto.Run(fmt.Sprintf("JSON.parse(%q)", b))
+	if err != nil {
+		throw(call.Otto, err.Error())
+	}
+	return v
+}
+
+func ipv6Expand(call otto.FunctionCall) otto.Value {
+	if len(call.ArgumentList) != 1 {
+		throw(call.Otto, "IPV6_EXPAND takes exactly one argument")
+	}
+	ip, err := transform.IPv6Expand(call.Argument(0).String())
+	if err != nil {
+		throw(call.Otto, "IPV6_EXPAND: "+err.Error())
+	}
+	v, _ := otto.ToValue(ip)
+	return v
+}
+
+func openpgpkeyName(call otto.FunctionCall) otto.Value {
+	if len(call.ArgumentList) != 1 {
+		throw(call.Otto, "OPENPGPKEY_NAME takes exactly one argument")
+	}
+	v, _ := otto.ToValue(transform.LocalPartOwnerName(call.Argument(0).String(), "_openpgpkey"))
+	return v
+}
+
+// readUserFile reads a file named in dnsconfig.js. Names starting with
+// "." are relative to the current file, as for require().
+func readUserFile(call otto.FunctionCall, file string) []byte {
+	if strings.HasPrefix(file, ".") {
+		file = filepath.Join(currentDirectory, file)
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		throw(call.Otto, err.Error())
+	}
+	return data
+}
+
+func tlsaHash(call otto.FunctionCall) otto.Value {
+	if len(call.ArgumentList) != 3 {
+		throw(call.Otto, "TLSA_HASH takes exactly three arguments")
+	}
+	file := call.Argument(0).String()
+	data := readUserFile(call, file)
+	selector, _ := call.Argument(1).ToInteger()
+	matchingType, _ := call.Argument(2).ToInteger()
+	hash, err := transform.DANEAssociation(data, uint8(selector), uint8(matchingType))
+	if err != nil {
+		throw(call.Otto, fmt.Sprintf("%s: %s", file, err))
+	}
+	v, _ := otto.ToValue(hash)
+	return v
+}
+
+// sshfpHash returns a list of {algorithm, type, fingerprint} objects.
+func sshfpHash(call otto.FunctionCall) otto.Value {
+	if len(call.ArgumentList) != 3 {
+		throw(call.Otto, "SSHFP_HASH takes exactly three arguments")
+	}
+	file := call.Argument(0).String()
+	data := readUserFile(call, file)
+	host := ""
+	if h := call.Argument(1); h.IsDefined() && !h.IsNull() {
+		host = h.String()
+	}
+	fpType, _ := call.Argument(2).ToInteger()
+	fps, err := transform.SSHFingerprints(data, host, uint8(fpType))
+	if err != nil {
+		throw(call.Otto, fmt.Sprintf("%s: %s", file, err))
+	}
+	b, _ := json.Marshal(fps)
+	v, err := call.Otto.Run(fmt.Sprintf("JSON.parse(%q)", b))
+	if err != nil {
+		throw(call.Otto, err.Error())
+	}
+	return v
+}
+
+// mtaSTSPolicy returns a {policy, id} object.
+func mtaSTSPolicy(call otto.FunctionCall) otto.Value {
+	if len(call.ArgumentList) != 3 {
+		throw(call.Otto, "MTA_STS_POLICY takes exactly three arguments")
+	}
+	mode := call.Argument(0).String()
+	var mx []string
+	if arg := call.Argument(1); arg.IsDefined() && !arg.IsNull() {
+		exported, _ := arg.Export()
+		switch v := exported.(type) {
+		case []string:
+			mx = v
+		case []interface{}:
+			for _, m := range v {
+				mx = append(mx, fmt.Sprint(m))
+			}
+		default:
+			throw(call.Otto, "MTA_STS_POLICY: mx must be a list of host names")
+		}
+	}
+	maxAge, _ := call.Argument(2).ToInteger()
+	if maxAge < 0 {
+		throw(call.Otto, "MTA_STS_POLICY: max_age must not be negative")
+	}
+	policy, id, err := transform.MTASTSPolicy(mode, mx, uint32(maxAge))
+	if err != nil {
+		throw(call.Otto, "MTA_STS_POLICY: "+err.Error())
+	}
+	b, _ := json.Marshal(map[string]string{"policy": policy, "id": id})
+	v, err := call.Otto.Run(fmt.Sprintf("JSON.parse(%q)", b))
+	if err != nil {
+		throw(call.Otto, err.Error())
+	}
+	return v
+}
+
+// reDKIMKey matches the base64 of a DKIM key, which is at least this long.
+var reDKIMKey = regexp.MustCompile(`^[A-Za-z0-9+/=\s]{40,}$`)
+
+// dkimKey returns the {k, p} tags of the DKIM record of a public key,
+// given as text or as the name of a file that has it.
+func dkimKey(call otto.FunctionCall) otto.Value {
+	if len(call.ArgumentList) != 1 {
+		throw(call.Otto, "DKIM_KEY takes exactly one argument")
+	}
+	arg := call.Argument(0).String()
+	data := arg
+	if !strings.Contains(arg, "-----BEGIN") && !strings.Contains(arg, "p=") && !reDKIMKey.MatchString(arg) {
+		data = string(readUserFile(call, arg))
+	}
+	k, p, err := transform.DKIMPublicKey(data)
+	if err != nil {
+		throw(call.Otto, fmt.Sprintf("DKIM_KEY(%q): %s", arg, err))
+	}
+	b, _ := json.Marshal(map[string]string{"k": k, "p": p})
+	v, err := call.Otto.Run(fmt.Sprintf("JSON.parse(%q)", b))
+	if err != nil {
+		throw(call.Otto, err.Error())
+	}
+	return v
+}
+
+// zoneRecords returns the records of a BIND zone file of origin, except
+// the SOA, as record objects like those of A() or MX().
+func zoneRecords(call otto.FunctionCall) otto.Value {
+	if len(call.ArgumentList) != 2 {
+		throw(call.Otto, "ZONE_RECORDS takes exactly two arguments")
+	}
+	file := call.Argument(0).String()
+	origin := strings.TrimSuffix(call.Argument(1).String(), ".")
+	data := readUserFile(call, file)
+	recs, err := bind.ReadZone(bytes.NewReader(data), origin, file)
+	if err != nil {
+		throw(call.Otto, err.Error())
+	}
+	b, _ := json.Marshal(recs)
+	// The zone may have any text, which only JSON.parse reads as it is.
+	v, err := call.Otto.Call("JSON.parse", nil, string(b))
+	if err != nil {
+		throw(call.Otto, err.Error())
+	}
+	return v
+}
+
+func smimeaName(call otto.FunctionCall) otto.Value {
+	if len(call.ArgumentList) != 1 {
+		throw(call.Otto, "SMIMEA_NAME takes exactly one argument")
+	}
+	v, _ := otto.ToValue(transform.LocalPartOwnerName(call.Argument(0).String(), "_smimecert"))
+	return v
+}
@@ -0,0 +1,3 @@
+D("foo.com","none",
+    DNAME("sub","example.com.")
+);
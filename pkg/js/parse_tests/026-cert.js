@@ -0,0 +1,3 @@
+D("foo.com","none",
+    CERT("smime",1,1234,8,"MIIBCgKCAQEA")
+);
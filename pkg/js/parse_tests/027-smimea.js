@@ -0,0 +1,3 @@
+D("foo.com","none",
+    SMIMEA(SMIMEA_NAME("hugh@foo.com"),3,0,1,"abcdef0123")
+);
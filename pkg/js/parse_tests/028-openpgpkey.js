@@ -0,0 +1,3 @@
+D("foo.com","none",
+    OPENPGPKEY(OPENPGPKEY_NAME("hugh@foo.com"),"mQENBFVHm5sBCADK")
+);
@@ -0,0 +1,3 @@
+var CF = NewDnsProvider("Cloudflare", "CLOUDFLAREAPI");
+var BIND = NewDnsProvider("bind", "BIND");
+D("foo.com","none",DnsProvider(BIND),REPLICATE_FROM(CF));
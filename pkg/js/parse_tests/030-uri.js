@@ -0,0 +1,3 @@
+D("foo.com","none",
+    URI("_ftp._tcp",10,1,"ftp://ftp.foo.com/public")
+);
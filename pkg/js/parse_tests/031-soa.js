@@ -0,0 +1,3 @@
+D("foo.com","none",
+    SOA("@","ns1.foo.com.","hostmaster@foo.com",3600,600,604800,1440,{soa_serial: "unixtime"})
+);
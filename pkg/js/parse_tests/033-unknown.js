@@ -0,0 +1,3 @@
+D("foo.com","none",
+    UNKNOWN("@",65280,"0a0B 0c")
+);
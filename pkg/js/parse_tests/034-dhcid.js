@@ -0,0 +1,3 @@
+D("foo.com","none",
+    DHCID("host1","AAIBY2/AuCccgoJbsaxcQc9TUapptP69lOjxfNuVAA2kjEA=")
+);
@@ -0,0 +1,4 @@
+TTL_POLICY({ns_ttl: "2d", negative_ttl: 300});
+D("foo.com","none",
+    NS("sub","ns1.example.net.")
+);
@@ -0,0 +1,4 @@
+D("foo.com","none",
+    A("new","1.2.3.4",PRIORITY_HINT("first")),
+    CNAME("www","new",PRIORITY_HINT("last"))
+);
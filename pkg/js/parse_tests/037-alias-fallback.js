@@ -0,0 +1,4 @@
+D("foo.com","none",
+    ALIAS("@","lb.example.net.",ALIAS_FALLBACK()),
+    ALIAS("www","lb.example.net.",ALIAS_FALLBACK("cached"))
+);
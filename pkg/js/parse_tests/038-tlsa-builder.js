@@ -0,0 +1,4 @@
+D("foo.com","none",
+    TLSA_BUILDER({file: "./038-tlsa-builder.pem"}),
+    TLSA_BUILDER({label: "_25._tcp.mail", file: ["./038-tlsa-builder.pem"], selector: 0, usage: 1})
+);
@@ -0,0 +1,4 @@
+D("foo.com","none",
+    SSHFP_BUILDER({label: "host", file: "./039-sshfp-builder.pub", type: [1, 2]}),
+    SSHFP_BUILDER({label: "other", file: ["./039-sshfp-builder.known_hosts"], host: "other.example.com"})
+);
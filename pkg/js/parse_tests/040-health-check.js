@@ -0,0 +1,4 @@
+D("foo.com","none",
+    A("www","192.0.2.1",HEALTH_CHECK("tcp:443")),
+    A("www","192.0.2.2",HEALTH_CHECK("http:80/healthz", "3s"))
+);
@@ -0,0 +1,4 @@
+D("foo.com","none",
+    RP("@","hostmaster@foo.com","contact"),
+    RP("lab","noc.foo.com.",".")
+);
@@ -0,0 +1,4 @@
+D("foo.com","none",
+    PENDING_VERIFICATION("google", "google-site-verification=abc123"),
+    PENDING_VERIFICATION("github", "0123456789", "_github-challenge-acme", TTL(300))
+);
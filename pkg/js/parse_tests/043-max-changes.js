@@ -0,0 +1 @@
+D("foo.com", "none", MAX_CHANGES(50), MAX_DELETES(5));
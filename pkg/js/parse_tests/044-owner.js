@@ -0,0 +1 @@
+D("foo.com","none",OWNER("team-a"),A("@","1.2.3.4"));
@@ -0,0 +1 @@
+D("foo.com","none",REGISTRAR_LOCK("on"),A("@","1.2.3.4"));
@@ -0,0 +1,2 @@
+var ADMIN = {first_name: "Ann", email: "hostmaster@foo.com"};
+D("foo.com","none",CONTACTS({admin: ADMIN, tech: ADMIN}));
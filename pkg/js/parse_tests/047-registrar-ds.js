@@ -0,0 +1 @@
+D("foo.com","none",REGISTRAR_DS(2371, 13, 2, "1F987CC6583E92DF0890718C42B0A7D6F5EE0E9F6AC95E1E0B5DB5A2A1E18BAE"));
@@ -0,0 +1 @@
+D("foo.com","none",REGISTRAR_DS_AUTO);
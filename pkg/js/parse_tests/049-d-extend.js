@@ -0,0 +1,4 @@
+D("foo.com","none",A("@","1.2.3.4"));
+D("bar.com","none",A("@","1.2.3.4"));
+D_EXTEND("foo.com",A("www","1.2.3.5"),{"team":"web"});
+D_EXTEND("foo.com",[CNAME("mail","mx.bar.com.")]);
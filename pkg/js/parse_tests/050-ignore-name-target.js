@@ -0,0 +1,6 @@
+D("foo.com","none",
+  IGNORE_NAME("@", "TXT,MX"),
+  IGNORE_NAME("_acme-challenge**", ["TXT", "CNAME"]),
+  IGNORE_NAME("legacy"),
+  IGNORE_TARGET("**.acm-validations.aws.", "CNAME")
+);
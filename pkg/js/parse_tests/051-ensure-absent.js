@@ -0,0 +1,4 @@
+D("foo.com","none",NO_PURGE,
+  A("@","1.2.3.4"),
+  ENSURE_ABSENT(A("foo","1.2.3.5"),[CNAME("old","bar.com.")])
+);
@@ -0,0 +1,14 @@
+D("foo.com","none",
+  DMARC_BUILDER({
+    policy: "reject",
+    subdomain_policy: "quarantine",
+    alignment_dkim: "strict",
+    alignment_spf: "r",
+    pct: 50,
+    rua: ["dmarc@foo.com", "mailto:reports@example.net!10m"],
+    ruf: "https://dmarc.example.net/ruf",
+    failure_options: "1:d",
+    report_interval: "1d"
+  }),
+  DMARC_BUILDER({label: "mail", policy: "none", ttl: 300})
+);
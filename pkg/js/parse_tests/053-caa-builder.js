@@ -0,0 +1,9 @@
+D("foo.com","none",
+  CAA_BUILDER({
+    iodef: "security@foo.com",
+    issue: ["letsencrypt.org", "digicert.com; account=123"],
+    issuewild: "none",
+    issue_critical: true
+  }),
+  CAA_BUILDER({label: "www", issue: "letsencrypt.org"})
+);
@@ -0,0 +1,9 @@
+D("foo.com","none",
+  MTA_STS_BUILDER({
+    mode: "enforce",
+    mx: ["mail.foo.com", "*.mx.example.net"],
+    rua: "tlsrpt@foo.com",
+    host: "sts.example.net."
+  }),
+  MTA_STS_BUILDER({label: "sub", mode: "testing", m365: true, max_age: 86400, host: ["10.1.2.3", "2001:db8::1"], id: "v1", ttl: 300})
+);
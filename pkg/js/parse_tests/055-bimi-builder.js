@@ -0,0 +1,4 @@
+D("foo.com","none",
+  BIMI_BUILDER({l: "https://foo.com/bimi/logo.svg", a: "https://foo.com/bimi/vmc.pem"}),
+  BIMI_BUILDER({selector: "brand2", label: "news", l: "https://cdn.foo.com/Logo.SVG", ttl: 300})
+);
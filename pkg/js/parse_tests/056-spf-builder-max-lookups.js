@@ -0,0 +1,7 @@
+D("foo.com","none",
+  SPF_BUILDER({
+    parts: ["v=spf1", "include:_spf.google.com", "include:mailgun.org", "~all"],
+    flatten: ["*"],
+    maxLookups: 10
+  })
+);
@@ -0,0 +1,6 @@
+var REG = NewRegistrar("none", "NONE");
+var files = require('./globImports/domains/*.js');
+
+D_EXTEND("bar.com",
+    TXT("@", "files=" + files.length)
+);
@@ -0,0 +1,6 @@
+D(REV("192.0.2.0/24"), "none",
+  CLASSLESS_DELEGATE("192.0.2.128/30", "ns1.customer.example.", "ns2.customer.example.", TTL(3600))
+);
+D(REV("192.0.2.128/30"), "none",
+  PTR("192.0.2.129", "www.customer.example.")
+);
@@ -0,0 +1,10 @@
+DEFAULTS(DefaultTTL(3600, "MX"));
+D("foo.com", "none",
+  DefaultTTL("1h"),
+  DefaultTTL(300, ["txt", "CNAME"]),
+  A("@", "1.2.3.4"),
+  MX("@", 10, "mx.foo.com."),
+  TXT("@", "v=spf1 -all"),
+  TXT("_dmarc", "v=DMARC1; p=reject", TTL(60)),
+  CNAME("www", "foo.com.")
+);
@@ -0,0 +1,14 @@
+TEMPLATE("office365",
+  MX("@", 0, "${tenant}.mail.protection.outlook.com."),
+  TXT("@", "MS=${verification}"),
+  CNAME("autodiscover", "autodiscover.outlook.com."),
+  CNAME("selector1._domainkey", "selector1-${tenant}._domainkey.${org}.onmicrosoft.com."),
+  TXT("_dmarc", "v=DMARC1; p=reject; rua=mailto:dmarc@${domain}")
+);
+D("foo.com", "none",
+  A("@", "1.2.3.4"),
+  APPLY_TEMPLATE("office365", {tenant: "foo-com", org: "fooinc", verification: "ms123"})
+);
+D("bar.com", "none",
+  APPLY_TEMPLATE("office365", {tenant: "bar-com", org: "fooinc", verification: "ms456"})
+);
@@ -0,0 +1,9 @@
+D("foo.com!internal", "none",
+  A("www", "10.1.2.3")
+);
+D("foo.com", "none",
+  A("www", "1.2.3.4")
+);
+D_EXTEND("foo.com!internal",
+  A("intranet", "10.1.2.4")
+);
@@ -0,0 +1,4 @@
+D("foo.com", "none",
+  A("www", "1.2.3.4", NOTE("the web server")),
+  TXT("@", "v=spf1 -all")
+);
@@ -0,0 +1,6 @@
+D("foo.com", "none",
+  A("www", "1.2.3.4", ROUTING({set: "blue", weight: 90})),
+  A("www", "1.2.3.5", ROUTING({set: "green", weight: 10, health_check: "abc"})),
+  CNAME("eu", "eu.example.net.", ROUTING({set: "europe", geo: "continent:EU"})),
+  A("api", "1.2.3.6", ROUTING({set: "main", failover: "primary"}))
+);
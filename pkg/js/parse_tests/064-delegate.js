@@ -0,0 +1,4 @@
+D("foo.com", "none",
+  DELEGATE("sub", ["ns1.sub", "192.0.2.1", "2001:db8::1"], ["ns2.sub.foo.com.", "192.0.2.2"], TTL(3600)),
+  DELEGATE("other", ["ns1.sub", "192.0.2.1"], "ns.example.net.")
+);
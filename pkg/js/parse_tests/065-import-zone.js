@@ -0,0 +1,4 @@
+D("foo.com", "none",
+  IMPORT_ZONE("./065-import-zone.zone", NOTE("legacy")),
+  A("new", "1.2.3.6")
+);
@@ -0,0 +1,4 @@
+D("foo.com", "none",
+  DKIM("mail", "./066-dkim.pub"),
+  DKIM("s1", "v=DKIM1; k=ed25519; p=dsebMn/rxC4fyRDDpjZe5D+WiRZajtHuPsSqH+53ATA=", TTL(3600))
+);
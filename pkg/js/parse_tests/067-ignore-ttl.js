@@ -0,0 +1,6 @@
+D("foo.com", "none", IGNORE_TTL,
+  A("www", "1.2.3.4")
+);
+D("foo.net", "none",
+  A("www", "1.2.3.4", IGNORE_TTL)
+);
@@ -0,0 +1,7 @@
+var hosts = CIDR_HOSTS("192.0.2.0/30");
+D("foo.com", "none",
+  A("web1", IP_ADD("192.0.2.10", 1)),
+  AAAA("web1", IP_ADD("2001:db8::ff", 1)),
+  TXT("expanded", IPV6_EXPAND("2001:db8::1")),
+  _.map(hosts, function(h, i) { return A("host" + (i + 1), h); })
+);
@@ -0,0 +1,23 @@
+DEFAULTS(DefaultTTL(3600));
+
+WITH_DEFAULTS(DefaultTTL(300), {"cloudflare_proxy_default": "on"}, function() {
+  D("foo.com", "none",
+    A("@", "1.2.3.4")
+  );
+  WITH_DEFAULTS(DefaultTTL(60), function() {
+    D("bar.com", "none",
+      A("@", "1.2.3.4")
+    );
+  });
+});
+
+PUSH_DEFAULTS({"cloudflare_proxy_default": "off"});
+DEFAULTS();
+D("baz.com", "none",
+  A("@", "1.2.3.4")
+);
+POP_DEFAULTS();
+
+D("qux.com", "none",
+  A("@", "1.2.3.4")
+);
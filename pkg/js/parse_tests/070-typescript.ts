@@ -0,0 +1,24 @@
+interface Host {
+  name: string;
+  ip: string;
+  ttl?: number;
+}
+
+type Hosts = Host[];
+
+declare var HOSTS: Hosts;
+
+require("./typescriptImports/hosts.ts");
+
+function records<T extends Host>(hosts: T[], ttl: number): DomainModifier[] {
+  const out: DomainModifier[] = [];
+  for (let i: number = 0; i < hosts.length; i++) {
+    const h = hosts[i]!;
+    out.push(A(h.name, h.ip, TTL(h.ttl || ttl)));
+  }
+  return out;
+}
+
+D("foo.com", "none" as string,
+  records<Host>(HOSTS, 600)
+);
@@ -0,0 +1 @@
+var ip = "1.2.3.4";
@@ -0,0 +1,5 @@
+require('../common.js');
+
+D("bar.com", REG,
+    A("@", ip)
+);
@@ -0,0 +1,3 @@
+D("foo.com", REG,
+    CNAME("www", "bar.com.")
+);
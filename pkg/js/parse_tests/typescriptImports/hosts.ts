@@ -0,0 +1,4 @@
+var HOSTS: Array<{ name: string; ip: string; ttl?: number }> = [
+  { name: "@", ip: "1.2.3.4" },
+  { name: "www", ip: "1.2.3.5", ttl: 60 }
+];
@@ -192,103 +192,273 @@ var _escData = map[string]*_escFile{
 
 	"/helpers.js": {
 		local:   "pkg/js/helpers.js",
-		size:    21786,
+		size:    62927,
 		modtime: 0,
 		compressed: `
-H4sIAAAAAAAC/+w8a3PbOJLf/Ss6qduhmDD0I5PsljzaW40fs671qyRlNns+nQoWIQkTCuQBoBXvjPPb
-r/AiAT5kTWpn9svlQyyCjX6h0d0AGgwKjoELRuYiON7be0AM5hldwAB+3gMAYHhJuGCI8T7cTSPVllA+
-y1n2QBLsNWdrRGijYUbRGpvWJ0MiwQtUpGLIlhwGcDc93ttbFHQuSEaBUCIISsk/cS80THgcdXG1hbNW
-7p6ONZMNVp4cZq7xZmRp9aQgEYjHHEewxgJZ9sgCerI1dDiUzzAYQHA1vP4wvAw0sSf1v9QAw0spEUic
-fagw9x38ffW/ZVQqIa4Ej/OCr3oML8NjM1CiYFRhaohwSvmt0cqzQmQLTXUgmc/uf8JzEcA330BA8tk8
-ow+YcZJRHgChXn/5Tz7HPhwMYJGxNRIzIXot78O6YhKef41ivJHXukl4/pxuKN6cKrswainVG5bmr3pW
-IjpsNa2xX/2MPKX04ecnF36esaRpureV5brgxkInk8s+HEQeJxyzh4alkyXNGE5mKbrHqW/wruw5y+aY
-81PElry3jswEsYLv78txA4zmK1hnCVkQzCJpJEQA4YDiOC7hDMY+zFGaSoANESuDzwIhxtBj3xKVKigY
-Jw84fbQQ2tbk0LIlVmSoyJT2EiRQaaOzmPBzQ7G3Dj3z6xkZjE0BTjkuOw0lB7UeUsSetLqflDm7r+Q/
-X0V3P01LLR2XcE9ttG6ULDVisxh/FpgmhstYihbB2ufW8SArlm0g+PtwdH1x/UPfUC4HQ3uYgvIizzMm
-cNKHAF577NvpXGsOQNt8s4NhTM8TLdzT3t7+Ppzq+VFNjz6cMIwEBgSn12ODMIYPHINYYcgRQ2ssMOOA
-uLV3QDSR7PO4MsLTromnXIGWeLBlmmo2y2EkMICDYyDwnevX4xTTpVgdA3n92h0Qb3gd+DtSH+inJpkj
-TQaxZbHGVHQSkfBrGFSAd2R63M7CupWqtCnt4pxwGhOa4M83C6WQEF4MBvDmMGxYj3wLryGQUzbB8xQx
-LIeAyVFCFDI6x15kcuhYJ+oy1GRDwSgejq2pnJ0PP1xOxmC8MQcEHAvIFnZIKlWAyADlefqofqQpLApR
-MGxjdSzxnUkPpByLyCrkG5KmME8xYoDoI+QMP5Cs4PCA0gJzSdA1MtOrzCeaMb/Lip4dXtfMlDLccQ79
-WTSZXPYewj6MsVCzZDK5VET1HNKzxGFbgzvhWXqWsWCELnsPnmd5gIHK4ehykp0WDCnf+OBZkQlkFnmP
-uf1ZLEQKA3g4bgsULZidSbpGYr7CUo8Psfrd2/+f3n8nr8PeHV+vkg19nP5n+B/7hhkpRtljALRI06bV
-PliTpZkAJMeUJJAY6oYdz2wLSgQMIOBBg8rd0dQlYCCrl176AQPpuTi+oKLsf2hHUQpbqNSE9+EwgnUf
-3h9EsOrD2/cHBzYZKe6CJJjCAIp4Ba/g6NuyeWOaE3gFfyxbqdP69qBsfnSb378zHMCrARR3Uoapl9g8
-lJOvTBU8Q7MTzxqcatMu25klbt/fyOoSb+rEVWbTaXxr9AmfDIfnKVr21OSuZWaVQavp41m1nlBzhBYp
-WsIvA+0dXDL7+3AyHM5ORheTi5PhpYxqRJA5SmUzyG5queLCKOupeDqE776DP4bHWv1Onv3SZqPXaI1f
-RnAQSgjKT7KCKm94AGuMKIcko4EAuQzLmIlsWHs1J8OL3c5yWljsBonsjtLUHc5Gzm+6tyT8FrHK+Qua
-4AWhOAlcZZYg8Obw14ywk9XeSTakWRtctYEYajZJHpmRuzKZDo/jOFTjMISBefd9QVIpWTAMjO6Hw+Eu
-GIbDNiTDYYXn8mI41ogEYksstiCToC3YZLNFN3r3duagBItTL2a6MJe9mtjLV0FkNC1zhz7c3QWSQhBB
-NWGnEdwFklIQaS+KBB69eztMCeKTxxzr94ojv59ZMQiGKJfLt345wGAmWqTIRmU6yltmnso+VObDnZzS
-AdCkLYh+qoBqybTpw969nSEpQFjP1usARvRpif8xd1ho5NttKJS712j6FRLr6530P9p7cgb8v26uz3r/
-zCiekSSspmTjVbsrAz8419WwTQOu8IaIkt/8fk76uuAWRd8iMOI6gvveus3IfLctpXnhhhT10jcerQ2U
-ctziae6CYRCBnrIRBCfXw6sz9UM/X32U/08+TuSf28lI/hnfnqs/ox/ln+uhbJ6WGbRh74X2bGVQsC5g
-GSmA7rl60uZRNDflUnpyc3rTEylZh324EMBXWZEmcI8BUcCMZUzqRdGxac+BjAaHR3+Kd5riaNlsVOh2
-ndb/ylk9R0igZTWrl8/MezcqawYt+etifY9ZC5eeSTVjPa8H+2p6KnvZzb0r0JahVRZn0N1ORrshu52M
-mqikIRpEyio1qowlmEU5wwvMMJ3jSIkUyUyAzNUiHH/OnyWoEDZJauuvhY5Sja0G5rxVrJnXenC81xXP
-3TBKmG4KRspuAC1+9/u2cKbf/z7WT1EumNKTBVMP7XCVwixw1dLeQ5u3AVYP7XBGjxbSPLbDapVaUP30
-K2K1M7vGox+1DeeMZIyIx2iDyXIlojxj4lmTHY9+bBqs9tpfZ66Wi25r1OxtseiMbXn777Y1zh6siJX9
-6Oc2WC2shdRPrTgzVkLJ319pC+O/nt9qa0DpUjK1Wkcq7X0moKqOLYYgm7/aFEoWtngmQpeY5YzQLUPe
-ElV/1xHnq0VeymJBy4Z2eEew0nNUTb8qOtvB1YuZgqMljoDjFM9FxiK9r0LoUq9u5pgJsiBzJLAa2Mnl
-uCVVkq1fPayKg+7Rspx1Q7gc/8qJLhM7TxagGCccELzU8C/L7cPf0UJEypHSioVSD61gVjtVkNDPrcCu
-omwHt+0rnER15Gt0esP0Ic3n2srIWS98DuGXX6A6z/lcbjxPPk52S8UmHyctVqhWDLstqK0x1Nj+rdNr
-6VOF3rvHZuONg9iQOe67MABW9YQr0AVhXJgOdcDPwiIywIQm5IEkBUotidjvc30zOevDxUJCMwyIYedA
-4dB0isr9KW4XOxlNHwHN55jzTiYiEKuCAxGQZJjTQEiHIjCDzQoJ2EipJSlCrYg13v6abfADZhHcPypQ
-QpcNDWi+I3XAuJZcYg73aP5pg1hS42yerXMkyD1JZYDdrDBV2FJMe+o4M4TBAA7VsVaPUIGpHGqUpo8h
-3DOMPtXQ3bPsE6aOZjBi6aOURite4KXZ4haYC0fvtV1YZz517YFs31hxASsDGMCdAz3dbaekjdDdwfR5
-Wq2MNTZTrj7W0snn5vbVx+bUVlsCv1UC+e9OAdef29YQHTngTnnb9Y67n9ctm5PX42o9e3U2Phv9eOat
-j53NsBqAuz9UP3SDFwM4DGunRL2XFYbKueSCQ0ZxGXjVcYfEH78Md9+1djfe1aGeW44CT2Ft57piZNZ1
-xOfwak7D4zZVzH6L05efKZ8JkfbhIRaZwRXWNu6qGp3SXmcC3afYqQeZqO23uzTbqPOvFVmu+nAUAcWb
-7xHHfXgrw6N6/a19/U69vrjtw/vp1CJShR0vD+ELHMEXeAtfjuFb+ALv4AvAF3j/sjxuSwnFz53Q1vjd
-dgxP5Bq3Bu+dxksgxS4MgOSx+unvR6umutP1K0w0SB1GnaEY1LN4jXINF1U2SNq6uNVLxfooyUSPhMcN
-sKcw/ikjtBdEQe1tq/N2mbFoNdu1znvNX0ZHcsRLLcmHhp5k47OaUkAdujIkSm3J53+rvgxDjsYU+7vp
-jGUbacklV3mcZpswAqdBTpmwnE9m5jjmqaaDqfvLNkYC+AJB2DbtNbQBOoagTJQvfri+Gek9UMcfu61d
-5xI1N+kXmnm1IJ5/vLi6vRlNZpPR8Hp8fjO60j4mVS5Lz8Ky8EVFljp8M87UIZqpe4NEoHJ3TUb/FiL1
-4/q/MmIHfwmeCb+alWZAxwIZ9isvpQ5xKh+tw3ddwrBJUFV1aGiRNiL97YfRD2c9xwZ0QznKSfw3jPMP
-9BPNNlQyoI9kTNC7mTX6l22dKAQrDIZXr/bgFfwlwTnDcyRwsgev9itUSyzKlKOntc4FYsIrPcmSzuig
-gMsans7yHVWOZut2vJIdZwJIIJfpkdKuLsC71yapZFFVb/CzjspP+r0D2waT5YLHivT07mAKQ5u2SCty
-4a1eBn6Xwync5HrVYc/eMratX2lXYGsoqxosryzLViPBK6uqCfqEu05/Q0DcqZWCIX2sJoku1rrHDi5J
-kOAE7vFCrx0JL+da7JyQrQuBhF7wLskDpi5bnaqRwljbaRGz4ktkCrPG6Zuf72/0dpbEbm1H/laxyZSw
-8N7PTxoicqxrt40E6Xeq3PbrnI/JrDSkVvgKPWBHWJQyjJJHq/p6T4nbDhQgaqpx1ZxyijlNZUjb6q57
-peIGfu1pty5h2xymDZJuvx3j9s4rYidwO+PhWVPLmHSORluuWgJ3uSOvaDRLYFB1UYlqA7BZEZ0lYVdi
-tM4SWybVkhK1VzBvQbe/D7qQX1RWqyaVWeW3dlKleVniOKJvvnG287xXnZSNMA4S75aBh+O4FcNTa2tZ
-oe3EYjXE3fpqZ9DUbp+NRjejPtjw55VuBy0ou+1RJ63GAOqr1/o6R9UwJqa69ecnf31TeQRz8cYdmcbK
-+7sq3Jim+phInGW3S8LlHCv7NERUuXyVwgu8fiaLlyCNDSWtjSZyk9NDPanXw6Hi8etGr8B6TYb/tyAM
-80ZZvHX4rhpaEVURtNeGw1dTC4IwhhuaPsLWztsY2GCGgRfaxQf1XTipUHezbc+byWkqHX5JZm+bI6tr
-o9WRGcs4lTGDqKjqWIa37rbQugKmq1beMdIKp9XGn+GwzZJkTCxolRtJBFY/rc70hYf97nDaUqG0s2k1
-TCzYAuQTPphuxVfubxnJ1B4OImlj1Lf5FXUBofQVd3UG5JrDOf3rtpnSpbTbTIux7FJZ7xYCddfW17ja
-urFX3b1TgzFoGVLnplnjXfMiV9lLpH2vnNkHeaoF7maa2pJOHDe7lEGtBK9Gz+/q3+qJ7ZajuTLYkgEY
-vel3jma9lfwzSzaUJHq100tsfatf8yrXUc5+IllAdVBFVWIYAeK8WGMguUTHMOdxmWQQc9xTyyVb0shG
-3uiljO4lzLlnBW2j33bhT6PrW8H2drADuyfvXeHzLcoou/3mXYLnJMFwjzhOQC5nJKsW/k25zLF38Li+
-g1ctb+QCTT55J9Kq603rvTsJ6929U7C2IO/iHK4+Vpj1kKlxtHLuOckeb71y5+fFz0aStU6G20PClkuB
-1eVAhufti4att/a+OttVwnfmuTtkueuu/HZrdtvMbN2stnbp8FeCdea884zyLMVxmi17rbJU1xivOu8v
-BlF7hDW3GNvfBr3xJ5LnhC5fhEED4pm92ae9dv/oXxtmeG43vUgO1d3lMspwWLBsDSsh8v7+Phdo/il7
-wGyRZpt4nq330f6fDg/e/fHbg/3Do8P37w8kpgeCbIef0APic0ZyEaP7rBCqT0ruGWKP+/cpyY3dxSux
-dvZrb3tJ5m2HyYiWZCLmeUpEL4htFry/DznDQhDM3ugtW1e6nvr3Ork7mIbwCo7evQ/hNciGw2lYazlq
-tLydhrUb1XZzvFi7x1i0WKvbJeXlkpaK7yCoX3t0Dr8kvpY+tFg3LpBrvw9/kHy27Ay+lT7nz8r1vHnj
-XXGRPMIVEqt4kWYZU0zvK2krM/Kww2sI4gBeQ9Kya5iUxeRpViSLFDEMqrYe874+3MZCXY0U6khc8ugU
-X5SnhKoS+Xx2O7r5+I/Zzfm5qsyflyhnOcs+P/YhyBaLAJ6O5WjfyiZICEf3KU7qKK47MVAfAaZt/c8/
-XF52YVgUaerheD1CJF0WtMIl32D2xl5mdlXQ36t4NxfWssVCB0MqSHkvFHrOnbaw77Nn7np2ampm+lUa
-a6FKm0S7yFw/S4VaIh8okZ4DpePxZbtkJZEP1xc/no3Gw8vx+LJNlMKi4jz1JfGJ0J1pXD9HQouh7PnD
-eHJzFcHt6ObHi9OzEYxvz04uzi9OYHR2cjM6hck/bs/Gjk+Y2Wsh1UwY4YQwGWz/tZdDVIfyZkcQBaHy
-OuZihxF8dHZ6MTo7aSkCc15uKRnhWcF0hXq3XF6NSIK5IFQt0nbq9fueQ2lxpCuLpCvTZ1MVx/6pkVHh
-5OzqdrsePYj/V2anMj+MLpv6+zC6lMHbvH97cNgK8vbg0EKdj1qvqqhmW5Ezvj2fff/h4lLOWIE+YV5t
-8yvPmyMmeB8m+vMNgkOmavxkP5vp90QG9xh+ymQE1yuMAIJQeXV1CKy7n16P9WN52ThnZI3Yo4Mrhl7l
-I/8SqMuxDG368HdVVtjbrMh8pbGEOsvOmDqYKChKBWY4AZuGOXzaUKI4UqsxyY8ga6xYkSsyXWiHGWTM
-pO4uKzQT9pAjgoITunTuRSsmVXZl8OJ1niKhcaMkIeYkzn7vQmtrrj6Ukbjyzni++EOihV6kSAhM+zCE
-lHD9nQT9+QPT3wDI4Fm5VGcwW1yodoN6FH/5BZzHal/3qHnvPnBNpNwNRQJSjLiAI8ApVtsvjUTNUDTD
-5e5Gl83u9Gl0ZGjT7MbQRnaaMbTh+aLsqv293r1WZUkrXGrO0byOCHrHINf74BZaZh3OoZa0Lqyivlr0
-ygxj8nFSHTVKcooFux9mVGlKK4KwRFzZpm+MNg2/WNjRlIZFuFIy5kIa2xJTzPQXVSrqzioebWpIrQo1
-SwavXGV6DdX+6IH36ZOyw6AG31IXU1ERIm3eOVWrpsnHSa8ctsgoLNLfsCi7huGzN1C7kYXNj+64irUr
-LqlWnuO59OVJZBJPPWul4up6s9185SjwUjUW5rhG9YftQ+abWZ1wTZUNydWkqRSZd+myocdnMVXVQt4q
-1/0gwrY4sdXRnwyHWxw8yRK80F3nGRVoLuR0S6utvl5mqhkq8NncfJKhD99nWYoRVXv4mCZyDjGsLiuZ
-qUQYTvYtfCytQvrzcofBu5HiXMJleFFwnDTIc17gPlwa33Iy5KCjkl7JpdkGJ9J5KDgXNa99ZAN6Ogbo
-0lRjJnaPT0dPhWND0qQPQ4O5ojeXMisiEmKOWNJGjXD7TY/t9Jwo4gx1ZxTZ3afXDFxzXPoj/TgYQEAz
-ioOwhs+8hjt4efwSpsdtyKT0NYSqaTtSDVIhLjGXIpacvqh1U3dNelvksd51MJDu9ZtvdmHX6xNCSxh2
-Z2AzDMsxxVSwR9mkmcpYZUBfGyfrCpdzr/4ZAudVOS074sHJcOi7n5eq28sIHCSR92WVXaPDTqg7o0XN
-psKOjekIUic4uoOtt6xTTPVW9Y4cSgQVh/LpjkzD8Hivy9B/BWOOVX09cxKJz6BscZmsB4qxCpIITv92
-cWUv15QfCPzz0btv4f5RYO9rb3+7uOohVn7eYr4q6Kcx+aec+Efv3lXfWRp1Fn1b8RFjLSLD60GFtJJ+
-ZI8PWcxTMsc9EklYB9Tf8R1JEf8vAAD//1RgxiUaVQAA
+H4sIAAAAAAAC/+y9e3cbN7I4+L8/RVln7jRpt6mHx5l7qXASRpJjbWRJS9GJs7LCH8QGyY6a3bwNkJLG
+1nz2PVV4NNAPinYeM3vO+g+L3Q0UCoVCoVAoVAVLwUHIPB7LYP/JkxXLYZylE+jBxycAADmfxkLmLBdd
+uLwK6V2UitEiz1ZxxL3X2ZzFaeXFKGVzrt8+6CYiPmHLRPbzqYAeXF7tP3myvW3eigvJxjfAcg5yxr2y
+csYknL+7eDM6PHrdf3cyvGi1QbAVjzouXA2hgCz5fJEwyYWFOs+ieBLzXEA2Ac7GMxgevT0/6Q+PWu0Q
+ru8BsVZAi8o9+Ig9mCzTsYyzFOI0ljFL4n/yVlvTyyNeEwHXELGWkA/79KdKNeet12cAqKD94CB+ym8H
+Bq8W9jQEeb/gIcy5ZKYr8QRa+Lbt9AafodeD4G3/9F3/JFBNPdD/SKucT7E5ol4XCshdB36X/jedQoJ1
+CiJ1Fksxa+V82t7X/CeXeUqQKl04TMW5puCjncgm9Bp6iHx2/SsfywD++lcI4sVonKUrnos4S0UAcerV
+x3/43PHLQQ8mWT5nciRlq+Z7u0yYSCy+hDAelyjaRGLxGG1SfntIPKTJYsnbho9uzaKLDlpVzu0WP0OP
+KF34+OCWH2d5VGXz84LL3eKab4fDky7sVF/fL/hweFKqQ6KE56vKlImnaZbzyJU15U+S5VMuSx95KpY5
+H7FrwVPpTTiXnos8G3MhDlk+Fa15qCeoIeb2NvKCEiJGrIQQTyCWEAtgnU7HltMQuzBmSYIFbmM50/BM
+IZbn7L5rGkWyLnMRr3hyb0oo/kV2yaecmkllRiMSMcks3486sXitW2zN2x5Lt3QfNJ8CTwS3lfqIQakG
+drGFnPwrTRH3E/7zSXT561UIXgvFbCi1dUZ9KTU26vA7ydNIY9nBroUw97EtistZnt0i18NRnmd5K/ip
+Pzg9Pv2+q3Gww6Lk1zIVy8UiyyWPuhDAc68jRliUXgegZlS1gkYROc/O+gdadQ7V9CtmXxcOcs4kBwaH
+pxcaYgfeCbUmLVjO5lzyXAATZjoBSyPEX3QQZJ+mAIjleIZltvgdmy8S3hln86dxKnmesmQLIj5OWM4F
+MFjF/BayCTAQiySWMMvy+J9ZSsutar5g88MmcYHDvmC5FNBT6yLBagVPA91jHEwq0El4OpUz+AfswadP
+pZcoe/dQ6D7d/uXyw+2Lq+d/2e5ILqQqdrl71W631w3rYWsrgOeKBM8h2Gp3TQ/nSyHhmgNTH7MJJFwi
+JUOI4mksRQhbL7aIllujLWATyXNgIOJ0mnDYeroVVCW2Yp2eI00VmjtXLokaCEB9dTujqS0ZLpCmv26b
+doLF0IOdfYjha3fF14D3IX7+3IXrTTyn/GVcnoI1zeypZlg+Xc55KhsbwfJz6BUFL+Or/XoU5rWtIn3U
+gubohJ04jfjd2YTYrg1Pez14sbuOAczAQywMj+PcIJWOpZClY+6Po9OkWT1d3KoYURk9lfUkHh29Hx6d
+qrnR7kI/ispTUyuSMgOm+15gd30Ph602ArrmkyznoVor1LSFOAWWZnLGc5jECXfnotesMw+JZtCDR6hZ
+sKWu8ChxA9ukO8faXaWEazmqp5ntHi1fh602TOJcyDWTyB2JS0JJM5DHj7sb8qPHcS5TVphPD6LeLYDW
+pQQwEFxCNjFTrGiTxnGxSO7pR5LAZCmXuSGBEsNHuNbTEi6zAvhtnCQwTjjLgaX3sMj5Ks6WAlYsWXKB
+DboDXGxgPq7R7uukwqPkccUGcbRLohJp/N2U5eZOp9PuwgVbqaVpvMxznkoDWYTE+yxy+B+ByQxLz2GZ
+yjiB87PzAnLb6Xt5B/exupHRs7XoSLtuD+R2VCTxmLfafxbZvL51YcCFzPwdq96u4puEicZ9a0EVD6Sz
+f/EJ4ywwO2vncwkeTtVsWUHDn7O15DUjki1aViz+dDxsYJsQJilqOjR1Umq2YBJkGR5pPnH3/JBNHEEj
+IJZWi3FI5LfqisVJ6i1P5bGGF2BWW6ToU081nqRtVFYmqSn89FHaeoiAZDdcuJ1MI+xMCsxq/Jb8jpyZ
+saKTbuf9IfHGq0OSqZUukyQEUtM7izyTGWqtagZ0UCwVnBvCToiCX4OU+b2r06dmvjzAJE5Zkrhfff7x
+2f/g5Lj49vGG33eVlAuh0+k8oOTgivMXebbguYw5jfDBybHq9i3POaSZRFjTeMU1n7xYwQ2/7xEoyFIl
+erL5HAmaxKm7QHoYGC5yZ81TZ3dRfP/0CYodjn29dqzdlvRQs1TvwbBTK5bH7Drhdtxja4jSoj+o18Bu
++D3EqSkrXCR0B2ZMtA5OjkMs2i5vuQ5Oji9v+P2VM1HpubLh0mNmDVxKyy+J+kPFiMXC2KDkzBgNWv/8
+/OTnkQXZxolNS6dZJmFIhEDTYjoVMGYpzNjK2+ToXQyC+8tHyVOWyocQbmfxeFaFn/NFwsaeNPA6VBn6
+C2pZf/v0SWk0ZP4J1g63gQop59h9qhhUtFk1ONbIFlKx9kaQScH6vy7OTjuKOvHkXqOJCtfGyq1t+xIr
+IxtsJhB2rRAvEVnxBQ2Q0JouTsB6RsgmFZ5qa7mueSBUc7q0s1VyvuAMhKLHlrRlLK5mDRZVyHTgLx8V
+zAeIlag027yiOYcx1vXLZ5PPHsMS6LUj2YU0U0xuyu+bkYVY6g2B0jXjdApxjf6MG3/olYfaM/+ZXrei
+8o5Nk7FXmFM+alp1ISKDBTxYuux7VfWIQK8AvyrLH93+qqMLt7Y//OXDx9aH2+ftDw/b07CoOmdyPFNC
+rASjNBQK43px97sMCcQpYAHd/ecQ0CBRuySY8SOh6xDEF6c1JNCiRmFPMrhU+8F5fvApLZbXQsZyKdcT
+25jLTFO15NHomPFYlbGohaiWwrUAR505W7RWoYPsRqD16lsPGzuf6bOJ8rdiiYQ4hVUTK2SXNyj2Cqxa
+q8ubq88ZuWxdNwyDN44dbXpx+e1oGWl2FlWrqbMPmWdFwfIWpGpOySK9r60YUivwCR3VRgWjuoZsocu4
+REb3S9WAa5aP88Hx2eB4+PPozfHpsLVqd+Etu+GAWycYz1g65cD06mGEXWuLkNxqQ5YrKxwCam3hBmmr
+TdJcmUNUfbstEDhbb+I0gjiFWAr4Z+Zpg2VUHCm/InU+oHbpyEe/wCbXawIeUGv70D2ALAeFti+1zdHK
+Io+zPJb3o1mMJwsrh2pnPx4fHg0uWspsozbaPI0KYmWpsj7IGRecTMX2DAgJEktR2G9DiFMhOYuIVMpi
+oYg29+hjGnU3TYTAhnqDY1FSeG++DzVta43KLN/UF69zQfsxg6jbdIWn61Q/4mCj/ilGJxXQfWUtZ0EY
+1JkhH+mV2hI09yqENJPQsCwRftUpVmIle4Cm+v9rFqeErGWq07PhUUvyO0lGm3u4nd0X7MTvYiFFB+xB
+nFLMcGOFIwtZqksSW91wvoBY7heTUYCYZbfqnElvyfKcE2O56niBQ4Mqrr59+gT4YxNVHCE6TEPVtEBI
+M8nrJx5+6VJZS503R/2T4ZvRwZujgx9a4xkf34Qg4znPlkivE477EpZCf7vf7/ftJFzaxlDYIBw60xOg
+ThJhwuJEAIGD1pYcL7rnZ4PhVghbMynVw/Z5f/gGRQXWptfCed+G2xlPlZ0QT2lyJTrzpUfWdcg3EJpK
+EaWfbv/SQsw+RM8/UfPf4M/Wh+3Os/Y3bXP8osqvHQoXi0IUru90tcc1Ci5qADPOEjkbERpdRdGHQtzo
+ztLEXKYRn8Qpj8rz3mUzTZzKFFbvoae3HsPscJkzUrdMlboldt7R6BX19a+OzHSTdYw4N9w3OHs3PD79
+vpUtaMaYZdKR9nYnI7iyhDG45fF0JnkUwpRnmoGQ4bIVzyHPlhJ3DIssicf3Iagjaj23yb4y04bbOe3D
+QTcNLCc+FlxCq7yLEly2Q4OMah5aOy/2Xr1qKyRaW+MslXGKJ+VH77ZC4unDIxz7dxcvDvpqyJ9ttWmn
+aJFtbS3yeM7ye/Vd8HGWRvjYJiMyQnHHXyF2fKjIoL7oSaYRNV11LcplGjfYgczntayuYdWZenT9BjZ+
+2H9SVV5LGBmsbpQSIrjSSfSjIrv7Zsoz99FQ1X3nUi94dN3SvevCMr1Js1uDIK1PN/UT4DLQDDeiQqgr
+ar7XnSuU7sI+8lR/6yBfbUJuXzlwuDJYO7uGw5PR+dnJ8cHPLTUdHONjlk9f3MYRx0J6stDonF64C14r
+FSMpE8W2KZ8yGa84jNl4hnOsZd5gmZDAXpz1YR6n8Xw5d3mwionjE9aRMhmp142s4tcqcYpC0h13F7HH
+x73Arhh6wSUO65qxJzMC9DRqlzdX+x5ua3ekqzpJu6ptpkQhta1b+Sbn4fCktXIGF8cU6ad8KdR4+qPh
+7wMacV2L50OtqSV36+eIuYOv7ztUA9nRwMnUQDr4qkO/W9u/tD5Ez9utSzGfRbfp/RUu047ybWv0AE8B
+1k2tlTkrTzMJDI0ccQSRxkMj5k+uZRpLQLEUVBq83Lty29Ili4+eRFQuDoIfp9LW3zUyAvu9xEkAogu7
+Icy78NVOCLMuvPxqZ8fssZeXQRQgGyw7M3gGe3+zr2/16wiewd/t29R5+3LHvr53X3/1SmMAz3qwvMQ+
++La0lT31t85paPbATZFwWM9Y9w0LujbPLKdXeo+mxYyRZuqog8BBa2v4fhi+fU/L4iU+4Er69v3WlStU
+6hD5nZjaNwAp0GVfTzI+3i/cPUcZhCrmKWfk4+NqZFXYFeeDop8ojVbG94CAF949qktA0z6JBS3K6p0I
+1s7YisKoe9ekUyr7SOGjWMxv3zrUJDQJOUM77S5lide0u8VK6ww2WByhYjnct8o8nrfaHZm9Wyx4fsCE
+Pf/2eqqWi6DOihZ1Sv6Wl/Kq2tWHJgtQQZ+ziSaIsMJZ87w7BdD7Th17Zmkg1UmTZ8dxAbYixfA+v6OZ
+uIK1LuhJZcKmroP3C35VwyruaPsifM5u+EG//zph2sZbcqMtlgXqqo8FvumMGZskbAqfesrUvO+T8aDf
+Hx0MjofHB/0T9BGMZTxmCb4GrEZe6G4Z6Hk47cLXX8Pf28rV3XWK3jKq8imb860QdsgRKRUH2TKlqbMD
+c85SoYdjKThkufZt48qJxfG47biVcUkx0DUQrM6SxBVeFQdtXb3GO1t/UWYBOyU9prVF4MXuxnM96rgu
+yPaoTMMqDURfoRkvQj1yb91DUhqHPvT0t++WcYI9C/qBpj2aDzaA0O/XAen3Czgnx31lHgyVrWENMCxa
+Aw1fe+BGr/snJ9/1D34o1OSBPuRhqSqigRTHdp5BhNazzLeAZHnJOkmTe8wMNxFYtQXVVWK9LIosWXHc
++wJf8fwe8mWKZuB4xZVtGJtnUZRzIfStjRu+kBCT5ypLYiZQQeedX0WGFekh2nJXzvpeO4xntPGmJcB8
+hwDRCsrrnv78tGcK4KrnvlQ4rbdw+Uia6ta+QvSgbbPuYL3Ji+gxmrAkuWZoQVFgLFcPXr0cOSwFhqfU
+zYMmzrK1qtxlPwWh7hyeVXTh8jLAFoIQisX/KoTLAFsKQqWBMskHr172EWWUyeo7YeTX0674MmepwLsW
+XTvBQQvakJp1PBlqJK869aeCHXVRolRANW2KqKfqJkfbD3Sd/NXLEdG8XT0T9Qvorl9Z+PcLB4WKI3sd
+CNKUFZhuAcQ9itKLcvjkQU94HJ//5+z0qIWGy1EctR1DSflT/VIG/hanTIZ1FHA7rxuh/uvfj/W+3HED
+omsANGgjFvM6JvOX7bK5VH2sUR4mLBG8ZsJdBv0gBCWyQwgOTvtvj+iHen77Hv8fvh/in/PhAP9cnL+m
+P4Mf8c9pH18XBw8avadqZbNKgVkCpiEVaJ6rB3UrisLG3lEZnh2etWQSz9tdOJYgZtkyiUirToGjNEK6
+UDtmy7gDWQ67e//d2WiKs2n1JYHbdFr/nrN6zJjytNezevrIvHe1MoWgaf50Ob/meQ2WHktVdT1RVvaK
+6XlwNBjqoUUJfMPvcYhZMsUDw9k8HPNcxpN4zOS6IT8aDGvG/GgwLAtli2Dt0DlftZTGr6rX3leFZvN3
+i39zkToxr77/SVzBc6luMNZJY6eQ6qsppp5qC9pOm7L2xWcsNC5roCjZTPOjojUcgK+N5nf45uBY3+qJ
+4ikXa8BR0So4em3BbY7dYT12hy52Z+dHp+ffn/9w9LOCuVheJ/H4ht83gy2qVGEX30wD58PBZtieDwdV
+eCiiNaDTvgWV5RHPw0XOJzzn6ZiHNNlD3CPFY7rsxe8WjzZ42q9tkl5/8fwl1JpnX4FzcxnqTHMLupfN
+BVT3m7//uyVAyhYyJzqZYvRQX64gmClcvKmvQeQzhemhvpymoympH+vLKpKaourpy4TL4Fyx8Pw6uwvl
+XQN7bm8DFoA5uzfawZzFidmN7YO8kxAL2OpsQUwmnlxrDDB8PzQIqS3Eec3e4XzTTQNiUX0r7+S/Q6Hw
+CYyoVYrkC3lnS8i7Kv0v3h6/PdJK3VKwKQ8FT/hYZnlIRvI4nZJCsNH6r4BV6avef7EMIbya5YNBuLmE
+25P/XE1AzOM5Z9RZU44eGgqabhcTVj03FHdpYFnGefdl0/di8KNeJ7VrWaiOi0O8yvzoinMx+LGGWWg7
+8mWcYrBoHmR9mt28IGW5/A9mkXxluliIf/VcV1Z11pRUT7Uws9yWwt9fqCde/Hx6oLhB8DxmiVZD6Lyh
+Ua7TV4hFcZDS2urjaTjuZLVPZqriD0A2gZzKK1FODdZom/j6i1lIob6ZNlLzmdALQjCwz3I6yvpztxTi
+Ph2rfjirecyS+pIbKAh2/IuzObtZEW1bGv99U2xjzAEdBH4Rx2QkqhLlTK9Gc/o/V//zSc7FLMy5zO9D
+freIcx5qd4dGzhqSqwZRIaWBgljAnKVsWtzqMFZixVDoRFGVR2dfvnLN13/OH/mset3MbESO5s+KTmuW
+RUXAugJ/sgJz6fGHWpv8wC/2fV59v1NXTHNM3Rfkoep7zVU1mGg+s1+uCr6usO+70x9Oz346dUwpOcY/
+aWTSwrVzAoyEIUSpQKe2PEsgyrhIA4lU5ok6eTaXjUgQasZGQCyNgJqiw5AZv3vB03EW8QgGrw/g5av/
++bv6rDhdo1nldv3hM43oLv8gX2JDf4BGrHWXYPjz+VEAz9cYTD5TdyaEq2M5OK5Xbh7Ta94NjmsoOzj+
+N+o1/27NZZnHG2suyzzeSHPZTEO9ePNa7zELayZNzEfs11SxZjnA1188kBsYJCdxOuX5Io/TNcNZY8T+
+U/VQMZssPsPOSOWdjpkazqvPMoabwaVhBbVvBbtxBW/nCs7WlQZ2eHJRs8zj2/9P7lBhe9vvi70kvKXK
+b9n7/X/m0p6ITbayWGzjjSwW/gO2sab7ZZ29dVc6iHSO5+5Kt/bv7P3l4fvhZvZdNExVufD9cOOl1zBD
+eavxBw8wylQbVsRcpZe38Zh33TIAHeteQUXVxURVoVzwThpAunCcRvEqjpYsMU10/Dp4DacLx8bWx3Lu
+3Evf1ZVCxwtEny3SNTY2xsuMjUiEIGdLFe7D6F9MSp7DrQoTASqsSpyaLpZwe5Pd8hXPKWQnFsVNbZkC
+Cu8QG4nniCUXgI4Stwy9Wjxw42y+YDK+jhNcPOl6DkJLeNqibXEbej3YJQWwFaeSpzjUGDejDdc5Zzcl
+cNd5dsPdyxmc5cm9uUaFAKbaG1dyvKLV5FztzKcml4P1fgxuwYIBenDplL7azDGhrqHLnavH26pFrOK7
+cH50enh8+v3ox6PB8evjg/7w+Oy0ZU5XJJIzVE5ca9T8wg4NLSZh69stWKYJF4IWMYiFcsRtK3clzRFm
+H6DcFosLliAz0O134Cwdc/g/zq5hxfN4cv8C+Sbhkv8f3W4R+0fdt6HqqnDMI89hWN354nNCIpY2Nt00
+Z2MOC57HmRclaB19gAjU5OegS5mLYZfsxT93XvzPlf7bGb24emZuhJmi6y921qBi+2p8mJLsludjJnhN
+RL7OVqjC8WFYvhc1t8NyTl60m8W12HP8y7VIDb51HNkdSiDcy50rr3u6Cn7qiFk8kbW3uobvh14Mnkvt
+UkWMCR/1EI+ZivJpiPFw1Rln6ZhJarltF7C370ubnscWsrfvq+sYuZv8UXudf/deZn5XdwrXsJnZaJNy
+uqFn5WmN49vpRXEi/Pbo4mjw45F3wuw4WpUKuHOyEpzqaQ9226WJ1toqIBQr6YKu63KrZcIkU8ze2Wpv
+7hHrOvVSoDM3LrENVlFcLraIjJou4RRFjADs1JFi9EfcyvmorkZ1YeVcyLTIv+2/Hx286Z9+f3TRSr0Q
+Bew6y6WO03tLCosOWlAoN2nJ97WQ28DIdd1zf3W67Ldairo8Z3cj1ZTowpzdkSdyK3DqBCGkfhcOj06O
+hht0IeK4DP1eXSharemCaqrSBV3H6YLjSK8LamfwykKlBFBLxWNL4WvYVT/+C3YfD8pmo4MW10UWmYjp
+Nh+pWjyvdZ9NvXu8Lr5FiG8r5UYSQ305oZ+HCOLyMslu6TLTLJ7OurAXIl7fMcG78BJ3EPT5b+bzK/p8
+fN6Fr66uDCCK4by1C/+CPfgXvIR/7cPf4F/wCv4F8C/4autJcbUk5Y+FiCzhuy6ua7yAXrm8F94VCxG6
+0IN40aGfvocsvWqKWKM2bapIuQz+M6BVkBl6ckIHxXVV3MFbzveiTLbiuqgv7fKtpbX6rYuMAavQXn8b
+xqERjrilEj5U6IQvH6UUFWqglW7CUguf/6300gg5FCP0N6MZzuAeXFqsFp0ku22H4LzAKdO280nPHIc9
+aTqoZSzPbnUP4F8QtOsmuyqtC+3TqYISssffn54NjkyUX7o0n0T2KrL6OrL+b+5FA7emLyYrtfzG1IcF
+7XfT4pahjkeRZCn3LlDdzjLBIWHXPDH3LhEWFpkm2TVYQLWXDfshHfKqy4Z9VLzp+WqfIsZQKYR2fW/u
+YFW7WIuvc5k1XyZcxcs+ppj7rcCpF4RQqrm/iariBfbXo7xMeFlF0Q0N+4Pvj4afS1KluyEYTdYNaWoJ
+t55q9UhtQjdV8zdSTvWuiXZuzgjdugmXV4dueUupS9F6rX9vENjFrtTGeqqrBv+x91MNxnQ51XT097mi
++tHA65ZobkAXTP4WI6mMhoP+6cXrs8FbpZUkpBqrdduGv6YdTLl8dT9TLlG1h1aaCMggqppRvzH8gLd/
+/D13hnYH37jNU6hUCs25ZJeBxcEg7yVuofqVHrarDUrr3CFlUtlRnr8bfH/UarvxrfGFnY9R5wfOF+90
+0IWeuVZiIjeNKvXtu0YQMl/ysuzDRCrHmMWhHRL3iSLETJGWxGzayDxlv8+wsDSridkMQCzo1p0XYuya
+I3frCFA2lLMTxAahYDyHeKp3beoUvMCS7vUo6aT2bQH2JoCHfVBhHiCKJ3rLX4oLTR3z7hUjK1E0KD8o
+ld354dKDu6rjU9o6/cTyFHdNS4mbvTgl+O5miSoIr8cvy/s6D+ZmW5i3TM46kyTL6AsKo3Staa3cDoXk
+d+LxaycbDKlMCDvy53aWJW4BJiHhTEjYrRc/tBvF2TYiinRNbBdnA3p0evFucDTqf3dxdDpsmQ2+DhtM
++00VqU5/0W7B9yrwWAjcxHd2+d0hqg/eWxk1QCe094axT+V84aXuUAIlpJiHfuoO/CfnC/+uv38ZvK6Y
+vk7uldXv1l/y3yAwoxcBsojEGCICtfFToo6XyAh65TfG/Ig90ADLKtTZT6fGVlUMjfMSPj5O+aiT3aY8
+12liyrfcz06H/QOMF26GIJVdMruzsQyBRfM4dZ4lH8/s44ODk4Wjv4mNULNDkWcq8Ua5dtEHWjqo2HMI
+RrocLR2lAH4GBBVeEyhzcPT98cVw0B+MTs4OfmgJyaRL5NrPm5HbMvMoySj7G1Xeb2r+8KKlb5tB4aAB
+6mqQOsBXv2uR27jyJqiTvHTxj0RdeNLiq2PwKPO+W0zbMz1ACuuu/lvyOjM96Tqd8tGwHey6na0pY74X
+3yqmVJeao9Oz06N6QtMnd/VPs1GJGK4G4FXtvxueNUDFTy5UtpRZHbTzEzzNORq9Hpy9LUuEuq+b8uoi
+Ic+Q0STP5p6MMLa0GQeRLXPn+AjXYZbKmFEsvGtct/HkLb5eSi4gzdwAFS4ofd6nU7AlIiNd3GascQJT
+tP1T16ctdUaYliJHtKvsWRtYYqdRChyc9C8uTo4uLshE+j3Gqh7HUR66XaD8GZXI/Amfqqxh23uvQGYI
+bPvlLlzTnEfrxPnqb260BEF+iHsvd/8OERfjPL7G3YQ+df5noarB9t7flNLHJMyyRMdfI7gokNVBZREt
+zY2HCYOjHwn/ts4FozSRJ8aSSTCpoE2OpzE0UKgZL6NDLX1cfYDg9Yqm970AfDp01odt9afT6jzDEJf8
+jo/pursTRevp/JGUDxVUauK3KuwcpYzLORM3lnfVaOFQVU82lRmnB/PL3SsEsY3w5zaOlhtduoikdbl7
+FcLujtNtEf8TCUIRYFov9+CFW3pPlXaKL1iu1IP55UtMjeoel2oOdOSsE4D+8rdka6o6MxRZdcqzqnI0
+VZODp9Eiiciur1WyDbitbRzP+POZQ5s5jCKOTFy0XPDGZqum3hrjHkIN56Oh/z4XYbjmSUYOQinolHuq
+JZV0T8UzLr4VsfS32sHjUcrdSM4e6WtV4VM/5c0lzRtPZF7GV/Z0HRmg3W49EiudFbHSGXytfsJzmkv7
+wKo4kGDz0TCsjMIPe95BQpB0oofHEbKrgh0SfSa24VpADmHLa32Sh7V0/q1YCkAbASLTJRmObxwZztIo
+tFHrnOa0SYCcrdSiEAu77VYpO6bJkneg79Si1RUU6qXbQ+q1CfHqx/PRYYSm5BScxDccPRXEbkcsr4MQ
+gt3/2evsdPY6u/iwt7Oz242u/7vb3Q2uvARqDuG8VFAsTtxcCnMxXR96bs2kcJN54GeEte/mZN4k3Yz/
++K1nGkVkW0FD7FMzviUR8cdLbJol+bQx42NVoj8uyI2PAcunIY08ptuFh6pM9+2+dbC1E4XGHr5WyUaf
++hiV/Xt8ijMHQaUTTnXkE5eHkUkNF9ODZeFgbQKMdZ2n/KGq/9gHlbZut11HiTWr229c1Fy2+x3Wpd8g
+4ImZTWLZQqTvVwpN/jeibKxlRyHTbfzeGc9Y3pfqd5H5TVncgk5tCESC+7ynZXixmHWCdSNcXpeUCERY
+IXyESIvpwrZZtyJ4APX2x3BhnPoCWCjJrtTGWEDOExWnmER+p0ItsZxM4jt4vFvEMaJDol1T7K9/pY5o
+1nyhQOmPipTqVfPsKsaSpKYicRGLtpKER/cPxiz9EOjo5USJGc/5+qmGnZ1lpCg7SGPSOx9vH4aTzZoY
+9ldkWI8K+/BrlVmtbIyiHHqmxuWvV/u1xYzjfBTlRY6Jrs0xAd/oGFLQhaAfVGGgubS8QNMDCL7iOUuK
+JULYkO96naaEYZ1atFQ+iOIgojKGRVRqIs5NbUKZm3rymEZyNy+OFzja/WdR0T8wZ3Su75711NEesmPe
+sesnjTW9MsdAPUXhjdL+0HKt2mpCvmVbD/oBfAN96NL96LY/4REP2iPn65S9ekwa46nq8zgK0IYJgSv6
+XzknG+lZ3x2fHuqtdpxwbSBwD0343ZiCJ0pB1251mkJPKVTufGzB70wGPnw+PL1AWF72mQKwy3IUSX7S
+AetHGdok5ejz16ZsAV5aXT88rHtsXiKCFy/7d1Bu1u4SHzY7XigOJhDN0eDo4GxweKHHLHKTwf+GYwAz
+jYoTgKrodiYLeqeWJktJ2TT/VB6HJV8nV3Myg0PP/Pj0qZKpq06IesmtGiSoUfBsAlasdPnrVWPKN/29
+VTvHm1yT8J9NfKc6ERpInzFDwU2Updgmb9rNPXv2BJ7BtxFf5BwNndETeLZdMPaUSzs9WuoEXEiWy00Z
+nApbJt+Mv5tYGwu5SA+og0rgXiv3AOoLZQeEj2pH9KC+O2XrymQLKTrU9NXlzhX09SRTm1SnvKFLz6+y
+ewVnC3WtxsRyzPJ19ewZPxiGKlJ4e1m9TWgieGZINURP2ganhDadoFthAv303n4TKtf3NXdgYYMxt5nO
+SHszqHaciIvzpTQbeOVN5KDVSBrsjOGdmm56SegLqeqzn+/7oU5sELrhHfxNnoUmm2zr44MqETrctdlN
+OfQBsVW+0BFEa0A2KHGSqAXHFgaW5JxF94b05ZoI2wyUk8cF55STkFT7BNVdX2q+neCKMX3iv+6OVp3z
+inFxdOtt6HW58ZUvR9FwxsPjppoxaRyNhmyGqvC6lcyRbtArqtCKttHysG5p0Hg3rw2l6LXrwCnflRXP
+ZcG1NKlMttq6Sgh/nkWOIPrrX537qt6nxpZ1Z4qSnqeSD6Nel354ZAks/KJoiD93La3YzY4Gg7NBF4xT
+khlVWheCz9GB1Xnpg9kRl5Wvsu5FJxyRTlH/sZSSsxAOahF9UrIZeaohfF2sPHZ/W9XBbLUTldTB1ql0
+kZyyLeKx5PNH3LGxSOXypKJGFbh2cYQyccsjgwMAzyv1AyNKc/6/yzjnAoKaUmWC1AKyFIFWHQyfYDUA
+2niTMrmHtZXXIUD548VSyf0yPaqZOZ548zvBO+9FO2vV9DI5GhV1lk8PcSWJcehdJqk1Zqk4y03mVYdf
+C5iGHP+A3TqmwpVymRYaEwIwBKrfBnvQL3evauJgfwGXVbgtWFPIR2Hnai08QyvTR7qhw+KkygCP6fOF
+BLksY0DZT4r4F83sYwVNPfvU8M0me1NwIk8/dpRZiVZeu1v1d6zQK30C7UitnI3DyjdcLrrw8aH6hVw3
+a/O1+GUfSkt8VaGtUTz2q1Xs8meLF8PoV127d6vTFWzebPxWyfa82eaORZHaFxkyhOBnWyDrUHFvLJ4U
+KTG0t2YITIjlnEO8MAcNHauOGCNvSeusUTgrGqanXLqXq8ceO9SxQXEtNCzB75qOPfkchjBXd7tuIDGf
+xzTVt7cLx5HCfTni4zjicM2ESh5COJvyL+zOqAt4ORxiUeQy0fzP1DGbF6WFqirlsAtznk+5ynWr91KI
+HsasKsqamPDHr/FCtoWsxo4G1PTziaMfisdOTB5LDG78XHRO8PrdSKE110pymj31+4yymtxuWGY+T0Gm
+zjeqxhsoxvMmlXitQvzwZJ0ibLTg9v6XFGtUk8dZKjK8bZlNW7V9CX7qD04p++ZbV1uGZYpLdZZLHnWD
+sLaqTpM0r/8atC5u4sUiTqdP20GlxCOX8R6e1AtK/55RzsfGTB0vNBMIWoM1xwkgvz7KA7y9LSQb32Qr
+nk+S7LYzzubbbPu/d3de/f1vO9u7e7tffbWDkFYxMxV+ZSuGvmoL2SEHfaqTxNc5y++3r5N4ofmuM5Nz
+x1h83ooyz4KGa1yUSZN0rWOUA7Jjcyljnr9Qtw/c3rXo3/MIT40x3eCrr9rwHPDF7lW79Gav8ublVenm
+sL0NuZy7NwTS5bw535DGJAjqLkEZ94LlvC5RSbqcl6V7pBYA+C/Es8aY+HIfYvgHiZ4XL1yQhKN3YWE5
+h23qbcFGHnTrABPVpVWz7o9JtowmCcu5yt/ERZfev+WSmUykgnB0AhLZ0wGKQft6dD44e//z6Oz1a1y5
+YGxBjhZ5dnffhSCbTMxNknN8BVEs8FJPVAZx2ggh9QHwtK7+63cnJ00QJssk8WA8H7A4mS7TAhZ+4fkL
+fY4yd0nQfVLgrhZTyCYTtRimMs7t6UvLSUXZ7vroHR697r87GTZSaqTrFRSraTWtNtrUzOmjraSmkXdp
+jJKDJRcXJ/U9s428Oz3+8Whw0T+5uDip68rSgBIi8XviN5Ju3MbpY02obhA/v7sYnr0N4Xxw9uPx4dEA
+Ls6PDjAODqjTIMDImReOTBiZzETFTBjwKM5xsf198xNRheLEOQzaJHV0biHd8cHR4fHg6KAuiUzxcU1k
+GeWoHYTr+uWFkom4kHFK+7aNav251whVd8gZwYY7dTD2L/1pEg6P3p6vp6NX4v8nZiMx3w1O6qK4nuDi
+rb+/3NmtLfJyZ9eUej2oTTpDr03gnovz16Pv3h2f4IwtZXgnybtguRQqAAD9NOfUF+evNVxoyQyuOaA5
+zlwoCNCmhdXJQUdVx7gq9Gi9LXUifAdWB1qFjPw2oPhlObvtwk8znnNoqaN4gtJWWnaWc8R4mbJE8pxH
+YNQwB0+zlBBGUmp8ZDxXzvjD4UloIqJAlmvV3UUlzaQ5FwlhKeJ06iQiJiSNZqdB8/kiYVKBZ1EU6/M7
+GwyNCDbOubrGYTwDgpFYTP4rCvymgTQ37AC1NEmYlDztQt9epzYuLwqsLqCX1Tm7O8mym+VCdJWNUX/W
+lxLNGCrXN7r5SeOkqqhboHh056LEklt2LwygtiPSHWaqEeH0pqO46NMncB4dz8W1rqkO/MJqa/319oAn
+nExD1VvvtH68M2qmQq5T0KZduYvl2BQqhQvsi5fmZmvlvYrV47sbPta5rjNsG4Tv8TcxDq3NWFu89IvC
+D/I34GUHQAMN1vhganz0xHXPL+xrV5BWKubstlotZ7dYaZSzW7GYBL57pjrvMG6EZqY4E1DpBsqItFAn
+J6Y06p/OiajMdGZmZf5gcepl9gEAUChAz2PqIlK7AVxIKV8smQ3Z8cQQE0VMrGjMBQmJKU95rq49Fa07
+9hx2WwJqSOizwl//Ws8K//A5YWEr9Erla0LiFK24k6R0hxe/jYxc6YGry7nVmjhaFZQyqSbYpP05xr61
+bBHqAQnJ2aqo2m4/mm6zGVi7hr+dgTMrAMQCxIKPKbJlqLc4hQwvj4up5hOfilvSmzL7pVa/X88SPhuX
+Gy6RstJzfbPEEHLRRMsKHR+F1K71pM7d7N/rNJK1KsWBzc9cp0rEWcQnqqq+q4zx8p0FGDN7yawr+HiJ
+5spv+R3DQKZoewk6cOQm/uICplyCrtGBVqa9dIqWRmOdurwL32VZwhmtuoKnEU7vnC8oWp2VpNG2Kd9B
+hkozCdYM5oUSd5KV5nyyFDyqNC8EOtSfaLF30Bfai1GZGzAIaQQyU+Vc0MJS0uyqlZaiwixqDjOGaKXi
+EYzbOIm60NeQi/bGLFUF0PEkGrM8qmvNOnZ31rdnm7OUDYvmq9S2ohv7Y75SCnmjHVJldZvBgLE2lhJJ
++3DQV9d7nEtGIZgyqFlluvvX965DTSsYs45mpH1g4zGG/Ovt7r0MlBdolkOQZikPTECmTI0QpBkc9DuO
+euXMDF+9+pJrPg6whrs8CHXMhAtU9XlVd5vRUa5qYjKrPMG1F9ZVDD/hp/hZteEbWEEXLlelezZY1NFf
+dnFFo5d4XtrrGVp++gTuy/2gEalgP2jES3Celrwq6k4qHJwaTyrGDHoKpbqjCte+MWblkMQt/ePF1TPz
+qv1N60Nn7ff289YH8Wy/86z9zV+2YxXHeMya7wBV4kU6fC60FAkp+TpSeEtxq7r8WHd9krUfvaegG+jB
+mBnz9H7Qvty56sg8nrfaHZmdYLjkAyZ4q10lGo7OpQJy9Xi3EvKoJ7usapf6amPd4P2A9Vcr3OZsjIBq
+URtZT0g7nSr3nF3F99OnQvMlxiKhhFQRrYAeAp1euUNP7VJRklpucXzhV8E3znVveuduAnAS2YJNu4N1
+IqRmO5alHHgq83t8pfqUORjXXOL7PKVdXzxxxRMF41iFVtaX5VTde0dlwRTjWlkxkLCLB4Pj4fFB/2RT
+7bECpl3nIF1Sx0hhqMQixZeWW+jJF4bbv1z+0v0grp5/e/kL/jHxyhW0cjcNOKPm4FwoAS1tI7d/aemy
+CP9b3c63V88/dPSPT3hcJb7pftj+sK1waFtpU4+FuhJF35y9rW4mVMdfkOX0Q3SVgtYgZFzS1e4cWBTp
+poJQdTV0iWlVBs9eUCfh3RlTEfGqFT1R6S9FJ3Lm32c25MzCNY3paW5/lxv1lKL1OjdmoPlypRtrm1nq
+WrP+9reXnZEcLzq3t7eeUav4pPRyvCfShfOjt/Sr2MG4Om+Wg0qIDZQR24snKGd8voGmqv7RFTJ1eQDV
+cGoMz+tYzr1rhITAMqeL/uoiC1P6qhZ1LQWUksvoPYWDLr12+/wyhMP+6dGLoyPqssk004UdS0c8KHOB
+hLBrvxV9d4Hutm2YTp2ExsBLM5gxMTMgLt70X+y9+iqEPfv4anevBKrQNF1+aLTk0VgVpqU44RuvHC58
+Z+kgOjcHBykvmgUXFSuiSfxTZ++jb6hYvoQuOK+K2k4+oDoA5jPC2LUw/KRBCKaUKaje9lgU8cFVUwqp
+6CcJF76KbMlOurJ9IqXZPrlhTT5vfa2TToRFk2QyG/+TC7v+PZIei9jgTf/iDd0UUzHd6su2a6MXW/lF
+mdG+XIBRdWfjVzEcKAHVT+FswdOLizfOdKRvkOVAbt8jvAcptLzYTEYteI5w/kARhThp6/9SqKs2LrKo
+pMUmtGQsqHh596tEy5AyCRWp1NQoFhJGZQLa8yVOhQougfe84wN3FP8IseM18OVyx1XWf8O8BOdatGgQ
+E0Y8XO5dQdeNA+F/Lp6KVvDpqv3nTf+664/UtXX3H4mEyiBMY1MSBqonyJBIeHXVmGBWLy3W3dKeLB69
+n62FFrVupNZkgTe0O05UP/1G8bt+cCZCeyOPsrLQOnzbHxx8vtAibSBL4vE9xDTb992DvJh2YKNozvJx
+52uq8Y86iaYgdLW5JITgf5csZ3gPlgdkkso5ohFAa9FTUsTerR+ZusMCk2xSfBfQElipJEJYEk9TPJ4b
+RTfxPHSexWLShQDV+rE0jSfsjkcBtBgW7qFgW0yqMBdjqdHg+ZinEtf+bAJzLnDhES6t1KU4ZPMQdkBm
+sLuzA63FWFah5ksWQr7UluJ3g2MBbDrNddS5NKItzJJkMVpnBcllCjQqszp5Vwh2/PcZVmTdzki9FF0I
+digeEP4XBYRKIIJQxTZW26JC/d7tRoGDTGuS9dpNDSg3Ui3g6TciX+5mK68ZAvVxhEeS+YppbhV8nKWR
+gGsubzlPHfJpWJpMkY6J7GCNg57H1Xb+gHN7J3iSOxWrtlVioVhlGambMKGdLm68us+Nu+TisMYkq22l
+Fu6qxhNqnSHVAWWn4CM2XhMl7COoSdpVbCf0Xz1buxDk+ER/4aFiuX3Kak0BFbvjlmqEcjNsadhbzTaA
++p2/JgSr9HmZx4+ZtB0LXmvV9i+xLeuCL7nW2+VnWFhRtDR2bLnegLrO/FOPw7Jk+lmuA48nHqk93VCB
+FZN4Hstig/90d2eOWro5+SBp+25w3KnSx7cjhftPjSlJ/fzQKX6XDUrh/tOr5+3W00u0aj+/vJlP5dU3
+jkl7E3pTPHe4ZhGi1/0CYmuOcChWTQ7j2vSMwLBOh9ovgJYi431YDUClvhcTgQztIWwVosZMChQ2W4/Y
+xXRrFbVWMpXrMlj1SObQcrLoIRy34tX+eu+Zsj5Q8aFpIEKlXg05CpKUS/9exKliXydFkFRKQQyES6FK
+7Y38jXwNqHRJyTakNB5sy5ZvBX5Va9wvQ/xMNMiroQEL1LcakUB/mxBqwW2EwmIsN3C5wlKOp9hYFonP
+/Ndfl1/8A7W7eobCz9bsnFr9ge5qGL3wsYk1lo+yCyqWzoway80Iky9Z04jkS0YQcQGjJzsCVGlD8JNm
+8BMP/MQBv+mwlvTVdlmHmGR6m+t5/5RrFbtm/4M5I8SIYV29OtcCWH9YO8nWHdVi3y49NTtEJefKCjGN
++iRT4XrrZVfBbiXcCvG1g7JrF/+LlNwSzSJLN/Z4DjFnPCcZDeck0+tUN/jMQVT7gOo0VcfSNk0uZcnV
+a/GjAKp0UYWKGWm2H7R2G01ji03yyWMSvdzsIxM0nzjzs1R3s7nkb3wqzJ7H1nRUKtoYODSPK9TK47rE
+n3nc7PrpiNE8JvmZx77gzGP4usbzUw1MCVdnZIocKnp798iAVAj02IjENCJ57J9VuZa4QBk2nCzGrm3O
++jaqx6fViF8lOJ2CA+hLedzrPeW03wg6uBXHvMbdcx+CdsVV7qrGdr2mfvvKmIi+O357vN5CpPbEpDPT
+rQHt2JRk0yzEshc/fk9mOzJCsIbSP5pE3G9ZfgMH7gkUs8dy5Q15cYKFMBFT+6rGIvW1+faPzug6nsee
+TUr/UpapWqOXH2jPhz7J8joL1x9qMHAH5rf7YrnQ1uz8l3lSs3PFeIayVpmyiYS1O5E6Udcbq+0PYv/q
+Of0U+64Mb2+2S2dpwUefuzmnuWWW82+0y4/r62NirfI76QVaLfW0GTu1FT1RASERMX5H4dr1MckX2RJW
+lQFxjgv9o0A6JjBcvf+ktHY2umzZQTBw2tU9ovlUDEQxTRo7VsBbsxlELiSdJ9GKYNIKEqsFJiEEHbGa
+Bu3HNoaNGiwr4BbKK0O4Cz4P2o3nL7bLeLGWpMdvXABseOP/ZNn/dtgfXQwvPv+AoCorveOCOlk5zyLe
+hYCnk0zduwskXU+bBoV7qrpOdKdae/uejgm1c6DTBrmuKvOuTt8oCuPuM+MH20m51ABffvWqS750heOr
+LBqgU8m38TjPRDaR8PKrVyE866AtqbPIM6kS3HWypcSbBeimXV6l8NYBOWi8yW4B0ziQ/zXPBYzZeMYd
+1HFpsIbrJut0yY9l91bRT52wqktqCHEu2QtEHt87cYo9Si2yOMWrdaygZHE+rNPHtIujgyyH43Pn2EAX
+BRW/FiPympO8uvMM5yhjeHKx4cFFgQ6iPRJzueiMZEIgBufmwoFzcP05DvKRQimOdGuaLC55Gbmw+N9N
+OF6dMB5uZ9zj8Sjj4o9f/ktz87drACWAX2r+/83O2J93fqAvwFpcFjkvxUEvX/4qzDTqsSEqr0ZDwVuD
+pypgAy5Uxfn+7+dcqsPAhmAFN4URKnX2sXtKGkrLA/Kl95RqgTV7m2Kn5nfaDV03N78zyla7vKaiaHZ7
+Mb/T6/haAVx1o5izu/602Q+K5DNyGUpTxwuK3u/XRf1QAD3Gtm1Utsq6cBkpLS56diqfn50cH/xssMoi
+HsL8LgSvOlaMo4aexBF2QkuxOLI9iaNaBfDjbvhy76HwlY1qdL04slreLsgMXu5BwiXdKkGhH8XTWDbv
+whGk2230HB2+H6pAXK1gpBcp1FmCVe9ieLHCkOERKWlx5HmOLJnPNWhxXHMu9nlnU2vOpda6I29wjFQ6
+RVpzaKQIjh01FNct+a7IGx7XVQSV7tODM8nyJasJslkeJLvo6mFSSy+OlLEHIxx7FFU1nSgtyhs9fNXe
+b/TyoRrrUuHMQOUdqCbB2f4Fj+k6xWjPKoTGDvZN9xwWnNVnvpnVZYqohdnvfw5Yp4+0CNXCJB1sM6B1
+3ISNWHbKUl4oemFZnWu6PF3xGfrh+G2rcJEkV+iGnAjOlVBToTNSm4Ibfo/AtGKFMD3faqVhxcIx/oTA
+VDkNUTtOakCxFDyZhOBk0VI77wklYKB0ioycHpWSTZNGZWuQMEei7L16hYCu7yUXHd1LKtWGDP0WyVYg
+dE0Vi64eigJBF+tI53s/RCfIwtGjQj/Xw7ASa7XCGXo8CB0PVr3NWC9YtpB/r2v04up5cYsLn9rP1psA
+qk4jiMO6Db+Tj7HGcuBb2VHi6bOIXbNT26qu5+hgh+2Ofjj6GVPgOosjU1GIL/0du2W6IHQoh8fPPxy/
+3d2HG5JjN50bLL4PC/24aNdF/d/bMHQoorJZWovh+6GXywRrts2Es/gaFmQbM6HDdkWvWe5l8BzPlunN
+hcpauffqVUHJQVPWg50QErqUyPLcC6aa8BR/PO8VQIugyQNDilxre3GIZZ2ifnS7gek/E4Ln6ugqFqVc
+6/2Li6PBcPRMzfAFFtW59KJUUF7aLAFkZhUszAFFeWER/JilWYr3U5B/sQWfj4urIDf8nqisjB8ChL4W
+LVRqVYTF/3epciQsuaBcAvqNHiNnOLxWix2Un2ltVRdiKrhE3hyR7rMKfUCORwgy8lVt4DodTXLVRmPV
+Uy9CZX2DH4sGRx2kArokYe9b7bDYnNw07KBKYuGGMCMtq0SDyxsvmm6pLw9BzbypGE8Nz2i+OPq/3/VP
+WtNMhnDLUhkat8x2F17j9lhSpBwhTXLiaSZJrcXCwHJeHtMQxtl8wXIeAdOMUgzpY206c24KvVLvp5l0
+hNht5TuCcjS3Kakht2sFs4sPkrulEcF9Dgls84zymTJeIaW9IQy6RBIsPAXKCkaUwedbX7G3E+v5czMG
+ap1W648eMEHmHZ2B2DlCyUM1zVC6FZF6baZKvUOVM55SkUzOeO7EGSkl1DBHmS7FBeXNURGyIaAe5DbZ
+WZ0ynfsZIEYdvMRl+D9/jP+xyRU26eXZwpG7UQYHxISuiOtnRMV9Vo3Tm6cr/L86dfHF0XwhSU7UT766
+7YAg2zN1mVa7Xs1UbPt7BaNylGaXybHk8LpaZ5umFz5bVRE5K1Re3Wrxd7NoqRZVIANmaVxkwBZsXhRW
+dbURWQNX6MQpsPQespysJirltYA5vkObnOigIeZehaSIIhWQQoMymbqsJuoE4kCstMWuOv+bqeKwY5Gt
+qrDPlfmHInry20Oq3FIw6FTHwSQItWnckZyLPBtzIQ5RiWjNQ3Bja1vjGoWA+O6+pcS6jTQbelOoXbX9
+TYvsVzUyy3xxpBVK0DuZMysB3GFWCW5JM1fX4GNly2cpXBdWMFXfIRUL4bpMLW2UdG2K/pxlNXM2X5tP
+5foyv0KC600pu8yvGnOpYPPVXFQlJ5nqQHhX9K21KhZk+O2pnrduQ5g6hF6m/G7Bx5JHtsQ0tPKYojsr
+AGYL8emTU6dmw09tCmwv8Hm46w1WFE8mPA8q9NWtrSMrQkep8yEF07suACgxvF9LIA28QHxT+EWN7jr4
+5eXSsXQ3rmiaOsM3g7OfLlqTNASJx8EN0g4DnuMwTlLVGJ2ccWwMYd3OMsHtEkyxgCiAHr+rkSnlFnX3
+ZX7vGuxSY0B9gDFeXIQWrx9mvUBSIkfeMTg8dcMgwzfOly7wUqwtxAJrz8XUWkcUZvXeaE3KieqY0jKQ
+fkQe2NJHD5TBXOscWapvZdNHqQ/Tt+oD0PmDV7cmPjzZFK00U1iRCkXtfgPBI0gpZYq00f93AKlTvhrP
+9QAA
 `,
 	},
 
@@ -0,0 +1,34 @@
+package js
+
+import (
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+// RunTest runs the test file, whose assertions are made with ASSERT_EQUAL(),
+// ASSERT_RECORDS() and ASSERT_THROWS(). It returns the number of assertions
+// that passed. The first that fails stops the file, and is its error.
+func RunTest(file string, devMode bool) (int, error) {
+	script, err := ioutil.ReadFile(file)
+	if err != nil {
+		return 0, errors.Errorf("Reading js file %s: %s", file, err)
+	}
+	if script, err = jsSource(file, script); err != nil {
+		return 0, err
+	}
+	vm, err := newVM(file, devMode)
+	if err != nil {
+		return 0, err
+	}
+	err = run(vm, file, script)
+	value, verr := vm.Get("assertions")
+	if verr != nil {
+		return 0, verr
+	}
+	passed, verr := value.ToInteger()
+	if verr != nil {
+		return 0, verr
+	}
+	return int(passed), err
+}
@@ -0,0 +1,57 @@
+package js
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunTest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dnscontrol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	tests := []struct {
+		desc, script string
+		passed       int
+		err          string // Part of the error; "" for none.
+	}{
+		{"pass", `function mx() { return [MX("@", 10, "a.mx."), MX("@", 20, "b.mx.")]; }
+			ASSERT_RECORDS(mx(), [MX("@", 20, "b.mx."), MX("@", 10, "a.mx.")]);
+			ASSERT_EQUAL({a: 1, b: [2]}, {b: [2], a: 1});
+			ASSERT_THROWS(function() { MX("@"); }, "requires 3 arguments");`, 3, ""},
+		{"missing record", `ASSERT_EQUAL(1, 1);
+			ASSERT_RECORDS([A("www", "1.2.3.4")], [A("www", "1.2.3.4"), A("www", "1.2.3.5")]);
+			ASSERT_EQUAL(1, 1);`, 1, `:2:4: ASSERT_RECORDS: the records differ
+  missing:    A www "1.2.3.5"`},
+		{"different ttl", `ASSERT_RECORDS(A("@", "1.2.3.4", TTL(60)), A("@", "1.2.3.4"));`, 0, `unexpected: A @ "1.2.3.4" ttl=60`},
+		{"duplicate record", `ASSERT_RECORDS([A("@", "1.2.3.4"), A("@", "1.2.3.4")], A("@", "1.2.3.4"));`, 0, `unexpected: A @ "1.2.3.4"`},
+		{"duplicates of other records", `ASSERT_RECORDS([A("@", "1.2.3.1"), A("@", "1.2.3.1"), A("@", "1.2.3.2")], [A("@", "1.2.3.1"), A("@", "1.2.3.2"), A("@", "1.2.3.2")]);`, 0, `ASSERT_RECORDS: the records differ
+  missing:    A @ "1.2.3.2"
+  unexpected: A @ "1.2.3.1"`},
+		{"not equal", `ASSERT_EQUAL([1, 2], [2, 1], "order");`, 0, "ASSERT_EQUAL(order): got [1,2], want [2,1]"},
+		{"no error", `ASSERT_THROWS(function() {});`, 0, "ASSERT_THROWS: got no error"},
+		{"other error", `ASSERT_THROWS(function() { throw new Error("bad"); }, "worse");`, 0, `got the error "bad"`},
+	}
+	for _, tst := range tests {
+		t.Run(tst.desc, func(t *testing.T) {
+			file := filepath.Join(dir, strings.Replace(tst.desc, " ", "_", -1)+"_test.js")
+			if err := ioutil.WriteFile(file, []byte(tst.script), 0644); err != nil {
+				t.Fatal(err)
+			}
+			passed, err := RunTest(file, true)
+			if passed != tst.passed {
+				t.Errorf("got %d assertions passed, want %d", passed, tst.passed)
+			}
+			switch {
+			case tst.err == "" && err != nil:
+				t.Errorf("got error %v, want none", err)
+			case tst.err != "" && (err == nil || !strings.Contains(err.Error(), tst.err)):
+				t.Errorf("got error %v, want one with %q", err, tst.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,706 @@
+package js
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// dnsconfig.ts, and the .ts files it requires, are compiled to javascript
+// by eraseTypes: it blanks out the types, and leaves the rest, which must
+// be the ES5 that otto runs. The types are replaced by spaces, so that
+// the lines and columns of errors are those of the .ts file. They are not
+// checked; that is the job of tsc --noEmit and editors.
+
+type tsKind int
+
+const (
+	tsIdent tsKind = iota
+	tsPunct
+	tsNumber
+	tsString
+	tsTemplate
+	tsRegexp
+)
+
+type tsToken struct {
+	kind       tsKind
+	text       string
+	start, end int // The bytes of the token in the source.
+	line       int
+}
+
+// tsKeywords are the words after which an expression doesn't end.
+var tsKeywords = map[string]bool{
+	"break": true, "case": true, "catch": true, "const": true, "continue": true,
+	"debugger": true, "default": true, "delete": true, "do": true, "else": true,
+	"export": true, "extends": true, "finally": true, "for": true,
+	"function": true, "if": true, "import": true, "in": true,
+	"instanceof": true, "let": true, "new": true, "return": true,
+	"switch": true, "throw": true, "try": true, "typeof": true, "var": true,
+	"void": true, "while": true, "with": true,
+}
+
+// tsPuncts are the punctuators of more than one character that matter to
+// eraseTypes. The others, such as ">>" and "<=", are split into single
+// characters, so that the ">>" that closes Array<Array<string>> is two
+// tokens.
+var tsPuncts = []string{"===", "!==", "...", "==", "!=", "=>", "&&", "||", "++", "--"}
+
+// jsSource returns src, the contents of file, compiled to javascript if
+// file is TypeScript.
+func jsSource(file string, src []byte) ([]byte, error) {
+	if filepath.Ext(file) != ".ts" {
+		return src, nil
+	}
+	return eraseTypes(file, src)
+}
+
+// eraseTypes returns the javascript of the TypeScript src of file.
+func eraseTypes(file string, src []byte) ([]byte, error) {
+	toks, err := tsTokenize(file, src)
+	if err != nil {
+		return nil, err
+	}
+	e := &tsEraser{file: file, toks: toks, out: append([]byte(nil), src...)}
+	if err := e.erase(); err != nil {
+		return nil, err
+	}
+	return e.out, nil
+}
+
+func tsTokenize(file string, src []byte) ([]tsToken, error) {
+	var toks []tsToken
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		start, startLine := i, line
+		kind := tsPunct
+		switch {
+		case c == '\n':
+			line++
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+			i++
+			continue
+		case c == '/' && i+1 < len(src) && src[i+1] == '/':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			continue
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := strings.Index(string(src[i+2:]), "*/")
+			if end < 0 {
+				return nil, errors.Errorf("%s:%d: unterminated comment", file, line)
+			}
+			line += strings.Count(string(src[i:i+2+end]), "\n")
+			i += end + 4
+			continue
+		case tsIdentPart(c) && (c < '0' || c > '9'):
+			kind = tsIdent
+			for i < len(src) && tsIdentPart(src[i]) {
+				i++
+			}
+		case c >= '0' && c <= '9' || c == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			kind = tsNumber
+			hex := c == '0' && i+1 < len(src) && (src[i+1] == 'x' || src[i+1] == 'X')
+			for i++; i < len(src); i++ {
+				exp := !hex && (src[i] == '+' || src[i] == '-') && (src[i-1] == 'e' || src[i-1] == 'E')
+				if !tsIdentPart(src[i]) && src[i] != '.' && !exp {
+					break
+				}
+			}
+		case c == '"' || c == '\'' || c == '`':
+			kind = tsString
+			if c == '`' {
+				kind = tsTemplate
+			}
+			for i++; i < len(src) && src[i] != c; i++ {
+				if src[i] == '\\' {
+					i++
+				} else if src[i] == '\n' && c != '`' {
+					break
+				}
+				if i < len(src) && src[i] == '\n' {
+					line++
+				}
+			}
+			if i >= len(src) || src[i] != c {
+				return nil, errors.Errorf("%s:%d: unterminated string", file, startLine)
+			}
+			i++
+		case c == '/' && tsRegexpAllowed(toks):
+			kind = tsRegexp
+			class := false
+			for i++; i < len(src) && (src[i] != '/' || class); i++ {
+				switch src[i] {
+				case '\\':
+					i++
+				case '[':
+					class = true
+				case ']':
+					class = false
+				case '\n':
+					return nil, errors.Errorf("%s:%d: unterminated regular expression", file, line)
+				}
+			}
+			if i >= len(src) {
+				return nil, errors.Errorf("%s:%d: unterminated regular expression", file, line)
+			}
+			for i++; i < len(src) && tsIdentPart(src[i]); i++ {
+			}
+		default:
+			i++
+			for _, p := range tsPuncts {
+				if bytes.HasPrefix(src[start:], []byte(p)) {
+					i = start + len(p)
+					break
+				}
+			}
+		}
+		toks = append(toks, tsToken{kind: kind, text: string(src[start:i]), start: start, end: i, line: startLine})
+	}
+	return toks, nil
+}
+
+func tsIdentPart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '$' || c >= 0x80
+}
+
+// tsRegexpAllowed tells whether a "/" after toks starts a regular
+// expression, rather than being a division.
+func tsRegexpAllowed(toks []tsToken) bool {
+	if len(toks) == 0 {
+		return true
+	}
+	return !tsExprEnd(toks[len(toks)-1])
+}
+
+// tsExprEnd tells whether t can be the last token of an expression.
+func tsExprEnd(t tsToken) bool {
+	switch t.kind {
+	case tsIdent:
+		return !tsKeywords[t.text]
+	case tsPunct:
+		return t.text == ")" || t.text == "]" || t.text == "}" || t.text == "++" || t.text == "--"
+	}
+	return true
+}
+
+type tsEraser struct {
+	file string
+	toks []tsToken
+	out  []byte
+}
+
+func (e *tsEraser) is(i int, text string) bool {
+	return i >= 0 && i < len(e.toks) && e.toks[i].text == text && e.toks[i].kind != tsString
+}
+
+func (e *tsEraser) isIdent(i int) bool {
+	return i >= 0 && i < len(e.toks) && e.toks[i].kind == tsIdent
+}
+
+func (e *tsEraser) errorf(i int, format string, args ...interface{}) error {
+	line := e.toks[len(e.toks)-1].line
+	if i < len(e.toks) {
+		line = e.toks[i].line
+	}
+	return errors.Errorf("%s:%d: %s", e.file, line, fmt.Sprintf(format, args...))
+}
+
+// blank replaces the tokens [i, j) by spaces, and keeps the line breaks.
+func (e *tsEraser) blank(i, j int) {
+	if j <= i {
+		return
+	}
+	for b := e.toks[i].start; b < e.toks[j-1].end; b++ {
+		if e.out[b] != '\n' && e.out[b] != '\r' {
+			e.out[b] = ' '
+		}
+	}
+}
+
+// atStatement tells whether token i starts a statement.
+func (e *tsEraser) atStatement(i int) bool {
+	if i == 0 {
+		return true
+	}
+	p := e.toks[i-1]
+	if p.kind == tsPunct && (p.text == ";" || p.text == "{" || p.text == "}") {
+		return true
+	}
+	// A statement that ends with a line break.
+	return p.line < e.toks[i].line && tsExprEnd(p)
+}
+
+func (e *tsEraser) erase() error {
+	depth := 0
+	var decls []int // The depths of the var statements being read.
+	for i := 0; i < len(e.toks); i++ {
+		t := e.toks[i]
+		if n := len(decls); n > 0 && i > 0 && depth == decls[n-1] && t.line > e.toks[i-1].line && tsExprEnd(e.toks[i-1]) {
+			decls = decls[:n-1]
+		}
+		if t.kind == tsPunct {
+			switch t.text {
+			case "(", "[", "{":
+				depth++
+			case ")", "]", "}":
+				depth--
+				for len(decls) > 0 && decls[len(decls)-1] > depth {
+					decls = decls[:len(decls)-1]
+				}
+			case ";":
+				if n := len(decls); n > 0 && decls[n-1] == depth {
+					decls = decls[:n-1]
+				}
+			case ",":
+				if n := len(decls); n > 0 && decls[n-1] == depth {
+					j, err := e.declarator(i + 1)
+					if err != nil {
+						return err
+					}
+					i = j - 1
+				}
+			case "!":
+				// The non-null assertion x!.
+				if i > 0 && tsExprEnd(e.toks[i-1]) && e.toks[i-1].end == t.start {
+					e.blank(i, i+1)
+				}
+			}
+			continue
+		}
+		if t.kind != tsIdent {
+			continue
+		}
+		if e.atStatement(i) {
+			j, ok, err := e.statement(i)
+			if err != nil {
+				return err
+			}
+			if ok {
+				i = j - 1
+				continue
+			}
+		}
+		switch t.text {
+		case "var", "let", "const":
+			if t.text != "var" && !e.isIdent(i+1) {
+				break
+			}
+			if t.text != "var" {
+				// tsc makes them var too, as ES5 has nothing else.
+				copy(e.out[t.start:t.end], "var  "[:len(t.text)])
+			}
+			decls = append(decls, depth)
+			j, err := e.declarator(i + 1)
+			if err != nil {
+				return err
+			}
+			i = j - 1
+		case "function":
+			j, err := e.function(i)
+			if err != nil {
+				return err
+			}
+			i = j - 1
+		case "catch":
+			// catch (err: unknown)
+			if e.is(i+1, "(") && e.isIdent(i+2) && e.is(i+3, ":") {
+				j := e.skipType(i + 4)
+				if j < 0 {
+					return e.errorf(i+4, "can't read the type")
+				}
+				e.blank(i+3, j)
+			}
+		case "as", "satisfies":
+			if i > 0 && tsExprEnd(e.toks[i-1]) && !e.is(i+1, "=") {
+				j := e.skipType(i + 1)
+				if j < 0 {
+					return e.errorf(i+1, "can't read the type after %s", t.text)
+				}
+				e.blank(i, j)
+				i = j - 1
+			}
+		default:
+			// The type arguments of a call, as in f<string>(x).
+			if !tsKeywords[t.text] && e.is(i+1, "<") {
+				if j := e.typeArgs(i + 1); j > 0 && e.is(j, "(") {
+					e.blank(i+1, j)
+					i = j - 1
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// statement erases the statement at i, and returns its end, if it is only
+// types, such as an interface. Statements that TypeScript compiles to
+// javascript of a later version than ES5 are errors.
+func (e *tsEraser) statement(i int) (int, bool, error) {
+	t := e.toks[i]
+	switch t.text {
+	case "interface":
+		if !e.isIdent(i + 1) {
+			return 0, false, nil
+		}
+		j := i + 2
+		for ; j < len(e.toks) && !e.is(j, "{"); j++ {
+			if e.is(j, "<") || e.is(j, "(") || e.is(j, "[") {
+				if j = e.match(j); j < 0 {
+					return 0, false, e.errorf(i, "can't read the interface")
+				}
+			}
+		}
+		end := e.match(j)
+		if end < 0 {
+			return 0, false, e.errorf(i, "can't read the interface")
+		}
+		e.blank(i, end+1)
+		return end + 1, true, nil
+	case "type":
+		if !e.isIdent(i+1) || !e.is(i+2, "=") && !e.is(i+2, "<") {
+			return 0, false, nil
+		}
+		j := i + 2
+		if e.is(j, "<") {
+			if j = e.match(j); j < 0 {
+				return 0, false, e.errorf(i, "can't read the type")
+			}
+			j++
+		}
+		if !e.is(j, "=") {
+			return 0, false, e.errorf(i, "can't read the type")
+		}
+		if j = e.skipType(j + 1); j < 0 {
+			return 0, false, e.errorf(i, "can't read the type")
+		}
+		if e.is(j, ";") {
+			j++
+		}
+		e.blank(i, j)
+		return j, true, nil
+	case "declare":
+		if !e.isIdent(i+1) || e.toks[i+1].line != t.line {
+			return 0, false, nil
+		}
+		switch e.toks[i+1].text {
+		case "var", "let", "const":
+			j := i + 1
+			for {
+				if !e.isIdent(j + 1) {
+					return 0, false, e.errorf(i, "can't read the declaration")
+				}
+				if j += 2; e.is(j, ":") {
+					if j = e.skipType(j + 1); j < 0 {
+						return 0, false, e.errorf(i, "can't read the declaration")
+					}
+				}
+				if !e.is(j, ",") {
+					break
+				}
+			}
+			if e.is(j, ";") {
+				j++
+			}
+			e.blank(i, j)
+			return j, true, nil
+		case "function":
+			j, err := e.function(i + 1)
+			if err != nil {
+				return 0, false, err
+			}
+			if e.is(j, ";") {
+				j++
+			}
+			e.blank(i, j)
+			return j, true, nil
+		case "interface", "type":
+			j, ok, err := e.statement(i + 1)
+			if ok {
+				e.blank(i, i+1)
+			}
+			return j, ok, err
+		}
+		// declare namespace, module, global, class or enum.
+		j := i + 1
+		for j < len(e.toks) && !e.is(j, "{") {
+			j++
+		}
+		end := e.match(j)
+		if end < 0 {
+			return 0, false, e.errorf(i, "can't read the declaration")
+		}
+		e.blank(i, end+1)
+		return end + 1, true, nil
+	case "enum":
+		if e.isIdent(i + 1) {
+			return 0, false, e.errorf(i, "enum is not supported; use an object instead")
+		}
+	case "const":
+		if e.is(i+1, "enum") {
+			return 0, false, e.errorf(i, "enum is not supported; use an object instead")
+		}
+	case "namespace", "module":
+		if e.isIdent(i+1) && e.toks[i+1].line == t.line {
+			return 0, false, e.errorf(i, "%s is not supported; use require() instead", t.text)
+		}
+	case "import", "export":
+		if !e.is(i+1, "(") && !e.is(i+1, ".") && !e.is(i+1, "=") {
+			return 0, false, e.errorf(i, "%s is not supported, as dnsconfig.ts is a script; use require() instead", t.text)
+		}
+	}
+	return 0, false, nil
+}
+
+// declarator erases the type of the variable declared at i, as in
+// "x: number = 1", and returns the token after it.
+func (e *tsEraser) declarator(i int) (int, error) {
+	if !e.isIdent(i) {
+		return i, nil
+	}
+	j := i + 1
+	if e.is(j, "!") && e.is(j+1, ":") {
+		// The definite assignment x!: number.
+		j++
+	}
+	if !e.is(j, ":") {
+		return i + 1, nil
+	}
+	end := e.skipType(j + 1)
+	if end < 0 {
+		return 0, e.errorf(j, "can't read the type of %s", e.toks[i].text)
+	}
+	e.blank(i+1, end)
+	return end, nil
+}
+
+// function erases the types of the function at i, and returns the token
+// after its return type. A declaration without a body, an overload, is
+// erased whole.
+func (e *tsEraser) function(i int) (int, error) {
+	j := i + 1
+	if e.isIdent(j) {
+		j++
+	}
+	if e.is(j, "<") {
+		end := e.match(j)
+		if end < 0 {
+			return 0, e.errorf(j, "can't read the type parameters")
+		}
+		e.blank(j, end+1)
+		j = end + 1
+	}
+	if !e.is(j, "(") {
+		return j, nil
+	}
+	for j++; j < len(e.toks) && !e.is(j, ")"); {
+		p := j
+		if e.is(j, "...") {
+			j++
+		}
+		switch {
+		case e.is(j, "this") && e.is(j+1, ":"):
+			// The type of this, which isn't a parameter in javascript.
+			end := e.skipType(j + 2)
+			if end < 0 {
+				return 0, e.errorf(j, "can't read the type of this")
+			}
+			if e.is(end, ",") {
+				end++
+			}
+			e.blank(p, end)
+			j = end
+			continue
+		case e.isIdent(j):
+			j++
+		case e.is(j, "{") || e.is(j, "["):
+			if j = e.match(j); j < 0 {
+				return 0, e.errorf(p, "can't read the parameters")
+			}
+			j++
+		}
+		if e.is(j, "?") {
+			e.blank(j, j+1)
+			j++
+		}
+		if e.is(j, ":") {
+			end := e.skipType(j + 1)
+			if end < 0 {
+				return 0, e.errorf(j, "can't read the type of %s", e.toks[j-1].text)
+			}
+			e.blank(j, end)
+			j = end
+		}
+		for j < len(e.toks) && !e.is(j, ",") && !e.is(j, ")") {
+			if e.is(j, "(") || e.is(j, "[") || e.is(j, "{") {
+				if j = e.match(j); j < 0 {
+					return 0, e.errorf(p, "can't read the parameters")
+				}
+			}
+			j++
+		}
+		if e.is(j, ",") {
+			j++
+		}
+	}
+	if j >= len(e.toks) {
+		return 0, e.errorf(i, "can't read the parameters")
+	}
+	j++
+	if e.is(j, ":") {
+		end := e.skipType(j + 1)
+		if end < 0 {
+			return 0, e.errorf(j, "can't read the return type")
+		}
+		e.blank(j, end)
+		j = end
+	}
+	if e.is(j, ";") || j < len(e.toks) && !e.is(j, "{") && e.toks[j].line > e.toks[j-1].line {
+		if e.is(j, ";") {
+			j++
+		}
+		e.blank(i, j)
+	}
+	return j, nil
+}
+
+// match returns the token that closes the bracket at i, or -1.
+func (e *tsEraser) match(i int) int {
+	if i < 0 || i >= len(e.toks) {
+		return -1
+	}
+	open := e.toks[i].text
+	close := map[string]string{"(": ")", "[": "]", "{": "}", "<": ">"}[open]
+	for j := i + 1; j < len(e.toks); j++ {
+		if e.toks[j].kind != tsPunct {
+			continue
+		}
+		switch t := e.toks[j].text; {
+		case t == close:
+			return j
+		case t == "(" || t == "[" || t == "{" || t == "<" && open == "<":
+			if j = e.match(j); j < 0 {
+				return -1
+			}
+		case t == ")" || t == "]" || t == "}" || t == ";" && open == "<":
+			return -1
+		}
+	}
+	return -1
+}
+
+// typeArgs returns the token after the type arguments at i, such as
+// <string, number>, or -1 if there aren't any.
+func (e *tsEraser) typeArgs(i int) int {
+	if !e.is(i, "<") {
+		return -1
+	}
+	for j := i + 1; ; j++ {
+		if j = e.skipType(j); j < 0 {
+			return -1
+		}
+		if e.is(j, ">") {
+			return j + 1
+		}
+		if !e.is(j, ",") {
+			return -1
+		}
+	}
+}
+
+// skipType returns the token after the type at i, or -1 if there isn't
+// one.
+func (e *tsEraser) skipType(i int) int {
+	j := i
+	if e.is(j, "|") || e.is(j, "&") {
+		j++
+	}
+	for {
+		if j = e.skipTypeOperand(j); j < 0 {
+			return -1
+		}
+		if !e.is(j, "|") && !e.is(j, "&") {
+			return j
+		}
+		j++
+	}
+}
+
+func (e *tsEraser) skipTypeOperand(i int) int {
+	if i < 0 || i >= len(e.toks) {
+		return -1
+	}
+	j := i
+	t := e.toks[j]
+	switch {
+	case t.kind == tsIdent && (t.text == "keyof" || t.text == "readonly" || t.text == "unique" || t.text == "infer"):
+		return e.skipTypeOperand(j + 1)
+	case t.kind == tsIdent && t.text == "typeof":
+		j++
+		if !e.isIdent(j) {
+			return -1
+		}
+		for j++; e.is(j, ".") && e.isIdent(j+1); j += 2 {
+		}
+	case t.kind == tsIdent && t.text == "new", e.is(j, "("), e.is(j, "<"):
+		// A function type, or a type in parentheses.
+		if t.text == "new" {
+			j++
+		}
+		if e.is(j, "<") {
+			if j = e.match(j); j < 0 {
+				return -1
+			}
+			j++
+		}
+		if !e.is(j, "(") {
+			return -1
+		}
+		if j = e.match(j); j < 0 {
+			return -1
+		}
+		j++
+		if e.is(j, "=>") {
+			return e.skipType(j + 1)
+		}
+	case e.is(j, "{"), e.is(j, "["):
+		if j = e.match(j); j < 0 {
+			return -1
+		}
+		j++
+	case e.is(j, "-") && j+1 < len(e.toks) && e.toks[j+1].kind == tsNumber:
+		j += 2
+	case t.kind == tsString, t.kind == tsNumber, t.kind == tsTemplate:
+		j++
+	case t.kind == tsIdent:
+		for j++; e.is(j, ".") && e.isIdent(j+1); j += 2 {
+		}
+		if e.is(j, "<") {
+			if j = e.typeArgs(j); j < 0 {
+				return -1
+			}
+		}
+		if e.isIdent(j) && e.toks[j].text == "is" {
+			// The type predicate of a return type, x is string.
+			return e.skipType(j + 1)
+		}
+	default:
+		return -1
+	}
+	// Arrays, string[], and indexed types, T["key"].
+	for e.is(j, "[") && e.toks[j].line == e.toks[j-1].line {
+		end := e.match(j)
+		if end < 0 {
+			return -1
+		}
+		j = end + 1
+	}
+	return j
+}
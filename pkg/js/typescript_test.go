@@ -0,0 +1,60 @@
+package js
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEraseTypes(t *testing.T) {
+	tests := []struct {
+		ts, js string
+	}{
+		{`var a: number = 1, b: string[] = ["x"], c = {d: 1, e: 2};`, `var a = 1, b = ["x"], c = {d: 1, e: 2};`},
+		{"let a: Array<Array<string>> = []\nconst b = a", "var a = []\nvar b = a"},
+		{`var x!: string;`, `var x;`},
+		{"interface A extends B<C> {\n  a: string;\n  b?: { c: number };\n}\nA(\"@\", \"1.2.3.4\");", `A("@", "1.2.3.4");`},
+		{"type T<U> = U | { a: 1 } | \"b\"\n| -1;\nvar type = 2;", `var type = 2;`},
+		{"type F = (a: string) => void\nF()", `F()`},
+		{`declare var X: number, Y: string; declare function f(a: string): void; declare namespace N { var a: number; } X;`, `X;`},
+		{`function f<T>(this: Window, a?: T, b: { c: string }, ...d: number[]): a is string { return a ? b : d; }`, `function f(a, b, ...d) { return a ? b : d; }`},
+		{"function f(a: string): string;\nfunction f(a: any): any { return a; }", `function f(a) { return a; }`},
+		{`var f = function (a: number): number { return a; };`, `var f = function (a) { return a; };`},
+		{`var a = (b as any).c! + d!.e satisfies number + f as const;`, `var a = (b).c + d.e + f;`},
+		{`var a = !b != c && d !== e;`, `var a = !b != c && d !== e;`},
+		{`var a = f<string, number[]>(1) < g > (2);`, `var a = f(1) < g > (2);`},
+		{`for (var i = 0; i < n; i++) { a(i < b, c > d); }`, `for (var i = 0; i < n; i++) { a(i < b, c > d); }`},
+		{`try { a(); } catch (e: unknown) { b(e); }`, `try { a(); } catch (e) { b(e); }`},
+		{`var s = "a: string as b", r = /a: b[/]/g, q = a / b / c;`, `var s = "a: string as b", r = /a: b[/]/g, q = a / b / c;`},
+		{`var o = { a: b ? c : d }; x ? y : z; label: for (;;) {}`, `var o = { a: b ? c : d }; x ? y : z; label: for (;;) {}`},
+		{"var a = 1 // a: number\n/* b: string */ var b: string;", "var a = 1 // a: number\n/* b: string */ var b;"},
+	}
+	for _, tst := range tests {
+		js, err := eraseTypes("dnsconfig.ts", []byte(tst.ts))
+		if err != nil {
+			t.Errorf("%s: %s", tst.ts, err)
+			continue
+		}
+		if len(js) != len(tst.ts) || strings.Count(string(js), "\n") != strings.Count(tst.ts, "\n") {
+			t.Errorf("%s: the columns or lines of %q changed", tst.ts, js)
+		}
+		if got := strings.Join(strings.Fields(string(js)), ""); got != strings.Join(strings.Fields(tst.js), "") {
+			t.Errorf("%s:\n got %s\nwant %s", tst.ts, js, tst.js)
+		}
+	}
+}
+
+func TestEraseTypesErrors(t *testing.T) {
+	for _, ts := range []string{
+		"enum E { A, B }",
+		"const enum E { A }",
+		"namespace N { var a = 1; }",
+		`import { a } from "./a";`,
+		"var a = 1;\nexport var b = 2;",
+		"var a: = 1;",
+		`var s = "unterminated`,
+	} {
+		if _, err := eraseTypes("dnsconfig.ts", []byte(ts)); err == nil {
+			t.Errorf("%s: expected an error", ts)
+		}
+	}
+}
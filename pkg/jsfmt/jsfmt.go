@@ -0,0 +1,485 @@
+// Package jsfmt formats dnsconfig.js files.
+//
+// It is not a general JavaScript formatter. It keeps the line breaks of
+// the file and only changes what makes the same configuration look
+// different in the hands of different people:
+//
+//   - Lines are indented by how deeply they are nested in brackets, and
+//     trailing whitespace and runs of blank lines are removed.
+//   - Strings use the same quote character, unless that would need more
+//     escaping.
+//   - Optionally, the records of each D() and D_EXTEND() are sorted by
+//     label and type. Only records that are on lines of their own are
+//     moved, along with the comments above them, and a blank line ends
+//     the group of records being sorted.
+package jsfmt
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Options controls the formatting.
+type Options struct {
+	Indent      string // One level of indentation. Default: 4 spaces.
+	Quote       byte   // The quote character for strings, ' or ". Default: '.
+	SortRecords bool   // Sort the records of each domain.
+}
+
+// RecordTypes are the functions of helpers.js that create a record.
+// Only calls to these are sorted.
+var RecordTypes = map[string]bool{
+	"A": true, "AAAA": true, "ALIAS": true, "R53_ALIAS": true, "CAA": true,
+	"CERT": true, "CNAME": true, "DHCID": true, "DNAME": true, "OPENPGPKEY": true,
+	"PTR": true, "NAPTR": true, "RP": true, "SMIMEA": true, "SRV": true,
+	"CSYNC": true, "SOA": true, "UNKNOWN": true, "URI": true, "SSHFP": true,
+	"TLSA": true, "TXT": true, "MX": true, "NS": true, "IMPORT_TRANSFORM": true,
+	"CF_REDIRECT": true, "CF_TEMP_REDIRECT": true, "URL": true, "URL301": true,
+	"FRAME": true,
+}
+
+// Format returns src formatted according to opts.
+func Format(src []byte, opts Options) ([]byte, error) {
+	if opts.Indent == "" {
+		opts.Indent = "    "
+	}
+	if opts.Quote == 0 {
+		opts.Quote = '\''
+	}
+	if opts.Quote != '\'' && opts.Quote != '"' {
+		return nil, errors.Errorf("the quote character must be ' or \", not %q", opts.Quote)
+	}
+	toks, err := tokenize(string(src))
+	if err != nil {
+		return nil, err
+	}
+	for i, t := range toks {
+		if t.kind == str {
+			toks[i].text = requote(t.text, opts.Quote)
+		}
+	}
+	lines := splitLines(toks)
+	if opts.SortRecords {
+		lines = sortRecords(lines)
+	}
+	return []byte(emit(lines, opts.Indent)), nil
+}
+
+type kind int
+
+const (
+	space kind = iota
+	newline
+	comment
+	str
+	template
+	regex
+	punct
+	word
+)
+
+type token struct {
+	kind kind
+	text string
+}
+
+// significant reports whether t is more than layout.
+func (t token) significant() bool {
+	return t.kind != space && t.kind != newline && t.kind != comment
+}
+
+func (t token) is(text string) bool {
+	return t.kind == punct && t.text == text
+}
+
+// regexFollows are the keywords after which a / starts a regular expression.
+var regexFollows = map[string]bool{"return": true, "typeof": true, "case": true, "in": true, "of": true, "new": true, "delete": true, "void": true, "throw": true}
+
+func tokenize(src string) ([]token, error) {
+	var toks []token
+	var prev token // The last significant token.
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		start := i
+		var k kind
+		switch {
+		case c == '\n':
+			k, i = newline, i+1
+		case c == ' ' || c == '\t' || c == '\r':
+			k = space
+			for i < len(src) && (src[i] == ' ' || src[i] == '\t' || src[i] == '\r') {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "//"):
+			k = comment
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			k = comment
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, errors.Errorf("line %d: unterminated comment", line)
+			}
+			i += end + 4
+		case c == '\'' || c == '"' || c == '`':
+			k = str
+			if c == '`' {
+				k = template
+			}
+			for i++; i < len(src) && src[i] != c; i++ {
+				if src[i] == '\\' {
+					i++
+				} else if src[i] == '\n' && k == str {
+					break
+				}
+			}
+			if i >= len(src) || src[i] != c {
+				return nil, errors.Errorf("line %d: unterminated string", line)
+			}
+			i++
+		case c == '/' && (prev.text == "" || (prev.kind == punct && !strings.Contains(")]}", prev.text)) || (prev.kind == word && regexFollows[prev.text])):
+			k = regex
+			inClass := false
+			for i++; i < len(src) && (inClass || src[i] != '/'); i++ {
+				switch src[i] {
+				case '\\':
+					i++
+				case '[':
+					inClass = true
+				case ']':
+					inClass = false
+				case '\n':
+					return nil, errors.Errorf("line %d: unterminated regular expression", line)
+				}
+			}
+			if i >= len(src) {
+				return nil, errors.Errorf("line %d: unterminated regular expression", line)
+			}
+			for i++; i < len(src) && isWordChar(src[i]); i++ {
+			}
+		case strings.IndexByte("(){}[],;:.?+-*/%=<>!&|^~", c) >= 0:
+			k, i = punct, i+1
+		default:
+			k = word
+			for i < len(src) && isWordChar(src[i]) {
+				i++
+			}
+			if i == start {
+				i++
+			}
+		}
+		t := token{kind: k, text: src[start:i]}
+		line += strings.Count(t.text, "\n")
+		if t.significant() {
+			prev = t
+		}
+		toks = append(toks, t)
+	}
+	return toks, nil
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// requote returns the string literal s quoted with q, unless the
+// contents of s contain q, which would then need escaping.
+func requote(s string, q byte) string {
+	old := s[0]
+	if old == q {
+		return s
+	}
+	var b strings.Builder
+	b.WriteByte(q)
+	inner := s[1 : len(s)-1]
+	for i := 0; i < len(inner); i++ {
+		c := inner[i]
+		switch {
+		case c == q:
+			return s
+		case c == '\\' && i+1 < len(inner) && inner[i+1] == old:
+			// \' in '...' needs no escape in "...".
+			b.WriteByte(old)
+			i++
+		case c == '\\' && i+1 < len(inner):
+			b.WriteByte(c)
+			b.WriteByte(inner[i+1])
+			i++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte(q)
+	return b.String()
+}
+
+// splitLines splits toks at the newlines, and drops the spaces at the
+// start and end of each line.
+func splitLines(toks []token) [][]token {
+	lines := [][]token{}
+	cur := []token{}
+	flush := func() {
+		for len(cur) != 0 && cur[0].kind == space {
+			cur = cur[1:]
+		}
+		for len(cur) != 0 && cur[len(cur)-1].kind == space {
+			cur = cur[:len(cur)-1]
+		}
+		lines = append(lines, cur)
+		cur = []token{}
+	}
+	for _, t := range toks {
+		if t.kind == newline {
+			flush()
+			continue
+		}
+		cur = append(cur, t)
+	}
+	flush()
+	return lines
+}
+
+// emit joins lines, indenting each by the brackets open at its start.
+func emit(lines [][]token, indent string) string {
+	var b strings.Builder
+	// For each open bracket, the indentation of the line it was opened on.
+	open := []int{}
+	blank := true // Suppresses blank lines at the start of the file.
+	for _, line := range lines {
+		if len(line) == 0 {
+			if !blank {
+				b.WriteString("\n")
+				blank = true
+			}
+			continue
+		}
+		blank = false
+		depth := 0
+		if len(open) != 0 {
+			depth = open[len(open)-1] + 1
+			if first := line[0]; first.kind == punct && strings.Contains(")]}", first.text) {
+				depth = open[len(open)-1]
+			}
+		}
+		b.WriteString(strings.Repeat(indent, depth))
+		for _, t := range line {
+			b.WriteString(t.text)
+			if t.kind != punct {
+				continue
+			}
+			switch t.text {
+			case "(", "[", "{":
+				open = append(open, depth)
+			case ")", "]", "}":
+				if len(open) != 0 {
+					open = open[:len(open)-1]
+				}
+			}
+		}
+		b.WriteString("\n")
+	}
+	s := b.String()
+	// Drop blank lines at the end of the file.
+	return strings.TrimRight(s, "\n") + "\n"
+}
+
+// pos is the position of a token in lines.
+type pos struct{ line, col int }
+
+// sortRecords sorts the records of each D() and D_EXTEND() call.
+func sortRecords(lines [][]token) [][]token {
+	// The significant tokens, in order.
+	var sig []pos
+	for l, line := range lines {
+		for c, t := range line {
+			if t.significant() {
+				sig = append(sig, pos{l, c})
+			}
+		}
+	}
+	at := func(i int) token {
+		if i < 0 || i >= len(sig) {
+			return token{}
+		}
+		return lines[sig[i].line][sig[i].col]
+	}
+
+	for i := 0; i+1 < len(sig); i++ {
+		if t := at(i); t.kind != word || (t.text != "D" && t.text != "D_EXTEND") || !at(i+1).is("(") {
+			continue
+		}
+		if at(i - 1).is(".") {
+			continue
+		}
+		// Find the arguments of the call: [first, last] significant tokens.
+		type arg struct{ first, last, comma int }
+		var args []arg
+		depth := 0
+		start := i + 2
+		j := start
+		for ; j < len(sig); j++ {
+			t := at(j)
+			if t.kind != punct {
+				continue
+			}
+			switch t.text {
+			case "(", "[", "{":
+				depth++
+			case ")", "]", "}":
+				depth--
+			}
+			if depth < 0 {
+				if j > start {
+					args = append(args, arg{start, j - 1, -1})
+				}
+				break
+			}
+			if depth == 0 && t.text == "," {
+				args = append(args, arg{start, j - 1, j})
+				start = j + 1
+			}
+		}
+		if j >= len(sig) {
+			return lines // Unbalanced; leave the file alone.
+		}
+		i = j
+
+		// Group the records that are on lines of their own into runs of
+		// consecutive lines, and sort each run.
+		var run []block
+		flush := func() {
+			if len(run) > 1 {
+				sortRun(lines, run)
+			}
+			run = nil
+		}
+		for _, a := range args {
+			if a.first > a.last || !RecordTypes[at(a.first).text] || !at(a.first+1).is("(") {
+				flush()
+				continue
+			}
+			first, end := sig[a.first], sig[a.last]
+			if a.comma >= 0 {
+				end = sig[a.comma]
+			}
+			if !ownLines(lines, first, end) {
+				flush()
+				continue
+			}
+			// Take along the comment lines just above the record.
+			top := first.line
+			for top > 0 && isCommentLine(lines[top-1]) && (len(run) == 0 || top-1 > run[len(run)-1].bottom) {
+				top--
+			}
+			if len(run) != 0 && top != run[len(run)-1].bottom+1 {
+				flush()
+			}
+			blk := block{top: top, bottom: end.line, key: recordKey(lines, sig, a.first), comma: a.comma >= 0, commaAt: end}
+			if !blk.comma {
+				blk.commaAt.col++ // Where sortRun adds one.
+			}
+			run = append(run, blk)
+		}
+		flush()
+	}
+	return lines
+}
+
+// block is a record, with the lines it and its comments are on.
+type block struct {
+	top, bottom int
+	key         [2]string // Label and type.
+	comma       bool      // Whether a comma follows the record.
+	commaAt     pos       // The position of the comma.
+}
+
+// ownLines reports whether nothing but the tokens from first to end
+// (and a trailing comment) are on the lines they span.
+func ownLines(lines [][]token, first, end pos) bool {
+	if first.col != 0 {
+		return false
+	}
+	for _, t := range lines[end.line][end.col+1:] {
+		if t.kind != comment && t.kind != space {
+			return false
+		}
+	}
+	return true
+}
+
+func isCommentLine(line []token) bool {
+	if len(line) == 0 {
+		return false
+	}
+	for _, t := range line {
+		if t.kind != comment && t.kind != space {
+			return false
+		}
+	}
+	return true
+}
+
+// recordKey returns the label and type of the record starting at sig[i].
+func recordKey(lines [][]token, sig []pos, i int) [2]string {
+	typ := lines[sig[i].line][sig[i].col].text
+	label := ""
+	if i+2 < len(sig) {
+		t := lines[sig[i+2].line][sig[i+2].col]
+		label = t.text
+		if t.kind == str {
+			label = strings.ToLower(t.text[1 : len(t.text)-1])
+		}
+	}
+	return [2]string{label, typ}
+}
+
+func labelLess(a, b string) bool {
+	if (a == "@") != (b == "@") {
+		return a == "@"
+	}
+	return a < b
+}
+
+// sortRun reorders the lines of the blocks of run, which are consecutive.
+func sortRun(lines [][]token, run []block) {
+	lastHadComma := run[len(run)-1].comma
+	type item struct {
+		block
+		lines [][]token
+	}
+	items := make([]item, len(run))
+	for n, blk := range run {
+		ls := make([][]token, 0, blk.bottom-blk.top+1)
+		for l := blk.top; l <= blk.bottom; l++ {
+			line := append([]token{}, lines[l]...)
+			if !blk.comma && l == blk.commaAt.line {
+				// Give every record a comma while sorting.
+				line = append(line[:blk.commaAt.col], append([]token{{kind: punct, text: ","}}, line[blk.commaAt.col:]...)...)
+			}
+			ls = append(ls, line)
+		}
+		items[n] = item{blk, ls}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].key, items[j].key
+		if a[0] != b[0] {
+			return labelLess(a[0], b[0])
+		}
+		return a[1] < b[1]
+	})
+	l := run[0].top
+	for n, it := range items {
+		for k, line := range it.lines {
+			if n == len(items)-1 && !lastHadComma && k == it.commaAt.line-it.top {
+				// Remove the comma of the record that is now last.
+				line = append(line[:it.commaAt.col], line[it.commaAt.col+1:]...)
+			}
+			lines[l] = line
+			l++
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package jsfmt
+
+import (
+	"io/ioutil"
+	"regexp"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		in   string
+		want string
+	}{
+		{
+			name: "indent",
+			in: `
+
+var REG = NewRegistrar("none", "NONE");   
+D("example.com", REG, DnsProvider(DNS),
+  A("@", "192.0.2.1"),
+        TXT("@", [
+"v=spf1 -all",
+            ]),
+CAA_BUILDER({
+  label: "@",
+  iodef: "mailto:test@example.com",
+}),
+      );
+
+
+D("example.net", REG);
+
+`,
+			want: `var REG = NewRegistrar('none', 'NONE');
+D('example.com', REG, DnsProvider(DNS),
+    A('@', '192.0.2.1'),
+    TXT('@', [
+        'v=spf1 -all',
+    ]),
+    CAA_BUILDER({
+        label: '@',
+        iodef: 'mailto:test@example.com',
+    }),
+);
+
+D('example.net', REG);
+`,
+		},
+		{
+			name: "quotes",
+			opts: Options{Quote: '"', Indent: "\t"},
+			in: `D('example.com', REG, // it's 'quoted'
+TXT('a', 'it\'s'),
+TXT('b', 'say "hi"'),
+TXT('c', ` + "`a\n'b'`" + `),
+    TXT('d', /'x'/.source)
+);
+`,
+			want: `D("example.com", REG, // it's 'quoted'
+	TXT("a", "it's"),
+	TXT("b", 'say "hi"'),
+	TXT("c", ` + "`a\n'b'`" + `),
+	TXT("d", /'x'/.source)
+);
+`,
+		},
+		{
+			name: "sort",
+			opts: Options{SortRecords: true},
+			in: `D('example.com', REG, DnsProvider(DNS),
+    DefaultTTL(300),
+    MX('@', 10, 'mx'),
+    // The web server.
+    CNAME('www', 'web'),
+    A('web', '192.0.2.2'), // Trailing.
+    A('@', '192.0.2.1'),
+
+    TXT('_dmarc', 'v=DMARC1; p=none'),
+    A('Api', '192.0.2.3'),
+    TXT('a', 'x'), A('b', '192.0.2.4'),
+    CNAME('mail', 'mx')
+);
+D_EXTEND('sub.example.com',
+    CNAME('z', 'www'),
+    A('y', '192.0.2.5')
+);
+`,
+			want: `D('example.com', REG, DnsProvider(DNS),
+    DefaultTTL(300),
+    A('@', '192.0.2.1'),
+    MX('@', 10, 'mx'),
+    A('web', '192.0.2.2'), // Trailing.
+    // The web server.
+    CNAME('www', 'web'),
+
+    TXT('_dmarc', 'v=DMARC1; p=none'),
+    A('Api', '192.0.2.3'),
+    TXT('a', 'x'), A('b', '192.0.2.4'),
+    CNAME('mail', 'mx')
+);
+D_EXTEND('sub.example.com',
+    A('y', '192.0.2.5'),
+    CNAME('z', 'www')
+);
+`,
+		},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			got, err := Format([]byte(tst.in), tst.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tst.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tst.want)
+			}
+			again, err := Format(got, tst.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(again) != string(got) {
+				t.Errorf("formatting again changed the result:\n%s", again)
+			}
+		})
+	}
+}
+
+func TestFormatErrors(t *testing.T) {
+	for _, in := range []string{"D('example.com\n", "/* comment", "var x = `abc"} {
+		if _, err := Format([]byte(in), Options{}); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
+
+// TestRecordTypes checks RecordTypes against the record builders of helpers.js.
+func TestRecordTypes(t *testing.T) {
+	b, err := ioutil.ReadFile("../js/helpers.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := map[string]bool{}
+	for _, m := range regexp.MustCompile(`(?m)^var (\w+) = recordBuilder\(`).FindAllStringSubmatch(string(b), -1) {
+		found[m[1]] = true
+		if !RecordTypes[m[1]] {
+			t.Errorf("%s is missing from RecordTypes", m[1])
+		}
+	}
+	for typ := range RecordTypes {
+		if !found[typ] {
+			t.Errorf("%s is not a record builder of helpers.js", typ)
+		}
+	}
+}
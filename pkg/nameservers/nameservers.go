@@ -2,12 +2,12 @@
 package nameservers
 
 import (
-	"fmt"
 	"strings"
 
 	"strconv"
 
 	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
 )
 
 // DetermineNameservers will find all nameservers we should use for a domain. It follows the following rules:
@@ -21,7 +21,7 @@ func DetermineNameservers(dc *models.DomainConfig) ([]*models.Nameserver, error)
 		if n == 0 {
 			continue
 		}
-		fmt.Printf("----- Getting nameservers from: %s\n", dnsProvider.Name)
+		printer.Printf("----- Getting nameservers from: %s\n", dnsProvider.Name)
 		nss, err := dnsProvider.Driver.GetNameservers(dc.Name)
 		if err != nil {
 			return nil, err
@@ -48,7 +48,7 @@ func AddNSRecords(dc *models.DomainConfig) {
 	if ttls, ok := dc.Metadata["ns_ttl"]; ok {
 		t, err := strconv.ParseUint(ttls, 10, 32)
 		if err != nil {
-			fmt.Printf("WARNING: ns_ttl fpr %s (%s) is not a valid int", dc.Name, ttls)
+			printer.Printf("WARNING: ns_ttl fpr %s (%s) is not a valid int", dc.Name, ttls)
 		} else {
 			ttl = uint32(t)
 		}
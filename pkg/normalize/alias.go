@@ -0,0 +1,144 @@
+package normalize
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/miekg/dns/dnsutil"
+	"github.com/pkg/errors"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/providers"
+)
+
+// lookupIP resolves ALIAS targets. Tests replace it.
+var lookupIP = net.LookupIP
+
+// aliasCacheFile keeps the addresses of ALIAS_FALLBACK("cached") targets.
+const aliasCacheFile = "aliascache.json"
+
+// aliasResolver resolves ALIAS targets according to their ALIAS_FALLBACK policy.
+type aliasResolver struct {
+	cache    map[string][]string // Read from aliasCacheFile, if it exists.
+	resolved map[string][]string // Looked up during this run.
+	changed  map[string]bool     // Targets whose cache entry is missing or out of date.
+}
+
+func (r *aliasResolver) resolve(target, policy string) ([]string, error) {
+	addrs, ok := r.resolved[target]
+	if !ok {
+		ips, err := lookupIP(target)
+		if err != nil {
+			return nil, errors.Wrapf(err, "resolving ALIAS target %s", target)
+		}
+		for _, ip := range ips {
+			addrs = append(addrs, ip.String())
+		}
+		sort.Strings(addrs)
+		r.resolved[target] = addrs
+	}
+	if policy == "live" {
+		return addrs, nil
+	}
+	if r.cache == nil {
+		r.cache = map[string][]string{}
+		b, err := ioutil.ReadFile(aliasCacheFile)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if err == nil {
+			if err := json.Unmarshal(b, &r.cache); err != nil {
+				return nil, errors.Wrapf(err, "reading %s", aliasCacheFile)
+			}
+		}
+	}
+	cached, ok := r.cache[target]
+	if !ok {
+		r.changed[target] = true
+		return addrs, nil
+	}
+	if strings.Join(cached, " ") != strings.Join(addrs, " ") {
+		r.changed[target] = true
+	}
+	return cached, nil
+}
+
+// flattenAliases replaces the ALIAS records that have an ALIAS_FALLBACK
+// with the A and AAAA records of their target, in domains with a DNS
+// provider that can't use ALIAS. All providers of such a domain get the
+// A and AAAA records, so they serve the same data.
+func flattenAliases(cfg *models.DNSConfig) (errs []error) {
+	r := &aliasResolver{resolved: map[string][]string{}, changed: map[string]bool{}}
+	for _, domain := range cfg.Domains {
+		valid := true
+		for _, rec := range domain.Records {
+			if p, ok := rec.Metadata["alias_fallback"]; ok && p != "live" && p != "cached" {
+				errs = append(errs, errors.Errorf("ALIAS_FALLBACK of %s is %q, it must be \"live\" or \"cached\"", rec.GetLabelFQDN(), p))
+				valid = false
+			}
+		}
+		if !valid || allProvidersCan(domain, providers.CanUseAlias) {
+			continue
+		}
+		records := models.Records{}
+		for _, rec := range domain.Records {
+			policy, ok := rec.Metadata["alias_fallback"]
+			if rec.Type != "ALIAS" || !ok {
+				records = append(records, rec)
+				continue
+			}
+			addrs, err := r.resolve(dnsutil.AddOrigin(rec.GetTargetField(), domain.Name+"."), policy)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if len(addrs) == 0 {
+				errs = append(errs, errors.Errorf("ALIAS target %s of %s has no addresses", rec.GetTargetField(), rec.GetLabelFQDN()))
+				continue
+			}
+			for _, addr := range addrs {
+				cp, err := rec.Copy()
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				cp.Type = "AAAA"
+				if net.ParseIP(addr).To4() != nil {
+					cp.Type = "A"
+				}
+				delete(cp.Metadata, "alias_fallback")
+				cp.SetTarget(addr)
+				records = append(records, cp)
+			}
+		}
+		domain.Records = records
+	}
+	if len(r.changed) != 0 {
+		changed := []string{}
+		for target := range r.changed {
+			r.cache[target] = r.resolved[target]
+			changed = append(changed, target)
+		}
+		sort.Strings(changed)
+		b, _ := json.MarshalIndent(r.cache, "", "  ")
+		if err := ioutil.WriteFile("aliascache.updated.json", b, 0644); err != nil {
+			errs = append(errs, err)
+		} else {
+			errs = append(errs, Warning{errors.Errorf("%d ALIAS lookups are out of date with the cache (%s).\nWrote changes to aliascache.updated.json. Please rename and commit:\n    $ mv aliascache.updated.json aliascache.json\n    $ git commit -m'Update aliascache.json' aliascache.json", len(changed), strings.Join(changed, ","))})
+		}
+	}
+	return errs
+}
+
+func allProvidersCan(dc *models.DomainConfig, cap providers.Capability) bool {
+	for _, provider := range dc.DNSProviderInstances {
+		if !providers.ProviderHasCabability(provider.ProviderType, cap) {
+			return false
+		}
+	}
+	return true
+}
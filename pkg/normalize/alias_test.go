@@ -0,0 +1,95 @@
+package normalize
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/providers"
+)
+
+func TestFlattenAliases(t *testing.T) {
+	dir, err := ioutil.TempDir("", "alias")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(dir)
+
+	providers.RegisterDomainServiceProviderType("FAKEALIAS", nil, providers.CanUseAlias)
+	providers.RegisterDomainServiceProviderType("FAKENOALIAS", nil)
+	lookupIP = func(host string) ([]net.IP, error) {
+		return []net.IP{net.ParseIP("2001:db8::1"), net.ParseIP("192.0.2.1")}, nil
+	}
+	defer func() { lookupIP = net.LookupIP }()
+
+	cfg := func(pType, policy string) *models.DNSConfig {
+		alias := &models.RecordConfig{Type: "ALIAS", TTL: 300, Metadata: map[string]string{"alias_fallback": policy}}
+		alias.SetLabel("@", "example.com")
+		alias.SetTarget("lb.example.net.")
+		return &models.DNSConfig{Domains: []*models.DomainConfig{{
+			Name:    "example.com",
+			Records: models.Records{alias},
+			DNSProviderInstances: []*models.DNSProviderInstance{
+				{ProviderBase: models.ProviderBase{Name: "p", ProviderType: pType}},
+			},
+		}}}
+	}
+
+	// Providers that can use ALIAS keep it.
+	c := cfg("FAKEALIAS", "live")
+	if errs := flattenAliases(c); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if len(c.Domains[0].Records) != 1 || c.Domains[0].Records[0].Type != "ALIAS" {
+		t.Fatalf("expected the ALIAS record to be kept, got %v", c.Domains[0].Records)
+	}
+
+	c = cfg("FAKENOALIAS", "live")
+	if errs := flattenAliases(c); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	recs := c.Domains[0].Records
+	if len(recs) != 2 || recs[0].Type != "A" || recs[0].GetTargetField() != "192.0.2.1" || recs[1].Type != "AAAA" || recs[1].TTL != 300 {
+		t.Fatalf("unexpected records %v", recs)
+	}
+	if _, ok := recs[0].Metadata["alias_fallback"]; ok {
+		t.Errorf("alias_fallback should not be copied")
+	}
+
+	// Without a cache, the cached policy uses the live addresses and asks
+	// for the cache to be written.
+	c = cfg("FAKENOALIAS", "cached")
+	errs := flattenAliases(c)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 warning, got %v", errs)
+	}
+	if _, ok := errs[0].(Warning); !ok {
+		t.Fatalf("expected a warning, got %v", errs[0])
+	}
+	if err := os.Rename("aliascache.updated.json", aliasCacheFile); err != nil {
+		t.Fatal(err)
+	}
+
+	// With the cache, the cached addresses are used even when the target moves.
+	lookupIP = func(host string) ([]net.IP, error) {
+		return []net.IP{net.ParseIP("192.0.2.2")}, nil
+	}
+	c = cfg("FAKENOALIAS", "cached")
+	errs = flattenAliases(c)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 warning, got %v", errs)
+	}
+	if recs := c.Domains[0].Records; len(recs) != 2 || recs[0].GetTargetField() != "192.0.2.1" {
+		t.Fatalf("expected the cached addresses, got %v", recs)
+	}
+
+	c = cfg("FAKENOALIAS", "sometimes")
+	if errs := flattenAliases(c); len(errs) != 1 {
+		t.Fatalf("expected an error for an invalid policy, got %v", errs)
+	}
+}
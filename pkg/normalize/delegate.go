@@ -0,0 +1,76 @@
+package normalize
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/pkg/errors"
+)
+
+// checkDelegations checks the DELEGATE() of dc. A nameserver in the
+// delegated zone needs glue, and if the zone is also a domain of config
+// with nameservers of its own, they must be the ones of the delegation.
+func checkDelegations(config *models.DNSConfig, dc *models.DomainConfig) (errs []error) {
+	var children []string
+	delegations := map[string][]string{} // The nameservers, by delegated zone.
+	labels := map[string]string{}
+	addrs := map[string]bool{} // The names that have A or AAAA records.
+	for _, r := range dc.Records {
+		name := strings.ToLower(r.GetLabelFQDN())
+		switch {
+		case r.Type == "A" || r.Type == "AAAA":
+			addrs[name] = true
+		case r.Type == "NS" && r.Metadata["delegate"] != "":
+			if _, ok := delegations[name]; !ok {
+				children = append(children, name)
+				labels[name] = r.GetLabel()
+			}
+			delegations[name] = append(delegations[name], strings.ToLower(strings.TrimSuffix(r.GetTargetField(), ".")))
+		}
+	}
+
+	for _, child := range children {
+		nameservers := uniqueSorted(delegations[child])
+		for _, ns := range nameservers {
+			if (ns == child || strings.HasSuffix(ns, "."+child)) && !addrs[ns] {
+				errs = append(errs, errors.Errorf("DELEGATE(%q) in %s: nameserver %s is in %s, so it needs glue addresses", labels[child], dc.Name, ns, child))
+			}
+		}
+		for _, d := range config.Domains {
+			if d.Name != child || (d.Tag != "" && dc.Tag != "" && d.Tag != dc.Tag) {
+				continue
+			}
+			var have []string
+			for _, ns := range d.Nameservers {
+				have = append(have, strings.ToLower(ns.Name))
+			}
+			for _, r := range d.Records {
+				if r.Type == "NS" && r.GetLabel() == "@" {
+					have = append(have, strings.ToLower(strings.TrimSuffix(r.GetTargetField(), ".")))
+				}
+			}
+			if len(have) == 0 {
+				continue // The nameservers of its providers, which aren't known until they are asked.
+			}
+			if have = uniqueSorted(have); strings.Join(have, " ") != strings.Join(nameservers, " ") {
+				errs = append(errs, errors.Errorf("DELEGATE(%q) in %s lists the nameservers %s, but D(%q) has %s",
+					labels[child], dc.Name, strings.Join(nameservers, ", "), d.Name, strings.Join(have, ", ")))
+			}
+		}
+	}
+	return errs
+}
+
+func uniqueSorted(l []string) []string {
+	seen := map[string]bool{}
+	var u []string
+	for _, s := range l {
+		if !seen[s] {
+			seen[s] = true
+			u = append(u, s)
+		}
+	}
+	sort.Strings(u)
+	return u
+}
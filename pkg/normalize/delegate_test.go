@@ -0,0 +1,53 @@
+package normalize
+
+import (
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/models"
+)
+
+func TestCheckDelegations(t *testing.T) {
+	ns := func(domain, label, target string, delegate bool) *models.RecordConfig {
+		rc := makeRC(label, domain, target, models.RecordConfig{Type: "NS", Metadata: map[string]string{}})
+		if delegate {
+			rc.Metadata["delegate"] = "true"
+		}
+		return rc
+	}
+	a := func(label string) *models.RecordConfig {
+		return makeRC(label, "example.com", "192.0.2.1", models.RecordConfig{Type: "A"})
+	}
+	tests := []struct {
+		name   string
+		parent []*models.RecordConfig
+		child  []*models.RecordConfig // nil for no child domain.
+		errs   int
+	}{
+		{"outside", []*models.RecordConfig{ns("example.com", "sub", "ns1.example.net.", true)}, nil, 0},
+		{"glue", []*models.RecordConfig{ns("example.com", "sub", "ns1.sub.example.com.", true), a("ns1.sub")}, nil, 0},
+		{"no glue", []*models.RecordConfig{ns("example.com", "sub", "ns1.sub.example.com.", true)}, nil, 1},
+		{"not delegated", []*models.RecordConfig{ns("example.com", "sub", "ns1.sub.example.com.", false)}, nil, 0},
+		{"child matches", []*models.RecordConfig{
+			ns("example.com", "sub", "ns1.example.net.", true),
+			ns("example.com", "sub", "NS2.example.net.", true),
+		}, []*models.RecordConfig{
+			ns("sub.example.com", "@", "ns2.example.net.", false),
+			ns("sub.example.com", "@", "ns1.example.net.", false),
+		}, 0},
+		{"child differs", []*models.RecordConfig{ns("example.com", "sub", "ns1.example.net.", true)},
+			[]*models.RecordConfig{ns("sub.example.com", "@", "ns1.example.org.", false)}, 1},
+		{"child without NS", []*models.RecordConfig{ns("example.com", "sub", "ns1.example.net.", true)}, []*models.RecordConfig{}, 0},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			parent := &models.DomainConfig{Name: "example.com", Records: tst.parent}
+			config := &models.DNSConfig{Domains: []*models.DomainConfig{parent}}
+			if tst.child != nil {
+				config.Domains = append(config.Domains, &models.DomainConfig{Name: "sub.example.com", Records: tst.child})
+			}
+			if errs := checkDelegations(config, parent); len(errs) != tst.errs {
+				t.Errorf("got %v, want %d errors", errs, tst.errs)
+			}
+		})
+	}
+}
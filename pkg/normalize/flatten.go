@@ -1,6 +1,7 @@
 package normalize
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -33,11 +34,17 @@ func flattenSPFs(cfg *models.DNSConfig) []error {
 				}
 			}
 			if flatten, ok := txt.Metadata["flatten"]; ok && strings.HasPrefix(txt.GetTargetField(), "v=spf1") {
-				rec = rec.Flatten(flatten)
-				err = txt.SetTargetTXT(rec.TXT())
+				needed, err := needsFlattening(rec, txt)
 				if err != nil {
 					errs = append(errs, err)
-					continue
+				}
+				if needed {
+					rec = rec.Flatten(flatten)
+					err = txt.SetTargetTXT(rec.TXT())
+					if err != nil {
+						errs = append(errs, err)
+						continue
+					}
 				}
 			}
 			// now split if needed
@@ -79,3 +86,22 @@ func flattenSPFs(cfg *models.DNSConfig) []error {
 	}
 	return errs
 }
+
+// needsFlattening reports whether the SPF record rec of txt should be
+// flattened. It always should, unless SPF_BUILDER's maxLookups is set and
+// rec needs no more DNS lookups than that; then it returns a warning with
+// the count, as a reminder of how close the record is to its limit.
+func needsFlattening(rec *spflib.SPFRecord, txt *models.RecordConfig) (bool, error) {
+	s, ok := txt.Metadata["max_lookups"]
+	if !ok {
+		return true, nil
+	}
+	max, err := strconv.Atoi(s)
+	if err != nil || max < 1 {
+		return false, errors.Errorf("SPF record %s: max_lookups %q is not a positive number", txt.GetLabelFQDN(), s)
+	}
+	if n := rec.Lookups(); n <= max {
+		return false, Warning{errors.Errorf("SPF record %s needs %d of its max_lookups %d DNS lookups; not flattened", txt.GetLabelFQDN(), n, max)}
+	}
+	return true, nil
+}
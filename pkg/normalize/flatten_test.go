@@ -0,0 +1,42 @@
+package normalize
+
+import (
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/spflib"
+)
+
+func TestNeedsFlattening(t *testing.T) {
+	rec, err := spflib.Parse("v=spf1 mx include:a.example.net include:b.example.net -all", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		max    string // "" for no max_lookups
+		needed bool
+		warn   bool
+		err    bool
+	}{
+		{"", true, false, false},
+		{"2", true, false, false},
+		{"3", false, true, false},
+		{"10", false, true, false},
+		{"0", false, false, true},
+		{"ten", false, false, true},
+	}
+	for _, tst := range tests {
+		txt := makeRC("@", "example.com", "", models.RecordConfig{Type: "TXT", Metadata: map[string]string{"flatten": "*"}})
+		if tst.max != "" {
+			txt.Metadata["max_lookups"] = tst.max
+		}
+		needed, err := needsFlattening(rec, txt)
+		if needed != tst.needed {
+			t.Errorf("max_lookups %q: needed %v, want %v", tst.max, needed, tst.needed)
+		}
+		_, isWarning := err.(Warning)
+		if (err != nil && isWarning) != tst.warn || (err != nil && !isWarning) != tst.err {
+			t.Errorf("max_lookups %q: got error %v, want warning: %v, error: %v", tst.max, err, tst.warn, tst.err)
+		}
+	}
+}
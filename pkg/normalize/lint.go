@@ -0,0 +1,106 @@
+package normalize
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/pkg/errors"
+)
+
+// The checks of this file find mistakes that DNS servers accept, but
+// that are unlikely to be what was meant. They only return warnings;
+// "dnscontrol check --strict" fails on them.
+
+// lintTXT returns a warning for each string of a TXT record that looks
+// like it was quoted or split by hand, as in a zone file.
+func lintTXT(txts []string) (errs []error) {
+	for _, s := range txts {
+		var msg string
+		switch {
+		case len(s) >= 2 && strings.HasPrefix(s, `"`) && strings.HasSuffix(s, `"`):
+			msg = "is wrapped in quotes, which become part of the value"
+		case strings.Contains(s, `" "`):
+			msg = `contains " ", like the strings of a zone file; give the strings as a list instead`
+		case strings.Count(s, `"`)-strings.Count(s, `\"`) == 1:
+			msg = "has an unbalanced quote"
+		case strings.ContainsAny(s, "\r\n"):
+			msg = "contains a line break"
+		default:
+			continue
+		}
+		errs = append(errs, Warning{errors.Errorf("TXT string %q %s", s, msg)})
+	}
+	return errs
+}
+
+// lintSRVTarget warns about SRV targets without any dot, which are
+// qualified with the domain. SRV records usually point at hosts with
+// longer names, so this is more often a name whose end was left out.
+func lintSRVTarget(target, domain string) error {
+	if target == "." || target == "@" || strings.Contains(target, ".") {
+		return nil
+	}
+	return Warning{errors.Errorf("target %s has no dot, so it means %s.%s.; write the full name, ending with a dot", target, target, domain)}
+}
+
+// lintTargetsAtCNAMEs warns about MX, NS and SRV records whose target is
+// the name of a CNAME in any domain of config. RFC 2181 (section 10.3)
+// and RFC 2782 forbid it, and some mail servers and resolvers don't
+// follow the CNAME.
+func lintTargetsAtCNAMEs(config *models.DNSConfig, dc *models.DomainConfig) (errs []error) {
+	cnames := map[string]string{}
+	for _, d := range config.Domains {
+		if d.Tag != "" && dc.Tag != "" && d.Tag != dc.Tag {
+			continue // Another view of split horizon domains.
+		}
+		for _, r := range d.Records {
+			if r.Type == "CNAME" {
+				cnames[strings.ToLower(r.GetLabelFQDN())] = r.GetTargetField()
+			}
+		}
+	}
+	for _, r := range dc.Records {
+		if r.Type != "MX" && r.Type != "NS" && r.Type != "SRV" {
+			continue
+		}
+		target := strings.ToLower(strings.TrimSuffix(r.GetTargetField(), "."))
+		if cname, ok := cnames[target]; ok {
+			errs = append(errs, Warning{errors.Errorf("In %s %s: target %s is a CNAME (to %s); use the name it points to", r.Type, r.GetLabelFQDN(), target, cname)})
+		}
+	}
+	return errs
+}
+
+// hostnameTypes are the types whose target is a host name, in which case
+// doesn't matter.
+var hostnameTypes = map[string]bool{
+	"ALIAS": true, "CNAME": true, "DNAME": true, "MX": true, "NS": true, "PTR": true, "SRV": true,
+}
+
+// lintNearDuplicates warns about records that are the same but for their
+// TTL or, for targets that are host names, their case. checkDuplicates only finds records that
+// are exactly the same. Providers keep one of them, and which one may
+// change from one run to the next.
+func lintNearDuplicates(records []*models.RecordConfig) (errs []error) {
+	seen := map[string]*models.RecordConfig{}
+	for _, r := range records {
+		target := r.GetTargetCombined()
+		if hostnameTypes[r.Type] {
+			target = strings.ToLower(target)
+		}
+		key := fmt.Sprintf("%s %s %s", r.GetLabelFQDN(), r.Type, target)
+		first, ok := seen[key]
+		if !ok {
+			seen[key] = r
+			continue
+		}
+		if first.TTL == r.TTL && first.GetTargetCombined() == r.GetTargetCombined() {
+			// An exact duplicate: an error of checkDuplicates.
+			continue
+		}
+		errs = append(errs, Warning{errors.Errorf("Near duplicate %s records %s: %s ttl=%d and %s ttl=%d differ only by TTL or case",
+			r.Type, r.GetLabelFQDN(), first.GetTargetCombined(), first.TTL, r.GetTargetCombined(), r.TTL)})
+	}
+	return errs
+}
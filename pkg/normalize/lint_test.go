@@ -0,0 +1,104 @@
+package normalize
+
+import (
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/models"
+)
+
+func TestLintTXT(t *testing.T) {
+	tests := []struct {
+		txt  string
+		warn bool
+	}{
+		{"v=spf1 -all", false},
+		{`say "hi"`, false},
+		{`"v=spf1 -all"`, true},
+		{`v=DKIM1; p=abc" "def`, true},
+		{`v=DKIM1; p=abc"`, true},
+		{"line\nbreak", true},
+	}
+	for _, tst := range tests {
+		errs := lintTXT([]string{tst.txt})
+		if (len(errs) != 0) != tst.warn {
+			t.Errorf("%q: got %v, want warning: %v", tst.txt, errs, tst.warn)
+		}
+		for _, err := range errs {
+			if _, ok := err.(Warning); !ok {
+				t.Errorf("%q: %v is not a warning", tst.txt, err)
+			}
+		}
+	}
+}
+
+func TestLintSRVTarget(t *testing.T) {
+	tests := []struct {
+		target string
+		warn   bool
+	}{
+		{"sip.example.net.", false},
+		{".", false},
+		{"@", false},
+		{"sip", true},
+	}
+	for _, tst := range tests {
+		if err := lintSRVTarget(tst.target, "example.com"); (err != nil) != tst.warn {
+			t.Errorf("%q: got %v, want warning: %v", tst.target, err, tst.warn)
+		}
+	}
+}
+
+func TestLintTargetsAtCNAMEs(t *testing.T) {
+	com := &models.DomainConfig{
+		Name: "example.com",
+		Records: []*models.RecordConfig{
+			makeRC("mail", "example.com", "mx.example.net.", models.RecordConfig{Type: "CNAME"}),
+			makeRC("@", "example.com", "mail.example.com.", models.RecordConfig{Type: "MX"}),
+			makeRC("@", "example.com", "mx.example.net.", models.RecordConfig{Type: "MX"}),
+		},
+	}
+	exnet := &models.DomainConfig{
+		Name: "example.net",
+		Records: []*models.RecordConfig{
+			makeRC("_sip._tcp", "example.net", "Mail.Example.com.", models.RecordConfig{Type: "SRV"}),
+			makeRC("www", "example.net", "mail.example.com.", models.RecordConfig{Type: "CNAME"}),
+		},
+	}
+	config := &models.DNSConfig{Domains: []*models.DomainConfig{com, exnet}}
+	if errs := lintTargetsAtCNAMEs(config, com); len(errs) != 1 {
+		t.Errorf("example.com: got %v, want 1 warning", errs)
+	}
+	if errs := lintTargetsAtCNAMEs(config, exnet); len(errs) != 1 {
+		t.Errorf("example.net: got %v, want 1 warning", errs)
+	}
+}
+
+func TestLintNearDuplicates(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []*models.RecordConfig
+		warn    bool
+	}{
+		{"exact", []*models.RecordConfig{
+			makeRC("www", "example.com", "1.2.3.4", models.RecordConfig{Type: "A", TTL: 300}),
+			makeRC("www", "example.com", "1.2.3.4", models.RecordConfig{Type: "A", TTL: 300}),
+		}, false},
+		{"ttl", []*models.RecordConfig{
+			makeRC("www", "example.com", "1.2.3.4", models.RecordConfig{Type: "A", TTL: 300}),
+			makeRC("www", "example.com", "1.2.3.4", models.RecordConfig{Type: "A", TTL: 600}),
+		}, true},
+		{"hostname case", []*models.RecordConfig{
+			makeRC("@", "example.com", "ns1.example.net.", models.RecordConfig{Type: "NS", TTL: 300}),
+			makeRC("@", "example.com", "NS1.example.net.", models.RecordConfig{Type: "NS", TTL: 300}),
+		}, true},
+		{"txt case", []*models.RecordConfig{
+			makeRC("@", "example.com", "token=abc", models.RecordConfig{Type: "TXT", TTL: 300}),
+			makeRC("@", "example.com", "token=ABC", models.RecordConfig{Type: "TXT", TTL: 300}),
+		}, false},
+	}
+	for _, tst := range tests {
+		if errs := lintNearDuplicates(tst.records); (len(errs) != 0) != tst.warn {
+			t.Errorf("%s: got %v, want warning: %v", tst.name, errs, tst.warn)
+		}
+	}
+}
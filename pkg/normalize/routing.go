@@ -0,0 +1,53 @@
+package normalize
+
+import (
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/providers"
+	"github.com/pkg/errors"
+)
+
+// checkRouting checks the ROUTING() of the records of dc. The records of
+// a name and type either all have one or none do, the records of a set
+// have the same routing, and the providers they go to support routing.
+func checkRouting(dc *models.DomainConfig) (errs []error) {
+	routed := map[models.RecordKey]bool{}
+	plain := map[models.RecordKey]bool{}
+	sets := map[string]*models.Routing{}
+	unsupported := map[string]bool{}
+	for _, rc := range dc.Records {
+		r := rc.Routing()
+		if r == nil {
+			plain[rc.Key()] = true
+			continue
+		}
+		routed[rc.Key()] = true
+		if err := r.Check(); err != nil {
+			errs = append(errs, errors.Wrapf(err, "In %s %s", rc.Type, rc.GetLabelFQDN()))
+			continue
+		}
+		set := rc.GetLabelFQDN() + " " + rc.Type + " " + r.Set
+		if first, ok := sets[set]; ok && *first != *r {
+			errs = append(errs, errors.Errorf("In %s %s: the records of the set %q have different ROUTING()", rc.Type, rc.GetLabelFQDN(), r.Set))
+		}
+		sets[set] = r
+		for _, p := range dc.DNSProviderInstances {
+			if rc.ForProvider(p.Name) && !providers.ProviderHasCabability(p.ProviderType, providers.CanUseRoutingPolicy) {
+				unsupported[p.ProviderType] = true
+			}
+		}
+	}
+	reported := map[models.RecordKey]bool{}
+	for _, rc := range dc.Records {
+		if k := rc.Key(); routed[k] && plain[k] && !reported[k] {
+			reported[k] = true
+			errs = append(errs, errors.Errorf("In %s %s: some records have ROUTING() and some don't; all the records of a name and type must be in sets", k.Type, k.NameFQDN))
+		}
+	}
+	for _, p := range dc.DNSProviderInstances {
+		if unsupported[p.ProviderType] {
+			delete(unsupported, p.ProviderType)
+			errs = append(errs, errors.Errorf("Domain %s uses ROUTING(), but DNS provider type %s does not support it", dc.Name, p.ProviderType))
+		}
+	}
+	return errs
+}
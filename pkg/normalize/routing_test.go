@@ -0,0 +1,63 @@
+package normalize
+
+import (
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/providers"
+)
+
+func TestCheckRouting(t *testing.T) {
+	providers.RegisterDomainServiceProviderType("FAKEROUTING", nil, providers.CanUseRoutingPolicy)
+	rec := func(label, target string, routing ...string) *models.RecordConfig {
+		rc := makeRC(label, "example.com", target, models.RecordConfig{Type: "A", Metadata: map[string]string{}})
+		for i := 0; i+1 < len(routing); i += 2 {
+			rc.Metadata["routing_"+routing[i]] = routing[i+1]
+		}
+		return rc
+	}
+	tests := []struct {
+		name     string
+		provider string
+		records  []*models.RecordConfig
+		errs     int
+	}{
+		{"none", "FAKE", []*models.RecordConfig{rec("www", "1.2.3.4")}, 0},
+		{"weighted", "FAKEROUTING", []*models.RecordConfig{
+			rec("www", "1.2.3.4", "set", "a", "weight", "10"),
+			rec("www", "1.2.3.5", "set", "a", "weight", "10"),
+			rec("www", "1.2.3.6", "set", "b", "weight", "20"),
+		}, 0},
+		{"geo", "FAKEROUTING", []*models.RecordConfig{
+			rec("www", "1.2.3.4", "set", "eu", "geo", "continent:EU"),
+			rec("www", "1.2.3.5", "set", "ca", "geo", "US-CA"),
+			rec("www", "1.2.3.6", "set", "other", "geo", "*"),
+		}, 0},
+		{"unsupported", "FAKE", []*models.RecordConfig{
+			rec("www", "1.2.3.4", "set", "a", "weight", "10"),
+			rec("www", "1.2.3.5", "set", "b", "weight", "10"),
+		}, 1},
+		{"no set", "FAKEROUTING", []*models.RecordConfig{rec("www", "1.2.3.4", "weight", "10")}, 1},
+		{"two policies", "FAKEROUTING", []*models.RecordConfig{rec("www", "1.2.3.4", "set", "a", "weight", "10", "failover", "primary")}, 1},
+		{"bad weight", "FAKEROUTING", []*models.RecordConfig{rec("www", "1.2.3.4", "set", "a", "weight", "300")}, 1},
+		{"bad geo", "FAKEROUTING", []*models.RecordConfig{rec("www", "1.2.3.4", "set", "a", "geo", "europe")}, 1},
+		{"bad failover", "FAKEROUTING", []*models.RecordConfig{rec("www", "1.2.3.4", "set", "a", "failover", "first")}, 1},
+		{"different in a set", "FAKEROUTING", []*models.RecordConfig{
+			rec("www", "1.2.3.4", "set", "a", "weight", "10"),
+			rec("www", "1.2.3.5", "set", "a", "weight", "20"),
+		}, 1},
+		{"mixed", "FAKEROUTING", []*models.RecordConfig{
+			rec("www", "1.2.3.4", "set", "a", "weight", "10"),
+			rec("www", "1.2.3.5"),
+		}, 1},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			dc := &models.DomainConfig{Name: "example.com", Records: tst.records,
+				DNSProviderInstances: []*models.DNSProviderInstance{{ProviderBase: models.ProviderBase{Name: "p", ProviderType: tst.provider}}}}
+			if errs := checkRouting(dc); len(errs) != tst.errs {
+				t.Errorf("got %v, want %d errors", errs, tst.errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package normalize
+
+import (
+	"strconv"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/providers"
+	"github.com/pkg/errors"
+)
+
+// applyTTLPolicy checks dc against the TTL_POLICY() of dnsconfig.js, and
+// fills in the TTLs dnsconfig.js leaves unset. It must run before records
+// without a TTL get models.DefaultTTL.
+//
+// NS records (at the apex and delegations) without a TTL get the policy's
+// ns_ttl; NS records and NAMESERVER_TTL() with a different TTL are errors.
+// An SOA() must use the policy's negative_ttl as its minimum. Without one,
+// the soa_minimum metadata asks providers that can manage the SOA to
+// set it. Other providers can't be checked, which is a warning.
+func applyTTLPolicy(policy *models.TTLPolicy, dc *models.DomainConfig) (errs []error) {
+	if policy == nil {
+		return nil
+	}
+	if dc.Metadata == nil {
+		dc.Metadata = map[string]string{}
+	}
+
+	if policy.NSTTL != 0 {
+		if s, ok := dc.Metadata["ns_ttl"]; !ok {
+			dc.Metadata["ns_ttl"] = strconv.FormatUint(uint64(policy.NSTTL), 10)
+		} else if ttl, err := strconv.ParseUint(s, 10, 32); err != nil || uint32(ttl) != policy.NSTTL {
+			errs = append(errs, errors.Errorf("NAMESERVER_TTL(%s) of domain %s does not match the TTL_POLICY ns_ttl %d", s, dc.Name, policy.NSTTL))
+		}
+		for _, rec := range dc.Records {
+			if rec.Type != "NS" {
+				continue
+			}
+			if rec.TTL == 0 {
+				rec.TTL = policy.NSTTL
+			} else if rec.TTL != policy.NSTTL {
+				errs = append(errs, errors.Errorf("NS record %s of domain %s has TTL %d, the TTL_POLICY ns_ttl is %d", rec.GetLabel(), dc.Name, rec.TTL, policy.NSTTL))
+			}
+		}
+	}
+
+	if policy.NegativeTTL != 0 {
+		if soa := findSOA(dc); soa != nil {
+			// A malformed SOA is reported by checkSOA.
+			if minttl, err := soa.SOAMinimum(); err == nil && minttl != policy.NegativeTTL {
+				errs = append(errs, errors.Errorf("SOA minimum of domain %s is %d, the TTL_POLICY negative_ttl is %d", dc.Name, minttl, policy.NegativeTTL))
+			}
+			return errs
+		}
+		dc.Metadata["soa_minimum"] = strconv.FormatUint(uint64(policy.NegativeTTL), 10)
+		for _, p := range dc.DNSProviderInstances {
+			if !providers.ProviderHasCabability(p.ProviderType, providers.CanUseSOA) {
+				errs = append(errs, Warning{errors.Errorf("%s(%s) can't manage the SOA of domain %s, so the TTL_POLICY negative_ttl is not enforced there", p.Name, p.ProviderType, dc.Name)})
+			}
+		}
+	}
+	return errs
+}
+
+func findSOA(dc *models.DomainConfig) *models.RecordConfig {
+	for _, rec := range dc.Records {
+		if rec.Type == "SOA" {
+			return rec
+		}
+	}
+	return nil
+}
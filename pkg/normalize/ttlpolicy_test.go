@@ -0,0 +1,68 @@
+package normalize
+
+import (
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/providers"
+)
+
+func TestApplyTTLPolicy(t *testing.T) {
+	providers.RegisterDomainServiceProviderType("FAKESOA", nil, providers.CanUseSOA)
+	providers.RegisterDomainServiceProviderType("FAKENOSOA", nil)
+	policy := &models.TTLPolicy{NSTTL: 86400, NegativeTTL: 300}
+	rec := func(label, typ string, ttl uint32, target string) *models.RecordConfig {
+		r := &models.RecordConfig{Type: typ, TTL: ttl}
+		r.SetLabel(label, "example.com")
+		r.SetTarget(target)
+		return r
+	}
+
+	dc := &models.DomainConfig{
+		Name: "example.com",
+		Records: models.Records{
+			rec("sub", "NS", 0, "ns1.example.net."),
+			rec("www", "A", 0, "192.0.2.1"),
+		},
+		DNSProviderInstances: []*models.DNSProviderInstance{
+			{ProviderBase: models.ProviderBase{Name: "p", ProviderType: "FAKESOA"}},
+		},
+	}
+	if errs := applyTTLPolicy(policy, dc); len(errs) != 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+	if dc.Records[0].TTL != 86400 || dc.Records[1].TTL != 0 {
+		t.Errorf("unexpected TTLs %d %d", dc.Records[0].TTL, dc.Records[1].TTL)
+	}
+	if dc.Metadata["ns_ttl"] != "86400" || dc.Metadata["soa_minimum"] != "300" {
+		t.Errorf("unexpected metadata %v", dc.Metadata)
+	}
+
+	// Explicit values that differ from the policy are errors.
+	dc = &models.DomainConfig{
+		Name:     "example.com",
+		Metadata: map[string]string{"ns_ttl": "3600"},
+		Records: models.Records{
+			rec("sub", "NS", 3600, "ns1.example.net."),
+			rec("@", "SOA", 0, "ns1.example.com. hostmaster.example.com. 0 3600 600 604800 1440"),
+		},
+	}
+	if errs := applyTTLPolicy(policy, dc); len(errs) != 3 {
+		t.Errorf("expected 3 errors, got %v", errs)
+	}
+
+	// A provider that can't manage the SOA gets a warning.
+	dc = &models.DomainConfig{
+		Name: "example.com",
+		DNSProviderInstances: []*models.DNSProviderInstance{
+			{ProviderBase: models.ProviderBase{Name: "p", ProviderType: "FAKENOSOA"}},
+		},
+	}
+	errs := applyTTLPolicy(policy, dc)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 warning, got %v", errs)
+	}
+	if _, ok := errs[0].(Warning); !ok {
+		t.Errorf("expected a warning, got %v", errs[0])
+	}
+}
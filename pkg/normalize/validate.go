@@ -1,13 +1,23 @@
 package normalize
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/healthcheck"
+	"github.com/StackExchange/dnscontrol/pkg/ownership"
 	"github.com/StackExchange/dnscontrol/pkg/transform"
+	"github.com/StackExchange/dnscontrol/pkg/verification"
 	"github.com/StackExchange/dnscontrol/providers"
+	"github.com/StackExchange/dnscontrol/providers/diff"
 	"github.com/miekg/dns"
 	"github.com/miekg/dns/dnsutil"
 	"github.com/pkg/errors"
@@ -37,7 +47,12 @@ func checkTarget(target string) error {
 	if len(target) < 1 {
 		return errors.Errorf("empty target")
 	}
-	if strings.ContainsAny(target, `'" +,|!£$%&/()=?^*ç°§;:<>[]()@`) {
+	invalid := `'" +,|!£$%&/()=?^*ç°§;:<>[]()@`
+	if strings.HasSuffix(target, ".in-addr.arpa.") {
+		// RFC2317 zones, such as 128/26.2.0.192.in-addr.arpa.
+		invalid = strings.Replace(invalid, "/", "", 1)
+	}
+	if strings.ContainsAny(target, invalid) {
 		return errors.Errorf("target (%v) includes invalid char", target)
 	}
 	// If it containts a ".", it must end in a ".".
@@ -47,25 +62,164 @@ func checkTarget(target string) error {
 	return nil
 }
 
+// checkBase64 returns an error if s is not valid base64.
+func checkBase64(s string) error {
+	if _, err := base64.StdEncoding.DecodeString(s); err != nil {
+		return errors.Errorf("value is not valid base64: %s", err)
+	}
+	return nil
+}
+
+// checkHex returns an error if s is not a hex string.
+func checkHex(s string) error {
+	if _, err := hex.DecodeString(s); err != nil {
+		return errors.Errorf("value is not valid hex: %s", err)
+	}
+	return nil
+}
+
+// checkURI returns an error if s is not an absolute URI. Surrounding quotes are ignored.
+func checkURI(s string) error {
+	u, err := url.Parse(models.StripQuotes(strings.TrimSpace(s)))
+	if err != nil {
+		return errors.Errorf("value is not a valid URI: %s", err)
+	}
+	if u.Scheme == "" {
+		return errors.Errorf("URI %q has no scheme", s)
+	}
+	return nil
+}
+
+// checkDHCID returns an error if s is not a valid DHCID digest: base64 of
+// an identifier type (2 octets), a digest type (1 octet) and the digest
+// (RFC 4701). SHA-256, the only digest type defined, has 32 octets.
+func checkDHCID(s string) error {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return errors.Errorf("value is not valid base64: %s", err)
+	}
+	if len(b) < 3 {
+		return errors.Errorf("DHCID rdata is too short")
+	}
+	if b[2] == 1 && len(b) != 35 {
+		return errors.Errorf("DHCID SHA-256 digest has %d octets, not 32", len(b)-3)
+	}
+	return nil
+}
+
+// checkCSYNC returns an error if rec is not a usable CSYNC record.
+// RFC 7477 only defines what A, AAAA and NS in the type bitmap mean;
+// other rtypes are allowed but get a warning.
+func checkCSYNC(rec *models.RecordConfig) error {
+	if rec.GetLabel() != "@" {
+		return errors.Errorf("CSYNC records can only be set for the bare domain")
+	}
+	if rec.CsyncFlags&^3 != 0 {
+		return errors.Errorf("CSYNC flags %d is invalid. Only 1 (immediate) and 2 (soaminimum) are defined", rec.CsyncFlags)
+	}
+	if err := (&models.RecordConfig{Type: "CSYNC"}).SetTargetCSYNC(rec.CsyncSerial, rec.CsyncFlags, rec.GetTargetField()); err != nil {
+		return err
+	}
+	for _, t := range strings.Fields(strings.ToUpper(rec.GetTargetField())) {
+		if t != "A" && t != "AAAA" && t != "NS" {
+			return Warning{errors.Errorf("rtype %s has no defined meaning in a CSYNC type bitmap", t)}
+		}
+	}
+	return nil
+}
+
+// checkUnknown returns an error if rec is not a usable UNKNOWN() record.
+// Types dnscontrol knows must be declared with their own function, so
+// that they are compared field by field like the records read from providers.
+func checkUnknown(rec *models.RecordConfig) error {
+	n, _ := models.UnknownTypeNumber(rec.Type)
+	name := dns.Type(n).String()
+	if n == models.TypeCSYNC {
+		name = "CSYNC"
+	}
+	if validTypes[name] {
+		return errors.Errorf("use %s() instead of UNKNOWN() for %s records", name, name)
+	}
+	return (&models.RecordConfig{}).SetTargetUnknown(n, rec.GetTargetField())
+}
+
+// checkSOA returns an error if rec is not a usable SOA record.
+func checkSOA(rec *models.RecordConfig) error {
+	if rec.GetLabel() != "@" {
+		return errors.Errorf("SOA records can only be set for the bare domain")
+	}
+	if err := (&models.RecordConfig{Type: "SOA"}).SetTargetSOAString(rec.GetTargetField()); err != nil {
+		return err
+	}
+	part := strings.Fields(rec.GetTargetField())
+	if err := checkTarget(part[0]); err != nil {
+		return errors.Wrap(err, "mname")
+	}
+	if err := checkTarget(soaRname(part[1])); err != nil {
+		return errors.Wrap(err, "rname")
+	}
+	switch s := rec.Metadata["soa_serial"]; s {
+	case "", "date", "increment", "unixtime":
+	default:
+		return errors.Errorf("soa_serial %q is invalid. Use date, increment or unixtime", s)
+	}
+	return nil
+}
+
+// soaRname turns an email address (hostmaster@example.com) into the
+// form used in SOA records (hostmaster.example.com.).
+func soaRname(s string) string {
+	if !strings.Contains(s, "@") {
+		return s
+	}
+	return dns.Fqdn(strings.Replace(s, "@", ".", 1))
+}
+
+// canonicalizeSOA makes the mname and rname of an SOA record fully qualified.
+// The rname may be given as an email address.
+func canonicalizeSOA(rec *models.RecordConfig, origin string) {
+	part := strings.Fields(rec.GetTargetField())
+	if len(part) != 7 {
+		return // Already reported by checkSOA.
+	}
+	part[0] = dnsutil.AddOrigin(part[0], origin+".")
+	part[1] = dnsutil.AddOrigin(soaRname(part[1]), origin+".")
+	rec.SetTarget(strings.Join(part, " "))
+}
+
+// validTypes lists the valid rec.Type values. True means it is a real DNS record type, false means it is a pseudo-type used internally.
+var validTypes = map[string]bool{
+	"A":                true,
+	"AAAA":             true,
+	"CNAME":            true,
+	"CAA":              true,
+	"CERT":             true,
+	"CSYNC":            true,
+	"DHCID":            true,
+	"DNAME":            true,
+	"TLSA":             true,
+	"IMPORT_TRANSFORM": false,
+	"MX":               true,
+	"OPENPGPKEY":       true,
+	"SMIMEA":           true,
+	"SOA":              true,
+	"SRV":              true,
+	"SSHFP":            true,
+	"TXT":              true,
+	"URI":              true,
+	"NS":               true,
+	"PTR":              true,
+	"NAPTR":            true,
+	"RP":               true,
+	"ALIAS":            false,
+}
+
 // validateRecordTypes list of valid rec.Type values. Returns true if this is a real DNS record type, false means it is a pseudo-type used internally.
 func validateRecordTypes(rec *models.RecordConfig, domain string, pTypes []string) error {
-	var validTypes = map[string]bool{
-		"A":                true,
-		"AAAA":             true,
-		"CNAME":            true,
-		"CAA":              true,
-		"TLSA":             true,
-		"IMPORT_TRANSFORM": false,
-		"MX":               true,
-		"SRV":              true,
-		"SSHFP":            true,
-		"TXT":              true,
-		"NS":               true,
-		"PTR":              true,
-		"NAPTR":            true,
-		"ALIAS":            false,
-	}
 	_, ok := validTypes[rec.Type]
+	if _, unknown := models.UnknownTypeNumber(rec.Type); unknown {
+		ok = true // Checked by checkUnknown.
+	}
 	if !ok {
 		cType := providers.GetCustomRecordType(rec.Type)
 		if cType == nil {
@@ -85,6 +239,29 @@ func validateRecordTypes(rec *models.RecordConfig, domain string, pTypes []strin
 	return nil
 }
 
+// checkRecordProviders checks that the PROVIDERS() of rec are DNS
+// providers of domain.
+func checkRecordProviders(rec *models.RecordConfig, domain *models.DomainConfig) error {
+	list, ok := rec.Metadata["providers"]
+	if !ok {
+		return nil
+	}
+	n := 0
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		n++
+		if _, ok := domain.DNSProviderNames[name]; !ok {
+			return errors.Errorf("PROVIDERS of %s record %s: %s is not a DNS provider of %s", rec.Type, rec.GetLabel(), name, domain.Name)
+		}
+	}
+	if n == 0 {
+		return errors.Errorf("PROVIDERS of %s record %s is empty", rec.Type, rec.GetLabel())
+	}
+	return nil
+}
+
 // underscores in names are often used erroneously. They are valid for dns records, but invalid for urls.
 // here we list common records expected to have underscores. Anything else containing an underscore will print a warning.
 var labelUnderscores = []string{
@@ -98,7 +275,39 @@ var labelUnderscores = []string{
 }
 
 // these record types may contain underscores
-var rTypeUnderscores = []string{"SRV", "TLSA", "TXT"}
+var rTypeUnderscores = []string{"OPENPGPKEY", "SMIMEA", "SRV", "TLSA", "TXT", "URI"}
+
+// checkHealthCheck checks the HEALTH_CHECK() of rec, if it has one.
+func checkHealthCheck(rec *models.RecordConfig) error {
+	s, ok := rec.Metadata["health_check"]
+	if !ok {
+		return nil
+	}
+	if rec.Type != "A" && rec.Type != "AAAA" {
+		return errors.Errorf("HEALTH_CHECK of %s record %s: only A and AAAA records can have one", rec.Type, rec.GetLabel())
+	}
+	if _, err := healthcheck.Parse(s); err != nil {
+		return err
+	}
+	if t, ok := rec.Metadata["health_timeout"]; ok {
+		if n, err := strconv.ParseUint(t, 10, 32); err != nil || n == 0 {
+			return errors.Errorf("HEALTH_CHECK timeout of %s record %s is %q, it must be a number of seconds", rec.Type, rec.GetLabel(), t)
+		}
+	}
+	return nil
+}
+
+// checkThresholds checks the MAX_CHANGES() and MAX_DELETES() of dc.
+func checkThresholds(dc *models.DomainConfig) (errs []error) {
+	for _, k := range []string{"max_changes", "max_deletes"} {
+		if s, ok := dc.Metadata[k]; ok {
+			if n, err := strconv.Atoi(s); err != nil || n < 1 {
+				errs = append(errs, errors.Errorf("%s of domain %s is %q, it must be a positive integer", k, dc.Name, s))
+			}
+		}
+	}
+	return errs
+}
 
 func checkLabel(label string, rType string, domain string, meta map[string]string) error {
 	if label == "@" {
@@ -157,11 +366,19 @@ func checkTargets(rec *models.RecordConfig, domain string) (errs []error) {
 		check(checkIPv4(target))
 	case "AAAA":
 		check(checkIPv6(target))
+	case "CERT":
+		check(checkBase64(target))
 	case "CNAME":
 		check(checkTarget(target))
 		if label == "@" {
 			check(errors.Errorf("cannot create CNAME record for bare domain"))
 		}
+	case "CSYNC":
+		check(checkCSYNC(rec))
+	case "DHCID":
+		check(checkDHCID(target))
+	case "DNAME":
+		check(checkTarget(target))
 	case "MX":
 		check(checkTarget(target))
 	case "NS":
@@ -169,16 +386,42 @@ func checkTargets(rec *models.RecordConfig, domain string) (errs []error) {
 		if label == "@" {
 			check(errors.Errorf("cannot create NS record for bare domain. Use NAMESERVER instead"))
 		}
+	case "OPENPGPKEY":
+		check(checkBase64(target))
+		if !strings.HasSuffix(label, "._openpgpkey") && !strings.Contains(label, "._openpgpkey.") {
+			check(Warning{errors.Errorf("label should be <hash>._openpgpkey. Use OPENPGPKEY_NAME() to compute it")})
+		}
 	case "PTR":
 		check(checkTarget(target))
 	case "NAPTR":
 		check(checkTarget(target))
+	case "RP":
+		check(errors.Wrap(checkTarget(soaRname(target)), "RP mbox"))
+		check(errors.Wrap(checkTarget(rec.RpTxt), "RP txt"))
 	case "ALIAS":
 		check(checkTarget(target))
+	case "SMIMEA":
+		check(checkHex(target))
+		if !strings.HasSuffix(label, "._smimecert") && !strings.Contains(label, "._smimecert.") {
+			check(Warning{errors.Errorf("label should be <hash>._smimecert. Use SMIMEA_NAME() to compute it")})
+		}
+	case "SOA":
+		check(checkSOA(rec))
 	case "SRV":
 		check(checkTarget(target))
-	case "TXT", "IMPORT_TRANSFORM", "CAA", "SSHFP", "TLSA":
+		check(lintSRVTarget(target, domain))
+	case "TXT":
+		for _, e := range lintTXT(rec.TxtStrings) {
+			check(e)
+		}
+	case "IMPORT_TRANSFORM", "CAA", "SSHFP", "TLSA":
+	case "URI":
+		check(checkURI(target))
 	default:
+		if _, ok := models.UnknownTypeNumber(rec.Type); ok {
+			check(checkUnknown(rec))
+			return
+		}
 		if rec.Metadata["orig_custom_type"] != "" {
 			// it is a valid custom type. We perform no validation on target
 			return
@@ -234,10 +477,14 @@ func importTransform(srcDomain, dstDomain *models.DomainConfig, transforms []tra
 			r := newRec()
 			r.SetTarget(transformCNAME(r.GetTargetField(), srcDomain.Name, dstDomain.Name))
 			dstDomain.Records = append(dstDomain.Records, r)
-		case "MX", "NAPTR", "NS", "SRV", "TXT", "CAA", "TLSA":
+		case "CERT", "CSYNC", "DHCID", "DNAME", "MX", "NAPTR", "NS", "OPENPGPKEY", "RP", "SMIMEA", "SOA", "SRV", "TXT", "CAA", "TLSA", "URI":
 			// Not imported.
 			continue
 		default:
+			if _, ok := models.UnknownTypeNumber(rec.Type); ok {
+				// Not imported.
+				continue
+			}
 			return errors.Errorf("import_transform: Unimplemented record type %v (%v)",
 				rec.Type, rec.GetLabel())
 		}
@@ -272,6 +519,10 @@ func NormalizeAndValidateConfig(config *models.DNSConfig) (errs []error) {
 			if domain.KeepUnknown && providers.ProviderHasCabability(pType, providers.CantUseNOPURGE) {
 				errs = append(errs, errors.Errorf("%s uses NO_PURGE which is not supported by %s(%s)", domain.Name, provider.Name, pType))
 			}
+			// OWNER leaves records alone like NO_PURGE does, so it needs the same support.
+			if domain.Owner != "" && providers.ProviderHasCabability(pType, providers.CantUseNOPURGE) {
+				errs = append(errs, errors.Errorf("%s uses OWNER which is not supported by %s(%s)", domain.Name, provider.Name, pType))
+			}
 
 			// Record if any providers do not support TXTMulti:
 			if !providers.ProviderHasCabability(pType, providers.CanUseTXTMulti) {
@@ -279,6 +530,20 @@ func NormalizeAndValidateConfig(config *models.DNSConfig) (errs []error) {
 			}
 		}
 
+		if domain.ReplicateFrom != "" {
+			errs = append(errs, checkReplicateFrom(domain)...)
+		}
+		if domain.Owner != "" {
+			errs = append(errs, checkOwner(domain)...)
+		}
+		if _, err := domain.Contacts(); err != nil {
+			errs = append(errs, errors.Wrapf(err, "%s", domain.Name))
+		}
+		errs = append(errs, checkRegistrarDS(domain)...)
+		errs = append(errs, checkRegistrarLock(domain)...)
+		errs = append(errs, applyTTLPolicy(config.TTLPolicy, domain)...)
+		errs = append(errs, checkThresholds(domain)...)
+
 		// Normalize Nameservers.
 		for _, ns := range domain.Nameservers {
 			ns.Name = dnsutil.AddOrigin(ns.Name, domain.Name)
@@ -286,14 +551,36 @@ func NormalizeAndValidateConfig(config *models.DNSConfig) (errs []error) {
 		}
 		// Normalize Records.
 		models.PostProcessRecords(domain.Records)
+		soaCount := 0
 		for _, rec := range domain.Records {
 			if rec.TTL == 0 {
 				rec.TTL = models.DefaultTTL
 			}
+			if rec.Type == "SOA" {
+				if soaCount++; soaCount == 2 {
+					errs = append(errs, errors.Errorf("domain %s has more than one SOA record", domain.Name))
+				}
+			}
+			if err := checkRecordProviders(rec, domain); err != nil {
+				errs = append(errs, err)
+			}
 			// Validate the unmodified inputs:
 			if err := validateRecordTypes(rec, domain.Name, pTypes); err != nil {
 				errs = append(errs, err)
 			}
+			if h, ok := rec.Metadata["priority_hint"]; ok && h != "first" && h != "last" {
+				errs = append(errs, errors.Errorf("PRIORITY_HINT of %s record %s is %q, it must be \"first\" or \"last\"", rec.Type, rec.GetLabel(), h))
+			}
+			if err := checkHealthCheck(rec); err != nil {
+				errs = append(errs, err)
+			}
+			if s, ok := rec.Metadata["verification"]; ok {
+				if rec.Type != "TXT" {
+					errs = append(errs, errors.Errorf("%s record %s: only TXT records can be verification records", rec.Type, rec.GetLabel()))
+				} else if err := verification.CheckService(s); err != nil {
+					errs = append(errs, err)
+				}
+			}
 			if err := checkLabel(rec.GetLabel(), rec.Type, domain.Name, rec.Metadata); err != nil {
 				errs = append(errs, err)
 			}
@@ -302,7 +589,7 @@ func NormalizeAndValidateConfig(config *models.DNSConfig) (errs []error) {
 			}
 
 			// Canonicalize Targets.
-			if rec.Type == "CNAME" || rec.Type == "MX" || rec.Type == "NAPTR" || rec.Type == "NS" || rec.Type == "SRV" {
+			if rec.Type == "CNAME" || rec.Type == "DNAME" || rec.Type == "MX" || rec.Type == "NAPTR" || rec.Type == "NS" || rec.Type == "SRV" {
 				// #rtype_variations
 				// These record types have a target that is a hostname.
 				// We normalize them to a FQDN so there is less variation to handle.  If a
@@ -310,6 +597,21 @@ func NormalizeAndValidateConfig(config *models.DNSConfig) (errs []error) {
 				rec.SetTarget(dnsutil.AddOrigin(rec.GetTargetField(), domain.Name+"."))
 			} else if rec.Type == "A" || rec.Type == "AAAA" {
 				rec.SetTarget(net.ParseIP(rec.GetTargetField()).String())
+			} else if rec.Type == "SOA" {
+				canonicalizeSOA(rec, domain.Name)
+			} else if rec.Type == "RP" {
+				// The mbox may be given as an email address, like the SOA rname.
+				origin := domain.Name + "."
+				rec.SetTargetRP(dnsutil.AddOrigin(soaRname(rec.GetTargetField()), origin), dnsutil.AddOrigin(rec.RpTxt, origin))
+			} else if rec.Type == "CSYNC" {
+				// Uppercase and sort the type bitmap.
+				rec.SetTargetCSYNC(rec.CsyncSerial, rec.CsyncFlags, rec.GetTargetField())
+			} else if n, ok := models.UnknownTypeNumber(rec.Type); ok {
+				// Lowercase the rdata and remove spaces.
+				rec.SetTargetUnknown(n, rec.GetTargetField())
+			} else if rec.Type == "URI" {
+				// Remove the quotes a URI may have been written with.
+				rec.SetTargetURI(rec.UriPriority, rec.UriWeight, rec.GetTargetField())
 			} else if rec.Type == "PTR" {
 				var err error
 				var name string
@@ -321,6 +623,11 @@ func NormalizeAndValidateConfig(config *models.DNSConfig) (errs []error) {
 				if rec.CaaTag != "issue" && rec.CaaTag != "issuewild" && rec.CaaTag != "iodef" {
 					errs = append(errs, errors.Errorf("CAA tag %s is invalid", rec.CaaTag))
 				}
+			} else if rec.Type == "SMIMEA" {
+				if rec.SmimeaUsage > 3 || rec.SmimeaSelector > 1 || rec.SmimeaMatchingType > 2 {
+					errs = append(errs, errors.Errorf("SMIMEA usage/selector/matchingtype %d/%d/%d is invalid in record %s (domain %s)",
+						rec.SmimeaUsage, rec.SmimeaSelector, rec.SmimeaMatchingType, rec.GetLabel(), domain.Name))
+				}
 			} else if rec.Type == "TLSA" {
 				if rec.TlsaUsage < 0 || rec.TlsaUsage > 3 {
 					errs = append(errs, errors.Errorf("TLSA Usage %d is invalid in record %s (domain %s)",
@@ -345,6 +652,7 @@ func NormalizeAndValidateConfig(config *models.DNSConfig) (errs []error) {
 			// Populate FQDN:
 			rec.SetLabel(rec.GetLabel(), domain.Name)
 		}
+		errs = append(errs, normalizeEnsureAbsent(domain)...)
 	}
 
 	// SPF flattening
@@ -352,6 +660,11 @@ func NormalizeAndValidateConfig(config *models.DNSConfig) (errs []error) {
 		errs = append(errs, ers...)
 	}
 
+	// Replace ALIAS records for providers that can't use them
+	if ers := flattenAliases(config); len(ers) > 0 {
+		errs = append(errs, ers...)
+	}
+
 	// Process IMPORT_TRANSFORM
 	for _, domain := range config.Domains {
 		for _, rec := range domain.Records {
@@ -379,16 +692,39 @@ func NormalizeAndValidateConfig(config *models.DNSConfig) (errs []error) {
 		}
 	}
 
+	// Mark the records of shared zones
+	for _, domain := range config.Domains {
+		ownership.AddMarkers(domain)
+	}
+
+	errs = append(errs, checkViews(config)...)
 	for _, d := range config.Domains {
 		// Check that CNAMES don't have to co-exist with any other records
 		errs = append(errs, checkCNAMEs(d)...)
+		// Check that nothing exists below a DNAME
+		errs = append(errs, checkDNAMEs(d)...)
+		// Check that CNAMEs don't loop, or make long chains
+		errs = append(errs, checkCNAMEChains(config, d)...)
+		// Check that delegations have glue, and match the NS records of the delegated zone
+		errs = append(errs, checkDelegations(config, d)...)
 		// Check that if any advanced record types are used in a domain, every provider for that domain supports them
 		err := checkProviderCapabilities(d)
 		if err != nil {
 			errs = append(errs, err)
 		}
+		// Check the sets of weighted, geo and failover routing
+		errs = append(errs, checkRouting(d)...)
+		// Check that providers that set one value per name and type get one
+		errs = append(errs, checkSingleValues(d)...)
+		// Check that no record is one that IGNORE_NAME or IGNORE_TARGET leaves alone
+		errs = append(errs, checkIgnored(d)...)
+		// Check that no record is both declared and ENSURE_ABSENT
+		errs = append(errs, checkEnsureAbsent(d)...)
 		// Check for duplicates
 		errs = append(errs, checkDuplicates(d.Records)...)
+		errs = append(errs, lintNearDuplicates(d.Records)...)
+		// Check that MX, NS and SRV records don't point at CNAMEs
+		errs = append(errs, lintTargetsAtCNAMEs(config, d)...)
 		// Validate FQDN consistency
 		for _, r := range d.Records {
 			if r.NameFQDN == "" || !strings.HasSuffix(r.NameFQDN, d.Name) {
@@ -418,6 +754,135 @@ func checkCNAMEs(dc *models.DomainConfig) (errs []error) {
 	return
 }
 
+// checkDNAMEs enforces RFC 6672: a DNAME can not coexist with a CNAME or
+// another DNAME at the same name, and no records may exist below it.
+func checkDNAMEs(dc *models.DomainConfig) (errs []error) {
+	dnames := map[string]bool{}
+	for _, r := range dc.Records {
+		if r.Type == "DNAME" {
+			if dnames[r.GetLabelFQDN()] {
+				errs = append(errs, errors.Errorf("Cannot have multiple DNAMEs with same name: %s", r.GetLabelFQDN()))
+			}
+			dnames[r.GetLabelFQDN()] = true
+		}
+	}
+	if len(dnames) == 0 {
+		return
+	}
+	for _, r := range dc.Records {
+		name := r.GetLabelFQDN()
+		if dnames[name] && r.Type == "CNAME" {
+			errs = append(errs, errors.Errorf("Cannot have CNAME and DNAME record with same name: %s", name))
+		}
+		for owner := range dnames {
+			if strings.HasSuffix(name, "."+owner) {
+				errs = append(errs, errors.Errorf("Cannot have %s record %s below DNAME %s", r.Type, name, owner))
+			}
+		}
+	}
+	return
+}
+
+// defaultMaxCNAMEChain is the number of CNAMEs after which a chain gets
+// a warning, unless MAX_CNAME_CHAIN() says otherwise.
+const defaultMaxCNAMEChain = 3
+
+// checkCNAMEChains follows the CNAME and ALIAS records of dc through the
+// names of all the domains of config. A chain that comes back to its
+// start is an error, as nothing can resolve it. A chain longer than the
+// max_cname_chain of dc, which resolvers may give up on, and one that
+// goes through a CNAME that also has other records, are warnings.
+func checkCNAMEChains(config *models.DNSConfig, dc *models.DomainConfig) (errs []error) {
+	max := defaultMaxCNAMEChain
+	if s, ok := dc.Metadata["max_cname_chain"]; ok {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			return []error{errors.Errorf("%s: MAX_CNAME_CHAIN(%q) must be a number of at least 1", dc.Name, s)}
+		}
+		max = n
+	}
+
+	targets := map[string]string{} // CNAME and ALIAS targets, by name.
+	cnames := map[string]bool{}
+	others := map[string]map[string]bool{} // The types of the other records, by name.
+	for _, d := range config.Domains {
+		if d.Tag != "" && dc.Tag != "" && d.Tag != dc.Tag {
+			continue // Another view of split horizon domains.
+		}
+		for _, r := range d.Records {
+			name := strings.ToLower(r.GetLabelFQDN())
+			if r.Type == "CNAME" || r.Type == "ALIAS" {
+				targets[name] = strings.ToLower(strings.TrimSuffix(r.GetTargetField(), "."))
+				cnames[name] = r.Type == "CNAME"
+				continue
+			}
+			if others[name] == nil {
+				others[name] = map[string]bool{}
+			}
+			others[name][r.Type] = true
+		}
+	}
+
+	for _, r := range dc.Records {
+		if r.Type != "CNAME" && r.Type != "ALIAS" {
+			continue
+		}
+		start := strings.ToLower(r.GetLabelFQDN())
+		chain := []string{start}
+		seen := map[string]bool{start: true}
+		for name := targets[start]; ; name = targets[name] {
+			chain = append(chain, name)
+			if name == start {
+				errs = append(errs, errors.Errorf("CNAME loop: %s", strings.Join(chain, " -> ")))
+				break
+			}
+			if seen[name] {
+				break // A loop that doesn't include r, which its records report.
+			}
+			seen[name] = true
+			if _, ok := targets[name]; !ok {
+				if n := len(chain) - 1; n > max {
+					errs = append(errs, Warning{errors.Errorf("Chain of %d CNAMEs, more than %d: %s; point %s at the end of the chain", n, max, strings.Join(chain, " -> "), start)})
+				}
+				break
+			}
+			if cnames[name] && len(others[name]) != 0 {
+				var types []string
+				for t := range others[name] {
+					types = append(types, t)
+				}
+				sort.Strings(types)
+				errs = append(errs, Warning{errors.Errorf("In %s %s: target %s is a CNAME that also has %s records, which resolvers may answer instead", r.Type, start, name, strings.Join(types, ", "))})
+				break
+			}
+		}
+	}
+	return errs
+}
+
+// checkSingleValues checks that providers that are CantUseMultipleValues
+// get at most one record of each name and type.
+func checkSingleValues(dc *models.DomainConfig) (errs []error) {
+	for _, provider := range dc.DNSProviderInstances {
+		if !providers.ProviderHasCabability(provider.ProviderType, providers.CantUseMultipleValues) {
+			continue
+		}
+		counts := map[models.RecordKey]int{}
+		for _, r := range dc.Records {
+			if r.ForProvider(provider.Name) {
+				counts[r.Key()]++
+			}
+		}
+		for _, r := range dc.Records {
+			if k := r.Key(); counts[k] > 1 {
+				errs = append(errs, errors.Errorf("%s has %d %s records, but DNS provider %s(%s) can only set one value for each name and type", k.NameFQDN, counts[k], k.Type, provider.Name, provider.ProviderType))
+				counts[k] = 0
+			}
+		}
+	}
+	return errs
+}
+
 func checkDuplicates(records []*models.RecordConfig) (errs []error) {
 	seen := map[string]*models.RecordConfig{}
 	for _, r := range records {
@@ -430,6 +895,176 @@ func checkDuplicates(records []*models.RecordConfig) (errs []error) {
 	return errs
 }
 
+// checkIgnored checks the patterns of IGNORE_NAME() and IGNORE_TARGET(),
+// and that they don't match records of the domain: dnscontrol can't both
+// manage a record and leave it alone.
+func checkIgnored(dc *models.DomainConfig) (errs []error) {
+	ignores, err := diff.CompileIgnores(dc)
+	if err != nil {
+		return []error{errors.Wrapf(err, "%s", dc.Name)}
+	}
+	for _, records := range []models.Records{dc.Records, dc.EnsureAbsent} {
+		for _, r := range records {
+			if m := ignores.Match(r); m != "" {
+				errs = append(errs, errors.Errorf("%s record %s matches %s, so it can't be managed", r.Type, r.GetLabelFQDN(), m))
+			}
+		}
+	}
+	return errs
+}
+
+// normalizeEnsureAbsent checks the records of ENSURE_ABSENT(), and
+// canonicalizes their names and targets as those of the domain, so the
+// differ can find them in the zone. TTLs don't matter.
+func normalizeEnsureAbsent(dc *models.DomainConfig) (errs []error) {
+	models.PostProcessRecords(dc.EnsureAbsent)
+	for _, rec := range dc.EnsureAbsent {
+		if err := validateRecordTypes(rec, dc.Name, nil); err != nil {
+			errs = append(errs, err)
+		}
+		for _, err := range checkTargets(rec, dc.Name) {
+			if _, ok := err.(Warning); !ok {
+				errs = append(errs, errors.Wrap(err, "ENSURE_ABSENT"))
+			}
+		}
+		switch rec.Type { // #rtype_variations
+		case "CNAME", "DNAME", "MX", "NAPTR", "NS", "SRV":
+			rec.SetTarget(dnsutil.AddOrigin(rec.GetTargetField(), dc.Name+"."))
+		case "A", "AAAA":
+			rec.SetTarget(net.ParseIP(rec.GetTargetField()).String())
+		}
+		rec.SetLabel(rec.GetLabel(), dc.Name)
+	}
+	return errs
+}
+
+// checkEnsureAbsent checks that no record is both declared and in
+// ENSURE_ABSENT().
+func checkEnsureAbsent(dc *models.DomainConfig) (errs []error) {
+	for _, a := range dc.EnsureAbsent {
+		for _, r := range dc.Records {
+			if a.Key() == r.Key() && a.GetTargetCombined() == r.GetTargetCombined() {
+				errs = append(errs, errors.Errorf("%s record %s %s is both declared and in ENSURE_ABSENT", r.Type, r.GetLabelFQDN(), r.GetTargetCombined()))
+			}
+		}
+	}
+	return errs
+}
+
+// checkOwner checks a domain that uses OWNER().
+func checkOwner(dc *models.DomainConfig) (errs []error) {
+	if err := ownership.CheckOwner(dc.Owner); err != nil {
+		errs = append(errs, errors.Wrapf(err, "%s", dc.Name))
+	}
+	if dc.ReplicateFrom != "" {
+		errs = append(errs, errors.Errorf("%s uses REPLICATE_FROM(%s) and can not use OWNER()", dc.Name, dc.ReplicateFrom))
+	}
+	for _, r := range dc.Records {
+		if ownership.IsMarker(r.GetLabel()) {
+			errs = append(errs, errors.Errorf("%s record %s: names starting with _dnscontrol- are reserved for the markers of OWNER()", r.Type, r.GetLabel()))
+		}
+	}
+	return errs
+}
+
+// checkRegistrarDS checks the DS records of REGISTRAR_DS(), and that only
+// one way of declaring them is used.
+func checkRegistrarDS(dc *models.DomainConfig) (errs []error) {
+	if dc.NoRegistrarDS && len(dc.RegistrarDS) != 0 {
+		errs = append(errs, errors.Errorf("%s can not use both REGISTRAR_DS() and REGISTRAR_DS_NONE", dc.Name))
+	}
+	if dc.AutoDS && (dc.NoRegistrarDS || len(dc.RegistrarDS) != 0) {
+		errs = append(errs, errors.Errorf("%s can not use REGISTRAR_DS_AUTO with REGISTRAR_DS() or REGISTRAR_DS_NONE", dc.Name))
+	}
+	for _, ds := range dc.RegistrarDS {
+		if err := ds.Validate(); err != nil {
+			errs = append(errs, errors.Wrapf(err, "%s", dc.Name))
+		}
+	}
+	return errs
+}
+
+// checkRegistrarLock checks the state of REGISTRAR_LOCK(), and that the
+// registrar can lock domains, so that check and preview report it rather
+// than push.
+func checkRegistrarLock(dc *models.DomainConfig) (errs []error) {
+	if dc.RegistrarLock == "" {
+		return nil
+	}
+	if l := dc.RegistrarLock; l != "on" && l != "off" {
+		errs = append(errs, errors.Errorf("%s: REGISTRAR_LOCK(%q) must be \"on\" or \"off\"", dc.Name, l))
+	}
+	if reg := dc.RegistrarInstance; reg != nil && !providers.ProviderHasCabability(reg.ProviderType, providers.CanUseRegistrarLock) {
+		errs = append(errs, errors.Errorf("%s uses REGISTRAR_LOCK() which is not supported by registrar %s(%s)", dc.Name, reg.Name, reg.ProviderType))
+	}
+	return errs
+}
+
+var isViewTag = regexp.MustCompile(`^[\w-]+$`)
+
+// checkViews checks the views of split horizon domains, such as
+// D("example.com!internal"). Each view must have its own DNS providers,
+// and only one view of a domain can have a registrar, other than NONE,
+// to set its nameservers.
+func checkViews(config *models.DNSConfig) (errs []error) {
+	views := map[string][]*models.DomainConfig{}
+	var names []string
+	for _, d := range config.Domains {
+		if d.Tag != "" && !isViewTag.MatchString(d.Tag) {
+			errs = append(errs, errors.Errorf("%s: the view %q must be made of letters, digits, \"-\" and \"_\"", d.UniqueName(), d.Tag))
+		}
+		name := strings.ToLower(d.Name)
+		if views[name] == nil {
+			names = append(names, name)
+		}
+		views[name] = append(views[name], d)
+	}
+	for _, name := range names {
+		if len(views[name]) < 2 {
+			continue
+		}
+		servedBy := map[string]string{}
+		registrar := ""
+		for _, d := range views[name] {
+			for _, p := range d.DNSProviderInstances {
+				if other, ok := servedBy[p.Name]; ok {
+					errs = append(errs, errors.Errorf("%s and %s are views of the same domain, and can't both use the DNS provider %s", other, d.UniqueName(), p.Name))
+				}
+				servedBy[p.Name] = d.UniqueName()
+			}
+			if d.RegistrarInstance != nil && d.RegistrarInstance.ProviderType != "NONE" {
+				if registrar != "" {
+					errs = append(errs, errors.Errorf("%s and %s are views of the same domain, and can't both have a registrar; use NONE for one of them", registrar, d.UniqueName()))
+				}
+				registrar = d.UniqueName()
+			}
+		}
+	}
+	return errs
+}
+
+// checkReplicateFrom checks a domain that copies its records from another provider.
+func checkReplicateFrom(dc *models.DomainConfig) (errs []error) {
+	if len(dc.Records) != 0 {
+		errs = append(errs, errors.Errorf("%s uses REPLICATE_FROM(%s) and can not have records of its own", dc.Name, dc.ReplicateFrom))
+	}
+	found, targets := false, 0
+	for _, provider := range dc.DNSProviderInstances {
+		if provider.Name == dc.ReplicateFrom {
+			found = true
+		} else {
+			targets++
+		}
+	}
+	if !found {
+		errs = append(errs, errors.Errorf("%s uses REPLICATE_FROM(%s) but %s is not one of its DNS providers", dc.Name, dc.ReplicateFrom, dc.ReplicateFrom))
+	}
+	if targets == 0 {
+		errs = append(errs, errors.Errorf("%s uses REPLICATE_FROM(%s) but has no other DNS provider to replicate to", dc.Name, dc.ReplicateFrom))
+	}
+	return errs
+}
+
 func checkProviderCapabilities(dc *models.DomainConfig) error {
 	types := []struct {
 		rType string
@@ -437,23 +1072,34 @@ func checkProviderCapabilities(dc *models.DomainConfig) error {
 	}{
 		{"ALIAS", providers.CanUseAlias},
 		{"PTR", providers.CanUsePTR},
+		{"OPENPGPKEY", providers.CanUseOPENPGPKEY},
+		{"SMIMEA", providers.CanUseSMIMEA},
+		{"SOA", providers.CanUseSOA},
 		{"SRV", providers.CanUseSRV},
 		{"CAA", providers.CanUseCAA},
+		{"CERT", providers.CanUseCERT},
+		{"CSYNC", providers.CanUseCSYNC},
+		{"DHCID", providers.CanUseDHCID},
+		{"RP", providers.CanUseRP},
+		{"UNKNOWN", providers.CanUseUNKNOWN},
+		{"DNAME", providers.CanUseDNAME},
 		{"TLSA", providers.CanUseTLSA},
+		{"URI", providers.CanUseURI},
 	}
 	for _, ty := range types {
-		hasAny := false
-		for _, r := range dc.Records {
-			if r.Type == ty.rType {
-				hasAny = true
-				break
-			}
-		}
-		if !hasAny {
-			continue
-		}
 		for _, provider := range dc.DNSProviderInstances {
-			if !providers.ProviderHasCabability(provider.ProviderType, ty.cap) {
+			hasAny := false
+			for _, r := range dc.Records {
+				rType := r.Type
+				if _, ok := models.UnknownTypeNumber(rType); ok {
+					rType = "UNKNOWN"
+				}
+				if rType == ty.rType && r.ForProvider(provider.Name) {
+					hasAny = true
+					break
+				}
+			}
+			if hasAny && !providers.ProviderHasCabability(provider.ProviderType, ty.cap) {
 				return errors.Errorf("Domain %s uses %s records, but DNS provider type %s does not support them", dc.Name, ty.rType, provider.ProviderType)
 			}
 		}
@@ -4,8 +4,10 @@ import (
 	"testing"
 
 	"fmt"
+	"strings"
 
 	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/providers"
 )
 
 func TestCheckLabel(t *testing.T) {
@@ -83,6 +85,7 @@ func Test_assert_valid_target(t *testing.T) {
 		{"foo bar", true},
 		{"elb21.freshdesk.com/", true},
 		{"elb21.freshdesk.com/.", true},
+		{"130.128/26.2.0.192.in-addr.arpa.", false},
 	}
 
 	for _, test := range tests {
@@ -195,6 +198,469 @@ func TestCNAMEMutex(t *testing.T) {
 	}
 }
 
+func TestDNAMEMutex(t *testing.T) {
+	var recA = &models.RecordConfig{Type: "DNAME"}
+	recA.SetLabel("foo", "example.com")
+	recA.SetTarget("example.net.")
+	tests := []struct {
+		rType string
+		name  string
+		fail  bool
+	}{
+		{"A", "foo", false},
+		{"A", "bar.foo", true},
+		{"A", "foo2", false},
+		{"CNAME", "foo", true},
+		{"DNAME", "foo", true},
+		{"TXT", "a.b.foo", true},
+	}
+	for _, tst := range tests {
+		t.Run(fmt.Sprintf("%s %s", tst.rType, tst.name), func(t *testing.T) {
+			var recB = &models.RecordConfig{Type: tst.rType}
+			recB.SetLabel(tst.name, "example.com")
+			recB.SetTarget("example2.com.")
+			dc := &models.DomainConfig{
+				Name:    "example.com",
+				Records: []*models.RecordConfig{recA, recB},
+			}
+			errs := checkDNAMEs(dc)
+			if errs != nil && !tst.fail {
+				t.Error("Got error but expected none")
+			}
+			if errs == nil && tst.fail {
+				t.Error("Expected error but got none")
+			}
+		})
+	}
+}
+
+func TestCheckIgnored(t *testing.T) {
+	dc := &models.DomainConfig{
+		Name: "example.com",
+		Records: []*models.RecordConfig{
+			makeRC("www", "example.com", "1.2.3.4", models.RecordConfig{Type: "A"}),
+			makeRC("foo", "example.com", "1.2.3.4", models.RecordConfig{Type: "A"}),
+			makeRC("bar", "example.com", "foo.example.net.", models.RecordConfig{Type: "CNAME"}),
+		},
+		IgnoredNames:   []*models.Ignore{{Pattern: "www", Types: "TXT"}, {Pattern: "f*"}},
+		IgnoredTargets: []*models.Ignore{{Pattern: "*.example.net.", Types: "CNAME"}},
+	}
+	if errs := checkIgnored(dc); len(errs) != 2 {
+		t.Errorf("got %v, want errors for foo and bar", errs)
+	}
+	dc.IgnoredNames = []*models.Ignore{{Pattern: "[a-"}}
+	if errs := checkIgnored(dc); len(errs) != 1 {
+		t.Errorf("got %v, want an error for the bad pattern", errs)
+	}
+}
+
+func TestEnsureAbsent(t *testing.T) {
+	dc := &models.DomainConfig{
+		Name: "example.com",
+		Records: []*models.RecordConfig{
+			makeRC("www", "example.com", "www.example.net.", models.RecordConfig{Type: "CNAME"}),
+		},
+		EnsureAbsent: []*models.RecordConfig{
+			makeRC("www", "example.com", "www.example.net.", models.RecordConfig{Type: "CNAME"}),
+			makeRC("old", "example.com", "app", models.RecordConfig{Type: "CNAME"}),
+		},
+	}
+	if errs := normalizeEnsureAbsent(dc); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if got := dc.EnsureAbsent[1].GetTargetField(); got != "app.example.com." {
+		t.Errorf("target of old is %s, want app.example.com.", got)
+	}
+	if errs := checkEnsureAbsent(dc); len(errs) != 1 {
+		t.Errorf("got %v, want an error for www", errs)
+	}
+}
+
+func TestReplicateFrom(t *testing.T) {
+	rec := &models.RecordConfig{Type: "A"}
+	rec.SetLabel("foo", "example.com")
+	rec.SetTarget("1.2.3.4")
+	src := &models.DNSProviderInstance{ProviderBase: models.ProviderBase{Name: "src"}}
+	dst := &models.DNSProviderInstance{ProviderBase: models.ProviderBase{Name: "dst"}}
+	tests := []struct {
+		name      string
+		records   []*models.RecordConfig
+		providers []*models.DNSProviderInstance
+		fail      bool
+	}{
+		{"ok", nil, []*models.DNSProviderInstance{src, dst}, false},
+		{"records", []*models.RecordConfig{rec}, []*models.DNSProviderInstance{src, dst}, true},
+		{"no source", nil, []*models.DNSProviderInstance{dst}, true},
+		{"no target", nil, []*models.DNSProviderInstance{src}, true},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			dc := &models.DomainConfig{
+				Name:                 "example.com",
+				ReplicateFrom:        "src",
+				Records:              tst.records,
+				DNSProviderInstances: tst.providers,
+			}
+			errs := checkReplicateFrom(dc)
+			if errs != nil && !tst.fail {
+				t.Errorf("Got errors but expected none: %v", errs)
+			}
+			if errs == nil && tst.fail {
+				t.Error("Expected error but got none")
+			}
+		})
+	}
+}
+
+func TestRecordProviders(t *testing.T) {
+	providers.RegisterDomainServiceProviderType("FAKEPTR", nil, providers.CanUsePTR)
+	providers.RegisterDomainServiceProviderType("FAKENOPTR", nil)
+	rec := func(list string) *models.RecordConfig {
+		r := &models.RecordConfig{Type: "PTR", Metadata: map[string]string{"providers": list}}
+		r.SetLabel("1", "2.0.192.in-addr.arpa")
+		r.SetTarget("www.example.com.")
+		return r
+	}
+	tests := []struct {
+		name string
+		rec  *models.RecordConfig
+		fail bool
+	}{
+		{"ptr", rec("ptr"), false},
+		{"both", rec("ptr, noptr"), true},
+		{"unknown", rec("ptr,other"), true},
+		{"empty", rec(""), true},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			dc := &models.DomainConfig{
+				Name:             "2.0.192.in-addr.arpa",
+				Records:          models.Records{tst.rec},
+				DNSProviderNames: map[string]int{"ptr": -1, "noptr": -1},
+				DNSProviderInstances: []*models.DNSProviderInstance{
+					{ProviderBase: models.ProviderBase{Name: "ptr", ProviderType: "FAKEPTR"}},
+					{ProviderBase: models.ProviderBase{Name: "noptr", ProviderType: "FAKENOPTR"}},
+				},
+			}
+			err := checkRecordProviders(tst.rec, dc)
+			if err == nil {
+				err = checkProviderCapabilities(dc)
+			}
+			if err != nil && !tst.fail {
+				t.Errorf("Got error but expected none: %v", err)
+			}
+			if err == nil && tst.fail {
+				t.Error("Expected error but got none")
+			}
+		})
+	}
+}
+
+func TestCheckSingleValues(t *testing.T) {
+	providers.RegisterDomainServiceProviderType("FAKESINGLE", nil, providers.CantUseMultipleValues)
+	providers.RegisterDomainServiceProviderType("FAKEMULTI", nil)
+	a := func(label, ip, list string) *models.RecordConfig {
+		r := &models.RecordConfig{Type: "A", Metadata: map[string]string{}}
+		if list != "" {
+			r.Metadata["providers"] = list
+		}
+		r.SetLabel(label, "example.com")
+		r.SetTarget(ip)
+		return r
+	}
+	tests := []struct {
+		name    string
+		records models.Records
+		errs    int
+	}{
+		{"one each", models.Records{a("www", "192.0.2.1", ""), a("home", "192.0.2.2", "")}, 0},
+		{"two", models.Records{a("www", "192.0.2.1", ""), a("www", "192.0.2.2", ""), a("www", "192.0.2.3", "")}, 1},
+		{"other provider", models.Records{a("www", "192.0.2.1", ""), a("www", "192.0.2.2", "multi")}, 0},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			dc := &models.DomainConfig{
+				Name:    "example.com",
+				Records: tst.records,
+				DNSProviderInstances: []*models.DNSProviderInstance{
+					{ProviderBase: models.ProviderBase{Name: "single", ProviderType: "FAKESINGLE"}},
+					{ProviderBase: models.ProviderBase{Name: "multi", ProviderType: "FAKEMULTI"}},
+				},
+			}
+			if errs := checkSingleValues(dc); len(errs) != tst.errs {
+				t.Errorf("got errors %v, want %d", errs, tst.errs)
+			}
+		})
+	}
+}
+
+func TestCheckViews(t *testing.T) {
+	view := func(tag, registrar string, providers ...string) *models.DomainConfig {
+		dc := &models.DomainConfig{Name: "example.com", Tag: tag,
+			RegistrarInstance: &models.RegistrarInstance{ProviderBase: models.ProviderBase{ProviderType: registrar}}}
+		for _, p := range providers {
+			dc.DNSProviderInstances = append(dc.DNSProviderInstances, &models.DNSProviderInstance{ProviderBase: models.ProviderBase{Name: p}})
+		}
+		return dc
+	}
+	tests := []struct {
+		name    string
+		domains []*models.DomainConfig
+		fail    bool
+	}{
+		{"ok", []*models.DomainConfig{view("", "NAMEDOTCOM", "cf"), view("internal", "NONE", "infoblox")}, false},
+		{"shared provider", []*models.DomainConfig{view("", "NONE", "cf"), view("internal", "NONE", "cf", "infoblox")}, true},
+		{"two registrars", []*models.DomainConfig{view("", "NAMEDOTCOM", "cf"), view("internal", "NAMEDOTCOM", "infoblox")}, true},
+		{"bad tag", []*models.DomainConfig{view("in ternal", "NONE", "infoblox")}, true},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			errs := checkViews(&models.DNSConfig{Domains: tst.domains})
+			if errs != nil && !tst.fail {
+				t.Errorf("Got errors but expected none: %v", errs)
+			}
+			if errs == nil && tst.fail {
+				t.Error("Expected error but got none")
+			}
+		})
+	}
+}
+
+func TestCheckRegistrarLock(t *testing.T) {
+	providers.RegisterRegistrarType("FAKELOCKER", nil, providers.CanUseRegistrarLock)
+	providers.RegisterRegistrarType("FAKENOLOCKER", nil)
+	tests := []struct {
+		name      string
+		lock      string
+		registrar string
+		errs      int
+	}{
+		{"unmanaged", "", "FAKENOLOCKER", 0},
+		{"on", "on", "FAKELOCKER", 0},
+		{"off", "off", "FAKELOCKER", 0},
+		{"bad state", "yes", "FAKELOCKER", 1},
+		{"registrar can't lock", "on", "FAKENOLOCKER", 1},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			dc := &models.DomainConfig{Name: "example.com", RegistrarLock: tst.lock,
+				RegistrarInstance: &models.RegistrarInstance{ProviderBase: models.ProviderBase{Name: "reg", ProviderType: tst.registrar}}}
+			if errs := checkRegistrarLock(dc); len(errs) != tst.errs {
+				t.Errorf("got errors %v, want %d", errs, tst.errs)
+			}
+		})
+	}
+}
+
+func TestCheckCNAMEChains(t *testing.T) {
+	cname := func(label, target string) *models.RecordConfig {
+		return makeRC(label, "example.com", target, models.RecordConfig{Type: "CNAME"})
+	}
+	a := func(label string) *models.RecordConfig {
+		return makeRC(label, "example.com", "10.1.2.3", models.RecordConfig{Type: "A"})
+	}
+	tests := []struct {
+		name     string
+		records  []*models.RecordConfig
+		max      string
+		errors   int
+		warnings int
+	}{
+		{"short", []*models.RecordConfig{cname("www", "web.example.com."), cname("web", "lb.example.com."), a("lb")}, "", 0, 0},
+		{"outside", []*models.RecordConfig{cname("www", "web.example.net.")}, "", 0, 0},
+		{"long", []*models.RecordConfig{cname("a", "b.example.com."), cname("b", "c.example.com."), cname("c", "d.example.com."), cname("d", "e.example.com.")}, "", 0, 1},
+		{"max", []*models.RecordConfig{cname("www", "web.example.com."), cname("web", "lb.example.com."), a("lb")}, "1", 0, 1},
+		{"bad max", []*models.RecordConfig{a("lb")}, "0", 1, 0},
+		{"loop", []*models.RecordConfig{cname("a", "b.example.com."), cname("b", "A.example.com.")}, "", 2, 0},
+		{"into a loop", []*models.RecordConfig{cname("c", "a.example.com."), cname("a", "b.example.com."), cname("b", "a.example.com.")}, "", 2, 0},
+		{"other data", []*models.RecordConfig{cname("www", "web.example.com."), cname("web", "lb.example.com."), a("web")}, "", 0, 1},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			dc := &models.DomainConfig{Name: "example.com", Records: tst.records, Metadata: map[string]string{}}
+			if tst.max != "" {
+				dc.Metadata["max_cname_chain"] = tst.max
+			}
+			errors, warnings := 0, 0
+			for _, err := range checkCNAMEChains(&models.DNSConfig{Domains: []*models.DomainConfig{dc}}, dc) {
+				if _, ok := err.(Warning); ok {
+					warnings++
+				} else {
+					errors++
+				}
+			}
+			if errors != tst.errors || warnings != tst.warnings {
+				t.Errorf("got %d errors and %d warnings, want %d and %d", errors, warnings, tst.errors, tst.warnings)
+			}
+		})
+	}
+}
+
+func TestCheckOwner(t *testing.T) {
+	rec := func(label string) *models.RecordConfig {
+		r := &models.RecordConfig{Type: "TXT"}
+		r.SetLabel(label, "example.com")
+		r.SetTargetTXT("x")
+		return r
+	}
+	tests := []struct {
+		name      string
+		owner     string
+		replicate string
+		records   []*models.RecordConfig
+		fail      bool
+	}{
+		{"ok", "team-a", "", []*models.RecordConfig{rec("www")}, false},
+		{"bad owner", "team a", "", nil, true},
+		{"replicate", "team-a", "src", nil, true},
+		{"marker", "team-a", "", []*models.RecordConfig{rec("_dnscontrol-a.www")}, true},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			dc := &models.DomainConfig{
+				Name:          "example.com",
+				Owner:         tst.owner,
+				ReplicateFrom: tst.replicate,
+				Records:       tst.records,
+			}
+			errs := checkOwner(dc)
+			if errs != nil && !tst.fail {
+				t.Errorf("Got errors but expected none: %v", errs)
+			}
+			if errs == nil && tst.fail {
+				t.Error("Expected error but got none")
+			}
+		})
+	}
+}
+
+func TestCheckURI(t *testing.T) {
+	tests := []struct {
+		uri  string
+		fail bool
+	}{
+		{"https://www.example.com/", false},
+		{`"ftp://ftp.example.com/public"`, false},
+		{"www.example.com", true},
+		{"https://exa mple.com/%zz", true},
+	}
+	for _, tst := range tests {
+		t.Run(tst.uri, func(t *testing.T) {
+			err := checkURI(tst.uri)
+			if err != nil && !tst.fail {
+				t.Errorf("Got error but expected none: %s", err)
+			}
+			if err == nil && tst.fail {
+				t.Error("Expected error but got none")
+			}
+		})
+	}
+}
+
+func TestCheckCSYNC(t *testing.T) {
+	tests := []struct {
+		label, types string
+		flags        uint16
+		fail, warn   bool
+	}{
+		{"@", "A NS AAAA", 3, false, false},
+		{"@", "NS MX", 0, false, true},
+		{"@", "NS", 4, true, false},
+		{"@", "", 0, true, false},
+		{"www", "NS", 0, true, false},
+	}
+	for _, tst := range tests {
+		t.Run(tst.label+" "+tst.types, func(t *testing.T) {
+			rec := &models.RecordConfig{Type: "CSYNC", CsyncFlags: tst.flags}
+			rec.SetLabel(tst.label, "example.com")
+			rec.SetTarget(tst.types)
+			err := checkCSYNC(rec)
+			_, isWarning := err.(Warning)
+			if (err != nil && !isWarning) != tst.fail {
+				t.Errorf("expected fail=%v, got %v", tst.fail, err)
+			}
+			if isWarning != tst.warn {
+				t.Errorf("expected warning=%v, got %v", tst.warn, err)
+			}
+		})
+	}
+}
+
+func TestCheckDHCID(t *testing.T) {
+	tests := []struct {
+		digest string
+		fail   bool
+	}{
+		{"AAIBY2/AuCccgoJbsaxcQc9TUapptP69lOjxfNuVAA2kjEA=", false},
+		{"AAIBY2/AuCccgoJbsaxcQc9TUapptP69lOjxfNuVAA2k", true}, // truncated
+		{"AAE=", true},
+		{"not base64!", true},
+	}
+	for _, tst := range tests {
+		t.Run(tst.digest, func(t *testing.T) {
+			err := checkDHCID(tst.digest)
+			if (err != nil) != tst.fail {
+				t.Errorf("expected fail=%v, got %v", tst.fail, err)
+			}
+		})
+	}
+}
+
+func TestCheckRP(t *testing.T) {
+	tests := []struct {
+		mbox, txt string
+		fail      bool
+	}{
+		{"hostmaster@example.com", ".", false},
+		{"hostmaster.example.com.", "contact", false},
+		{"hostmaster.example.com", ".", true}, // no trailing dot
+		{"host master@example.com", ".", true},
+		{"hostmaster@example.com", "", true},
+	}
+	for _, tst := range tests {
+		t.Run(tst.mbox+" "+tst.txt, func(t *testing.T) {
+			rec := &models.RecordConfig{Type: "RP"}
+			rec.SetLabel("@", "example.com")
+			rec.SetTargetRP(tst.mbox, tst.txt)
+			errs := checkTargets(rec, "example.com")
+			if (len(errs) != 0) != tst.fail {
+				t.Errorf("expected fail=%v, got %v", tst.fail, errs)
+			}
+		})
+	}
+}
+
+func TestCheckThresholds(t *testing.T) {
+	dc := &models.DomainConfig{Name: "example.com", Metadata: map[string]string{"max_changes": "10", "max_deletes": "0"}}
+	errs := checkThresholds(dc)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "max_deletes") {
+		t.Errorf("expected an error about max_deletes, got %v", errs)
+	}
+}
+
+func TestCheckUnknown(t *testing.T) {
+	tests := []struct {
+		rtype, rdata string
+		fail         bool
+	}{
+		{"TYPE65280", "0a0b0c", false},
+		{"TYPE13", "025043054c696e7578", false}, // HINFO
+		{"TYPE65280", "xyz", true},
+		{"TYPE1", "c0000201", true},  // A
+		{"TYPE62", "00000001", true}, // CSYNC
+	}
+	for _, tst := range tests {
+		t.Run(tst.rtype, func(t *testing.T) {
+			rec := &models.RecordConfig{Type: tst.rtype}
+			rec.SetTarget(tst.rdata)
+			err := checkUnknown(rec)
+			if (err != nil) != tst.fail {
+				t.Errorf("expected fail=%v, got %v", tst.fail, err)
+			}
+		})
+	}
+}
+
 func TestCAAValidation(t *testing.T) {
 	config := &models.DNSConfig{
 		Domains: []*models.DomainConfig{
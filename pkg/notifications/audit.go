@@ -0,0 +1,126 @@
+package notifications
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/StackExchange/dnscontrol/pkg/redact"
+	"github.com/pkg/errors"
+)
+
+// AuditEntry is a line of the audit log: a correction push ran.
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	Run      string    `json:"run"` // The same for all the corrections of a push.
+	Domain   string    `json:"domain"`
+	Provider string    `json:"provider"`
+	Msg      string    `json:"correction"`
+	Result   string    `json:"result"` // "ok" or "error".
+	Error    string    `json:"error,omitempty"`
+	Caller   *Caller   `json:"caller"`
+}
+
+// Caller identifies who ran dnscontrol.
+type Caller struct {
+	User    string   `json:"user"`
+	Host    string   `json:"host"`
+	CIActor string   `json:"ci_actor,omitempty"` // The user who started the CI job, if any.
+	CIJob   string   `json:"ci_job,omitempty"`   // A URL or ID of the CI job, if any.
+	Git     *gitInfo `json:"git,omitempty"`
+}
+
+// ciEnv lists the environment variables CI systems name the actor and
+// the job with, in order of preference.
+var ciEnv = struct{ actor, job []string }{
+	actor: []string{"GITHUB_ACTOR", "GITLAB_USER_LOGIN", "BUILDKITE_BUILD_CREATOR", "BUILD_REQUESTEDFOR", "CIRCLE_USERNAME", "DNSCONTROL_ACTOR"},
+	job:   []string{"CI_JOB_URL", "BUILDKITE_BUILD_URL", "CIRCLE_BUILD_URL", "BUILD_URL", "GITHUB_RUN_ID"},
+}
+
+func firstEnv(names []string) string {
+	for _, n := range names {
+		if v := os.Getenv(n); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// currentCaller describes the user running dnscontrol. Tests replace it.
+var currentCaller = func() *Caller {
+	c := &Caller{User: os.Getenv("USER"), CIActor: firstEnv(ciEnv.actor), CIJob: firstEnv(ciEnv.job), Git: currentGit()}
+	if u, err := user.Current(); err == nil {
+		c.User = u.Username
+	}
+	c.Host, _ = os.Hostname()
+	return c
+}
+
+// auditLog appends an AuditEntry to a file for each correction push
+// runs. Previews are not logged.
+type auditLog struct {
+	mu     sync.Mutex
+	f      *os.File
+	run    string
+	caller *Caller
+}
+
+// NewAuditLog opens the audit log at path, creating it if needed, and
+// returns a Notifier that appends to it. Entries are written as they
+// happen, one JSON object per line, so the log is complete up to a
+// crash. The file is only ever appended to.
+func NewAuditLog(path string) (Notifier, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0640)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening the audit log")
+	}
+	b := make([]byte, 8)
+	rand.Read(b)
+	return &auditLog{f: f, run: hex.EncodeToString(b), caller: currentCaller()}, nil
+}
+
+func (a *auditLog) Notify(domain, provider, msg string, err error, preview bool) {
+	if preview {
+		return
+	}
+	e := &AuditEntry{
+		Time:     time.Now().UTC(),
+		Run:      a.run,
+		Domain:   domain,
+		Provider: provider,
+		Msg:      redact.String(msg),
+		Result:   "ok",
+		Caller:   a.caller,
+	}
+	if err != nil {
+		e.Result, e.Error = "error", redact.String(err.Error())
+	}
+	b, jerr := json.Marshal(e)
+	if jerr != nil {
+		printer.Warnf("Could not write to the audit log: %s\n", jerr)
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, werr := a.f.Write(append(b, '\n')); werr != nil {
+		printer.Warnf("Could not write to the audit log: %s\n", werr)
+		return
+	}
+	a.f.Sync()
+}
+
+func (a *auditLog) Done() {
+	if err := a.f.Close(); err != nil {
+		printer.Warnf("Could not close the audit log: %s\n", err)
+	}
+}
+
+// Multi returns a Notifier that passes everything on to all of ns.
+func Multi(ns ...Notifier) Notifier {
+	return multiNotifier(ns)
+}
@@ -0,0 +1,60 @@
+package notifications
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestAuditLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.jsonl")
+	if err := ioutil.WriteFile(path, []byte(`{"run":"earlier"}`+"\n"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	defer func(c func() *Caller) { currentCaller = c }(currentCaller)
+	currentCaller = func() *Caller { return &Caller{User: "alice", Host: "ci-7", CIActor: "bob"} }
+
+	n, err := NewAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.Notify("example.com", "bind", "CREATE A www 1.2.3.4", nil, true)
+	n.Notify("example.com", "bind", "CREATE A www 1.2.3.4", nil, false)
+	n.Notify("example.com", "r53", "DELETE A old", errors.Errorf("throttled"), false)
+	n.Done()
+
+	fh, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fh.Close()
+	var entries []AuditEntry
+	s := bufio.NewScanner(fh)
+	for s.Scan() {
+		var e AuditEntry
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, e)
+	}
+	if len(entries) != 3 || entries[0].Run != "earlier" {
+		t.Fatalf("expected the earlier entry and 2 new ones, got %+v", entries)
+	}
+	ok, failed := entries[1], entries[2]
+	if ok.Domain != "example.com" || ok.Provider != "bind" || ok.Result != "ok" || ok.Error != "" || ok.Caller.User != "alice" || ok.Caller.CIActor != "bob" || ok.Time.IsZero() {
+		t.Errorf("unexpected entry %+v", ok)
+	}
+	if failed.Result != "error" || failed.Error != "throttled" || failed.Run != ok.Run || ok.Run == "" {
+		t.Errorf("unexpected entry %+v", failed)
+	}
+}
@@ -0,0 +1,116 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/StackExchange/dnscontrol/pkg/redact"
+	"github.com/pkg/errors"
+)
+
+// batch keeps the notifications of a run, for the notifiers that send a
+// summary when it is done instead of a message per correction.
+type batch struct {
+	mu      sync.Mutex
+	entries []*entry
+}
+
+// entry is one notification.
+type entry struct {
+	Domain   string
+	Provider string
+	Msg      string
+	Err      error
+	Preview  bool
+	RunError bool // Not a correction, but an error that kept corrections from running.
+}
+
+// domainEntries are the notifications of one domain.
+type domainEntries struct {
+	Name    string
+	Entries []*entry
+}
+
+func (b *batch) add(domain, provider, msg string, err error, preview bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries = append(b.entries, &entry{Domain: domain, Provider: provider, Msg: msg, Err: err, Preview: preview})
+}
+
+func (b *batch) addRunError(domain, provider, msg string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries = append(b.entries, &entry{Domain: domain, Provider: provider, Msg: msg, Err: err, RunError: true})
+}
+
+// domains returns the notifications grouped by domain, in the order the
+// domains were first seen.
+func (b *batch) domains() []*domainEntries {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var ds []*domainEntries
+	byName := map[string]*domainEntries{}
+	for _, e := range b.entries {
+		d := byName[e.Domain]
+		if d == nil {
+			d = &domainEntries{Name: e.Domain}
+			byName[e.Domain] = d
+			ds = append(ds, d)
+		}
+		d.Entries = append(d.Entries, e)
+	}
+	return ds
+}
+
+// counts returns the number of notifications, and of failed corrections.
+func (b *batch) counts() (total, failed int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, e := range b.entries {
+		if e.Err != nil && !e.RunError {
+			failed++
+		}
+	}
+	return len(b.entries), failed
+}
+
+// postJSON posts v, encoded as JSON with the secrets redacted, to url.
+func postJSON(url string, v interface{}) error {
+	body, err := encodeJSON(v)
+	if err != nil {
+		return err
+	}
+	return post(url, body, nil)
+}
+
+// encodeJSON encodes v as JSON, with the secrets redacted.
+func encodeJSON(v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(redact.String(string(b))), nil
+}
+
+// post posts the JSON body to url, with the extra header given.
+func post(url string, body []byte, header http.Header) error {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return errors.Errorf("%s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package notifications
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/StackExchange/dnscontrol/pkg/redact"
+)
+
+func init() {
+	initers = append(initers, func(cfg map[string]string) Notifier {
+		host, from, to := cfg["smtp_host"], cfg["smtp_from"], cfg["smtp_to"]
+		if host == "" || from == "" || to == "" {
+			return nil
+		}
+		e := &emailNotifier{host: host, from: from, username: cfg["smtp_username"], password: cfg["smtp_password"]}
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			e.host = net.JoinHostPort(host, "25")
+		}
+		for _, addr := range strings.Split(to, ",") {
+			e.to = append(e.to, strings.TrimSpace(addr))
+		}
+		return e
+	})
+}
+
+// emailNotifier mails a digest of the corrections of a preview or push.
+type emailNotifier struct {
+	host               string // host:port
+	from               string
+	to                 []string
+	username, password string
+	batch
+}
+
+// sendMail sends a mail. Tests replace it.
+var sendMail = smtp.SendMail
+
+func (e *emailNotifier) Notify(domain, provider, msg string, err error, preview bool) {
+	e.add(domain, provider, msg, err, preview)
+}
+
+func (e *emailNotifier) Done() {
+	if total, _ := e.counts(); total == 0 {
+		return
+	}
+	var auth smtp.Auth
+	if e.username != "" {
+		host, _, _ := net.SplitHostPort(e.host)
+		auth = smtp.PlainAuth("", e.username, e.password, host)
+	}
+	if err := sendMail(e.host, auth, e.from, e.to, []byte(e.message(time.Now()))); err != nil {
+		printer.Warnf("Could not send the notification mail: %s\n", err)
+	}
+}
+
+// message returns the mail, headers included. Only the body is redacted:
+// the addresses are values of creds.json too.
+func (e *emailNotifier) message(now time.Time) string {
+	total, failed := e.counts()
+	domains := e.domains()
+	what := "push"
+	if domains[0].Entries[0].Preview {
+		what = "preview"
+	}
+	subject := fmt.Sprintf("DNSControl %s: %d corrections", what, total)
+	if failed != 0 {
+		subject += fmt.Sprintf(", %d failed", failed)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", e.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(e.to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(redact.String(e.body(domains)))
+	return b.String()
+}
+
+// body returns the body of the mail.
+func (e *emailNotifier) body(domains []*domainEntries) string {
+	var b strings.Builder
+	for _, d := range domains {
+		fmt.Fprintf(&b, "%s\r\n%s\r\n", d.Name, strings.Repeat("=", len(d.Name)))
+		for _, c := range d.Entries {
+			status := "ran"
+			switch {
+			case c.Preview:
+				status = "to run"
+			case c.Err != nil:
+				status = "FAILED: " + c.Err.Error()
+			}
+			fmt.Fprintf(&b, "\r\n[%s] %s\r\n", c.Provider, status)
+			for _, line := range strings.Split(strings.TrimSpace(c.Msg), "\n") {
+				fmt.Fprintf(&b, "    %s\r\n", line)
+			}
+		}
+		b.WriteString("\r\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,41 @@
+package notifications
+
+import (
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/pkg/redact"
+)
+
+func TestEmail(t *testing.T) {
+	defer func(s func(string, smtp.Auth, string, []string, []byte) error) { sendMail = s }(sendMail)
+	var addr, from, msg string
+	var to []string
+	sendMail = func(a string, auth smtp.Auth, f string, t []string, m []byte) error {
+		addr, from, to, msg = a, f, t, string(m)
+		return nil
+	}
+
+	if n := Init(map[string]string{"smtp_host": "mail.example.com"}); len(n.(multiNotifier)) != 0 {
+		t.Errorf("expected no email notifier without smtp_from and smtp_to")
+	}
+	// The values of creds.json are redacted, but not from the headers.
+	redact.Add("dns@example.com")
+	n := Init(map[string]string{"smtp_host": "mail.example.com", "smtp_from": "dns@example.com", "smtp_to": "a@example.com, b@example.com"})
+	n.Notify("example.com", "bind", "GENERATE_ZONEFILE: example.com\nCREATE A www 1.2.3.4", nil, true)
+	n.Done()
+
+	if addr != "mail.example.com:25" || from != "dns@example.com" || len(to) != 2 || to[1] != "b@example.com" {
+		t.Errorf("unexpected envelope %s %s %v", addr, from, to)
+	}
+	for _, want := range []string{
+		"From: dns@example.com\r\n",
+		"Subject: DNSControl preview: 1 corrections\r\n",
+		"\r\n\r\nexample.com\r\n===========\r\n\r\n[bind] to run\r\n    GENERATE_ZONEFILE: example.com\r\n    CREATE A www 1.2.3.4\r\n",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in:\n%s", want, msg)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+)
+
+func init() {
+	initers = append(initers, func(cfg map[string]string) Notifier {
+		if key, ok := cfg["pagerduty_routing_key"]; ok {
+			return &incidentNotifier{name: "PagerDuty", open: func(s *failureSummary) error { return openPagerDuty(key, s) }}
+		}
+		return nil
+	}, func(cfg map[string]string) Notifier {
+		if key, ok := cfg["opsgenie_api_key"]; ok {
+			url := cfg["opsgenie_url"]
+			if url == "" {
+				url = "https://api.opsgenie.com/v2/alerts"
+			}
+			return &incidentNotifier{name: "Opsgenie", open: func(s *failureSummary) error { return openOpsgenie(url, key, s) }}
+		}
+		return nil
+	})
+}
+
+// incidentNotifier opens an incident when corrections fail during a push,
+// or errors keep them from running.
+type incidentNotifier struct {
+	name string
+	open func(*failureSummary) error
+	batch
+}
+
+func (n *incidentNotifier) Notify(domain, provider, msg string, err error, preview bool) {
+	if !preview {
+		n.add(domain, provider, msg, err, preview)
+	}
+}
+
+func (n *incidentNotifier) NotifyError(domain, provider, msg string, err error) {
+	n.addRunError(domain, provider, msg, err)
+}
+
+func (n *incidentNotifier) Done() {
+	s := n.summarize()
+	if s == nil {
+		return
+	}
+	if err := n.open(s); err != nil {
+		printer.Warnf("Could not open the %s incident: %s\n", n.name, err)
+	}
+}
+
+// failureSummary describes a push with failed corrections, or errors that
+// kept corrections from running.
+type failureSummary struct {
+	Title   string
+	Details string            // What ran and what failed, for each domain with failures.
+	Domains map[string]string // "not applied" or "partly applied", for each domain with failures.
+}
+
+// summarize returns the summary of the failures, or nil if there are none.
+func (n *incidentNotifier) summarize() *failureSummary {
+	s := &failureSummary{Domains: map[string]string{}}
+	var names []string
+	var b strings.Builder
+	failed, errs := 0, 0
+	for _, d := range n.domains() {
+		ran, bad, stopped := 0, 0, 0
+		for _, e := range d.Entries {
+			switch {
+			case e.RunError:
+				stopped++
+			case e.Err != nil:
+				bad++
+			default:
+				ran++
+			}
+		}
+		if bad == 0 && stopped == 0 {
+			continue
+		}
+		failed += bad
+		errs += stopped
+		names = append(names, d.Name)
+		state := "not applied"
+		if ran != 0 {
+			state = "partly applied"
+		}
+		s.Domains[d.Name] = state
+		fmt.Fprintf(&b, "%s: %s (%d corrections ran, %d failed)\n", d.Name, state, ran, bad)
+		for _, e := range d.Entries {
+			msg := strings.Replace(strings.TrimSpace(e.Msg), "\n", "\n      ", -1)
+			switch {
+			case e.RunError:
+				fmt.Fprintf(&b, "  [%s] ERROR: %s\n      %s\n", e.Provider, e.Err, msg)
+			case e.Err != nil:
+				fmt.Fprintf(&b, "  [%s] FAILED: %s\n      %s\n", e.Provider, e.Err, msg)
+			default:
+				fmt.Fprintf(&b, "  [%s] ran: %s\n", e.Provider, msg)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	var what []string
+	if failed != 0 {
+		what = append(what, fmt.Sprintf("%d corrections failed", failed))
+	}
+	if errs != 0 {
+		what = append(what, fmt.Sprintf("%d errors", errs))
+	}
+	s.Title = fmt.Sprintf("DNSControl push: %s for %s", strings.Join(what, " and "), strings.Join(names, ", "))
+	s.Details = b.String()
+	return s
+}
+
+// pagerdutyURL is the PagerDuty Events API v2 endpoint. Tests replace it.
+var pagerdutyURL = "https://events.pagerduty.com/v2/enqueue"
+
+func openPagerDuty(routingKey string, s *failureSummary) error {
+	host, _ := os.Hostname()
+	// Only the payload is redacted: the routing key is a value of creds.json too.
+	payload, err := encodeJSON(map[string]interface{}{
+		"summary":   truncate(s.Title, 1024),
+		"source":    host,
+		"severity":  "error",
+		"component": "dnscontrol",
+		"custom_details": map[string]interface{}{
+			"domains": s.Domains,
+			"details": s.Details,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"routing_key":  routingKey,
+		"event_action": "trigger",
+		"payload":      json.RawMessage(payload),
+	})
+	if err != nil {
+		return err
+	}
+	return post(pagerdutyURL, body, nil)
+}
+
+func openOpsgenie(url, apiKey string, s *failureSummary) error {
+	body, err := encodeJSON(map[string]interface{}{
+		"message":     truncate(s.Title, 130),
+		"description": truncate(s.Details, 15000),
+		"details":     s.Domains,
+		"source":      "dnscontrol",
+		"priority":    "P2",
+	})
+	if err != nil {
+		return err
+	}
+	return post(url, body, http.Header{"Authorization": {"GenieKey " + apiKey}})
+}
+
+// truncate shortens s to n bytes, for APIs that limit the length of fields.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-3] + "..."
+}
@@ -0,0 +1,101 @@
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/pkg/redact"
+	"github.com/pkg/errors"
+)
+
+func TestIncident(t *testing.T) {
+	var got map[string]interface{}
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		got = nil
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+	defer func(u string) { pagerdutyURL = u }(pagerdutyURL)
+	pagerdutyURL = srv.URL
+
+	// The values of creds.json are redacted, but the routing key must be sent.
+	redact.Add("R0UT1NGK3Y")
+	n := Init(map[string]string{"pagerduty_routing_key": "R0UT1NGK3Y"})
+	n.Notify("example.com", "bind", "CREATE A www 1.2.3.4", nil, false)
+	n.Done()
+	if got != nil {
+		t.Fatalf("expected no incident without failures, got %v", got)
+	}
+
+	n.Notify("example.com", "r53", "CREATE A www 1.2.3.4", errors.Errorf("throttled"), false)
+	n.Notify("example.net", "r53", "DELETE MX @", nil, false)
+	n.Done()
+	payload, _ := got["payload"].(map[string]interface{})
+	details, _ := payload["custom_details"].(map[string]interface{})
+	if got["event_action"] != "trigger" || got["routing_key"] != "R0UT1NGK3Y" || payload["summary"] != "DNSControl push: 1 corrections failed for example.com" {
+		t.Errorf("unexpected event %v", got)
+	}
+	if d, _ := details["domains"].(map[string]interface{}); d["example.com"] != "partly applied" || d["example.net"] != nil {
+		t.Errorf("unexpected domains %v", details["domains"])
+	}
+	if s, _ := details["details"].(string); !strings.Contains(s, "[r53] FAILED: throttled") {
+		t.Errorf("unexpected details %q", s)
+	}
+
+	n = Init(map[string]string{"opsgenie_api_key": "0PSG3N1EK3Y", "opsgenie_url": srv.URL})
+	n.Notify("example.com", "r53", "CREATE A www 1.2.3.4", errors.Errorf("throttled"), false)
+	n.Done()
+	if auth != "GenieKey 0PSG3N1EK3Y" || got["message"] != "DNSControl push: 1 corrections failed for example.com" {
+		t.Errorf("unexpected alert %q %v", auth, got)
+	}
+	if d, _ := got["details"].(map[string]interface{}); d["example.com"] != "not applied" {
+		t.Errorf("unexpected details %v", got["details"])
+	}
+}
+
+func TestIncidentRunErrors(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = nil
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+	defer func(u string) { pagerdutyURL = u }(pagerdutyURL)
+	pagerdutyURL = srv.URL
+
+	// Errors open an incident even if no correction failed, or ran.
+	n := Init(map[string]string{"pagerduty_routing_key": "R0UT1NGK3Y"})
+	n.Notify("example.com", "bind", "CREATE A www 1.2.3.4", nil, false)
+	n.(ErrorNotifier).NotifyError("example.com", "r53", "could not get the corrections", errors.Errorf("unauthorized"))
+	n.(ErrorNotifier).NotifyError("example.net", "", "push aborted", errors.Errorf("r53 would delete 9 records of example.net, more than the limit of 5"))
+	n.Done()
+	payload, _ := got["payload"].(map[string]interface{})
+	details, _ := payload["custom_details"].(map[string]interface{})
+	if payload["summary"] != "DNSControl push: 2 errors for example.com, example.net" {
+		t.Errorf("unexpected summary %v", payload["summary"])
+	}
+	if d, _ := details["domains"].(map[string]interface{}); d["example.com"] != "partly applied" || d["example.net"] != "not applied" {
+		t.Errorf("unexpected domains %v", details["domains"])
+	}
+	if s, _ := details["details"].(string); !strings.Contains(s, "[r53] ERROR: unauthorized\n      could not get the corrections") {
+		t.Errorf("unexpected details %q", s)
+	}
+
+	n = Init(map[string]string{"pagerduty_routing_key": "R0UT1NGK3Y"})
+	n.Notify("example.com", "r53", "CREATE A www 1.2.3.4", errors.Errorf("throttled"), false)
+	n.(ErrorNotifier).NotifyError("example.com", "", "push stopped", errors.Errorf("no nameservers"))
+	n.Done()
+	payload, _ = got["payload"].(map[string]interface{})
+	if payload["summary"] != "DNSControl push: 1 corrections failed and 1 errors for example.com" {
+		t.Errorf("unexpected summary %v", payload["summary"])
+	}
+}
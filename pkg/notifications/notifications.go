@@ -11,6 +11,13 @@ type Notifier interface {
 	Done()
 }
 
+// ErrorNotifier is implemented by the notifiers that also want to know
+// about the errors that keep corrections from running during a push, such
+// as a DNS provider that can't be read, or limits that abort the push.
+type ErrorNotifier interface {
+	NotifyError(domain, provider string, message string, err error)
+}
+
 // new notification types should add themselves to this array
 var initers = []func(map[string]string) Notifier{}
 
@@ -39,3 +46,11 @@ func (m multiNotifier) Done() {
 		n.Done()
 	}
 }
+
+func (m multiNotifier) NotifyError(domain, provider string, message string, err error) {
+	for _, n := range m {
+		if en, ok := n.(ErrorNotifier); ok {
+			en.NotifyError(domain, provider, message, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+)
+
+func init() {
+	initers = append(initers, func(cfg map[string]string) Notifier {
+		if url, ok := cfg["slack_url"]; ok {
+			return &slackNotifier{url: url}
+		}
+		return nil
+	})
+}
+
+// slackNotifier posts a summary of the corrections push ran to a Slack
+// incoming webhook. Previews are not posted.
+type slackNotifier struct {
+	url string
+	batch
+}
+
+func (s *slackNotifier) Notify(domain, provider, msg string, err error, preview bool) {
+	if !preview {
+		s.add(domain, provider, msg, err, preview)
+	}
+}
+
+func (s *slackNotifier) Done() {
+	if total, _ := s.counts(); total == 0 {
+		return
+	}
+	if err := postJSON(s.url, map[string]string{"text": s.summary()}); err != nil {
+		printer.Warnf("Could not post to Slack: %s\n", err)
+	}
+}
+
+// summary returns the message posted, in Slack's mrkdwn.
+func (s *slackNotifier) summary() string {
+	total, failed := s.counts()
+	var b strings.Builder
+	fmt.Fprintf(&b, "*DNSControl push:* %d corrections", total)
+	if failed != 0 {
+		fmt.Fprintf(&b, ", *%d failed*", failed)
+	}
+	b.WriteString("\n")
+	for _, d := range s.domains() {
+		fmt.Fprintf(&b, "\n*%s* (%d)\n", slackEscape(d.Name), len(d.Entries))
+		for _, e := range d.Entries {
+			status := "ran"
+			if e.Err != nil {
+				status = "FAILED: " + slackEscape(e.Err.Error())
+			}
+			fmt.Fprintf(&b, "%s %s\n```%s```\n", slackEscape(e.Provider), status, slackEscape(strings.TrimSpace(e.Msg)))
+		}
+	}
+	return b.String()
+}
+
+// slackEscape escapes the characters Slack's mrkdwn uses for links and mentions.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
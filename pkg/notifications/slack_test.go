@@ -0,0 +1,43 @@
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestSlack(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	n := Init(map[string]string{"slack_url": srv.URL})
+	n.Notify("example.com", "bind", "CREATE A www <1.2.3.4>", nil, true)
+	n.Done()
+	if got != nil {
+		t.Fatalf("previews should not be posted, got %v", got)
+	}
+
+	n = Init(map[string]string{"slack_url": srv.URL})
+	n.Notify("example.com", "bind", "CREATE A www 1.2.3.4", nil, false)
+	n.Notify("example.net", "r53", "DELETE MX @", errors.Errorf("throttled"), false)
+	n.Notify("example.com", "r53", "CREATE A www 1.2.3.4", nil, false)
+	n.Done()
+	want := "*DNSControl push:* 3 corrections, *1 failed*\n\n" +
+		"*example.com* (2)\nbind ran\n```CREATE A www 1.2.3.4```\nr53 ran\n```CREATE A www 1.2.3.4```\n\n" +
+		"*example.net* (1)\nr53 FAILED: throttled\n```DELETE MX @```\n"
+	if got["text"] != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got["text"], want)
+	}
+	if strings.Contains(slackEscape("<!channel>"), "<") {
+		t.Errorf("mentions should be escaped")
+	}
+}
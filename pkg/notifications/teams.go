@@ -0,0 +1,95 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+)
+
+func init() {
+	initers = append(initers, func(cfg map[string]string) Notifier {
+		if url, ok := cfg["teams_url"]; ok {
+			return &teamsNotifier{url: url}
+		}
+		return nil
+	})
+}
+
+// teamsNotifier posts the corrections of a preview or push to a Microsoft
+// Teams incoming webhook, as an Adaptive Card with a section per domain.
+type teamsNotifier struct {
+	url string
+	batch
+}
+
+func (t *teamsNotifier) Notify(domain, provider, msg string, err error, preview bool) {
+	t.add(domain, provider, msg, err, preview)
+}
+
+func (t *teamsNotifier) Done() {
+	if total, _ := t.counts(); total == 0 {
+		return
+	}
+	if err := postJSON(t.url, t.message()); err != nil {
+		printer.Warnf("Could not post to Teams: %s\n", err)
+	}
+}
+
+// card is an element of an Adaptive Card.
+type card map[string]interface{}
+
+func textBlock(text string, more card) card {
+	c := card{"type": "TextBlock", "text": text, "wrap": true}
+	for k, v := range more {
+		c[k] = v
+	}
+	return c
+}
+
+// message returns the webhook message, with the card.
+func (t *teamsNotifier) message() card {
+	total, failed := t.counts()
+	domains := t.domains()
+	title := "DNSControl push"
+	if domains[0].Entries[0].Preview {
+		title = "DNSControl preview"
+	}
+	summary := fmt.Sprintf("%d corrections", total)
+	if failed != 0 {
+		summary += fmt.Sprintf(", %d failed", failed)
+	}
+	body := []card{
+		textBlock(title, card{"size": "Medium", "weight": "Bolder"}),
+		textBlock(summary, card{"isSubtle": true, "spacing": "None"}),
+	}
+	for _, d := range domains {
+		items := []card{textBlock(d.Name, card{"weight": "Bolder"})}
+		for _, e := range d.Entries {
+			status := "ran"
+			color := "Good"
+			switch {
+			case e.Preview:
+				status, color = "to run", "Default"
+			case e.Err != nil:
+				status, color = "FAILED: "+e.Err.Error(), "Attention"
+			}
+			items = append(items,
+				textBlock(e.Provider+" "+status, card{"color": color, "spacing": "Small"}),
+				textBlock(strings.TrimSpace(e.Msg), card{"fontType": "Monospace", "spacing": "None"}))
+		}
+		body = append(body, card{"type": "Container", "separator": true, "spacing": "Medium", "items": items})
+	}
+	return card{
+		"type": "message",
+		"attachments": []card{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": card{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.2",
+				"body":    body,
+			},
+		}},
+	}
+}
@@ -0,0 +1,50 @@
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestTeams(t *testing.T) {
+	var got struct {
+		Attachments []struct {
+			ContentType string
+			Content     struct {
+				Type string
+				Body []struct {
+					Type  string
+					Text  string
+					Items []struct{ Text, Color string }
+				}
+			}
+		}
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	n := Init(map[string]string{"teams_url": srv.URL})
+	n.Notify("example.com", "bind", "CREATE A www 1.2.3.4", nil, false)
+	n.Notify("example.net", "r53", "DELETE MX @", errors.Errorf("throttled"), false)
+	n.Done()
+
+	if len(got.Attachments) != 1 || got.Attachments[0].ContentType != "application/vnd.microsoft.card.adaptive" {
+		t.Fatalf("unexpected message %+v", got)
+	}
+	c := got.Attachments[0].Content
+	if c.Type != "AdaptiveCard" || len(c.Body) != 4 || c.Body[0].Text != "DNSControl push" || c.Body[1].Text != "2 corrections, 1 failed" {
+		t.Fatalf("unexpected card %+v", c)
+	}
+	net := c.Body[3]
+	if net.Type != "Container" || len(net.Items) != 3 || net.Items[0].Text != "example.net" ||
+		net.Items[1].Text != "r53 FAILED: throttled" || net.Items[1].Color != "Attention" || net.Items[2].Text != "DELETE MX @" {
+		t.Errorf("unexpected section %+v", net)
+	}
+}
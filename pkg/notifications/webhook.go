@@ -0,0 +1,147 @@
+package notifications
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+)
+
+func init() {
+	initers = append(initers, func(cfg map[string]string) Notifier {
+		if url, ok := cfg["webhook_url"]; ok {
+			return &webhookNotifier{url: url, secret: cfg["webhook_secret"], started: time.Now()}
+		}
+		return nil
+	})
+}
+
+// webhookNotifier posts all the corrections of a preview or push to a URL
+// as one JSON document (see webhookPayload), signed with HMAC-SHA256 if
+// it has a secret.
+type webhookNotifier struct {
+	url     string
+	secret  string
+	started time.Time
+	batch
+}
+
+// webhookSignatureHeader holds "sha256=" and the hex HMAC-SHA256 of the
+// body, keyed with webhook_secret.
+const webhookSignatureHeader = "X-Dnscontrol-Signature"
+
+// webhookPayload is the JSON document posted.
+type webhookPayload struct {
+	Version     int              `json:"version"`
+	Push        bool             `json:"push"`
+	Started     time.Time        `json:"started"`
+	Duration    float64          `json:"duration_seconds"`
+	Git         *gitInfo         `json:"git,omitempty"`
+	Corrections int              `json:"corrections"`
+	Failed      int              `json:"failed"`
+	Domains     []*webhookDomain `json:"domains"`
+}
+
+type webhookDomain struct {
+	Name        string               `json:"name"`
+	Corrections []*webhookCorrection `json:"corrections"`
+}
+
+type webhookCorrection struct {
+	Provider string `json:"provider"`
+	Msg      string `json:"msg"`
+	Ran      bool   `json:"ran"`
+	Error    string `json:"error,omitempty"`
+}
+
+// gitInfo describes the git checkout dnscontrol runs in.
+type gitInfo struct {
+	Commit string `json:"commit"`
+	Branch string `json:"branch,omitempty"`
+	Dirty  bool   `json:"dirty"`
+}
+
+// git runs git, and returns its output. Tests replace it.
+var git = func(args ...string) (string, error) {
+	out, err := exec.Command("git", args...).Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// currentGit returns the git metadata of the current directory, or nil
+// if it isn't a git checkout.
+func currentGit() *gitInfo {
+	commit, err := git("rev-parse", "HEAD")
+	if err != nil || commit == "" {
+		return nil
+	}
+	g := &gitInfo{Commit: commit}
+	if branch, err := git("rev-parse", "--abbrev-ref", "HEAD"); err == nil && branch != "HEAD" {
+		g.Branch = branch
+	}
+	if status, err := git("status", "--porcelain"); err == nil {
+		g.Dirty = status != ""
+	}
+	return g
+}
+
+func (w *webhookNotifier) Notify(domain, provider, msg string, err error, preview bool) {
+	w.add(domain, provider, msg, err, preview)
+}
+
+func (w *webhookNotifier) Done() {
+	if total, _ := w.counts(); total == 0 {
+		return
+	}
+	if err := w.send(w.payload()); err != nil {
+		printer.Warnf("Could not post to the webhook: %s\n", err)
+	}
+}
+
+func (w *webhookNotifier) payload() *webhookPayload {
+	total, failed := w.counts()
+	p := &webhookPayload{
+		Version:     1,
+		Started:     w.started.UTC(),
+		Duration:    time.Since(w.started).Seconds(),
+		Git:         currentGit(),
+		Corrections: total,
+		Failed:      failed,
+	}
+	for _, d := range w.domains() {
+		wd := &webhookDomain{Name: d.Name}
+		for _, e := range d.Entries {
+			p.Push = !e.Preview
+			c := &webhookCorrection{Provider: e.Provider, Msg: e.Msg, Ran: !e.Preview}
+			if e.Err != nil {
+				c.Error = e.Err.Error()
+			}
+			wd.Corrections = append(wd.Corrections, c)
+		}
+		p.Domains = append(p.Domains, wd)
+	}
+	return p
+}
+
+func (w *webhookNotifier) send(p *webhookPayload) error {
+	body, err := encodeJSON(p)
+	if err != nil {
+		return err
+	}
+	header := http.Header{}
+	if w.secret != "" {
+		header.Set(webhookSignatureHeader, "sha256="+sign(w.secret, body))
+	}
+	return post(w.url, body, header)
+}
+
+// sign returns the hex HMAC-SHA256 of body.
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
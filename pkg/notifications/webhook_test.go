@@ -0,0 +1,50 @@
+package notifications
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestWebhook(t *testing.T) {
+	defer func(g func(...string) (string, error)) { git = g }(git)
+	git = func(args ...string) (string, error) {
+		switch args[0] + " " + args[1] {
+		case "rev-parse HEAD":
+			return "0123abcd", nil
+		case "rev-parse --abbrev-ref":
+			return "main", nil
+		}
+		return " M dnsconfig.js", nil
+	}
+	var body []byte
+	var signature string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+		signature = r.Header.Get(webhookSignatureHeader)
+	}))
+	defer srv.Close()
+
+	n := Init(map[string]string{"webhook_url": srv.URL, "webhook_secret": "s3cret-key"})
+	n.Notify("example.com", "bind", "CREATE A www 1.2.3.4", nil, false)
+	n.Notify("example.com", "r53", "CREATE A www 1.2.3.4", errors.Errorf("throttled"), false)
+	n.Done()
+
+	if signature != "sha256="+sign("s3cret-key", body) {
+		t.Errorf("bad signature %q", signature)
+	}
+	var p webhookPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		t.Fatal(err)
+	}
+	if !p.Push || p.Corrections != 2 || p.Failed != 1 || p.Git == nil || p.Git.Commit != "0123abcd" || p.Git.Branch != "main" || !p.Git.Dirty {
+		t.Errorf("unexpected payload %s", body)
+	}
+	if len(p.Domains) != 1 || len(p.Domains[0].Corrections) != 2 || p.Domains[0].Corrections[1].Error != "throttled" || !p.Domains[0].Corrections[0].Ran {
+		t.Errorf("unexpected domains %s", body)
+	}
+}
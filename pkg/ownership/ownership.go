@@ -0,0 +1,139 @@
+// Package ownership lets several teams or tools share a zone, like the TXT
+// registry of external-dns.
+//
+// A domain with OWNER(name) in dnsconfig.js gets a TXT marker for each of
+// its record sets, which says who owns the set. The marker of the A
+// records of www is the TXT record _dnscontrol-a.www, which contains
+// "heritage=dnscontrol,dnscontrol/owner=<name>". Markers are separate
+// names so that they never clash with CNAMEs.
+//
+// The differ then only deletes or modifies record sets whose marker names
+// the domain's owner. Record sets without a marker are adopted when
+// dnsconfig.js lists them exactly as they are, and left alone otherwise.
+package ownership
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/pkg/errors"
+)
+
+// prefix starts the first label of markers.
+const prefix = "_dnscontrol-"
+
+// heritage starts the contents of markers.
+const heritage = "heritage=dnscontrol,dnscontrol/owner="
+
+// wildcard replaces "*" in the names of markers, as a "*" that is not the
+// first label is not a wildcard, and few providers accept it.
+const wildcard = "_wildcard"
+
+var ownerRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// CheckOwner returns an error if owner can't be used as the name of an owner.
+func CheckOwner(owner string) error {
+	if !ownerRe.MatchString(owner) {
+		return errors.Errorf("owner %q must be letters, digits, '.', '_' and '-'", owner)
+	}
+	return nil
+}
+
+// IsMarker reports whether the short name label is the name of a marker.
+func IsMarker(label string) bool {
+	return strings.HasPrefix(label, prefix)
+}
+
+// MarkerLabel returns the short name of the marker of the record set of
+// label and rtype (as in models.RecordKey).
+func MarkerLabel(label, rtype string) string {
+	first := prefix + strings.ToLower(rtype)
+	if label == "@" {
+		return first
+	}
+	labels := strings.Split(label, ".")
+	if labels[0] == "*" {
+		labels[0] = wildcard
+	}
+	return first + "." + strings.Join(labels, ".")
+}
+
+// MarkerKey returns the key of the marker of the record set k.
+func MarkerKey(k models.RecordKey) models.RecordKey {
+	name := prefix + strings.ToLower(k.Type) + "." + k.NameFQDN
+	if strings.HasPrefix(k.NameFQDN, "*.") {
+		name = prefix + strings.ToLower(k.Type) + "." + wildcard + k.NameFQDN[1:]
+	}
+	return models.RecordKey{NameFQDN: name, Type: "TXT"}
+}
+
+// Owned returns the key of the record set the marker m is about. ok is
+// false if m is not the key of a marker.
+func Owned(m models.RecordKey) (k models.RecordKey, ok bool) {
+	if m.Type != "TXT" || !strings.HasPrefix(m.NameFQDN, prefix) {
+		return k, false
+	}
+	parts := strings.SplitN(m.NameFQDN, ".", 2)
+	if len(parts) != 2 || len(parts[0]) == len(prefix) {
+		return k, false
+	}
+	name := parts[1]
+	if strings.HasPrefix(name, wildcard+".") {
+		name = "*" + name[len(wildcard):]
+	}
+	return models.RecordKey{NameFQDN: name, Type: strings.ToUpper(parts[0][len(prefix):])}, true
+}
+
+// Value returns the contents of the markers of owner.
+func Value(owner string) string {
+	return heritage + owner
+}
+
+// ownerOf returns the owner a marker record names, or "" if it doesn't.
+func ownerOf(r *models.RecordConfig) string {
+	txt := strings.Join(r.TxtStrings, "")
+	if len(r.TxtStrings) == 0 {
+		txt = r.GetTargetField()
+	}
+	if !strings.HasPrefix(txt, heritage) {
+		return ""
+	}
+	return txt[len(heritage):]
+}
+
+// Owners returns the owner of each record set the markers among records
+// are about.
+func Owners(records []*models.RecordConfig) map[models.RecordKey]string {
+	owners := map[models.RecordKey]string{}
+	for _, r := range records {
+		if k, ok := Owned(r.Key()); ok {
+			if owner := ownerOf(r); owner != "" {
+				owners[k] = owner
+			}
+		}
+	}
+	return owners
+}
+
+// AddMarkers adds to dc a marker for each of its record sets, except
+// markers and the SOA, which belongs to the zone rather than to an owner.
+func AddMarkers(dc *models.DomainConfig) {
+	if dc.Owner == "" {
+		return
+	}
+	sets := map[models.RecordKey]bool{}
+	var markers models.Records
+	for _, r := range dc.Records {
+		k := r.Key()
+		if IsMarker(r.GetLabel()) || r.Type == "SOA" || sets[k] {
+			continue
+		}
+		sets[k] = true
+		m := &models.RecordConfig{Type: "TXT", TTL: r.TTL, Metadata: map[string]string{}}
+		m.SetLabel(MarkerLabel(r.GetLabel(), k.Type), dc.Name)
+		m.SetTargetTXT(Value(dc.Owner))
+		markers = append(markers, m)
+	}
+	dc.Records = append(dc.Records, markers...)
+}
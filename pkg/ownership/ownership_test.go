@@ -0,0 +1,55 @@
+package ownership
+
+import (
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/models"
+)
+
+func TestMarkers(t *testing.T) {
+	dc := &models.DomainConfig{Name: "example.com", Owner: "team"}
+	for _, s := range []struct{ label, rtype, target string }{
+		{"@", "A", "1.2.3.4"},
+		{"@", "A", "1.2.3.5"},
+		{"www", "CNAME", "example.com."},
+		{"*.dev", "A", "1.2.3.6"},
+	} {
+		r := &models.RecordConfig{Type: s.rtype, TTL: 300}
+		r.SetLabel(s.label, dc.Name)
+		r.SetTarget(s.target)
+		dc.Records = append(dc.Records, r)
+	}
+	AddMarkers(dc)
+	if len(dc.Records) != 7 {
+		t.Fatalf("got %d records, want 7", len(dc.Records))
+	}
+	owners := Owners(dc.Records)
+	for _, r := range dc.Records[:4] {
+		if owners[r.Key()] != "team" {
+			t.Errorf("%s %s: got owner %q, want \"team\"", r.Type, r.GetLabelFQDN(), owners[r.Key()])
+		}
+	}
+	for i, want := range []string{"_dnscontrol-a", "_dnscontrol-cname.www", "_dnscontrol-a._wildcard.dev"} {
+		m := dc.Records[4+i]
+		if m.GetLabel() != want {
+			t.Errorf("marker %d: got %q, want %q", i, m.GetLabel(), want)
+		}
+		if m.Key() != MarkerKey(dc.Records[[]int{0, 2, 3}[i]].Key()) {
+			t.Errorf("marker %d: MarkerKey does not match %s", i, m.GetLabelFQDN())
+		}
+	}
+}
+
+func TestOwned(t *testing.T) {
+	for _, name := range []string{"_dnscontrol-.example.com", "_dnscontrol-a", "www.example.com"} {
+		if k, ok := Owned(models.RecordKey{NameFQDN: name, Type: "TXT"}); ok {
+			t.Errorf("%s: got %v, want not a marker", name, k)
+		}
+	}
+	if err := CheckOwner("team-a.prod"); err != nil {
+		t.Error(err)
+	}
+	if err := CheckOwner("team,a"); err == nil {
+		t.Error("expected an error for an owner with a comma")
+	}
+}
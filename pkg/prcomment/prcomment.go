@@ -0,0 +1,223 @@
+// Package prcomment posts the results of a preview as a comment on the
+// GitHub pull request or GitLab merge request being built, so reviewers
+// see which DNS changes it causes.
+//
+// The comment is updated, rather than added again, each time the pipeline
+// runs: it is recognized by the Marker it starts with.
+package prcomment
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/pkg/redact"
+	"github.com/StackExchange/dnscontrol/pkg/report"
+	"github.com/pkg/errors"
+)
+
+// Marker identifies comments made by dnscontrol.
+const Marker = "<!-- dnscontrol-preview -->"
+
+const commentTemplate = Marker + `
+### DNSControl {{if .Push}}push{{else}}preview{{end}}: {{.Corrections}} correction{{if ne .Corrections 1}}s{{end}}
+{{range .Domains}}
+<details{{if .Corrections}} open{{end}}><summary><b>{{.Name}}</b>: {{.Corrections}} correction{{if ne .Corrections 1}}s{{end}}</summary>
+{{range .Warnings}}
+> :warning: {{.}}
+{{end}}{{range .Providers}}{{if .Error}}
+**{{.Name}}**: :x: {{.Error}}
+{{else if .Corrections}}
+**{{.Name}}**{{if .Registrar}} (registrar){{end}}
+
+` + "```" + `
+{{range $i, $c := .Corrections}}{{range lines $c.Msg}}{{.}}
+{{end}}{{end}}` + "```" + `
+{{end}}{{end}}
+</details>
+{{end}}`
+
+// Render returns the comment for run.
+func Render(run *report.Run) (string, error) {
+	t, err := report.ParseTemplate("comment", commentTemplate)
+	if err != nil {
+		return "", err
+	}
+	buf := &bytes.Buffer{}
+	if err := t.Execute(buf, run); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Poster creates or updates the comment on a pull or merge request.
+type Poster interface {
+	Post(body string) error
+}
+
+// FromEnv returns the Poster for the CI system dnscontrol runs in, using
+// the environment variables set by GitHub Actions or GitLab CI.
+//
+// GitHub needs GITHUB_TOKEN and GITHUB_REPOSITORY. The pull request is
+// taken from GITHUB_REF (refs/pull/N/merge) or, failing that, from the
+// event in GITHUB_EVENT_PATH.
+//
+// GitLab needs GITLAB_TOKEN (an access token with the api scope),
+// CI_PROJECT_ID and CI_MERGE_REQUEST_IID.
+func FromEnv() (Poster, error) {
+	redact.Add(os.Getenv("GITHUB_TOKEN"), os.Getenv("GITLAB_TOKEN"))
+	if os.Getenv("GITHUB_TOKEN") != "" && os.Getenv("GITHUB_REPOSITORY") != "" {
+		n, err := githubPullRequest()
+		if err != nil {
+			return nil, err
+		}
+		api := os.Getenv("GITHUB_API_URL")
+		if api == "" {
+			api = "https://api.github.com"
+		}
+		return &github{
+			api:   strings.TrimRight(api, "/"),
+			token: os.Getenv("GITHUB_TOKEN"),
+			repo:  os.Getenv("GITHUB_REPOSITORY"),
+			pr:    n,
+		}, nil
+	}
+	if os.Getenv("GITLAB_TOKEN") != "" && os.Getenv("CI_PROJECT_ID") != "" {
+		iid := os.Getenv("CI_MERGE_REQUEST_IID")
+		if iid == "" {
+			return nil, errors.Errorf("CI_MERGE_REQUEST_IID is not set: this is not a merge request pipeline")
+		}
+		api := os.Getenv("CI_API_V4_URL")
+		if api == "" {
+			api = "https://gitlab.com/api/v4"
+		}
+		return &gitlab{
+			api:     strings.TrimRight(api, "/"),
+			token:   os.Getenv("GITLAB_TOKEN"),
+			project: os.Getenv("CI_PROJECT_ID"),
+			mr:      iid,
+		}, nil
+	}
+	return nil, errors.Errorf("neither GITHUB_TOKEN and GITHUB_REPOSITORY nor GITLAB_TOKEN and CI_PROJECT_ID are set")
+}
+
+func githubPullRequest() (int, error) {
+	ref := os.Getenv("GITHUB_REF")
+	if strings.HasPrefix(ref, "refs/pull/") {
+		if n, err := strconv.Atoi(strings.Split(ref, "/")[2]); err == nil {
+			return n, nil
+		}
+	}
+	if path := os.Getenv("GITHUB_EVENT_PATH"); path != "" {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return 0, err
+		}
+		var event struct {
+			PullRequest struct {
+				Number int `json:"number"`
+			} `json:"pull_request"`
+		}
+		if err := json.Unmarshal(b, &event); err != nil {
+			return 0, errors.Wrap(err, "GITHUB_EVENT_PATH")
+		}
+		if event.PullRequest.Number != 0 {
+			return event.PullRequest.Number, nil
+		}
+	}
+	return 0, errors.Errorf("no pull request in GITHUB_REF (%s) or GITHUB_EVENT_PATH", ref)
+}
+
+// do sends a JSON request and decodes the JSON response into result (unless it is nil).
+func do(method, url string, header map[string]string, body, result interface{}) error {
+	var rd io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		rd = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, url, rd)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("%s %s: %s: %s", method, url, resp.Status, b)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(b, result)
+}
+
+type github struct {
+	api, token, repo string
+	pr               int
+}
+
+type githubComment struct {
+	ID   int64  `json:"id,omitempty"`
+	Body string `json:"body"`
+}
+
+func (g *github) Post(body string) error {
+	header := map[string]string{
+		"Authorization": "token " + g.token,
+		"Accept":        "application/vnd.github.v3+json",
+	}
+	url := fmt.Sprintf("%s/repos/%s/issues/%d/comments", g.api, g.repo, g.pr)
+	var comments []githubComment
+	if err := do("GET", url+"?per_page=100", header, nil, &comments); err != nil {
+		return err
+	}
+	for _, c := range comments {
+		if strings.HasPrefix(c.Body, Marker) {
+			return do("PATCH", fmt.Sprintf("%s/repos/%s/issues/comments/%d", g.api, g.repo, c.ID), header, githubComment{Body: body}, nil)
+		}
+	}
+	return do("POST", url, header, githubComment{Body: body}, nil)
+}
+
+type gitlab struct {
+	api, token, project, mr string
+}
+
+type gitlabNote struct {
+	ID   int64  `json:"id,omitempty"`
+	Body string `json:"body"`
+}
+
+func (g *gitlab) Post(body string) error {
+	header := map[string]string{"PRIVATE-TOKEN": g.token}
+	url := fmt.Sprintf("%s/projects/%s/merge_requests/%s/notes", g.api, g.project, g.mr)
+	var notes []gitlabNote
+	if err := do("GET", url+"?per_page=100", header, nil, &notes); err != nil {
+		return err
+	}
+	for _, n := range notes {
+		if strings.HasPrefix(n.Body, Marker) {
+			return do("PUT", fmt.Sprintf("%s/%d", url, n.ID), header, gitlabNote{Body: body}, nil)
+		}
+	}
+	return do("POST", url, header, gitlabNote{Body: body}, nil)
+}
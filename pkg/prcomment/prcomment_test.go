@@ -0,0 +1,88 @@
+package prcomment
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/pkg/report"
+)
+
+func TestRender(t *testing.T) {
+	run := &report.Run{Domains: []*report.Domain{
+		{Name: "example.com", Providers: []*report.Provider{
+			{Name: "bind", Corrections: []*report.Correction{{Msg: "CREATE A www 1.2.3.4\n"}}},
+			{Name: "none", Registrar: true},
+		}},
+		{Name: "example.net", Warnings: []string{"example.net is frozen"}, Providers: []*report.Provider{
+			{Name: "bind", Error: "boom"},
+		}},
+	}}
+	body, err := Render(run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		Marker,
+		"preview: 1 correction\n",
+		"<details open><summary><b>example.com</b>: 1 correction</summary>",
+		"```\nCREATE A www 1.2.3.4\n```",
+		"<details><summary><b>example.net</b>: 0 corrections</summary>",
+		":warning: example.net is frozen",
+		"**bind**: :x: boom",
+	} {
+		if !strings.Contains(body, s) {
+			t.Errorf("%q missing from:\n%s", s, body)
+		}
+	}
+}
+
+func TestGithubUpdatesComment(t *testing.T) {
+	var method, path, posted string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method == "GET" {
+			json.NewEncoder(w).Encode([]githubComment{{ID: 1, Body: "LGTM"}, {ID: 2, Body: Marker + "\nold"}})
+			return
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		var c githubComment
+		json.Unmarshal(b, &c)
+		method, path, posted = r.Method, r.URL.Path, c.Body
+	}))
+	defer srv.Close()
+
+	g := &github{api: srv.URL, token: "secret", repo: "acme/dns", pr: 7}
+	if err := g.Post(Marker + "\nnew"); err != nil {
+		t.Fatal(err)
+	}
+	if method != "PATCH" || path != "/repos/acme/dns/issues/comments/2" || posted != Marker+"\nnew" {
+		t.Errorf("unexpected request %s %s %q", method, path, posted)
+	}
+}
+
+func TestGitlabCreatesNote(t *testing.T) {
+	var method, path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(`[{"id": 1, "body": "LGTM"}]`))
+			return
+		}
+		method, path = r.Method, r.URL.Path
+	}))
+	defer srv.Close()
+
+	g := &gitlab{api: srv.URL, token: "secret", project: "42", mr: "3"}
+	if err := g.Post(Marker + "\nnew"); err != nil {
+		t.Fatal(err)
+	}
+	if method != "POST" || path != "/projects/42/merge_requests/3/notes" {
+		t.Errorf("unexpected request %s %s", method, path)
+	}
+}
@@ -0,0 +1,175 @@
+package printer
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Change is a change to a record, as shown in the table of the changes
+// of a provider.
+type Change struct {
+	Action string // "create", "delete" or "modify".
+	Name   string
+	Type   string
+	Before string // The value before the change, or "" for a create.
+	After  string // The value after the change, or "" for a delete.
+}
+
+// ChangePrinter is implemented by the CLIs that can show the changes of a
+// provider as a table, besides its corrections.
+type ChangePrinter interface {
+	PrintChanges(changes []Change)
+}
+
+// ANSI colors.
+const (
+	colorReset  = "\x1b[0m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorBold   = "\x1b[1m"
+)
+
+var actionColors = map[string]string{"create": colorGreen, "delete": colorRed, "modify": colorYellow}
+var actionSymbols = map[string]string{"create": "+", "delete": "-", "modify": "~"}
+
+// PrintChanges prints changes grouped by record set, one per line, with
+// the values before and after aligned. Values are shortened to fit in
+// c.Width, if set.
+func (c ConsolePrinter) PrintChanges(changes []Change) {
+	if len(changes) == 0 {
+		return
+	}
+	changes = append([]Change(nil), changes...)
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Name != changes[j].Name {
+			return changes[i].Name < changes[j].Name
+		}
+		return changes[i].Type < changes[j].Type
+	})
+	nameW, typeW, beforeW := width("NAME"), width("TYPE"), width("BEFORE")
+	for _, ch := range changes {
+		nameW = max(nameW, width(ch.Name))
+		typeW = max(typeW, width(ch.Type))
+		beforeW = max(beforeW, width(ch.Before))
+	}
+	valueW := 0 // No limit.
+	if c.Width > 0 {
+		// "~ " name "  " type "  " before " → " after
+		valueW = max((c.Width-2-nameW-2-typeW-2-3)/2, 10)
+		beforeW = min(beforeW, valueW)
+	}
+	header := fmt.Sprintf("  %s  %s  %s   %s", pad("NAME", nameW), pad("TYPE", typeW), pad("BEFORE", beforeW), "AFTER")
+	fmt.Fprintln(c.Writer, c.colored(colorBold, header))
+	for i, ch := range changes {
+		name, typ := ch.Name, ch.Type
+		if i > 0 && changes[i-1].Name == name && changes[i-1].Type == typ {
+			name, typ = "", "" // The same record set as the line above.
+		}
+		arrow := "→"
+		if ch.Action == "delete" {
+			arrow = " "
+		}
+		line := fmt.Sprintf("%s %s  %s  %s %s %s",
+			actionSymbols[ch.Action], pad(name, nameW), pad(typ, typeW),
+			pad(shorten(ch.Before, valueW), beforeW), arrow, shorten(ch.After, valueW))
+		fmt.Fprintln(c.Writer, c.colored(actionColors[ch.Action], strings.TrimRight(line, " ")))
+	}
+}
+
+// colored returns s in color, if c prints in color.
+func (c ConsolePrinter) colored(color, s string) string {
+	if !c.Color || color == "" {
+		return s
+	}
+	return color + s + colorReset
+}
+
+// correctionColor returns the color of a line of a correction, from the
+// word it starts with.
+func correctionColor(line string) string {
+	word := strings.ToUpper(strings.SplitN(strings.TrimSpace(line), " ", 2)[0])
+	switch {
+	case strings.HasPrefix(word, "CREATE"), strings.HasPrefix(word, "ADD"):
+		return colorGreen
+	case strings.HasPrefix(word, "DELETE"), strings.HasPrefix(word, "REMOVE"):
+		return colorRed
+	case strings.HasPrefix(word, "MODIFY"), strings.HasPrefix(word, "CHANGE"), strings.HasPrefix(word, "UPDATE"):
+		return colorYellow
+	}
+	return ""
+}
+
+// colorCorrection colors each line of msg by its action.
+func (c ConsolePrinter) colorCorrection(msg string) string {
+	if !c.Color {
+		return msg
+	}
+	lines := strings.Split(msg, "\n")
+	for i, l := range lines {
+		lines[i] = c.colored(correctionColor(l), l)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func width(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+func pad(s string, w int) string {
+	if n := width(s); n < w {
+		return s + strings.Repeat(" ", w-n)
+	}
+	return s
+}
+
+// shorten cuts s to w characters, if w is not 0.
+func shorten(s string, w int) string {
+	if w == 0 || width(s) <= w {
+		return s
+	}
+	return string([]rune(s)[:w-1]) + "…"
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// IsTerminal reports whether f is a terminal.
+func IsTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// UseColor reports whether output to f should be in color: if f is a
+// terminal, and color is not turned off with NO_COLOR or TERM=dumb.
+func UseColor(f *os.File) bool {
+	_, noColor := os.LookupEnv("NO_COLOR")
+	return IsTerminal(f) && !noColor && os.Getenv("TERM") != "dumb"
+}
+
+// TerminalWidth returns the width of f, from $COLUMNS or the terminal, or
+// 0 if f is not a terminal.
+func TerminalWidth(f *os.File) int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	if !IsTerminal(f) {
+		return 0
+	}
+	return terminalWidth(f)
+}
@@ -0,0 +1,47 @@
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var testChanges = []Change{
+	{Action: "create", Name: "www.example.com", Type: "A", After: "192.0.2.2 ttl=300"},
+	{Action: "delete", Name: "old.example.com", Type: "CNAME", Before: "www.example.com. ttl=300"},
+	{Action: "modify", Name: "www.example.com", Type: "A", Before: "192.0.2.1 ttl=300", After: "192.0.2.3 ttl=300"},
+}
+
+func TestPrintChanges(t *testing.T) {
+	output := &bytes.Buffer{}
+	ConsolePrinter{Writer: output}.PrintChanges(testChanges)
+	assert.Equal(t, ""+
+		"  NAME             TYPE   BEFORE                     AFTER\n"+
+		"- old.example.com  CNAME  www.example.com. ttl=300\n"+
+		"+ www.example.com  A                               → 192.0.2.2 ttl=300\n"+
+		"~                         192.0.2.1 ttl=300        → 192.0.2.3 ttl=300\n",
+		output.String())
+}
+
+func TestPrintChangesWidth(t *testing.T) {
+	output := &bytes.Buffer{}
+	ConsolePrinter{Writer: output, Width: 50}.PrintChanges(testChanges[1:2])
+	assert.Equal(t, ""+
+		"  NAME             TYPE   BEFORE       AFTER\n"+
+		"- old.example.com  CNAME  www.examp…\n",
+		output.String())
+}
+
+func TestColor(t *testing.T) {
+	output := &bytes.Buffer{}
+	p := ConsolePrinter{Writer: output, Color: true}
+	p.PrintChanges(testChanges[:1])
+	p.PrintCorrection(0, &models.Correction{Msg: "GENERATE_ZONEFILE: example.com\nDELETE A old\nMODIFY A www"})
+	assert.Equal(t, ""+
+		"\x1b[1m  NAME             TYPE  BEFORE   AFTER\x1b[0m\n"+
+		"\x1b[32m+ www.example.com  A            → 192.0.2.2 ttl=300\x1b[0m\n"+
+		"#1: GENERATE_ZONEFILE: example.com\n\x1b[31mDELETE A old\x1b[0m\n\x1b[33mMODIFY A www\x1b[0m\n",
+		output.String())
+}
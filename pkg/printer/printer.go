@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/redact"
 )
 
 // CLI is an abstraction around the CLI.
@@ -49,7 +50,7 @@ var (
 	// DefaultPrinter is the default Printer, used by Debugf, Printf, and Warnf.
 	DefaultPrinter = &ConsolePrinter{
 		Reader:  bufio.NewReader(os.Stdin),
-		Writer:  os.Stdout,
+		Writer:  redact.Writer(os.Stdout),
 		Verbose: false,
 	}
 )
@@ -60,6 +61,8 @@ type ConsolePrinter struct {
 	Writer io.Writer
 
 	Verbose bool
+	Color   bool // Color the changes of providers.
+	Width   int  // Width of the terminal, 0 if unknown.
 }
 
 // StartDomain is called at the start of each domain.
@@ -69,7 +72,7 @@ func (c ConsolePrinter) StartDomain(domain string) {
 
 // PrintCorrection is called to print/format each correction.
 func (c ConsolePrinter) PrintCorrection(i int, correction *models.Correction) {
-	fmt.Fprintf(c.Writer, "#%d: %s\n", i+1, correction.Msg)
+	fmt.Fprintf(c.Writer, "#%d: %s\n", i+1, c.colorCorrection(correction.Msg))
 }
 
 // PromptToRun prompts the user to see if they want to execute a correction.
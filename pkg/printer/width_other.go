@@ -0,0 +1,11 @@
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package printer
+
+import "os"
+
+// terminalWidth returns 0: the width of terminals is only known on Unix,
+// elsewhere only $COLUMNS sets it.
+func terminalWidth(f *os.File) int {
+	return 0
+}
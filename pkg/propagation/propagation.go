@@ -0,0 +1,174 @@
+// Package propagation checks that the changes push made are served by
+// nameservers, so that operators know they are live rather than only
+// accepted by the provider's API.
+package propagation
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+)
+
+// Check is a record set and the records it should have.
+type Check struct {
+	Name string   // The FQDN, without the final dot.
+	Type string   // Such as "A".
+	Want []string // The rdata of the records, sorted. Empty if there should be none.
+}
+
+// Server is a nameserver to query.
+type Server struct {
+	Addr      string // host:port
+	Recursive bool   // A resolver rather than an authoritative nameserver.
+}
+
+func (s Server) String() string {
+	return strings.TrimSuffix(s.Addr, ":53")
+}
+
+// Result is what a server served for a check.
+type Result struct {
+	Check
+	Server Server
+	Got    []string
+	Err    error
+}
+
+// Converged reports whether the server served the records of the check.
+func (r *Result) Converged() bool {
+	return r.Err == nil && strings.Join(r.Got, "\n") == strings.Join(r.Want, "\n")
+}
+
+func (r *Result) String() string {
+	got := strings.Join(r.Got, ", ")
+	if r.Err != nil {
+		got = r.Err.Error()
+	} else if len(r.Got) == 0 {
+		got = "no records"
+	}
+	want := strings.Join(r.Want, ", ")
+	if len(r.Want) == 0 {
+		want = "no records"
+	}
+	return fmt.Sprintf("%s %s at %s: got %s, want %s", r.Type, r.Name, r.Server, got, want)
+}
+
+// Checkable reports whether records of type rtype can be checked: it must
+// be a real record type that doesn't change on its own, unlike the SOA.
+func Checkable(rtype string) bool {
+	_, ok := dns.StringToType[rtype]
+	return ok && rtype != "SOA"
+}
+
+// NewChecks returns the checks of the record sets keys, given all the
+// records of their domain after the push. Keys of types that can't be
+// checked are skipped.
+func NewChecks(keys []models.RecordKey, records []*models.RecordConfig) []Check {
+	want := map[models.RecordKey][]string{}
+	for _, r := range records {
+		if Checkable(r.Type) {
+			want[r.Key()] = append(want[r.Key()], rdata(r.ToRR()))
+		}
+	}
+	seen := map[models.RecordKey]bool{}
+	var checks []Check
+	for _, k := range keys {
+		if seen[k] || !Checkable(k.Type) {
+			continue
+		}
+		seen[k] = true
+		w := want[k]
+		sort.Strings(w)
+		checks = append(checks, Check{Name: k.NameFQDN, Type: k.Type, Want: w})
+	}
+	sort.Slice(checks, func(i, j int) bool {
+		if checks[i].Name != checks[j].Name {
+			return checks[i].Name < checks[j].Name
+		}
+		return checks[i].Type < checks[j].Type
+	})
+	return checks
+}
+
+// NameServers returns the servers of names, which may have a port.
+func NameServers(names []string, recursive bool) []Server {
+	var servers []Server
+	for _, n := range names {
+		n = strings.TrimSuffix(strings.TrimSpace(n), ".")
+		if n == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(n); err != nil {
+			n = net.JoinHostPort(n, "53")
+		}
+		servers = append(servers, Server{Addr: n, Recursive: recursive})
+	}
+	return servers
+}
+
+// rdata returns the rdata of rr as text, lowercased so that names compare
+// equal whatever their case.
+func rdata(rr dns.RR) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(rr.String(), rr.Header().String())))
+}
+
+// Query returns what server serves for the record set of c.
+func Query(server Server, c Check, timeout time.Duration) ([]string, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(c.Name), dns.StringToType[c.Type])
+	m.RecursionDesired = server.Recursive
+	client := &dns.Client{Timeout: timeout}
+	in, _, err := client.Exchange(m, server.Addr)
+	if err == nil && in.Truncated {
+		client.Net = "tcp"
+		in, _, err = client.Exchange(m, server.Addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if in.Rcode != dns.RcodeSuccess && in.Rcode != dns.RcodeNameError {
+		return nil, errors.New(dns.RcodeToString[in.Rcode])
+	}
+	got := []string{}
+	for _, rr := range in.Answer {
+		h := rr.Header()
+		if h.Rrtype == dns.StringToType[c.Type] && strings.EqualFold(h.Name, dns.Fqdn(c.Name)) {
+			got = append(got, rdata(rr))
+		}
+	}
+	sort.Strings(got)
+	return got, nil
+}
+
+// Wait queries each server for each check every interval, until all of
+// them converge or timeout passes. It returns the results that didn't
+// converge.
+func Wait(checks []Check, servers []Server, timeout, interval time.Duration) []*Result {
+	var pending []*Result
+	for _, c := range checks {
+		for _, s := range servers {
+			pending = append(pending, &Result{Check: c, Server: s})
+		}
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		var next []*Result
+		for _, r := range pending {
+			r.Got, r.Err = Query(r.Server, r.Check, 5*time.Second)
+			if !r.Converged() {
+				next = append(next, r)
+			}
+		}
+		pending = next
+		if len(pending) == 0 || time.Now().Add(interval).After(deadline) {
+			return pending
+		}
+		time.Sleep(interval)
+	}
+}
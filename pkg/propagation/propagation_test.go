@@ -0,0 +1,73 @@
+package propagation
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/miekg/dns"
+)
+
+// serve serves the records of zone on a local port, until stop is called.
+func serve(t *testing.T, zone *sync.Map) (server Server, stop func()) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("can't listen on UDP:", err)
+	}
+	s := &dns.Server{PacketConn: pc, Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(req)
+		m.Authoritative = true
+		zone.Range(func(_, v interface{}) bool {
+			rr := v.(dns.RR)
+			if rr.Header().Name == req.Question[0].Name && rr.Header().Rrtype == req.Question[0].Qtype {
+				m.Answer = append(m.Answer, rr)
+			}
+			return true
+		})
+		w.WriteMsg(m)
+	})}
+	started := make(chan bool)
+	s.NotifyStartedFunc = func() { close(started) }
+	go s.ActivateAndServe()
+	<-started
+	return Server{Addr: pc.LocalAddr().String()}, func() { s.Shutdown() }
+}
+
+func record(label, rtype, target string) *models.RecordConfig {
+	r := &models.RecordConfig{Type: rtype, TTL: 300}
+	r.SetLabel(label, "example.com")
+	r.SetTarget(target)
+	return r
+}
+
+func TestWait(t *testing.T) {
+	zone := &sync.Map{}
+	old := record("www", "A", "1.1.1.1").ToRR()
+	zone.Store("www", old)
+	server, stop := serve(t, zone)
+	defer stop()
+
+	desired := []*models.RecordConfig{record("www", "A", "1.2.3.4"), record("mail", "MX", "mx.example.com.")}
+	desired[1].MxPreference = 10
+	checks := NewChecks([]models.RecordKey{desired[0].Key(), desired[1].Key(), {NameFQDN: "old.example.com", Type: "A"}}, desired)
+	if len(checks) != 3 {
+		t.Fatalf("got %d checks, want 3", len(checks))
+	}
+
+	pending := Wait(checks, []Server{server}, 0, time.Millisecond)
+	if len(pending) != 2 {
+		t.Fatalf("got %d pending, want 2 (www and mail): %v", len(pending), pending)
+	}
+	if got := pending[0].String(); got != "MX mail.example.com at "+server.Addr+": got no records, want 10 mx.example.com." {
+		t.Errorf("got %q", got)
+	}
+
+	zone.Store("www", desired[0].ToRR())
+	zone.Store("mail", desired[1].ToRR())
+	if pending := Wait(checks, []Server{server}, time.Second, time.Millisecond); len(pending) != 0 {
+		t.Errorf("got %d pending, want none: %v", len(pending), pending)
+	}
+}
@@ -0,0 +1,105 @@
+// Package redact removes credentials from the output of dnscontrol.
+//
+// The values of creds.json are registered with Add when they are loaded.
+// Output that goes through String or Writer has them, and anything that
+// looks like a token or an Authorization header, replaced by Replacement.
+// This keeps them out of CI logs when, for example, a provider error
+// echoes the request it made.
+package redact
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Replacement is what secrets are replaced with.
+const Replacement = "[REDACTED]"
+
+// minLength is the length of the shortest value Add accepts. Shorter
+// values, such as "true" or a port number, are too likely to appear in
+// ordinary output.
+const minLength = 8
+
+var (
+	mu      sync.RWMutex
+	secrets []string
+)
+
+// patterns match secrets whatever creds.json says.
+var patterns = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	// HTTP headers and key=value pairs.
+	{regexp.MustCompile(`(?i)((?:authorization|proxy-authorization|x-auth-key|x-auth-token|x-api-key|private-token)["']?\s*[:=]\s*["']?(?:(?:bearer|basic|token|apikey)\s+)?)[^\s"',&]+`), "${1}" + Replacement},
+	{regexp.MustCompile(`(?i)((?:api[_-]?key|api[_-]?token|api[_-]?secret|access[_-]?token|secret[_-]?key|client[_-]?secret|password|passwd)["']?\s*[:=]\s*["']?)[^\s"',&]+`), "${1}" + Replacement},
+	// URLs with a password.
+	{regexp.MustCompile(`(\w+://[^\s:/@]+:)[^\s/@]+@`), "${1}" + Replacement + "@"},
+	// Well-known token formats.
+	{regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`), Replacement},
+	{regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`), Replacement},
+	{regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}\b`), Replacement},
+	{regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}\b`), Replacement},
+}
+
+// Add registers secret values. Values shorter than 8 characters are ignored.
+func Add(values ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if len(v) >= minLength {
+			secrets = append(secrets, v)
+		}
+	}
+	// Longest first, so a secret containing another is replaced whole.
+	sort.SliceStable(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+}
+
+// String returns s with the registered secrets and known token patterns replaced.
+func String(s string) string {
+	mu.RLock()
+	for _, v := range secrets {
+		s = strings.Replace(s, v, Replacement, -1)
+	}
+	mu.RUnlock()
+	for _, p := range patterns {
+		s = p.re.ReplaceAllString(s, p.repl)
+	}
+	return s
+}
+
+type writer struct {
+	w io.Writer
+}
+
+// Writer returns a writer that redacts everything written to it before
+// passing it on to w. Each Write is redacted on its own, so a secret
+// split over two writes is not found.
+func Writer(w io.Writer) io.Writer {
+	return writer{w}
+}
+
+func (w writer) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.w, String(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// RecoverPanic prints a redacted panic message and stack trace to stderr
+// and exits, as an unrecovered panic would. Use it as
+// "defer redact.RecoverPanic()" at the start of main and of every
+// goroutine, as a panic is only recovered in its own goroutine.
+func RecoverPanic() {
+	if r := recover(); r != nil {
+		fmt.Fprintf(os.Stderr, "panic: %s\n\n%s", String(fmt.Sprint(r)), String(string(debug.Stack())))
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,275 @@
+// Package replace rewrites the record targets of dnsconfig.js, for
+// estate-wide migrations such as moving every CNAME from one load
+// balancer to another.
+//
+// dnsconfig.js is a program, so the targets can't be rewritten in the
+// records it produces. Instead, each string literal of the file that
+// holds the old target is replaced on its own, and the file is run
+// again: the replacement is kept only if the only records that changed
+// are records of the requested types whose target went from the old
+// value to the new one. Literals that also feed other records (a
+// variable used in a TXT record too, for example) are left alone and
+// reported, so they can be edited by hand.
+package replace
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/StackExchange/dnscontrol/models"
+)
+
+// Literal is a string literal of a JavaScript source.
+type Literal struct {
+	Start, End int    // Byte offsets of the literal, quotes included.
+	Line       int    // Line number, starting at 1.
+	Value      string // The text between the quotes, as written.
+}
+
+// literals returns the string literals of src, skipping comments.
+func literals(src string) []Literal {
+	var lits []Literal
+	line := 1
+	for i := 0; i < len(src); i++ {
+		switch c := src[i]; {
+		case c == '\n':
+			line++
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			line++
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return lits
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 3
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != c && src[j] != '\n' {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) || src[j] != c {
+				// Unterminated; let the JavaScript interpreter complain.
+				i = j - 1
+				continue
+			}
+			lits = append(lits, Literal{Start: i, End: j + 1, Line: line, Value: src[i+1 : j]})
+			i = j
+		}
+	}
+	return lits
+}
+
+// sameName reports whether a and b are the same DNS name, ignoring case
+// and a trailing dot.
+func sameName(a, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, "."), strings.TrimSuffix(b, "."))
+}
+
+// Change is a record whose target is replaced.
+type Change struct {
+	Domain string
+	Record *models.RecordConfig // After the change.
+	Old    string               // The target before the change.
+}
+
+func (c *Change) String() string {
+	return fmt.Sprintf("%s: %s %s %s -> %s", c.Domain, c.Record.Type, c.Record.GetLabel(), c.Old, c.Record.GetTargetField())
+}
+
+// Result is the result of Rewrite.
+type Result struct {
+	Source  string    // The rewritten source.
+	Lines   []int     // The lines of the literals that were replaced.
+	Changes []*Change // The records whose target changed.
+	Skipped []string  // The literals holding the old target that were left alone, and why.
+}
+
+// Rewrite replaces the string literals of src that hold the target match
+// (with or without a trailing dot) with with, wherever they only set the
+// target of records of the given types (of any type if types is empty).
+// exec runs a source and returns its configuration.
+func Rewrite(src, match, with string, types []string, exec func(src string) (*models.DNSConfig, error)) (*Result, error) {
+	base, err := exec(src)
+	if err != nil {
+		return nil, err
+	}
+	r := &rewriter{base: base, match: match, with: with, types: map[string]bool{}}
+	for _, t := range types {
+		r.types[strings.ToUpper(strings.TrimSpace(t))] = true
+	}
+
+	res := &Result{Source: src}
+	var keep []Literal
+	for _, lit := range literals(src) {
+		if !sameName(lit.Value, match) {
+			continue
+		}
+		cfg, err := exec(r.apply(src, []Literal{lit}))
+		if err != nil {
+			res.Skipped = append(res.Skipped, fmt.Sprintf("line %d: %s", lit.Line, err))
+			continue
+		}
+		changes, other := r.compare(cfg)
+		if len(other) != 0 {
+			res.Skipped = append(res.Skipped, fmt.Sprintf("line %d: would also change %s", lit.Line, strings.Join(other, ", ")))
+			continue
+		}
+		if len(changes) != 0 {
+			keep = append(keep, lit)
+			res.Lines = append(res.Lines, lit.Line)
+		}
+	}
+	if len(keep) == 0 {
+		return res, nil
+	}
+
+	res.Source = r.apply(src, keep)
+	cfg, err := exec(res.Source)
+	if err != nil {
+		return nil, err
+	}
+	changes, other := r.compare(cfg)
+	if len(other) != 0 {
+		return nil, errors.Errorf("replacing %q together would also change %s", match, strings.Join(other, ", "))
+	}
+	res.Changes = changes
+	return res, nil
+}
+
+type rewriter struct {
+	base        *models.DNSConfig
+	match, with string
+	types       map[string]bool
+}
+
+// apply replaces lits (in order) in src.
+func (r *rewriter) apply(src string, lits []Literal) string {
+	var b strings.Builder
+	last := 0
+	for _, lit := range lits {
+		v := strings.TrimSuffix(r.with, ".")
+		if strings.HasSuffix(lit.Value, ".") {
+			v += "."
+		}
+		b.WriteString(src[last : lit.Start+1])
+		b.WriteString(v)
+		last = lit.End - 1
+	}
+	b.WriteString(src[last:])
+	return b.String()
+}
+
+// compare returns the records of cfg whose target changed as requested
+// from r.base, and describes the other differences.
+func (r *rewriter) compare(cfg *models.DNSConfig) (changes []*Change, other []string) {
+	if len(cfg.Domains) != len(r.base.Domains) || !sameJSON(withoutDomains(cfg), withoutDomains(r.base)) {
+		return nil, []string{"the providers or domains"}
+	}
+	for i, dc := range cfg.Domains {
+		old := r.base.Domains[i]
+		if len(dc.Records) != len(old.Records) || !sameJSON(withoutRecords(dc), withoutRecords(old)) {
+			other = append(other, "domain "+old.Name)
+			continue
+		}
+		for j, rec := range dc.Records {
+			was := old.Records[j]
+			if sameJSON(rec, was) {
+				continue
+			}
+			cp := *rec
+			cp.Target = was.Target
+			if sameJSON(&cp, was) && sameName(was.Target, r.match) && sameName(rec.Target, r.with) &&
+				(len(r.types) == 0 || r.types[rec.Type]) {
+				changes = append(changes, &Change{Domain: old.Name, Record: rec, Old: was.Target})
+				continue
+			}
+			other = append(other, fmt.Sprintf("%s %s in %s", was.Type, was.GetLabel(), old.Name))
+		}
+	}
+	return changes, other
+}
+
+func withoutDomains(cfg *models.DNSConfig) models.DNSConfig {
+	c := *cfg
+	c.Domains = nil
+	return c
+}
+
+func withoutRecords(dc *models.DomainConfig) models.DomainConfig {
+	d := *dc
+	d.Records = nil
+	return d
+}
+
+func sameJSON(a, b interface{}) bool {
+	ja, erra := json.Marshal(a)
+	jb, errb := json.Marshal(b)
+	return erra == nil && errb == nil && string(ja) == string(jb)
+}
+
+// Patch returns a unified diff from old to new, two versions of the file
+// name that have the same number of lines (as Rewrite's sources do).
+func Patch(name, old, new string) string {
+	a, b := strings.Split(old, "\n"), strings.Split(new, "\n")
+	if len(a) != len(b) {
+		panic("replace.Patch: the sources don't have the same number of lines")
+	}
+	const context = 3
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", name, name)
+	for i := 0; i < len(a); {
+		if a[i] == b[i] {
+			i++
+			continue
+		}
+		// A hunk runs from context lines before the first change to
+		// context lines after the last change that is close enough.
+		start, end := i-context, i
+		if start < 0 {
+			start = 0
+		}
+		for j := i; j < len(a) && j <= end+2*context; j++ {
+			if a[j] != b[j] {
+				end = j
+			}
+		}
+		stop := end + context + 1
+		if stop > len(a) {
+			stop = len(a)
+		}
+		if stop == len(a) && a[len(a)-1] == "" && b[len(b)-1] == "" {
+			stop-- // The empty string after the final newline isn't a line.
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", start+1, stop-start, start+1, stop-start)
+		for j := start; j < stop; {
+			if a[j] == b[j] {
+				fmt.Fprintf(&out, " %s\n", a[j])
+				j++
+				continue
+			}
+			k := j
+			for k < stop && a[k] != b[k] {
+				k++
+			}
+			for _, l := range a[j:k] {
+				fmt.Fprintf(&out, "-%s\n", l)
+			}
+			for _, l := range b[j:k] {
+				fmt.Fprintf(&out, "+%s\n", l)
+			}
+			j = k
+		}
+		i = stop
+	}
+	return out.String()
+}
@@ -0,0 +1,113 @@
+package replace
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/js"
+)
+
+func TestLiterals(t *testing.T) {
+	src := `var a = "one"; // "not this"
+/* 'nor
+this' */ b('two', "it\"s")`
+	var got []string
+	for _, l := range literals(src) {
+		got = append(got, l.Value)
+		if src[l.Start] != src[l.End-1] {
+			t.Errorf("%q: bad offsets %d %d", l.Value, l.Start, l.End)
+		}
+	}
+	if strings.Join(got, "|") != `one|two|it\"s` {
+		t.Errorf("got %q", got)
+	}
+	if l := literals(src)[1]; l.Line != 3 {
+		t.Errorf("'two' is on line 3, got %d", l.Line)
+	}
+}
+
+const config = `var REG = NewRegistrar("none", "NONE");
+var DNS = NewDnsProvider("bind", "BIND");
+var LB = "old-lb.example.net.";
+
+D("example.com", REG, DnsProvider(DNS),
+    CNAME("www", LB),
+    CNAME("api", "OLD-LB.example.net."),
+    TXT("note", LB)
+);
+D("example.org", REG, DnsProvider(DNS),
+    CNAME("www", "old-lb.example.net."),
+    MX("@", 10, 'old-lb.example.net.')
+);
+`
+
+func run(src string) (*models.DNSConfig, error) {
+	return js.ExecuteJavascriptSource("dnsconfig.js", []byte(src), false)
+}
+
+func TestRewrite(t *testing.T) {
+	res, err := Rewrite(config, "old-lb.example.net", "new-lb.example.net", []string{"cname"}, run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Lines) != 2 || res.Lines[0] != 7 || res.Lines[1] != 11 {
+		t.Errorf("expected lines 7 and 11 to be replaced, got %v", res.Lines)
+	}
+	if len(res.Changes) != 2 {
+		t.Fatalf("expected 2 changes, got %v", res.Changes)
+	}
+	if s := res.Changes[0].String(); s != "example.com: CNAME api OLD-LB.example.net. -> new-lb.example.net." {
+		t.Errorf("got %q", s)
+	}
+	// LB also feeds a TXT record, and the MX isn't a CNAME.
+	if len(res.Skipped) != 2 || !strings.HasPrefix(res.Skipped[0], "line 3: would also change TXT note") ||
+		!strings.HasPrefix(res.Skipped[1], "line 12: would also change MX @") {
+		t.Errorf("unexpected skipped %q", res.Skipped)
+	}
+	if !strings.Contains(res.Source, `CNAME("api", "new-lb.example.net."),`) ||
+		!strings.Contains(res.Source, `var LB = "old-lb.example.net.";`) {
+		t.Errorf("unexpected source:\n%s", res.Source)
+	}
+
+	res, err = Rewrite(config, "old-lb.example.net.", "new-lb.example.net", nil, run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Changes) != 3 || len(res.Skipped) != 1 {
+		t.Errorf("without --types, expected 3 changes and 1 skipped, got %v %q", res.Changes, res.Skipped)
+	}
+
+	res, err = Rewrite(config, "nowhere.example.net", "new-lb.example.net", nil, run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Changes) != 0 || res.Source != config {
+		t.Errorf("expected no changes")
+	}
+}
+
+func TestPatch(t *testing.T) {
+	old := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n16\n"
+	new := strings.Replace(strings.Replace(old, "\n2\n", "\ntwo\n", 1), "\n15\n", "\nfifteen\n", 1)
+	want := `--- a/f.js
++++ b/f.js
+@@ -1,5 +1,5 @@
+ 1
+-2
++two
+ 3
+ 4
+ 5
+@@ -12,5 +12,5 @@
+ 12
+ 13
+ 14
+-15
++fifteen
+ 16
+`
+	if got := Patch("f.js", old, new); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,205 @@
+package report
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/pkg/redact"
+	"github.com/pkg/errors"
+)
+
+// Document is a change report: a Run rendered as Markdown or HTML, to
+// attach to a change ticket.
+type Document struct {
+	*Run
+	Generated time.Time
+}
+
+// Before returns the record before c, or nil if c creates it.
+func (c *Change) Before() *Record {
+	switch c.Action {
+	case "delete":
+		return c.Record
+	case "modify":
+		return c.Old
+	}
+	return nil
+}
+
+// After returns the record after c, or nil if c deletes it.
+func (c *Change) After() *Record {
+	if c.Action == "delete" {
+		return nil
+	}
+	return c.Record
+}
+
+// Failed returns the corrections of the provider that ran and failed.
+func (p *Provider) Failed() []*Correction {
+	var failed []*Correction
+	for _, c := range p.Corrections {
+		if c.Error != "" {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// documentFuncs are the functions available to the document templates.
+var documentFuncs = map[string]interface{}{
+	"lines": funcs["lines"],
+	"time": func(t time.Time) string {
+		return t.UTC().Format("2006-01-02 15:04:05 UTC")
+	},
+	// md escapes s for a Markdown table cell.
+	"md": func(s string) string {
+		s = strings.Replace(s, `\`, `\\`, -1)
+		s = strings.Replace(s, "|", `\|`, -1)
+		s = strings.Replace(s, "\n", " ", -1)
+		for _, c := range []string{"*", "_", "`", "<", "[", "]"} {
+			s = strings.Replace(s, c, `\`+c, -1)
+		}
+		return s
+	},
+}
+
+const markdownTemplate = `# DNSControl {{if .Push}}push{{else}}preview{{end}}: {{.Corrections}} correction{{if ne .Corrections 1}}s{{end}}
+
+Generated {{time .Generated}}.
+
+| Domain | Corrections |
+|---|---|
+{{range .Domains}}| {{md .Name}} | {{.Corrections}} |
+{{end}}{{range .Domains}}
+## {{md .Name}}
+{{if .Partial}}
+**Partly pushed**: some providers were changed and others failed.
+{{end}}{{range .Warnings}}
+> Warning: {{md .}}
+{{end}}{{range .Providers}}
+### {{md .Name}}{{if .Registrar}} (registrar){{end}}
+{{if .Skipped}}
+Skipped.
+{{else if .Error}}
+Error: {{md .Error}}
+{{else if not .Corrections}}
+No changes.
+{{else}}{{if .Changes}}
+| Action | Name | Type | Before | After |
+|---|---|---|---|---|
+{{range .Changes}}| {{.Action}} | {{md .Record.Name}} | {{.Record.Type}} | {{with .Before}}{{md .Value}} (TTL {{.TTL}}){{end}} | {{with .After}}{{md .Value}} (TTL {{.TTL}}){{end}} |
+{{end}}{{end}}
+Corrections:
+
+` + "```" + `
+{{range .Corrections}}{{range lines .Msg}}{{.}}
+{{end}}{{end}}` + "```" + `
+{{with .Failed}}
+Failed:
+{{range .}}
+* {{md (index (lines .Msg) 0)}}: {{md .Error}}{{end}}
+{{end}}{{end}}{{end}}{{end}}`
+
+const htmlTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>DNSControl {{if .Push}}push{{else}}preview{{end}}: {{.Corrections}} correction{{if ne .Corrections 1}}s{{end}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; vertical-align: top; }
+td.value { font-family: monospace; word-break: break-all; }
+.create { background: #e6ffed; } .delete { background: #ffeef0; } .modify { background: #fff8c5; }
+.error, .warning { color: #b31d28; }
+pre { background: #f6f8fa; padding: 0.6em; }
+</style>
+</head>
+<body>
+<h1>DNSControl {{if .Push}}push{{else}}preview{{end}}: {{.Corrections}} correction{{if ne .Corrections 1}}s{{end}}</h1>
+<p>Generated {{time .Generated}}.</p>
+<table>
+<tr><th>Domain</th><th>Corrections</th></tr>
+{{range .Domains}}<tr><td>{{.Name}}</td><td>{{.Corrections}}</td></tr>
+{{end}}</table>
+{{range .Domains}}
+<h2>{{.Name}}</h2>
+{{if .Partial}}<p class="error"><b>Partly pushed</b>: some providers were changed and others failed.</p>
+{{end}}{{range .Warnings}}<p class="warning">Warning: {{.}}</p>
+{{end}}{{range .Providers}}
+<h3>{{.Name}}{{if .Registrar}} (registrar){{end}}</h3>
+{{if .Skipped}}<p>Skipped.</p>
+{{else if .Error}}<p class="error">Error: {{.Error}}</p>
+{{else if not .Corrections}}<p>No changes.</p>
+{{else}}{{if .Changes}}<table>
+<tr><th>Action</th><th>Name</th><th>Type</th><th>Before</th><th>After</th></tr>
+{{range .Changes}}<tr class="{{.Action}}"><td>{{.Action}}</td><td>{{.Record.Name}}</td><td>{{.Record.Type}}</td><td class="value">{{with .Before}}{{.Value}} (TTL {{.TTL}}){{end}}</td><td class="value">{{with .After}}{{.Value}} (TTL {{.TTL}}){{end}}</td></tr>
+{{end}}</table>
+{{end}}<p>Corrections:</p>
+<pre>{{range .Corrections}}{{range lines .Msg}}{{.}}
+{{end}}{{end}}</pre>
+{{with .Failed}}<p class="error">Failed:</p>
+<ul>{{range .}}<li>{{index (lines .Msg) 0}}: {{.Error}}</li>{{end}}</ul>
+{{end}}{{end}}{{end}}{{end}}
+</body>
+</html>
+`
+
+// RenderMarkdown renders doc as Markdown.
+func RenderMarkdown(doc *Document) (string, error) {
+	t, err := template.New("markdown").Funcs(documentFuncs).Parse(markdownTemplate)
+	if err != nil {
+		return "", errors.Wrap(err, "template markdown")
+	}
+	buf := &bytes.Buffer{}
+	err = t.Execute(buf, doc)
+	return buf.String(), err
+}
+
+// RenderHTML renders doc as a standalone HTML page.
+func RenderHTML(doc *Document) (string, error) {
+	t, err := htmltemplate.New("html").Funcs(documentFuncs).Parse(htmlTemplate)
+	if err != nil {
+		return "", errors.Wrap(err, "template html")
+	}
+	buf := &bytes.Buffer{}
+	err = t.Execute(buf, doc)
+	return buf.String(), err
+}
+
+// WriteDocument renders doc to filename, as HTML if it ends in .html or
+// .htm and as Markdown if it ends in .md or .markdown. Secrets are
+// redacted.
+func WriteDocument(filename string, doc *Document) error {
+	render, err := documentRenderer(filename)
+	if err != nil {
+		return err
+	}
+	s, err := render(doc)
+	if err != nil {
+		return errors.Wrapf(err, "rendering %s", filename)
+	}
+	return ioutil.WriteFile(filename, []byte(redact.String(s)), 0644)
+}
+
+// CheckDocumentName returns an error if WriteDocument can't write a
+// document of that name, so that the name can be checked before a run.
+func CheckDocumentName(filename string) error {
+	_, err := documentRenderer(filename)
+	return err
+}
+
+func documentRenderer(filename string) (func(*Document) (string, error), error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".md", ".markdown":
+		return RenderMarkdown, nil
+	case ".html", ".htm":
+		return RenderHTML, nil
+	}
+	return nil, errors.Errorf("%s: the report must be a .md or .html file", filename)
+}
@@ -0,0 +1,77 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func testDocument() *Document {
+	run := &Run{Push: true, Domains: []*Domain{{
+		Name:     "example.com",
+		Warnings: []string{"something odd"},
+		Providers: []*Provider{
+			{
+				Name: "route53",
+				Changes: []*Change{
+					{Action: "create", Record: &Record{Name: "new.example.com", Type: "A", TTL: 300, Value: "192.0.2.1"}},
+					{Action: "modify", Record: &Record{Name: "www.example.com", Type: "TXT", TTL: 300, Value: `"a|b" <x>`}, Old: &Record{Name: "www.example.com", Type: "TXT", TTL: 600, Value: `"old"`}},
+					{Action: "delete", Record: &Record{Name: "old.example.com", Type: "CNAME", TTL: 300, Value: "gone.example.com."}},
+				},
+				Corrections: []*Correction{{Msg: "CREATE A new.example.com\nMODIFY TXT www.example.com\n", Ran: true}, {Msg: "DELETE CNAME old.example.com", Ran: true, Error: "throttled"}},
+			},
+			{Name: "cloudflare", Error: "unauthorized"},
+			{Name: "none", Registrar: true, Skipped: true},
+		},
+	}}}
+	return &Document{Run: run, Generated: time.Date(2019, 5, 2, 14, 3, 0, 0, time.UTC)}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	md, err := RenderMarkdown(testDocument())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"# DNSControl push: 2 corrections\n",
+		"Generated 2019-05-02 14:03:00 UTC.",
+		"| example.com | 2 |",
+		"> Warning: something odd",
+		"| create | new.example.com | A |  | 192.0.2.1 (TTL 300) |",
+		`| modify | www.example.com | TXT | "old" (TTL 600) | "a\|b" \<x> (TTL 300) |`,
+		"| delete | old.example.com | CNAME | gone.example.com. (TTL 300) |  |",
+		"```\nCREATE A new.example.com\nMODIFY TXT www.example.com\nDELETE CNAME old.example.com\n```",
+		"* DELETE CNAME old.example.com: throttled",
+		"### cloudflare\n\nError: unauthorized",
+		"### none (registrar)\n\nSkipped.",
+	} {
+		if !strings.Contains(md, expected) {
+			t.Errorf("expected %q in:\n%s", expected, md)
+		}
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	html, err := RenderHTML(testDocument())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"<title>DNSControl push: 2 corrections</title>",
+		`<tr class="modify"><td>modify</td><td>www.example.com</td><td>TXT</td><td class="value">&#34;old&#34; (TTL 600)</td><td class="value">&#34;a|b&#34; &lt;x&gt; (TTL 300)</td></tr>`,
+		`<p class="error">Error: unauthorized</p>`,
+		"<li>DELETE CNAME old.example.com: throttled</li>",
+	} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("expected %q in:\n%s", expected, html)
+		}
+	}
+}
+
+func TestCheckDocumentName(t *testing.T) {
+	for name, ok := range map[string]bool{"out.md": true, "OUT.HTML": true, "out.htm": true, "out.txt": false, "out": false} {
+		if err := CheckDocumentName(name); (err == nil) != ok {
+			t.Errorf("%s: unexpected error %v", name, err)
+		}
+	}
+}
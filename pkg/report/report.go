@@ -0,0 +1,317 @@
+// Package report collects the results of a preview or push so they can be
+// rendered in other formats than the console output.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/pkg/errors"
+)
+
+// SchemaVersion is the version of the JSON encoding of Run, described by
+// docs/schema/report-v1.json. Fields may be added without changing it;
+// removing, renaming or changing the meaning of a field needs a new version.
+const SchemaVersion = 1
+
+// Run is the result of a preview or push.
+type Run struct {
+	Push    bool      `json:"push"`
+	Domains []*Domain `json:"domains,omitempty"`
+}
+
+// MarshalJSON encodes r with its schema_version.
+func (r Run) MarshalJSON() ([]byte, error) {
+	type run Run // Without the MarshalJSON method.
+	return json.Marshal(struct {
+		SchemaVersion int `json:"schema_version"`
+		run
+	}{SchemaVersion, run(r)})
+}
+
+// Corrections returns the number of corrections of all domains.
+func (r *Run) Corrections() int {
+	n := 0
+	for _, d := range r.Domains {
+		n += d.Corrections()
+	}
+	return n
+}
+
+// Domain is the result for one domain.
+type Domain struct {
+	Name     string   `json:"name"`
+	Warnings []string `json:"warnings,omitempty"`
+	// DesiredHash is the zonehash.Hash of the records in dnsconfig.js, with --zone-hashes.
+	DesiredHash string `json:"desired_hash,omitempty"`
+	// Partial is true if push changed some DNS providers of the domain but failed at others.
+	Partial   bool        `json:"partial,omitempty"`
+	Providers []*Provider `json:"providers,omitempty"`
+}
+
+// Corrections returns the number of corrections of all providers of the domain.
+func (d *Domain) Corrections() int {
+	n := 0
+	for _, p := range d.Providers {
+		n += len(p.Corrections)
+	}
+	return n
+}
+
+// Provider is the result for one DNS provider or registrar of a domain.
+type Provider struct {
+	Name      string `json:"name"`
+	Registrar bool   `json:"registrar"`
+	Skipped   bool   `json:"skipped"`
+	Error     string `json:"error,omitempty"`
+	// ActualHash is the zonehash.Hash of the records served, with --zone-hashes.
+	ActualHash  string        `json:"actual_hash,omitempty"`
+	Changes     []*Change     `json:"changes,omitempty"`
+	Corrections []*Correction `json:"corrections,omitempty"`
+}
+
+// Change is a record the provider creates, deletes or modifies. Providers
+// that don't diff record by record have corrections but no changes.
+type Change struct {
+	Action string  `json:"action"` // "create", "delete" or "modify".
+	Record *Record `json:"record"` // The record created or deleted, or the modified record after the change.
+	Old    *Record `json:"old,omitempty"`
+}
+
+// Record is a record of a Change.
+type Record struct {
+	Name  string            `json:"name"` // Fully qualified, without the final dot.
+	Type  string            `json:"type"`
+	TTL   uint32            `json:"ttl"`
+	Value string            `json:"value"` // All the fields of the record, as in a zone file.
+	Meta  map[string]string `json:"meta,omitempty"`
+}
+
+// NewRecord returns the Record of rc.
+func NewRecord(rc *models.RecordConfig) *Record {
+	if rc == nil {
+		return nil
+	}
+	return &Record{Name: rc.GetLabelFQDN(), Type: rc.Type, TTL: rc.TTL, Value: rc.GetTargetCombined(), Meta: rc.Metadata}
+}
+
+// Correction is one correction of a provider.
+type Correction struct {
+	Msg string `json:"msg"`
+	// Ran is true if push ran the correction. Error is its error, if any.
+	Ran   bool   `json:"ran"`
+	Error string `json:"error,omitempty"`
+}
+
+// HashRecorder is implemented by the printer.CLIs that keep zone hashes.
+type HashRecorder interface {
+	// DesiredHash is called with the desired hash of the current domain.
+	DesiredHash(hash string)
+	// ActualHash is called with the actual hash of the current provider.
+	ActualHash(hash string)
+}
+
+// ChangeRecorder is implemented by the printer.CLIs that keep the changes of providers.
+type ChangeRecorder interface {
+	// Changes is called with the changes of the current provider.
+	Changes(changes []*Change)
+}
+
+// ConfigRecorder is implemented by the printer.CLIs that keep the configuration being run.
+type ConfigRecorder interface {
+	// Config is called once the configuration is read and validated. valid
+	// is false if it has validation errors (other than warnings).
+	Config(cfg *models.DNSConfig, valid bool)
+}
+
+// PartialRecorder is implemented by the printer.CLIs that keep which domains were partly pushed.
+type PartialRecorder interface {
+	// Partial is called when push changed some DNS providers of the current domain but failed at others.
+	Partial()
+}
+
+// Recorder is a printer.CLI that records everything it is told in Run,
+// and passes it on to another printer.CLI.
+type Recorder struct {
+	printer.CLI
+	Run Run
+	// DNSConfig is the configuration run, if it could be read, and Valid
+	// whether it passed validation.
+	DNSConfig *models.DNSConfig
+	Valid     bool
+	// Durations is the time spent in each provider, getting and running
+	// its corrections.
+	Durations map[string]time.Duration
+
+	domain     *Domain
+	provider   *Provider
+	correction *Correction
+	started    time.Time
+}
+
+// NewRecorder returns a Recorder that passes everything on to out.
+func NewRecorder(out printer.CLI, push bool) *Recorder {
+	return &Recorder{CLI: out, Run: Run{Push: push}, Durations: map[string]time.Duration{}}
+}
+
+// elapsed adds the time since r.started to the duration of the current provider.
+func (r *Recorder) elapsed() {
+	if r.provider != nil && !r.started.IsZero() {
+		r.Durations[r.provider.Name] += time.Since(r.started)
+	}
+	r.started = time.Time{}
+}
+
+// StartDomain is called at the start of each domain.
+func (r *Recorder) StartDomain(domain string) {
+	r.domain = &Domain{Name: domain}
+	r.provider = nil
+	r.Run.Domains = append(r.Run.Domains, r.domain)
+	r.CLI.StartDomain(domain)
+}
+
+// StartDNSProvider is called at the start of each new provider.
+func (r *Recorder) StartDNSProvider(name string, skip bool) {
+	r.startProvider(&Provider{Name: name, Skipped: skip})
+	r.CLI.StartDNSProvider(name, skip)
+}
+
+// StartRegistrar is called at the start of each new registrar.
+func (r *Recorder) StartRegistrar(name string, skip bool) {
+	r.startProvider(&Provider{Name: name, Registrar: true, Skipped: skip})
+	r.CLI.StartRegistrar(name, skip)
+}
+
+func (r *Recorder) startProvider(p *Provider) {
+	if r.domain != nil {
+		r.provider = p
+		r.domain.Providers = append(r.domain.Providers, p)
+		r.started = time.Now()
+	}
+}
+
+// EndProvider is called at the end of each provider.
+func (r *Recorder) EndProvider(numCorrections int, err error) {
+	if r.provider != nil && err != nil {
+		r.provider.Error = err.Error()
+	}
+	r.elapsed()
+	r.CLI.EndProvider(numCorrections, err)
+}
+
+// PrintCorrection is called to print/format each correction.
+func (r *Recorder) PrintCorrection(n int, c *models.Correction) {
+	r.correction = &Correction{Msg: c.Msg}
+	if r.provider != nil {
+		r.provider.Corrections = append(r.provider.Corrections, r.correction)
+	}
+	r.started = time.Now()
+	r.CLI.PrintCorrection(n, c)
+}
+
+// EndCorrection is called at the end of each correction.
+func (r *Recorder) EndCorrection(err error) {
+	if r.correction != nil {
+		r.correction.Ran = true
+		if err != nil {
+			r.correction.Error = err.Error()
+		}
+	}
+	r.elapsed()
+	r.CLI.EndCorrection(err)
+}
+
+// Merge adds the domains and durations child recorded to r. The domains
+// run in parallel each have their own Recorder.
+func (r *Recorder) Merge(child *Recorder) {
+	r.Run.Domains = append(r.Run.Domains, child.Run.Domains...)
+	for name, d := range child.Durations {
+		r.Durations[name] += d
+	}
+}
+
+// DesiredHash records the desired hash of the current domain.
+func (r *Recorder) DesiredHash(hash string) {
+	if r.domain != nil {
+		r.domain.DesiredHash = hash
+	}
+}
+
+// ActualHash records the actual hash of the current provider.
+func (r *Recorder) ActualHash(hash string) {
+	if r.provider != nil {
+		r.provider.ActualHash = hash
+	}
+}
+
+// Changes records the changes of the current provider.
+func (r *Recorder) Changes(changes []*Change) {
+	if r.provider != nil {
+		r.provider.Changes = append(r.provider.Changes, changes...)
+	}
+}
+
+// PrintChanges passes changes on, if the printer.CLI can print them.
+func (r *Recorder) PrintChanges(changes []printer.Change) {
+	if cp, ok := r.CLI.(printer.ChangePrinter); ok {
+		cp.PrintChanges(changes)
+	}
+}
+
+// Config records the configuration run.
+func (r *Recorder) Config(cfg *models.DNSConfig, valid bool) {
+	r.DNSConfig, r.Valid = cfg, valid
+}
+
+// Partial records that the current domain was partly pushed.
+func (r *Recorder) Partial() {
+	if r.domain != nil {
+		r.domain.Partial = true
+	}
+}
+
+// Warnf is called to print/format a warning.
+func (r *Recorder) Warnf(format string, args ...interface{}) {
+	if r.domain != nil {
+		r.domain.Warnings = append(r.domain.Warnings, strings.TrimSpace(fmt.Sprintf(format, args...)))
+	}
+	r.CLI.Warnf(format, args...)
+}
+
+// funcs are the functions available to templates, in addition to the
+// text/template builtins.
+var funcs = template.FuncMap{
+	"join":      strings.Join,
+	"lower":     strings.ToLower,
+	"upper":     strings.ToUpper,
+	"trimSpace": strings.TrimSpace,
+	"lines": func(s string) []string {
+		return strings.Split(strings.TrimRight(s, "\n"), "\n")
+	},
+	"json": func(v interface{}) (string, error) {
+		b, err := json.MarshalIndent(v, "", "  ")
+		return string(b), err
+	},
+}
+
+// ParseTemplate parses a text/template that renders a Run.
+func ParseTemplate(name, text string) (*template.Template, error) {
+	t, err := template.New(name).Funcs(funcs).Parse(text)
+	return t, errors.Wrapf(err, "template %s", name)
+}
+
+// ParseTemplateFile is like ParseTemplate, but reads the template from a file.
+func ParseTemplateFile(filename string) (*template.Template, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTemplate(filepath.Base(filename), string(b))
+}
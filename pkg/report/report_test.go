@@ -0,0 +1,145 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/pkg/errors"
+)
+
+const testTemplate = `{{range .Domains}}{{if .Corrections}}<details><summary>{{.Name}}: {{.Corrections}} changes</summary>
+{{range .Providers}}{{$p := .Name}}{{range .Corrections}}{{range lines .Msg}}- {{$p}}: {{.}}
+{{end}}{{end}}{{end}}</details>
+{{end}}{{end}}Total: {{.Corrections}}`
+
+func TestRecorderTemplate(t *testing.T) {
+	r := NewRecorder(printer.ConsolePrinter{Writer: ioutil.Discard}, false)
+	r.StartDomain("example.com")
+	r.Warnf("something odd\n")
+	r.StartDNSProvider("bind", false)
+	r.EndProvider(1, nil)
+	r.PrintCorrection(0, &models.Correction{Msg: "CREATE A www 1.2.3.4\nDELETE A old 1.2.3.5\n"})
+	r.StartRegistrar("none", true)
+	r.StartDomain("example.net")
+	r.StartDNSProvider("bind", false)
+	r.EndProvider(0, errors.Errorf("boom"))
+
+	if len(r.Run.Domains) != 2 || r.Run.Domains[0].Warnings[0] != "something odd" || r.Run.Domains[1].Providers[0].Error != "boom" {
+		t.Fatalf("unexpected run %+v", r.Run)
+	}
+
+	f, err := ioutil.TempFile("", "template")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString(testTemplate)
+	f.Close()
+	tmpl, err := ParseTemplateFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, &r.Run); err != nil {
+		t.Fatal(err)
+	}
+	expected := `<details><summary>example.com: 1 changes</summary>
+- bind: CREATE A www 1.2.3.4
+- bind: DELETE A old 1.2.3.5
+</details>
+Total: 1`
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	r := NewRecorder(printer.ConsolePrinter{Writer: ioutil.Discard}, true)
+	r.StartDomain("example.com")
+	r.DesiredHash("aaa")
+	r.StartDNSProvider("bind", false)
+	r.EndProvider(1, nil)
+	r.ActualHash("bbb")
+	r.PrintCorrection(0, &models.Correction{Msg: "CREATE A www 1.2.3.4"})
+	r.EndCorrection(errors.Errorf("boom"))
+	r.Partial()
+
+	tmpl, err := ParseTemplate("json", "{{json .}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, &r.Run); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		SchemaVersion int  `json:"schema_version"`
+		Push          bool `json:"push"`
+		Domains       []struct {
+			Name        string
+			DesiredHash string `json:"desired_hash"`
+			Partial     bool
+			Providers   []struct {
+				Name        string
+				ActualHash  string `json:"actual_hash"`
+				Corrections []struct {
+					Msg   string
+					Ran   bool
+					Error string
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	d, p := doc.Domains[0], doc.Domains[0].Providers[0]
+	c := p.Corrections[0]
+	if doc.SchemaVersion != SchemaVersion || !doc.Push || c.Msg != "CREATE A www 1.2.3.4" || !c.Ran || c.Error != "boom" || d.DesiredHash != "aaa" || !d.Partial || p.ActualHash != "bbb" {
+		t.Errorf("unexpected JSON %s", buf)
+	}
+}
+
+// TestSchemaVersion makes sure the published schema is updated with SchemaVersion.
+func TestSchemaVersion(t *testing.T) {
+	b, err := ioutil.ReadFile(fmt.Sprintf("../../docs/schema/report-v%d.json", SchemaVersion))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema struct {
+		Properties struct {
+			SchemaVersion struct {
+				Const int
+			} `json:"schema_version"`
+		}
+	}
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if schema.Properties.SchemaVersion.Const != SchemaVersion {
+		t.Errorf("schema has version %d, expected %d", schema.Properties.SchemaVersion.Const, SchemaVersion)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	r := NewRecorder(printer.ConsolePrinter{Writer: ioutil.Discard}, false)
+	for _, name := range []string{"example.com", "example.net"} {
+		child := NewRecorder(printer.ConsolePrinter{Writer: ioutil.Discard}, false)
+		child.StartDomain(name)
+		child.StartDNSProvider("bind", false)
+		child.EndProvider(0, nil)
+		r.Merge(child)
+	}
+	if len(r.Run.Domains) != 2 || r.Run.Domains[1].Name != "example.net" {
+		t.Errorf("unexpected domains %v", r.Run.Domains)
+	}
+	if _, ok := r.Durations["bind"]; !ok {
+		t.Errorf("expected the time spent in bind")
+	}
+}
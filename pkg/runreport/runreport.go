@@ -0,0 +1,172 @@
+// Package runreport describes a preview or push for bug reports.
+//
+// With --run-report, preview and push write a JSON file with the versions,
+// flags, providers, counts, durations and kinds of errors of the run, so
+// an issue can be reproduced without asking about the environment. It is
+// written locally and never sent anywhere, and it holds no secrets: no
+// credentials, no flag values, no records and no error messages.
+package runreport
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/pkg/report"
+)
+
+// SchemaVersion is the version of the JSON encoding of Report, described
+// by docs/schema/run-report-v1.json. Fields may be added without changing
+// it; removing, renaming or changing the meaning of a field needs a new
+// version.
+const SchemaVersion = 1
+
+// Report is the contents of the run report.
+type Report struct {
+	SchemaVersion int    `json:"schema_version"`
+	Version       string `json:"version"`
+	GoVersion     string `json:"go_version"`
+	OS            string `json:"os"`
+	Arch          string `json:"arch"`
+	Command       string `json:"command"`
+	// Flags are the names of the flags given, without their values.
+	Flags     []string    `json:"flags"`
+	Providers []*Provider `json:"providers"`
+	// Domains is the number of domains in dnsconfig.js, DomainsRun the
+	// number run (see --domains) and Records the number of their records.
+	Domains     int `json:"domains"`
+	DomainsRun  int `json:"domains_run"`
+	Records     int `json:"records"`
+	Corrections int `json:"corrections"`
+	// Seconds is the duration of the run, and ProviderSeconds the time spent
+	// in each provider.
+	Seconds         float64            `json:"seconds"`
+	ProviderSeconds map[string]float64 `json:"provider_seconds,omitempty"`
+	// Errors counts the errors of the run by category: "config" (reading
+	// dnsconfig.js), "validation", "providers" (setting them up, usually a
+	// credentials problem), "dns_provider" and "registrar" (getting the
+	// corrections), "correction" (running them) and "other".
+	Errors map[string]int `json:"errors,omitempty"`
+}
+
+// Provider is a provider of dnsconfig.js.
+type Provider struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Registrar bool   `json:"registrar"`
+	Domains   int    `json:"domains"`
+}
+
+// New returns the report of a run of command with the command line args,
+// that rec recorded, took d and returned err.
+func New(version, command string, args []string, rec *report.Recorder, d time.Duration, err error) *Report {
+	r := &Report{
+		SchemaVersion:   SchemaVersion,
+		Version:         version,
+		GoVersion:       runtime.Version(),
+		OS:              runtime.GOOS,
+		Arch:            runtime.GOARCH,
+		Command:         command,
+		Flags:           flagNames(args),
+		Providers:       []*Provider{},
+		DomainsRun:      len(rec.Run.Domains),
+		Corrections:     rec.Run.Corrections(),
+		Seconds:         d.Seconds(),
+		ProviderSeconds: map[string]float64{},
+		Errors:          map[string]int{},
+	}
+	if cfg := rec.DNSConfig; cfg != nil {
+		r.Domains = len(cfg.Domains)
+		run := map[string]bool{}
+		for _, d := range rec.Run.Domains {
+			run[d.Name] = true
+		}
+		byName := map[string]*Provider{}
+		for _, p := range cfg.Registrars {
+			byName[p.Name] = &Provider{Name: p.Name, Type: p.Type, Registrar: true}
+			r.Providers = append(r.Providers, byName[p.Name])
+		}
+		for _, p := range cfg.DNSProviders {
+			byName[p.Name] = &Provider{Name: p.Name, Type: p.Type}
+			r.Providers = append(r.Providers, byName[p.Name])
+		}
+		for _, dc := range cfg.Domains {
+			if p := byName[dc.RegistrarName]; p != nil {
+				p.Domains++
+			}
+			for name := range dc.DNSProviderNames {
+				if p := byName[name]; p != nil {
+					p.Domains++
+				}
+			}
+			if run[dc.UniqueName()] {
+				r.Records += len(dc.Records)
+			}
+		}
+	}
+	for name, d := range rec.Durations {
+		r.ProviderSeconds[name] = d.Seconds()
+	}
+
+	for _, d := range rec.Run.Domains {
+		for _, p := range d.Providers {
+			if p.Error != "" {
+				if p.Registrar {
+					r.Errors["registrar"]++
+				} else {
+					r.Errors["dns_provider"]++
+				}
+			}
+			for _, c := range p.Corrections {
+				if c.Error != "" {
+					r.Errors["correction"]++
+				}
+			}
+		}
+	}
+	if err != nil && len(r.Errors) == 0 {
+		switch {
+		case rec.DNSConfig == nil:
+			r.Errors["config"]++
+		case !rec.Valid:
+			r.Errors["validation"]++
+		case len(rec.Run.Domains) == 0:
+			r.Errors["providers"]++
+		default:
+			r.Errors["other"]++
+		}
+	}
+	return r
+}
+
+// flagNames returns the names of the flags in args, a command line.
+func flagNames(args []string) []string {
+	names := []string{}
+	for _, a := range args {
+		if a == "--" {
+			break
+		}
+		if !strings.HasPrefix(a, "-") || a == "-" {
+			continue
+		}
+		name := strings.TrimLeft(a, "-")
+		if i := strings.Index(name, "="); i >= 0 {
+			name = name[:i]
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Write writes r to filename.
+func (r *Report) Write(filename string) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, append(b, '\n'), 0644)
+}
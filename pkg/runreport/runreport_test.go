@@ -0,0 +1,80 @@
+package runreport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/StackExchange/dnscontrol/pkg/report"
+	"github.com/pkg/errors"
+)
+
+func TestFlagNames(t *testing.T) {
+	got := flagNames([]string{"push", "--providers", "bind", "-v", "--creds=secret.json", "--", "--not-a-flag"})
+	if strings.Join(got, " ") != "creds providers v" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestNew(t *testing.T) {
+	cfg := &models.DNSConfig{
+		Registrars:   []*models.RegistrarConfig{{Name: "reg", Type: "NONE"}},
+		DNSProviders: []*models.DNSProviderConfig{{Name: "bind", Type: "BIND"}},
+		Domains: []*models.DomainConfig{
+			{Name: "example.com", RegistrarName: "reg", DNSProviderNames: map[string]int{"bind": -1}, Records: models.Records{{}, {}}},
+			{Name: "example.net", RegistrarName: "reg", DNSProviderNames: map[string]int{"bind": -1}, Records: models.Records{{}}},
+		},
+	}
+	rec := report.NewRecorder(printer.ConsolePrinter{Writer: ioutil.Discard}, true)
+	rec.Config(cfg, true)
+	rec.StartDomain("example.com")
+	rec.StartDNSProvider("bind", false)
+	rec.EndProvider(1, nil)
+	rec.PrintCorrection(0, &models.Correction{Msg: "CREATE A www 192.0.2.1"})
+	rec.EndCorrection(errors.Errorf("boom"))
+
+	r := New("3.0", "push", nil, rec, time.Second, errors.Errorf("Completed with errors"))
+	if r.Domains != 2 || r.DomainsRun != 1 || r.Records != 2 || r.Corrections != 1 || r.Seconds != 1 {
+		t.Errorf("unexpected counts %+v", r)
+	}
+	if len(r.Providers) != 2 || r.Providers[0].Domains != 2 || !r.Providers[0].Registrar || r.Providers[1].Type != "BIND" {
+		t.Errorf("unexpected providers %+v %+v", r.Providers[0], r.Providers[1])
+	}
+	if _, ok := r.ProviderSeconds["bind"]; !ok {
+		t.Errorf("expected the time spent in bind")
+	}
+	if len(r.Errors) != 1 || r.Errors["correction"] != 1 {
+		t.Errorf("unexpected errors %v", r.Errors)
+	}
+
+	r = New("3.0", "preview", nil, report.NewRecorder(printer.ConsolePrinter{Writer: ioutil.Discard}, false), time.Second, errors.Errorf("syntax error"))
+	if r.Errors["config"] != 1 {
+		t.Errorf("expected a config error, got %v", r.Errors)
+	}
+}
+
+// TestSchemaVersion makes sure the published schema is updated with SchemaVersion.
+func TestSchemaVersion(t *testing.T) {
+	b, err := ioutil.ReadFile(fmt.Sprintf("../../docs/schema/run-report-v%d.json", SchemaVersion))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema struct {
+		Properties struct {
+			SchemaVersion struct {
+				Const int
+			} `json:"schema_version"`
+		}
+	}
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if schema.Properties.SchemaVersion.Const != SchemaVersion {
+		t.Errorf("schema has version %d, expected %d", schema.Properties.SchemaVersion.Const, SchemaVersion)
+	}
+}
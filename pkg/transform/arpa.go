@@ -9,7 +9,15 @@ import (
 )
 
 // ReverseDomainName turns a CIDR block into a reversed (in-addr) name.
+// An address without a netmask is the block of just that address.
 func ReverseDomainName(cidr string) (string, error) {
+	if !strings.Contains(cidr, "/") {
+		if ip := net.ParseIP(cidr); ip != nil && ip.To4() != nil {
+			cidr += "/32"
+		} else {
+			cidr += "/128"
+		}
+	}
 	a, c, err := net.ParseCIDR(cidr)
 	if err != nil {
 		return "", err
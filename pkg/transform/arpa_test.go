@@ -71,6 +71,10 @@ func TestReverse(t *testing.T) {
 		{"174.1.0.0/31", false, "0/31.0.1.174.in-addr.arpa"},
 		{"174.1.0.2/31", false, "2/31.0.1.174.in-addr.arpa"},
 
+		// Addresses without a netmask:
+		{"174.136.107.14", false, "14.107.136.174.in-addr.arpa"},
+		{"2001:db8::1", false, "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa"},
+
 		// Error Cases:
 		{"0.0.0.0/0", true, ""},
 		{"2001::/0", true, ""},
@@ -0,0 +1,39 @@
+package transform
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+)
+
+// DANEAssociation returns the certificate association data of a TLSA (RFC
+// 6698) or SMIMEA record for the first certificate or public key in
+// pemData. A public key can only be used with selector 1
+// (SubjectPublicKeyInfo).
+func DANEAssociation(pemData []byte, selector, matchingType uint8) (string, error) {
+	for {
+		var block *pem.Block
+		block, pemData = pem.Decode(pemData)
+		if block == nil {
+			return "", errors.Errorf("no certificate or public key found")
+		}
+		switch block.Type {
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return "", err
+			}
+			return dns.CertificateToDANE(selector, matchingType, cert)
+		case "PUBLIC KEY":
+			if selector != 1 {
+				return "", errors.Errorf("a public key can only be used with selector 1")
+			}
+			if _, err := x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+				return "", err
+			}
+			return dns.CertificateToDANE(selector, matchingType, &x509.Certificate{RawSubjectPublicKeyInfo: block.Bytes})
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package transform
+
+import "testing"
+
+const (
+	testCert = `-----BEGIN CERTIFICATE-----
+MIIBijCCATGgAwIBAgIUJcRir3+3sAGvCVrshLDDWy+WlogwCgYIKoZIzj0EAwIw
+GjEYMBYGA1UEAwwPd3d3LmV4YW1wbGUuY29tMCAXDTI2MTAxNjEzMTI0N1oYDzIx
+MjYwOTIyMTMxMjQ3WjAaMRgwFgYDVQQDDA93d3cuZXhhbXBsZS5jb20wWTATBgcq
+hkjOPQIBBggqhkjOPQMBBwNCAAS15d4ZfsekMYIVmzrp4Tr/+PCMmi6eSGOuUQO5
+0zFN6WsVrNMdqofHUrSeaOCm8kCzlh+JpWqDGZln2QvfHJ9Ro1MwUTAdBgNVHQ4E
+FgQUHswhBxhp4/bTFx0lHDuDBwO3yKkwHwYDVR0jBBgwFoAUHswhBxhp4/bTFx0l
+HDuDBwO3yKkwDwYDVR0TAQH/BAUwAwEB/zAKBggqhkjOPQQDAgNHADBEAiA8FRvK
+8S5UWBZJRVaVEX3yQN53tk0cRfYfxnDj+wJrawIgJa9YAo1ulkeWWwMUBRCuizzP
+GqyXNyfNS9OuGSanPXY=
+-----END CERTIFICATE-----
+`
+	testPublicKey = `-----BEGIN PUBLIC KEY-----
+MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEteXeGX7HpDGCFZs66eE6//jwjJou
+nkhjrlEDudMxTelrFazTHaqHx1K0nmjgpvJAs5YfiaVqgxmZZ9kL3xyfUQ==
+-----END PUBLIC KEY-----
+`
+	certSHA256 = "6df2cbddc1e7c697b85b11fe39c3b6d41fbb8fb85b87e0361da4a4a7a4a28634"
+	spkiSHA256 = "9a4561c46054f982b35a98d3f04862d9e49681fb753d2822fe1960345d342147"
+)
+
+func TestDANEAssociation(t *testing.T) {
+	tests := []struct {
+		pem                    string
+		selector, matchingType uint8
+		expected               string
+	}{
+		{testCert, 0, 1, certSHA256},
+		{testCert, 1, 1, spkiSHA256},
+		{testPublicKey, 1, 1, spkiSHA256},
+		{"junk\n" + testPublicKey + testCert, 1, 1, spkiSHA256},
+		{testPublicKey, 0, 1, ""},
+		{testCert, 1, 3, ""},
+		{"junk", 1, 1, ""},
+	}
+	for i, tst := range tests {
+		got, err := DANEAssociation([]byte(tst.pem), tst.selector, tst.matchingType)
+		if tst.expected == "" {
+			if err == nil {
+				t.Errorf("%d: expected an error, got %s", i, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%d: %s", i, err)
+		} else if got != tst.expected {
+			t.Errorf("%d: expected %s, got %s", i, tst.expected, got)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package transform
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ed25519"
+)
+
+// dkimMinRSABits is the smallest RSA key that verifiers accept (RFC 8301).
+const dkimMinRSABits = 1024
+
+var reQuoted = regexp.MustCompile(`"([^"]*)"`)
+
+// oidEd25519 is the algorithm of ed25519 public keys (RFC 8410).
+var oidEd25519 = asn1.ObjectIdentifier{1, 3, 101, 112}
+
+// DKIMPublicKey returns the key type ("rsa" or "ed25519") and the base64
+// key of the p= tag of the DKIM (RFC 6376) record of the public key in
+// data. data is a PEM public key, a DKIM record such as "v=DKIM1; k=rsa;
+// p=MIIB...", which may be split in quoted strings like the zone file
+// lines that opendkim-genkey writes, or the base64 of the key alone.
+func DKIMPublicKey(data string) (keyType, key string, err error) {
+	if block, _ := pem.Decode([]byte(strings.TrimSpace(data))); block != nil {
+		return dkimPEMKey(block)
+	}
+
+	if quoted := reQuoted.FindAllStringSubmatch(data, -1); quoted != nil {
+		data = ""
+		for _, q := range quoted {
+			data += q[1]
+		}
+	}
+	keyType = "rsa"
+	if strings.Contains(data, "p=") {
+		for _, tag := range strings.Split(data, ";") {
+			tag = strings.TrimSpace(tag)
+			switch {
+			case strings.HasPrefix(tag, "k="):
+				keyType = strings.TrimSpace(tag[2:])
+			case strings.HasPrefix(tag, "p="):
+				key = tag[2:]
+			}
+		}
+	} else {
+		key = data
+	}
+	key = strings.Join(strings.Fields(key), "")
+	if key == "" {
+		return "", "", errors.Errorf("no public key found")
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return "", "", errors.Errorf("the key is not base64; it may have been mangled when it was copied")
+	}
+	switch keyType {
+	case "rsa":
+		pub, err := x509.ParsePKIXPublicKey(raw)
+		if err != nil {
+			return "", "", errors.Wrap(err, "the key is not an RSA public key; it may have been mangled when it was copied")
+		}
+		if err := checkDKIMRSAKey(pub); err != nil {
+			return "", "", err
+		}
+	case "ed25519":
+		if len(raw) != ed25519.PublicKeySize {
+			return "", "", errors.Errorf("an ed25519 key is %d bytes, not %d", ed25519.PublicKeySize, len(raw))
+		}
+	default:
+		return "", "", errors.Errorf("key type k=%s is not rsa or ed25519", keyType)
+	}
+	return keyType, key, nil
+}
+
+// dkimPEMKey returns the key type and p= of the public key of block.
+func dkimPEMKey(block *pem.Block) (keyType, key string, err error) {
+	var pub interface{}
+	switch {
+	case strings.HasSuffix(block.Type, "PRIVATE KEY"):
+		return "", "", errors.Errorf("this is a private key; DKIM publishes the public key, and the private key should stay on the mail server")
+	case block.Type == "PUBLIC KEY":
+		if pub, ok := parseEd25519PublicKey(block.Bytes); ok {
+			return "ed25519", base64.StdEncoding.EncodeToString(pub), nil
+		}
+		pub, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case block.Type == "RSA PUBLIC KEY":
+		pub, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return "", "", errors.Errorf("a %s is not a public key", block.Type)
+	}
+	if err != nil {
+		return "", "", err
+	}
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		if err := checkDKIMRSAKey(pub); err != nil {
+			return "", "", err
+		}
+		der, err := x509.MarshalPKIXPublicKey(pub)
+		if err != nil {
+			return "", "", err
+		}
+		return "rsa", base64.StdEncoding.EncodeToString(der), nil
+	}
+	return "", "", errors.Errorf("the key is not an RSA or ed25519 key")
+}
+
+// parseEd25519PublicKey returns the key of der, if it is the
+// SubjectPublicKeyInfo of an ed25519 key. x509 only parses those from Go
+// 1.13 on.
+func parseEd25519PublicKey(der []byte) (ed25519.PublicKey, bool) {
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	rest, err := asn1.Unmarshal(der, &spki)
+	if err != nil || len(rest) != 0 || !spki.Algorithm.Algorithm.Equal(oidEd25519) {
+		return nil, false
+	}
+	key := spki.PublicKey.RightAlign()
+	if len(key) != ed25519.PublicKeySize {
+		return nil, false
+	}
+	return ed25519.PublicKey(key), true
+}
+
+func checkDKIMRSAKey(pub interface{}) error {
+	rsaPub, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return errors.Errorf("the key is not an RSA key; use k=ed25519 for ed25519 keys")
+	}
+	if bits := rsaPub.N.BitLen(); bits < dkimMinRSABits {
+		return errors.Errorf("the RSA key has %d bits; verifiers ignore keys of less than %d", bits, dkimMinRSABits)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package transform
+
+import (
+	"encoding/hex"
+	"math/big"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// maxCIDRHosts is the most addresses that CIDRHosts returns, so that a
+// mistyped netmask doesn't make millions of records.
+const maxCIDRHosts = 65536
+
+// parseIP returns ip in 4 bytes if it is an IPv4 address, else in 16.
+func parseIP(s string) (net.IP, error) {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, errors.Errorf("%q is not an IP address", s)
+	}
+	if ip4 := ip.To4(); ip4 != nil && !strings.Contains(s, ":") {
+		return ip4, nil
+	}
+	return ip.To16(), nil
+}
+
+// ipFromInt returns the address of the number n, in as many bytes as size,
+// or false if it doesn't fit.
+func ipFromInt(n *big.Int, size int) (net.IP, bool) {
+	if n.Sign() < 0 || n.BitLen() > 8*size {
+		return nil, false
+	}
+	ip := make(net.IP, size)
+	b := n.Bytes()
+	copy(ip[size-len(b):], b)
+	return ip, true
+}
+
+// IPAdd returns the address n after ip, or before it if n is negative.
+func IPAdd(ip string, n int64) (string, error) {
+	a, err := parseIP(ip)
+	if err != nil {
+		return "", err
+	}
+	sum := new(big.Int).Add(new(big.Int).SetBytes(a), big.NewInt(n))
+	b, ok := ipFromInt(sum, len(a))
+	if !ok {
+		return "", errors.Errorf("%s %+d is beyond the last or first address", ip, n)
+	}
+	return b.String(), nil
+}
+
+// CIDRHosts returns the addresses of the hosts of the block cidr, in
+// order. Those of an IPv4 block don't include its network and broadcast
+// addresses, except in a /31 or /32, which have none (RFC 3021).
+func CIDRHosts(cidr string) ([]string, error) {
+	_, block, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+	ones, bits := block.Mask.Size()
+	if bits-ones > 16 {
+		return nil, errors.Errorf("%s has more than %d addresses", cidr, maxCIDRHosts)
+	}
+	first := new(big.Int).SetBytes(block.IP)
+	count := int64(1) << uint(bits-ones)
+	if bits == 32 && count > 2 {
+		first.Add(first, big.NewInt(1))
+		count -= 2
+	}
+	hosts := make([]string, 0, count)
+	one := big.NewInt(1)
+	for n := first; int64(len(hosts)) < count; n.Add(n, one) {
+		ip, _ := ipFromInt(n, len(block.IP))
+		hosts = append(hosts, ip.String())
+	}
+	return hosts, nil
+}
+
+// IPv6Expand returns the IPv6 address ip with all of its 32 hex digits,
+// like "2001:0db8:0000:0000:0000:0000:0000:0001".
+func IPv6Expand(ip string) (string, error) {
+	a := net.ParseIP(ip)
+	if a == nil || !strings.Contains(ip, ":") {
+		return "", errors.Errorf("%q is not an IPv6 address", ip)
+	}
+	h := hex.EncodeToString(a.To16())
+	groups := make([]string, 8)
+	for i := range groups {
+		groups[i] = h[4*i : 4*i+4]
+	}
+	return strings.Join(groups, ":"), nil
+}
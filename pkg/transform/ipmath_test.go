@@ -0,0 +1,86 @@
+package transform
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIPAdd(t *testing.T) {
+	tests := []struct {
+		ip       string
+		n        int64
+		expected string
+	}{
+		{"192.0.2.1", 1, "192.0.2.2"},
+		{"192.0.2.255", 1, "192.0.3.0"},
+		{"192.0.2.10", -10, "192.0.2.0"},
+		{"255.255.255.255", 1, ""},
+		{"0.0.0.0", -1, ""},
+		{"2001:db8::1", 255, "2001:db8::100"},
+		{"2001:db8::ffff", 1, "2001:db8::1:0"},
+		{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", 1, ""},
+		{"192.0.2", 1, ""},
+	}
+	for _, tst := range tests {
+		actual, err := IPAdd(tst.ip, tst.n)
+		if tst.expected == "" {
+			if err == nil {
+				t.Errorf("IPAdd(%s, %d): expected an error, got %s", tst.ip, tst.n, actual)
+			}
+		} else if err != nil || actual != tst.expected {
+			t.Errorf("IPAdd(%s, %d): expected %s, got %s (%v)", tst.ip, tst.n, tst.expected, actual, err)
+		}
+	}
+}
+
+func TestCIDRHosts(t *testing.T) {
+	tests := []struct {
+		cidr     string
+		expected string
+	}{
+		{"192.0.2.0/30", "192.0.2.1 192.0.2.2"},
+		{"192.0.2.5/30", "192.0.2.5 192.0.2.6"},
+		{"192.0.2.4/31", "192.0.2.4 192.0.2.5"},
+		{"192.0.2.4/32", "192.0.2.4"},
+		{"2001:db8::/126", "2001:db8:: 2001:db8::1 2001:db8::2 2001:db8::3"},
+		{"10.0.0.0/8", ""},
+		{"2001:db8::/64", ""},
+		{"192.0.2.0", ""},
+	}
+	for _, tst := range tests {
+		hosts, err := CIDRHosts(tst.cidr)
+		if tst.expected == "" {
+			if err == nil {
+				t.Errorf("CIDRHosts(%s): expected an error, got %d hosts", tst.cidr, len(hosts))
+			}
+		} else if actual := strings.Join(hosts, " "); err != nil || actual != tst.expected {
+			t.Errorf("CIDRHosts(%s): expected %s, got %s (%v)", tst.cidr, tst.expected, actual, err)
+		}
+	}
+	if hosts, err := CIDRHosts("10.0.0.0/16"); err != nil || len(hosts) != 65534 || hosts[65533] != "10.0.255.254" {
+		t.Errorf("CIDRHosts(10.0.0.0/16): got %d hosts (%v)", len(hosts), err)
+	}
+}
+
+func TestIPv6Expand(t *testing.T) {
+	tests := []struct {
+		ip       string
+		expected string
+	}{
+		{"2001:db8::1", "2001:0db8:0000:0000:0000:0000:0000:0001"},
+		{"::", "0000:0000:0000:0000:0000:0000:0000:0000"},
+		{"::ffff:192.0.2.1", "0000:0000:0000:0000:0000:ffff:c000:0201"},
+		{"192.0.2.1", ""},
+		{"2001:db8::g", ""},
+	}
+	for _, tst := range tests {
+		actual, err := IPv6Expand(tst.ip)
+		if tst.expected == "" {
+			if err == nil {
+				t.Errorf("IPv6Expand(%s): expected an error, got %s", tst.ip, actual)
+			}
+		} else if err != nil || actual != tst.expected {
+			t.Errorf("IPv6Expand(%s): expected %s, got %s (%v)", tst.ip, tst.expected, actual, err)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package transform
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// LocalPartOwnerName returns the owner name used by per-mailbox records
+// such as SMIMEA (RFC 8162) and OPENPGPKEY (RFC 7929): the SHA2-256 hash of
+// the local part of the address, truncated to 28 octets and hex encoded,
+// followed by the given suffix label (e.g. "_smimecert").
+// If address is a full email address, the domain part is ignored.
+// The local part is hashed exactly as given; no case folding is done.
+func LocalPartOwnerName(address, suffix string) string {
+	local := address
+	if i := strings.LastIndex(address, "@"); i >= 0 {
+		local = address[:i]
+	}
+	sum := sha256.Sum256([]byte(local))
+	return hex.EncodeToString(sum[:28]) + "." + suffix
+}
@@ -0,0 +1,20 @@
+package transform
+
+import "testing"
+
+func TestLocalPartOwnerName(t *testing.T) {
+	// Example from RFC 7929 section 3 (hugh@example.com).
+	const hugh = "c93f1e400f26708f98cb19d936620da35eec8f72e57f9eec01c1afd6._openpgpkey"
+	tests := []struct {
+		address, suffix, expected string
+	}{
+		{"hugh", "_openpgpkey", hugh},
+		{"hugh@example.com", "_openpgpkey", hugh},
+		{"hugh@example.com", "_smimecert", "c93f1e400f26708f98cb19d936620da35eec8f72e57f9eec01c1afd6._smimecert"},
+	}
+	for _, tst := range tests {
+		if got := LocalPartOwnerName(tst.address, tst.suffix); got != tst.expected {
+			t.Errorf("%s: expected %s, got %s", tst.address, tst.expected, got)
+		}
+	}
+}
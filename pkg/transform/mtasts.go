@@ -0,0 +1,46 @@
+package transform
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+)
+
+// mtaSTSMaxAge is the longest max_age of an MTA-STS policy, a year.
+const mtaSTSMaxAge = 31557600
+
+// MTASTSPolicy returns the MTA-STS policy (RFC 8461) of a domain, which
+// must be served at https://mta-sts.<domain>/.well-known/mta-sts.txt, and
+// the id of its _mta-sts TXT record. The id is a hash of the policy, so
+// it changes when the policy does. mode is "enforce", "testing" or
+// "none", and mx the MX host names, or patterns such as "*.example.net".
+func MTASTSPolicy(mode string, mx []string, maxAge uint32) (policy, id string, err error) {
+	switch mode {
+	case "enforce", "testing":
+		if len(mx) == 0 {
+			return "", "", errors.Errorf("mode %s needs MX host names", mode)
+		}
+	case "none":
+	default:
+		return "", "", errors.Errorf("mode must be enforce, testing or none, not %q", mode)
+	}
+	if maxAge > mtaSTSMaxAge {
+		return "", "", errors.Errorf("max_age %d is more than a year (%d)", maxAge, mtaSTSMaxAge)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "version: STSv1\nmode: %s\n", mode)
+	for _, m := range mx {
+		m = strings.ToLower(strings.TrimSuffix(m, "."))
+		if _, ok := dns.IsDomainName(strings.TrimPrefix(m, "*.")); !ok || !strings.Contains(m, ".") || strings.Contains(strings.TrimPrefix(m, "*."), "*") {
+			return "", "", errors.Errorf("mx %q is not a host name or a pattern such as *.example.com", m)
+		}
+		fmt.Fprintf(&b, "mx: %s\n", m)
+	}
+	fmt.Fprintf(&b, "max_age: %d\n", maxAge)
+	sum := sha256.Sum256([]byte(b.String()))
+	return b.String(), hex.EncodeToString(sum[:10]), nil
+}
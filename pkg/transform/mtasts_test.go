@@ -0,0 +1,40 @@
+package transform
+
+import "testing"
+
+func TestMTASTSPolicy(t *testing.T) {
+	policy, id, err := MTASTSPolicy("enforce", []string{"mail.example.com.", "*.Example.net"}, 604800)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "version: STSv1\nmode: enforce\nmx: mail.example.com\nmx: *.example.net\nmax_age: 604800\n"
+	if policy != want {
+		t.Errorf("policy is %q, want %q", policy, want)
+	}
+	if len(id) != 20 {
+		t.Errorf("id %q is not 20 characters long", id)
+	}
+	_, id2, _ := MTASTSPolicy("testing", []string{"mail.example.com.", "*.Example.net"}, 604800)
+	if id2 == id {
+		t.Errorf("the id %s didn't change with the mode", id)
+	}
+
+	for _, tst := range []struct {
+		mode   string
+		mx     []string
+		maxAge uint32
+	}{
+		{"enforced", []string{"mail.example.com"}, 86400},
+		{"enforce", nil, 86400},
+		{"enforce", []string{"mail"}, 86400},
+		{"enforce", []string{"mail.*.example.com"}, 86400},
+		{"enforce", []string{"mail.example.com"}, 40000000},
+	} {
+		if _, _, err := MTASTSPolicy(tst.mode, tst.mx, tst.maxAge); err == nil {
+			t.Errorf("%s %v %d: expected an error", tst.mode, tst.mx, tst.maxAge)
+		}
+	}
+	if _, _, err := MTASTSPolicy("none", nil, 86400); err != nil {
+		t.Errorf("mode none: %s", err)
+	}
+}
@@ -0,0 +1,141 @@
+package transform
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// sshfpAlgorithms maps OpenSSH key types to SSHFP algorithm numbers
+// (RFC 4255, RFC 6594, RFC 7479 and RFC 8709).
+var sshfpAlgorithms = map[string]uint8{
+	"ssh-rsa":             1,
+	"ssh-dss":             2,
+	"ecdsa-sha2-nistp256": 3,
+	"ecdsa-sha2-nistp384": 3,
+	"ecdsa-sha2-nistp521": 3,
+	"ssh-ed25519":         4,
+	"ssh-ed448":           6,
+}
+
+// SSHFingerprint is the data of an SSHFP record.
+type SSHFingerprint struct {
+	Algorithm   uint8  `json:"algorithm"`
+	Type        uint8  `json:"type"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// SSHFingerprints returns the SSHFP data of the keys in data, which is in
+// the format of OpenSSH public key files (one key per line) or of
+// known_hosts files. If host is not empty, only the known_hosts entries of
+// host are used; hashed host names are supported. fpType is 1 for SHA-1
+// or 2 for SHA-256.
+func SSHFingerprints(data []byte, host string, fpType uint8) ([]SSHFingerprint, error) {
+	if fpType != 1 && fpType != 2 {
+		return nil, errors.Errorf("SSHFP fingerprint type %d is not 1 (SHA-1) or 2 (SHA-256)", fpType)
+	}
+	var fps []SSHFingerprint
+	seen := map[string]bool{}
+	s := bufio.NewScanner(bytes.NewReader(data))
+	s.Buffer(nil, 1<<20)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], "@") {
+			// Comments, and @cert-authority and @revoked known_hosts entries.
+			continue
+		}
+		// The key type is the first field of a public key file, and
+		// the second of a known_hosts entry.
+		i := 0
+		if _, ok := sshfpAlgorithms[fields[0]]; !ok {
+			if host != "" && !knownHostMatches(fields[0], host) {
+				continue
+			}
+			i = 1
+		} else if host != "" {
+			continue
+		}
+		if len(fields) < i+2 {
+			continue
+		}
+		alg, ok := sshfpAlgorithms[fields[i]]
+		if !ok {
+			continue
+		}
+		blob, err := base64.StdEncoding.DecodeString(fields[i+1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s key", fields[i])
+		}
+		if keyType, ok := sshString(blob); !ok || keyType != fields[i] {
+			return nil, errors.Errorf("%s key contains a %q key", fields[i], keyType)
+		}
+		var fp string
+		if fpType == 1 {
+			sum := sha1.Sum(blob)
+			fp = hex.EncodeToString(sum[:])
+		} else {
+			sum := sha256.Sum256(blob)
+			fp = hex.EncodeToString(sum[:])
+		}
+		if !seen[fp] {
+			seen[fp] = true
+			fps = append(fps, SSHFingerprint{Algorithm: alg, Type: fpType, Fingerprint: fp})
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if len(fps) == 0 {
+		if host != "" {
+			return nil, errors.Errorf("no SSH keys found for %s", host)
+		}
+		return nil, errors.Errorf("no SSH keys found")
+	}
+	return fps, nil
+}
+
+// sshString returns the first string of an SSH wire format blob.
+func sshString(blob []byte) (string, bool) {
+	if len(blob) < 4 {
+		return "", false
+	}
+	n := binary.BigEndian.Uint32(blob)
+	if uint64(len(blob)-4) < uint64(n) {
+		return "", false
+	}
+	return string(blob[4 : 4+n]), true
+}
+
+// knownHostMatches reports whether the host names field of a known_hosts
+// entry lists host, either as plain text or hashed (|1|salt|hash).
+func knownHostMatches(names, host string) bool {
+	for _, name := range strings.Split(names, ",") {
+		if strings.HasPrefix(name, "|1|") {
+			part := strings.Split(name, "|")
+			if len(part) != 4 {
+				continue
+			}
+			salt, err1 := base64.StdEncoding.DecodeString(part[2])
+			hash, err2 := base64.StdEncoding.DecodeString(part[3])
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			mac := hmac.New(sha1.New, salt)
+			mac.Write([]byte(host))
+			if hmac.Equal(mac.Sum(nil), hash) {
+				return true
+			}
+		} else if strings.EqualFold(name, host) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+)
+
+const (
+	testEd25519 = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIIaJzMYZGlRLeM5oN92RVS6u+F36tfThGvTny8v8ZUFu root@host\n"
+	testECDSA   = "ecdsa-sha2-nistp256 AAAAE2VjZHNhLXNoYTItbmlzdHAyNTYAAAAIbmlzdHAyNTYAAABBBBkcsVBceobGht/4r/ai1jEvWtro2t7+Gr6ZRz5GWKA95Pa6yMb3pmWCHrPjq7aVFhC0Yp25LNbUwFy5E02HZrA=\n"
+	// testKnownHosts lists host.example.com and 192.0.2.1 (hashed) with the
+	// ed25519 key, and other.example.com with the ECDSA key.
+	testKnownHosts = `# comment
+|1|Qd0GkRF+WGQAV02iOReLfFVmmJE=|IMGzDNjVLCHHJkmdEcx0ASmTsO8= ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIIaJzMYZGlRLeM5oN92RVS6u+F36tfThGvTny8v8ZUFu
+|1|9qamUAhUYqljQiGhoUdrvW+H7pk=|O5uRzHWhCjlkDEovomWFy+asNTw= ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIIaJzMYZGlRLeM5oN92RVS6u+F36tfThGvTny8v8ZUFu
+other.example.com ecdsa-sha2-nistp256 AAAAE2VjZHNhLXNoYTItbmlzdHAyNTYAAAAIbmlzdHAyNTYAAABBBBkcsVBceobGht/4r/ai1jEvWtro2t7+Gr6ZRz5GWKA95Pa6yMb3pmWCHrPjq7aVFhC0Yp25LNbUwFy5E02HZrA=
+@revoked * ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQ
+`
+	ed25519SHA1   = "1cc0f1b0146bde6452b9cd846926e394f2984ae6"
+	ed25519SHA256 = "ccfaa12225a7a79e332221714988fea106accd5e1cd2eca2979484fa6e4063f4"
+	ecdsaSHA256   = "c6918d990f01ff3e9bc4ae513f853f1198bf6c43ab3f32426d7f10d23a3bfe36"
+)
+
+func TestSSHFingerprints(t *testing.T) {
+	tests := []struct {
+		data     string
+		host     string
+		fpType   uint8
+		expected []SSHFingerprint
+	}{
+		{testEd25519, "", 1, []SSHFingerprint{{4, 1, ed25519SHA1}}},
+		{testEd25519 + testECDSA, "", 2, []SSHFingerprint{{4, 2, ed25519SHA256}, {3, 2, ecdsaSHA256}}},
+		{testKnownHosts, "host.example.com", 2, []SSHFingerprint{{4, 2, ed25519SHA256}}},
+		{testKnownHosts, "OTHER.example.com", 2, []SSHFingerprint{{3, 2, ecdsaSHA256}}},
+		{testKnownHosts, "", 2, []SSHFingerprint{{4, 2, ed25519SHA256}, {3, 2, ecdsaSHA256}}},
+		{testKnownHosts, "unknown.example.com", 2, nil},
+		{testEd25519, "", 3, nil},
+		{"ssh-rsa AAAAC3NzaC1lZDI1NTE5AAAAIIaJzMYZGlRLeM5oN92RVS6u+F36tfThGvTny8v8ZUFu", "", 2, nil},
+	}
+	for i, tst := range tests {
+		got, err := SSHFingerprints([]byte(tst.data), tst.host, tst.fpType)
+		if tst.expected == nil {
+			if err == nil {
+				t.Errorf("%d: expected an error, got %v", i, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%d: %s", i, err)
+		} else if !reflect.DeepEqual(got, tst.expected) {
+			t.Errorf("%d: expected %v, got %v", i, tst.expected, got)
+		}
+	}
+}
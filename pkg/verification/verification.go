@@ -0,0 +1,127 @@
+// Package verification tracks the TXT records that prove control of a
+// domain to SaaS services.
+//
+// dnsconfig.js declares them with PENDING_VERIFICATION(service, token).
+// Once the service has verified the domain, `dnscontrol verify-complete`
+// writes a completion file for it. After a grace period (services may
+// check again shortly after the first verification) push removes the
+// record, so zones don't accumulate dead verification tokens.
+//
+// Like the freeze markers, the completion files are plain JSON files
+// meant to be committed alongside dnsconfig.js.
+package verification
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/pkg/errors"
+)
+
+// DefaultDir is the directory the completion files are kept in, unless told otherwise.
+const DefaultDir = ".dnscontrol-verification"
+
+// DefaultGrace is how long a verification record is kept once completed, unless told otherwise.
+const DefaultGrace = 7 * 24 * time.Hour
+
+var serviceRe = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// CheckService returns an error if service can't be used as a service name.
+func CheckService(service string) error {
+	if !serviceRe.MatchString(service) {
+		return errors.Errorf("verification service %q must be lowercase letters, digits, '.', '_' and '-'", service)
+	}
+	return nil
+}
+
+// Completion records that service verified domain.
+type Completion struct {
+	Domain      string    `json:"domain"`
+	Service     string    `json:"service"`
+	CompletedAt time.Time `json:"completed_at"`
+	RemoveAfter time.Time `json:"remove_after"`
+}
+
+func completionPath(dir, domain, service string) string {
+	return filepath.Join(dir, strings.ToLower(domain), service+".json")
+}
+
+// Complete writes the completion file of c.Domain and c.Service,
+// replacing any existing one.
+func Complete(dir string, c *Completion) error {
+	if c.Domain == "" {
+		return errors.Errorf("no domain to complete the verification of")
+	}
+	if err := CheckService(c.Service); err != nil {
+		return err
+	}
+	if c.CompletedAt.IsZero() {
+		c.CompletedAt = time.Now().UTC()
+	}
+	if c.RemoveAfter.IsZero() {
+		c.RemoveAfter = c.CompletedAt
+	}
+	p := completionPath(dir, c.Domain, c.Service)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(p, append(b, '\n'), 0644)
+}
+
+// Get returns the completion of service for domain, or nil if the
+// verification is still pending.
+func Get(dir, domain, service string) (*Completion, error) {
+	b, err := ioutil.ReadFile(completionPath(dir, domain, service))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c := &Completion{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, errors.Wrapf(err, "invalid verification file for %s %s", domain, service)
+	}
+	return c, nil
+}
+
+// Prune removes the verification records of dc whose grace period is
+// over at now. It returns a message for each completed verification.
+func Prune(dir string, dc *models.DomainConfig, now time.Time) (msgs []string, err error) {
+	records := models.Records{}
+	for _, rec := range dc.Records {
+		service, ok := rec.Metadata["verification"]
+		if !ok {
+			records = append(records, rec)
+			continue
+		}
+		c, err := Get(dir, dc.Name, service)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case c == nil:
+			records = append(records, rec)
+		case now.Before(c.RemoveAfter):
+			msgs = append(msgs, fmt.Sprintf("%s verified %s on %s; its TXT record %s will be removed after %s",
+				service, dc.Name, c.CompletedAt.Format(time.RFC3339), rec.GetLabelFQDN(), c.RemoveAfter.Format(time.RFC3339)))
+			records = append(records, rec)
+		default:
+			msgs = append(msgs, fmt.Sprintf("%s verified %s on %s; removing its TXT record %s. Delete PENDING_VERIFICATION(%q, ...) from dnsconfig.js",
+				service, dc.Name, c.CompletedAt.Format(time.RFC3339), rec.GetLabelFQDN(), service))
+		}
+	}
+	dc.Records = records
+	return msgs, nil
+}
@@ -0,0 +1,61 @@
+package verification
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/models"
+)
+
+func TestPrune(t *testing.T) {
+	dir, err := ioutil.TempDir("", "verification")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	done := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := Complete(dir, &Completion{Domain: "Example.com", Service: "google", CompletedAt: done, RemoveAfter: done.Add(DefaultGrace)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Complete(dir, &Completion{Domain: "example.com", Service: "Bad Name"}); err == nil {
+		t.Errorf("expected an error for an invalid service name")
+	}
+
+	domain := func() *models.DomainConfig {
+		dc := &models.DomainConfig{Name: "example.com"}
+		for _, service := range []string{"google", "github", ""} {
+			rc := &models.RecordConfig{Type: "TXT", Metadata: map[string]string{}}
+			rc.SetLabel("@", dc.Name)
+			rc.SetTargetTXT(service + "-token")
+			if service != "" {
+				rc.Metadata["verification"] = service
+			}
+			dc.Records = append(dc.Records, rc)
+		}
+		return dc
+	}
+
+	// During the grace period, the record is kept.
+	dc := domain()
+	msgs, err := Prune(dir, dc, done.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dc.Records) != 3 || len(msgs) != 1 || !strings.Contains(msgs[0], "will be removed after 2026-01-08") {
+		t.Errorf("unexpected result %d %q", len(dc.Records), msgs)
+	}
+
+	// After it, it is removed. github is still pending.
+	dc = domain()
+	msgs, err = Prune(dir, dc, done.Add(DefaultGrace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dc.Records) != 2 || dc.Records[0].GetTargetField() != "github-token" || len(msgs) != 1 || !strings.Contains(msgs[0], "removing") {
+		t.Errorf("unexpected result %d %q", len(dc.Records), msgs)
+	}
+}
@@ -0,0 +1,225 @@
+// Package yamlconfig reads dnsconfig.yaml, a configuration without code,
+// for those who can't allow a configuration to run javascript. It makes
+// the same models.DNSConfig as dnsconfig.js. The records of the domains
+// are written like in the zone files of octoDNS:
+//
+//	registrars:
+//	  none:
+//	    type: NONE
+//	dns_providers:
+//	  bind:
+//	    type: BIND
+//	domains:
+//	  example.com:
+//	    registrar: none
+//	    dns_providers: [bind]
+//	    records:
+//	      '':
+//	        type: A
+//	        value: 1.2.3.4
+//	      www:
+//	        type: CNAME
+//	        value: example.com.
+package yamlconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/providers/octodns/octoyaml"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+type config struct {
+	Registrars   map[string]*provider `yaml:"registrars"`
+	DNSProviders map[string]*provider `yaml:"dns_providers"`
+	Domains      map[string]*domain   `yaml:"domains"`
+}
+
+type provider struct {
+	Type string                 `yaml:"type"`
+	Meta map[string]interface{} `yaml:"meta"`
+}
+
+type domain struct {
+	Registrar    string                 `yaml:"registrar"`
+	DNSProviders providerNames          `yaml:"dns_providers"`
+	DefaultTTL   uint32                 `yaml:"default_ttl"`
+	Meta         map[string]string      `yaml:"meta"`
+	NoPurge      bool                   `yaml:"no_purge"`
+	Records      map[string]interface{} `yaml:"records"`   // In the format of octoDNS.
+	ZoneFile     string                 `yaml:"zone_file"` // An octoDNS zone file with more records.
+}
+
+// providerNames are the DNS providers of a domain, with the number of
+// their nameservers to use. They are either a list of names, which use
+// all the nameservers, or a map of names to numbers, like DnsProvider().
+type providerNames map[string]int
+
+func (p *providerNames) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var names []string
+	if err := unmarshal(&names); err == nil {
+		*p = providerNames{}
+		for _, n := range names {
+			(*p)[n] = -1
+		}
+		return nil
+	}
+	var counts map[string]int
+	if err := unmarshal(&counts); err != nil {
+		return errors.Errorf("dns_providers must be a list of names, or a map of names to numbers of nameservers")
+	}
+	*p = counts
+	return nil
+}
+
+// Load reads the configuration of file.
+func Load(file string) (*models.DNSConfig, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var c config
+	if err := yaml.UnmarshalStrict(data, &c); err != nil {
+		return nil, errors.Wrapf(err, "reading %s", file)
+	}
+
+	cfg := &models.DNSConfig{
+		Registrars:   []*models.RegistrarConfig{},
+		DNSProviders: []*models.DNSProviderConfig{},
+		Domains:      []*models.DomainConfig{},
+	}
+	for _, name := range sortedKeys(c.Registrars) {
+		meta, err := providerMeta(c.Registrars[name])
+		if err != nil {
+			return nil, errors.Wrapf(err, "registrar %s", name)
+		}
+		cfg.Registrars = append(cfg.Registrars, &models.RegistrarConfig{Name: name, Type: c.Registrars[name].Type, Metadata: meta})
+	}
+	for _, name := range sortedKeys(c.DNSProviders) {
+		meta, err := providerMeta(c.DNSProviders[name])
+		if err != nil {
+			return nil, errors.Wrapf(err, "dns provider %s", name)
+		}
+		cfg.DNSProviders = append(cfg.DNSProviders, &models.DNSProviderConfig{Name: name, Type: c.DNSProviders[name].Type, Metadata: meta})
+	}
+
+	names := make([]string, 0, len(c.Domains))
+	for name := range c.Domains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		dc, err := domainConfig(name, c.Domains[name], filepath.Dir(file))
+		if err != nil {
+			return nil, errors.Wrapf(err, "domain %s", name)
+		}
+		cfg.Domains = append(cfg.Domains, dc)
+	}
+	return cfg, nil
+}
+
+func domainConfig(name string, d *domain, dir string) (*models.DomainConfig, error) {
+	if d == nil {
+		return nil, errors.Errorf("has no settings")
+	}
+	dc := &models.DomainConfig{
+		Name:             name,
+		RegistrarName:    d.Registrar,
+		DNSProviderNames: d.DNSProviders,
+		Metadata:         d.Meta,
+		KeepUnknown:      d.NoPurge,
+		Records:          models.Records{},
+	}
+	dc.SplitTag()
+	if dc.DNSProviderNames == nil {
+		dc.DNSProviderNames = map[string]int{}
+	}
+	if len(d.Records) != 0 {
+		data, err := yaml.Marshal(d.Records)
+		if err != nil {
+			return nil, err
+		}
+		recs, err := octoyaml.ReadYaml(bytes.NewReader(data), dc.Name)
+		if err != nil {
+			return nil, err
+		}
+		dc.Records = append(dc.Records, recs...)
+	}
+	if d.ZoneFile != "" {
+		file := d.ZoneFile
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		recs, err := octoyaml.ReadYaml(f, dc.Name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", file)
+		}
+		dc.Records = append(dc.Records, recs...)
+	}
+	for _, r := range dc.Records {
+		if r.TTL == 0 {
+			r.TTL = d.DefaultTTL
+		}
+	}
+	return dc, nil
+}
+
+// providerMeta returns the meta of p as JSON, as NewRegistrar() and
+// NewDnsProvider() keep it.
+func providerMeta(p *provider) (json.RawMessage, error) {
+	if p == nil || p.Type == "" {
+		return nil, errors.Errorf("has no type")
+	}
+	if len(p.Meta) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(jsonValue(p.Meta))
+}
+
+// jsonValue converts the maps of v, which YAML keys with interface{},
+// to maps keyed with strings, which JSON can encode.
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := map[string]interface{}{}
+		for k, e := range v {
+			m[k] = jsonValue(e)
+		}
+		return m
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for k, e := range v {
+			m[fmt.Sprint(k)] = jsonValue(e)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, e := range v {
+			l[i] = jsonValue(e)
+		}
+		return l
+	}
+	return v
+}
+
+func sortedKeys(m map[string]*provider) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,74 @@
+package yamlconfig
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	cfg, err := Load("testdata/dnsconfig.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Registrars) != 1 || cfg.Registrars[0].Name != "none" || cfg.Registrars[0].Type != "NONE" {
+		t.Errorf("registrars: got %+v", cfg.Registrars)
+	}
+	if len(cfg.DNSProviders) != 2 || string(cfg.DNSProviders[1].Metadata) != `{"manage_redirects":true}` {
+		t.Errorf("dns providers: got %+v", cfg.DNSProviders)
+	}
+	if len(cfg.Domains) != 2 {
+		t.Fatalf("got %d domains, want 2", len(cfg.Domains))
+	}
+
+	com := cfg.Domains[0]
+	if com.Name != "example.com" || !com.KeepUnknown || com.Metadata["cloudflare_proxy_default"] != "on" {
+		t.Errorf("example.com: got %+v", com)
+	}
+	if com.DNSProviderNames["bind"] != -1 || com.DNSProviderNames["cloudflare"] != -1 {
+		t.Errorf("example.com: got providers %v", com.DNSProviderNames)
+	}
+	var recs []string
+	for _, r := range com.Records {
+		recs = append(recs, fmt.Sprintf("%s %s %s %d", r.GetLabel(), r.Type, r.GetTargetCombined(), r.TTL))
+	}
+	want := "@ A 1.2.3.4 600,@ A 1.2.3.5 600,@ MX 10 mx.example.com. 300,www CNAME example.com. 600"
+	if got := strings.Join(recs, ","); got != want {
+		t.Errorf("example.com records:\ngot  %s\nwant %s", got, want)
+	}
+
+	net := cfg.Domains[1]
+	if net.DNSProviderNames["bind"] != 2 {
+		t.Errorf("example.net: got providers %v", net.DNSProviderNames)
+	}
+	if len(net.Records) != 1 || net.Records[0].GetTargetField() != "v=spf1 -all" {
+		t.Errorf("example.net: got records %v", net.Records)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yamlconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	tests := map[string]string{
+		"unknown key":       "domains:\n  example.com:\n    registrar: none\n    dns-providers: [bind]\n",
+		"no type":           "registrars:\n  none: {}\n",
+		"bad dns_providers": "domains:\n  example.com:\n    dns_providers: bind\n",
+		"bad record":        "domains:\n  example.com:\n    records:\n      www:\n        type: A\n        address: 1.2.3.4\n",
+		"no zone file":      "domains:\n  example.com:\n    zone_file: nosuch.yaml\n",
+	}
+	for name, text := range tests {
+		file := filepath.Join(dir, "dnsconfig.yaml")
+		if err := ioutil.WriteFile(file, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(file); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Package zonehash computes stable hashes of record sets, so monitoring
+// can alert on drift (the records served no longer being the records in
+// dnsconfig.js) by comparing two strings instead of parsing diffs.
+package zonehash
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/models"
+)
+
+// Hash returns the hash of records. It only depends on the name, type,
+// TTL and data of each record, not on their order or metadata. SOA
+// records are left out: providers change their serial on every update.
+func Hash(records models.Records) string {
+	lines := make([]string, 0, len(records))
+	for _, r := range records {
+		if r.Type == "SOA" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s %d %s %s",
+			strings.ToLower(r.GetLabelFQDN()), r.TTL, r.Type, r.GetTargetCombined()))
+	}
+	sort.Strings(lines)
+	h := sha256.New()
+	for _, l := range lines {
+		h.Write([]byte(l))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Entry is one line of a history file: the hashes of a domain at a time.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Domain  string    `json:"domain"`
+	Desired string    `json:"desired"`
+	// Actual maps the DNS providers that can list their records to the
+	// hash of the records they serve.
+	Actual map[string]string `json:"actual,omitempty"`
+}
+
+// Append adds e to the history file, one JSON object per line.
+func Append(filename string, e *Entry) error {
+	f, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
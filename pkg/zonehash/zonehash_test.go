@@ -0,0 +1,65 @@
+package zonehash
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/models"
+)
+
+func rec(label, typ, target string, ttl uint32) *models.RecordConfig {
+	rc := &models.RecordConfig{Type: typ, TTL: ttl}
+	rc.SetLabel(label, "example.com")
+	rc.SetTarget(target)
+	return rc
+}
+
+func TestHash(t *testing.T) {
+	a := models.Records{rec("@", "A", "192.0.2.1", 300), rec("www", "CNAME", "example.com.", 300)}
+	b := models.Records{rec("www", "CNAME", "example.com.", 300), rec("@", "A", "192.0.2.1", 300)}
+	b[0].Metadata = map[string]string{"priority_hint": "first"}
+	b = append(b, rec("@", "SOA", "ns1.example.com. hostmaster.example.com. 42 3600 600 604800 1440", 300))
+	if Hash(a) != Hash(b) {
+		t.Errorf("expected order, metadata and SOA not to change the hash")
+	}
+	a[0].TTL = 600
+	if Hash(a) == Hash(b) {
+		t.Errorf("expected the TTL to change the hash")
+	}
+}
+
+func TestAppend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "zonehash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "history.jsonl")
+	now := time.Now().UTC()
+	for _, h := range []string{"aaa", "bbb"} {
+		if err := Append(file, &Entry{Time: now, Domain: "example.com", Desired: h, Actual: map[string]string{"bind": "aaa"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var got []string
+	for s := bufio.NewScanner(f); s.Scan(); {
+		e := &Entry{}
+		if err := json.Unmarshal(s.Bytes(), e); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, e.Desired+"/"+e.Actual["bind"])
+	}
+	if len(got) != 2 || got[0] != "aaa/aaa" || got[1] != "bbb/aaa" {
+		t.Errorf("unexpected history %v", got)
+	}
+}
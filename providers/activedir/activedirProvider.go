@@ -2,9 +2,9 @@ package activedir
 
 import (
 	"encoding/json"
-	"fmt"
 	"runtime"
 
+	"github.com/StackExchange/dnscontrol/pkg/printer"
 	"github.com/StackExchange/dnscontrol/providers"
 	"github.com/pkg/errors"
 )
@@ -20,11 +20,13 @@ type adProvider struct {
 var features = providers.DocumentationNotes{
 	providers.CanUseAlias:            providers.Cannot(),
 	providers.CanUseCAA:              providers.Cannot(),
+	providers.CanUseDHCID:            providers.Can(),
 	providers.CanUsePTR:              providers.Cannot(),
 	providers.CanUseSRV:              providers.Cannot(),
 	providers.DocCreateDomains:       providers.Cannot("AD depends on the zone already existing on the dns server"),
 	providers.DocDualHost:            providers.Cannot("This driver does not manage NS records, so should not be used for dual-host scenarios"),
 	providers.DocOfficiallySupported: providers.Can(),
+	providers.CanPatchRecords:        providers.Can(),
 }
 
 // Register with the dnscontrol system.
@@ -62,6 +64,6 @@ func newDNS(config map[string]string, metadata json.RawMessage) (providers.DNSSe
 		p.adServer = srv
 		return p, nil
 	}
-	fmt.Printf("WARNING: PowerShell not available. Active Directory will not be updated.\n")
+	printer.Printf("WARNING: PowerShell not available. Active Directory will not be updated.\n")
 	return providers.None{}, nil
 }
@@ -195,6 +195,8 @@ func (r *RecordConfigJson) unpackRecord(origin string) (rc *models.RecordConfig,
 		rc.SetTarget(r.Data)
 	case "CNAME":
 		rc.SetTarget(strings.ToLower(r.Data))
+	case "DHCID":
+		rc.SetTarget(r.Data)
 	case "NS":
 		// skip root NS
 		if rc.Name == "@" {
@@ -212,7 +214,7 @@ func (r *RecordConfigJson) unpackRecord(origin string) (rc *models.RecordConfig,
 // powerShellDump runs a PowerShell command to get a dump of all records in a DNS zone.
 func (c *adProvider) generatePowerShellZoneDump(domainname string) string {
 	cmdTxt := `@("REPLACE_WITH_ZONE") | %{
-Get-DnsServerResourceRecord -ComputerName REPLACE_WITH_COMPUTER_NAME -ZoneName $_ | select hostname,recordtype,@{n="timestamp";e={$_.timestamp.tostring()}},@{n="timetolive";e={$_.timetolive.totalseconds}},@{n="recorddata";e={($_.recorddata.ipv4address,$_.recorddata.ipv6address,$_.recorddata.HostNameAlias,$_.recorddata.NameServer,"unsupported_record_type" -ne $null)[0]-as [string]}} | ConvertTo-Json > REPLACE_WITH_FILENAMEPREFIX.REPLACE_WITH_ZONE.json
+Get-DnsServerResourceRecord -ComputerName REPLACE_WITH_COMPUTER_NAME -ZoneName $_ | select hostname,recordtype,@{n="timestamp";e={$_.timestamp.tostring()}},@{n="timetolive";e={$_.timetolive.totalseconds}},@{n="recorddata";e={($_.recorddata.ipv4address,$_.recorddata.ipv6address,$_.recorddata.HostNameAlias,$_.recorddata.NameServer,$_.recorddata.DhcId,"unsupported_record_type" -ne $null)[0]-as [string]}} | ConvertTo-Json > REPLACE_WITH_FILENAMEPREFIX.REPLACE_WITH_ZONE.json
 }`
 	cmdTxt = strings.Replace(cmdTxt, "REPLACE_WITH_ZONE", domainname, -1)
 	cmdTxt = strings.Replace(cmdTxt, "REPLACE_WITH_COMPUTER_NAME", c.adServer, -1)
@@ -225,7 +227,7 @@ func (c *adProvider) generatePowerShellCreate(domainname string, rec *models.Rec
 	content := rec.GetTargetField()
 	text := "\r\n" // Skip a line.
 	funcSuffix := rec.Type
-	if rec.Type == "NS" {
+	if rec.Type == "NS" || rec.Type == "DHCID" {
 		funcSuffix = ""
 	}
 	text += fmt.Sprintf("Add-DnsServerResourceRecord%s", funcSuffix)
@@ -240,6 +242,8 @@ func (c *adProvider) generatePowerShellCreate(domainname string, rec *models.Rec
 		text += fmt.Sprintf(` -IPv4Address "%s"`, content)
 	case "NS":
 		text += fmt.Sprintf(` -NS -NameServer "%s"`, content)
+	case "DHCID":
+		text += fmt.Sprintf(` -DhcId -DhcpIdentifier "%s"`, content)
 	default:
 		panic(errors.Errorf("generatePowerShellCreate() does not yet handle recType=%s recName=%#v content=%#v)",
 			rec.Type, rec.GetLabel(), content))
@@ -264,6 +268,8 @@ func (c *adProvider) generatePowerShellModify(domainname, recName, recType, oldC
 		queryField = "HostNameAlias"
 	case "NS":
 		queryField = "NameServer"
+	case "DHCID":
+		queryField = "DhcId"
 	default:
 		panic(errors.Errorf("generatePowerShellModify() does not yet handle recType=%s recName=%#v content=(%#v, %#v)", recType, recName, oldContent, newContent))
 		// We panic so that we quickly find any switch statements
@@ -318,7 +324,8 @@ func (c *adProvider) createRec(domainname string, cre diff.Correlation) []*model
 	rec := cre.Desired
 	arr := []*models.Correction{
 		{
-			Msg: cre.String(),
+			Msg:     cre.String(),
+			Records: cre.Records(),
 			F: func() error {
 				return c.powerShellDoCommand(c.generatePowerShellCreate(domainname, rec), true)
 			}},
@@ -329,7 +336,8 @@ func (c *adProvider) createRec(domainname string, cre diff.Correlation) []*model
 func (c *adProvider) modifyRec(domainname string, m diff.Correlation) *models.Correction {
 	old, rec := m.Existing, m.Desired
 	return &models.Correction{
-		Msg: m.String(),
+		Msg:     m.String(),
+		Records: m.Records(),
 		F: func() error {
 			return c.powerShellDoCommand(c.generatePowerShellModify(domainname, rec.GetLabel(), rec.Type, old.GetTargetField(), rec.GetTargetField(), old.TTL, rec.TTL), true)
 		},
@@ -339,7 +347,8 @@ func (c *adProvider) modifyRec(domainname string, m diff.Correlation) *models.Co
 func (c *adProvider) deleteRec(domainname string, cor diff.Correlation) *models.Correction {
 	rec := cor.Existing
 	return &models.Correction{
-		Msg: cor.String(),
+		Msg:     cor.String(),
+		Records: cor.Records(),
 		F: func() error {
 			return c.powerShellDoCommand(c.generatePowerShellDelete(domainname, rec.GetLabel(), rec.Type, rec.GetTargetField()), true)
 		},
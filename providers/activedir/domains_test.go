@@ -2,6 +2,7 @@ package activedir
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/StackExchange/dnscontrol/models"
@@ -44,3 +45,19 @@ func TestGetExistingRecords(t *testing.T) {
 		t.Fatalf("got\n(%s)\nbut expected\n(%s)", actualS, expectedS)
 	}
 }
+
+func TestGeneratePowerShellDHCID(t *testing.T) {
+	c := &adProvider{adServer: "dc1"}
+	digest := "AAIBY2/AuCccgoJbsaxcQc9TUapptP69lOjxfNuVAA2kjEA="
+	rec := makeRC("host1", "example.com", digest, models.RecordConfig{Type: "DHCID", TTL: 300})
+
+	expected := "\r\nAdd-DnsServerResourceRecord -ComputerName \"dc1\" -ZoneName \"example.com\" -Name \"host1\" -TimeToLive $(New-TimeSpan -Seconds 300) -DhcId -DhcpIdentifier \"" + digest + "\"\r\n"
+	if got := c.generatePowerShellCreate("example.com", rec); got != expected {
+		t.Errorf("got\n%q\nbut expected\n%q", got, expected)
+	}
+
+	modify := c.generatePowerShellModify("example.com", "host1", "DHCID", digest, "AAEBOSD+XR3Os/0LozeXVqcNc7FwCfQdWL3b/NaiUDlW2No=", 300, 300)
+	if !strings.Contains(modify, `$_.RecordData.DhcId -eq "`+digest+`"`) || !strings.Contains(modify, `$NewObj.RecordData.DhcId = "AAEBOSD+XR3Os/0LozeXVqcNc7FwCfQdWL3b/NaiUDlW2No="`) {
+		t.Errorf("unexpected modify commands:\n%s", modify)
+	}
+}
@@ -1,12 +1,12 @@
 package activedir
 
 import (
-	"fmt"
 	"os/exec"
 	"strconv"
 	"strings"
 	"sync"
 
+	"github.com/StackExchange/dnscontrol/pkg/printer"
 	"github.com/pkg/errors"
 )
 
@@ -22,12 +22,12 @@ func (c *adProvider) getRecords(domainname string) ([]byte, error) {
 		checkPS.Do(func() {
 			psAvailible = c.isPowerShellReady()
 			if !psAvailible {
-				fmt.Printf("\n\n\n")
-				fmt.Printf("***********************************************\n")
-				fmt.Printf("PowerShell DnsServer module not installed.\n")
-				fmt.Printf("See http://social.technet.microsoft.com/wiki/contents/articles/2202.remote-server-administration-tools-rsat-for-windows-client-and-windows-server-dsforum2wiki.aspx\n")
-				fmt.Printf("***********************************************\n")
-				fmt.Printf("\n\n\n")
+				printer.Printf("\n\n\n")
+				printer.Printf("***********************************************\n")
+				printer.Printf("PowerShell DnsServer module not installed.\n")
+				printer.Printf("See http://social.technet.microsoft.com/wiki/contents/articles/2202.remote-server-administration-tools-rsat-for-windows-client-and-windows-server-dsforum2wiki.aspx\n")
+				printer.Printf("***********************************************\n")
+				printer.Printf("\n\n\n")
 			}
 		})
 		if !psAvailible {
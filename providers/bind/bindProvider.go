@@ -17,27 +17,40 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/miekg/dns"
 	"github.com/pkg/errors"
 
 	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
 	"github.com/StackExchange/dnscontrol/providers"
 	"github.com/StackExchange/dnscontrol/providers/diff"
 )
 
 var features = providers.DocumentationNotes{
 	providers.CanUseCAA:              providers.Can(),
+	providers.CanUseCERT:             providers.Can(),
+	providers.CanUseCSYNC:            providers.Can(),
+	providers.CanUseDHCID:            providers.Can(),
+	providers.CanUseDNAME:            providers.Can(),
 	providers.CanUsePTR:              providers.Can(),
+	providers.CanUseRP:               providers.Can(),
 	providers.CanUseNAPTR:            providers.Can(),
+	providers.CanUseOPENPGPKEY:       providers.Can(),
+	providers.CanUseSMIMEA:           providers.Can(),
+	providers.CanUseSOA:              providers.Can(),
 	providers.CanUseSRV:              providers.Can(),
 	providers.CanUseSSHFP:            providers.Can(),
 	providers.CanUseTLSA:             providers.Can(),
 	providers.CanUseTXTMulti:         providers.Can(),
+	providers.CanUseUNKNOWN:          providers.Can(),
+	providers.CanUseURI:              providers.Can(),
 	providers.CantUseNOPURGE:         providers.Cannot(),
 	providers.DocCreateDomains:       providers.Can("Driver just maintains list of zone files. It should automatically add missing ones."),
 	providers.DocDualHost:            providers.Can(),
@@ -86,6 +99,7 @@ func (s SoaInfo) String() string {
 type Bind struct {
 	DefaultNS   []string `json:"default_ns"`
 	DefaultSoa  SoaInfo  `json:"default_soa"`
+	SoaSerial   string   `json:"soa_serial"` // How serials are generated, unless SOA() says otherwise.
 	nameservers []*models.Nameserver
 	directory   string
 }
@@ -113,14 +127,22 @@ func rrToRecord(rr dns.RR, origin string, replaceSerial uint32) (models.RecordCo
 		panicInvalid(rc.SetTarget(v.AAAA.String()))
 	case *dns.CAA:
 		panicInvalid(rc.SetTargetCAA(v.Flag, v.Tag, v.Value))
+	case *dns.CERT:
+		panicInvalid(rc.SetTargetCERT(v.Type, v.KeyTag, v.Algorithm, v.Certificate))
 	case *dns.CNAME:
 		panicInvalid(rc.SetTarget(v.Target))
+	case *dns.DHCID:
+		panicInvalid(rc.SetTarget(v.Digest))
+	case *dns.DNAME:
+		panicInvalid(rc.SetTarget(v.Target))
 	case *dns.MX:
 		panicInvalid(rc.SetTargetMX(v.Preference, v.Mx))
 	case *dns.NS:
 		panicInvalid(rc.SetTarget(v.Ns))
 	case *dns.PTR:
 		panicInvalid(rc.SetTarget(v.Ptr))
+	case *dns.RP:
+		panicInvalid(rc.SetTargetRP(v.Mbox, v.Txt))
 	case *dns.NAPTR:
 		panicInvalid(rc.SetTargetNAPTR(v.Order, v.Preference, v.Flags, v.Service, v.Regexp, v.Replacement))
 	case *dns.SOA:
@@ -139,6 +161,10 @@ func rrToRecord(rr dns.RR, origin string, replaceSerial uint32) (models.RecordCo
 				v.Ns, v.Mbox, newSerial, v.Refresh, v.Retry, v.Expire, v.Minttl),
 		))
 		// FIXME(tlim): SOA should be handled by splitting out the fields.
+	case *dns.OPENPGPKEY:
+		panicInvalid(rc.SetTarget(v.PublicKey))
+	case *dns.SMIMEA:
+		panicInvalid(rc.SetTargetSMIMEA(v.Usage, v.Selector, v.MatchingType, v.Certificate))
 	case *dns.SRV:
 		panicInvalid(rc.SetTargetSRV(v.Priority, v.Weight, v.Port, v.Target))
 	case *dns.SSHFP:
@@ -147,8 +173,22 @@ func rrToRecord(rr dns.RR, origin string, replaceSerial uint32) (models.RecordCo
 		panicInvalid(rc.SetTargetTLSA(v.Usage, v.Selector, v.MatchingType, v.Certificate))
 	case *dns.TXT:
 		panicInvalid(rc.SetTargetTXTs(v.Txt))
+	case *dns.URI:
+		panicInvalid(rc.SetTargetURI(v.Priority, v.Weight, v.Target))
+	case *dns.RFC3597:
+		if header.Rrtype == models.TypeCSYNC {
+			rc.Type = "CSYNC"
+			panicInvalid(rc.SetTargetCSYNCRdata(v.Rdata))
+		} else {
+			rc.Type = ""
+			panicInvalid(rc.SetTargetUnknown(header.Rrtype, v.Rdata))
+		}
 	default:
-		log.Fatalf("rrToRecord: Unimplemented zone record type=%s (%v)\n", rc.Type, rr)
+		// A type dnscontrol doesn't model. Keep its rdata, like UNKNOWN() does.
+		generic := &dns.RFC3597{}
+		panicInvalid(generic.ToRFC3597(rr))
+		rc.Type = ""
+		panicInvalid(rc.SetTargetUnknown(header.Rrtype, generic.Rdata))
 	}
 	return rc, oldSerial
 }
@@ -191,11 +231,63 @@ func makeDefaultSOA(info SoaInfo, origin string) *models.RecordConfig {
 	return &soaRec
 }
 
+// findSOA returns the SOA record of dc, or nil if dnsconfig.js doesn't set one.
+func findSOA(dc *models.DomainConfig) *models.RecordConfig {
+	for _, r := range dc.Records {
+		if r.Type == "SOA" && r.GetLabel() == "@" {
+			return r
+		}
+	}
+	return nil
+}
+
+// soaRecSerial returns the serial number of an SOA record.
+func soaRecSerial(rc *models.RecordConfig) uint32 {
+	return rc.ToRR().(*dns.SOA).Serial
+}
+
 // GetNameservers returns the nameservers for a domain.
 func (c *Bind) GetNameservers(string) ([]*models.Nameserver, error) {
 	return c.nameservers, nil
 }
 
+// GetZoneRecords returns the records in the zone file of domain, except the SOA.
+func (c *Bind) GetZoneRecords(domain string) (models.Records, error) {
+	zonefile := filepath.Join(c.directory, strings.Replace(strings.ToLower(domain), "/", "_", -1)+".zone")
+	fh, err := os.Open(zonefile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can not read zonefile of %s", domain)
+	}
+	defer fh.Close()
+	return ReadZone(fh, domain, zonefile)
+}
+
+// ReadZone returns the records of the zone file r of origin, except the
+// SOA. The file is named file in errors.
+func ReadZone(r io.Reader, origin, file string) (models.Records, error) {
+	records := models.Records{}
+	var parseErr error
+	// Drain the channel even after an error so the parser can finish.
+	for x := range dns.ParseZone(r, origin, file) {
+		if x.Error != nil {
+			if parseErr == nil {
+				parseErr = errors.Wrapf(x.Error, "error in zonefile %s", file)
+			}
+			continue
+		}
+		if x.RR.Header().Rrtype == dns.TypeSOA {
+			continue
+		}
+		rec, _ := rrToRecord(x.RR, origin, 0)
+		records = append(records, &rec)
+	}
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	models.PostProcessRecords(records)
+	return records, nil
+}
+
 // GetDomainCorrections returns a list of corrections to update a domain.
 func (c *Bind) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
 	dc.Punycode()
@@ -217,10 +309,10 @@ func (c *Bind) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correcti
 
 	// Read foundRecords:
 	foundRecords := make([]*models.RecordConfig, 0)
-	var oldSerial, newSerial uint32
+	var oldSerial uint32
 
 	if _, err := os.Stat(c.directory); os.IsNotExist(err) {
-		fmt.Printf("\nWARNING: BIND directory %q does not exist!\n", c.directory)
+		printer.Printf("\nWARNING: BIND directory %q does not exist!\n", c.directory)
 	}
 
 	zonefile := filepath.Join(c.directory, strings.Replace(strings.ToLower(dc.Name), "/", "_", -1)+".zone")
@@ -229,7 +321,7 @@ func (c *Bind) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correcti
 	if err != nil && !os.IsNotExist(os.ErrNotExist) {
 		// Don't whine if the file doesn't exist. However all other
 		// errors will be reported.
-		fmt.Printf("Could not read zonefile: %v\n", err)
+		printer.Printf("Could not read zonefile: %v\n", err)
 	} else {
 		for x := range dns.ParseZone(foundFH, dc.Name, zonefile) {
 			if x.Error != nil {
@@ -240,11 +332,10 @@ func (c *Bind) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correcti
 					log.Fatalf("Multiple SOA records in zonefile: %v\n", zonefile)
 				}
 				if serial != 0 {
-					// This was an SOA record. Update the serial.
+					// This was an SOA record. The serial is updated
+					// below, if anything changes.
 					oldSerial = serial
-					newSerial = generateSerial(oldSerial)
-					// Regenerate with new serial:
-					*soaRec, _ = rrToRecord(x.RR, dc.Name, newSerial)
+					*soaRec, _ = rrToRecord(x.RR, dc.Name, oldSerial)
 					rec = *soaRec
 				}
 				foundRecords = append(foundRecords, &rec)
@@ -252,8 +343,28 @@ func (c *Bind) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correcti
 		}
 	}
 
-	// Add SOA record to expected set:
-	if !dc.HasRecordTypeName("SOA", "@") {
+	// Add SOA record to expected set. An SOA record in dnsconfig.js
+	// replaces the one in the zonefile, except for the serial number:
+	soaSerial := c.SoaSerial
+	if userSoa := findSOA(dc); userSoa != nil {
+		if err := userSoa.SetSOASerial(soaRecSerial(soaRec)); err != nil {
+			return nil, err
+		}
+		if s := userSoa.Metadata["soa_serial"]; s != "" {
+			soaSerial = s
+		}
+		soaRec = userSoa
+	} else {
+		// TTL_POLICY() manages the negative caching TTL even without an SOA().
+		if s, ok := dc.Metadata["soa_minimum"]; ok {
+			minttl, err := strconv.ParseUint(s, 10, 32)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid soa_minimum %q", s)
+			}
+			if err := soaRec.SetSOAMinimum(uint32(minttl)); err != nil {
+				return nil, err
+			}
+		}
 		dc.Records = append(dc.Records, soaRec)
 	}
 
@@ -261,7 +372,12 @@ func (c *Bind) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correcti
 	models.PostProcessRecords(foundRecords)
 
 	differ := diff.New(dc)
-	_, create, del, mod := differ.IncrementalDiff(foundRecords)
+	unchanged, create, del, mod := differ.IncrementalDiff(foundRecords)
+	zoneRecords := dc.Records
+	if dc.Owner != "" {
+		// The records of other owners stay in the zonefile.
+		zoneRecords = ownedZone(foundRecords, unchanged, create, del, mod)
+	}
 
 	buf := &bytes.Buffer{}
 	// Print a list of changes. Generate an actual change that is the zone
@@ -288,6 +404,15 @@ func (c *Bind) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correcti
 	if !zoneFileFound {
 		msg = msg + fmt.Sprintf(" (%d records)\n", len(create))
 	}
+	if changes && (oldSerial != 0 || soaSerial != "") {
+		newSerial := nextSerial(soaSerial, oldSerial)
+		if err := soaRec.SetSOASerial(newSerial); err != nil {
+			return nil, err
+		}
+		if zoneFileFound {
+			fmt.Fprintf(buf, "SOA serial %d -> %d\n", oldSerial, newSerial)
+		}
+	}
 	msg += buf.String()
 	corrections := []*models.Correction{}
 	if changes {
@@ -295,13 +420,13 @@ func (c *Bind) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correcti
 			&models.Correction{
 				Msg: msg,
 				F: func() error {
-					fmt.Printf("CREATING ZONEFILE: %v\n", zonefile)
+					printer.Printf("CREATING ZONEFILE: %v\n", zonefile)
 					zf, err := os.Create(zonefile)
 					if err != nil {
 						log.Fatalf("Could not create zonefile: %v", err)
 					}
-					zonefilerecords := make([]dns.RR, 0, len(dc.Records))
-					for _, r := range dc.Records {
+					zonefilerecords := make([]dns.RR, 0, len(zoneRecords))
+					for _, r := range zoneRecords {
 						zonefilerecords = append(zonefilerecords, r.ToRR())
 					}
 					err = WriteZoneFile(zf, zonefilerecords, dc.Name)
@@ -320,3 +445,26 @@ func (c *Bind) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correcti
 
 	return corrections, nil
 }
+
+// ownedZone returns the records of a zonefile once the changes found by
+// the differ are made, keeping the records it left alone.
+func ownedZone(found []*models.RecordConfig, unchanged, create, del, mod diff.Changeset) []*models.RecordConfig {
+	changed := map[*models.RecordConfig]bool{}
+	records := []*models.RecordConfig{}
+	for _, cs := range []diff.Changeset{unchanged, create, del, mod} {
+		for _, c := range cs {
+			if c.Existing != nil {
+				changed[c.Existing] = true
+			}
+			if c.Desired != nil {
+				records = append(records, c.Desired)
+			}
+		}
+	}
+	for _, r := range found {
+		if !changed[r] {
+			records = append(records, r)
+		}
+	}
+	return records
+}
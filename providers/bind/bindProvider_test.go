@@ -0,0 +1,283 @@
+package bind
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/models"
+)
+
+const soaZone = `$TTL 300
+@ IN SOA ns1.example.com. hostmaster.example.com. 2015010801 3600 600 604800 1440
+@ IN A 192.0.2.1
+`
+
+func soaDomain(target string) *models.DomainConfig {
+	dc := &models.DomainConfig{Name: "example.com"}
+	a := &models.RecordConfig{Type: "A", TTL: 300}
+	a.SetLabel("@", dc.Name)
+	a.SetTarget("192.0.2.1")
+	soa := &models.RecordConfig{Type: "SOA", TTL: 300, Metadata: map[string]string{"soa_serial": "increment"}}
+	soa.SetLabel("@", dc.Name)
+	soa.SetTargetSOAString(target)
+	dc.Records = models.Records{a, soa}
+	return dc
+}
+
+func TestSOACorrections(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bind")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	zonefile := filepath.Join(dir, "example.com.zone")
+	if err := ioutil.WriteFile(zonefile, []byte(soaZone), 0644); err != nil {
+		t.Fatal(err)
+	}
+	nowFunc = func() time.Time { return time.Date(2015, 1, 8, 0, 0, 0, 0, time.UTC) }
+	c := &Bind{directory: dir}
+
+	// Same values as the zonefile: nothing to do.
+	corrections, err := c.GetDomainCorrections(soaDomain("ns1.example.com. hostmaster.example.com. 0 3600 600 604800 1440"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrections) != 0 {
+		t.Fatalf("expected no corrections, got %v", corrections[0].Msg)
+	}
+
+	// A new refresh value is written with the next serial number.
+	corrections, err = c.GetDomainCorrections(soaDomain("ns1.example.com. hostmaster.example.com. 0 7200 600 604800 1440"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrections) != 1 {
+		t.Fatalf("expected 1 correction, got %d", len(corrections))
+	}
+	if !strings.Contains(corrections[0].Msg, "SOA serial 2015010801 -> 2015010802") {
+		t.Errorf("unexpected correction message %q", corrections[0].Msg)
+	}
+	if err := corrections[0].F(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(zonefile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "2015010802 7200 600 604800 1440") {
+		t.Errorf("SOA not updated in zonefile:\n%s", b)
+	}
+}
+
+func TestSoaSerialDefault(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bind")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "example.com.zone"), []byte(soaZone), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer func(f func() time.Time) { nowFunc = f }(nowFunc)
+	nowFunc = func() time.Time { return time.Unix(2100000000, 0) }
+	c := &Bind{directory: dir, SoaSerial: "unixtime"}
+
+	dc := soaDomain("")
+	dc.Records[0].SetTarget("192.0.2.2")
+	dc.Records = dc.Records[:1] // No SOA(): the provider's soa_serial applies.
+	corrections, err := c.GetDomainCorrections(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrections) != 1 || !strings.Contains(corrections[0].Msg, "SOA serial 2015010801 -> 2100000000") {
+		t.Errorf("unexpected corrections %v", corrections[0].Msg)
+	}
+}
+
+func TestSOAMinimum(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bind")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	zonefile := filepath.Join(dir, "example.com.zone")
+	if err := ioutil.WriteFile(zonefile, []byte(soaZone), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c := &Bind{directory: dir}
+
+	// Without an SOA(), the soa_minimum metadata changes only the minimum.
+	dc := soaDomain("")
+	dc.Records = dc.Records[:1]
+	dc.Metadata = map[string]string{"soa_minimum": "300"}
+	corrections, err := c.GetDomainCorrections(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrections) != 1 {
+		t.Fatalf("expected 1 correction, got %d", len(corrections))
+	}
+	if err := corrections[0].F(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(zonefile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "hostmaster.example.com. 2015010802 3600 600 604800 300") {
+		t.Errorf("SOA minimum not updated in zonefile:\n%s", b)
+	}
+}
+
+func TestCSYNCZonefile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bind")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	c := &Bind{directory: dir}
+	domain := func() *models.DomainConfig {
+		dc := &models.DomainConfig{Name: "example.com"}
+		rc := &models.RecordConfig{Type: "CSYNC", TTL: 300}
+		rc.SetLabel("@", dc.Name)
+		rc.SetTargetCSYNC(66, 3, "A NS AAAA")
+		dc.Records = models.Records{rc}
+		return dc
+	}
+
+	corrections, err := c.GetDomainCorrections(domain())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrections) != 1 {
+		t.Fatalf("expected 1 correction, got %d", len(corrections))
+	}
+	if err := corrections[0].F(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "example.com.zone"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `TYPE62 \# 12 000000420003000460000008`) {
+		t.Errorf("CSYNC not in zonefile:\n%s", b)
+	}
+
+	// Reading the zonefile back gives the same record.
+	corrections, err = c.GetDomainCorrections(domain())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrections) != 0 {
+		t.Errorf("expected no corrections, got %v", corrections[0].Msg)
+	}
+}
+
+func TestUnknownZonefile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bind")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	zonefile := filepath.Join(dir, "example.com.zone")
+	c := &Bind{directory: dir}
+	domain := func(rdata string) *models.DomainConfig {
+		dc := &models.DomainConfig{Name: "example.com"}
+		hinfo := &models.RecordConfig{TTL: 300}
+		hinfo.SetLabel("@", dc.Name)
+		hinfo.SetTargetUnknown(13, rdata)
+		private := &models.RecordConfig{TTL: 300}
+		private.SetLabel("@", dc.Name)
+		private.SetTargetUnknown(65280, "0a0b0c")
+		dc.Records = models.Records{hinfo, private}
+		return dc
+	}
+
+	// HINFO isn't modeled by dnscontrol, but can be managed with UNKNOWN().
+	if err := ioutil.WriteFile(zonefile, []byte("@ 300 IN HINFO \"PC\" \"Linux\"\n@ 300 IN TYPE65280 \\# 3 0a0b0c\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	corrections, err := c.GetDomainCorrections(domain("025043054c696e7578"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrections) != 1 || !strings.Contains(corrections[0].Msg, "CREATE SOA") || strings.Contains(corrections[0].Msg, "TYPE13") {
+		t.Fatalf("expected only the SOA to be added, got %v", corrections[0].Msg)
+	}
+
+	corrections, err = c.GetDomainCorrections(domain("025043034253 44"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := corrections[0].F(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(zonefile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Types the DNS library knows are written in their usual format.
+	if !strings.Contains(string(b), `HINFO "PC" "BSD"`) || !strings.Contains(string(b), `TYPE65280 \# 3 0a0b0c`) {
+		t.Errorf("unexpected zonefile:\n%s", b)
+	}
+	corrections, err = c.GetDomainCorrections(domain("025043034253 44"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrections) != 0 {
+		t.Errorf("expected no corrections, got %v", corrections[0].Msg)
+	}
+}
+
+func TestRPZonefile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bind")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	zonefile := filepath.Join(dir, "example.com.zone")
+	c := &Bind{directory: dir}
+	domain := func() *models.DomainConfig {
+		dc := &models.DomainConfig{Name: "example.com"}
+		rc := &models.RecordConfig{Type: "RP", TTL: 300}
+		rc.SetLabel("@", dc.Name)
+		rc.SetTargetRP("hostmaster.example.com.", "contact.example.com.")
+		dc.Records = models.Records{rc}
+		return dc
+	}
+
+	// The names are case insensitive.
+	if err := ioutil.WriteFile(zonefile, []byte("@ 300 IN RP Hostmaster.Example.com. contact.example.COM.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	corrections, err := c.GetDomainCorrections(domain())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrections) != 1 || strings.Contains(corrections[0].Msg, "RP") {
+		t.Fatalf("expected only the SOA to be added, got %v", corrections)
+	}
+	if err := corrections[0].F(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(zonefile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(strings.Join(strings.Fields(string(b)), " "), "IN RP hostmaster.example.com. contact.example.com.") {
+		t.Errorf("RP not in zonefile:\n%s", b)
+	}
+
+	// Reading the zonefile back gives the same record.
+	corrections, err = c.GetDomainCorrections(domain())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrections) != 0 {
+		t.Errorf("expected no corrections, got %v", corrections[0].Msg)
+	}
+}
@@ -198,9 +198,17 @@ func (z *zoneGenData) generateZoneFileHelper(w io.Writer) error {
 
 		// items[3]: type
 		typeStr := dns.TypeToString[hdr.Rrtype]
+		if _, ok := rr.(*dns.RFC3597); ok {
+			// The rdata is in the RFC 3597 generic format.
+			typeStr = "TYPE" + strconv.Itoa(int(hdr.Rrtype))
+		}
 
 		// items[4]: the remaining line
 		target := items[4]
+		if rp, ok := rr.(*dns.RP); ok {
+			// The DNS library quotes the txt domain name, which it can't read back.
+			target = rp.Mbox + " " + rp.Txt
+		}
 
 		fmt.Fprintln(w, formatLine([]int{10, 5, 2, 5, 0}, []string{name, ttl, "IN", typeStr, target}))
 	}
@@ -9,6 +9,31 @@ import (
 
 var nowFunc = time.Now
 
+// nextSerial returns the SOA serial number that follows oldSerial.
+// The strategy is the soa_serial metadata of the SOA record. "date" (the
+// default) uses yyyymmddvv as described in generateSerial, "increment"
+// adds one to oldSerial and "unixtime" uses the current time in seconds
+// (or oldSerial + 1 if that is larger).
+func nextSerial(strategy string, oldSerial uint32) uint32 {
+	var newSerial uint32
+	switch strategy {
+	case "increment":
+		newSerial = oldSerial + 1
+	case "unixtime":
+		newSerial = uint32(nowFunc().Unix())
+		if newSerial <= oldSerial {
+			newSerial = oldSerial + 1
+		}
+	default:
+		return generateSerial(oldSerial)
+	}
+	if newSerial == 0 {
+		// We never return 0 as the serial number.
+		newSerial = 1
+	}
+	return newSerial
+}
+
 // generateSerial takes an old SOA serial number and increments it.
 func generateSerial(oldSerial uint32) uint32 {
 	// Serial numbers are in the format yyyymmddvv
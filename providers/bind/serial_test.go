@@ -49,3 +49,29 @@ func Test_generate_serial_1(t *testing.T) {
 		}
 	}
 }
+
+func Test_next_serial(t *testing.T) {
+	now, _ := time.Parse("20060102", "20150108")
+	nowFunc = func() time.Time {
+		return now
+	}
+	var tests = []struct {
+		Strategy string
+		Given    uint32
+		Expected uint32
+	}{
+		{"", 2015010801, 2015010802},
+		{"date", 123, 2015010801},
+		{"increment", 123, 124},
+		{"increment", 4294967295, 1},
+		{"unixtime", 123, 1420675200},
+		{"unixtime", 1420675200, 1420675201},
+		{"unixtime", 2015010801, 2015010802},
+	}
+	for i, tst := range tests {
+		found := nextSerial(tst.Strategy, tst.Given)
+		if tst.Expected != found {
+			t.Errorf("Test:%d/%s/%v: Expected (%d) got (%d)", i, tst.Strategy, tst.Given, tst.Expected, found)
+		}
+	}
+}
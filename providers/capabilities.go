@@ -49,6 +49,65 @@ const (
 
 	// CanUseRoute53Alias indicates the provider support the specific R53_ALIAS records that only the Route53 provider supports
 	CanUseRoute53Alias
+
+	// CanUseDNAME indicates the provider can handle DNAME records
+	CanUseDNAME
+
+	// CanUseCERT indicates the provider can handle CERT records
+	CanUseCERT
+
+	// CanUseSMIMEA indicates the provider can handle SMIMEA records
+	CanUseSMIMEA
+
+	// CanUseOPENPGPKEY indicates the provider can handle OPENPGPKEY records
+	CanUseOPENPGPKEY
+
+	// CanUseURI indicates the provider can handle URI records
+	CanUseURI
+
+	// CanUseSOA indicates the provider can manage the SOA record of a zone
+	CanUseSOA
+
+	// CanUseCSYNC indicates the provider can handle CSYNC records
+	CanUseCSYNC
+
+	// CanUseUNKNOWN indicates the provider can handle records of any type,
+	// declared with UNKNOWN() as RFC 3597 rdata
+	CanUseUNKNOWN
+
+	// CanUseDHCID indicates the provider can handle DHCID records
+	CanUseDHCID
+
+	// CanUseRP indicates the provider can handle RP records
+	CanUseRP
+
+	// CanUseRoutingPolicy indicates the provider can serve the weighted, geo
+	// and failover record sets of ROUTING()
+	CanUseRoutingPolicy
+
+	// CantUseMultipleValues indicates the provider sets one value per name
+	// and type, replacing the previous one, so it can't serve several
+	// records of the same name and type
+	CantUseMultipleValues
+
+	// CantReorderCorrections indicates the corrections of the provider must
+	// run in the order it returns them, so PRIORITY_HINT only orders the
+	// changes of the same kind
+	CantReorderCorrections
+
+	// CanCheckCredsWithNameservers indicates GetNameservers calls the API
+	// of the provider with its credentials, instead of returning names it
+	// knows, so check-creds can use it to check them
+	CanCheckCredsWithNameservers
+
+	// CanUseRegistrarLock indicates the registrar can lock domains against
+	// transfers, for REGISTRAR_LOCK()
+	CanUseRegistrarLock
+
+	// CanPatchRecords indicates the provider leaves the records of a domain
+	// to the differ, which honors its Patch, so acme-txt can add or remove
+	// one record without sending the others
+	CanPatchRecords
 )
 
 var providerCapabilities = map[string]map[Capability]bool{}
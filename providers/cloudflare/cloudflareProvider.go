@@ -3,7 +3,6 @@ package cloudflare
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"net"
 	"strings"
 	"time"
@@ -13,6 +12,7 @@ import (
 	"github.com/StackExchange/dnscontrol/pkg/transform"
 	"github.com/StackExchange/dnscontrol/providers"
 	"github.com/StackExchange/dnscontrol/providers/diff"
+	"github.com/gobwas/glob"
 	"github.com/miekg/dns/dnsutil"
 	"github.com/pkg/errors"
 )
@@ -38,15 +38,18 @@ Domain level metadata available:
 */
 
 var features = providers.DocumentationNotes{
-	providers.CanUseAlias:            providers.Can("CF automatically flattens CNAME records into A records dynamically"),
-	providers.CanUsePTR:              providers.Cannot(),
-	providers.CanUseCAA:              providers.Can(),
-	providers.CanUseSRV:              providers.Can(),
-	providers.CanUseTLSA:             providers.Can(),
-	providers.CanUseSSHFP:            providers.Can(),
-	providers.DocCreateDomains:       providers.Can(),
-	providers.DocDualHost:            providers.Cannot("Cloudflare will not work well in situations where it is not the only DNS server"),
-	providers.DocOfficiallySupported: providers.Can(),
+	providers.CanCheckCredsWithNameservers: providers.Can(),
+	providers.CanUseAlias:                  providers.Can("CF automatically flattens CNAME records into A records dynamically"),
+	providers.CanUsePTR:                    providers.Cannot(),
+	providers.CanUseCAA:                    providers.Can(),
+	providers.CanUseSRV:                    providers.Can(),
+	providers.CanUseURI:                    providers.Can(),
+	providers.CanUseTLSA:                   providers.Can(),
+	providers.CanUseSSHFP:                  providers.Can(),
+	providers.DocCreateDomains:             providers.Can(),
+	providers.DocDualHost:                  providers.Cannot("Cloudflare will not work well in situations where it is not the only DNS server"),
+	providers.DocOfficiallySupported:       providers.Can(),
+	providers.CanPatchRecords:              providers.Can(),
 }
 
 func init() {
@@ -92,6 +95,21 @@ func (c *CloudflareApi) GetNameservers(domain string) ([]*models.Nameserver, err
 	return models.StringsToNameservers(ns), nil
 }
 
+// GetZoneDSRecords returns the DS record of the key Cloudflare signs domain
+// with, if DNSSEC is enabled.
+func (c *CloudflareApi) GetZoneDSRecords(domain string) ([]*models.DSRecord, error) {
+	if c.domainIndex == nil {
+		if err := c.fetchDomainList(); err != nil {
+			return nil, err
+		}
+	}
+	id, ok := c.domainIndex[domain]
+	if !ok {
+		return nil, errors.Errorf("%s not listed in zones for cloudflare account", domain)
+	}
+	return c.getDNSSEC(id)
+}
+
 // GetDomainCorrections returns a list of corrections to update a domain.
 func (c *CloudflareApi) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
 	if c.domainIndex == nil {
@@ -112,13 +130,10 @@ func (c *CloudflareApi) GetDomainCorrections(dc *models.DomainConfig) ([]*models
 	if err != nil {
 		return nil, err
 	}
-	for i := len(records) - 1; i >= 0; i-- {
-		rec := records[i]
-		// Delete ignore labels
-		if labelMatches(dnsutil.TrimDomainName(rec.Original.(*cfRecord).Name, dc.Name), c.ignoredLabels) {
-			printer.Debugf("ignored_label: %s\n", rec.Original.(*cfRecord).Name)
-			records = append(records[:i], records[i+1:]...)
-		}
+	// The deprecated ignored_labels are IGNORE_NAME()s of those exact
+	// labels, which the differ leaves alone.
+	for _, l := range c.ignoredLabels {
+		dc.IgnoredNames = append(dc.IgnoredNames, &models.Ignore{Pattern: glob.QuoteMeta(l)})
 	}
 
 	if c.manageRedirects {
@@ -140,7 +155,7 @@ func (c *CloudflareApi) GetDomainCorrections(dc *models.DomainConfig) ([]*models
 			rec.TTL = 1
 		}
 		if labelMatches(rec.GetLabel(), c.ignoredLabels) {
-			log.Fatalf("FATAL: dnsconfig contains label that matches ignored_labels: %#v is in %v)\n", rec.GetLabel(), c.ignoredLabels)
+			return nil, errors.Errorf("dnsconfig contains label that matches ignored_labels: %#v is in %v", rec.GetLabel(), c.ignoredLabels)
 		}
 	}
 
@@ -149,7 +164,7 @@ func (c *CloudflareApi) GetDomainCorrections(dc *models.DomainConfig) ([]*models
 	// Normalize
 	models.PostProcessRecords(records)
 
-	differ := diff.New(dc, getProxyMetadata)
+	differ := diff.New(dc, getProxyMetadata, getNote)
 	_, create, del, mod := differ.IncrementalDiff(records)
 	corrections := []*models.Correction{}
 
@@ -157,23 +172,30 @@ func (c *CloudflareApi) GetDomainCorrections(dc *models.DomainConfig) ([]*models
 		ex := d.Existing
 		if ex.Type == "PAGE_RULE" {
 			corrections = append(corrections, &models.Correction{
-				Msg: d.String(),
-				F:   func() error { return c.deletePageRule(ex.Original.(*pageRule).ID, id) },
+				Msg:     d.String(),
+				Records: d.Records(),
+				F:       func() error { return c.deletePageRule(ex.Original.(*pageRule).ID, id) },
 			})
 
 		} else {
-			corrections = append(corrections, c.deleteRec(ex.Original.(*cfRecord), id))
+			correction := c.deleteRec(ex.Original.(*cfRecord), id)
+			correction.Records = d.Records()
+			corrections = append(corrections, correction)
 		}
 	}
 	for _, d := range create {
 		des := d.Desired
 		if des.Type == "PAGE_RULE" {
 			corrections = append(corrections, &models.Correction{
-				Msg: d.String(),
-				F:   func() error { return c.createPageRule(id, des.GetTargetField()) },
+				Msg:     d.String(),
+				Records: d.Records(),
+				F:       func() error { return c.createPageRule(id, des.GetTargetField()) },
 			})
 		} else {
-			corrections = append(corrections, c.createRec(des, id)...)
+			for _, correction := range c.createRec(des, id) {
+				correction.Records = d.Records()
+				corrections = append(corrections, correction)
+			}
 		}
 	}
 
@@ -182,15 +204,17 @@ func (c *CloudflareApi) GetDomainCorrections(dc *models.DomainConfig) ([]*models
 		ex := d.Existing
 		if rec.Type == "PAGE_RULE" {
 			corrections = append(corrections, &models.Correction{
-				Msg: d.String(),
-				F:   func() error { return c.updatePageRule(ex.Original.(*pageRule).ID, id, rec.GetTargetField()) },
+				Msg:     d.String(),
+				Records: d.Records(),
+				F:       func() error { return c.updatePageRule(ex.Original.(*pageRule).ID, id, rec.GetTargetField()) },
 			})
 		} else {
 			e := ex.Original.(*cfRecord)
 			proxy := e.Proxiable && rec.Metadata[metaProxy] != "off"
 			corrections = append(corrections, &models.Correction{
-				Msg: d.String(),
-				F:   func() error { return c.modifyRecord(id, e.ID, proxy, rec) },
+				Msg:     d.String(),
+				Records: d.Records(),
+				F:       func() error { return c.modifyRecord(id, e.ID, proxy, rec) },
 			})
 		}
 	}
@@ -282,7 +306,7 @@ func (c *CloudflareApi) preprocessConfig(dc *models.DomainConfig) error {
 	// Check UniversalSSL setting
 	if u := dc.Metadata[metaUniversalSSL]; u != "" {
 		u = strings.ToLower(u)
-		if (u != "on" && u != "off") {
+		if u != "on" && u != "off" {
 			return errors.Errorf("Bad metadata value for %s: '%s'. Use on/off.", metaUniversalSSL, u)
 		}
 	}
@@ -298,9 +322,9 @@ func (c *CloudflareApi) preprocessConfig(dc *models.DomainConfig) error {
 			rec.Metadata = map[string]string{}
 		}
 		// cloudflare uses "1" to mean "auto-ttl"
-		// if we get here and ttl is not specified (or is the dnscontrol default of 300), 
+		// if we get here and ttl is not specified (or is the dnscontrol default of 300),
 		// use automatic mode instead.
-		if rec.TTL == 0 || rec.TTL == 300{ 
+		if rec.TTL == 0 || rec.TTL == 300 {
 			rec.TTL = 1
 		}
 		if rec.TTL != 1 && rec.TTL < 120 {
@@ -403,7 +427,7 @@ func newCloudflare(m map[string]string, metadata json.RawMessage) (providers.DNS
 			api.ignoredLabels = append(api.ignoredLabels, l)
 		}
 		if len(api.ignoredLabels) > 0 {
-			printer.Warnf("Cloudflare 'ignored_labels' configuration is deprecated and might be removed. Please use the IGNORE domain directive to achieve the same effect.\n")
+			printer.Warnf("Cloudflare 'ignored_labels' configuration is deprecated and might be removed. Please use the IGNORE_NAME domain directive to achieve the same effect.\n")
 		}
 		// parse provider level metadata
 		if len(parsedMeta.IPConversions) > 0 {
@@ -452,6 +476,7 @@ type cfRecord struct {
 	ModifiedOn time.Time   `json:"modified_on"`
 	Data       *cfRecData  `json:"data"`
 	Priority   json.Number `json:"priority"`
+	Comment    string      `json:"comment"`
 }
 
 func (c *cfRecord) nativeToRecord(domain string) *models.RecordConfig {
@@ -492,6 +517,15 @@ func (c *cfRecord) nativeToRecord(domain string) *models.RecordConfig {
 			dnsutil.AddOrigin(data.Target+".", domain)); err != nil {
 			panic(errors.Wrap(err, "unparsable SRV record received from cloudflare"))
 		}
+	case "URI":
+		var priority uint16
+		if p, err := c.Priority.Int64(); err == nil {
+			priority = uint16(p)
+		}
+		data := *c.Data
+		if err := rc.SetTargetURI(priority, data.Weight, data.Target); err != nil {
+			panic(errors.Wrap(err, "unparsable URI record received from cloudflare"))
+		}
 	default: // "A", "AAAA", "ANAME", "CAA", "CNAME", "NS", "PTR", "TXT"
 		if err := rc.PopulateFromString(rType, c.Content, domain); err != nil {
 			panic(errors.Wrap(err, "unparsable record received from cloudflare"))
@@ -516,6 +550,25 @@ func getProxyMetadata(r *models.RecordConfig) map[string]string {
 	}
 }
 
+// getNote returns the comment of the record, which Cloudflare keeps as
+// the NOTE() of dnsconfig.js. Page rules have no comments.
+func getNote(r *models.RecordConfig) map[string]string {
+	if r.Type == "PAGE_RULE" {
+		return nil
+	}
+	note := r.Note()
+	if r.Original != nil {
+		note = ""
+		if cf, ok := r.Original.(*cfRecord); ok {
+			note = cf.Comment
+		}
+	}
+	if note == "" {
+		return nil
+	}
+	return map[string]string{"note": note}
+}
+
 // EnsureDomainExists returns an error of domain does not exist.
 func (c *CloudflareApi) EnsureDomainExists(domain string) error {
 	if _, ok := c.domainIndex[domain]; ok {
@@ -523,6 +576,6 @@ func (c *CloudflareApi) EnsureDomainExists(domain string) error {
 	}
 	var id string
 	id, err := c.createZone(domain)
-	fmt.Printf("Added zone for %s to Cloudflare account: %s\n", domain, id)
+	printer.Printf("Added zone for %s to Cloudflare account: %s\n", domain, id)
 	return err
 }
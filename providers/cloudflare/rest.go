@@ -149,6 +149,13 @@ func cfCaaData(rec *models.RecordConfig) *cfRecData {
 	}
 }
 
+func cfURIData(rec *models.RecordConfig) *cfRecData {
+	return &cfRecData{
+		Weight: rec.UriWeight,
+		Target: rec.GetTargetField(),
+	}
+}
+
 func cfTlsaData(rec *models.RecordConfig) *cfRecData {
 	return &cfRecData{
 		Usage:         rec.TlsaUsage,
@@ -174,6 +181,7 @@ func (c *CloudflareApi) createRec(rec *models.RecordConfig, domainID string) []*
 		TTL      uint32     `json:"ttl"`
 		Priority uint16     `json:"priority"`
 		Data     *cfRecData `json:"data"`
+		Comment  string     `json:"comment,omitempty"`
 	}
 	var id string
 	content := rec.GetTargetField()
@@ -184,8 +192,12 @@ func (c *CloudflareApi) createRec(rec *models.RecordConfig, domainID string) []*
 	if rec.Type == "MX" {
 		prio = fmt.Sprintf(" %d ", rec.MxPreference)
 	}
+	note := ""
+	if rec.Note() != "" {
+		note = " note=" + rec.Note()
+	}
 	arr := []*models.Correction{{
-		Msg: fmt.Sprintf("CREATE record: %s %s %d%s %s", rec.GetLabel(), rec.Type, rec.TTL, prio, content),
+		Msg: fmt.Sprintf("CREATE record: %s %s %d%s %s%s", rec.GetLabel(), rec.Type, rec.TTL, prio, content, note),
 		F: func() error {
 
 			cf := &createRecord{
@@ -194,6 +206,7 @@ func (c *CloudflareApi) createRec(rec *models.RecordConfig, domainID string) []*
 				TTL:      rec.TTL,
 				Content:  content,
 				Priority: rec.MxPreference,
+				Comment:  rec.Note(),
 			}
 			if rec.Type == "SRV" {
 				cf.Data = cfSrvData(rec)
@@ -208,6 +221,11 @@ func (c *CloudflareApi) createRec(rec *models.RecordConfig, domainID string) []*
 			} else if rec.Type == "SSHFP" {
 				cf.Data = cfSshfpData(rec)
 				cf.Name = rec.GetLabelFQDN()
+			} else if rec.Type == "URI" {
+				cf.Data = cfURIData(rec)
+				cf.Name = rec.GetLabelFQDN()
+				cf.Content = ""
+				cf.Priority = rec.UriPriority
 			}
 			endpoint := fmt.Sprintf(recordsURL, domainID)
 			buf := &bytes.Buffer{}
@@ -246,6 +264,7 @@ func (c *CloudflareApi) modifyRecord(domainID, recID string, proxied bool, rec *
 		Priority uint16     `json:"priority"`
 		TTL      uint32     `json:"ttl"`
 		Data     *cfRecData `json:"data"`
+		Comment  string     `json:"comment"`
 	}
 	r := record{
 		ID:       recID,
@@ -256,6 +275,7 @@ func (c *CloudflareApi) modifyRecord(domainID, recID string, proxied bool, rec *
 		Priority: rec.MxPreference,
 		TTL:      rec.TTL,
 		Data:     nil,
+		Comment:  rec.Note(),
 	}
 	if rec.Type == "SRV" {
 		r.Data = cfSrvData(rec)
@@ -270,6 +290,11 @@ func (c *CloudflareApi) modifyRecord(domainID, recID string, proxied bool, rec *
 	} else if rec.Type == "SSHFP" {
 		r.Data = cfSshfpData(rec)
 		r.Name = rec.GetLabelFQDN()
+	} else if rec.Type == "URI" {
+		r.Data = cfURIData(rec)
+		r.Name = rec.GetLabelFQDN()
+		r.Content = ""
+		r.Priority = rec.UriPriority
 	}
 	endpoint := fmt.Sprintf(singleRecordURL, domainID, recID)
 	buf := &bytes.Buffer{}
@@ -336,6 +361,45 @@ func (c *CloudflareApi) getUniversalSSL(domainID string) (bool, error) {
 	return result.Result.Enabled, err
 }
 
+// get the DS record of the key a zone is signed with, if it is
+func (c *CloudflareApi) getDNSSEC(domainID string) ([]*models.DSRecord, error) {
+	type dnssecResponse struct {
+		Success bool          `json:"success"`
+		Errors  []interface{} `json:"errors"`
+		Result  struct {
+			Status     string `json:"status"`
+			KeyTag     uint16 `json:"key_tag"`
+			Algorithm  string `json:"algorithm"`
+			DigestType string `json:"digest_type"`
+			Digest     string `json:"digest"`
+		} `json:"result"`
+	}
+
+	endpoint := fmt.Sprintf(zonesURL+"%s/dnssec", domainID)
+	var result dnssecResponse
+	if err := c.get(endpoint, &result); err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return nil, errors.Errorf("Error fetching DNSSEC status from cloudflare: %s", stringifyErrors(result.Errors))
+	}
+	// "pending" means that Cloudflare signs the zone, and waits for the DS
+	// record to be published.
+	r := result.Result
+	if r.Status != "active" && r.Status != "pending" {
+		return nil, nil
+	}
+	algorithm, err := strconv.ParseUint(r.Algorithm, 10, 8)
+	if err != nil {
+		return nil, errors.Errorf("Unknown DNSSEC algorithm %q from cloudflare", r.Algorithm)
+	}
+	digestType, err := strconv.ParseUint(r.DigestType, 10, 8)
+	if err != nil {
+		return nil, errors.Errorf("Unknown DNSSEC digest type %q from cloudflare", r.DigestType)
+	}
+	return []*models.DSRecord{{KeyTag: r.KeyTag, Algorithm: uint8(algorithm), DigestType: uint8(digestType), Digest: r.Digest}}, nil
+}
+
 // common error handling for all action responses
 func handleActionResponse(resp *http.Response, err error) (id string, e error) {
 	if err != nil {
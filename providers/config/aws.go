@@ -0,0 +1,113 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/aws/aws-sdk-go/private/protocol/jsonrpc"
+	"github.com/pkg/errors"
+)
+
+// The AWS SDK vendored here has no Secrets Manager or SSM client, so the
+// two calls needed are made with the SDK's JSON protocol handlers. The
+// session finds credentials the usual way: environment variables, the
+// shared config files, or the IAM role of the instance or task.
+
+var awsSession *session.Session
+
+// awsEndpoint overrides the endpoint of the AWS services. Tests set it.
+var awsEndpoint string
+
+func awsCall(service, targetPrefix, apiVersion, op, region string, in, out interface{}) error {
+	if awsSession == nil {
+		sess, err := session.NewSessionWithOptions(session.Options{SharedConfigState: session.SharedConfigEnable})
+		if err != nil {
+			return errors.Wrap(err, "creating AWS session")
+		}
+		awsSession = sess
+	}
+	cfg := aws.NewConfig()
+	if region != "" {
+		cfg.WithRegion(region)
+	}
+	if awsEndpoint != "" {
+		cfg.WithEndpoint(awsEndpoint)
+	}
+	c := awsSession.ClientConfig(service, cfg)
+	if aws.StringValue(c.Config.Region) == "" {
+		return errors.Errorf("no AWS region: set AWS_REGION or use an ARN")
+	}
+	svc := client.New(*c.Config, metadata.ClientInfo{
+		ServiceName:   service,
+		SigningName:   c.SigningName,
+		SigningRegion: c.SigningRegion,
+		Endpoint:      c.Endpoint,
+		APIVersion:    apiVersion,
+		JSONVersion:   "1.1",
+		TargetPrefix:  targetPrefix,
+	}, c.Handlers)
+	svc.Handlers.Sign.PushBackNamed(v4.SignRequestHandler)
+	svc.Handlers.Build.PushBackNamed(jsonrpc.BuildHandler)
+	svc.Handlers.Unmarshal.PushBackNamed(jsonrpc.UnmarshalHandler)
+	svc.Handlers.UnmarshalMeta.PushBackNamed(jsonrpc.UnmarshalMetaHandler)
+	svc.Handlers.UnmarshalError.PushBackNamed(jsonrpc.UnmarshalErrorHandler)
+	return svc.NewRequest(&request.Operation{Name: op, HTTPMethod: "POST", HTTPPath: "/"}, in, out).Send()
+}
+
+// arnRegion returns the region of an ARN, or "" if ref is not one.
+func arnRegion(ref string) string {
+	parts := strings.SplitN(ref, ":", 6)
+	if len(parts) == 6 && parts[0] == "arn" {
+		return parts[3]
+	}
+	return ""
+}
+
+type getSecretValueInput struct {
+	SecretId *string
+}
+
+type getSecretValueOutput struct {
+	SecretString *string
+}
+
+// readAWSSecret reads a secret of AWS Secrets Manager, by name or ARN.
+func readAWSSecret(ref string) (string, error) {
+	out := &getSecretValueOutput{}
+	if err := awsCall("secretsmanager", "secretsmanager", "2017-10-17", "GetSecretValue", arnRegion(ref), &getSecretValueInput{SecretId: aws.String(ref)}, out); err != nil {
+		return "", errors.Wrapf(err, "reading AWS secret %s", ref)
+	}
+	if out.SecretString == nil {
+		return "", errors.Errorf("AWS secret %s is binary, only string secrets are supported", ref)
+	}
+	return *out.SecretString, nil
+}
+
+type getParameterInput struct {
+	Name           *string
+	WithDecryption *bool
+}
+
+type getParameterOutput struct {
+	Parameter *struct {
+		Value *string
+	}
+}
+
+// readAWSParameter reads a parameter of the SSM Parameter Store, by name
+// or ARN. SecureString parameters are decrypted.
+func readAWSParameter(ref string) (string, error) {
+	out := &getParameterOutput{}
+	if err := awsCall("ssm", "AmazonSSM", "2014-11-06", "GetParameter", arnRegion(ref), &getParameterInput{Name: aws.String(ref), WithDecryption: aws.Bool(true)}, out); err != nil {
+		return "", errors.Wrapf(err, "reading SSM parameter %s", ref)
+	}
+	if out.Parameter == nil || out.Parameter.Value == nil {
+		return "", errors.Errorf("SSM parameter %s has no value", ref)
+	}
+	return *out.Parameter.Value, nil
+}
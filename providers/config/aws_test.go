@@ -0,0 +1,68 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestAWSSecretRefs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		in := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&in)
+		switch r.Header.Get("X-Amz-Target") {
+		case "secretsmanager.GetSecretValue":
+			if in["SecretId"] != "dns/r53" {
+				w.WriteHeader(400)
+				w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"not found"}`))
+				return
+			}
+			w.Write([]byte(`{"SecretString":"{\"KeyId\":\"id\",\"SecretKey\":\"secret\"}"}`))
+		case "AmazonSSM.GetParameter":
+			if in["WithDecryption"] != true {
+				t.Errorf("expected WithDecryption, got %v", in)
+			}
+			w.Write([]byte(`{"Parameter":{"Name":"/dns/token","Value":"token"}}`))
+		default:
+			t.Errorf("unexpected target %q", r.Header.Get("X-Amz-Target"))
+		}
+	}))
+	defer srv.Close()
+	defer func() { awsEndpoint, awsSession = "", nil }()
+	awsEndpoint, awsSession = srv.URL, nil
+	for k, v := range map[string]string{"AWS_ACCESS_KEY_ID": "AKID", "AWS_SECRET_ACCESS_KEY": "SECRET", "AWS_REGION": "us-east-1"} {
+		defer os.Setenv(k, os.Getenv(k))
+		os.Setenv(k, v)
+	}
+
+	m := map[string]map[string]string{
+		"r53":        {"KeyId": "awssm:dns/r53#KeyId", "SecretKey": "awssm:dns/r53#SecretKey"},
+		"cloudflare": {"apitoken": "awsssm:/dns/token", "apiuser": "me:you"},
+	}
+	if err := replaceSecretRefs(m); err != nil {
+		t.Fatal(err)
+	}
+	if got := m["r53"]; got["KeyId"] != "id" || got["SecretKey"] != "secret" {
+		t.Errorf("r53: got %v", got)
+	}
+	if got := m["cloudflare"]; got["apitoken"] != "token" || got["apiuser"] != "me:you" {
+		t.Errorf("cloudflare: got %v", got)
+	}
+
+	for _, v := range []string{"awssm:dns/missing", "awssm:dns/r53#Token"} {
+		if err := replaceSecretRefs(map[string]map[string]string{"r53": {"KeyId": v}}); err == nil {
+			t.Errorf("%s: expected an error", v)
+		}
+	}
+}
+
+func TestARNRegion(t *testing.T) {
+	if r := arnRegion("arn:aws:secretsmanager:eu-west-1:123456789012:secret:dns-AbCdEf"); r != "eu-west-1" {
+		t.Errorf("got %q", r)
+	}
+	if r := arnRegion("dns/r53"); r != "" {
+		t.Errorf("got %q", r)
+	}
+}
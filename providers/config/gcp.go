@@ -0,0 +1,79 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	gauth "golang.org/x/oauth2/google"
+)
+
+// gcpSecretManagerURL is the Secret Manager API endpoint. Tests replace it.
+var gcpSecretManagerURL = "https://secretmanager.googleapis.com/v1/"
+
+// gcpClient returns an HTTP client authorized with the Application
+// Default Credentials. Tests replace it.
+var gcpClient = func() (*http.Client, error) {
+	return gauth.DefaultClient(context.Background(), "https://www.googleapis.com/auth/cloud-platform")
+}
+
+// gcpSecretName expands a gcpsm: reference to the full name of a secret
+// version. "project/secret" and "project/secret/version" are short for
+// "projects/project/secrets/secret/versions/version"; the version
+// defaults to "latest".
+func gcpSecretName(ref string) (string, error) {
+	if strings.HasPrefix(ref, "projects/") {
+		if !strings.Contains(ref, "/versions/") {
+			ref += "/versions/latest"
+		}
+		return ref, nil
+	}
+	parts := strings.Split(ref, "/")
+	switch len(parts) {
+	case 2:
+		parts = append(parts, "latest")
+	case 3:
+	default:
+		return "", errors.Errorf("%q is not a secret: use project/secret[/version] or projects/.../secrets/...", ref)
+	}
+	return "projects/" + parts[0] + "/secrets/" + parts[1] + "/versions/" + parts[2], nil
+}
+
+// readGCPSecret reads a secret version of GCP Secret Manager.
+func readGCPSecret(ref string) (string, error) {
+	name, err := gcpSecretName(ref)
+	if err != nil {
+		return "", err
+	}
+	c, err := gcpClient()
+	if err != nil {
+		return "", errors.Wrap(err, "finding the GCP Application Default Credentials")
+	}
+	resp, err := c.Get(gcpSecretManagerURL + name + ":access")
+	if err != nil {
+		return "", errors.Wrapf(err, "reading GCP secret %s", name)
+	}
+	defer resp.Body.Close()
+	var out struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", errors.Wrapf(err, "reading GCP secret %s", name)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("reading GCP secret %s: %s %s", name, resp.Status, out.Error.Message)
+	}
+	b, err := base64.StdEncoding.DecodeString(out.Payload.Data)
+	if err != nil {
+		return "", errors.Wrapf(err, "reading GCP secret %s", name)
+	}
+	return string(b), nil
+}
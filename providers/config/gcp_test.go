@@ -0,0 +1,49 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGCPSecretName(t *testing.T) {
+	for ref, want := range map[string]string{
+		"dns-prod/r53":                             "projects/dns-prod/secrets/r53/versions/latest",
+		"dns-prod/r53/4":                           "projects/dns-prod/secrets/r53/versions/4",
+		"projects/dns-prod/secrets/r53":            "projects/dns-prod/secrets/r53/versions/latest",
+		"projects/dns-prod/secrets/r53/versions/2": "projects/dns-prod/secrets/r53/versions/2",
+		"r53": "",
+	} {
+		got, err := gcpSecretName(ref)
+		if got != want || (err != nil) != (want == "") {
+			t.Errorf("%s: got %q %v, want %q", ref, got, err, want)
+		}
+	}
+}
+
+func TestGCPSecretRefs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/projects/dns-prod/secrets/cloudflare/versions/latest:access" {
+			w.WriteHeader(404)
+			w.Write([]byte(`{"error":{"message":"Secret not found"}}`))
+			return
+		}
+		// base64 of {"apitoken":"token"}
+		w.Write([]byte(`{"name":"x","payload":{"data":"eyJhcGl0b2tlbiI6InRva2VuIn0="}}`))
+	}))
+	defer srv.Close()
+	defer func(u string, c func() (*http.Client, error)) { gcpSecretManagerURL, gcpClient = u, c }(gcpSecretManagerURL, gcpClient)
+	gcpSecretManagerURL = srv.URL + "/v1/"
+	gcpClient = func() (*http.Client, error) { return srv.Client(), nil }
+
+	m := map[string]map[string]string{"cloudflare": {"apitoken": "gcpsm:dns-prod/cloudflare#apitoken"}}
+	if err := replaceSecretRefs(m); err != nil {
+		t.Fatal(err)
+	}
+	if got := m["cloudflare"]["apitoken"]; got != "token" {
+		t.Errorf("got %q", got)
+	}
+	if err := replaceSecretRefs(map[string]map[string]string{"cloudflare": {"apitoken": "gcpsm:dns-prod/missing"}}); err == nil {
+		t.Errorf("expected an error for a missing secret")
+	}
+}
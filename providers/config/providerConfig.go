@@ -2,15 +2,23 @@
 // It cleans nonstandard json features (comments and trailing commas), as well as replaces environment variable placeholders with
 // their environment variable equivalents. To reference an environment variable in your json file, simply use values in this format:
 //    "key"="$ENV_VAR_NAME"
+// An entry with a "_vault" key gets its secrets from that HashiCorp Vault path instead:
+//    "_vault"="secret/data/dns/r53"
+// and a value can reference a secret in AWS Secrets Manager, the SSM Parameter Store or GCP Secret Manager:
+//    "key"="awssm:dns/r53#SecretKey"
+//    "key"="awsssm:/dns/r53/secret-key"
+//    "key"="gcpsm:my-project/r53-secret-key"
+// A file named *.sops.json is decrypted with the sops command first. It is used in place of a missing creds.json.
 package config
 
 import (
 	"encoding/json"
-	"fmt"
 	"os"
 	"strings"
 
 	"github.com/DisposaBoy/JsonConfigReader"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/StackExchange/dnscontrol/pkg/redact"
 	"github.com/TomOnTime/utfutil"
 	"github.com/pkg/errors"
 )
@@ -18,11 +26,28 @@ import (
 // LoadProviderConfigs will open the specified file name, and parse its contents. It will replace environment variables it finds if any value matches $[A-Za-z_-0-9]+
 func LoadProviderConfigs(fname string) (map[string]map[string]string, error) {
 	var results = map[string]map[string]string{}
-	dat, err := utfutil.ReadFile(fname, utfutil.POSIX)
+	if !isSOPS(fname) {
+		// Without creds.json, use creds.sops.json if there is one.
+		if _, err := os.Stat(fname); os.IsNotExist(err) {
+			if _, err := os.Stat(sopsName(fname)); err == nil {
+				fname = sopsName(fname)
+			}
+		}
+	}
+	var dat []byte
+	var err error
+	if isSOPS(fname) {
+		dat, err = decryptSOPS(fname)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		dat, err = utfutil.ReadFile(fname, utfutil.POSIX)
+	}
 	if err != nil {
 		// no creds file is ok. Bind requires nothing for example. Individual providers will error if things not found.
 		if os.IsNotExist(err) {
-			fmt.Printf("INFO: Config file %q does not exist. Skipping.\n", fname)
+			printer.Printf("INFO: Config file %q does not exist. Skipping.\n", fname)
 			return results, nil
 		}
 		return nil, errors.Errorf("While reading provider credentials file %v: %v", fname, err)
@@ -36,9 +61,38 @@ func LoadProviderConfigs(fname string) (map[string]map[string]string, error) {
 	if err = replaceEnvVars(results); err != nil {
 		return nil, err
 	}
+	if err = replaceVaultSecrets(results); err != nil {
+		return nil, err
+	}
+	if err = replaceSecretRefs(results); err != nil {
+		return nil, err
+	}
+	for _, keys := range results {
+		for k, v := range keys {
+			if isSecret(k) {
+				redact.Add(v)
+			}
+		}
+	}
 	return results, nil
 }
 
+// secretWords are the parts of the names of the creds.json fields that
+// hold secrets, such as "apikey", "api_token" or "SecretKey".
+var secretWords = []string{"token", "secret", "password", "passwd", "key", "auth", "credential", "private"}
+
+// isSecret returns whether the field name of creds.json holds a secret.
+// The others, such as "host" or "username", are kept in the output.
+func isSecret(name string) bool {
+	name = strings.ToLower(name)
+	for _, w := range secretWords {
+		if strings.Contains(name, w) {
+			return true
+		}
+	}
+	return false
+}
+
 func replaceEnvVars(m map[string]map[string]string) error {
 	for _, keys := range m {
 		for k, v := range keys {
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/pkg/redact"
+)
+
+func TestRedactSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "creds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "creds.json")
+	creds := `{
+  "cpanel": {"host": "whm.example.net", "username": "someadmin", "token": "tok-1234567890"},
+  "r53": {"KeyId": "keyid-1234567890", "SecretKey": "secret-1234567890"},
+  "pdns": {"api_password": "pass-1234567890", "serverName": "localhost-1"}
+}`
+	if err := ioutil.WriteFile(file, []byte(creds), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadProviderConfigs(file); err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"tok-1234567890", "keyid-1234567890", "secret-1234567890", "pass-1234567890"} {
+		if redact.String(secret) != redact.Replacement {
+			t.Errorf("expected %s to be redacted", secret)
+		}
+	}
+	for _, plain := range []string{"whm.example.net", "someadmin", "localhost-1"} {
+		if redact.String(plain) != plain {
+			t.Errorf("expected %s not to be redacted", plain)
+		}
+	}
+}
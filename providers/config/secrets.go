@@ -0,0 +1,70 @@
+package config
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// secretSchemes resolve the creds.json values of the form "scheme:reference".
+var secretSchemes = map[string]func(ref string) (string, error){
+	"awssm":  readAWSSecret,
+	"awsssm": readAWSParameter,
+	"gcpsm":  readGCPSecret,
+}
+
+// replaceSecretRefs replaces the creds.json values that reference a
+// secret store with the secret. A reference ending in "#field" picks
+// that field of a secret holding a JSON object.
+func replaceSecretRefs(m map[string]map[string]string) error {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for k, v := range m[name] {
+			i := strings.Index(v, ":")
+			if i < 0 {
+				continue
+			}
+			resolve, ok := secretSchemes[v[:i]]
+			if !ok {
+				continue
+			}
+			ref, field := v[i+1:], ""
+			if j := strings.LastIndex(ref, "#"); j >= 0 {
+				ref, field = ref[:j], ref[j+1:]
+			}
+			s, err := resolve(ref)
+			if err != nil {
+				return errors.Wrapf(err, "resolving %s of %s", k, name)
+			}
+			if field != "" {
+				if s, err = jsonField(s, field); err != nil {
+					return errors.Wrapf(err, "resolving %s of %s", k, name)
+				}
+			}
+			m[name][k] = s
+		}
+	}
+	return nil
+}
+
+func jsonField(s, field string) (string, error) {
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(s), &obj); err != nil {
+		return "", errors.Errorf("the secret is not a JSON object, so it has no field %q", field)
+	}
+	v, ok := obj[field]
+	if !ok {
+		return "", errors.Errorf("the secret has no field %q", field)
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(v)
+	return string(b), err
+}
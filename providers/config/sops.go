@@ -0,0 +1,42 @@
+package config
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// sopsSuffix marks a creds file encrypted with SOPS.
+const sopsSuffix = ".sops.json"
+
+// isSOPS reports whether fname is a SOPS-encrypted creds file.
+func isSOPS(fname string) bool {
+	return strings.HasSuffix(fname, sopsSuffix)
+}
+
+// sopsName returns the name of the encrypted file that stands in for fname.
+func sopsName(fname string) string {
+	return strings.TrimSuffix(fname, ".json") + sopsSuffix
+}
+
+// decryptSOPS returns the decrypted contents of a SOPS-encrypted file.
+//
+// The SOPS library needs Go 1.13 and the SDKs of every KMS it supports,
+// so the sops command, which has to be installed separately, does the
+// work. It finds the age, PGP or KMS keys the same way it does when run
+// by hand. Tests replace decryptSOPS.
+var decryptSOPS = func(fname string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("sops", "--decrypt", "--input-type", "json", "--output-type", "json", fname)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if e, ok := err.(*exec.Error); ok && e.Err == exec.ErrNotFound {
+			return nil, errors.Errorf("decrypting %s needs the sops command (https://github.com/mozilla/sops), which is not in $PATH [https://stackexchange.github.io/dnscontrol/credentials]", fname)
+		}
+		return nil, errors.Errorf("decrypting %s: %v: %s", fname, err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
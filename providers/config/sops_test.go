@@ -0,0 +1,51 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSOPS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "creds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	enc := filepath.Join(dir, "creds.sops.json")
+	if err := ioutil.WriteFile(enc, []byte(`{"cloudflare":{"apitoken":"ENC[AES256_GCM,data:...]"},"sops":{}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	defer func(old func(string) ([]byte, error)) { decryptSOPS = old }(decryptSOPS)
+	decryptSOPS = func(fname string) ([]byte, error) {
+		if fname != enc {
+			t.Errorf("decrypting %s, want %s", fname, enc)
+		}
+		return []byte(`{"cloudflare":{"apitoken":"token"}}`), nil
+	}
+
+	// creds.json is missing, so creds.sops.json is used.
+	for _, fname := range []string{filepath.Join(dir, "creds.json"), enc} {
+		m, err := LoadProviderConfigs(fname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := m["cloudflare"]["apitoken"]; got != "token" {
+			t.Errorf("%s: got %q", fname, got)
+		}
+	}
+
+	// An existing creds.json is preferred.
+	plain := filepath.Join(dir, "creds.json")
+	if err := ioutil.WriteFile(plain, []byte(`{"cloudflare":{"apitoken":"plain"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	m, err := LoadProviderConfigs(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m["cloudflare"]["apitoken"]; got != "plain" {
+		t.Errorf("got %q", got)
+	}
+}
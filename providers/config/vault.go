@@ -0,0 +1,95 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/pkg/errors"
+)
+
+// vaultKey is the creds.json key naming the Vault path an entry's secrets are read from.
+const vaultKey = "_vault"
+
+// vaultReader reads a Vault path. Tests replace newVaultReader.
+type vaultReader interface {
+	Read(path string) (*api.Secret, error)
+}
+
+var newVaultReader = func() (vaultReader, error) {
+	client, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		return nil, errors.Wrap(err, "creating Vault client")
+	}
+	// api.NewClient takes the address and token from VAULT_ADDR and VAULT_TOKEN.
+	if os.Getenv("VAULT_TOKEN") == "" && os.Getenv("VAULT_ROLE_ID") != "" {
+		mount := os.Getenv("VAULT_APPROLE_PATH")
+		if mount == "" {
+			mount = "approle"
+		}
+		secret, err := client.Logical().Write("auth/"+mount+"/login", map[string]interface{}{
+			"role_id":   os.Getenv("VAULT_ROLE_ID"),
+			"secret_id": os.Getenv("VAULT_SECRET_ID"),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "logging in to Vault with AppRole")
+		}
+		if secret == nil || secret.Auth == nil {
+			return nil, errors.Errorf("logging in to Vault with AppRole: no token returned")
+		}
+		client.SetToken(secret.Auth.ClientToken)
+	}
+	return client.Logical(), nil
+}
+
+// replaceVaultSecrets fills in the entries of m that have a "_vault" key
+// with the fields of the Vault secret at that path. Fields set in
+// creds.json take precedence over those of the secret. Vault is only
+// contacted if an entry asks for it.
+func replaceVaultSecrets(m map[string]map[string]string) error {
+	names := []string{}
+	for name, keys := range m {
+		if _, ok := keys[vaultKey]; ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	vault, err := newVaultReader()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		keys := m[name]
+		path := keys[vaultKey]
+		delete(keys, vaultKey)
+		secret, err := vault.Read(path)
+		if err != nil {
+			return errors.Wrapf(err, "reading Vault secret %s for %s", path, name)
+		}
+		if secret == nil || secret.Data == nil {
+			return errors.Errorf("Vault secret %s for %s does not exist", path, name)
+		}
+		data := secret.Data
+		// KV version 2 nests the fields under "data", next to "metadata".
+		if inner, ok := data["data"].(map[string]interface{}); ok {
+			if _, ok := data["metadata"]; ok {
+				data = inner
+			}
+		}
+		for k, v := range data {
+			if _, ok := keys[k]; ok {
+				continue
+			}
+			if s, ok := v.(string); ok {
+				keys[k] = s
+			} else {
+				keys[k] = fmt.Sprint(v)
+			}
+		}
+	}
+	return nil
+}
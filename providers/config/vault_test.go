@@ -0,0 +1,64 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+)
+
+type fakeVault map[string]map[string]interface{}
+
+func (f fakeVault) Read(path string) (*api.Secret, error) {
+	data, ok := f[path]
+	if !ok {
+		return nil, nil
+	}
+	return &api.Secret{Data: data}, nil
+}
+
+func TestReplaceVaultSecrets(t *testing.T) {
+	defer func(old func() (vaultReader, error)) { newVaultReader = old }(newVaultReader)
+	newVaultReader = func() (vaultReader, error) {
+		return fakeVault{
+			"secret/data/r53": {
+				"data":     map[string]interface{}{"KeyId": "id", "SecretKey": "secret"},
+				"metadata": map[string]interface{}{"version": 3},
+			},
+			"kv1/cloudflare": {"apitoken": "token", "port": 8443},
+		}, nil
+	}
+
+	m := map[string]map[string]string{
+		"r53":        {"_vault": "secret/data/r53", "KeyId": "override"},
+		"cloudflare": {"_vault": "kv1/cloudflare"},
+		"bind":       {"directory": "zones"},
+	}
+	if err := replaceVaultSecrets(m); err != nil {
+		t.Fatal(err)
+	}
+	if got := m["r53"]; len(got) != 2 || got["KeyId"] != "override" || got["SecretKey"] != "secret" {
+		t.Errorf("r53: got %v", got)
+	}
+	if got := m["cloudflare"]; len(got) != 2 || got["apitoken"] != "token" || got["port"] != "8443" {
+		t.Errorf("cloudflare: got %v", got)
+	}
+	if got := m["bind"]; len(got) != 1 {
+		t.Errorf("bind: got %v", got)
+	}
+
+	m = map[string]map[string]string{"r53": {"_vault": "secret/data/missing"}}
+	if err := replaceVaultSecrets(m); err == nil {
+		t.Errorf("expected an error for a missing secret")
+	}
+}
+
+func TestReplaceVaultSecretsUnused(t *testing.T) {
+	defer func(old func() (vaultReader, error)) { newVaultReader = old }(newVaultReader)
+	newVaultReader = func() (vaultReader, error) {
+		t.Fatal("Vault contacted without a _vault entry")
+		return nil, nil
+	}
+	if err := replaceVaultSecrets(map[string]map[string]string{"bind": {}}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,162 @@
+package cpanel
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The WHM JSON API (version 1). See
+// https://documentation.cpanel.net/display/DD/Guide+to+WHM+API+1
+
+type whmMetadata struct {
+	Result int    `json:"result"`
+	Reason string `json:"reason"`
+}
+
+type zoneRecord struct {
+	Line       int    `json:"Line"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	TTL        uint32 `json:"ttl"`
+	Address    string `json:"address,omitempty"`
+	Cname      string `json:"cname,omitempty"`
+	Exchange   string `json:"exchange,omitempty"`
+	Preference uint16 `json:"preference,omitempty"`
+	Nsdname    string `json:"nsdname,omitempty"`
+	Ptrdname   string `json:"ptrdname,omitempty"`
+	Txtdata    string `json:"txtdata,omitempty"`
+	Target     string `json:"target,omitempty"`
+	Priority   uint16 `json:"priority,omitempty"`
+	Weight     uint16 `json:"weight,omitempty"`
+	Port       uint16 `json:"port,omitempty"`
+	Flag       uint8  `json:"flag,omitempty"`
+	Tag        string `json:"tag,omitempty"`
+	Value      string `json:"value,omitempty"`
+}
+
+// call invokes a WHM API function and decodes its "data" into target (if not nil).
+func (api *CPanel) call(function string, params url.Values, target interface{}) error {
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("api.version", "1")
+	req, err := http.NewRequest(http.MethodGet, api.baseURL+"/json-api/"+function+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "whm "+api.username+":"+api.token)
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("cPanel: bad status code %d calling %s", resp.StatusCode, function)
+	}
+	var r struct {
+		Metadata whmMetadata     `json:"metadata"`
+		Data     json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return errors.Wrapf(err, "cPanel: decoding response of %s", function)
+	}
+	if r.Metadata.Result != 1 {
+		return errors.Errorf("cPanel: %s: %s", function, r.Metadata.Reason)
+	}
+	if target == nil || len(r.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(r.Data, target)
+}
+
+// listZones returns the names of all zones on the server, across all accounts.
+func (api *CPanel) listZones() ([]string, error) {
+	var data struct {
+		Zone []struct {
+			Domain string `json:"domain"`
+		} `json:"zone"`
+	}
+	if err := api.call("listzones", nil, &data); err != nil {
+		return nil, err
+	}
+	zones := []string{}
+	for _, z := range data.Zone {
+		zones = append(zones, z.Domain)
+	}
+	return zones, nil
+}
+
+func (api *CPanel) dumpZone(domain string) ([]*zoneRecord, error) {
+	var data struct {
+		Zone []struct {
+			Record []*zoneRecord `json:"record"`
+		} `json:"zone"`
+	}
+	if err := api.call("dumpzone", url.Values{"domain": {domain}}, &data); err != nil {
+		return nil, err
+	}
+	if len(data.Zone) == 0 {
+		return nil, errors.Errorf("cPanel: zone %s not found", domain)
+	}
+	return data.Zone[0].Record, nil
+}
+
+func (api *CPanel) addRecord(domain string, r *zoneRecord) error {
+	params := r.values()
+	params.Set("domain", domain)
+	return api.call("addzonerecord", params, nil)
+}
+
+func (api *CPanel) editRecord(domain string, r *zoneRecord) error {
+	params := r.values()
+	params.Set("domain", domain)
+	params.Set("line", strconv.Itoa(r.Line))
+	return api.call("editzonerecord", params, nil)
+}
+
+func (api *CPanel) removeRecord(domain string, line int) error {
+	return api.call("removezonerecord", url.Values{"zone": {domain}, "line": {strconv.Itoa(line)}}, nil)
+}
+
+// values returns the parameters addzonerecord and editzonerecord expect. #rtype_variations
+func (r *zoneRecord) values() url.Values {
+	v := url.Values{}
+	v.Set("name", r.Name)
+	v.Set("type", r.Type)
+	v.Set("class", "IN")
+	v.Set("ttl", strconv.FormatUint(uint64(r.TTL), 10))
+	switch r.Type {
+	case "A", "AAAA":
+		v.Set("address", r.Address)
+	case "CAA":
+		v.Set("flag", strconv.Itoa(int(r.Flag)))
+		v.Set("tag", r.Tag)
+		v.Set("value", r.Value)
+	case "CNAME":
+		v.Set("cname", r.Cname)
+	case "MX":
+		v.Set("exchange", r.Exchange)
+		v.Set("preference", strconv.Itoa(int(r.Preference)))
+	case "NS":
+		v.Set("nsdname", r.Nsdname)
+	case "PTR":
+		v.Set("ptrdname", r.Ptrdname)
+	case "SRV":
+		v.Set("priority", strconv.Itoa(int(r.Priority)))
+		v.Set("weight", strconv.Itoa(int(r.Weight)))
+		v.Set("port", strconv.Itoa(int(r.Port)))
+		v.Set("target", r.Target)
+	case "TXT":
+		v.Set("txtdata", r.Txtdata)
+	}
+	return v
+}
+
+func newClient() *http.Client {
+	return &http.Client{Timeout: 60 * time.Second}
+}
@@ -0,0 +1,252 @@
+package cpanel
+
+/*
+
+cPanel/WHM DNS provider:
+
+	Uses the WHM JSON API, which can edit the zones of every account on
+	the server.  This makes it possible to manage the zones of many
+	cPanel accounts (for example, an agency's clients) with one token.
+
+Info required in `creds.json`:
+   - host (for example https://server.example.com:2087)
+   - token (a WHM API token)
+   - username (optional, defaults to root)
+
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/providers"
+	"github.com/StackExchange/dnscontrol/providers/diff"
+	"github.com/pkg/errors"
+)
+
+var features = providers.DocumentationNotes{
+	providers.CanUseAlias:            providers.Cannot(),
+	providers.CanUseCAA:              providers.Can(),
+	providers.CanUsePTR:              providers.Can(),
+	providers.CanUseNAPTR:            providers.Cannot(),
+	providers.CanUseSRV:              providers.Can(),
+	providers.CanUseSSHFP:            providers.Cannot(),
+	providers.CanUseTLSA:             providers.Cannot(),
+	providers.CanUseTXTMulti:         providers.Cannot(),
+	providers.CantReorderCorrections: providers.Can("Records are addressed by their line number in the zone file"),
+	providers.DocCreateDomains:       providers.Cannot("Zones are created along with the cPanel account or addon domain"),
+	providers.DocOfficiallySupported: providers.Cannot(),
+}
+
+func init() {
+	providers.RegisterDomainServiceProviderType("CPANEL", newCPanel, features)
+}
+
+// CPanel is the handle for this provider.
+type CPanel struct {
+	baseURL     string
+	username    string
+	token       string
+	nameservers []*models.Nameserver
+	client      *http.Client
+}
+
+func newCPanel(m map[string]string, metadata json.RawMessage) (providers.DNSServiceProvider, error) {
+	api := &CPanel{
+		baseURL:  strings.TrimSuffix(m["host"], "/"),
+		username: m["username"],
+		token:    m["token"],
+		client:   newClient(),
+	}
+	if api.baseURL == "" || api.token == "" {
+		return nil, errors.Errorf("cPanel: host and token are required")
+	}
+	if api.username == "" {
+		api.username = "root"
+	}
+	if m["nameservers"] != "" {
+		api.nameservers = models.StringsToNameservers(strings.Split(m["nameservers"], ","))
+	}
+	return api, nil
+}
+
+// GetNameservers returns the nameservers configured in creds.json, if any.
+// They differ from server to server so there is no default.
+func (api *CPanel) GetNameservers(domain string) ([]*models.Nameserver, error) {
+	return api.nameservers, nil
+}
+
+// ListZones returns all zones on the server, across all cPanel accounts.
+func (api *CPanel) ListZones() ([]string, error) {
+	return api.listZones()
+}
+
+// GetZoneRecords returns the records of a zone, except the SOA.
+func (api *CPanel) GetZoneRecords(domain string) (models.Records, error) {
+	records, err := api.dumpZone(domain)
+	if err != nil {
+		return nil, err
+	}
+	existing := models.Records{}
+	for _, r := range records {
+		if rc, ok := toRecordConfig(domain, r); ok {
+			existing = append(existing, rc)
+		}
+	}
+	models.PostProcessRecords(existing)
+	return existing, nil
+}
+
+// GetDomainCorrections returns a list of corrections to update a domain.
+func (api *CPanel) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	dc.Punycode()
+
+	records, err := api.dumpZone(dc.Name)
+	if err != nil {
+		return nil, err
+	}
+	existing := []*models.RecordConfig{}
+	for _, r := range records {
+		rc, ok := toRecordConfig(dc.Name, r)
+		if ok {
+			existing = append(existing, rc)
+		}
+	}
+	existing = withoutApexNS(dc, existing)
+	models.PostProcessRecords(existing)
+
+	differ := diff.New(dc)
+	_, create, del, mod := differ.IncrementalDiff(existing)
+
+	// Records are addressed by their line number in the zone file.
+	// Edits keep the numbering intact, so do them first.  Then delete
+	// from the bottom up, and finally append the new records.
+	corrections := []*models.Correction{}
+	for _, m := range mod {
+		r := toZoneRecord(m.Desired)
+		r.Line = m.Existing.Original.(*zoneRecord).Line
+		corrections = append(corrections, &models.Correction{
+			Msg:     fmt.Sprintf("%s, line %d", m, r.Line),
+			Records: m.Records(),
+			F:       func() error { return api.editRecord(dc.Name, r) },
+		})
+	}
+	sort.Slice(del, func(i, j int) bool {
+		return del[i].Existing.Original.(*zoneRecord).Line > del[j].Existing.Original.(*zoneRecord).Line
+	})
+	for _, m := range del {
+		line := m.Existing.Original.(*zoneRecord).Line
+		corrections = append(corrections, &models.Correction{
+			Msg:     fmt.Sprintf("%s, line %d", m, line),
+			Records: m.Records(),
+			F:       func() error { return api.removeRecord(dc.Name, line) },
+		})
+	}
+	for _, m := range create {
+		r := toZoneRecord(m.Desired)
+		corrections = append(corrections, &models.Correction{
+			Msg:     m.String(),
+			Records: m.Records(),
+			F:       func() error { return api.addRecord(dc.Name, r) },
+		})
+	}
+	return corrections, nil
+}
+
+// withoutApexNS removes the apex NS records from existing, unless
+// dnsconfig.js has some. Without "nameservers" in creds.json, dnscontrol
+// doesn't know the nameservers of the server, and would delete them.
+func withoutApexNS(dc *models.DomainConfig, existing []*models.RecordConfig) []*models.RecordConfig {
+	for _, r := range dc.Records {
+		if r.Type == "NS" && r.GetLabel() == "@" {
+			return existing
+		}
+	}
+	filtered := []*models.RecordConfig{}
+	for _, r := range existing {
+		if !(r.Type == "NS" && r.GetLabel() == "@") {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// toRecordConfig converts a WHM zone record to a RecordConfig. It returns
+// false for entries we don't manage (SOA, $TTL lines, comments). #rtype_variations
+func toRecordConfig(origin string, r *zoneRecord) (*models.RecordConfig, bool) {
+	rc := &models.RecordConfig{
+		Type:     r.Type,
+		TTL:      r.TTL,
+		Original: r,
+	}
+	rc.SetLabelFromFQDN(strings.TrimSuffix(r.Name, "."), origin)
+	var err error
+	switch r.Type {
+	case "A":
+		err = rc.SetTarget(r.Address)
+	case "AAAA":
+		err = rc.SetTarget(r.Address)
+	case "CAA":
+		err = rc.SetTargetCAA(r.Flag, r.Tag, r.Value)
+	case "CNAME":
+		err = rc.SetTarget(fqdn(r.Cname))
+	case "MX":
+		err = rc.SetTargetMX(r.Preference, fqdn(r.Exchange))
+	case "NS":
+		err = rc.SetTarget(fqdn(r.Nsdname))
+	case "PTR":
+		err = rc.SetTarget(fqdn(r.Ptrdname))
+	case "SRV":
+		err = rc.SetTargetSRV(r.Priority, r.Weight, r.Port, fqdn(r.Target))
+	case "TXT":
+		err = rc.SetTargetTXT(r.Txtdata)
+	default:
+		return nil, false
+	}
+	if err != nil {
+		panic(errors.Wrap(err, "unparsable record received from cPanel"))
+	}
+	return rc, true
+}
+
+// toZoneRecord converts a RecordConfig to a WHM zone record. #rtype_variations
+func toZoneRecord(rc *models.RecordConfig) *zoneRecord {
+	r := &zoneRecord{
+		Name: rc.GetLabelFQDN() + ".",
+		Type: rc.Type,
+		TTL:  rc.TTL,
+	}
+	target := rc.GetTargetField()
+	switch rc.Type {
+	case "A", "AAAA":
+		r.Address = target
+	case "CAA":
+		r.Flag, r.Tag, r.Value = rc.CaaFlag, rc.CaaTag, target
+	case "CNAME":
+		r.Cname = target
+	case "MX":
+		r.Preference, r.Exchange = rc.MxPreference, target
+	case "NS":
+		r.Nsdname = target
+	case "PTR":
+		r.Ptrdname = target
+	case "SRV":
+		r.Priority, r.Weight, r.Port, r.Target = rc.SrvPriority, rc.SrvWeight, rc.SrvPort, target
+	case "TXT":
+		r.Txtdata = strings.Join(rc.TxtStrings, "")
+	default:
+		panic(errors.Errorf("cPanel: unsupported record type %s", rc.Type))
+	}
+	return r
+}
+
+func fqdn(s string) string {
+	if strings.HasSuffix(s, ".") {
+		return s
+	}
+	return s + "."
+}
@@ -0,0 +1,80 @@
+package cpanel
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/models"
+)
+
+const dump = `[
+ {"Line": 1, "type": ":RAW", "raw": "; cPanel first:11.70"},
+ {"Line": 3, "name": "example.com.", "type": "SOA", "ttl": 86400},
+ {"Line": 10, "name": "example.com.", "type": "A", "ttl": 14400, "address": "192.0.2.1"},
+ {"Line": 11, "name": "www.example.com.", "type": "CNAME", "ttl": 14400, "cname": "example.com"},
+ {"Line": 12, "name": "example.com.", "type": "MX", "ttl": 14400, "exchange": "mail.example.com", "preference": 10},
+ {"Line": 13, "name": "_sip._tcp.example.com.", "type": "SRV", "ttl": 14400, "priority": 1, "weight": 2, "port": 5060, "target": "sip.example.com"},
+ {"Line": 14, "name": "example.com.", "type": "TXT", "ttl": 14400, "txtdata": "v=spf1 -all"},
+ {"Line": 15, "name": "example.com.", "type": "CAA", "ttl": 14400, "flag": 0, "tag": "issue", "value": "letsencrypt.org"}
+]`
+
+func TestConversion(t *testing.T) {
+	var records []*zoneRecord
+	if err := json.Unmarshal([]byte(dump), &records); err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for _, r := range records {
+		rc, ok := toRecordConfig("example.com", r)
+		if !ok {
+			if r.Type != ":RAW" && r.Type != "SOA" {
+				t.Errorf("line %d (%s) was skipped", r.Line, r.Type)
+			}
+			continue
+		}
+		count++
+		back := toZoneRecord(rc)
+		if back.Name != r.Name || back.TTL != r.TTL {
+			t.Errorf("line %d: name/ttl mismatch: %+v", r.Line, back)
+		}
+		if got, want := back.values().Encode(), expectedValues(r); got != want {
+			t.Errorf("line %d: expected %s, got %s", r.Line, want, got)
+		}
+	}
+	if count != 6 {
+		t.Errorf("expected 6 records, got %d", count)
+	}
+}
+
+// expectedValues returns the parameters for r, with hostnames made absolute.
+func expectedValues(r *zoneRecord) string {
+	c := *r
+	for _, s := range []*string{&c.Cname, &c.Exchange, &c.Target} {
+		if *s != "" {
+			*s = fqdn(*s)
+		}
+	}
+	return c.values().Encode()
+}
+
+func TestWithoutApexNS(t *testing.T) {
+	rec := func(rtype, label, target string) *models.RecordConfig {
+		rc := &models.RecordConfig{Type: rtype}
+		rc.SetLabel(label, "example.com")
+		rc.SetTarget(target)
+		return rc
+	}
+	existing := []*models.RecordConfig{
+		rec("NS", "@", "ns1.whm.example.net."),
+		rec("NS", "sub", "ns1.example.org."),
+		rec("A", "@", "192.0.2.1"),
+	}
+	dc := &models.DomainConfig{Name: "example.com", Records: []*models.RecordConfig{rec("A", "@", "192.0.2.1")}}
+	if got := withoutApexNS(dc, existing); len(got) != 2 || got[0].GetLabel() != "sub" {
+		t.Errorf("expected only the apex NS record to be left alone, got %d records", len(got))
+	}
+	dc.Records = append(dc.Records, rec("NS", "@", "ns1.example.org."))
+	if got := withoutApexNS(dc, existing); len(got) != 3 {
+		t.Errorf("expected the apex NS records to be managed, got %d records", len(got))
+	}
+}
@@ -3,10 +3,10 @@ package diff
 import (
 	"fmt"
 	"sort"
-
-	"github.com/gobwas/glob"
+	"sync"
 
 	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/ownership"
 	"github.com/StackExchange/dnscontrol/pkg/printer"
 )
 
@@ -23,6 +23,7 @@ type Changeset []Correlation
 // Differ is an interface for computing the difference between two zones.
 type Differ interface {
 	// IncrementalDiff performs a diff on a record-by-record basis, and returns a sets for which records need to be created, deleted, or modified.
+	// Within each set, changes hinted "first" by PRIORITY_HINT come first and changes hinted "last" come last.
 	IncrementalDiff(existing []*models.RecordConfig) (unchanged, create, toDelete, modify Changeset)
 	// ChangedGroups performs a diff more appropriate for providers with a "RecordSet" model, where all records with the same name and type are grouped.
 	// Individual record changes are often not useful in such scenarios. Instead we return a map of record keys to a list of change descriptions within that group.
@@ -31,20 +32,23 @@ type Differ interface {
 
 // New is a constructor for a Differ.
 func New(dc *models.DomainConfig, extraValues ...func(*models.RecordConfig) map[string]string) Differ {
+	// The patterns were checked by validation.
+	ignores, err := CompileIgnores(dc)
+	if err != nil {
+		panic(err)
+	}
 	return &differ{
 		dc:          dc,
 		extraValues: extraValues,
-
-		// compile IGNORE glob patterns
-		compiledIgnoredLabels: compileIgnoredLabels(dc.IgnoredLabels),
+		ignores:     ignores,
 	}
 }
 
 type differ struct {
 	dc          *models.DomainConfig
 	extraValues []func(*models.RecordConfig) map[string]string
-
-	compiledIgnoredLabels []glob.Glob
+	ignores     Ignores
+	hints       map[models.RecordKey]int // The PRIORITY_HINTs of the desired record sets.
 }
 
 // get normalized content for record. target, ttl, mxprio, and specified metadata
@@ -82,6 +86,11 @@ func (d *differ) IncrementalDiff(existing []*models.RecordConfig) (unchanged, cr
 	create = Changeset{}
 	toDelete = Changeset{}
 	modify = Changeset{}
+	if d.dc.Patch {
+		// Providers that replace record sets with those of dc.Records
+		// need to see the records that are kept, too.
+		d.dc.Records = d.patch(existing)
+	}
 	desired := d.dc.Records
 
 	// sort existing and desired by name
@@ -89,34 +98,44 @@ func (d *differ) IncrementalDiff(existing []*models.RecordConfig) (unchanged, cr
 	existingByNameAndType := map[models.RecordKey][]*models.RecordConfig{}
 	desiredByNameAndType := map[models.RecordKey][]*models.RecordConfig{}
 	for _, e := range existing {
-		if d.matchIgnored(e.GetLabel()) {
-			printer.Debugf("Ignoring record %s %s due to IGNORE\n", e.GetLabel(), e.Type)
+		if m := d.ignores.Match(e); m != "" {
+			printer.Debugf("Ignoring record %s %s due to %s\n", e.GetLabel(), e.Type, m)
 		} else {
 			k := e.Key()
 			existingByNameAndType[k] = append(existingByNameAndType[k], e)
 		}
 	}
 	for _, dr := range desired {
-		if d.matchIgnored(dr.GetLabel()) {
-			panic(fmt.Sprintf("Trying to update/add IGNOREd record: %s %s", dr.GetLabel(), dr.Type))
+		if m := d.ignores.Match(dr); m != "" {
+			panic(fmt.Sprintf("Trying to update/add record ignored by %s: %s %s", m, dr.GetLabel(), dr.Type))
 		} else {
 			k := dr.Key()
 			desiredByNameAndType[k] = append(desiredByNameAndType[k], dr)
 		}
 	}
-	// if NO_PURGE is set, just remove anything that is only in existing.
+	// if NO_PURGE is set, just remove anything that is only in existing,
+	// except what ENSURE_ABSENT deletes.
 	if d.dc.KeepUnknown {
-		for k := range existingByNameAndType {
+		for k, records := range existingByNameAndType {
 			if _, ok := desiredByNameAndType[k]; !ok {
+				if absent := d.absent(records); len(absent) != 0 {
+					existingByNameAndType[k] = absent
+					continue
+				}
 				printer.Debugf("Ignoring record set %s %s due to NO_PURGE\n", k.Type, k.NameFQDN)
 				delete(existingByNameAndType, k)
 			}
 		}
 	}
+	// if OWNER is set, leave alone what is owned by someone else.
+	if d.dc.Owner != "" {
+		d.checkOwnership(existing, existingByNameAndType, desiredByNameAndType)
+	}
 	// Look through existing records. This will give us changes and deletions and some additions.
 	// Each iteration is only for a single type/name record set
 	for key, existingRecords := range existingByNameAndType {
 		desiredRecords := desiredByNameAndType[key]
+		d.keepTTLs(existingRecords, desiredRecords)
 		// first look through records that are the same target on both sides. Those are either modifications or unchanged
 		for i := len(existingRecords) - 1; i >= 0; i-- {
 			ex := existingRecords[i]
@@ -192,9 +211,245 @@ func (d *differ) IncrementalDiff(existing []*models.RecordConfig) (unchanged, cr
 			create = append(create, Correlation{d, nil, rec})
 		}
 	}
+
+	// Deleted records aren't in dnsconfig.js, so they take the hint of
+	// the records that replace them.
+	d.hints = map[models.RecordKey]int{}
+	for _, dr := range desired {
+		if h := priorityHint(dr); h != 0 {
+			d.hints[dr.Key()] = h
+		}
+	}
+	sortByHint(create)
+	sortByHint(toDelete)
+	sortByHint(modify)
+	record(d.dc, create, toDelete, modify)
 	return
 }
 
+// keepTTLs gives the desired records that IGNORE_TTL the TTL of the
+// existing record with the same target, or else of the existing records,
+// so that TTL differences are not changes.
+func (d *differ) keepTTLs(existing, desired []*models.RecordConfig) {
+	if len(existing) == 0 {
+		return
+	}
+	for _, de := range desired {
+		if d.dc.Metadata["ignore_ttl"] != "true" && de.Metadata["ignore_ttl"] != "true" {
+			continue
+		}
+		de.TTL = existing[0].TTL
+		for _, ex := range existing {
+			if ex.GetTargetField() == de.GetTargetField() {
+				de.TTL = ex.TTL
+				break
+			}
+		}
+	}
+}
+
+// Result holds the changes the IncrementalDiffs of a watched domain found.
+type Result struct {
+	Create, Delete, Modify Changeset
+}
+
+var (
+	watchedMu sync.Mutex
+	watched   = map[*models.DomainConfig]*Result{}
+)
+
+// Watch makes the IncrementalDiffs (and ChangedGroups) of dc keep what
+// they find until Unwatch, so the changes of a provider can be reported
+// as data instead of correction messages.
+func Watch(dc *models.DomainConfig) {
+	watchedMu.Lock()
+	defer watchedMu.Unlock()
+	watched[dc] = &Result{}
+}
+
+// Unwatch stops watching dc, and returns what its diffs found since Watch.
+func Unwatch(dc *models.DomainConfig) *Result {
+	watchedMu.Lock()
+	defer watchedMu.Unlock()
+	r := watched[dc]
+	delete(watched, dc)
+	return r
+}
+
+func record(dc *models.DomainConfig, create, toDelete, modify Changeset) {
+	watchedMu.Lock()
+	defer watchedMu.Unlock()
+	if r, ok := watched[dc]; ok {
+		r.Create = append(r.Create, create...)
+		r.Delete = append(r.Delete, toDelete...)
+		r.Modify = append(r.Modify, modify...)
+	}
+}
+
+// priorityHint ranks a record by its PRIORITY_HINT: -1 for "first", 1 for "last" and 0 otherwise.
+func priorityHint(r *models.RecordConfig) int {
+	switch r.Metadata["priority_hint"] {
+	case "first":
+		return -1
+	case "last":
+		return 1
+	}
+	return 0
+}
+
+// hint ranks the change like priorityHint. A deletion takes the hint of
+// the records that replace it.
+func (c Correlation) hint() int {
+	if c.Desired != nil {
+		return priorityHint(c.Desired)
+	}
+	return c.d.hints[c.Existing.Key()]
+}
+
+func sortByHint(cs Changeset) {
+	sort.SliceStable(cs, func(i, j int) bool { return cs[i].hint() < cs[j].hint() })
+}
+
+// SortCorrections orders the corrections of a provider by the
+// PRIORITY_HINT of their changes, which r holds, across creations,
+// deletions and modifications. The changes of a correction are those of
+// its Records. Corrections without records, or with changes of different
+// hints, keep their place among the unhinted ones.
+func SortCorrections(corrections []*models.Correction, r *Result) {
+	if r == nil {
+		return
+	}
+	hints := map[*models.RecordConfig]int{}
+	hinted := false
+	for _, cs := range []Changeset{r.Create, r.Delete, r.Modify} {
+		for _, c := range cs {
+			for _, rec := range c.Records() {
+				hints[rec] = c.hint()
+			}
+			hinted = hinted || c.hint() != 0
+		}
+	}
+	if !hinted {
+		return
+	}
+	ranks := map[*models.Correction]int{}
+	for _, correction := range corrections {
+		rank, found, mixed := 0, false, false
+		for _, rec := range correction.Records {
+			hint, ok := hints[rec]
+			if !ok {
+				continue
+			}
+			if found && rank != hint {
+				mixed = true
+			}
+			rank, found = hint, true
+		}
+		if !mixed {
+			ranks[correction] = rank
+		}
+	}
+	sort.SliceStable(corrections, func(i, j int) bool { return ranks[corrections[i]] < ranks[corrections[j]] })
+}
+
+// patch returns existing with the records of the domain added, and its
+// DeleteRecords removed. Records are the same if they have the same
+// target; the TTL and metadata of those added win.
+func (d *differ) patch(existing []*models.RecordConfig) models.Records {
+	has := func(records []*models.RecordConfig, r *models.RecordConfig) bool {
+		for _, x := range records {
+			if x.Key() == r.Key() && x.GetTargetCombined() == r.GetTargetCombined() {
+				return true
+			}
+		}
+		return false
+	}
+	var records models.Records
+	for _, e := range existing {
+		if d.ignores.Match(e) != "" || has(d.dc.Records, e) || has(d.dc.DeleteRecords, e) {
+			continue
+		}
+		records = append(records, e)
+	}
+	for _, r := range d.dc.Records {
+		if !has(d.dc.DeleteRecords, r) {
+			records = append(records, r)
+		}
+	}
+	return records
+}
+
+// absent returns the records that ENSURE_ABSENT lists.
+func (d *differ) absent(records []*models.RecordConfig) []*models.RecordConfig {
+	var absent []*models.RecordConfig
+	for _, r := range records {
+		for _, a := range d.dc.EnsureAbsent {
+			if a.Key() == r.Key() && a.GetTargetCombined() == r.GetTargetCombined() {
+				absent = append(absent, r)
+				break
+			}
+		}
+	}
+	return absent
+}
+
+// checkOwnership removes the record sets the domain's owner doesn't own
+// from existing, and from desired too if it would change them.
+func (d *differ) checkOwnership(all []*models.RecordConfig, existing, desired map[models.RecordKey][]*models.RecordConfig) {
+	owners := ownership.Owners(all)
+	// The record sets, with the markers counted as the sets they are about.
+	sets := map[models.RecordKey]bool{}
+	for k := range existing {
+		if owned, ok := ownership.Owned(k); ok {
+			k = owned
+		}
+		sets[k] = true
+	}
+	for k := range sets {
+		owner, marked := owners[k]
+		if owner == d.dc.Owner || k.Type == "SOA" {
+			continue
+		}
+		m := ownership.MarkerKey(k)
+		if !marked && len(existing[k]) != 0 && d.sameContent(existing[k], desired[k]) {
+			// Adopt it: the marker is all that changes.
+			printer.Debugf("Adopting record set %s %s for owner %s\n", k.Type, k.NameFQDN, d.dc.Owner)
+			continue
+		}
+		delete(existing, k)
+		delete(existing, m)
+		_, wanted := desired[k]
+		if _, ok := desired[m]; wanted || ok {
+			if marked {
+				printer.Warnf("Not changing %s %s: it is owned by %q\n", k.Type, k.NameFQDN, owner)
+			} else {
+				printer.Warnf("Not changing %s %s: it has no owner, and dnsconfig.js does not match it\n", k.Type, k.NameFQDN)
+			}
+			delete(desired, k)
+			delete(desired, m)
+		} else {
+			printer.Debugf("Ignoring record set %s %s owned by %q\n", k.Type, k.NameFQDN, owner)
+		}
+	}
+}
+
+// sameContent reports whether a and b are the same records.
+func (d *differ) sameContent(a, b []*models.RecordConfig) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	count := map[string]int{}
+	for _, r := range a {
+		count[d.content(r)]++
+	}
+	for _, r := range b {
+		if count[d.content(r)]--; count[d.content(r)] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
 func (d *differ) ChangedGroups(existing []*models.RecordConfig) map[models.RecordKey][]string {
 	changedKeys := map[models.RecordKey][]string{}
 	_, create, delete, modify := d.IncrementalDiff(existing)
@@ -210,45 +465,60 @@ func (d *differ) ChangedGroups(existing []*models.RecordConfig) map[models.Recor
 	return changedKeys
 }
 
-func (c Correlation) String() string {
-	if c.Existing == nil {
-		return fmt.Sprintf("CREATE %s %s %s", c.Desired.Type, c.Desired.GetLabelFQDN(), c.d.content(c.Desired))
+// Records returns the records of the change: the existing record it
+// deletes or modifies, and the desired record it creates or modifies.
+// Providers give them to the correction that makes the change.
+func (c Correlation) Records() []*models.RecordConfig {
+	var records []*models.RecordConfig
+	if c.Existing != nil {
+		records = append(records, c.Existing)
 	}
-	if c.Desired == nil {
-		return fmt.Sprintf("DELETE %s %s %s", c.Existing.Type, c.Existing.GetLabelFQDN(), c.d.content(c.Existing))
+	if c.Desired != nil {
+		records = append(records, c.Desired)
 	}
-	return fmt.Sprintf("MODIFY %s %s: (%s) -> (%s)", c.Existing.Type, c.Existing.GetLabelFQDN(), c.d.content(c.Existing), c.d.content(c.Desired))
+	return records
 }
 
-func sortedKeys(m map[string]*models.RecordConfig) []string {
-	s := []string{}
-	for v := range m {
-		s = append(s, v)
+func (c Correlation) String() string {
+	var s string
+	switch {
+	case c.Existing == nil:
+		s = fmt.Sprintf("CREATE %s %s %s", c.Desired.Type, c.Desired.GetLabelFQDN(), c.d.content(c.Desired))
+	case c.Desired == nil:
+		s = fmt.Sprintf("DELETE %s %s %s", c.Existing.Type, c.Existing.GetLabelFQDN(), c.d.content(c.Existing))
+	default:
+		s = fmt.Sprintf("MODIFY %s %s: (%s) -> (%s)", c.Existing.Type, c.Existing.GetLabelFQDN(), c.d.content(c.Existing), c.d.content(c.Desired))
+	}
+	if note := c.note(); note != "" {
+		s += " # " + note
 	}
-	sort.Strings(s)
 	return s
 }
 
-func compileIgnoredLabels(ignoredLabels []string) []glob.Glob {
-	result := make([]glob.Glob, 0, len(ignoredLabels))
-
-	for _, tst := range ignoredLabels {
-		g, err := glob.Compile(tst, '.')
-		if err != nil {
-			panic(fmt.Sprintf("Failed to compile IGNORE pattern %q: %v", tst, err))
+// note returns the NOTE() of the record, if the provider doesn't keep it.
+// Providers that keep notes give them as the extra value "note", which
+// the content of the record already shows.
+func (c Correlation) note() string {
+	r := c.Desired
+	if r == nil {
+		r = c.Existing
+	}
+	if r.Note() == "" {
+		return ""
+	}
+	for _, f := range c.d.extraValues {
+		if _, ok := f(r)["note"]; ok {
+			return ""
 		}
-
-		result = append(result, g)
 	}
-
-	return result
+	return r.Note()
 }
 
-func (d *differ) matchIgnored(name string) bool {
-	for _, tst := range d.compiledIgnoredLabels {
-		if tst.Match(name) {
-			return true
-		}
+func sortedKeys(m map[string]*models.RecordConfig) []string {
+	s := []string{}
+	for v := range m {
+		s = append(s, v)
 	}
-	return false
+	sort.Strings(s)
+	return s
 }
@@ -2,11 +2,13 @@ package diff
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/ownership"
 )
 
 func myRecord(s string) *models.RecordConfig {
@@ -115,6 +117,38 @@ func TestTTLChange(t *testing.T) {
 	checkLengths(t, existing, desired, 0, 0, 0, 1)
 }
 
+func TestIgnoreTTL(t *testing.T) {
+	existing := []*models.RecordConfig{
+		myRecord("www A 600 1.1.1.1"),
+		myRecord("www A 600 2.2.2.2"),
+		myRecord("mail A 600 3.3.3.3"),
+	}
+	desired := []*models.RecordConfig{
+		myRecord("www A 300 1.1.1.1"),
+		myRecord("www A 300 4.4.4.4"),
+		myRecord("mail A 300 3.3.3.3"),
+	}
+	desired[0].Metadata["ignore_ttl"] = "true"
+	desired[1].Metadata["ignore_ttl"] = "true"
+	_, _, _, mod := checkLengths(t, existing, desired, 1, 0, 0, 2)
+	for _, c := range mod {
+		if c.Desired.GetLabel() == "www" && c.Desired.TTL != 600 {
+			t.Errorf("Expected the new record of the set to keep TTL 600, got %d", c.Desired.TTL)
+		}
+	}
+
+	// For the whole domain.
+	dc := &models.DomainConfig{
+		Name:     "example.com",
+		Metadata: map[string]string{"ignore_ttl": "true"},
+		Records:  []*models.RecordConfig{myRecord("mail A 300 3.3.3.3"), myRecord("new A 300 5.5.5.5")},
+	}
+	un, cre, _, mod := New(dc).IncrementalDiff(existing[2:])
+	if len(un) != 1 || len(mod) != 0 || len(cre) != 1 || cre[0].Desired.TTL != 300 {
+		t.Errorf("Expected TTLs to be ignored, and new records to have theirs, got %d unchanged, %d modified, %s created", len(un), len(mod), cre)
+	}
+}
+
 func TestMetaChange(t *testing.T) {
 	existing := []*models.RecordConfig{
 		myRecord("www MX 1 1.1.1.1"),
@@ -153,6 +187,120 @@ func TestMetaOrdering(t *testing.T) {
 	checkLengths(t, existing, desired, 1, 0, 0, 0, getMeta)
 }
 
+func TestPriorityHint(t *testing.T) {
+	existing := []*models.RecordConfig{
+		myRecord("old A 1 1.1.1.1"),
+		myRecord("www CNAME 1 old"),
+		myRecord("other A 1 3.3.3.3"),
+		myRecord("sub NS 1 ns1"),
+		myRecord("sub NS 1 ns2"),
+	}
+	desired := []*models.RecordConfig{
+		myRecord("a A 1 1.1.1.1"),
+		myRecord("b A 1 1.1.1.1"),
+		myRecord("new A 1 2.2.2.2"),
+		myRecord("www CNAME 1 new"),
+		myRecord("sub NS 1 ns3"),
+		myRecord("z A 1 1.1.1.1"),
+	}
+	desired[2].Metadata["priority_hint"] = "first"
+	desired[3].Metadata["priority_hint"] = "first"
+	desired[4].Metadata["priority_hint"] = "last"
+	for i := 0; i < 10; i++ {
+		_, cre, del, mod := checkLengths(t, existing, desired, 0, 4, 3, 2)
+		if cre[0].Desired.GetLabel() != "new" {
+			t.Fatalf("expected new to be created first, got %v", cre)
+		}
+		if mod[0].Desired.GetLabel() != "www" {
+			t.Fatalf("expected www to be modified first, got %v", mod)
+		}
+		// The deleted NS record takes the hint of the one replacing it.
+		if del[2].Existing.GetLabel() != "sub" {
+			t.Fatalf("expected sub to be deleted last, got %v", del)
+		}
+	}
+}
+
+func TestSortCorrections(t *testing.T) {
+	existing := []*models.RecordConfig{
+		myRecord("old A 1 1.1.1.1"),
+		myRecord("www CNAME 1 old"),
+		myRecord("sub NS 1 ns1"),
+		myRecord("sub NS 1 ns0"),
+	}
+	desired := []*models.RecordConfig{
+		myRecord("new A 1 2.2.2.2"),
+		myRecord("www CNAME 1 new"),
+		myRecord("sub NS 1 ns2"),
+		myRecord("z A 1 1.1.1.1"),
+	}
+	desired[0].Metadata["priority_hint"] = "first"
+	desired[2].Metadata["priority_hint"] = "last"
+	dc := &models.DomainConfig{Name: "example.com", Records: desired}
+	Watch(dc)
+	_, create, del, mod := New(dc).IncrementalDiff(existing)
+	r := Unwatch(dc)
+
+	// The order most providers make them in: deletions, creations, then
+	// modifications, and a note of no change. The messages don't matter.
+	var corrections []*models.Correction
+	for _, cs := range []Changeset{del, create, mod} {
+		for _, c := range cs {
+			corrections = append(corrections, &models.Correction{Msg: c.String()[:6] + " " + c.Records()[0].GetLabel(), Records: c.Records()})
+		}
+	}
+	corrections = append(corrections, &models.Correction{Msg: "a note"})
+	SortCorrections(corrections, r)
+	var got []string
+	for _, c := range corrections {
+		got = append(got, c.Msg)
+	}
+	want := []string{
+		"CREATE new", // first
+		"DELETE old",
+		"CREATE z",
+		"MODIFY www",
+		"a note",
+		"DELETE sub", // last, like the NS record replacing it
+		"MODIFY sub",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got order\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// A correction that makes several changes is ranked if they have the
+	// same hint.
+	sub := &models.Correction{Msg: "sub", Records: append(del[1].Records(), mod[1].Records()...)}
+	mixed := &models.Correction{Msg: "mixed", Records: append(create[0].Records(), del[1].Records()...)}
+	corrections = []*models.Correction{sub, mixed, {Msg: "new", Records: create[0].Records()}}
+	SortCorrections(corrections, r)
+	if got := corrections[0].Msg + " " + corrections[1].Msg + " " + corrections[2].Msg; got != "new mixed sub" {
+		t.Errorf("got order %s, want new mixed sub", got)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dc := &models.DomainConfig{
+		Name:    "example.com",
+		Records: []*models.RecordConfig{myRecord("new A 1 2.2.2.2"), myRecord("www A 2 1.1.1.1")},
+	}
+	existing := []*models.RecordConfig{myRecord("old A 1 3.3.3.3"), myRecord("www A 1 1.1.1.1")}
+	New(dc).IncrementalDiff(existing)
+	if r := Unwatch(dc); r != nil {
+		t.Fatalf("expected nothing for an unwatched domain, got %+v", r)
+	}
+
+	Watch(dc)
+	New(dc).ChangedGroups(existing)
+	r := Unwatch(dc)
+	if len(r.Create) != 1 || len(r.Delete) != 1 || len(r.Modify) != 1 {
+		t.Fatalf("unexpected result %+v", r)
+	}
+	if r.Create[0].Desired.GetLabel() != "new" || r.Delete[0].Existing.GetLabel() != "old" || r.Modify[0].Desired.TTL != 2 {
+		t.Errorf("unexpected result %+v", r)
+	}
+}
+
 func checkLengths(t *testing.T, existing, desired []*models.RecordConfig, unCount, createCount, delCount, modCount int, valFuncs ...func(*models.RecordConfig) map[string]string) (un, cre, del, mod Changeset) {
 	return checkLengthsWithKeepUnknown(t, existing, desired, unCount, createCount, delCount, modCount, false, valFuncs...)
 }
@@ -200,6 +348,29 @@ func TestNoPurge(t *testing.T) {
 	checkLengthsWithKeepUnknown(t, existing, desired, 1, 0, 1, 0, true)
 }
 
+func TestEnsureAbsent(t *testing.T) {
+	existing := []*models.RecordConfig{
+		myRecord("www A 1 1.1.1.1"),
+		myRecord("foo A 1 1.1.1.1"),
+		myRecord("foo A 1 2.2.2.2"),
+		myRecord("bar A 1 1.1.1.1"),
+	}
+	dc := &models.DomainConfig{
+		Name:         "example.com",
+		Records:      []*models.RecordConfig{myRecord("www A 1 1.1.1.1")},
+		KeepUnknown:  true,
+		EnsureAbsent: []*models.RecordConfig{myRecord("foo A 300 2.2.2.2"), myRecord("baz A 1 1.1.1.1")},
+	}
+	un, cre, del, mod := New(dc).IncrementalDiff(existing)
+	// Only the foo record that is ENSURE_ABSENT is deleted.
+	if len(un) != 1 || len(cre) != 0 || len(del) != 1 || len(mod) != 0 {
+		t.Fatalf("got %d unchanged, %d created, %d deleted, %d modified; want 1, 0, 1, 0", len(un), len(cre), len(del), len(mod))
+	}
+	if got := del[0].Existing; got.GetLabel() != "foo" || got.GetTargetField() != "2.2.2.2" {
+		t.Errorf("deleted %s %s, want foo 2.2.2.2", got.GetLabel(), got.GetTargetField())
+	}
+}
+
 func TestIgnoredRecords(t *testing.T) {
 	existing := []*models.RecordConfig{
 		myRecord("www1 MX 1 1.1.1.1"),
@@ -231,6 +402,49 @@ func TestModifyingIgnoredRecords(t *testing.T) {
 	checkLengthsFull(t, existing, desired, 0, 0, 0, 1, false, []string{"www1", "www2"})
 }
 
+func TestIgnoreNameTypes(t *testing.T) {
+	existing := []*models.RecordConfig{
+		myRecord("@ TXT 1 ms=1234"),
+		myRecord("@ MX 1 mail.example.net."),
+		myRecord("www A 1 1.1.1.1"),
+	}
+	desired := []*models.RecordConfig{
+		myRecord("www A 1 1.1.1.1"),
+	}
+	dc := &models.DomainConfig{
+		Name:         "example.com",
+		Records:      desired,
+		IgnoredNames: []*models.Ignore{{Pattern: "@", Types: "txt, SRV"}},
+	}
+	un, cre, del, mod := New(dc).IncrementalDiff(existing)
+	// The TXT record is left alone, and the MX record deleted.
+	if len(un) != 1 || len(cre) != 0 || len(del) != 1 || len(mod) != 0 {
+		t.Fatalf("got %d unchanged, %d created, %d deleted, %d modified; want 1, 0, 1, 0", len(un), len(cre), len(del), len(mod))
+	}
+	if del[0].Existing.Type != "MX" {
+		t.Errorf("deleted %s, want MX", del[0].Existing.Type)
+	}
+}
+
+func TestIgnoreTarget(t *testing.T) {
+	existing := []*models.RecordConfig{
+		myRecord("_acme-challenge CNAME 1 abc.acme-dns.example.net."),
+		myRecord("_acme-challenge.www CNAME 1 def.acme-dns.example.net."),
+		myRecord("old CNAME 1 www.example.net."),
+	}
+	dc := &models.DomainConfig{
+		Name:           "example.com",
+		IgnoredTargets: []*models.Ignore{{Pattern: "*.acme-dns.example.net.", Types: "CNAME"}},
+	}
+	un, cre, del, mod := New(dc).IncrementalDiff(existing)
+	if len(un) != 0 || len(cre) != 0 || len(del) != 1 || len(mod) != 0 {
+		t.Fatalf("got %d unchanged, %d created, %d deleted, %d modified; want 0, 0, 1, 0", len(un), len(cre), len(del), len(mod))
+	}
+	if del[0].Existing.GetLabel() != "old" {
+		t.Errorf("deleted %s, want old", del[0].Existing.GetLabel())
+	}
+}
+
 func TestGlobIgnoredRecords(t *testing.T) {
 	existing := []*models.RecordConfig{
 		myRecord("www1 MX 1 1.1.1.1"),
@@ -262,3 +476,112 @@ func TestInvalidGlobIgnoredRecord(t *testing.T) {
 
 	checkLengthsFull(t, existing, desired, 0, 1, 0, 0, false, []string{"www1", "www2", "[.www3"})
 }
+
+func TestOwnership(t *testing.T) {
+	marker := func(label, owner string) *models.RecordConfig {
+		r := myRecord(label + " TXT 1 -")
+		r.SetTargetTXT("heritage=dnscontrol,dnscontrol/owner=" + owner)
+		return r
+	}
+	existing := []*models.RecordConfig{
+		myRecord("www A 1 1.1.1.1"), marker("_dnscontrol-a.www", "team"),
+		myRecord("api A 1 2.2.2.2"), marker("_dnscontrol-a.api", "other"),
+		myRecord("old A 1 3.3.3.3"), marker("_dnscontrol-a.old", "team"),
+		myRecord("legacy A 1 4.4.4.4"),
+		myRecord("same A 1 5.5.5.5"),
+	}
+	dc := &models.DomainConfig{
+		Name:  "example.com",
+		Owner: "team",
+		Records: []*models.RecordConfig{
+			myRecord("www A 1 1.1.1.2"),
+			myRecord("api A 1 9.9.9.9"),
+			myRecord("same A 1 5.5.5.5"),
+			myRecord("legacy A 1 6.6.6.6"),
+		},
+	}
+	ownership.AddMarkers(dc)
+	un, cre, del, mod := New(dc).IncrementalDiff(existing)
+	var got []string
+	for _, cs := range []Changeset{un, cre, del, mod} {
+		var names []string
+		for _, c := range cs {
+			if c.Desired != nil {
+				names = append(names, c.Desired.GetLabel())
+			} else {
+				names = append(names, c.Existing.GetLabel())
+			}
+		}
+		sort.Strings(names)
+		got = append(got, strings.Join(names, ","))
+	}
+	want := []string{
+		"_dnscontrol-a.www,same", // unchanged
+		"_dnscontrol-a.same",     // created: same is adopted
+		"_dnscontrol-a.old,old",  // deleted
+		"www",                    // modified
+	}
+	for i, name := range []string{"unchanged", "created", "deleted", "modified"} {
+		if got[i] != want[i] {
+			t.Errorf("%s: got %q, want %q", name, got[i], want[i])
+		}
+	}
+}
+
+func TestPatch(t *testing.T) {
+	txt := func(value string) *models.RecordConfig {
+		r := myRecord("_acme-challenge TXT 1 -")
+		r.SetTargetTXT(value)
+		return r
+	}
+	existing := []*models.RecordConfig{myRecord("www A 1 1.1.1.1"), txt("old"), txt("other")}
+	dc := &models.DomainConfig{
+		Name:          "example.com",
+		Patch:         true,
+		Records:       []*models.RecordConfig{txt("new")},
+		DeleteRecords: []*models.RecordConfig{txt("old")},
+	}
+	un, cre, del, mod := New(dc).IncrementalDiff(existing)
+	// www and other are kept, and old is replaced by new.
+	if len(un) != 2 || len(cre) != 0 || len(del) != 0 || len(mod) != 1 {
+		t.Fatalf("got %d unchanged, %d created, %d deleted, %d modified; want 2, 0, 0, 1", len(un), len(cre), len(del), len(mod))
+	}
+	if mod[0].Existing.GetTargetField() != "old" || mod[0].Desired.GetTargetField() != "new" {
+		t.Errorf("modified %s to %s, want old to new", mod[0].Existing.GetTargetField(), mod[0].Desired.GetTargetField())
+	}
+	// Providers that replace record sets find the records kept in dc.Records.
+	if len(dc.Records) != 3 {
+		t.Errorf("dc.Records has %d records, want 3", len(dc.Records))
+	}
+}
+
+func TestNote(t *testing.T) {
+	existing := []*models.RecordConfig{
+		myRecord("www A 1 1.1.1.1"),
+	}
+	desired := []*models.RecordConfig{
+		myRecord("www A 1 1.1.1.1"),
+		myRecord("mail A 1 2.2.2.2"),
+	}
+	desired[0].Metadata["note"] = "the web"
+	desired[1].Metadata["note"] = "the mail"
+	// A provider that can't keep notes only shows them.
+	_, cre, _, _ := checkLengths(t, existing, desired, 1, 1, 0, 0)
+	if want := "CREATE A mail.example.com 2.2.2.2 ttl=1 # the mail"; cre[0].String() != want {
+		t.Errorf("got %q, want %q", cre[0].String(), want)
+	}
+	// A provider that keeps them changes them.
+	keep := func(r *models.RecordConfig) map[string]string {
+		if r.Note() == "" {
+			return nil
+		}
+		return map[string]string{"note": r.Note()}
+	}
+	_, cre, _, mod := checkLengths(t, existing, desired, 0, 1, 0, 1, keep)
+	if want := "CREATE A mail.example.com 2.2.2.2 ttl=1 note=the mail"; cre[0].String() != want {
+		t.Errorf("got %q, want %q", cre[0].String(), want)
+	}
+	if want := "MODIFY A www.example.com: (1.1.1.1 ttl=1) -> (1.1.1.1 ttl=1 note=the web)"; mod[0].String() != want {
+		t.Errorf("got %q, want %q", mod[0].String(), want)
+	}
+}
@@ -0,0 +1,80 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/pkg/errors"
+
+	"github.com/StackExchange/dnscontrol/models"
+)
+
+// Ignores are the compiled IGNORE_NAME() and IGNORE_TARGET() rules of a
+// domain. The differ leaves the records they match alone, on every
+// provider.
+type Ignores []*ignore
+
+type ignore struct {
+	directive string          // As written in dnsconfig.js, for messages.
+	target    bool            // The pattern is of targets, not names.
+	glob      glob.Glob       // Matches labels, or targets.
+	types     map[string]bool // Empty for all types.
+}
+
+// CompileIgnores compiles the ignore rules of dc. Patterns are globs in
+// which "*" doesn't match dots, and "**" does.
+func CompileIgnores(dc *models.DomainConfig) (Ignores, error) {
+	var ignores Ignores
+	add := func(directive string, target bool, rule *models.Ignore) error {
+		g, err := glob.Compile(rule.Pattern, '.')
+		if err != nil {
+			return errors.Wrapf(err, "%s(%q): bad pattern", directive, rule.Pattern)
+		}
+		i := &ignore{directive: fmt.Sprintf("%s(%q)", directive, rule.Pattern), target: target, glob: g, types: map[string]bool{}}
+		for _, t := range strings.Split(rule.Types, ",") {
+			if t = strings.ToUpper(strings.TrimSpace(t)); t != "" && t != "*" {
+				i.types[t] = true
+			}
+		}
+		if len(i.types) != 0 {
+			i.directive = fmt.Sprintf("%s(%q, %q)", directive, rule.Pattern, rule.Types)
+		}
+		ignores = append(ignores, i)
+		return nil
+	}
+	for _, l := range dc.IgnoredLabels {
+		if err := add("IGNORE_NAME", false, &models.Ignore{Pattern: l}); err != nil {
+			return nil, err
+		}
+	}
+	for _, rule := range dc.IgnoredNames {
+		if err := add("IGNORE_NAME", false, rule); err != nil {
+			return nil, err
+		}
+	}
+	for _, rule := range dc.IgnoredTargets {
+		if err := add("IGNORE_TARGET", true, rule); err != nil {
+			return nil, err
+		}
+	}
+	return ignores, nil
+}
+
+// Match returns the rule that r matches, such as `IGNORE_NAME("foo")`, or
+// "" if none does.
+func (ignores Ignores) Match(r *models.RecordConfig) string {
+	for _, i := range ignores {
+		if len(i.types) != 0 && !i.types[r.Type] {
+			continue
+		}
+		s := r.GetLabel()
+		if i.target {
+			s = r.GetTargetField()
+		}
+		if i.glob.Match(s) {
+			return i.directive
+		}
+	}
+	return ""
+}
@@ -67,6 +67,7 @@ var features = providers.DocumentationNotes{
 	providers.DocCreateDomains:       providers.Can(),
 	providers.DocOfficiallySupported: providers.Cannot(),
 	providers.CanUseSRV:              providers.Can(),
+	providers.CanPatchRecords:        providers.Can(),
 }
 
 func init() {
@@ -123,7 +124,8 @@ func (api *DoApi) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Corre
 	for _, m := range delete {
 		id := m.Existing.Original.(*godo.DomainRecord).ID
 		corr := &models.Correction{
-			Msg: fmt.Sprintf("%s, DO ID: %d", m.String(), id),
+			Msg:     fmt.Sprintf("%s, DO ID: %d", m.String(), id),
+			Records: m.Records(),
 			F: func() error {
 				_, err := api.client.Domains.DeleteRecord(ctx, dc.Name, id)
 				return err
@@ -134,7 +136,8 @@ func (api *DoApi) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Corre
 	for _, m := range create {
 		req := toReq(dc, m.Desired)
 		corr := &models.Correction{
-			Msg: m.String(),
+			Msg:     m.String(),
+			Records: m.Records(),
 			F: func() error {
 				_, _, err := api.client.Domains.CreateRecord(ctx, dc.Name, req)
 				return err
@@ -146,7 +149,8 @@ func (api *DoApi) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Corre
 		id := m.Existing.Original.(*godo.DomainRecord).ID
 		req := toReq(dc, m.Desired)
 		corr := &models.Correction{
-			Msg: fmt.Sprintf("%s, DO ID: %d", m.String(), id),
+			Msg:     fmt.Sprintf("%s, DO ID: %d", m.String(), id),
+			Records: m.Records(),
 			F: func() error {
 				_, _, err := api.client.Domains.EditRecord(ctx, dc.Name, id, req)
 				return err
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
 	"github.com/StackExchange/dnscontrol/providers"
 	"github.com/StackExchange/dnscontrol/providers/diff"
 	"github.com/pkg/errors"
@@ -26,6 +27,7 @@ var features = providers.DocumentationNotes{
 	providers.DocCreateDomains:       providers.Cannot(),
 	providers.DocDualHost:            providers.Cannot("DNSimple does not allow sufficient control over the apex NS records"),
 	providers.DocOfficiallySupported: providers.Cannot(),
+	providers.CanPatchRecords:        providers.Can(),
 }
 
 func init() {
@@ -114,16 +116,18 @@ func (c *DnsimpleApi) GetDomainCorrections(dc *models.DomainConfig) ([]*models.C
 	for _, del := range del {
 		rec := del.Existing.Original.(dnsimpleapi.ZoneRecord)
 		corrections = append(corrections, &models.Correction{
-			Msg: del.String(),
-			F:   c.deleteRecordFunc(rec.ID, dc.Name),
+			Msg:     del.String(),
+			Records: del.Records(),
+			F:       c.deleteRecordFunc(rec.ID, dc.Name),
 		})
 	}
 
 	for _, cre := range create {
 		rec := cre.Desired
 		corrections = append(corrections, &models.Correction{
-			Msg: cre.String(),
-			F:   c.createRecordFunc(rec, dc.Name),
+			Msg:     cre.String(),
+			Records: cre.Records(),
+			F:       c.createRecordFunc(rec, dc.Name),
 		})
 	}
 
@@ -131,8 +135,9 @@ func (c *DnsimpleApi) GetDomainCorrections(dc *models.DomainConfig) ([]*models.C
 		old := mod.Existing.Original.(dnsimpleapi.ZoneRecord)
 		rec := mod.Desired
 		corrections = append(corrections, &models.Correction{
-			Msg: mod.String(),
-			F:   c.updateRecordFunc(&old, rec, dc.Name),
+			Msg:     mod.String(),
+			Records: mod.Records(),
+			F:       c.updateRecordFunc(&old, rec, dc.Name),
 		})
 	}
 
@@ -382,7 +387,7 @@ func removeOtherNS(dc *models.DomainConfig) {
 			if rec.GetLabelFQDN() == dc.Name && strings.HasSuffix(rec.GetTargetField(), ".dnsimple.com.") {
 				continue
 			}
-			fmt.Printf("Warning: dnsimple.com does not allow NS records to be modified. %s will not be added.\n", rec.GetTargetField())
+			printer.Printf("Warning: dnsimple.com does not allow NS records to be modified. %s will not be added.\n", rec.GetTargetField())
 			continue
 		}
 		newList = append(newList, rec)
@@ -0,0 +1,98 @@
+package dnsimple
+
+import (
+	"strconv"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/pkg/errors"
+
+	dnsimpleapi "github.com/dnsimple/dnsimple-go/dnsimple"
+)
+
+// GetDSRecords returns the DS records DNSimple publishes for domain.
+func (c *DnsimpleApi) GetDSRecords(domainName string) ([]*models.DSRecord, error) {
+	found, err := c.getDelegationSignerRecords(domainName)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*models.DSRecord, 0, len(found))
+	for _, r := range found {
+		ds, err := toDSRecord(r)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, ds)
+	}
+	return records, nil
+}
+
+// AddDSRecord publishes ds for domain.
+func (c *DnsimpleApi) AddDSRecord(domainName string, ds *models.DSRecord) error {
+	client := c.getClient()
+
+	accountID, err := c.getAccountID()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Domains.CreateDelegationSignerRecord(accountID, domainName, dnsimpleapi.DelegationSignerRecord{
+		Keytag:     strconv.Itoa(int(ds.KeyTag)),
+		Algorithm:  strconv.Itoa(int(ds.Algorithm)),
+		DigestType: strconv.Itoa(int(ds.DigestType)),
+		Digest:     ds.Digest,
+	})
+	return err
+}
+
+// RemoveDSRecord removes ds from domain.
+func (c *DnsimpleApi) RemoveDSRecord(domainName string, ds *models.DSRecord) error {
+	client := c.getClient()
+
+	accountID, err := c.getAccountID()
+	if err != nil {
+		return err
+	}
+
+	found, err := c.getDelegationSignerRecords(domainName)
+	if err != nil {
+		return err
+	}
+	for _, r := range found {
+		if have, err := toDSRecord(r); err == nil && have.String() == ds.String() {
+			_, err = client.Domains.DeleteDelegationSignerRecord(accountID, domainName, r.ID)
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *DnsimpleApi) getDelegationSignerRecords(domainName string) ([]dnsimpleapi.DelegationSignerRecord, error) {
+	client := c.getClient()
+
+	accountID, err := c.getAccountID()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.Domains.ListDelegationSignerRecords(accountID, domainName, nil)
+	if err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+func toDSRecord(r dnsimpleapi.DelegationSignerRecord) (*models.DSRecord, error) {
+	keytag, err := strconv.ParseUint(r.Keytag, 10, 16)
+	if err != nil {
+		return nil, errors.Wrapf(err, "keytag of DS record %d", r.ID)
+	}
+	algorithm, err := strconv.ParseUint(r.Algorithm, 10, 8)
+	if err != nil {
+		return nil, errors.Wrapf(err, "algorithm of DS record %d", r.ID)
+	}
+	digestType, err := strconv.ParseUint(r.DigestType, 10, 8)
+	if err != nil {
+		return nil, errors.Wrapf(err, "digest type of DS record %d", r.ID)
+	}
+	return &models.DSRecord{KeyTag: uint16(keytag), Algorithm: uint8(algorithm), DigestType: uint8(digestType), Digest: r.Digest}, nil
+}
@@ -0,0 +1,192 @@
+package dyndns
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+)
+
+var client = &http.Client{Timeout: 30 * time.Second}
+
+// templateData is the data made available to the URL and body templates.
+type templateData struct {
+	Domain   string // The zone, i.e. "example.com"
+	Label    string // The short name, "@" for the apex.
+	Host     string // The short name, "" for the apex.
+	FQDN     string // The full name without a trailing dot.
+	Type     string
+	Target   string // The target without a trailing dot.
+	TTL      uint32
+	Username string
+	Password string
+	Token    string
+}
+
+func (api *DynDNS) newTemplateData(domain string, rec *models.RecordConfig) *templateData {
+	host := rec.GetLabel()
+	if host == "@" {
+		host = ""
+	}
+	target := strings.TrimSuffix(rec.GetTargetField(), ".")
+	if rec.Type == "TXT" && len(rec.TxtStrings) > 0 {
+		target = strings.Join(rec.TxtStrings, "")
+	}
+	return &templateData{
+		Domain:   domain,
+		Label:    rec.GetLabel(),
+		Host:     host,
+		FQDN:     rec.GetLabelFQDN(),
+		Type:     rec.Type,
+		Target:   target,
+		TTL:      rec.TTL,
+		Username: api.username,
+		Password: api.password,
+		Token:    api.token,
+	}
+}
+
+func render(t *template.Template, data *templateData) (string, error) {
+	buf := &bytes.Buffer{}
+	if err := t.Execute(buf, data); err != nil {
+		return "", errors.Wrapf(err, "DYNDNS: rendering template %q", t.Name())
+	}
+	return buf.String(), nil
+}
+
+// call renders the template t for rec and sends the request.
+func (api *DynDNS) call(t *template.Template, domain string, rec *models.RecordConfig) error {
+	data := api.newTemplateData(domain, rec)
+	u, err := render(t, data)
+	if err != nil {
+		return err
+	}
+	var body string
+	if api.bodyTmpl != nil {
+		if body, err = render(api.bodyTmpl, data); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(api.method, u, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if api.contentType != "" {
+		req.Header.Set("Content-Type", api.contentType)
+	}
+	if api.username != "" || api.password != "" {
+		req.SetBasicAuth(api.username, api.password)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		// Don't leak the URL, it often includes a password.
+		return errors.Errorf("DYNDNS: request for %s failed", rec.GetLabelFQDN())
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return api.checkResponse(resp.StatusCode, respBody)
+}
+
+func (api *DynDNS) checkResponse(status int, body []byte) error {
+	if status < 200 || status > 299 {
+		return errors.Errorf("DYNDNS: bad status code %d: %s", status, strings.TrimSpace(string(body)))
+	}
+	if api.success != nil && !api.success.Match(body) {
+		return errors.Errorf("DYNDNS: unexpected response: %s", strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// lookupRecords queries the nameserver for every label/rtype that is
+// listed in dc.Records.  This is the best we can do, since services of
+// this kind have no API to list the records of a zone.
+func (api *DynDNS) lookupRecords(dc *models.DomainConfig) ([]*models.RecordConfig, error) {
+	server, err := api.server(dc.Name)
+	if err != nil {
+		return nil, err
+	}
+	found := []*models.RecordConfig{}
+	for key, recs := range dc.Records.Grouped() {
+		rrs, err := query(server, key.NameFQDN, key.Type)
+		if err != nil {
+			return nil, err
+		}
+		for _, rr := range rrs {
+			rc, err := rrToRecord(rr, dc.Name)
+			if err != nil {
+				return nil, err
+			}
+			// Services of this kind rarely let the TTL be set. Don't
+			// generate corrections that can't be fixed.
+			rc.TTL = recs[0].TTL
+			found = append(found, rc)
+		}
+	}
+	return found, nil
+}
+
+// server returns the host:port of the nameserver to query.
+func (api *DynDNS) server(domain string) (string, error) {
+	server := api.nameserver
+	if server == "" {
+		nss, err := net.LookupNS(domain)
+		if err != nil || len(nss) == 0 {
+			return "", errors.Errorf("DYNDNS: can not determine a nameserver for %s; set \"nameserver\" in creds.json", domain)
+		}
+		server = strings.TrimSuffix(nss[0].Host, ".")
+	}
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+	return server, nil
+}
+
+func query(server, fqdn, rtype string) ([]dns.RR, error) {
+	qtype, ok := dns.StringToType[rtype]
+	if !ok {
+		return nil, errors.Errorf("DYNDNS: can not query rtype %s", rtype)
+	}
+	m := &dns.Msg{}
+	m.SetQuestion(dns.Fqdn(fqdn), qtype)
+	c := &dns.Client{Timeout: 10 * time.Second}
+	r, _, err := c.Exchange(m, server)
+	if err != nil {
+		return nil, errors.Wrapf(err, "DYNDNS: querying %s for %s %s", server, fqdn, rtype)
+	}
+	if r.Rcode != dns.RcodeSuccess && r.Rcode != dns.RcodeNameError {
+		return nil, errors.Errorf("DYNDNS: querying %s for %s %s: %s", server, fqdn, rtype, dns.RcodeToString[r.Rcode])
+	}
+	rrs := []dns.RR{}
+	for _, rr := range r.Answer {
+		// Skip CNAMEs and the like that were followed on our behalf.
+		if rr.Header().Rrtype == qtype && strings.EqualFold(rr.Header().Name, dns.Fqdn(fqdn)) {
+			rrs = append(rrs, rr)
+		}
+	}
+	return rrs, nil
+}
+
+func rrToRecord(rr dns.RR, origin string) (*models.RecordConfig, error) {
+	rc := &models.RecordConfig{
+		Type: dns.TypeToString[rr.Header().Rrtype],
+		TTL:  rr.Header().Ttl,
+	}
+	rc.SetLabelFromFQDN(rr.Header().Name, origin)
+	switch v := rr.(type) { // #rtype_variations
+	case *dns.TXT:
+		return rc, rc.SetTargetTXTs(v.Txt)
+	default:
+		header := rr.Header().String()
+		return rc, rc.PopulateFromString(rc.Type, strings.TrimPrefix(rr.String(), header), origin)
+	}
+}
@@ -0,0 +1,77 @@
+package dyndns
+
+import (
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/models"
+)
+
+func TestTemplates(t *testing.T) {
+	p, err := newDynDNS(map[string]string{
+		"url":        "https://dyn.example.net/update?host={{.FQDN}}&ip={{.Target}}",
+		"url_TXT":    "https://dyn.example.net/txt?host={{.Host}}&txt={{urlquery .Target}}&key={{.Token}}",
+		"delete_url": "https://dyn.example.net/delete?host={{.FQDN}}&type={{.Type}}",
+		"token":      "s3cret",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := p.(*DynDNS)
+
+	tests := []struct {
+		rtype, label, target string
+		delete               bool
+		expected             string
+	}{
+		{"A", "home", "10.1.2.3", false, "https://dyn.example.net/update?host=home.example.com&ip=10.1.2.3"},
+		{"AAAA", "@", "2001:db8::1", false, "https://dyn.example.net/update?host=example.com&ip=2001:db8::1"},
+		{"TXT", "_acme-challenge", "a b", false, "https://dyn.example.net/txt?host=_acme-challenge&txt=a+b&key=s3cret"},
+		{"TXT", "home", "x", true, "https://dyn.example.net/delete?host=home.example.com&type=TXT"},
+	}
+	for _, tst := range tests {
+		rc := &models.RecordConfig{Type: tst.rtype}
+		rc.SetLabel(tst.label, "example.com")
+		if tst.rtype == "TXT" {
+			rc.SetTargetTXT(tst.target)
+		} else {
+			rc.SetTarget(tst.target)
+		}
+		tmpl := api.updateTemplate(tst.rtype)
+		if tst.delete {
+			tmpl = api.deleteTemplate(tst.rtype)
+		}
+		actual, err := render(tmpl, api.newTemplateData("example.com", rc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != tst.expected {
+			t.Errorf("%s %s: expected %q, got %q", tst.rtype, tst.label, tst.expected, actual)
+		}
+	}
+}
+
+func TestNoUpdateTemplate(t *testing.T) {
+	if _, err := newDynDNS(map[string]string{"delete_url": "https://x/"}, nil); err == nil {
+		t.Error("expected error when no update template is configured")
+	}
+}
+
+func TestCheckResponse(t *testing.T) {
+	p, err := newDynDNS(map[string]string{
+		"url":           "https://x/",
+		"success_regex": "^(good|nochg)",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := p.(*DynDNS)
+	if err := api.checkResponse(200, []byte("good 10.1.2.3")); err != nil {
+		t.Error(err)
+	}
+	if err := api.checkResponse(200, []byte("badauth")); err == nil {
+		t.Error("expected badauth to be an error")
+	}
+	if err := api.checkResponse(500, []byte("good")); err == nil {
+		t.Error("expected status 500 to be an error")
+	}
+}
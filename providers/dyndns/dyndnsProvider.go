@@ -0,0 +1,217 @@
+package dyndns
+
+/*
+
+DYNDNS -
+  Generic driver for small "dyndns style" services (Core-Networks,
+  SelfHost, and friends) whose only API is an HTTP request that sets
+  the value of a single record.
+
+	The request is described by URL templates in creds.json. Such
+	services can not list the records of a zone, therefore the
+	existing records are learned by querying the zone's nameservers.
+	Only records listed in dnsconfig.js are managed; nothing is
+	ever purged unless a delete template is configured.
+
+Info required in `creds.json`:
+   - url: the update URL template (text/template syntax)
+
+Optional:
+   - url_A, url_AAAA, url_TXT, ...: per-rtype update URL templates
+   - delete_url, delete_url_TXT, ...: templates used to remove a record
+   - method: HTTP method to use (default GET)
+   - body: template for the request body (POST/PUT only)
+   - content_type: Content-Type of the request body
+   - username, password: HTTP basic auth (also available to the templates)
+   - token: a token made available to the templates as {{.Token}}
+   - success_regex: the response body must match this regex (default: any 2xx status)
+   - nameserver: host[:port] queried for the existing records (default: the zone's NS)
+   - nameservers: comma-separated list of nameservers reported for the zone
+
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/providers"
+	"github.com/StackExchange/dnscontrol/providers/diff"
+	"github.com/pkg/errors"
+)
+
+var features = providers.DocumentationNotes{
+	providers.CanUseAlias:            providers.Cannot(),
+	providers.CanUseCAA:              providers.Cannot(),
+	providers.CanUsePTR:              providers.Cannot(),
+	providers.CanUseSRV:              providers.Cannot(),
+	providers.CanUseTLSA:             providers.Cannot(),
+	providers.CanUseTXTMulti:         providers.Cannot(),
+	providers.CantUseMultipleValues:  providers.Can("An update replaces the value of the name"),
+	providers.DocCreateDomains:       providers.Cannot("Zones are created in the web interface of the service"),
+	providers.DocDualHost:            providers.Cannot("Apex NS records can not be managed"),
+	providers.DocOfficiallySupported: providers.Cannot(),
+}
+
+func init() {
+	providers.RegisterDomainServiceProviderType("DYNDNS", newDynDNS, features)
+}
+
+// DynDNS is the handle for this provider.
+type DynDNS struct {
+	updateTmpls map[string]*template.Template // Keyed by rtype. "" is the fallback.
+	deleteTmpls map[string]*template.Template // Keyed by rtype. "" is the fallback.
+	bodyTmpl    *template.Template
+	method      string
+	contentType string
+	username    string
+	password    string
+	token       string
+	success     *regexp.Regexp
+	nameserver  string
+	nameservers []*models.Nameserver
+}
+
+func newDynDNS(m map[string]string, metadata json.RawMessage) (providers.DNSServiceProvider, error) {
+	api := &DynDNS{
+		updateTmpls: map[string]*template.Template{},
+		deleteTmpls: map[string]*template.Template{},
+		method:      strings.ToUpper(m["method"]),
+		contentType: m["content_type"],
+		username:    m["username"],
+		password:    m["password"],
+		token:       m["token"],
+		nameserver:  m["nameserver"],
+	}
+	if api.method == "" {
+		api.method = "GET"
+	}
+	for k, v := range m {
+		var tmpls map[string]*template.Template
+		var rtype string
+		switch {
+		case k == "url":
+			tmpls = api.updateTmpls
+		case strings.HasPrefix(k, "url_"):
+			tmpls, rtype = api.updateTmpls, strings.ToUpper(k[len("url_"):])
+		case k == "delete_url":
+			tmpls = api.deleteTmpls
+		case strings.HasPrefix(k, "delete_url_"):
+			tmpls, rtype = api.deleteTmpls, strings.ToUpper(k[len("delete_url_"):])
+		default:
+			continue
+		}
+		t, err := template.New(k).Parse(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "DYNDNS: invalid template %q", k)
+		}
+		tmpls[rtype] = t
+	}
+	if len(api.updateTmpls) == 0 {
+		return nil, errors.Errorf("DYNDNS: at least one update template (url or url_RTYPE) is required")
+	}
+	if b := m["body"]; b != "" {
+		t, err := template.New("body").Parse(b)
+		if err != nil {
+			return nil, errors.Wrap(err, "DYNDNS: invalid template \"body\"")
+		}
+		api.bodyTmpl = t
+	}
+	if s := m["success_regex"]; s != "" {
+		re, err := regexp.Compile(s)
+		if err != nil {
+			return nil, errors.Wrap(err, "DYNDNS: invalid success_regex")
+		}
+		api.success = re
+	}
+	if ns := m["nameservers"]; ns != "" {
+		api.nameservers = models.StringsToNameservers(strings.Split(ns, ","))
+	}
+	return api, nil
+}
+
+// GetNameservers returns the nameservers for a domain.
+func (api *DynDNS) GetNameservers(domain string) ([]*models.Nameserver, error) {
+	return api.nameservers, nil
+}
+
+// GetDomainCorrections returns a list of corrections to update a domain.
+func (api *DynDNS) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	dc.Punycode()
+
+	// The service manages the NS records itself.
+	dc.Filter(func(r *models.RecordConfig) bool {
+		return !(r.Type == "NS" && r.GetLabel() == "@")
+	})
+
+	for key, recs := range dc.Records.Grouped() {
+		if api.updateTemplate(key.Type) == nil {
+			return nil, errors.Errorf("DYNDNS: no update template for %s records (label %s)", key.Type, key.NameFQDN)
+		}
+		// An update replaces the value, so several would never converge.
+		if len(recs) > 1 {
+			return nil, errors.Errorf("DYNDNS: %s has %d %s records; the service can only set one value", key.NameFQDN, len(recs), key.Type)
+		}
+	}
+
+	existing, err := api.lookupRecords(dc)
+	if err != nil {
+		return nil, err
+	}
+	models.PostProcessRecords(existing)
+
+	// The service can not enumerate the zone: existing only has the names
+	// and types of dnsconfig.js, so anything else is left alone.
+	differ := diff.New(dc)
+	_, create, del, mod := differ.IncrementalDiff(existing)
+
+	corrections := []*models.Correction{}
+	for _, m := range del {
+		t := api.deleteTemplate(m.Existing.Type)
+		rec := m.Existing
+		if t == nil {
+			// The service has no way to remove a record.  Fail, rather than
+			// pretending the deletion happened.
+			corrections = append(corrections, &models.Correction{
+				Msg:     fmt.Sprintf("%s (not supported by this service; remove it manually)", m),
+				Records: m.Records(),
+				F: func() error {
+					return errors.Errorf("DYNDNS: can not delete %s %s: no delete template (delete_url or delete_url_%s) is configured", rec.GetLabelFQDN(), rec.Type, rec.Type)
+				},
+			})
+			continue
+		}
+		corrections = append(corrections, &models.Correction{
+			Msg:     m.String(),
+			Records: m.Records(),
+			F:       func() error { return api.call(t, dc.Name, rec) },
+		})
+	}
+	for _, m := range append(create, mod...) {
+		t := api.updateTemplate(m.Desired.Type)
+		rec := m.Desired
+		corrections = append(corrections, &models.Correction{
+			Msg:     m.String(),
+			Records: m.Records(),
+			F:       func() error { return api.call(t, dc.Name, rec) },
+		})
+	}
+	return corrections, nil
+}
+
+func (api *DynDNS) updateTemplate(rtype string) *template.Template {
+	if t, ok := api.updateTmpls[rtype]; ok {
+		return t
+	}
+	return api.updateTmpls[""]
+}
+
+func (api *DynDNS) deleteTemplate(rtype string) *template.Template {
+	if t, ok := api.deleteTmpls[rtype]; ok {
+		return t
+	}
+	return api.deleteTmpls[""]
+}
@@ -2,13 +2,13 @@ package exoscale
 
 import (
 	"encoding/json"
-	"fmt"
 	"strings"
 
 	"github.com/exoscale/egoscale"
 	"github.com/pkg/errors"
 
 	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
 	"github.com/StackExchange/dnscontrol/providers"
 	"github.com/StackExchange/dnscontrol/providers/diff"
 )
@@ -33,6 +33,7 @@ var features = providers.DocumentationNotes{
 	providers.DocCreateDomains:       providers.Cannot(),
 	providers.DocDualHost:            providers.Cannot("Exoscale does not allow sufficient control over the apex NS records"),
 	providers.DocOfficiallySupported: providers.Cannot(),
+	providers.CanPatchRecords:        providers.Can(),
 }
 
 func init() {
@@ -114,16 +115,18 @@ func (c *exoscaleProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 	for _, del := range delete {
 		rec := del.Existing.Original.(egoscale.DNSRecord)
 		corrections = append(corrections, &models.Correction{
-			Msg: del.String(),
-			F:   c.deleteRecordFunc(rec.ID, dc.Name),
+			Msg:     del.String(),
+			Records: del.Records(),
+			F:       c.deleteRecordFunc(rec.ID, dc.Name),
 		})
 	}
 
 	for _, cre := range create {
 		rec := cre.Desired
 		corrections = append(corrections, &models.Correction{
-			Msg: cre.String(),
-			F:   c.createRecordFunc(rec, dc.Name),
+			Msg:     cre.String(),
+			Records: cre.Records(),
+			F:       c.createRecordFunc(rec, dc.Name),
 		})
 	}
 
@@ -131,8 +134,9 @@ func (c *exoscaleProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mod
 		old := mod.Existing.Original.(egoscale.DNSRecord)
 		new := mod.Desired
 		corrections = append(corrections, &models.Correction{
-			Msg: mod.String(),
-			F:   c.updateRecordFunc(&old, new, dc.Name),
+			Msg:     mod.String(),
+			Records: mod.Records(),
+			F:       c.updateRecordFunc(&old, new, dc.Name),
 		})
 	}
 
@@ -236,7 +240,7 @@ func removeOtherNS(dc *models.DomainConfig) {
 			if rec.GetLabelFQDN() == dc.Name && defaultNSSUffix(rec.GetTargetField()) {
 				continue
 			}
-			fmt.Printf("Warning: exoscale.com(.io, .ch, .net) does not allow NS records to be modified. %s will not be added.\n", rec.GetTargetField())
+			printer.Printf("Warning: exoscale.com(.io, .ch, .net) does not allow NS records to be modified. %s will not be added.\n", rec.GetTargetField())
 			continue
 		}
 		newList = append(newList, rec)
@@ -0,0 +1,96 @@
+package freedns
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const defaultBaseURL = "https://freedns.afraid.org/api/"
+
+var client = &http.Client{Timeout: 30 * time.Second}
+
+// dynEntry is one line of the getdyndns listing.
+type dynEntry struct {
+	Host      string
+	Address   string
+	UpdateURL string
+}
+
+// sha returns the hash FreeDNS uses to authenticate API calls.
+func sha(username, password string) string {
+	h := sha1.Sum([]byte(strings.ToLower(username) + "|" + password))
+	return hex.EncodeToString(h[:])
+}
+
+// fetchDynEntries returns all hosts that have dynamic DNS enabled.
+func (api *FreeDNS) fetchDynEntries() ([]*dynEntry, error) {
+	q := url.Values{}
+	q.Set("action", "getdyndns")
+	q.Set("v", "2")
+	q.Set("sha", api.sha)
+	resp, err := client.Get(api.baseURL + "?" + q.Encode())
+	if err != nil {
+		return nil, errors.Errorf("FreeDNS: could not fetch the list of hosts")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("FreeDNS: bad status code %d fetching the list of hosts", resp.StatusCode)
+	}
+	return parseDynEntries(resp.Body)
+}
+
+// parseDynEntries parses the v2 getdyndns output: host|address|updateurl
+func parseDynEntries(r io.Reader) ([]*dynEntry, error) {
+	entries := []*dynEntry{}
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "ERROR") {
+			return nil, errors.Errorf("FreeDNS: %s", line)
+		}
+		parts := strings.Split(line, "|")
+		if len(parts) != 3 {
+			return nil, errors.Errorf("FreeDNS: unparsable line in host list: %q", line)
+		}
+		entries = append(entries, &dynEntry{
+			Host:      strings.ToLower(parts[0]),
+			Address:   parts[1],
+			UpdateURL: parts[2],
+		})
+	}
+	return entries, s.Err()
+}
+
+// updateAddress points the dynamic host at address.
+func (api *FreeDNS) updateAddress(e *dynEntry, address string) error {
+	u, err := url.Parse(e.UpdateURL)
+	if err != nil {
+		return errors.Wrapf(err, "FreeDNS: invalid update URL for %s", e.Host)
+	}
+	q := u.Query()
+	q.Set("address", address)
+	u.RawQuery = q.Encode()
+	resp, err := client.Get(u.String())
+	if err != nil {
+		// Don't include the URL: it contains the host's secret token.
+		return errors.Errorf("FreeDNS: updating %s failed", e.Host)
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || strings.HasPrefix(string(body), "ERROR") {
+		return errors.Errorf("FreeDNS: updating %s failed: %s", e.Host, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
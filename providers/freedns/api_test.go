@@ -0,0 +1,44 @@
+package freedns
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDynEntries(t *testing.T) {
+	in := `home.example.com|198.51.100.7|https://freedns.afraid.org/dynamic/update.php?abc123
+v6.example.com|2001:db8::1|https://freedns.afraid.org/dynamic/update.php?def456
+other.example.net|203.0.113.9|https://freedns.afraid.org/dynamic/update.php?ghi789
+`
+	entries, err := parseDynEntries(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+
+	var types []string
+	for _, e := range entries {
+		if rc, ok := toRecordConfig(e, "example.com"); ok {
+			types = append(types, rc.Type+" "+rc.GetLabel()+" "+rc.GetTargetField())
+		}
+	}
+	expected := "A home 198.51.100.7,AAAA v6 2001:db8::1"
+	if actual := strings.Join(types, ","); actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestParseDynEntriesError(t *testing.T) {
+	if _, err := parseDynEntries(strings.NewReader("ERROR: Could not authenticate.\n")); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestSha(t *testing.T) {
+	// echo -n "user|pass" | sha1sum
+	if s := sha("User", "pass"); s != "8894721a433861735f4c2f52ff577ddd37279e24" {
+		t.Errorf("unexpected sha %s", s)
+	}
+}
@@ -0,0 +1,171 @@
+package freedns
+
+/*
+
+FreeDNS (afraid.org) provider:
+
+	FreeDNS has no API for managing records. The only scraping-free
+	interface is the dynamic DNS one, which lists the hosts that have
+	dynamic updates enabled and lets us change their address.
+
+	Therefore this provider can only update the address of existing
+	A/AAAA records.  Anything else (creating, deleting, other rtypes)
+	is reported as a change that must be made in the web interface;
+	the provider degrades to read-only for those records rather than
+	failing.
+
+Info required in `creds.json`:
+   - username
+   - password
+  or
+   - sha (the SHA-1 of "username|password")
+
+*/
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/StackExchange/dnscontrol/providers"
+	"github.com/StackExchange/dnscontrol/providers/diff"
+	"github.com/pkg/errors"
+)
+
+var features = providers.DocumentationNotes{
+	providers.CanUseAlias:            providers.Cannot(),
+	providers.CanUseCAA:              providers.Cannot(),
+	providers.CanUsePTR:              providers.Cannot(),
+	providers.CanUseNAPTR:            providers.Cannot(),
+	providers.CanUseSRV:              providers.Cannot("Only A and AAAA records can be managed via the dynamic DNS interface"),
+	providers.CanUseSSHFP:            providers.Cannot(),
+	providers.CanUseTLSA:             providers.Cannot(),
+	providers.CanUseTXTMulti:         providers.Cannot(),
+	providers.DocCreateDomains:       providers.Cannot("Domains must be added in the web interface"),
+	providers.DocDualHost:            providers.Cannot(),
+	providers.DocOfficiallySupported: providers.Cannot(),
+	providers.CanPatchRecords:        providers.Can(),
+}
+
+func init() {
+	providers.RegisterDomainServiceProviderType("FREEDNS", newFreeDNS, features)
+}
+
+var defaultNameServerNames = []string{
+	"ns1.afraid.org",
+	"ns2.afraid.org",
+	"ns3.afraid.org",
+	"ns4.afraid.org",
+}
+
+// FreeDNS is the handle for this provider.
+type FreeDNS struct {
+	sha     string
+	baseURL string
+}
+
+func newFreeDNS(m map[string]string, metadata json.RawMessage) (providers.DNSServiceProvider, error) {
+	api := &FreeDNS{
+		sha:     m["sha"],
+		baseURL: m["baseurl"],
+	}
+	if api.sha == "" {
+		if m["username"] == "" || m["password"] == "" {
+			return nil, errors.Errorf("FreeDNS: username and password (or sha) are required")
+		}
+		api.sha = sha(m["username"], m["password"])
+	}
+	if api.baseURL == "" {
+		api.baseURL = defaultBaseURL
+	}
+	return api, nil
+}
+
+// GetNameservers returns the nameservers for a domain.
+func (api *FreeDNS) GetNameservers(domain string) ([]*models.Nameserver, error) {
+	return models.StringsToNameservers(defaultNameServerNames), nil
+}
+
+// GetDomainCorrections returns a list of corrections to update a domain.
+func (api *FreeDNS) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	dc.Punycode()
+
+	// The apex NS records are managed by FreeDNS.
+	dc.Filter(func(r *models.RecordConfig) bool {
+		return !(r.Type == "NS" && r.GetLabel() == "@")
+	})
+
+	entries, err := api.fetchDynEntries()
+	if err != nil {
+		return nil, err
+	}
+	existing := []*models.RecordConfig{}
+	for _, e := range entries {
+		rc, ok := toRecordConfig(e, dc.Name)
+		if ok {
+			existing = append(existing, rc)
+		}
+	}
+	models.PostProcessRecords(existing)
+
+	// FreeDNS does not let us set the TTL via this interface.
+	desiredTTL := map[models.RecordKey]uint32{}
+	for _, r := range dc.Records {
+		desiredTTL[r.Key()] = r.TTL
+	}
+	for _, r := range existing {
+		if ttl, ok := desiredTTL[r.Key()]; ok {
+			r.TTL = ttl
+		}
+	}
+
+	differ := diff.New(dc)
+	_, create, del, mod := differ.IncrementalDiff(existing)
+
+	corrections := []*models.Correction{}
+	for _, m := range mod {
+		e := m.Existing.Original.(*dynEntry)
+		target := m.Desired.GetTargetField()
+		corrections = append(corrections, &models.Correction{
+			Msg:     m.String(),
+			Records: m.Records(),
+			F:       func() error { return api.updateAddress(e, target) },
+		})
+	}
+
+	// Everything else can't be expressed via the dynamic DNS interface.
+	// Tell the user, but don't fail: the remaining changes can still be made.
+	for _, m := range create {
+		printer.Warnf("FreeDNS: can not create records via the API; add it in the web interface: %s\n", m)
+	}
+	for _, m := range del {
+		printer.Warnf("FreeDNS: can not delete records via the API; remove it in the web interface: %s\n", m)
+	}
+
+	return corrections, nil
+}
+
+// toRecordConfig converts a dynamic DNS entry to a RecordConfig. It
+// returns false if the entry is not part of the domain.
+func toRecordConfig(e *dynEntry, origin string) (*models.RecordConfig, bool) {
+	if e.Host != origin && !strings.HasSuffix(e.Host, "."+origin) {
+		return nil, false
+	}
+	ip := net.ParseIP(e.Address)
+	if ip == nil {
+		return nil, false
+	}
+	rc := &models.RecordConfig{
+		Type:     "A",
+		TTL:      models.DefaultTTL,
+		Original: e,
+	}
+	if ip.To4() == nil {
+		rc.Type = "AAAA"
+	}
+	rc.SetLabelFromFQDN(e.Host, origin)
+	rc.SetTargetIP(ip)
+	return rc, true
+}
@@ -27,12 +27,13 @@ Info required in `creds.json`:
 */
 
 var features = providers.DocumentationNotes{
-	providers.CanUseCAA:              providers.Can(),
-	providers.CanUsePTR:              providers.Can(),
-	providers.CanUseSRV:              providers.Can(),
-	providers.CantUseNOPURGE:         providers.Cannot(),
-	providers.DocCreateDomains:       providers.Cannot("Can only manage domains registered through their service"),
-	providers.DocOfficiallySupported: providers.Cannot(),
+	providers.CanCheckCredsWithNameservers: providers.Can(),
+	providers.CanUseCAA:                    providers.Can(),
+	providers.CanUsePTR:                    providers.Can(),
+	providers.CanUseSRV:                    providers.Can(),
+	providers.CantUseNOPURGE:               providers.Cannot(),
+	providers.DocCreateDomains:             providers.Cannot("Can only manage domains registered through their service"),
+	providers.DocOfficiallySupported:       providers.Cannot(),
 }
 
 func init() {
@@ -13,6 +13,7 @@ import (
 	gandioperation "github.com/prasmussen/gandi-api/operation"
 
 	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
 )
 
 // fetchDomainList gets list of domains for account. Cache ids for easy lookup.
@@ -117,7 +118,7 @@ func (c *GandiApi) getEditableZone(domainname string, zoneinfo *gandizone.ZoneIn
 	if zoneinfo.Domains < 2 {
 		// If there is only on{ domain linked to this zone, use it.
 		zoneID = zoneinfo.Id
-		fmt.Printf("Using zone id=%d named %#v\n", zoneID, zoneinfo.Name)
+		printer.Printf("Using zone id=%d named %#v\n", zoneID, zoneinfo.Name)
 		return zoneID, nil
 	}
 
@@ -130,7 +131,7 @@ func (c *GandiApi) getEditableZone(domainname string, zoneinfo *gandizone.ZoneIn
 	for _, z := range zones {
 		if z.Name == zonename {
 			zoneID = z.Id
-			fmt.Printf("Recycling zone id=%d named %#v\n", zoneID, z.Name)
+			printer.Printf("Recycling zone id=%d named %#v\n", zoneID, z.Name)
 			return zoneID, nil
 		}
 	}
@@ -139,7 +140,7 @@ func (c *GandiApi) getEditableZone(domainname string, zoneinfo *gandizone.ZoneIn
 		return 0, err
 	}
 	zoneID = zoneinfo.Id
-	fmt.Printf("Created zone id=%d named %#v\n", zoneID, zoneinfo.Name)
+	printer.Printf("Created zone id=%d named %#v\n", zoneID, zoneinfo.Name)
 	return zoneID, nil
 }
 
@@ -10,19 +10,22 @@ import (
 	gdns "google.golang.org/api/dns/v1"
 
 	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
 	"github.com/StackExchange/dnscontrol/providers"
 	"github.com/StackExchange/dnscontrol/providers/diff"
 	"github.com/pkg/errors"
 )
 
 var features = providers.DocumentationNotes{
-	providers.DocCreateDomains:       providers.Can(),
-	providers.DocDualHost:            providers.Can(),
-	providers.DocOfficiallySupported: providers.Can(),
-	providers.CanUsePTR:              providers.Can(),
-	providers.CanUseSRV:              providers.Can(),
-	providers.CanUseCAA:              providers.Can(),
-	providers.CanUseTXTMulti:         providers.Can(),
+	providers.CanCheckCredsWithNameservers: providers.Can(),
+	providers.DocCreateDomains:             providers.Can(),
+	providers.DocDualHost:                  providers.Can(),
+	providers.DocOfficiallySupported:       providers.Can(),
+	providers.CanUsePTR:                    providers.Can(),
+	providers.CanUseSRV:                    providers.Can(),
+	providers.CanUseCAA:                    providers.Can(),
+	providers.CanUseTXTMulti:               providers.Can(),
+	providers.CanPatchRecords:              providers.Can(),
 }
 
 func sPtr(s string) *string {
@@ -58,7 +61,7 @@ func New(cfg map[string]string, metadata json.RawMessage) (providers.DNSServiceP
 	}
 	var nss *string = nil
 	if val, ok := cfg["name_server_set"]; ok {
-		fmt.Printf("GCLOUD :name_server_set %s configured\n", val)
+		printer.Printf("GCLOUD :name_server_set %s configured\n", val)
 		nss = sPtr(val)
 	}
 	return &gcloud{
@@ -244,7 +247,7 @@ func (g *gcloud) EnsureDomainExists(domain string) error {
 	}
 	var mz *gdns.ManagedZone
 	if g.nameServerSet != nil {
-		fmt.Printf("Adding zone for %s to gcloud account with name_server_set %s\n", domain, *g.nameServerSet)
+		printer.Printf("Adding zone for %s to gcloud account with name_server_set %s\n", domain, *g.nameServerSet)
 		mz = &gdns.ManagedZone{
 			DnsName:       domain + ".",
 			NameServerSet: *g.nameServerSet,
@@ -252,7 +255,7 @@ func (g *gcloud) EnsureDomainExists(domain string) error {
 			Description:   "zone added by dnscontrol",
 		}
 	} else {
-		fmt.Printf("Adding zone for %s to gcloud account \n", domain)
+		printer.Printf("Adding zone for %s to gcloud account \n", domain)
 		mz = &gdns.ManagedZone{
 			DnsName:     domain + ".",
 			Name:        "zone-" + strings.Replace(domain, ".", "-", -1),
@@ -18,17 +18,19 @@ type HXClient struct {
 }
 
 var features = providers.DocumentationNotes{
-	providers.CanUseAlias:            providers.Cannot("Using ALIAS is possible through our extended DNS (X-DNS) service. Feel free to get in touch with us."),
-	providers.CanUseCAA:              providers.Can(),
-	providers.CanUsePTR:              providers.Can(),
-	providers.CanUseRoute53Alias:     providers.Cannot("Using ALIAS is possible through our extended DNS (X-DNS) service. Feel free to get in touch with us."),
-	providers.CanUseSRV:              providers.Can(),
-	providers.CanUseTLSA:             providers.Can(),
-	providers.CanUseTXTMulti:         providers.Can(),
-	providers.CantUseNOPURGE:         providers.Can(),
-	providers.DocCreateDomains:       providers.Can(),
-	providers.DocDualHost:            providers.Can(),
-	providers.DocOfficiallySupported: providers.Cannot("Actively maintained provider module."),
+	providers.CanCheckCredsWithNameservers: providers.Can(),
+	providers.CanUseAlias:                  providers.Cannot("Using ALIAS is possible through our extended DNS (X-DNS) service. Feel free to get in touch with us."),
+	providers.CanUseCAA:                    providers.Can(),
+	providers.CanUsePTR:                    providers.Can(),
+	providers.CanUseRoute53Alias:           providers.Cannot("Using ALIAS is possible through our extended DNS (X-DNS) service. Feel free to get in touch with us."),
+	providers.CanUseSRV:                    providers.Can(),
+	providers.CanUseTLSA:                   providers.Can(),
+	providers.CanUseTXTMulti:               providers.Can(),
+	providers.CantUseNOPURGE:               providers.Can(),
+	providers.DocCreateDomains:             providers.Can(),
+	providers.DocDualHost:                  providers.Can(),
+	providers.DocOfficiallySupported:       providers.Cannot("Actively maintained provider module."),
+	providers.CanPatchRecords:              providers.Can(),
 }
 
 func newProvider(conf map[string]string) (*HXClient, error) {
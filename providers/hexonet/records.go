@@ -11,6 +11,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
 	"github.com/StackExchange/dnscontrol/providers/diff"
 )
 
@@ -138,9 +139,9 @@ func toRecord(r *HXRecord, origin string) *models.RecordConfig {
 func (n *HXClient) showCommand(cmd map[string]string) {
 	b, err := json.MarshalIndent(cmd, "", "  ")
 	if err != nil {
-		fmt.Println("error:", err)
+		printer.Printf("error: %s\n", err)
 	}
-	fmt.Print(string(b))
+	printer.Printf("%s", b)
 }
 
 func (n *HXClient) updateZoneBy(params map[string]string, domain string) error {
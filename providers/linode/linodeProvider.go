@@ -91,6 +91,7 @@ func NewLinode(m map[string]string, metadata json.RawMessage) (providers.DNSServ
 var features = providers.DocumentationNotes{
 	providers.DocDualHost:            providers.Cannot(),
 	providers.DocOfficiallySupported: providers.Cannot(),
+	providers.CanPatchRecords:        providers.Can(),
 }
 
 func init() {
@@ -168,7 +169,8 @@ func (api *LinodeApi) GetDomainCorrections(dc *models.DomainConfig) ([]*models.C
 			continue
 		}
 		corr := &models.Correction{
-			Msg: fmt.Sprintf("%s, Linode ID: %d", m.String(), id),
+			Msg:     fmt.Sprintf("%s, Linode ID: %d", m.String(), id),
+			Records: m.Records(),
 			F: func() error {
 				return api.deleteRecord(domainID, id)
 			},
@@ -185,7 +187,8 @@ func (api *LinodeApi) GetDomainCorrections(dc *models.DomainConfig) ([]*models.C
 			return nil, err
 		}
 		corr := &models.Correction{
-			Msg: fmt.Sprintf("%s: %s", m.String(), string(j)),
+			Msg:     fmt.Sprintf("%s: %s", m.String(), string(j)),
+			Records: m.Records(),
 			F: func() error {
 				record, err := api.createRecord(domainID, req)
 				if err != nil {
@@ -211,7 +214,8 @@ func (api *LinodeApi) GetDomainCorrections(dc *models.DomainConfig) ([]*models.C
 			return nil, err
 		}
 		corr := &models.Correction{
-			Msg: fmt.Sprintf("%s, Linode ID: %d: %s", m.String(), id, string(j)),
+			Msg:     fmt.Sprintf("%s, Linode ID: %d: %s", m.String(), id, string(j)),
+			Records: m.Records(),
 			F: func() error {
 				return api.modifyRecord(domainID, id, req)
 			},
@@ -0,0 +1,60 @@
+package namedotcom
+
+import (
+	"time"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/namedotcom/go/namecom"
+	"github.com/pkg/errors"
+)
+
+// GetDomainStatus returns when domain expires, and whether it is locked.
+func (n *NameCom) GetDomainStatus(domain string) (*models.DomainStatus, error) {
+	d, err := n.client.GetDomain(&namecom.GetDomainRequest{DomainName: domain})
+	if err != nil {
+		return nil, err
+	}
+	status := &models.DomainStatus{Locked: &d.Locked, AutoRenew: &d.AutorenewEnabled}
+	if d.ExpireDate != "" {
+		if status.Expires, err = time.Parse(time.RFC3339, d.ExpireDate); err != nil {
+			return nil, errors.Wrapf(err, "expiration of %s", domain)
+		}
+	}
+	return status, nil
+}
+
+// GetDSRecords returns the DS records name.com publishes for domain.
+func (n *NameCom) GetDSRecords(domain string) ([]*models.DSRecord, error) {
+	response, err := n.client.ListDNSSECs(&namecom.ListDNSSECsRequest{DomainName: domain})
+	if err != nil {
+		return nil, err
+	}
+	var records []*models.DSRecord
+	for _, d := range response.Dnssec {
+		records = append(records, &models.DSRecord{
+			KeyTag:     uint16(d.KeyTag),
+			Algorithm:  uint8(d.Algorithm),
+			DigestType: uint8(d.DigestType),
+			Digest:     d.Digest,
+		})
+	}
+	return records, nil
+}
+
+// AddDSRecord publishes ds for domain.
+func (n *NameCom) AddDSRecord(domain string, ds *models.DSRecord) error {
+	_, err := n.client.CreateDNSSEC(&namecom.DNSSEC{
+		DomainName: domain,
+		KeyTag:     int32(ds.KeyTag),
+		Algorithm:  int32(ds.Algorithm),
+		DigestType: int32(ds.DigestType),
+		Digest:     ds.Digest,
+	})
+	return err
+}
+
+// RemoveDSRecord removes ds from domain.
+func (n *NameCom) RemoveDSRecord(domain string, ds *models.DSRecord) error {
+	_, err := n.client.DeleteDNSSEC(&namecom.DeleteDNSSECRequest{DomainName: domain, Digest: ds.Digest})
+	return err
+}
@@ -20,13 +20,15 @@ type NameCom struct {
 }
 
 var features = providers.DocumentationNotes{
-	providers.CanUseAlias:            providers.Can(),
-	providers.CanUsePTR:              providers.Cannot("PTR records are not supported (See Link)", "https://www.name.com/support/articles/205188508-Reverse-DNS-records"),
-	providers.CanUseSRV:              providers.Can(),
-	providers.CanUseTXTMulti:         providers.Cannot(),
-	providers.DocCreateDomains:       providers.Cannot("New domains require registration"),
-	providers.DocDualHost:            providers.Cannot("Apex NS records not editable"),
-	providers.DocOfficiallySupported: providers.Can(),
+	providers.CanCheckCredsWithNameservers: providers.Can(),
+	providers.CanUseAlias:                  providers.Can(),
+	providers.CanUsePTR:                    providers.Cannot("PTR records are not supported (See Link)", "https://www.name.com/support/articles/205188508-Reverse-DNS-records"),
+	providers.CanUseSRV:                    providers.Can(),
+	providers.CanUseTXTMulti:               providers.Cannot(),
+	providers.DocCreateDomains:             providers.Cannot("New domains require registration"),
+	providers.DocDualHost:                  providers.Cannot("Apex NS records not editable"),
+	providers.DocOfficiallySupported:       providers.Can(),
+	providers.CanPatchRecords:              providers.Can(),
 }
 
 func newReg(conf map[string]string) (providers.Registrar, error) {
@@ -48,18 +48,18 @@ func (n *NameCom) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Corre
 
 	for _, d := range del {
 		rec := d.Existing.Original.(*namecom.Record)
-		c := &models.Correction{Msg: d.String(), F: func() error { return n.deleteRecord(rec.ID, dc.Name) }}
+		c := &models.Correction{Msg: d.String(), Records: d.Records(), F: func() error { return n.deleteRecord(rec.ID, dc.Name) }}
 		corrections = append(corrections, c)
 	}
 	for _, cre := range create {
 		rec := cre.Desired
-		c := &models.Correction{Msg: cre.String(), F: func() error { return n.createRecord(rec, dc.Name) }}
+		c := &models.Correction{Msg: cre.String(), Records: cre.Records(), F: func() error { return n.createRecord(rec, dc.Name) }}
 		corrections = append(corrections, c)
 	}
 	for _, chng := range mod {
 		old := chng.Existing.Original.(*namecom.Record)
 		new := chng.Desired
-		c := &models.Correction{Msg: chng.String(), F: func() error {
+		c := &models.Correction{Msg: chng.String(), Records: chng.Records(), F: func() error {
 			err := n.deleteRecord(old.ID, dc.Name)
 			if err != nil {
 				return err
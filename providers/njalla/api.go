@@ -0,0 +1,124 @@
+package njalla
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const defaultBaseURL = "https://njal.la/api/1/"
+
+type request struct {
+	Method string      `json:"method"`
+	Params interface{} `json:"params"`
+}
+
+type response struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type domain struct {
+	Name        string   `json:"name"`
+	Status      string   `json:"status"`
+	Expiry      string   `json:"expiry"`
+	Nameservers []string `json:"nameservers,omitempty"`
+}
+
+type record struct {
+	ID      json.Number `json:"id,omitempty"`
+	Domain  string      `json:"domain,omitempty"`
+	Name    string      `json:"name,omitempty"`
+	Type    string      `json:"type,omitempty"`
+	Content string      `json:"content"`
+	TTL     uint32      `json:"ttl,omitempty"`
+	Prio    *uint16     `json:"prio,omitempty"`
+}
+
+// call sends a JSON-RPC request to the API and decodes the result into target (if not nil).
+func (api *Njalla) call(method string, params interface{}, target interface{}) error {
+	body, err := json.Marshal(&request{Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, api.baseURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Njalla "+api.token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("Njalla: bad status code %d calling %s", resp.StatusCode, method)
+	}
+	r := &response{}
+	if err := json.NewDecoder(resp.Body).Decode(r); err != nil {
+		return errors.Wrapf(err, "Njalla: decoding response of %s", method)
+	}
+	if r.Error != nil {
+		return errors.Errorf("Njalla: %s: %s (code %d)", method, r.Error.Message, r.Error.Code)
+	}
+	if target == nil {
+		return nil
+	}
+	return json.Unmarshal(r.Result, target)
+}
+
+func (api *Njalla) listDomains() ([]domain, error) {
+	var result struct {
+		Domains []domain `json:"domains"`
+	}
+	if err := api.call("list-domains", map[string]string{}, &result); err != nil {
+		return nil, err
+	}
+	return result.Domains, nil
+}
+
+func (api *Njalla) getDomain(name string) (*domain, error) {
+	d := &domain{}
+	if err := api.call("get-domain", map[string]string{"domain": name}, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (api *Njalla) setNameservers(name string, nss []string) error {
+	return api.call("edit-domain", map[string]interface{}{"domain": name, "nameservers": nss}, nil)
+}
+
+func (api *Njalla) listRecords(name string) ([]*record, error) {
+	var result struct {
+		Records []*record `json:"records"`
+	}
+	if err := api.call("list-records", map[string]string{"domain": name}, &result); err != nil {
+		return nil, err
+	}
+	return result.Records, nil
+}
+
+func (api *Njalla) addRecord(r *record) error {
+	return api.call("add-record", r, nil)
+}
+
+func (api *Njalla) editRecord(r *record) error {
+	return api.call("edit-record", r, nil)
+}
+
+func (api *Njalla) removeRecord(domain string, id json.Number) error {
+	return api.call("remove-record", map[string]interface{}{"domain": domain, "id": id}, nil)
+}
+
+func newClient() *http.Client {
+	return &http.Client{Timeout: 60 * time.Second}
+}
@@ -0,0 +1,274 @@
+package njalla
+
+/*
+
+Njalla API DNS provider and registrar:
+
+Info required in `creds.json`:
+   - token
+
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/providers"
+	"github.com/StackExchange/dnscontrol/providers/diff"
+	"github.com/pkg/errors"
+)
+
+var features = providers.DocumentationNotes{
+	providers.CanUseAlias:            providers.Can("Njalla calls these ANAME records"),
+	providers.CanUseCAA:              providers.Can(),
+	providers.CanUsePTR:              providers.Cannot(),
+	providers.CanUseNAPTR:            providers.Cannot(),
+	providers.CanUseSRV:              providers.Can(),
+	providers.CanUseSSHFP:            providers.Cannot(),
+	providers.CanUseTLSA:             providers.Cannot(),
+	providers.CanUseTXTMulti:         providers.Cannot(),
+	providers.DocCreateDomains:       providers.Cannot("Domains must be registered or added in the web interface"),
+	providers.DocDualHost:            providers.Cannot("Apex NS records can not be managed"),
+	providers.DocOfficiallySupported: providers.Cannot(),
+}
+
+func init() {
+	providers.RegisterDomainServiceProviderType("NJALLA", newDsp, features)
+	providers.RegisterRegistrarType("NJALLA", newReg)
+}
+
+var defaultNameServerNames = []string{
+	"1-you.njalla.no",
+	"2-can.njalla.in",
+	"3-get.njalla.fo",
+}
+
+// Njalla is the handle for this provider.
+type Njalla struct {
+	token   string
+	baseURL string
+	client  *http.Client
+}
+
+func newDsp(m map[string]string, metadata json.RawMessage) (providers.DNSServiceProvider, error) {
+	return newProvider(m)
+}
+
+func newReg(m map[string]string) (providers.Registrar, error) {
+	return newProvider(m)
+}
+
+func newProvider(m map[string]string) (*Njalla, error) {
+	api := &Njalla{
+		token:   m["token"],
+		baseURL: m["baseurl"],
+		client:  newClient(),
+	}
+	if api.token == "" {
+		return nil, errors.Errorf("Njalla token is required")
+	}
+	if api.baseURL == "" {
+		api.baseURL = defaultBaseURL
+	}
+	return api, nil
+}
+
+// GetNameservers returns the nameservers for a domain.
+func (api *Njalla) GetNameservers(domain string) ([]*models.Nameserver, error) {
+	return models.StringsToNameservers(defaultNameServerNames), nil
+}
+
+// GetZoneRecords returns the records of a zone.
+func (api *Njalla) GetZoneRecords(domain string) (models.Records, error) {
+	records, err := api.listRecords(domain)
+	if err != nil {
+		return nil, err
+	}
+	existing := models.Records{}
+	for _, r := range records {
+		rc, err := toRecordConfig(domain, r)
+		if err != nil {
+			return nil, err
+		}
+		existing = append(existing, rc)
+	}
+	models.PostProcessRecords(existing)
+	return existing, nil
+}
+
+// GetDomainCorrections returns a list of corrections to update a domain.
+func (api *Njalla) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	dc.Punycode()
+
+	// Njalla manages the apex NS records itself.
+	dc.Filter(func(r *models.RecordConfig) bool {
+		return !(r.Type == "NS" && r.GetLabel() == "@")
+	})
+
+	records, err := api.listRecords(dc.Name)
+	if err != nil {
+		return nil, err
+	}
+	existing := []*models.RecordConfig{}
+	for _, r := range records {
+		rc, err := toRecordConfig(dc.Name, r)
+		if err != nil {
+			return nil, err
+		}
+		existing = append(existing, rc)
+	}
+	models.PostProcessRecords(existing)
+
+	differ := diff.New(dc)
+	_, create, del, mod := differ.IncrementalDiff(existing)
+
+	corrections := []*models.Correction{}
+	for _, m := range del {
+		id := m.Existing.Original.(*record).ID
+		corrections = append(corrections, &models.Correction{
+			Msg:     fmt.Sprintf("%s, Njalla ID: %s", m, id),
+			Records: m.Records(),
+			F:       func() error { return api.removeRecord(dc.Name, id) },
+		})
+	}
+	for _, m := range create {
+		r := toNjallaRecord(dc.Name, m.Desired)
+		corrections = append(corrections, &models.Correction{
+			Msg:     m.String(),
+			Records: m.Records(),
+			F:       func() error { return api.addRecord(r) },
+		})
+	}
+	for _, m := range mod {
+		r := toNjallaRecord(dc.Name, m.Desired)
+		r.ID = m.Existing.Original.(*record).ID
+		// The name and type of a record can not be edited.
+		r.Name, r.Type = "", ""
+		corrections = append(corrections, &models.Correction{
+			Msg:     fmt.Sprintf("%s, Njalla ID: %s", m, r.ID),
+			Records: m.Records(),
+			F:       func() error { return api.editRecord(r) },
+		})
+	}
+	return corrections, nil
+}
+
+// GetRegistrarCorrections returns a list of corrections for this registrar.
+func (api *Njalla) GetRegistrarCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	d, err := api.getDomain(dc.Name)
+	if err != nil {
+		return nil, err
+	}
+	found := make([]string, len(d.Nameservers))
+	for i, ns := range d.Nameservers {
+		found[i] = strings.TrimSuffix(strings.ToLower(ns), ".")
+	}
+	sort.Strings(found)
+	foundNameservers := strings.Join(found, ",")
+
+	expected := []string{}
+	for _, ns := range dc.Nameservers {
+		expected = append(expected, ns.Name)
+	}
+	sort.Strings(expected)
+	expectedNameservers := strings.Join(expected, ",")
+
+	if foundNameservers == expectedNameservers {
+		return nil, nil
+	}
+	return []*models.Correction{
+		{
+			Msg: fmt.Sprintf("Update nameservers %s -> %s", foundNameservers, expectedNameservers),
+			F:   func() error { return api.setNameservers(dc.Name, expected) },
+		},
+	}, nil
+}
+
+// GetDomainStatus returns when domain expires. Njalla doesn't say whether
+// domains are locked.
+func (api *Njalla) GetDomainStatus(domain string) (*models.DomainStatus, error) {
+	d, err := api.getDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+	status := &models.DomainStatus{}
+	if d.Expiry != "" {
+		if status.Expires, err = time.Parse(time.RFC3339, d.Expiry); err != nil {
+			if status.Expires, err = time.Parse("2006-01-02", d.Expiry); err != nil {
+				return nil, errors.Wrapf(err, "expiration of %s", domain)
+			}
+		}
+	}
+	return status, nil
+}
+
+// toRecordConfig converts a Njalla record to a RecordConfig. #rtype_variations
+func toRecordConfig(origin string, r *record) (*models.RecordConfig, error) {
+	rc := &models.RecordConfig{
+		Type:     r.Type,
+		TTL:      r.TTL,
+		Original: r,
+	}
+	rc.SetLabel(r.Name, origin)
+
+	var prio uint16
+	if r.Prio != nil {
+		prio = *r.Prio
+	}
+	content := r.Content
+	switch r.Type {
+	case "ANAME":
+		rc.Type = "ALIAS"
+		return rc, rc.SetTarget(fqdn(content))
+	case "CNAME", "NS":
+		return rc, rc.SetTarget(fqdn(content))
+	case "MX":
+		return rc, rc.SetTargetMX(prio, fqdn(content))
+	case "SRV":
+		// Njalla stores "weight port target" and the priority separately.
+		return rc, rc.SetTargetSRVPriorityString(prio, content)
+	case "TXT":
+		return rc, rc.SetTargetTXT(content)
+	default:
+		return rc, rc.PopulateFromString(r.Type, content, origin)
+	}
+}
+
+// toNjallaRecord converts a RecordConfig to a Njalla record. #rtype_variations
+func toNjallaRecord(origin string, rc *models.RecordConfig) *record {
+	r := &record{
+		Domain:  origin,
+		Name:    rc.GetLabel(),
+		Type:    rc.Type,
+		Content: rc.GetTargetField(),
+		TTL:     rc.TTL,
+	}
+	switch rc.Type {
+	case "ALIAS":
+		r.Type = "ANAME"
+	case "MX":
+		r.Prio = &rc.MxPreference
+	case "SRV":
+		r.Prio = &rc.SrvPriority
+		r.Content = fmt.Sprintf("%d %d %s", rc.SrvWeight, rc.SrvPort, rc.GetTargetField())
+	case "CAA":
+		r.Content = fmt.Sprintf("%d %s %s", rc.CaaFlag, rc.CaaTag, rc.GetTargetField())
+	case "TXT":
+		if len(rc.TxtStrings) > 0 {
+			r.Content = strings.Join(rc.TxtStrings, "")
+		}
+	}
+	return r
+}
+
+func fqdn(s string) string {
+	if strings.HasSuffix(s, ".") {
+		return s
+	}
+	return s + "."
+}
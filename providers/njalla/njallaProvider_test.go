@@ -0,0 +1,31 @@
+package njalla
+
+import (
+	"testing"
+)
+
+func TestConversion(t *testing.T) {
+	prio := func(p uint16) *uint16 { return &p }
+	records := []*record{
+		{Name: "@", Type: "A", Content: "192.0.2.1", TTL: 300},
+		{Name: "www", Type: "CNAME", Content: "example.com.", TTL: 300},
+		{Name: "@", Type: "ANAME", Content: "lb.example.net.", TTL: 300},
+		{Name: "@", Type: "MX", Content: "mail.example.com.", TTL: 3600, Prio: prio(10)},
+		{Name: "_sip._tcp", Type: "SRV", Content: "5 5060 sip.example.com.", TTL: 300, Prio: prio(20)},
+		{Name: "@", Type: "TXT", Content: "v=spf1 -all", TTL: 300},
+		{Name: "@", Type: "CAA", Content: "0 issue letsencrypt.org", TTL: 300},
+	}
+	for _, r := range records {
+		rc, err := toRecordConfig("example.com", r)
+		if err != nil {
+			t.Fatalf("%s %s: %s", r.Type, r.Name, err)
+		}
+		back := toNjallaRecord("example.com", rc)
+		if back.Type != r.Type || back.Name != r.Name || back.Content != r.Content || back.TTL != r.TTL {
+			t.Errorf("round trip mismatch: %+v -> %+v", r, back)
+		}
+		if (r.Prio == nil) != (back.Prio == nil) || (r.Prio != nil && *r.Prio != *back.Prio) {
+			t.Errorf("prio mismatch: %+v -> %+v", r, back)
+		}
+	}
+}
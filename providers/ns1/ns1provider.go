@@ -19,13 +19,14 @@ import (
 )
 
 var docNotes = providers.DocumentationNotes{
-	providers.DocCreateDomains:       providers.Cannot(),
-	providers.DocOfficiallySupported: providers.Cannot(),
-	providers.DocDualHost:            providers.Can(),
+	providers.CanCheckCredsWithNameservers: providers.Can(),
+	providers.DocCreateDomains:             providers.Cannot(),
+	providers.DocOfficiallySupported:       providers.Cannot(),
+	providers.DocDualHost:                  providers.Can(),
 }
 
 func init() {
-	providers.RegisterDomainServiceProviderType("NS1", newProvider, providers.CanUseSRV, docNotes)
+	providers.RegisterDomainServiceProviderType("NS1", newProvider, providers.CanUseSRV, providers.CanUseDNAME, docNotes)
 }
 
 type nsone struct {
@@ -78,23 +79,28 @@ func (n *nsone) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correct
 		desc := strings.Join(descs, "\n")
 		_, current := foundGrouped[k]
 		recs, wanted := desiredGrouped[k]
+		changed := append(models.Records{}, foundGrouped[k]...)
+		changed = append(changed, recs...)
 		if wanted && !current {
 			// pure addition
 			corrections = append(corrections, &models.Correction{
-				Msg: desc,
-				F:   func() error { return n.add(recs, dc.Name) },
+				Msg:     desc,
+				Records: changed,
+				F:       func() error { return n.add(recs, dc.Name) },
 			})
 		} else if current && !wanted {
 			// pure deletion
 			corrections = append(corrections, &models.Correction{
-				Msg: desc,
-				F:   func() error { return n.remove(key, dc.Name) },
+				Msg:     desc,
+				Records: changed,
+				F:       func() error { return n.remove(key, dc.Name) },
 			})
 		} else {
 			// modification
 			corrections = append(corrections, &models.Correction{
-				Msg: desc,
-				F:   func() error { return n.modify(recs, dc.Name) },
+				Msg:     desc,
+				Records: changed,
+				F:       func() error { return n.modify(recs, dc.Name) },
 			})
 		}
 	}
@@ -28,6 +28,7 @@ import (
 	"strings"
 
 	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
 	"github.com/StackExchange/dnscontrol/providers"
 	"github.com/StackExchange/dnscontrol/providers/diff"
 	"github.com/StackExchange/dnscontrol/providers/octodns/octoyaml"
@@ -41,6 +42,7 @@ var features = providers.DocumentationNotes{
 	//providers.CanUseTXTMulti:   providers.Can(),
 	providers.DocCreateDomains: providers.Cannot("Driver just maintains list of OctoDNS config files. You must manually create the master config files that refer these."),
 	providers.DocDualHost:      providers.Cannot("Research is needed."),
+	providers.CanPatchRecords:  providers.Can(),
 }
 
 func initProvider(config map[string]string, providermeta json.RawMessage) (providers.DNSServiceProvider, error) {
@@ -147,7 +149,7 @@ func (c *Provider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Corr
 			&models.Correction{
 				Msg: msg,
 				F: func() error {
-					fmt.Printf("CREATING CONFIGFILE: %v\n", zoneFileName)
+					printer.Printf("CREATING CONFIGFILE: %v\n", zoneFileName)
 					zf, err := os.Create(zoneFileName)
 					if err != nil {
 						log.Fatalf("Could not create zonefile: %v", err)
@@ -138,7 +138,7 @@ func parseLeaf(results models.Records, k string, v interface{}, origin string) (
 					return nil, errors.Errorf("parseLeaf: unknown type in values: rtpe=%s k=%s k2=%s v2.(type)=%T v2=%v", rType, k, k2, v2, v2)
 				}
 			default:
-				panic("Should not happen")
+				return nil, errors.Errorf("parseLeaf: unknown key %q in %s", k2, k)
 			}
 		} else if typeof(k2) == "string" && typeof(v2) == "[]interface {}" {
 			// The 2nd level key is a string, and the 2nd level value is a list.
@@ -211,7 +211,7 @@ func parseLeaf(results models.Records, k string, v interface{}, origin string) (
 			case "SRV":
 				r.MxPreference = 0
 			default:
-				panic("ugh")
+				return nil, errors.Errorf("parseLeaf: %s records of %s can't have a list of priorities and values", r.Type, k)
 			}
 		}
 	} else if rTarget != "" && len(rTargets) == 0 {
@@ -267,5 +267,5 @@ func decodeTTL(ttl interface{}) (uint32, error) {
 		}
 		return uint32(i), nil
 	}
-	panic("I don't know what type this TTL is")
+	return 0, errors.Errorf("unknown type of ttl (%v)", ttl)
 }
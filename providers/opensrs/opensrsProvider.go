@@ -1,16 +1,28 @@
 package opensrs
 
+/*
+
+OpenSRS (Tucows) registrar:
+
+Info required in `creds.json`:
+   - username (the reseller username)
+   - apikey
+   - baseurl (optional; use https://horizon.opensrs.net:55443 for the test environment)
+
+OpenSRS only accepts API calls from IP addresses that have been
+allowed in the reseller control panel.
+
+*/
+
 import (
-	"encoding/json"
-	"errors"
 	"fmt"
 	"sort"
 	"strings"
 
 	"github.com/StackExchange/dnscontrol/models"
 	"github.com/StackExchange/dnscontrol/providers"
-
-	opensrs "github.com/philhug/opensrs-go/opensrs"
+	"github.com/StackExchange/dnscontrol/providers/opensrs/xcp"
+	"github.com/pkg/errors"
 )
 
 var docNotes = providers.DocumentationNotes{
@@ -20,27 +32,32 @@ var docNotes = providers.DocumentationNotes{
 }
 
 func init() {
-	providers.RegisterRegistrarType("OPENSRS", newReg)
+	providers.RegisterRegistrarType("OPENSRS", newReg, docNotes)
 }
 
+const defaultBaseURL = "https://rr-n1-tor.opensrs.net:55443"
+
 var defaultNameServerNames = []string{
 	"ns1.systemdns.com",
 	"ns2.systemdns.com",
 	"ns3.systemdns.com",
 }
 
+// OpenSRSApi is the handle for this registrar.
 type OpenSRSApi struct {
 	UserName string // reseller user name
 	ApiKey   string // API Key
 
-	BaseURL string          // An alternate base URI
-	client  *opensrs.Client // Client
+	BaseURL string      // An alternate base URI
+	client  *xcp.Client // Client
 }
 
+// GetNameservers returns the default OpenSRS nameservers.
 func (c *OpenSRSApi) GetNameservers(domainName string) ([]*models.Nameserver, error) {
 	return models.StringsToNameservers(defaultNameServerNames), nil
 }
 
+// GetRegistrarCorrections returns a list of corrections for this registrar.
 func (c *OpenSRSApi) GetRegistrarCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
 	corrections := []*models.Correction{}
 
@@ -60,6 +77,13 @@ func (c *OpenSRSApi) GetRegistrarCorrections(dc *models.DomainConfig) ([]*models
 	expected := strings.Join(expectedSet, ",")
 
 	if actual != expected {
+		locked, err := c.isLocked(dc.Name)
+		if err != nil {
+			return nil, err
+		}
+		if locked {
+			return nil, errors.Errorf("OpenSRS: %s is locked; unlock it to update its nameservers %s -> %s", dc.Name, actual, expected)
+		}
 		return []*models.Correction{
 			{
 				Msg: fmt.Sprintf("Update nameservers %s -> %s", actual, expected),
@@ -73,72 +97,89 @@ func (c *OpenSRSApi) GetRegistrarCorrections(dc *models.DomainConfig) ([]*models
 
 // OpenSRS calls
 
-func (c *OpenSRSApi) getClient() *opensrs.Client {
-	return c.client
-}
-
-// Returns the name server names that should be used. If the domain is registered
-// then this method will return the delegation name servers. If this domain
-// is hosted only, then it will return the default OpenSRS name servers.
+// Returns the delegation name servers of a domain in the reseller's
+// portfolio.
 func (c *OpenSRSApi) getNameservers(domainName string) ([]string, error) {
-	client := c.getClient()
-
-	status, err := client.Domains.GetDomain(domainName, "status", 1)
+	resp, err := c.client.Call("DOMAIN", "GET", map[string]interface{}{
+		"domain": domainName,
+		"type":   "nameservers",
+	})
 	if err != nil {
 		return nil, err
 	}
+	return parseNameserverList(resp.Attributes["nameserver_list"])
+}
 
-	if status.Attributes.LockState == "0" {
-		dom, err := client.Domains.GetDomain(domainName, "nameservers", 1)
-		if err != nil {
-			return nil, err
+// isLocked returns whether a domain is locked, which makes OpenSRS refuse
+// to change its nameservers.
+func (c *OpenSRSApi) isLocked(domainName string) (bool, error) {
+	resp, err := c.client.Call("DOMAIN", "GET", map[string]interface{}{
+		"domain": domainName,
+		"type":   "status",
+	})
+	if err != nil {
+		return false, err
+	}
+	return xcp.String(resp.Attributes["lock_state"]) != "0", nil
+}
+
+// parseNameserverList extracts the names from a nameserver_list attribute.
+func parseNameserverList(v interface{}) ([]string, error) {
+	if v == nil || v == "" {
+		return []string{}, nil
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("OpenSRS: unexpected nameserver_list %#v", v)
+	}
+	names := []string{}
+	for _, e := range list {
+		ns, ok := e.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("OpenSRS: unexpected nameserver_list entry %#v", e)
 		}
-		return dom.Attributes.NameserverList.ToString(), nil
-	} else {
-		return nil, errors.New("Domain is locked")
+		names = append(names, strings.TrimSuffix(strings.ToLower(xcp.String(ns["name"])), "."))
 	}
+	return names, nil
 }
 
 // Returns a function that can be invoked to change the delegation of the domain to the given name server names.
 func (c *OpenSRSApi) updateNameserversFunc(nameServerNames []string, domainName string) func() error {
 	return func() error {
-		client := c.getClient()
-
-		_, err := client.Domains.UpdateDomainNameservers(domainName, nameServerNames)
-		if err != nil {
-			return err
-		}
-		return nil
+		_, err := c.client.Call("DOMAIN", "ADVANCED_UPDATE_NAMESERVERS", map[string]interface{}{
+			"domain":    domainName,
+			"op_type":   "assign",
+			"assign_ns": nameServerNames,
+		})
+		return err
 	}
 }
 
 // constructors
 
 func newReg(conf map[string]string) (providers.Registrar, error) {
-	return newProvider(conf, nil)
+	return newProvider(conf)
 }
 
-func newProvider(m map[string]string, metadata json.RawMessage) (*OpenSRSApi, error) {
+func newProvider(m map[string]string) (*OpenSRSApi, error) {
 	api := &OpenSRSApi{}
 	api.ApiKey = m["apikey"]
 
 	if api.ApiKey == "" {
-		return nil, fmt.Errorf("OpenSRS apikey must be provided.")
+		return nil, errors.Errorf("OpenSRS apikey must be provided")
 	}
 
 	api.UserName = m["username"]
 	if api.UserName == "" {
-		return nil, fmt.Errorf("OpenSRS username key must be provided.")
+		return nil, errors.Errorf("OpenSRS username key must be provided")
 	}
 
+	api.BaseURL = defaultBaseURL
 	if m["baseurl"] != "" {
 		api.BaseURL = m["baseurl"]
 	}
 
-	api.client = opensrs.NewClient(opensrs.NewApiKeyMD5Credentials(api.UserName, api.ApiKey))
-	if api.BaseURL != "" {
-		api.client.BaseURL = api.BaseURL
-	}
+	api.client = xcp.NewClient(api.UserName, api.ApiKey, api.BaseURL)
 
 	return api, nil
 }
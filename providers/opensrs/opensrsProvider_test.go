@@ -0,0 +1,70 @@
+package opensrs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/providers/opensrs/xcp"
+)
+
+func TestParseNameserverList(t *testing.T) {
+	list := []interface{}{
+		map[string]interface{}{"name": "NS1.example.com.", "sortorder": "1"},
+		map[string]interface{}{"name": "ns2.example.com", "sortorder": "2"},
+	}
+	names, err := parseNameserverList(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"ns1.example.com", "ns2.example.com"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+	if _, err := parseNameserverList("garbage"); err == nil {
+		t.Error("expected error for malformed nameserver_list")
+	}
+}
+
+func TestLockedDomain(t *testing.T) {
+	lockState := "1"
+	updated := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, err := xcp.DecodeResponse(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		attributes := map[string]interface{}{}
+		switch xcp.String(req.Attributes["type"]) {
+		case "status":
+			attributes["lock_state"] = lockState
+		case "nameservers":
+			attributes["nameserver_list"] = []interface{}{map[string]interface{}{"name": "ns1.example.net"}}
+		default:
+			updated = true
+		}
+		b, _ := xcp.Encode(map[string]interface{}{"is_success": "1", "response_code": "200", "attributes": attributes})
+		w.Write(b)
+	}))
+	defer server.Close()
+
+	api, err := newProvider(map[string]string{"apikey": "key", "username": "user", "baseurl": server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dc := &models.DomainConfig{Name: "example.com", Nameservers: models.StringsToNameservers([]string{"ns1.example.org"})}
+	if _, err := api.GetRegistrarCorrections(dc); err == nil {
+		t.Error("expected an error for a locked domain")
+	}
+
+	lockState = "0"
+	corrections, err := api.GetRegistrarCorrections(dc)
+	if err != nil || len(corrections) != 1 {
+		t.Fatalf("got corrections %v, %v; want one", corrections, err)
+	}
+	if err := corrections[0].F(); err != nil || !updated {
+		t.Errorf("nameservers not updated: %v", err)
+	}
+}
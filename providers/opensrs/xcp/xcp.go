@@ -0,0 +1,291 @@
+// Package xcp implements the XML-over-HTTPS protocol (XCP) used by the
+// OpenSRS family of Tucows APIs.
+//
+// Requests and responses are OPS envelopes whose payload is a tree of
+// dt_assoc (maps), dt_array (lists) and plain strings.  This package only
+// deals with the envelope, the encoding and the authentication; the
+// objects and actions are up to the caller.  That keeps it usable for any
+// service that speaks XCP (domains, DNS, email, ...).
+package xcp
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Client sends XCP requests.
+//
+// OpenSRS authenticates a request by the reseller username and a signature
+// made with the API key.  In addition, requests are only accepted from IP
+// addresses that have been allowed in the reseller web interface.
+type Client struct {
+	Username   string
+	Key        string
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client that signs requests with username and key.
+func NewClient(username, key, baseURL string) *Client {
+	return &Client{
+		Username:   username,
+		Key:        key,
+		BaseURL:    baseURL,
+		HTTPClient: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// Response is a decoded OPS response.
+type Response struct {
+	Success      bool
+	ResponseCode string
+	ResponseText string
+	Attributes   map[string]interface{}
+}
+
+// Call performs action on object. attributes may contain strings, numbers,
+// []string, []interface{} and map[string]interface{} values.  It returns an
+// error if the request could not be made or if OpenSRS reports a failure.
+func (c *Client) Call(object, action string, attributes map[string]interface{}) (*Response, error) {
+	body, err := Encode(map[string]interface{}{
+		"protocol":   "XCP",
+		"object":     object,
+		"action":     action,
+		"attributes": attributes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.BaseURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "text/xml")
+	req.Header.Set("X-Username", c.Username)
+	req.Header.Set("X-Signature", Sign(body, c.Key))
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("OpenSRS %s %s: bad status code %d", action, object, resp.StatusCode)
+	}
+	r, err := DecodeResponse(bytes.NewReader(b))
+	if err != nil {
+		return nil, errors.Wrapf(err, "OpenSRS %s %s", action, object)
+	}
+	if !r.Success {
+		return r, errors.Errorf("OpenSRS %s %s: %s (%s)", action, object, r.ResponseText, r.ResponseCode)
+	}
+	return r, nil
+}
+
+// Sign returns the X-Signature header value for body: md5(md5(body+key)+key).
+func Sign(body []byte, key string) string {
+	h := md5.Sum(append(append([]byte{}, body...), key...))
+	h = md5.Sum([]byte(hex.EncodeToString(h[:]) + key))
+	return hex.EncodeToString(h[:])
+}
+
+// Encode wraps data in an OPS envelope.
+func Encode(data map[string]interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString("<?xml version='1.0' encoding='UTF-8' standalone='no' ?>\n")
+	buf.WriteString("<!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'>\n")
+	buf.WriteString("<OPS_envelope><header><version>0.9</version></header><body><data_block>")
+	if err := encodeValue(buf, data); err != nil {
+		return nil, err
+	}
+	buf.WriteString("</data_block></body></OPS_envelope>\n")
+	return buf.Bytes(), nil
+}
+
+func encodeValue(buf *bytes.Buffer, v interface{}) error {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		buf.WriteString("<dt_assoc>")
+		for _, k := range keys {
+			if err := encodeItem(buf, k, t[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("</dt_assoc>")
+	case []interface{}:
+		buf.WriteString("<dt_array>")
+		for i, e := range t {
+			if err := encodeItem(buf, strconv.Itoa(i), e); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("</dt_array>")
+	case []string:
+		l := make([]interface{}, len(t))
+		for i, s := range t {
+			l[i] = s
+		}
+		return encodeValue(buf, l)
+	case string:
+		return xml.EscapeText(buf, []byte(t))
+	case int, int64, uint16, uint32:
+		fmt.Fprintf(buf, "%d", t)
+	default:
+		return errors.Errorf("xcp: can not encode %T", v)
+	}
+	return nil
+}
+
+func encodeItem(buf *bytes.Buffer, key string, v interface{}) error {
+	buf.WriteString(`<item key="`)
+	if err := xml.EscapeText(buf, []byte(key)); err != nil {
+		return err
+	}
+	buf.WriteString(`">`)
+	if err := encodeValue(buf, v); err != nil {
+		return err
+	}
+	buf.WriteString("</item>")
+	return nil
+}
+
+// DecodeResponse parses an OPS envelope.
+func DecodeResponse(r io.Reader) (*Response, error) {
+	data, err := Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("xcp: response is not a dt_assoc")
+	}
+	resp := &Response{
+		Success:      m["is_success"] == "1",
+		ResponseCode: String(m["response_code"]),
+		ResponseText: String(m["response_text"]),
+	}
+	resp.Attributes, _ = m["attributes"].(map[string]interface{})
+	return resp, nil
+}
+
+// Decode returns the contents of the data_block of an OPS envelope.  Maps
+// are returned as map[string]interface{}, lists as []interface{} and
+// everything else as string.
+func Decode(r io.Reader) (interface{}, error) {
+	d := xml.NewDecoder(r)
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, errors.Wrap(err, "xcp: no data_block found")
+		}
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == "data_block" {
+			return decodeValue(d)
+		}
+	}
+}
+
+// decodeValue consumes the contents of the current element, including
+// its end tag.
+func decodeValue(d *xml.Decoder) (interface{}, error) {
+	var text bytes.Buffer
+	var value interface{}
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "dt_assoc":
+				value, err = decodeItems(d, false)
+			case "dt_array":
+				value, err = decodeItems(d, true)
+			case "dt_scalar":
+				value, err = decodeValue(d)
+			default:
+				err = d.Skip()
+			}
+			if err != nil {
+				return nil, err
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			if value != nil {
+				return value, nil
+			}
+			return strings.TrimSpace(text.String()), nil
+		}
+	}
+}
+
+// decodeItems consumes the items of a dt_assoc or dt_array.
+func decodeItems(d *xml.Decoder, isArray bool) (interface{}, error) {
+	m := map[string]interface{}{}
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local != "item" {
+				if err := d.Skip(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			key := ""
+			for _, a := range t.Attr {
+				if a.Name.Local == "key" {
+					key = a.Value
+				}
+			}
+			v, err := decodeValue(d)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = v
+		case xml.EndElement:
+			if !isArray {
+				return m, nil
+			}
+			l := make([]interface{}, len(m))
+			for k, v := range m {
+				i, err := strconv.Atoi(k)
+				if err != nil || i < 0 || i >= len(l) {
+					return nil, errors.Errorf("xcp: invalid dt_array index %q", k)
+				}
+				l[i] = v
+			}
+			return l, nil
+		}
+	}
+}
+
+// String returns v if it is a string, or "" otherwise.
+func String(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
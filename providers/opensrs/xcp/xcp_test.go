@@ -0,0 +1,83 @@
+package xcp
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	in := map[string]interface{}{
+		"action": "ADVANCED_UPDATE_NAMESERVERS",
+		"attributes": map[string]interface{}{
+			"domain":    "example.com",
+			"assign_ns": []string{"ns1.example.net", "ns2.example.net"},
+			"note":      "a<b & c",
+		},
+	}
+	b, err := Encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"action": "ADVANCED_UPDATE_NAMESERVERS",
+		"attributes": map[string]interface{}{
+			"domain":    "example.com",
+			"assign_ns": []interface{}{"ns1.example.net", "ns2.example.net"},
+			"note":      "a<b & c",
+		},
+	}
+	if !reflect.DeepEqual(out, expected) {
+		t.Errorf("expected %#v, got %#v", expected, out)
+	}
+}
+
+func TestDecodeResponse(t *testing.T) {
+	body := `<?xml version='1.0' encoding='UTF-8' standalone='no' ?>
+<!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'>
+<OPS_envelope>
+ <header><version>0.9</version></header>
+ <body>
+  <data_block>
+   <dt_assoc>
+    <item key="protocol">XCP</item>
+    <item key="is_success">1</item>
+    <item key="response_code">200</item>
+    <item key="response_text">Query Successful</item>
+    <item key="attributes">
+     <dt_assoc>
+      <item key="nameserver_list">
+       <dt_array>
+        <item key="1"><dt_assoc><item key="name">ns2.systemdns.com</item></dt_assoc></item>
+        <item key="0"><dt_assoc><item key="name">ns1.systemdns.com</item></dt_assoc></item>
+       </dt_array>
+      </item>
+     </dt_assoc>
+    </item>
+   </dt_assoc>
+  </data_block>
+ </body>
+</OPS_envelope>`
+	r, err := DecodeResponse(bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Success || r.ResponseCode != "200" {
+		t.Errorf("unexpected status: %+v", r)
+	}
+	list := r.Attributes["nameserver_list"].([]interface{})
+	if len(list) != 2 || String(list[0].(map[string]interface{})["name"]) != "ns1.systemdns.com" {
+		t.Errorf("unexpected nameserver_list: %#v", list)
+	}
+}
+
+func TestSign(t *testing.T) {
+	// md5(md5("<xml/>" + "key") + "key")
+	if s := Sign([]byte("<xml/>"), "key"); s != "0ad69e2131f761e92c1a47e3d6b0d29f" {
+		t.Errorf("unexpected signature %s", s)
+	}
+}
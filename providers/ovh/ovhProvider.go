@@ -19,21 +19,23 @@ type ovhProvider struct {
 }
 
 var features = providers.DocumentationNotes{
-	providers.CanUseAlias:            providers.Cannot(),
-	providers.CanUseCAA:              providers.Can(),
-	providers.CanUsePTR:              providers.Cannot(),
-	providers.CanUseSRV:              providers.Can(),
-	providers.CanUseTLSA:             providers.Can(),
-	providers.CanUseSSHFP:            providers.Can(),
-	providers.DocCreateDomains:       providers.Cannot("New domains require registration"),
-	providers.DocDualHost:            providers.Can(),
-	providers.DocOfficiallySupported: providers.Cannot(),
+	providers.CanCheckCredsWithNameservers: providers.Can(),
+	providers.CanUseAlias:                  providers.Cannot(),
+	providers.CanUseCAA:                    providers.Can(),
+	providers.CanUsePTR:                    providers.Cannot(),
+	providers.CanUseSRV:                    providers.Can(),
+	providers.CanUseTLSA:                   providers.Can(),
+	providers.CanUseSSHFP:                  providers.Can(),
+	providers.DocCreateDomains:             providers.Cannot("New domains require registration"),
+	providers.DocDualHost:                  providers.Can(),
+	providers.DocOfficiallySupported:       providers.Cannot(),
+	providers.CanPatchRecords:              providers.Can(),
 }
 
 func newOVH(m map[string]string, metadata json.RawMessage) (*ovhProvider, error) {
 	appKey, appSecretKey, consumerKey := m["app-key"], m["app-secret-key"], m["consumer-key"]
 
-	c,err := ovh.NewClient(ovh.OvhEU, appKey, appSecretKey, consumerKey)
+	c, err := ovh.NewClient(ovh.OvhEU, appKey, appSecretKey, consumerKey)
 	if c == nil {
 		return nil, err
 	}
@@ -54,7 +56,7 @@ func newReg(conf map[string]string) (providers.Registrar, error) {
 }
 
 func init() {
-	providers.RegisterRegistrarType("OVH", newReg)
+	providers.RegisterRegistrarType("OVH", newReg, providers.CanUseRegistrarLock)
 	providers.RegisterDomainServiceProviderType("OVH", newDsp, features)
 }
 
@@ -112,16 +114,18 @@ func (c *ovhProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.C
 	for _, del := range delete {
 		rec := del.Existing.Original.(*Record)
 		corrections = append(corrections, &models.Correction{
-			Msg: del.String(),
-			F:   c.deleteRecordFunc(rec.ID, dc.Name),
+			Msg:     del.String(),
+			Records: del.Records(),
+			F:       c.deleteRecordFunc(rec.ID, dc.Name),
 		})
 	}
 
 	for _, cre := range create {
 		rec := cre.Desired
 		corrections = append(corrections, &models.Correction{
-			Msg: cre.String(),
-			F:   c.createRecordFunc(rec, dc.Name),
+			Msg:     cre.String(),
+			Records: cre.Records(),
+			F:       c.createRecordFunc(rec, dc.Name),
 		})
 	}
 
@@ -129,8 +133,9 @@ func (c *ovhProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.C
 		oldR := mod.Existing.Original.(*Record)
 		newR := mod.Desired
 		corrections = append(corrections, &models.Correction{
-			Msg: mod.String(),
-			F:   c.updateRecordFunc(oldR, newR, dc.Name),
+			Msg:     mod.String(),
+			Records: mod.Records(),
+			F:       c.updateRecordFunc(oldR, newR, dc.Name),
 		})
 	}
 
@@ -212,3 +217,13 @@ func (c *ovhProvider) GetRegistrarCorrections(dc *models.DomainConfig) ([]*model
 
 	return nil, nil
 }
+
+// GetDomainStatus returns when domain expires, and whether it is locked.
+func (c *ovhProvider) GetDomainStatus(domain string) (*models.DomainStatus, error) {
+	return c.fetchDomainStatus(domain)
+}
+
+// SetRegistrarLock locks or unlocks domain against transfers.
+func (c *ovhProvider) SetRegistrarLock(domain string, locked bool) error {
+	return c.updateTransferLock(domain, locked)
+}
@@ -3,6 +3,7 @@ package ovh
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/StackExchange/dnscontrol/models"
 	"github.com/miekg/dns/dnsutil"
@@ -244,6 +245,14 @@ type Domain struct {
 	TransferLockStatus string `json:"transferLockStatus,omitempty"`
 }
 
+func (c *ovhProvider) updateTransferLock(fqdn string, locked bool) error {
+	domain := Domain{TransferLockStatus: "unlocked"}
+	if locked {
+		domain.TransferLockStatus = "locked"
+	}
+	return c.client.CallAPI("PUT", fmt.Sprintf("/domain/%s", fqdn), &domain, &Void{}, true)
+}
+
 func (c *ovhProvider) updateNS(fqdn string, ns []string) error {
 	// we first need to make sure we can edit the NS
 	// by default zones are in "hosted" mode meaning they default
@@ -282,3 +291,41 @@ func (c *ovhProvider) updateNS(fqdn string, ns []string) error {
 
 	return nil
 }
+
+// ServiceInfos describes the subscription of a domain in ovh's protocol.
+type ServiceInfos struct {
+	Expiration string `json:"expiration,omitempty"`
+	Renew      struct {
+		Automatic bool `json:"automatic"`
+	} `json:"renew"`
+}
+
+// Retrieve the expiration and transfer lock of a domain
+func (c *ovhProvider) fetchDomainStatus(fqdn string) (*models.DomainStatus, error) {
+	var domain Domain
+	if err := c.client.CallAPI("GET", "/domain/"+fqdn, nil, &domain, true); err != nil {
+		return nil, err
+	}
+	var infos ServiceInfos
+	if err := c.client.CallAPI("GET", "/domain/"+fqdn+"/serviceInfos", nil, &infos, true); err != nil {
+		return nil, err
+	}
+	status := &models.DomainStatus{AutoRenew: &infos.Renew.Automatic}
+	if infos.Expiration != "" {
+		expires, err := time.Parse("2006-01-02", infos.Expiration)
+		if err != nil {
+			return nil, errors.Wrapf(err, "expiration of %s", fqdn)
+		}
+		status.Expires = expires
+	}
+	// While "locking" or "unlocking", the lock is still as it was.
+	switch domain.TransferLockStatus {
+	case "locked", "unlocking":
+		locked := true
+		status.Locked = &locked
+	case "unlocked", "locking":
+		locked := false
+		status.Locked = &locked
+	}
+	return status, nil
+}
@@ -0,0 +1,101 @@
+package plesk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The Plesk REST API. See https://docs.plesk.com/en-US/obsidian/api-rpc/about-rest-api.79359/
+
+type dnsRecord struct {
+	ID    int    `json:"id,omitempty"`
+	Type  string `json:"type"`
+	Host  string `json:"host"`
+	Value string `json:"value"`
+	Opt   string `json:"opt,omitempty"`
+}
+
+type domain struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// request sends a request to the API and decodes the response into target (if not nil).
+func (api *Plesk) request(method, path string, query url.Values, body, target interface{}) error {
+	u := api.baseURL + "/api/v2/" + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var reqBody []byte
+	if body != nil {
+		var err error
+		if reqBody, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, u, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	if api.apiKey != "" {
+		req.Header.Set("X-API-Key", api.apiKey)
+	} else {
+		req.SetBasicAuth(api.username, api.password)
+	}
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var e struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(b, &e) == nil && e.Message != "" {
+			return errors.Errorf("Plesk: %s %s: %s", method, path, e.Message)
+		}
+		return errors.Errorf("Plesk: %s %s: bad status code %d", method, path, resp.StatusCode)
+	}
+	if target == nil {
+		return nil
+	}
+	return json.Unmarshal(b, target)
+}
+
+func (api *Plesk) listDomains() ([]domain, error) {
+	domains := []domain{}
+	err := api.request(http.MethodGet, "domains", nil, nil, &domains)
+	return domains, err
+}
+
+func (api *Plesk) listRecords(domain string) ([]*dnsRecord, error) {
+	records := []*dnsRecord{}
+	err := api.request(http.MethodGet, "dns/records", url.Values{"domain": {domain}}, nil, &records)
+	return records, err
+}
+
+func (api *Plesk) createRecord(domain string, r *dnsRecord) error {
+	return api.request(http.MethodPost, "dns/records", url.Values{"domain": {domain}}, r, nil)
+}
+
+func (api *Plesk) deleteRecord(id int) error {
+	return api.request(http.MethodDelete, fmt.Sprintf("dns/records/%d", id), nil, nil, nil)
+}
+
+func newClient() *http.Client {
+	return &http.Client{Timeout: 60 * time.Second}
+}
@@ -0,0 +1,327 @@
+package plesk
+
+/*
+
+Plesk DNS provider:
+
+	Uses the Plesk REST API (Plesk Onyx 17.8 and later).
+
+Info required in `creds.json`:
+   - host (for example https://plesk.example.com:8443)
+   - apikey
+  or
+   - username
+   - password
+
+Provider level metadata available:
+   - default_records: "adopt" (default) or "ignore".
+     Plesk creates records from the server's DNS template when a domain
+     is added.  With "adopt" they are managed like any other record, so
+     anything not in dnsconfig.js is deleted.  With "ignore" they are
+     left alone unless dnsconfig.js has a record with the same label and
+     type.
+   - template_records: the records of the DNS template, as a list of
+     "TYPE label" strings. Defaults to Plesk's stock template.
+
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/providers"
+	"github.com/StackExchange/dnscontrol/providers/diff"
+	"github.com/pkg/errors"
+)
+
+var features = providers.DocumentationNotes{
+	providers.CanUseAlias:            providers.Cannot(),
+	providers.CanUseCAA:              providers.Cannot(),
+	providers.CanUsePTR:              providers.Can(),
+	providers.CanUseNAPTR:            providers.Cannot(),
+	providers.CanUseSRV:              providers.Cannot(),
+	providers.CanUseSSHFP:            providers.Cannot(),
+	providers.CanUseTLSA:             providers.Cannot(),
+	providers.CanUseTXTMulti:         providers.Cannot(),
+	providers.DocCreateDomains:       providers.Cannot("Domains must be added to a subscription in Plesk"),
+	providers.DocOfficiallySupported: providers.Cannot(),
+}
+
+func init() {
+	providers.RegisterDomainServiceProviderType("PLESK", newPlesk, features)
+}
+
+// defaultTemplate is the stock Plesk DNS template.
+var defaultTemplate = []string{
+	"NS @", "A @", "AAAA @", "MX @", "TXT @", "TXT _dmarc",
+	"CNAME ftp", "A ipv4", "AAAA ipv6", "A mail", "AAAA mail",
+	"A ns1", "A ns2", "A webmail", "AAAA webmail", "CNAME www",
+}
+
+// Plesk is the handle for this provider.
+type Plesk struct {
+	baseURL        string
+	apiKey         string
+	username       string
+	password       string
+	ignoreTemplate bool
+	template       map[string]bool
+	client         *http.Client
+}
+
+func newPlesk(m map[string]string, metadata json.RawMessage) (providers.DNSServiceProvider, error) {
+	api := &Plesk{
+		baseURL:  strings.TrimSuffix(m["host"], "/"),
+		apiKey:   m["apikey"],
+		username: m["username"],
+		password: m["password"],
+		client:   newClient(),
+	}
+	if api.baseURL == "" {
+		return nil, errors.Errorf("Plesk: host is required")
+	}
+	if api.apiKey == "" && (api.username == "" || api.password == "") {
+		return nil, errors.Errorf("Plesk: apikey (or username and password) is required")
+	}
+
+	parsedMeta := &struct {
+		DefaultRecords  string   `json:"default_records"`
+		TemplateRecords []string `json:"template_records"`
+	}{}
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, parsedMeta); err != nil {
+			return nil, err
+		}
+	}
+	switch parsedMeta.DefaultRecords {
+	case "", "adopt":
+	case "ignore":
+		api.ignoreTemplate = true
+	default:
+		return nil, errors.Errorf("Plesk: bad value for default_records: %q. Use adopt/ignore", parsedMeta.DefaultRecords)
+	}
+	if len(parsedMeta.TemplateRecords) == 0 {
+		parsedMeta.TemplateRecords = defaultTemplate
+	}
+	api.template = map[string]bool{}
+	for _, t := range parsedMeta.TemplateRecords {
+		f := strings.Fields(t)
+		if len(f) != 2 {
+			return nil, errors.Errorf("Plesk: template_records entry %q is not \"TYPE label\"", t)
+		}
+		api.template[templateKey(f[0], f[1])] = true
+	}
+	return api, nil
+}
+
+// GetNameservers returns the nameservers for a domain.
+func (api *Plesk) GetNameservers(domain string) ([]*models.Nameserver, error) {
+	// Plesk servers have no common nameservers.
+	return nil, nil
+}
+
+// ListZones returns the names of all domains on the server.
+func (api *Plesk) ListZones() ([]string, error) {
+	domains, err := api.listDomains()
+	if err != nil {
+		return nil, err
+	}
+	zones := []string{}
+	for _, d := range domains {
+		zones = append(zones, d.Name)
+	}
+	return zones, nil
+}
+
+// GetZoneRecords returns the records of a zone, including the ones
+// created from the server's DNS template.
+func (api *Plesk) GetZoneRecords(domain string) (models.Records, error) {
+	records, err := api.listRecords(domain)
+	if err != nil {
+		return nil, err
+	}
+	existing := models.Records{}
+	for _, r := range records {
+		if rc, ok := toRecordConfig(domain, r); ok {
+			existing = append(existing, rc)
+		}
+	}
+	models.PostProcessRecords(existing)
+	return existing, nil
+}
+
+// GetDomainCorrections returns a list of corrections to update a domain.
+func (api *Plesk) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	dc.Punycode()
+
+	records, err := api.listRecords(dc.Name)
+	if err != nil {
+		return nil, err
+	}
+	existing := []*models.RecordConfig{}
+	for _, r := range records {
+		rc, ok := toRecordConfig(dc.Name, r)
+		if ok {
+			existing = append(existing, rc)
+		}
+	}
+	if api.ignoreTemplate {
+		existing = api.withoutTemplateRecords(dc, existing)
+	}
+	existing = withoutApexNS(dc, existing)
+	models.PostProcessRecords(existing)
+
+	// The API does not expose TTLs. The zone's TTL applies to all records.
+	desiredTTL := map[models.RecordKey]uint32{}
+	for _, r := range dc.Records {
+		desiredTTL[r.Key()] = r.TTL
+	}
+	for _, r := range existing {
+		if ttl, ok := desiredTTL[r.Key()]; ok {
+			r.TTL = ttl
+		}
+	}
+
+	differ := diff.New(dc)
+	_, create, del, mod := differ.IncrementalDiff(existing)
+
+	corrections := []*models.Correction{}
+	for _, m := range del {
+		id := m.Existing.Original.(*dnsRecord).ID
+		corrections = append(corrections, &models.Correction{
+			Msg:     fmt.Sprintf("%s, Plesk ID: %d", m, id),
+			Records: m.Records(),
+			F:       func() error { return api.deleteRecord(id) },
+		})
+	}
+	for _, m := range create {
+		r := toPleskRecord(dc.Name, m.Desired)
+		corrections = append(corrections, &models.Correction{
+			Msg:     m.String(),
+			Records: m.Records(),
+			F:       func() error { return api.createRecord(dc.Name, r) },
+		})
+	}
+	// Records can not be edited; replace them.
+	for _, m := range mod {
+		id := m.Existing.Original.(*dnsRecord).ID
+		r := toPleskRecord(dc.Name, m.Desired)
+		corrections = append(corrections, &models.Correction{
+			Msg:     fmt.Sprintf("%s, Plesk ID: %d", m, id),
+			Records: m.Records(),
+			F: func() error {
+				if err := api.deleteRecord(id); err != nil {
+					return err
+				}
+				return api.createRecord(dc.Name, r)
+			},
+		})
+	}
+	return corrections, nil
+}
+
+// withoutTemplateRecords removes the records created by the DNS template
+// from existing, unless dnsconfig.js has records with the same label and type.
+func (api *Plesk) withoutTemplateRecords(dc *models.DomainConfig, existing []*models.RecordConfig) []*models.RecordConfig {
+	managed := map[string]bool{}
+	for _, r := range dc.Records {
+		managed[templateKey(r.Type, r.GetLabel())] = true
+	}
+	filtered := []*models.RecordConfig{}
+	for _, r := range existing {
+		k := templateKey(r.Type, r.GetLabel())
+		if api.template[k] && !managed[k] {
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+	return filtered
+}
+
+func templateKey(rtype, label string) string {
+	return strings.ToUpper(rtype) + " " + strings.ToLower(label)
+}
+
+// toRecordConfig converts a Plesk record to a RecordConfig. It returns
+// false for record types we don't manage. #rtype_variations
+func toRecordConfig(origin string, r *dnsRecord) (*models.RecordConfig, bool) {
+	rc := &models.RecordConfig{
+		Type:     r.Type,
+		TTL:      models.DefaultTTL,
+		Original: r,
+	}
+	host := strings.TrimSuffix(strings.ToLower(r.Host), ".")
+	if host == origin || strings.HasSuffix(host, "."+origin) {
+		rc.SetLabelFromFQDN(host, origin)
+	} else if host == "" {
+		rc.SetLabel("@", origin)
+	} else {
+		rc.SetLabel(host, origin)
+	}
+	var err error
+	switch r.Type {
+	case "A", "AAAA":
+		err = rc.SetTarget(r.Value)
+	case "CNAME", "NS", "PTR":
+		err = rc.SetTarget(fqdn(r.Value))
+	case "MX":
+		err = rc.SetTargetMXStrings(r.Opt, fqdn(r.Value))
+	case "TXT":
+		err = rc.SetTargetTXT(r.Value)
+	default:
+		return nil, false
+	}
+	if err != nil {
+		panic(errors.Wrap(err, "unparsable record received from Plesk"))
+	}
+	return rc, true
+}
+
+// toPleskRecord converts a RecordConfig to a Plesk record. #rtype_variations
+func toPleskRecord(origin string, rc *models.RecordConfig) *dnsRecord {
+	r := &dnsRecord{
+		Type:  rc.Type,
+		Host:  rc.GetLabel(),
+		Value: rc.GetTargetField(),
+	}
+	if r.Host == "@" {
+		r.Host = ""
+	}
+	switch rc.Type {
+	case "MX":
+		r.Opt = strconv.Itoa(int(rc.MxPreference))
+	case "TXT":
+		r.Value = strings.Join(rc.TxtStrings, "")
+	}
+	return r
+}
+
+func fqdn(s string) string {
+	if strings.HasSuffix(s, ".") {
+		return s
+	}
+	return s + "."
+}
+
+// withoutApexNS removes the apex NS records from existing, unless
+// dnsconfig.js has some. Plesk servers have no common nameservers, so
+// dnscontrol doesn't add them to the desired records, and would delete
+// those of the server.
+func withoutApexNS(dc *models.DomainConfig, existing []*models.RecordConfig) []*models.RecordConfig {
+	for _, r := range dc.Records {
+		if r.Type == "NS" && r.GetLabel() == "@" {
+			return existing
+		}
+	}
+	filtered := []*models.RecordConfig{}
+	for _, r := range existing {
+		if !(r.Type == "NS" && r.GetLabel() == "@") {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
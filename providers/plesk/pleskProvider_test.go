@@ -0,0 +1,89 @@
+package plesk
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/models"
+)
+
+func TestConversion(t *testing.T) {
+	records := []*dnsRecord{
+		{ID: 1, Type: "A", Host: "example.com.", Value: "192.0.2.1"},
+		{ID: 2, Type: "CNAME", Host: "www.example.com.", Value: "example.com."},
+		{ID: 3, Type: "MX", Host: "example.com.", Value: "mail.example.com.", Opt: "10"},
+		{ID: 4, Type: "TXT", Host: "example.com.", Value: "v=spf1 +a +mx -all"},
+	}
+	for _, r := range records {
+		rc, ok := toRecordConfig("example.com", r)
+		if !ok {
+			t.Fatalf("record %d was skipped", r.ID)
+		}
+		back := toPleskRecord("example.com", rc)
+		expectedHost := rc.GetLabel()
+		if expectedHost == "@" {
+			expectedHost = ""
+		}
+		if back.Type != r.Type || back.Host != expectedHost || back.Value != r.Value || back.Opt != r.Opt {
+			t.Errorf("round trip mismatch: %+v -> %+v", r, back)
+		}
+	}
+	if _, ok := toRecordConfig("example.com", &dnsRecord{Type: "SOA"}); ok {
+		t.Error("SOA records should be skipped")
+	}
+}
+
+func TestIgnoreTemplate(t *testing.T) {
+	p, err := newPlesk(map[string]string{"host": "https://plesk", "apikey": "k"}, json.RawMessage(`{"default_records":"ignore"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := p.(*Plesk)
+
+	rec := func(rtype, label, target string) *models.RecordConfig {
+		rc := &models.RecordConfig{Type: rtype}
+		rc.SetLabel(label, "example.com")
+		rc.SetTarget(target)
+		return rc
+	}
+	existing := []*models.RecordConfig{
+		rec("A", "@", "192.0.2.1"),          // template, managed below: kept
+		rec("A", "webmail", "192.0.2.1"),    // template, not managed: ignored
+		rec("A", "custom", "192.0.2.1"),     // not template: kept
+		rec("CNAME", "www", "example.com."), // template, not managed: ignored
+	}
+	dc := &models.DomainConfig{Name: "example.com", Records: []*models.RecordConfig{rec("A", "@", "192.0.2.2")}}
+	got := api.withoutTemplateRecords(dc, existing)
+	if len(got) != 2 || got[0].GetLabel() != "@" || got[1].GetLabel() != "custom" {
+		for _, r := range got {
+			t.Log(r.Type, r.GetLabel())
+		}
+		t.Errorf("unexpected records after filtering")
+	}
+
+	if _, err := newPlesk(map[string]string{"host": "https://plesk", "apikey": "k"}, json.RawMessage(`{"default_records":"bogus"}`)); err == nil {
+		t.Error("expected error for bad default_records")
+	}
+}
+
+func TestWithoutApexNS(t *testing.T) {
+	rec := func(rtype, label, target string) *models.RecordConfig {
+		rc := &models.RecordConfig{Type: rtype}
+		rc.SetLabel(label, "example.com")
+		rc.SetTarget(target)
+		return rc
+	}
+	existing := []*models.RecordConfig{
+		rec("NS", "@", "ns1.plesk.example.net."),
+		rec("NS", "sub", "ns1.example.org."),
+		rec("A", "@", "192.0.2.1"),
+	}
+	dc := &models.DomainConfig{Name: "example.com", Records: []*models.RecordConfig{rec("A", "@", "192.0.2.1")}}
+	if got := withoutApexNS(dc, existing); len(got) != 2 || got[0].GetLabel() != "sub" {
+		t.Errorf("expected only the apex NS record to be left alone, got %d records", len(got))
+	}
+	dc.Records = append(dc.Records, rec("NS", "@", "ns1.example.org."))
+	if got := withoutApexNS(dc, existing); len(got) != 3 {
+		t.Errorf("expected the apex NS records to be managed, got %d records", len(got))
+	}
+}
@@ -0,0 +1,346 @@
+// Package plugin lets providers that are not built into dnscontrol be
+// used as separate binaries, so that third parties can ship them without
+// forking dnscontrol.
+//
+// A plugin is a program whose main function calls Serve. dnscontrol
+// starts it with hashicorp/go-plugin when a PLUGIN provider is created,
+// and talks to it over gRPC until Stop is called.
+//
+// Info required in `creds.json`:
+//   - plugin: the path of the plugin binary, or its name if it is in PATH
+//   - anything else the plugin needs; it receives the entry without "plugin"
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/redact"
+	"github.com/StackExchange/dnscontrol/providers"
+	"github.com/hashicorp/go-hclog"
+	goplugin "github.com/hashicorp/go-plugin"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// The record types dnscontrol checks with capabilities are all allowed:
+// which ones a plugin supports is only known once it runs. The plugin's
+// RecordTypes are checked instead.
+var features = providers.DocumentationNotes{
+	providers.CanUseAlias:            providers.Can("If the plugin supports it"),
+	providers.CanUseCAA:              providers.Can("If the plugin supports it"),
+	providers.CanUseCERT:             providers.Can("If the plugin supports it"),
+	providers.CanUseCSYNC:            providers.Can("If the plugin supports it"),
+	providers.CanUseDHCID:            providers.Can("If the plugin supports it"),
+	providers.CanUseDNAME:            providers.Can("If the plugin supports it"),
+	providers.CanUseNAPTR:            providers.Can("If the plugin supports it"),
+	providers.CanUseOPENPGPKEY:       providers.Can("If the plugin supports it"),
+	providers.CanUsePTR:              providers.Can("If the plugin supports it"),
+	providers.CanUseRP:               providers.Can("If the plugin supports it"),
+	providers.CanUseSMIMEA:           providers.Can("If the plugin supports it"),
+	providers.CanUseSOA:              providers.Can("If the plugin supports it"),
+	providers.CanUseSRV:              providers.Can("If the plugin supports it"),
+	providers.CanUseSSHFP:            providers.Can("If the plugin supports it"),
+	providers.CanUseTLSA:             providers.Can("If the plugin supports it"),
+	providers.CanUseTXTMulti:         providers.Can("If the plugin supports it"),
+	providers.CanUseUNKNOWN:          providers.Can("If the plugin supports it"),
+	providers.CanUseURI:              providers.Can("If the plugin supports it"),
+	providers.DocOfficiallySupported: providers.Cannot("Plugins are supported by their authors"),
+}
+
+func init() {
+	providers.RegisterDomainServiceProviderType("PLUGIN", newDNSProvider, features)
+	providers.RegisterRegistrarType("PLUGIN", newRegistrar, providers.DocumentationNotes{
+		providers.CanUseRegistrarLock: providers.Can("If the plugin supports it"),
+	})
+}
+
+// Provider is a DNS provider or registrar run by a plugin.
+type Provider struct {
+	name        string
+	conn        *grpc.ClientConn
+	recordTypes map[string]bool // Nil if the plugin supports all.
+	features    map[string]bool
+}
+
+// zoneLister, domainCreator and zoneListerCreator are Providers whose
+// plugin can list zones, create them, or both. They are separate types so
+// that commands that check for providers.ZoneLister or
+// providers.DomainCreator only find them if the plugin has them.
+type zoneLister struct {
+	*Provider
+}
+
+type domainCreator struct {
+	*Provider
+}
+
+type zoneListerCreator struct {
+	*Provider
+}
+
+func newDNSProvider(conf map[string]string, meta json.RawMessage) (providers.DNSServiceProvider, error) {
+	p, err := start(conf, "dns", meta)
+	if err != nil {
+		return nil, err
+	}
+	return dnsProvider(p), nil
+}
+
+// dnsProvider returns p as the type that has the optional methods of its
+// plugin.
+func dnsProvider(p *Provider) providers.DNSServiceProvider {
+	switch lists, creates := p.features["ListZones"], p.features["EnsureDomainExists"]; {
+	case lists && creates:
+		return zoneListerCreator{p}
+	case lists:
+		return zoneLister{p}
+	case creates:
+		return domainCreator{p}
+	}
+	return p
+}
+
+func newRegistrar(conf map[string]string) (providers.Registrar, error) {
+	return start(conf, "registrar", nil)
+}
+
+// start starts the plugin of conf. It runs until Stop is called.
+func start(conf map[string]string, kind string, meta json.RawMessage) (*Provider, error) {
+	path := conf["plugin"]
+	if path == "" {
+		return nil, errors.Errorf("PLUGIN providers need the path of the plugin binary as \"plugin\" in creds.json")
+	}
+	client := goplugin.NewClient(&goplugin.ClientConfig{
+		HandshakeConfig:  handshake,
+		Plugins:          pluginSet(Plugin{}, nil),
+		Cmd:              exec.Command(path),
+		AllowedProtocols: []goplugin.Protocol{goplugin.ProtocolGRPC},
+		Managed:          true,
+		Stderr:           redact.Writer(os.Stderr),
+		Logger:           hclog.New(&hclog.LoggerOptions{Name: "plugin", Output: redact.Writer(os.Stderr), Level: hclog.Error}),
+	})
+	rpcClient, err := client.Client()
+	if err != nil {
+		client.Kill()
+		return nil, errors.Wrapf(err, "starting plugin %s", path)
+	}
+	conn, err := rpcClient.Dispense(pluginName)
+	if err != nil {
+		client.Kill()
+		return nil, errors.Wrapf(err, "starting plugin %s", path)
+	}
+	creds := map[string]string{}
+	for k, v := range conf {
+		if k != "plugin" {
+			creds[k] = v
+		}
+	}
+	p, err := configure(path, conn.(*grpc.ClientConn), kind, creds, meta)
+	if err != nil {
+		client.Kill()
+	}
+	return p, err
+}
+
+// Stop stops the plugins that were started. dnscontrol calls it before it
+// exits.
+func Stop() {
+	goplugin.CleanupClients()
+}
+
+// configure makes the first call to a started plugin.
+func configure(name string, conn *grpc.ClientConn, kind string, creds map[string]string, meta json.RawMessage) (*Provider, error) {
+	p := &Provider{name: name, conn: conn, features: map[string]bool{}}
+	var reply ConfigureReply
+	if err := p.call("Configure", ConfigureArgs{Kind: kind, Creds: creds, Meta: meta}, &reply); err != nil {
+		return nil, err
+	}
+	if len(reply.RecordTypes) != 0 {
+		p.recordTypes = map[string]bool{}
+		for _, t := range reply.RecordTypes {
+			p.recordTypes[strings.ToUpper(t)] = true
+		}
+	}
+	for _, f := range reply.Features {
+		p.features[f] = true
+	}
+	return p, nil
+}
+
+func (p *Provider) call(method string, args, reply interface{}) error {
+	if err := p.conn.Invoke(context.Background(), "/"+serviceName+"/"+method, args, reply, grpc.CallContentSubtype(jsonCodec{}.Name())); err != nil {
+		return errors.Errorf("plugin %s: %s", p.name, status.Convert(err).Message())
+	}
+	return nil
+}
+
+// corrections turns the corrections of the plugin into ones that run
+// them in the plugin.
+func (p *Provider) corrections(cs []Correction) []*models.Correction {
+	corrections := make([]*models.Correction, 0, len(cs))
+	for _, c := range cs {
+		correction := &models.Correction{Msg: c.Msg}
+		if c.Run {
+			id := c.ID
+			correction.F = func() error {
+				var ok bool
+				return p.call("RunCorrection", id, &ok)
+			}
+		}
+		corrections = append(corrections, correction)
+	}
+	return corrections
+}
+
+// GetNameservers returns the nameservers for a domain.
+func (p *Provider) GetNameservers(domain string) ([]*models.Nameserver, error) {
+	var ns []string
+	if err := p.call("GetNameservers", domain, &ns); err != nil {
+		return nil, err
+	}
+	return models.StringsToNameservers(ns), nil
+}
+
+// GetDomainCorrections returns a list of corrections to update a domain.
+func (p *Provider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	if p.recordTypes != nil {
+		for _, r := range dc.Records {
+			if !p.recordTypes[r.Type] {
+				return nil, errors.Errorf("plugin %s does not support %s records (%s)", p.name, r.Type, r.GetLabelFQDN())
+			}
+		}
+	}
+	var cs []Correction
+	if err := p.call("GetDomainCorrections", dc, &cs); err != nil {
+		return nil, err
+	}
+	return p.corrections(cs), nil
+}
+
+// GetRegistrarCorrections returns a list of corrections for this registrar.
+func (p *Provider) GetRegistrarCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	var cs []Correction
+	if err := p.call("GetRegistrarCorrections", dc, &cs); err != nil {
+		return nil, err
+	}
+	return p.corrections(cs), nil
+}
+
+// ListZones returns the zones of the account.
+func (p zoneLister) ListZones() ([]string, error) {
+	return p.listZones()
+}
+
+// ListZones returns the zones of the account.
+func (p zoneListerCreator) ListZones() ([]string, error) {
+	return p.listZones()
+}
+
+func (p *Provider) listZones() ([]string, error) {
+	var zones []string
+	err := p.call("ListZones", true, &zones)
+	return zones, err
+}
+
+// GetZoneRecords returns the records of a zone, if the plugin can list them.
+func (p *Provider) GetZoneRecords(domain string) (models.Records, error) {
+	if !p.features["GetZoneRecords"] {
+		return nil, errors.Wrapf(providers.ErrNotSupported, "plugin %s can't list the records of zones", p.name)
+	}
+	var records models.Records
+	if err := p.call("GetZoneRecords", domain, &records); err != nil {
+		return nil, err
+	}
+	fixLabels(records, domain)
+	return records, nil
+}
+
+// GetDomainStatus returns when a domain expires, and whether it is
+// locked, if the plugin can tell.
+func (p *Provider) GetDomainStatus(domain string) (*models.DomainStatus, error) {
+	if !p.features["GetDomainStatus"] {
+		return nil, errors.Wrapf(providers.ErrNotSupported, "plugin %s can't tell when domains expire", p.name)
+	}
+	status := &models.DomainStatus{}
+	if err := p.call("GetDomainStatus", domain, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// SetRegistrarLock locks or unlocks a domain against transfers, if the
+// plugin can.
+func (p *Provider) SetRegistrarLock(domain string, locked bool) error {
+	if !p.features["SetRegistrarLock"] {
+		return errors.Wrapf(providers.ErrNotSupported, "plugin %s can't lock domains", p.name)
+	}
+	var ok bool
+	return p.call("SetRegistrarLock", SetRegistrarLockArgs{Domain: domain, Locked: locked}, &ok)
+}
+
+// GetContacts returns the contacts of a domain, if the plugin can.
+func (p *Provider) GetContacts(domain string) (map[string]*models.Contact, error) {
+	if !p.features["Contacts"] {
+		return nil, errors.Wrapf(providers.ErrNotSupported, "plugin %s can't manage contacts", p.name)
+	}
+	var contacts map[string]*models.Contact
+	if err := p.call("GetContacts", domain, &contacts); err != nil {
+		return nil, err
+	}
+	return contacts, nil
+}
+
+// SetContacts updates the contacts of a domain, if the plugin can.
+func (p *Provider) SetContacts(domain string, contacts map[string]*models.Contact) error {
+	if !p.features["Contacts"] {
+		return errors.Wrapf(providers.ErrNotSupported, "plugin %s can't manage contacts", p.name)
+	}
+	var ok bool
+	return p.call("SetContacts", SetContactsArgs{Domain: domain, Contacts: contacts}, &ok)
+}
+
+// GetDSRecords returns the DS records published for a domain, if the
+// plugin can.
+func (p *Provider) GetDSRecords(domain string) ([]*models.DSRecord, error) {
+	if !p.features["DSRecords"] {
+		return nil, errors.Wrapf(providers.ErrNotSupported, "plugin %s can't publish DS records", p.name)
+	}
+	var records []*models.DSRecord
+	if err := p.call("GetDSRecords", domain, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// AddDSRecord publishes a DS record for a domain.
+func (p *Provider) AddDSRecord(domain string, ds *models.DSRecord) error {
+	var ok bool
+	return p.call("AddDSRecord", DSRecordArgs{Domain: domain, DS: ds}, &ok)
+}
+
+// RemoveDSRecord removes a DS record of a domain.
+func (p *Provider) RemoveDSRecord(domain string, ds *models.DSRecord) error {
+	var ok bool
+	return p.call("RemoveDSRecord", DSRecordArgs{Domain: domain, DS: ds}, &ok)
+}
+
+// EnsureDomainExists creates a zone if it doesn't exist.
+func (p domainCreator) EnsureDomainExists(domain string) error {
+	return p.ensureDomainExists(domain)
+}
+
+// EnsureDomainExists creates a zone if it doesn't exist.
+func (p zoneListerCreator) EnsureDomainExists(domain string) error {
+	return p.ensureDomainExists(domain)
+}
+
+func (p *Provider) ensureDomainExists(domain string) error {
+	var ok bool
+	return p.call("EnsureDomainExists", domain, &ok)
+}
@@ -0,0 +1,284 @@
+package plugin
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/providers"
+	goplugin "github.com/hashicorp/go-plugin"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// fakeDNS is the DNS provider of the plugin under test.
+type fakeDNS struct {
+	creds   map[string]string
+	applied []string
+}
+
+func (f *fakeDNS) GetNameservers(domain string) ([]*models.Nameserver, error) {
+	return models.StringsToNameservers([]string{"ns1." + f.creds["suffix"]}), nil
+}
+
+func (f *fakeDNS) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	corrections := []*models.Correction{{Msg: "a note"}}
+	for _, r := range dc.Records {
+		r := r
+		msg := "CREATE " + r.Type + " " + r.GetLabelFQDN() + " " + r.GetTargetField()
+		corrections = append(corrections, &models.Correction{Msg: msg, F: func() error {
+			if r.GetLabel() == "bad" {
+				return errors.Errorf("refused")
+			}
+			f.applied = append(f.applied, msg)
+			return nil
+		}})
+	}
+	return corrections, nil
+}
+
+func (f *fakeDNS) ListZones() ([]string, error) {
+	return []string{"example.com"}, nil
+}
+
+// startFake serves p over gRPC in the test, and configures it as kind.
+func startFake(t *testing.T, p Plugin, kind string, creds map[string]string) (*Provider, error) {
+	client, _ := goplugin.TestPluginGRPCConn(t, pluginSet(p, nil))
+	conn, err := client.Dispense(pluginName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return configure("fake", conn.(*grpc.ClientConn), kind, creds, json.RawMessage(`{}`))
+}
+
+func TestPlugin(t *testing.T) {
+	dns := &fakeDNS{}
+	p := Plugin{
+		NewDNSProvider: func(creds map[string]string, meta json.RawMessage) (providers.DNSServiceProvider, error) {
+			dns.creds = creds
+			return dns, nil
+		},
+		RecordTypes: []string{"A", "txt"},
+	}
+	prov, err := startFake(t, p, "dns", map[string]string{"suffix": "example.net."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !prov.features["ListZones"] || prov.features["GetZoneRecords"] {
+		t.Errorf("unexpected features %v", prov.features)
+	}
+	ns, err := prov.GetNameservers("example.com")
+	if err != nil || len(ns) != 1 || ns[0].Name != "ns1.example.net." {
+		t.Errorf("unexpected nameservers %v, %v", ns, err)
+	}
+
+	dc := &models.DomainConfig{Name: "example.com"}
+	for _, label := range []string{"www", "bad"} {
+		r := &models.RecordConfig{Type: "A", TTL: 300}
+		r.SetLabel(label, "example.com")
+		r.SetTarget("192.0.2.1")
+		dc.Records = append(dc.Records, r)
+	}
+	corrections, err := prov.GetDomainCorrections(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrections) != 3 || corrections[0].F != nil || corrections[1].Msg != "CREATE A www.example.com 192.0.2.1" {
+		t.Fatalf("unexpected corrections %+v", corrections)
+	}
+	if err := corrections[1].F(); err != nil {
+		t.Error(err)
+	}
+	if err := corrections[2].F(); err == nil {
+		t.Error("expected the error of the correction")
+	}
+	if len(dns.applied) != 1 || dns.applied[0] != corrections[1].Msg {
+		t.Errorf("unexpected corrections run %v", dns.applied)
+	}
+
+	mx := &models.RecordConfig{Type: "MX"}
+	mx.SetLabel("@", "example.com")
+	dc.Records = append(dc.Records, mx)
+	if _, err := prov.GetDomainCorrections(dc); err == nil {
+		t.Error("expected MX records to be refused")
+	}
+	if _, err := prov.GetZoneRecords("example.com"); err == nil {
+		t.Error("expected GetZoneRecords to be unsupported")
+	}
+}
+
+func TestPluginKinds(t *testing.T) {
+	p := Plugin{
+		NewRegistrar: func(map[string]string) (providers.Registrar, error) {
+			return providers.None{}, nil
+		},
+	}
+	if _, err := startFake(t, p, "dns", nil); err == nil {
+		t.Error("expected a registrar-only plugin to refuse to be a DNS provider")
+	}
+	prov, err := startFake(t, p, "registrar", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if corrections, err := prov.GetRegistrarCorrections(&models.DomainConfig{Name: "example.com"}); err != nil || len(corrections) != 0 {
+		t.Errorf("unexpected corrections %v, %v", corrections, err)
+	}
+}
+
+// fakeLocker is a registrar that can lock domains.
+type fakeLocker struct {
+	providers.None
+	locked bool
+}
+
+func (f *fakeLocker) GetDomainStatus(domain string) (*models.DomainStatus, error) {
+	return &models.DomainStatus{Locked: &f.locked}, nil
+}
+
+func (f *fakeLocker) SetRegistrarLock(domain string, locked bool) error {
+	f.locked = locked
+	return nil
+}
+
+func TestPluginRegistrarLock(t *testing.T) {
+	reg := &fakeLocker{}
+	p := Plugin{
+		NewRegistrar: func(map[string]string) (providers.Registrar, error) {
+			return reg, nil
+		},
+	}
+	prov, err := startFake(t, p, "registrar", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dc := &models.DomainConfig{Name: "example.com", RegistrarName: "fake", RegistrarLock: "on"}
+	corrections, err := providers.RegistrarLockCorrections(prov, dc)
+	if err != nil || len(corrections) != 1 {
+		t.Fatalf("got corrections %v, %v; want one", corrections, err)
+	}
+	if msg := corrections[0].Msg; msg != "Change transfer lock off -> on" {
+		t.Errorf("got correction %q", msg)
+	}
+	if err := corrections[0].F(); err != nil || !reg.locked {
+		t.Errorf("domain not locked: %v", err)
+	}
+	if corrections, err := providers.RegistrarLockCorrections(prov, dc); err != nil || len(corrections) != 0 {
+		t.Errorf("unexpected corrections once locked: %v, %v", corrections, err)
+	}
+
+	prov, err = startFake(t, Plugin{NewRegistrar: func(map[string]string) (providers.Registrar, error) {
+		return providers.None{}, nil
+	}}, "registrar", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := providers.RegistrarLockCorrections(prov, dc); err == nil {
+		t.Error("expected a plugin that can't lock domains to fail")
+	}
+	if _, err := prov.GetDomainStatus("example.com"); errors.Cause(err) != providers.ErrNotSupported {
+		t.Errorf("got error %v, want providers.ErrNotSupported", err)
+	}
+}
+
+// fakeDNSSEC is a registrar that can publish DS records.
+type fakeDNSSEC struct {
+	providers.None
+	records []*models.DSRecord
+}
+
+func (f *fakeDNSSEC) GetDSRecords(domain string) ([]*models.DSRecord, error) {
+	return f.records, nil
+}
+
+func (f *fakeDNSSEC) AddDSRecord(domain string, ds *models.DSRecord) error {
+	f.records = append(f.records, ds)
+	return nil
+}
+
+func (f *fakeDNSSEC) RemoveDSRecord(domain string, ds *models.DSRecord) error {
+	for i, r := range f.records {
+		if r.String() == ds.String() {
+			f.records = append(f.records[:i], f.records[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func TestPluginDSRecords(t *testing.T) {
+	old := &models.DSRecord{KeyTag: 1, Algorithm: 13, DigestType: 2, Digest: "AA"}
+	reg := &fakeDNSSEC{records: []*models.DSRecord{old}}
+	prov, err := startFake(t, Plugin{NewRegistrar: func(map[string]string) (providers.Registrar, error) {
+		return reg, nil
+	}}, "registrar", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dc := &models.DomainConfig{Name: "example.com", RegistrarDS: []*models.DSRecord{{KeyTag: 2, Algorithm: 13, DigestType: 2, Digest: "bb"}}}
+	corrections, err := providers.DSCorrections(prov, dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msgs []string
+	for _, c := range corrections {
+		msgs = append(msgs, c.Msg)
+		if err := c.F(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The new DS record is published before the old one is removed.
+	if want := []string{"Publish DS 2 13 2 BB", "Remove DS 1 13 2 AA"}; strings.Join(msgs, "|") != strings.Join(want, "|") {
+		t.Errorf("got corrections %q, want %q", msgs, want)
+	}
+	if corrections, err := providers.DSCorrections(prov, dc); err != nil || len(corrections) != 0 {
+		t.Errorf("unexpected corrections once published: %v, %v", corrections, err)
+	}
+}
+
+// fakeCreator is a DNS provider that can create zones, but not list them.
+type fakeCreator struct {
+	providers.None
+	created []string
+}
+
+func (f *fakeCreator) EnsureDomainExists(domain string) error {
+	f.created = append(f.created, domain)
+	return nil
+}
+
+func TestPluginOptionalMethods(t *testing.T) {
+	creator := &fakeCreator{}
+	tests := []struct {
+		name           string
+		dsp            providers.DNSServiceProvider
+		lists, creates bool
+	}{
+		{"lister", &fakeDNS{}, true, false},
+		{"creator", creator, false, true},
+		{"neither", providers.None{}, false, false},
+	}
+	for _, tst := range tests {
+		dsp := tst.dsp
+		prov, err := startFake(t, Plugin{NewDNSProvider: func(map[string]string, json.RawMessage) (providers.DNSServiceProvider, error) {
+			return dsp, nil
+		}}, "dns", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := dnsProvider(prov)
+		_, lists := got.(providers.ZoneLister)
+		c, creates := got.(providers.DomainCreator)
+		if lists != tst.lists || creates != tst.creates {
+			t.Errorf("%s: is a ZoneLister %v and a DomainCreator %v", tst.name, lists, creates)
+		}
+		if creates {
+			if err := c.EnsureDomainExists("example.com"); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+	if len(creator.created) != 1 || creator.created[0] != "example.com" {
+		t.Errorf("created %v", creator.created)
+	}
+}
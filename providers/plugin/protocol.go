@@ -0,0 +1,127 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/StackExchange/dnscontrol/models"
+	goplugin "github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+// ProtocolVersion is the version of the protocol between dnscontrol and
+// its plugins. It changes when a change would break plugins built
+// against an older version.
+const ProtocolVersion = 2
+
+// magicEnv is set in the environment of the plugins dnscontrol starts, so
+// that a plugin run by hand can say what it is rather than wait for
+// dnscontrol.
+const magicEnv = "DNSCONTROL_PLUGIN_MAGIC_COOKIE"
+const magicValue = "a4c5b3e0b1d94c3f8ea2d4d3c0f7d21b"
+
+// handshake is what dnscontrol and its plugins check of each other
+// before they talk.
+var handshake = goplugin.HandshakeConfig{
+	ProtocolVersion:  ProtocolVersion,
+	MagicCookieKey:   magicEnv,
+	MagicCookieValue: magicValue,
+}
+
+// serviceName is the name of the gRPC service plugins serve.
+const serviceName = "dnscontrol.plugin.Provider"
+
+// pluginName is the name of the service in the plugin sets of go-plugin.
+const pluginName = "provider"
+
+// grpcPlugin serves plugin over gRPC, or returns the connection to it.
+type grpcPlugin struct {
+	goplugin.NetRPCUnsupportedPlugin
+	plugin Plugin
+	stderr *os.File // Where the provider prints, if not to os.Stdout.
+}
+
+func (g grpcPlugin) GRPCServer(_ *goplugin.GRPCBroker, s *grpc.Server) error {
+	s.RegisterService(serviceDesc(), &Server{plugin: g.plugin, stderr: g.stderr})
+	return nil
+}
+
+func (g grpcPlugin) GRPCClient(_ context.Context, _ *goplugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return c, nil
+}
+
+// pluginSet returns the plugins of go-plugin that serve p.
+func pluginSet(p Plugin, stderr *os.File) map[string]goplugin.Plugin {
+	return map[string]goplugin.Plugin{pluginName: grpcPlugin{plugin: p, stderr: stderr}}
+}
+
+// jsonCodec encodes the messages of the service as JSON, so that they are
+// the types of dnscontrol rather than types generated from a .proto file.
+// The calls ask for it with the content subtype "json".
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                               { return "json" }
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// ConfigureArgs are the arguments of Server.Configure, the first call
+// dnscontrol makes.
+type ConfigureArgs struct {
+	Kind  string            // "dns" or "registrar".
+	Creds map[string]string // The creds.json entry, without "plugin".
+	Meta  json.RawMessage   // The metadata of NewDnsProvider(), for "dns".
+}
+
+// ConfigureReply is the reply of Server.Configure.
+type ConfigureReply struct {
+	// RecordTypes are the record types the DNS provider supports. Empty
+	// means all.
+	RecordTypes []string
+	// Features are the optional methods the provider implements:
+	// "ListZones", "GetZoneRecords" and "EnsureDomainExists" for DNS
+	// providers, and "GetDomainStatus", "SetRegistrarLock", "Contacts"
+	// (GetContacts and SetContacts) and "DSRecords" (GetDSRecords,
+	// AddDSRecord and RemoveDSRecord) for registrars.
+	Features []string
+}
+
+// SetRegistrarLockArgs are the arguments of Server.SetRegistrarLock.
+type SetRegistrarLockArgs struct {
+	Domain string
+	Locked bool
+}
+
+// SetContactsArgs are the arguments of Server.SetContacts.
+type SetContactsArgs struct {
+	Domain   string
+	Contacts map[string]*models.Contact
+}
+
+// DSRecordArgs are the arguments of Server.AddDSRecord and
+// Server.RemoveDSRecord.
+type DSRecordArgs struct {
+	Domain string
+	DS     *models.DSRecord
+}
+
+// Correction is a correction returned by Server.GetDomainCorrections or
+// Server.GetRegistrarCorrections. Server.RunCorrection runs it by ID.
+type Correction struct {
+	ID  int
+	Msg string
+	Run bool // False if the correction has nothing to run.
+}
+
+// fixLabels sets the fully qualified names of records received over
+// RPC, which only have their short names.
+func fixLabels(records models.Records, domain string) {
+	for _, r := range records {
+		r.SetLabel(r.GetLabel(), domain)
+	}
+}
@@ -0,0 +1,321 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/providers"
+	"github.com/hashicorp/go-hclog"
+	goplugin "github.com/hashicorp/go-plugin"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// Plugin describes the provider a plugin binary implements. Its
+// initializers are the same as those of the providers built into
+// dnscontrol.
+type Plugin struct {
+	NewDNSProvider providers.DspInitializer       // Nil if it is not a DNS provider.
+	NewRegistrar   providers.RegistrarInitializer // Nil if it is not a registrar.
+	// RecordTypes are the record types the DNS provider supports, such as
+	// "A" and "MX". dnscontrol refuses the others before sending them to
+	// the plugin. Empty means all.
+	RecordTypes []string
+}
+
+// Serve serves p to dnscontrol over gRPC, and returns when dnscontrol is
+// done with it. A plugin's main function calls it and nothing else.
+func Serve(p Plugin) {
+	if os.Getenv(magicEnv) != magicValue {
+		fmt.Fprintln(os.Stderr, "This is a dnscontrol provider plugin. Set it as the \"plugin\" of a PLUGIN provider in creds.json to use it.")
+		os.Exit(1)
+	}
+	goplugin.Serve(&goplugin.ServeConfig{
+		HandshakeConfig: handshake,
+		Plugins:         pluginSet(p, os.Stderr),
+		GRPCServer:      goplugin.DefaultGRPCServer,
+		Logger:          hclog.New(&hclog.LoggerOptions{Output: os.Stderr, Level: hclog.Error, JSONFormat: true}),
+	})
+}
+
+// serviceDesc describes the gRPC service of Server. Like net/rpc, its
+// methods are those of Server of the form
+//
+//	func (s *Server) Name(args T, reply *R) error
+//
+// whose request is T and response R, in JSON.
+func serviceDesc() *grpc.ServiceDesc {
+	desc := &grpc.ServiceDesc{
+		ServiceName: serviceName,
+		HandlerType: (*interface{})(nil),
+	}
+	t := reflect.TypeOf(&Server{})
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		if m.Type.NumIn() != 3 || m.Type.In(2).Kind() != reflect.Ptr || m.Type.NumOut() != 1 {
+			continue
+		}
+		name, f, argType, replyType := m.Name, m.Func, m.Type.In(1), m.Type.In(2).Elem()
+		desc.Methods = append(desc.Methods, grpc.MethodDesc{
+			MethodName: name,
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				args := reflect.New(argType)
+				if err := dec(args.Interface()); err != nil {
+					return nil, err
+				}
+				call := func(_ context.Context, args interface{}) (interface{}, error) {
+					reply := reflect.New(replyType)
+					out := f.Call([]reflect.Value{reflect.ValueOf(srv), reflect.ValueOf(args).Elem(), reply})
+					if err, _ := out[0].Interface().(error); err != nil {
+						return nil, err
+					}
+					return reply.Interface(), nil
+				}
+				if interceptor == nil {
+					return call(ctx, args.Interface())
+				}
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/" + name}
+				return interceptor(ctx, args.Interface(), info, call)
+			},
+		})
+	}
+	return desc
+}
+
+// Server is the gRPC service of a plugin. Its methods are the protocol;
+// plugins don't use it directly.
+type Server struct {
+	plugin      Plugin
+	stderr      *os.File
+	dsp         providers.DNSServiceProvider
+	registrar   providers.Registrar
+	mu          sync.Mutex
+	corrections map[int]*models.Correction
+	nextID      int
+}
+
+// Configure creates the provider.
+func (s *Server) Configure(args ConfigureArgs, reply *ConfigureReply) error {
+	if s.stderr != nil {
+		// go-plugin points os.Stdout and os.Stderr at pipes that only its
+		// net/rpc protocol reads. Point them back at stderr, which
+		// dnscontrol shows.
+		os.Stdout, os.Stderr = s.stderr, s.stderr
+	}
+	switch args.Kind {
+	case "dns":
+		if s.plugin.NewDNSProvider == nil {
+			return errors.Errorf("this plugin is not a DNS provider")
+		}
+		dsp, err := s.plugin.NewDNSProvider(args.Creds, args.Meta)
+		if err != nil {
+			return err
+		}
+		s.dsp = dsp
+		reply.RecordTypes = s.plugin.RecordTypes
+		if _, ok := dsp.(providers.ZoneLister); ok {
+			reply.Features = append(reply.Features, "ListZones")
+		}
+		if _, ok := dsp.(providers.ZoneRecordLister); ok {
+			reply.Features = append(reply.Features, "GetZoneRecords")
+		}
+		if _, ok := dsp.(providers.DomainCreator); ok {
+			reply.Features = append(reply.Features, "EnsureDomainExists")
+		}
+	case "registrar":
+		if s.plugin.NewRegistrar == nil {
+			return errors.Errorf("this plugin is not a registrar")
+		}
+		r, err := s.plugin.NewRegistrar(args.Creds)
+		if err != nil {
+			return err
+		}
+		s.registrar = r
+		if _, ok := r.(providers.DomainStatusGetter); ok {
+			reply.Features = append(reply.Features, "GetDomainStatus")
+		}
+		if _, ok := r.(providers.RegistrarLocker); ok {
+			reply.Features = append(reply.Features, "SetRegistrarLock")
+		}
+		if _, ok := r.(providers.ContactManager); ok {
+			reply.Features = append(reply.Features, "Contacts")
+		}
+		if _, ok := r.(providers.RegistrarDNSSEC); ok {
+			reply.Features = append(reply.Features, "DSRecords")
+		}
+	default:
+		return errors.Errorf("unknown kind %q", args.Kind)
+	}
+	sort.Strings(reply.Features)
+	return nil
+}
+
+// keep stores corrections to be run by RunCorrection.
+func (s *Server) keep(corrections []*models.Correction, reply *[]Correction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.corrections == nil {
+		s.corrections = map[int]*models.Correction{}
+	}
+	for _, c := range corrections {
+		s.nextID++
+		s.corrections[s.nextID] = c
+		*reply = append(*reply, Correction{ID: s.nextID, Msg: c.Msg, Run: c.F != nil})
+	}
+}
+
+// GetNameservers returns the nameservers of a domain.
+func (s *Server) GetNameservers(domain string, reply *[]string) error {
+	if s.dsp == nil {
+		return errors.Errorf("not configured as a DNS provider")
+	}
+	ns, err := s.dsp.GetNameservers(domain)
+	for _, n := range ns {
+		*reply = append(*reply, n.Name)
+	}
+	return err
+}
+
+// GetDomainCorrections returns the corrections of a domain.
+func (s *Server) GetDomainCorrections(dc *models.DomainConfig, reply *[]Correction) error {
+	if s.dsp == nil {
+		return errors.Errorf("not configured as a DNS provider")
+	}
+	fixLabels(dc.Records, dc.Name)
+	corrections, err := s.dsp.GetDomainCorrections(dc)
+	s.keep(corrections, reply)
+	return err
+}
+
+// GetRegistrarCorrections returns the registrar corrections of a domain.
+func (s *Server) GetRegistrarCorrections(dc *models.DomainConfig, reply *[]Correction) error {
+	if s.registrar == nil {
+		return errors.Errorf("not configured as a registrar")
+	}
+	fixLabels(dc.Records, dc.Name)
+	corrections, err := s.registrar.GetRegistrarCorrections(dc)
+	s.keep(corrections, reply)
+	return err
+}
+
+// RunCorrection runs a correction returned earlier.
+func (s *Server) RunCorrection(id int, reply *bool) error {
+	s.mu.Lock()
+	c, ok := s.corrections[id]
+	delete(s.corrections, id)
+	s.mu.Unlock()
+	if !ok {
+		return errors.Errorf("no correction %d", id)
+	}
+	if c.F == nil {
+		return nil
+	}
+	return c.F()
+}
+
+// ListZones lists the zones of the account.
+func (s *Server) ListZones(_ bool, reply *[]string) error {
+	l, ok := s.dsp.(providers.ZoneLister)
+	if !ok {
+		return errors.Errorf("ListZones is not implemented")
+	}
+	zones, err := l.ListZones()
+	*reply = zones
+	return err
+}
+
+// GetZoneRecords returns the records of a zone.
+func (s *Server) GetZoneRecords(domain string, reply *models.Records) error {
+	l, ok := s.dsp.(providers.ZoneRecordLister)
+	if !ok {
+		return errors.Errorf("GetZoneRecords is not implemented")
+	}
+	records, err := l.GetZoneRecords(domain)
+	*reply = records
+	return err
+}
+
+// GetDomainStatus returns when a domain expires, and whether it is locked.
+func (s *Server) GetDomainStatus(domain string, reply *models.DomainStatus) error {
+	g, ok := s.registrar.(providers.DomainStatusGetter)
+	if !ok {
+		return errors.Errorf("GetDomainStatus is not implemented")
+	}
+	status, err := g.GetDomainStatus(domain)
+	if status != nil {
+		*reply = *status
+	}
+	return err
+}
+
+// SetRegistrarLock locks or unlocks a domain against transfers.
+func (s *Server) SetRegistrarLock(args SetRegistrarLockArgs, reply *bool) error {
+	l, ok := s.registrar.(providers.RegistrarLocker)
+	if !ok {
+		return errors.Errorf("SetRegistrarLock is not implemented")
+	}
+	return l.SetRegistrarLock(args.Domain, args.Locked)
+}
+
+// GetContacts returns the contacts of a domain, by role.
+func (s *Server) GetContacts(domain string, reply *map[string]*models.Contact) error {
+	cm, ok := s.registrar.(providers.ContactManager)
+	if !ok {
+		return errors.Errorf("GetContacts is not implemented")
+	}
+	contacts, err := cm.GetContacts(domain)
+	*reply = contacts
+	return err
+}
+
+// SetContacts updates the contacts of a domain.
+func (s *Server) SetContacts(args SetContactsArgs, reply *bool) error {
+	cm, ok := s.registrar.(providers.ContactManager)
+	if !ok {
+		return errors.Errorf("SetContacts is not implemented")
+	}
+	return cm.SetContacts(args.Domain, args.Contacts)
+}
+
+// GetDSRecords returns the DS records published for a domain.
+func (s *Server) GetDSRecords(domain string, reply *[]*models.DSRecord) error {
+	rd, ok := s.registrar.(providers.RegistrarDNSSEC)
+	if !ok {
+		return errors.Errorf("GetDSRecords is not implemented")
+	}
+	records, err := rd.GetDSRecords(domain)
+	*reply = records
+	return err
+}
+
+// AddDSRecord publishes a DS record for a domain.
+func (s *Server) AddDSRecord(args DSRecordArgs, reply *bool) error {
+	rd, ok := s.registrar.(providers.RegistrarDNSSEC)
+	if !ok {
+		return errors.Errorf("AddDSRecord is not implemented")
+	}
+	return rd.AddDSRecord(args.Domain, args.DS)
+}
+
+// RemoveDSRecord removes a DS record of a domain.
+func (s *Server) RemoveDSRecord(args DSRecordArgs, reply *bool) error {
+	rd, ok := s.registrar.(providers.RegistrarDNSSEC)
+	if !ok {
+		return errors.Errorf("RemoveDSRecord is not implemented")
+	}
+	return rd.RemoveDSRecord(args.Domain, args.DS)
+}
+
+// EnsureDomainExists creates a zone if it doesn't exist.
+func (s *Server) EnsureDomainExists(domain string, reply *bool) error {
+	c, ok := s.dsp.(providers.DomainCreator)
+	if !ok {
+		return errors.Errorf("EnsureDomainExists is not implemented")
+	}
+	return c.EnsureDomainExists(domain)
+}
@@ -2,7 +2,9 @@ package providers
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
+	"strings"
 
 	"github.com/StackExchange/dnscontrol/models"
 	"github.com/pkg/errors"
@@ -24,6 +26,189 @@ type DomainCreator interface {
 	EnsureDomainExists(domain string) error
 }
 
+// ZoneLister should be implemented by providers that can enumerate all the zones in an account.
+// Implement this only if the provider supports the `dnscontrol list-zones` command.
+type ZoneLister interface {
+	ListZones() ([]string, error)
+}
+
+// ZoneRecordLister should be implemented by providers that can return the records currently in a zone.
+// Implement this only if the provider can be the source of a REPLICATE_FROM() domain.
+type ZoneRecordLister interface {
+	GetZoneRecords(domain string) (models.Records, error)
+}
+
+// DomainStatusGetter should be implemented by registrars that can say when a domain expires, and whether it is locked.
+// Implement this only if the registrar supports the `dnscontrol check-expiry` command.
+type DomainStatusGetter interface {
+	GetDomainStatus(domain string) (*models.DomainStatus, error)
+}
+
+// ErrNotSupported is the cause of the errors of optional methods that a provider has but can't use, such as those of
+// the PLUGIN provider when the plugin binary doesn't implement them. Callers treat it like a provider without the method.
+var ErrNotSupported = errors.New("not supported")
+
+// RegistrarLocker should be implemented by registrars that can lock domains against transfers, along with DomainStatusGetter.
+// Implement this only if the registrar supports REGISTRAR_LOCK().
+type RegistrarLocker interface {
+	SetRegistrarLock(domain string, locked bool) error
+}
+
+// RegistrarLockCorrections returns the correction that locks or unlocks the domain of dc, if it uses REGISTRAR_LOCK() and
+// its lock at registrar r differs.
+func RegistrarLockCorrections(r Registrar, dc *models.DomainConfig) ([]*models.Correction, error) {
+	if dc.RegistrarLock == "" {
+		return nil, nil
+	}
+	getter, ok := r.(DomainStatusGetter)
+	locker, ok2 := r.(RegistrarLocker)
+	if !ok || !ok2 {
+		return nil, errors.Errorf("registrar %s can't lock domains, so %s can not use REGISTRAR_LOCK()", dc.RegistrarName, dc.Name)
+	}
+	status, err := getter.GetDomainStatus(dc.Name)
+	if err != nil {
+		return nil, err
+	}
+	if status.Locked == nil {
+		return nil, errors.Errorf("registrar %s can't tell whether %s is locked", dc.RegistrarName, dc.Name)
+	}
+	want := dc.RegistrarLock == "on"
+	if *status.Locked == want {
+		return nil, nil
+	}
+	state := map[bool]string{true: "on", false: "off"}
+	return []*models.Correction{{
+		Msg: fmt.Sprintf("Change transfer lock %s -> %s", state[*status.Locked], state[want]),
+		F:   func() error { return locker.SetRegistrarLock(dc.Name, want) },
+	}}, nil
+}
+
+// ContactManager should be implemented by registrars that can read and update the WHOIS contacts of domains.
+// Implement this only if the registrar supports CONTACTS(). SetContacts is given complete contacts, by role.
+type ContactManager interface {
+	GetContacts(domain string) (map[string]*models.Contact, error)
+	SetContacts(domain string, contacts map[string]*models.Contact) error
+}
+
+// ContactCorrections returns a correction for each contact role whose contact at registrar r differs from the one declared in
+// the metadata of dc.
+func ContactCorrections(r Registrar, dc *models.DomainConfig) ([]*models.Correction, error) {
+	want, err := dc.Contacts()
+	if err != nil || len(want) == 0 {
+		return nil, err
+	}
+	cm, ok := r.(ContactManager)
+	if !ok {
+		return nil, errors.Errorf("registrar %s can't manage contacts, so %s can not use CONTACTS()", dc.RegistrarName, dc.Name)
+	}
+	have, err := cm.GetContacts(dc.Name)
+	if err != nil {
+		return nil, err
+	}
+	var corrections []*models.Correction
+	for _, role := range models.ContactRoles {
+		w, ok := want[role]
+		if !ok {
+			continue
+		}
+		h := have[role]
+		if h == nil {
+			h = &models.Contact{}
+		}
+		diffs := h.Diff(w)
+		if len(diffs) == 0 {
+			continue
+		}
+		update := map[string]*models.Contact{role: h.Merge(w)}
+		corrections = append(corrections, &models.Correction{
+			Msg: fmt.Sprintf("Update %s contact: %s", role, strings.Join(diffs, ", ")),
+			F:   func() error { return cm.SetContacts(dc.Name, update) },
+		})
+	}
+	return corrections, nil
+}
+
+// ZoneSigner should be implemented by DNS providers that sign zones with DNSSEC, and can tell the DS records of their keys.
+// Implement this only if the provider supports REGISTRAR_DS_AUTO. GetZoneDSRecords returns no records if the zone isn't signed.
+type ZoneSigner interface {
+	GetZoneDSRecords(domain string) ([]*models.DSRecord, error)
+}
+
+// SignerDSRecords returns the DS records of the keys the DNS providers of dc sign it with, and the names of the providers
+// that can tell.
+func SignerDSRecords(dc *models.DomainConfig) (records []*models.DSRecord, signers []string, err error) {
+	seen := map[string]bool{}
+	for _, p := range dc.DNSProviderInstances {
+		zs, ok := p.Driver.(ZoneSigner)
+		if !ok {
+			continue
+		}
+		signers = append(signers, p.Name)
+		found, err := zs.GetZoneDSRecords(dc.Name)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "DNSSEC keys of %s at %s", dc.Name, p.Name)
+		}
+		for _, ds := range found {
+			if !seen[ds.String()] {
+				seen[ds.String()] = true
+				records = append(records, ds)
+			}
+		}
+	}
+	return records, signers, nil
+}
+
+// RegistrarDNSSEC should be implemented by registrars that can publish the DS records of domains.
+// Implement this only if the registrar supports REGISTRAR_DS().
+type RegistrarDNSSEC interface {
+	GetDSRecords(domain string) ([]*models.DSRecord, error)
+	AddDSRecord(domain string, ds *models.DSRecord) error
+	RemoveDSRecord(domain string, ds *models.DSRecord) error
+}
+
+// DSCorrections returns the corrections that make registrar r publish the DS records of dc, if it uses REGISTRAR_DS() or
+// REGISTRAR_DS_NONE. REGISTRAR_DS_AUTO must have been turned into one of them by the caller, with SignerDSRecords. New DS records are published before old ones are removed, so that the domain stays trusted during a
+// key rollover.
+func DSCorrections(r Registrar, dc *models.DomainConfig) ([]*models.Correction, error) {
+	if len(dc.RegistrarDS) == 0 && !dc.NoRegistrarDS {
+		return nil, nil
+	}
+	rd, ok := r.(RegistrarDNSSEC)
+	if !ok {
+		return nil, errors.Errorf("registrar %s can't publish DS records, so %s can not use REGISTRAR_DS()", dc.RegistrarName, dc.Name)
+	}
+	existing, err := rd.GetDSRecords(dc.Name)
+	if err != nil {
+		return nil, err
+	}
+	have := map[string]bool{}
+	for _, ds := range existing {
+		have[ds.String()] = true
+	}
+	want := map[string]bool{}
+	var corrections []*models.Correction
+	for _, ds := range dc.RegistrarDS {
+		ds := ds
+		want[ds.String()] = true
+		if !have[ds.String()] {
+			corrections = append(corrections, &models.Correction{
+				Msg: fmt.Sprintf("Publish DS %s", ds),
+				F:   func() error { return rd.AddDSRecord(dc.Name, ds) },
+			})
+		}
+	}
+	for _, ds := range existing {
+		ds := ds
+		if !want[ds.String()] {
+			corrections = append(corrections, &models.Correction{
+				Msg: fmt.Sprintf("Remove DS %s", ds),
+				F:   func() error { return rd.RemoveDSRecord(dc.Name, ds) },
+			})
+		}
+	}
+	return corrections, nil
+}
+
 // RegistrarInitializer is a function to create a registrar. Function will be passed the unprocessed json payload from the configuration file for the given provider.
 type RegistrarInitializer func(map[string]string) (Registrar, error)
 
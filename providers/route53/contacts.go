@@ -0,0 +1,100 @@
+package route53
+
+import (
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/aws/aws-sdk-go/aws"
+	r53d "github.com/aws/aws-sdk-go/service/route53domains"
+)
+
+// GetContacts returns the contacts of domain, by role.
+func (r *route53Provider) GetContacts(domain string) (map[string]*models.Contact, error) {
+	detail, err := r.getDomainDetail(domain)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]*models.Contact{
+		"registrant": fromContactDetail(detail.RegistrantContact),
+		"admin":      fromContactDetail(detail.AdminContact),
+		"tech":       fromContactDetail(detail.TechContact),
+	}, nil
+}
+
+// SetContacts updates the contacts of domain. The fields Route 53 has
+// and models.Contact doesn't, such as the contact type, are kept.
+func (r *route53Provider) SetContacts(domain string, contacts map[string]*models.Contact) error {
+	detail, err := r.getDomainDetail(domain)
+	if err != nil {
+		return err
+	}
+	input := &r53d.UpdateDomainContactInput{DomainName: &domain}
+	if c, ok := contacts["registrant"]; ok {
+		input.RegistrantContact = toContactDetail(detail.RegistrantContact, c)
+	}
+	if c, ok := contacts["admin"]; ok {
+		input.AdminContact = toContactDetail(detail.AdminContact, c)
+	}
+	if c, ok := contacts["tech"]; ok {
+		input.TechContact = toContactDetail(detail.TechContact, c)
+	}
+	withRetry(func() error {
+		_, err = r.registrar.UpdateDomainContact(input)
+		return err
+	})
+	return err
+}
+
+func (r *route53Provider) getDomainDetail(domain string) (*r53d.GetDomainDetailOutput, error) {
+	var detail *r53d.GetDomainDetailOutput
+	var err error
+	withRetry(func() error {
+		detail, err = r.registrar.GetDomainDetail(&r53d.GetDomainDetailInput{DomainName: &domain})
+		return err
+	})
+	return detail, err
+}
+
+func fromContactDetail(d *r53d.ContactDetail) *models.Contact {
+	if d == nil {
+		return &models.Contact{}
+	}
+	return &models.Contact{
+		FirstName:    aws.StringValue(d.FirstName),
+		LastName:     aws.StringValue(d.LastName),
+		Organization: aws.StringValue(d.OrganizationName),
+		Email:        aws.StringValue(d.Email),
+		Phone:        aws.StringValue(d.PhoneNumber),
+		Fax:          aws.StringValue(d.Fax),
+		Address1:     aws.StringValue(d.AddressLine1),
+		Address2:     aws.StringValue(d.AddressLine2),
+		City:         aws.StringValue(d.City),
+		State:        aws.StringValue(d.State),
+		PostalCode:   aws.StringValue(d.ZipCode),
+		Country:      aws.StringValue(d.CountryCode),
+	}
+}
+
+// toContactDetail returns d with the fields of c.
+func toContactDetail(d *r53d.ContactDetail, c *models.Contact) *r53d.ContactDetail {
+	detail := r53d.ContactDetail{}
+	if d != nil {
+		detail = *d
+	}
+	set := func(field **string, v string) {
+		if v != "" {
+			*field = aws.String(v)
+		}
+	}
+	set(&detail.FirstName, c.FirstName)
+	set(&detail.LastName, c.LastName)
+	set(&detail.OrganizationName, c.Organization)
+	set(&detail.Email, c.Email)
+	set(&detail.PhoneNumber, c.Phone)
+	set(&detail.Fax, c.Fax)
+	set(&detail.AddressLine1, c.Address1)
+	set(&detail.AddressLine2, c.Address2)
+	set(&detail.City, c.City)
+	set(&detail.State, c.State)
+	set(&detail.ZipCode, c.PostalCode)
+	set(&detail.CountryCode, c.Country)
+	return &detail
+}
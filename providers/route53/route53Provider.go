@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
 	"github.com/StackExchange/dnscontrol/providers"
 	"github.com/StackExchange/dnscontrol/providers/diff"
 	"github.com/aws/aws-sdk-go/aws"
@@ -51,7 +53,7 @@ func newRoute53(m map[string]string, metadata json.RawMessage) (*route53Provider
 
 	var dls *string = nil
 	if val, ok := m["DelegationSet"]; ok {
-		fmt.Printf("ROUTE53 DelegationSet %s configured\n", val)
+		printer.Printf("ROUTE53 DelegationSet %s configured\n", val)
 		dls = sPtr(val)
 	}
 	api := &route53Provider{client: r53.New(sess), registrar: r53d.New(sess), delegationSet: dls}
@@ -63,20 +65,23 @@ func newRoute53(m map[string]string, metadata json.RawMessage) (*route53Provider
 }
 
 var features = providers.DocumentationNotes{
-	providers.CanUseAlias:            providers.Cannot("R53 does not provide a generic ALIAS functionality. Use R53_ALIAS instead."),
-	providers.DocCreateDomains:       providers.Can(),
-	providers.DocDualHost:            providers.Can(),
-	providers.DocOfficiallySupported: providers.Can(),
-	providers.CanUsePTR:              providers.Can(),
-	providers.CanUseSRV:              providers.Can(),
-	providers.CanUseTXTMulti:         providers.Can(),
-	providers.CanUseCAA:              providers.Can(),
-	providers.CanUseRoute53Alias:     providers.Can(),
+	providers.CanCheckCredsWithNameservers: providers.Can(),
+	providers.CanUseAlias:                  providers.Cannot("R53 does not provide a generic ALIAS functionality. Use R53_ALIAS instead."),
+	providers.DocCreateDomains:             providers.Can(),
+	providers.DocDualHost:                  providers.Can(),
+	providers.DocOfficiallySupported:       providers.Can(),
+	providers.CanUsePTR:                    providers.Can(),
+	providers.CanUseSRV:                    providers.Can(),
+	providers.CanUseTXTMulti:               providers.Can(),
+	providers.CanUseCAA:                    providers.Can(),
+	providers.CanUseRoute53Alias:           providers.Can(),
+	providers.CanUseRoutingPolicy:          providers.Can(),
+	providers.CanPatchRecords:              providers.Can(),
 }
 
 func init() {
 	providers.RegisterDomainServiceProviderType("ROUTE53", newRoute53Dsp, features)
-	providers.RegisterRegistrarType("ROUTE53", newRoute53Reg)
+	providers.RegisterRegistrarType("ROUTE53", newRoute53Reg, providers.CanUseRegistrarLock)
 	providers.RegisterCustomRecordType("R53_ALIAS", "ROUTE53", "")
 }
 
@@ -99,7 +104,7 @@ func withRetry(f func() error) {
 			if currentRetry >= maxRetries {
 				return
 			}
-			fmt.Printf("============ Route53 rate limit exceeded. Waiting %s to retry.\n", sleepTime)
+			printer.Printf("============ Route53 rate limit exceeded. Waiting %s to retry.\n", sleepTime)
 			time.Sleep(sleepTime)
 		} else {
 			return
@@ -199,7 +204,7 @@ func (r *route53Provider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 	models.PostProcessRecords(existingRecords)
 
 	// diff
-	differ := diff.New(dc, getAliasMap)
+	differ := diff.New(dc, getAliasMap, models.RoutingMetadata)
 	namesToUpdate := differ.ChangedGroups(existingRecords)
 
 	if len(namesToUpdate) == 0 {
@@ -221,47 +226,74 @@ func (r *route53Provider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 	changes := []*r53.Change{}
 	changeDesc := ""
 	delDesc := ""
+	var changeRecords, delRecords []*models.RecordConfig
 	for k, recs := range updates {
-		chg := &r53.Change{}
-		var rrset *r53.ResourceRecordSet
+		// the record sets we have in r53: one, or one for each ROUTING() set.
+		var existing []*r53.ResourceRecordSet
+		for _, r := range records {
+			if unescape(r.Name) == k.NameFQDN && (*r.Type == k.Type || k.Type == "R53_ALIAS_"+*r.Type) {
+				existing = append(existing, r)
+			}
+		}
 		if len(recs) == 0 {
-			dels = append(dels, chg)
-			chg.Action = sPtr("DELETE")
+			if len(existing) == 0 {
+				return nil, fmt.Errorf("No record set found to delete. Name: '%s'. Type: '%s'", k.NameFQDN, k.Type)
+			}
 			delDesc += strings.Join(namesToUpdate[k], "\n") + "\n"
-			// on delete just submit the original resource set we got from r53.
-			for _, r := range records {
-				if unescape(r.Name) == k.NameFQDN && (*r.Type == k.Type || k.Type == "R53_ALIAS_"+*r.Type) {
-					rrset = r
-					break
-				}
+			delRecords = append(delRecords, recordsOf(k, existingRecords)...)
+			// on delete just submit the original resource sets we got from r53.
+			for _, rrset := range existing {
+				dels = append(dels, &r53.Change{Action: sPtr("DELETE"), ResourceRecordSet: rrset})
 			}
-			if rrset == nil {
-				return nil, fmt.Errorf("No record set found to delete. Name: '%s'. Type: '%s'", k.NameFQDN, k.Type)
+			continue
+		}
+		changeDesc += strings.Join(namesToUpdate[k], "\n") + "\n"
+		changeRecords = append(changeRecords, recordsOf(k, existingRecords)...)
+		changeRecords = append(changeRecords, recs...)
+		// on change or create, just build new record sets from our desired state
+		sets := map[string]*r53.ResourceRecordSet{}
+		var setIDs []string
+		for _, r := range recs {
+			setID := ""
+			routing := r.Routing()
+			if routing != nil {
+				setID = routing.Set
 			}
-		} else {
-			changes = append(changes, chg)
-			changeDesc += strings.Join(namesToUpdate[k], "\n") + "\n"
-			// on change or create, just build a new record set from our desired state
-			chg.Action = sPtr("UPSERT")
-			rrset = &r53.ResourceRecordSet{
-				Name: sPtr(k.NameFQDN),
-				Type: sPtr(k.Type),
+			rrset, ok := sets[setID]
+			if !ok {
+				rrset = &r53.ResourceRecordSet{
+					Name: sPtr(k.NameFQDN),
+					Type: sPtr(k.Type),
+				}
+				setIDs = append(setIDs, setID)
 			}
-			for _, r := range recs {
-				val := r.GetTargetCombined()
-				if r.Type != "R53_ALIAS" {
-					rr := &r53.ResourceRecord{
-						Value: &val,
-					}
-					rrset.ResourceRecords = append(rrset.ResourceRecords, rr)
-					i := int64(r.TTL)
-					rrset.TTL = &i // TODO: make sure that ttls are consistent within a set
-				} else {
-					rrset = aliasToRRSet(zone, r)
+			val := r.GetTargetCombined()
+			if r.Type != "R53_ALIAS" {
+				rr := &r53.ResourceRecord{
+					Value: &val,
 				}
+				rrset.ResourceRecords = append(rrset.ResourceRecords, rr)
+				i := int64(r.TTL)
+				rrset.TTL = &i // TODO: make sure that ttls are consistent within a set
+			} else {
+				rrset = aliasToRRSet(zone, r)
+			}
+			if routing != nil {
+				setRouting(rrset, routing)
+			}
+			sets[setID] = rrset
+		}
+		for _, setID := range setIDs {
+			changes = append(changes, &r53.Change{Action: sPtr("UPSERT"), ResourceRecordSet: sets[setID]})
+		}
+		// the sets we no longer want are deleted before the upserts, as r53
+		// doesn't allow a name and type to have both routed and plain sets.
+		for _, rrset := range existing {
+			if _, ok := sets[aws.StringValue(rrset.SetIdentifier)]; !ok {
+				dels = append(dels, &r53.Change{Action: sPtr("DELETE"), ResourceRecordSet: rrset})
+				delDesc += fmt.Sprintf("DELETE %s %s set %q\n", k.Type, k.NameFQDN, aws.StringValue(rrset.SetIdentifier))
 			}
 		}
-		chg.ResourceRecordSet = rrset
 	}
 
 	changeReq := &r53.ChangeResourceRecordSetsInput{
@@ -272,10 +304,11 @@ func (r *route53Provider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 		ChangeBatch: &r53.ChangeBatch{Changes: dels},
 	}
 
-	addCorrection := func(msg string, req *r53.ChangeResourceRecordSetsInput) {
+	addCorrection := func(msg string, records []*models.RecordConfig, req *r53.ChangeResourceRecordSetsInput) {
 		corrections = append(corrections,
 			&models.Correction{
-				Msg: msg,
+				Msg:     msg,
+				Records: records,
 				F: func() error {
 					var err error
 					req.HostedZoneId = zone.Id
@@ -289,17 +322,28 @@ func (r *route53Provider) GetDomainCorrections(dc *models.DomainConfig) ([]*mode
 	}
 
 	if len(dels) > 0 {
-		addCorrection(delDesc, delReq)
+		addCorrection(delDesc, delRecords, delReq)
 	}
 
 	if len(changes) > 0 {
-		addCorrection(changeDesc, changeReq)
+		addCorrection(changeDesc, changeRecords, changeReq)
 	}
 
 	return corrections, nil
 
 }
 
+// recordsOf returns those of records that have the name and type of k.
+func recordsOf(k models.RecordKey, records []*models.RecordConfig) []*models.RecordConfig {
+	var found []*models.RecordConfig
+	for _, r := range records {
+		if r.Key() == k {
+			found = append(found, r)
+		}
+	}
+	return found
+}
+
 func nativeToRecords(set *r53.ResourceRecordSet, origin string) []*models.RecordConfig {
 	results := []*models.RecordConfig{}
 	if set.AliasTarget != nil {
@@ -331,9 +375,69 @@ func nativeToRecords(set *r53.ResourceRecordSet, origin string) []*models.Record
 			}
 		}
 	}
+	if routing := getRouting(set); routing != nil {
+		for _, rc := range results {
+			rc.SetRouting(routing)
+		}
+	}
 	return results
 }
 
+// getRouting returns the ROUTING() of a weighted, geolocation or failover
+// record set, or nil if it is a plain one.
+func getRouting(set *r53.ResourceRecordSet) *models.Routing {
+	if set.SetIdentifier == nil {
+		return nil
+	}
+	routing := &models.Routing{
+		Set:         *set.SetIdentifier,
+		Failover:    strings.ToLower(aws.StringValue(set.Failover)),
+		HealthCheck: aws.StringValue(set.HealthCheckId),
+	}
+	if set.Weight != nil {
+		routing.Weight = strconv.FormatInt(*set.Weight, 10)
+	}
+	if geo := set.GeoLocation; geo != nil {
+		switch {
+		case geo.ContinentCode != nil:
+			routing.Geo = "continent:" + *geo.ContinentCode
+		case geo.SubdivisionCode != nil:
+			routing.Geo = aws.StringValue(geo.CountryCode) + "-" + *geo.SubdivisionCode
+		default:
+			routing.Geo = aws.StringValue(geo.CountryCode)
+		}
+	}
+	return routing
+}
+
+// setRouting makes rrset the set of routing.
+func setRouting(rrset *r53.ResourceRecordSet, routing *models.Routing) {
+	rrset.SetIdentifier = sPtr(routing.Set)
+	if routing.Weight != "" {
+		w, _ := strconv.ParseInt(routing.Weight, 10, 64) // checked by validation.
+		rrset.Weight = &w
+	}
+	if routing.Geo != "" {
+		geo := &r53.GeoLocation{}
+		switch {
+		case strings.HasPrefix(routing.Geo, "continent:"):
+			geo.ContinentCode = sPtr(strings.TrimPrefix(routing.Geo, "continent:"))
+		case strings.Contains(routing.Geo, "-"):
+			parts := strings.SplitN(routing.Geo, "-", 2)
+			geo.CountryCode, geo.SubdivisionCode = sPtr(parts[0]), sPtr(parts[1])
+		default:
+			geo.CountryCode = sPtr(routing.Geo)
+		}
+		rrset.GeoLocation = geo
+	}
+	if routing.Failover != "" {
+		rrset.Failover = sPtr(strings.ToUpper(routing.Failover))
+	}
+	if routing.HealthCheck != "" {
+		rrset.HealthCheckId = sPtr(routing.HealthCheck)
+	}
+}
+
 func getAliasMap(r *models.RecordConfig) map[string]string {
 	if r.Type != "R53_ALIAS" {
 		return nil
@@ -418,6 +522,39 @@ func (r *route53Provider) getRegistrarNameservers(domainName *string) ([]string,
 	return nameservers, nil
 }
 
+// GetDomainStatus returns when domain expires, and whether it is locked.
+func (r *route53Provider) GetDomainStatus(domain string) (*models.DomainStatus, error) {
+	domainDetail, err := r.getDomainDetail(domain)
+	if err != nil {
+		return nil, err
+	}
+	locked := false
+	for _, s := range domainDetail.StatusList {
+		if s != nil && *s == "clientTransferProhibited" {
+			locked = true
+		}
+	}
+	status := &models.DomainStatus{Locked: &locked, AutoRenew: domainDetail.AutoRenew}
+	if domainDetail.ExpirationDate != nil {
+		status.Expires = *domainDetail.ExpirationDate
+	}
+	return status, nil
+}
+
+// SetRegistrarLock locks or unlocks domain against transfers.
+func (r *route53Provider) SetRegistrarLock(domain string, locked bool) error {
+	var err error
+	withRetry(func() error {
+		if locked {
+			_, err = r.registrar.EnableDomainTransferLock(&r53d.EnableDomainTransferLockInput{DomainName: &domain})
+		} else {
+			_, err = r.registrar.DisableDomainTransferLock(&r53d.DisableDomainTransferLockInput{DomainName: &domain})
+		}
+		return err
+	})
+	return err
+}
+
 func (r *route53Provider) updateRegistrarNameservers(domainName string, nameservers []string) (*string, error) {
 	servers := []*r53d.Nameserver{}
 	for i := range nameservers {
@@ -443,13 +580,15 @@ func (r *route53Provider) fetchRecordSets(zoneID *string) ([]*r53.ResourceRecord
 	}
 	var next *string
 	var nextType *string
+	var nextID *string
 	var records []*r53.ResourceRecordSet
 	for {
 		listInput := &r53.ListResourceRecordSetsInput{
-			HostedZoneId:    zoneID,
-			StartRecordName: next,
-			StartRecordType: nextType,
-			MaxItems:        sPtr("100"),
+			HostedZoneId:          zoneID,
+			StartRecordName:       next,
+			StartRecordType:       nextType,
+			StartRecordIdentifier: nextID,
+			MaxItems:              sPtr("100"),
 		}
 		var list *r53.ListResourceRecordSetsOutput
 		var err error
@@ -465,6 +604,7 @@ func (r *route53Provider) fetchRecordSets(zoneID *string) ([]*r53.ResourceRecord
 		if list.NextRecordName != nil {
 			next = list.NextRecordName
 			nextType = list.NextRecordType
+			nextID = list.NextRecordIdentifier
 		} else {
 			break
 		}
@@ -487,9 +627,9 @@ func (r *route53Provider) EnsureDomainExists(domain string) error {
 		return nil
 	}
 	if r.delegationSet != nil {
-		fmt.Printf("Adding zone for %s to route 53 account with delegationSet %s\n", domain, *r.delegationSet)
+		printer.Printf("Adding zone for %s to route 53 account with delegationSet %s\n", domain, *r.delegationSet)
 	} else {
-		fmt.Printf("Adding zone for %s to route 53 account\n", domain)
+		printer.Printf("Adding zone for %s to route 53 account\n", domain)
 	}
 	in := &r53.CreateHostedZoneInput{
 		Name:            &domain,
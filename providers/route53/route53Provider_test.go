@@ -1,6 +1,11 @@
 package route53
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/models"
+	r53 "github.com/aws/aws-sdk-go/service/route53"
+)
 
 func TestUnescape(t *testing.T) {
 	var tests = []struct {
@@ -22,3 +27,27 @@ func TestUnescape(t *testing.T) {
 		}
 	}
 }
+
+func TestRouting(t *testing.T) {
+	var tests = []*models.Routing{
+		{Set: "a", Weight: "10"},
+		{Set: "b", Weight: "0", HealthCheck: "abcdef"},
+		{Set: "eu", Geo: "continent:EU"},
+		{Set: "de", Geo: "DE"},
+		{Set: "ca", Geo: "US-CA"},
+		{Set: "other", Geo: "*"},
+		{Set: "main", Failover: "primary"},
+		{Set: "backup", Failover: "secondary"},
+	}
+
+	for i, test := range tests {
+		rrset := &r53.ResourceRecordSet{}
+		setRouting(rrset, test)
+		if actual := getRouting(rrset); *actual != *test {
+			t.Errorf("%d: Expected %+v, got %+v", i, *test, *actual)
+		}
+	}
+	if getRouting(&r53.ResourceRecordSet{}) != nil {
+		t.Errorf("Expected no routing for a plain record set")
+	}
+}
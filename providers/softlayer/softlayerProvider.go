@@ -23,7 +23,8 @@ type SoftLayer struct {
 }
 
 var features = providers.DocumentationNotes{
-	providers.CanUseSRV: providers.Can(),
+	providers.CanUseSRV:       providers.Can(),
+	providers.CanPatchRecords: providers.Can(),
 }
 
 func init() {
@@ -74,23 +75,26 @@ func (s *SoftLayer) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Cor
 	for _, del := range delete {
 		existing := del.Existing.Original.(datatypes.Dns_Domain_ResourceRecord)
 		corrections = append(corrections, &models.Correction{
-			Msg: del.String(),
-			F:   s.deleteRecordFunc(*existing.Id),
+			Msg:     del.String(),
+			Records: del.Records(),
+			F:       s.deleteRecordFunc(*existing.Id),
 		})
 	}
 
 	for _, cre := range create {
 		corrections = append(corrections, &models.Correction{
-			Msg: cre.String(),
-			F:   s.createRecordFunc(cre.Desired, domain),
+			Msg:     cre.String(),
+			Records: cre.Records(),
+			F:       s.createRecordFunc(cre.Desired, domain),
 		})
 	}
 
 	for _, mod := range modify {
 		existing := mod.Existing.Original.(datatypes.Dns_Domain_ResourceRecord)
 		corrections = append(corrections, &models.Correction{
-			Msg: mod.String(),
-			F:   s.updateRecordFunc(&existing, mod.Desired),
+			Msg:     mod.String(),
+			Records: mod.Records(),
+			F:       s.updateRecordFunc(&existing, mod.Desired),
 		})
 	}
 
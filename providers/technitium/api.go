@@ -0,0 +1,192 @@
+package technitium
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The Technitium DNS Server HTTP API. See
+// https://github.com/TechnitiumSoftware/DnsServer/blob/master/APIDOCS.md
+
+type zone struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Internal bool   `json:"internal"`
+	Disabled bool   `json:"disabled"`
+}
+
+type record struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	TTL      uint32 `json:"ttl"`
+	Disabled bool   `json:"disabled"`
+	RData    rData  `json:"rData"`
+}
+
+// rData holds the type specific fields of a record. The API uses the
+// same names for the fields of a listed record and for the parameters
+// that add or delete one.
+type rData struct {
+	IPAddress  string `json:"ipAddress,omitempty"`
+	NameServer string `json:"nameServer,omitempty"`
+	Cname      string `json:"cname,omitempty"`
+	PtrName    string `json:"ptrName,omitempty"`
+	Exchange   string `json:"exchange,omitempty"`
+	Preference uint16 `json:"preference,omitempty"`
+	Text       string `json:"text,omitempty"`
+	Priority   uint16 `json:"priority,omitempty"`
+	Weight     uint16 `json:"weight,omitempty"`
+	Port       uint16 `json:"port,omitempty"`
+	Target     string `json:"target,omitempty"`
+	Flags      uint8  `json:"flags,omitempty"`
+	Tag        string `json:"tag,omitempty"`
+	Value      string `json:"value,omitempty"`
+	Aname      string `json:"aname,omitempty"`
+	Protocol   string `json:"protocol,omitempty"`
+	Forwarder  string `json:"forwarder,omitempty"`
+}
+
+// call invokes an API function and decodes its "response" into target (if not nil).
+// Parameters are sent as a form so that the token does not end up in a URL.
+func (api *Technitium) call(function string, params url.Values, target interface{}) error {
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("token", api.token)
+	resp, err := api.client.PostForm(api.baseURL+"/api/"+function, params)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("Technitium: bad status code %d calling %s", resp.StatusCode, function)
+	}
+	var r struct {
+		Status       string          `json:"status"`
+		ErrorMessage string          `json:"errorMessage"`
+		Response     json.RawMessage `json:"response"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return errors.Wrapf(err, "Technitium: decoding response of %s", function)
+	}
+	switch r.Status {
+	case "ok":
+	case "invalid-token":
+		return errors.Errorf("Technitium: invalid or expired token")
+	default:
+		return errors.Errorf("Technitium: %s: %s", function, r.ErrorMessage)
+	}
+	if target == nil || len(r.Response) == 0 {
+		return nil
+	}
+	return json.Unmarshal(r.Response, target)
+}
+
+func (api *Technitium) listZones() ([]*zone, error) {
+	var data struct {
+		Zones []*zone `json:"zones"`
+	}
+	if err := api.call("zones/list", nil, &data); err != nil {
+		return nil, err
+	}
+	return data.Zones, nil
+}
+
+// getZone returns the zone with the given name, or nil if it does not exist.
+func (api *Technitium) getZone(name string) (*zone, error) {
+	zones, err := api.listZones()
+	if err != nil {
+		return nil, err
+	}
+	for _, z := range zones {
+		if strings.EqualFold(z.Name, name) {
+			return z, nil
+		}
+	}
+	return nil, nil
+}
+
+func (api *Technitium) createZone(name string) error {
+	return api.call("zones/create", url.Values{"zone": {name}, "type": {"Primary"}}, nil)
+}
+
+func (api *Technitium) createForwarderZone(name string, fwd *rData) error {
+	return api.call("zones/create", url.Values{
+		"zone":      {name},
+		"type":      {"Forwarder"},
+		"protocol":  {fwd.Protocol},
+		"forwarder": {fwd.Forwarder},
+	}, nil)
+}
+
+func (api *Technitium) getRecords(domain string) ([]*record, error) {
+	var data struct {
+		Records []*record `json:"records"`
+	}
+	params := url.Values{"domain": {domain}, "zone": {domain}, "listZone": {"true"}}
+	if err := api.call("zones/records/get", params, &data); err != nil {
+		return nil, err
+	}
+	return data.Records, nil
+}
+
+func (api *Technitium) addRecord(domain string, r *record) error {
+	params := r.values()
+	params.Set("zone", domain)
+	params.Set("ttl", strconv.FormatUint(uint64(r.TTL), 10))
+	return api.call("zones/records/add", params, nil)
+}
+
+func (api *Technitium) deleteRecord(domain string, r *record) error {
+	params := r.values()
+	params.Set("zone", domain)
+	return api.call("zones/records/delete", params, nil)
+}
+
+// values returns the parameters that identify r when adding or deleting it. #rtype_variations
+func (r *record) values() url.Values {
+	v := url.Values{}
+	v.Set("domain", r.Name)
+	v.Set("type", r.Type)
+	d := r.RData
+	switch r.Type {
+	case "A", "AAAA":
+		v.Set("ipAddress", d.IPAddress)
+	case "ANAME":
+		v.Set("aname", d.Aname)
+	case "CAA":
+		v.Set("flags", strconv.Itoa(int(d.Flags)))
+		v.Set("tag", d.Tag)
+		v.Set("value", d.Value)
+	case "CNAME":
+		v.Set("cname", d.Cname)
+	case "FWD":
+		v.Set("protocol", d.Protocol)
+		v.Set("forwarder", d.Forwarder)
+	case "MX":
+		v.Set("exchange", d.Exchange)
+		v.Set("preference", strconv.Itoa(int(d.Preference)))
+	case "NS":
+		v.Set("nameServer", d.NameServer)
+	case "PTR":
+		v.Set("ptrName", d.PtrName)
+	case "SRV":
+		v.Set("priority", strconv.Itoa(int(d.Priority)))
+		v.Set("weight", strconv.Itoa(int(d.Weight)))
+		v.Set("port", strconv.Itoa(int(d.Port)))
+		v.Set("target", d.Target)
+	case "TXT":
+		v.Set("text", d.Text)
+	}
+	return v
+}
+
+func newClient() *http.Client {
+	return &http.Client{Timeout: 60 * time.Second}
+}
@@ -0,0 +1,376 @@
+package technitium
+
+/*
+
+Technitium DNS Server provider:
+
+	Uses the HTTP API of the server (usually on port 5380).
+
+Info required in `creds.json`:
+   - host (for example http://dns.example.com:5380)
+   - token (an API token; create one under Administration > Sessions)
+   - nameservers (optional; a comma separated list used for NAMESERVER records)
+
+Domain metadata:
+   - technitium_forwarder: a comma separated list of forwarders. If set,
+     the zone is a conditional forwarder zone rather than a primary zone.
+   - technitium_forwarder_protocol: Udp (the default), Tcp, Tls, Https or Quic.
+
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/StackExchange/dnscontrol/pkg/printer"
+	"github.com/StackExchange/dnscontrol/providers"
+	"github.com/StackExchange/dnscontrol/providers/diff"
+	"github.com/pkg/errors"
+)
+
+var features = providers.DocumentationNotes{
+	providers.CanUseAlias:            providers.Can("Technitium calls these ANAME records"),
+	providers.CanUseCAA:              providers.Can(),
+	providers.CanUsePTR:              providers.Can(),
+	providers.CanUseNAPTR:            providers.Cannot(),
+	providers.CanUseSRV:              providers.Can(),
+	providers.CanUseSSHFP:            providers.Cannot(),
+	providers.CanUseTLSA:             providers.Cannot(),
+	providers.CanUseTXTMulti:         providers.Cannot(),
+	providers.DocCreateDomains:       providers.Can(),
+	providers.DocOfficiallySupported: providers.Cannot(),
+}
+
+func init() {
+	providers.RegisterDomainServiceProviderType("TECHNITIUM", newTechnitium, features)
+}
+
+const (
+	metaForwarder         = "technitium_forwarder"
+	metaForwarderProtocol = "technitium_forwarder_protocol"
+)
+
+// Technitium is the handle for this provider.
+type Technitium struct {
+	baseURL     string
+	token       string
+	nameservers []*models.Nameserver
+	client      *http.Client
+}
+
+func newTechnitium(m map[string]string, metadata json.RawMessage) (providers.DNSServiceProvider, error) {
+	api := &Technitium{
+		baseURL: strings.TrimSuffix(m["host"], "/"),
+		token:   m["token"],
+		client:  newClient(),
+	}
+	if api.baseURL == "" || api.token == "" {
+		return nil, errors.Errorf("Technitium: host and token are required")
+	}
+	if m["nameservers"] != "" {
+		api.nameservers = models.StringsToNameservers(strings.Split(m["nameservers"], ","))
+	}
+	return api, nil
+}
+
+// GetNameservers returns the nameservers configured in creds.json, if any.
+func (api *Technitium) GetNameservers(domain string) ([]*models.Nameserver, error) {
+	return api.nameservers, nil
+}
+
+// EnsureDomainExists creates the zone as a primary zone if it does not exist.
+// Conditional forwarder zones are created by push instead, because the
+// forwarders are only known from the domain's metadata.
+func (api *Technitium) EnsureDomainExists(domain string) error {
+	z, err := api.getZone(domain)
+	if err != nil || z != nil {
+		return err
+	}
+	printer.Printf("Adding zone for %s to Technitium account\n", domain)
+	return api.createZone(domain)
+}
+
+// ListZones returns the names of all zones on the server, except the
+// built-in ones (localhost, the reverse zones of the loopback addresses).
+func (api *Technitium) ListZones() ([]string, error) {
+	zones, err := api.listZones()
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, z := range zones {
+		if !z.Internal {
+			names = append(names, z.Name)
+		}
+	}
+	return names, nil
+}
+
+// GetZoneRecords returns the records of a zone, except the SOA and the
+// forwarders of conditional forwarder zones.
+func (api *Technitium) GetZoneRecords(domain string) (models.Records, error) {
+	records, err := api.getRecords(domain)
+	if err != nil {
+		return nil, err
+	}
+	existing := models.Records{}
+	for _, r := range records {
+		if rc, ok := toRecordConfig(domain, r); ok {
+			existing = append(existing, rc)
+		}
+	}
+	models.PostProcessRecords(existing)
+	return existing, nil
+}
+
+// GetDomainCorrections returns a list of corrections to update a domain.
+func (api *Technitium) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
+	dc.Punycode()
+
+	forwarders, err := desiredForwarders(dc)
+	if err != nil {
+		return nil, err
+	}
+	z, err := api.getZone(dc.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	corrections := []*models.Correction{}
+	records := []*record{}
+	switch {
+	case z == nil && len(forwarders) == 0:
+		return nil, errors.Errorf("Technitium: zone %s does not exist. Run create-domains first", dc.Name)
+	case z == nil:
+		// The forwarder zone is created with its first forwarder.
+		first := forwarders[0]
+		forwarders = forwarders[1:]
+		corrections = append(corrections, &models.Correction{
+			Msg: fmt.Sprintf("Create conditional forwarder zone %s (forwarder %s %s)", dc.Name, first.Protocol, first.Forwarder),
+			F:   func() error { return api.createForwarderZone(dc.Name, first) },
+		})
+	case len(forwarders) > 0 && z.Type != "Forwarder":
+		return nil, errors.Errorf("Technitium: %s has %s set, but the zone is a %s zone. Delete the zone so it can be recreated", dc.Name, metaForwarder, z.Type)
+	case len(forwarders) == 0 && z.Type == "Forwarder":
+		return nil, errors.Errorf("Technitium: %s is a conditional forwarder zone, but %s is not set", dc.Name, metaForwarder)
+	default:
+		if records, err = api.getRecords(dc.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	// Without configured nameservers, the apex NS records are left alone.
+	if len(api.nameservers) == 0 {
+		dc.Filter(func(r *models.RecordConfig) bool {
+			return !(r.Type == "NS" && r.GetLabel() == "@")
+		})
+	}
+
+	existing := []*models.RecordConfig{}
+	existingForwarders := []*rData{}
+	for _, r := range records {
+		if r.Type == "FWD" {
+			if strings.EqualFold(r.Name, dc.Name) {
+				d := r.RData
+				existingForwarders = append(existingForwarders, &d)
+			}
+			continue
+		}
+		if r.Type == "NS" && len(api.nameservers) == 0 && strings.EqualFold(r.Name, dc.Name) {
+			continue
+		}
+		rc, ok := toRecordConfig(dc.Name, r)
+		if ok {
+			existing = append(existing, rc)
+		}
+	}
+	models.PostProcessRecords(existing)
+
+	fwdAdd, fwdDel := diffForwarders(existingForwarders, forwarders)
+	for _, f := range fwdDel {
+		r := &record{Name: dc.Name, Type: "FWD", RData: *f}
+		corrections = append(corrections, &models.Correction{
+			Msg: fmt.Sprintf("Remove forwarder %s %s", f.Protocol, f.Forwarder),
+			F:   func() error { return api.deleteRecord(dc.Name, r) },
+		})
+	}
+	for _, f := range fwdAdd {
+		r := &record{Name: dc.Name, Type: "FWD", TTL: models.DefaultTTL, RData: *f}
+		corrections = append(corrections, &models.Correction{
+			Msg: fmt.Sprintf("Add forwarder %s %s", f.Protocol, f.Forwarder),
+			F:   func() error { return api.addRecord(dc.Name, r) },
+		})
+	}
+
+	differ := diff.New(dc)
+	_, create, del, mod := differ.IncrementalDiff(existing)
+
+	for _, m := range del {
+		r := m.Existing.Original.(*record)
+		corrections = append(corrections, &models.Correction{
+			Msg:     m.String(),
+			Records: m.Records(),
+			F:       func() error { return api.deleteRecord(dc.Name, r) },
+		})
+	}
+	for _, m := range create {
+		r := toRecord(m.Desired)
+		corrections = append(corrections, &models.Correction{
+			Msg:     m.String(),
+			Records: m.Records(),
+			F:       func() error { return api.addRecord(dc.Name, r) },
+		})
+	}
+	for _, m := range mod {
+		old := m.Existing.Original.(*record)
+		r := toRecord(m.Desired)
+		corrections = append(corrections, &models.Correction{
+			Msg:     m.String(),
+			Records: m.Records(),
+			F: func() error {
+				if err := api.deleteRecord(dc.Name, old); err != nil {
+					return err
+				}
+				return api.addRecord(dc.Name, r)
+			},
+		})
+	}
+	return corrections, nil
+}
+
+// desiredForwarders returns the forwarders listed in the domain's metadata.
+func desiredForwarders(dc *models.DomainConfig) ([]*rData, error) {
+	list := dc.Metadata[metaForwarder]
+	if list == "" {
+		return nil, nil
+	}
+	protocol := dc.Metadata[metaForwarderProtocol]
+	switch strings.ToLower(protocol) {
+	case "":
+		protocol = "Udp"
+	case "udp", "tcp", "tls", "https", "quic":
+		protocol = strings.ToUpper(protocol[:1]) + strings.ToLower(protocol[1:])
+	default:
+		return nil, errors.Errorf("Technitium: invalid %s %q for %s", metaForwarderProtocol, protocol, dc.Name)
+	}
+	forwarders := []*rData{}
+	for _, f := range strings.Split(list, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			forwarders = append(forwarders, &rData{Protocol: protocol, Forwarder: f})
+		}
+	}
+	return forwarders, nil
+}
+
+// diffForwarders returns the forwarders that must be added and deleted
+// to go from existing to desired.
+func diffForwarders(existing, desired []*rData) (add, del []*rData) {
+	key := func(f *rData) string { return f.Protocol + " " + strings.ToLower(f.Forwarder) }
+	have := map[string]bool{}
+	for _, f := range existing {
+		have[key(f)] = true
+	}
+	want := map[string]bool{}
+	for _, f := range desired {
+		want[key(f)] = true
+		if !have[key(f)] {
+			add = append(add, f)
+		}
+	}
+	for _, f := range existing {
+		if !want[key(f)] {
+			del = append(del, f)
+		}
+	}
+	sort.Slice(del, func(i, j int) bool { return key(del[i]) < key(del[j]) })
+	return add, del
+}
+
+// toRecordConfig converts a Technitium record to a RecordConfig. It returns
+// false for records we don't manage (SOA, disabled records, unsupported types). #rtype_variations
+func toRecordConfig(origin string, r *record) (*models.RecordConfig, bool) {
+	if r.Disabled {
+		return nil, false
+	}
+	rc := &models.RecordConfig{
+		Type:     r.Type,
+		TTL:      r.TTL,
+		Original: r,
+	}
+	rc.SetLabelFromFQDN(r.Name, origin)
+	d := r.RData
+	var err error
+	switch r.Type {
+	case "A", "AAAA":
+		err = rc.SetTarget(d.IPAddress)
+	case "ANAME":
+		rc.Type = "ALIAS"
+		err = rc.SetTarget(fqdn(d.Aname))
+	case "CAA":
+		err = rc.SetTargetCAA(d.Flags, d.Tag, d.Value)
+	case "CNAME":
+		err = rc.SetTarget(fqdn(d.Cname))
+	case "MX":
+		err = rc.SetTargetMX(d.Preference, fqdn(d.Exchange))
+	case "NS":
+		err = rc.SetTarget(fqdn(d.NameServer))
+	case "PTR":
+		err = rc.SetTarget(fqdn(d.PtrName))
+	case "SRV":
+		err = rc.SetTargetSRV(d.Priority, d.Weight, d.Port, fqdn(d.Target))
+	case "TXT":
+		err = rc.SetTargetTXT(d.Text)
+	default:
+		return nil, false
+	}
+	if err != nil {
+		panic(errors.Wrap(err, "unparsable record received from Technitium"))
+	}
+	return rc, true
+}
+
+// toRecord converts a RecordConfig to a Technitium record. #rtype_variations
+func toRecord(rc *models.RecordConfig) *record {
+	r := &record{
+		Name: rc.GetLabelFQDN(),
+		Type: rc.Type,
+		TTL:  rc.TTL,
+	}
+	target := strings.TrimSuffix(rc.GetTargetField(), ".")
+	d := &r.RData
+	switch rc.Type {
+	case "A", "AAAA":
+		d.IPAddress = target
+	case "ALIAS":
+		r.Type = "ANAME"
+		d.Aname = target
+	case "CAA":
+		d.Flags, d.Tag, d.Value = rc.CaaFlag, rc.CaaTag, rc.GetTargetField()
+	case "CNAME":
+		d.Cname = target
+	case "MX":
+		d.Preference, d.Exchange = rc.MxPreference, target
+	case "NS":
+		d.NameServer = target
+	case "PTR":
+		d.PtrName = target
+	case "SRV":
+		d.Priority, d.Weight, d.Port, d.Target = rc.SrvPriority, rc.SrvWeight, rc.SrvPort, target
+	case "TXT":
+		d.Text = strings.Join(rc.TxtStrings, "")
+	default:
+		panic(errors.Errorf("Technitium: unsupported record type %s", rc.Type))
+	}
+	return r
+}
+
+func fqdn(s string) string {
+	if s == "" || strings.HasSuffix(s, ".") {
+		return s
+	}
+	return s + "."
+}
@@ -0,0 +1,89 @@
+package technitium
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/models"
+)
+
+const records = `[
+ {"disabled": false, "name": "example.com", "type": "SOA", "ttl": 900, "rData": {"primaryNameServer": "ns1.example.com"}},
+ {"disabled": false, "name": "example.com", "type": "A", "ttl": 3600, "rData": {"ipAddress": "192.0.2.1"}},
+ {"disabled": false, "name": "www.example.com", "type": "CNAME", "ttl": 3600, "rData": {"cname": "example.com"}},
+ {"disabled": false, "name": "example.com", "type": "MX", "ttl": 3600, "rData": {"preference": 10, "exchange": "mail.example.com"}},
+ {"disabled": false, "name": "_sip._tcp.example.com", "type": "SRV", "ttl": 3600, "rData": {"priority": 1, "weight": 2, "port": 5060, "target": "sip.example.com"}},
+ {"disabled": false, "name": "example.com", "type": "TXT", "ttl": 3600, "rData": {"text": "v=spf1 -all"}},
+ {"disabled": false, "name": "example.com", "type": "CAA", "ttl": 3600, "rData": {"flags": 0, "tag": "issue", "value": "letsencrypt.org"}},
+ {"disabled": false, "name": "alias.example.com", "type": "ANAME", "ttl": 3600, "rData": {"aname": "example.net"}},
+ {"disabled": true, "name": "old.example.com", "type": "A", "ttl": 3600, "rData": {"ipAddress": "192.0.2.2"}}
+]`
+
+func TestConversion(t *testing.T) {
+	var recs []*record
+	if err := json.Unmarshal([]byte(records), &recs); err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for _, r := range recs {
+		rc, ok := toRecordConfig("example.com", r)
+		if !ok {
+			if r.Type != "SOA" && !r.Disabled {
+				t.Errorf("%s %s was skipped", r.Name, r.Type)
+			}
+			continue
+		}
+		count++
+		back := toRecord(rc)
+		if back.Name != r.Name || back.Type != r.Type || back.TTL != r.TTL {
+			t.Errorf("%s %s: name/type/ttl mismatch: %+v", r.Name, r.Type, back)
+		}
+		if got, want := back.values().Encode(), r.values().Encode(); got != want {
+			t.Errorf("%s %s: expected %s, got %s", r.Name, r.Type, want, got)
+		}
+	}
+	if count != 7 {
+		t.Errorf("expected 7 records, got %d", count)
+	}
+}
+
+func TestDesiredForwarders(t *testing.T) {
+	dc := &models.DomainConfig{
+		Name: "corp.example",
+		Metadata: map[string]string{
+			metaForwarder:         "10.0.0.1, 10.0.0.2",
+			metaForwarderProtocol: "tcp",
+		},
+	}
+	fwds, err := desiredForwarders(dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fwds) != 2 || fwds[0].Forwarder != "10.0.0.1" || fwds[1].Forwarder != "10.0.0.2" || fwds[0].Protocol != "Tcp" {
+		t.Errorf("unexpected forwarders %+v %+v", fwds[0], fwds[1])
+	}
+
+	dc.Metadata[metaForwarderProtocol] = "carrier-pigeon"
+	if _, err := desiredForwarders(dc); err == nil {
+		t.Errorf("expected an error for an invalid protocol")
+	}
+}
+
+func TestDiffForwarders(t *testing.T) {
+	existing := []*rData{
+		{Protocol: "Udp", Forwarder: "10.0.0.1"},
+		{Protocol: "Udp", Forwarder: "10.0.0.9"},
+		{Protocol: "Udp", Forwarder: "10.0.0.8"},
+	}
+	desired := []*rData{
+		{Protocol: "Udp", Forwarder: "10.0.0.1"},
+		{Protocol: "Tcp", Forwarder: "10.0.0.2"},
+	}
+	add, del := diffForwarders(existing, desired)
+	if len(add) != 1 || add[0].Forwarder != "10.0.0.2" {
+		t.Errorf("unexpected additions %v", add)
+	}
+	if len(del) != 2 || del[0].Forwarder != "10.0.0.8" || del[1].Forwarder != "10.0.0.9" {
+		t.Errorf("unexpected deletions %v", del)
+	}
+}
@@ -30,6 +30,7 @@ var features = providers.DocumentationNotes{
 	providers.CanUseTLSA:             providers.Cannot(),
 	providers.DocCreateDomains:       providers.Can(),
 	providers.DocOfficiallySupported: providers.Cannot(),
+	providers.CanPatchRecords:        providers.Can(),
 }
 
 func init() {
@@ -108,7 +109,8 @@ func (api *VultrApi) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Co
 	for _, mod := range delete {
 		id := mod.Existing.Original.(*vultr.DNSRecord).RecordID
 		corrections = append(corrections, &models.Correction{
-			Msg: fmt.Sprintf("%s; Vultr RecordID: %v", mod.String(), id),
+			Msg:     fmt.Sprintf("%s; Vultr RecordID: %v", mod.String(), id),
+			Records: mod.Records(),
 			F: func() error {
 				return api.client.DeleteDNSRecord(dc.Name, id)
 			},
@@ -118,7 +120,8 @@ func (api *VultrApi) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Co
 	for _, mod := range create {
 		r := toVultrRecord(dc, mod.Desired)
 		corrections = append(corrections, &models.Correction{
-			Msg: mod.String(),
+			Msg:     mod.String(),
+			Records: mod.Records(),
 			F: func() error {
 				return api.client.CreateDNSRecord(dc.Name, r.Name, r.Type, r.Data, r.Priority, r.TTL)
 			},
@@ -130,7 +133,8 @@ func (api *VultrApi) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Co
 		r := toVultrRecord(dc, mod.Desired)
 		r.RecordID = id
 		corrections = append(corrections, &models.Correction{
-			Msg: fmt.Sprintf("%s; Vultr RecordID: %v", mod.String(), id),
+			Msg:     fmt.Sprintf("%s; Vultr RecordID: %v", mod.String(), id),
+			Records: mod.Records(),
 			F: func() error {
 				return api.client.UpdateDNSRecord(dc.Name, *r)
 			},
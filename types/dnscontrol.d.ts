@@ -0,0 +1,453 @@
+// Code generated by "go generate"; DO NOT EDIT.
+
+// TypeScript definitions of the functions of dnsconfig.js. See
+// https://stackexchange.github.io/dnscontrol/typescript
+
+/** A record or a setting of a domain, given to D(). */
+type DomainModifier = object;
+
+/** A setting of a record, given after the arguments of a record function such as A(). */
+type RecordModifier = object;
+
+/** underscore.js, which dnsconfig.js can use. */
+declare const _: any;
+
+/** A adds an A record To a domain. The name should be the relative label for the record. Use `@` for the domain apex. */
+declare function A(name: string, target: any, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** AAAA adds an AAAA record To a domain. The name should be the relative label for the record. Use `@` for the domain apex. */
+declare function AAAA(name: string, target: any, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** ALIAS is a virtual record type that points a record at another record. It is analogous to a CNAME, but is usually resolved at request-time and served as an A record. Unlike CNAMEs, ALIAS records can be used at the zone apex (`@`) */
+declare function ALIAS(name: string, target: any, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** ALIAS_FALLBACK makes an ALIAS record portable to DNS providers that don't support ALIAS. If any DNS provider of the domain can't use ALIAS records, dnscontrol resolves the target when it runs (`preview` or `push`) and replaces the ALIAS with the A and AAAA records of the target. All providers of that domain then get the A and AAAA records, so they serve the same data. Providers that support ALIAS, such as Cloudflare, keep the ALIAS record when they are the only ones serving the domain. */
+declare function ALIAS_FALLBACK(policy?: any): RecordModifier;
+
+/** `APPLY_TEMPLATE` adds the records and modifiers of the TEMPLATE `name` to the domain. The parameters in their strings, such as `${tenant}`, are replaced by the values of the object `params`, and `${domain}` by the name of the domain. A parameter without a value is an error. */
+declare function APPLY_TEMPLATE(name?: string, params?: { [param: string]: string | number }): DomainModifier;
+
+/** `ASSERT_EQUAL` fails a test of dnscontrol test unless `got` and `want` are equal. Objects and arrays are compared by their contents. The optional `message` is included in the error. */
+declare function ASSERT_EQUAL(got?: any, want?: any, message?: string): void;
+
+/** `ASSERT_RECORDS` fails a test of dnscontrol test unless the modifiers `got` add the same records as the modifiers `want`, in any order. Both are added to a domain named `domain`, `example.com` by default, as if they were given to `D()`. The error lists the records that are missing and those that are unexpected. */
+declare function ASSERT_RECORDS(got?: any, want?: any, domain?: string): void;
+
+/** `ASSERT_THROWS` fails a test of dnscontrol test unless calling `fn` throws an error whose message contains `text`. Without `text`, any error will do. */
+declare function ASSERT_THROWS(fn?: () => any, text?: string): void;
+
+/** The options of BIMI_BUILDER(). */
+interface BimiBuilderOptions {
+    /** The https URL of the logo, an SVG file. */
+    l?: any;
+    /** The https URL of the Verified Mark Certificate, a PEM file. (optional) */
+    a?: any;
+    /** The BIMI selector; the record is at <selector>._bimi. (default: 'default') */
+    selector?: any;
+    /** The DNS label of the domain the record is for. (default: '@') */
+    label?: any;
+    /** The time for TTL, integer or string. (default: not defined, using DefaultTTL) */
+    ttl?: number | string;
+}
+/** BIMI_BUILDER returns the records that options describe. */
+declare function BIMI_BUILDER(options: BimiBuilderOptions): DomainModifier[];
+
+/** CAA adds a CAA record to a domain. The name should be the relative label for the record. Use `@` for the domain apex. */
+declare function CAA(name: string, tag: string, value: string, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** The options of CAA_BUILDER(). A CA is a domain name, optionally followed by parameters ('ca.example; account=123'), or 'none' alone to allow no CA. */
+interface CaaBuilderOptions {
+    /** The DNS label for the CAA record. (default: '@') */
+    label?: any;
+    /** The contact URI, such as 'mailto:security@example.com'. Email addresses get mailto:. (optional) */
+    iodef?: any;
+    /** Boolean if sending report is required/critical. If not supported, certificate should be refused. (optional) */
+    iodef_critical?: boolean;
+    /** List of CAs which are allowed to issue certificates for the domain (creates one record for each). */
+    issue?: string | string[];
+    /** Allowed CAs which can issue wildcard certificates for this domain. (creates one record for each) */
+    issuewild?: string | string[];
+    /** Booleans to set the critical flag of the issue and issuewild records. (optional) */
+    issue_critical?: boolean;
+    /** Booleans to set the critical flag of the issue and issuewild records. (optional) */
+    issuewild_critical?: boolean;
+}
+/** CAA_BUILDER returns the records that options describe. */
+declare function CAA_BUILDER(options: CaaBuilderOptions): DomainModifier[];
+
+/** CAA_CRITICAL: Critical CAA flag */
+declare const CAA_CRITICAL: RecordModifier;
+
+/** CERT adds a CERT record (RFC 4398) to a domain. The name should be the relative label for the record. */
+declare function CERT(name: string, type: number, keytag: number, algorithm: number, target: string, ...modifiers: RecordModifier[]): DomainModifier;
+
+declare const CF_PROXY_DEFAULT_OFF: { cloudflare_proxy_default: 'off' };
+
+declare const CF_PROXY_DEFAULT_ON: { cloudflare_proxy_default: 'on' };
+
+/** Proxy+Railgun enabled. */
+declare const CF_PROXY_FULL: { cloudflare_proxy: 'full' };
+
+/** Proxy disabled. */
+declare const CF_PROXY_OFF: { cloudflare_proxy: 'off' };
+
+/** Proxy enabled. */
+declare const CF_PROXY_ON: { cloudflare_proxy: 'on' };
+
+/** `CF_REDIRECT` is the same as `CF_TEMP_REDIRECT` but generates a http 301 redirect (permanent redirect) instead of a temporary redirect. */
+declare function CF_REDIRECT(source: any, destination: any, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** `CF_REDIRECT` uses Cloudflare-specific features ("page rules") to generate an HTTP 301 redirect. */
+declare function CF_TEMP_REDIRECT(source: any, destination: any, ...modifiers: RecordModifier[]): DomainModifier;
+
+declare const CF_UNIVERSALSSL_OFF: { cloudflare_universalssl: 'off' };
+
+declare const CF_UNIVERSALSSL_ON: { cloudflare_universalssl: 'on' };
+
+/** `CIDR_HOSTS` returns the addresses of the hosts of an IPv4 or IPv6 block, in order. Those of an IPv4 block leave out its network and broadcast addresses, except in a /31 or /32, which have none. A block may have at most 65536 addresses (a /16 or a /112), so that a mistyped netmask doesn't make millions of records. */
+declare function CIDR_HOSTS(cidr: string): string[];
+
+/** `CLASSLESS_DELEGATE` delegates the reverse lookups of a block of IPv4 addresses smaller than a /24 (a /25 to a /31) to other nameservers, as RFC2317, "Classless in-addr.arpa delegation", describes. This is typically done by an ISP for a customer that has a few addresses. */
+declare function CLASSLESS_DELEGATE(cidr?: string, ...modifiers: any[]): DomainModifier;
+
+/** The variables given with -v key=value, and CLI_DEFAULTS(). */
+declare const CLI: { [key: string]: any };
+
+/** `CLI_DEFAULTS` gives the default values of the variables that can be set on the command line with `-v key=value`. They are the properties of the `CLI` object, so that one configuration can be pushed to staging and production without editing it: */
+declare function CLI_DEFAULTS(defaults?: { [key: string]: any }): void;
+
+/** CNAME adds a CNAME record to the domain. The name should be the relative label for the domain. Using `@` or `*` for CNAME records is not recommended, as different providers support them differently. */
+declare function CNAME(name: string, target: any, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** CONTACTS declares the WHOIS contacts of a domain, so that its registrar keeps them as `dnsconfig.js` says. This helps organizations that must keep their contacts accurate and consistent across many domains. */
+declare function CONTACTS(contacts?: any): DomainModifier;
+
+/** `CSYNC` adds a CSYNC record (RFC 7477) to a domain. A CSYNC record asks the parent zone to copy the listed records (usually NS, and glue A and AAAA records) from this zone. The name must be `"@"`. */
+declare function CSYNC(name: string, serial: number, flags: number, types: string | string[], ...modifiers: RecordModifier[]): DomainModifier;
+
+/** `D` adds a new Domain for DNSControl to manage. The first two arguments are required: the domain name (fully qualified `example.com` without a trailing dot), and the name of the registrar (as previously declared with NewRegistrar). Any number of additional arguments may be included to add DNS Providers with DNSProvider, add records with A, CNAME, and so forth, or add metadata. */
+declare function D(name?: string, registrar?: string, ...modifiers: any[]): void;
+
+/** `DEFAULTS` allows you to declare a set of default arguments to apply to all subsequent domains. Subsequent calls to D will have these arguments passed as if they were the first modifiers in the argument list. */
+declare function DEFAULTS(...modifiers: any[]): void;
+
+/** `DELEGATE` delegates the subdomain `name` to its own zone, served by `nameservers`. It adds an NS record for each nameserver and, for the nameservers that are inside this domain, their glue: the A and AAAA records that resolvers need to reach a nameserver whose name is in the zone it serves. */
+declare function DELEGATE(name?: string, ...modifiers: any[]): DomainModifier;
+
+/** `DHCID` adds a DHCID record (RFC 4701) to a domain. DHCP servers publish DHCID records next to the A and AAAA records they register, to tell which client owns a name. Managing them in DNSControl keeps names that are synced from DHCP from being taken over by another client. */
+declare function DHCID(name: string, target: any, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** DKIM adds the TXT record `selector._domainkey` that publishes a DKIM public key, `v=DKIM1; k=rsa; p=...`. The record is split into strings of at most 255 bytes, as keys of 2048 bits and more need, so the provider must support TXT records with multiple strings. */
+declare function DKIM(selector?: string, key?: string, ...modifiers: any[]): DomainModifier;
+
+declare function DKIM_KEY(key: string): { k: string; p: string };
+
+/** The options of DMARC_BUILDER(). */
+interface DmarcBuilderOptions {
+    /** The DNS label the policy is for; the record is at _dmarc.<label>. (default: '@') */
+    label?: any;
+    /** 'none', 'quarantine' or 'reject' (p=). */
+    policy?: 'none' | 'quarantine' | 'reject';
+    /** The policy of subdomains (sp=). (optional) */
+    subdomain_policy?: 'none' | 'quarantine' | 'reject';
+    /** 'strict' or 'relaxed' (adkim=, aspf=). (optional) */
+    alignment_dkim?: 'strict' | 'relaxed';
+    /** 'strict' or 'relaxed' (adkim=, aspf=). (optional) */
+    alignment_spf?: 'strict' | 'relaxed';
+    /** The percentage of messages the policy applies to, 0 to 100 (pct=). (optional) */
+    pct?: number;
+    /** The URIs aggregate and failure reports are sent to, or a list of them. Email addresses get mailto:. (optional) */
+    rua?: string | string[];
+    /** The URIs aggregate and failure reports are sent to, or a list of them. Email addresses get mailto:. (optional) */
+    ruf?: string | string[];
+    /** '0', '1', 'd' and 's', as a string such as '1:d' or a list (fo=). (optional) */
+    failure_options?: string | string[];
+    /** The format of failure reports (rf=). (optional) */
+    failure_format?: any;
+    /** The seconds between aggregate reports, or a duration such as '1d' (ri=). (optional) */
+    report_interval?: number | string;
+    /** The time for TTL, integer or string. (default: not defined, using DefaultTTL) */
+    ttl?: number | string;
+}
+/** DMARC_BUILDER returns the records that options describe. */
+declare function DMARC_BUILDER(options: DmarcBuilderOptions): DomainModifier[];
+
+/** DNAME adds a DNAME record to the domain. The name should be the relative label for the domain. A DNAME redirects every name *below* the label to the same name below the target; unlike a CNAME, the label itself may have other records. */
+declare function DNAME(name: string, target: any, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** `D_EXTEND` adds records and modifiers to a domain that was declared before with D. It takes the same modifiers as `D`, without the registrar. The records of all the `D_EXTEND` calls of a domain are merged with those of its `D` before anything is compared with the providers, so a large domain can be split across files, for example one per team, loaded with require. */
+declare function D_EXTEND(name?: string, ...modifiers: any[]): void;
+
+/** DefaultTTL sets the TTL for all records in a domain that do not explicitly set one with TTL. If neither `DefaultTTl` or `TTL` exist for a record, it will use the DNSControl global default of 300 seconds. */
+declare function DefaultTTL(v?: number | string, types?: string | string[]): DomainModifier;
+
+/** DnsProvider indicates that the specified provider should be used to manage records for this domain. The name must match the name used with NewDnsProvider. */
+declare function DnsProvider(name?: string, nsCount?: number): DomainModifier;
+
+/** ENSURE_ABSENT lists records that must not exist: DNSControl deletes them if they are in the zone, even in a domain with NO_PURGE. They are written like the records of the domain. A record matches if it has the same name, type and target, whatever its TTL. */
+declare function ENSURE_ABSENT(...records: any[]): DomainModifier;
+
+/** The environment variables allowed with --allow-env. */
+declare const ENV: { readonly [name: string]: string | undefined };
+
+/** `FETCH(url)` downloads `url` and returns its body as a string, so that lists of IP addresses or office ranges can come from an internal endpoint instead of being copied into `dnsconfig.js` by hand. Use `JSON.parse()` on the result if it is JSON. */
+declare function FETCH(url: string): string;
+
+/** Documentation needed. */
+declare function FRAME(name: string, target: any, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** HEALTH_CHECK gives providers without health checks a poor man's failover. When `preview` or `push` runs, it probes the target of each A or AAAA record that has a HEALTH_CHECK, and leaves the records that fail out of the zone. The other records with the same name and type (the round-robin pool) keep answering. A record that passes again is put back by the next `push`. */
+declare function HEALTH_CHECK(check?: string, timeout?: number | string): RecordModifier;
+
+/** `IGNORE(pattern)` is the old name of IGNORE_NAME(pattern): it leaves alone the records of all types whose label matches `pattern`. Use `IGNORE_NAME` in new configurations. */
+declare function IGNORE(name?: string): DomainModifier;
+
+/** `IGNORE_NAME` makes DNSControl leave alone the records whose label matches `pattern`: they are neither changed nor deleted, with any DNS provider. `types` limits it to some record types, as a comma separated list (`"TXT,MX"`) or a list (`["TXT", "MX"]`). By default, records of all types are left alone. */
+declare function IGNORE_NAME(pattern?: string, types?: string | string[]): DomainModifier;
+
+/** `IGNORE_TARGET` makes DNSControl leave alone the records whose target matches `pattern`, whatever their name: they are neither changed nor deleted, with any DNS provider. `types` limits it to some record types, as a comma separated list or a list. By default, records of all types are left alone. */
+declare function IGNORE_TARGET(pattern?: string, types?: string | string[]): DomainModifier;
+
+/** IGNORE_TTL keeps the TTLs that records have at the DNS provider: a TTL that differs from the one of `dnsconfig.js` is not a correction. It is for domains whose TTLs are tuned by hand, or forced by the provider, where correcting them would be a change at every `push`. */
+declare const IGNORE_TTL: { ignore_ttl: 'true' };
+
+/** Don't use this feature. It was added for a very specific situation at Stack Overflow. */
+declare function IMPORT_TRANSFORM(translation_table: any, domain: any, ttl: number, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** `IMPORT_ZONE` reads a standard BIND zone file when the configuration runs, and adds its records to the domain. It eases moving a legacy zone into DNSControl a piece at a time: import the zone file as it is, then move its records into `dnsconfig.js` one by one, deleting them from the file. */
+declare function IMPORT_ZONE(file?: string, ...modifiers: any[]): DomainModifier;
+
+/** Converts the IP address from string to an integer. This allows performing mathematical operations with the IP address. */
+declare function IP(dot?: string): number;
+
+/** `IPV6_EXPAND` returns an IPv6 address with all of its 32 hex digits, for example `IPV6_EXPAND('2001:db8::1')` returns `2001:0db8:0000:0000:0000:0000:0000:0001`. This helps when a name is made of the digits of an address, such as the labels of an `ip6.arpa` name. */
+declare function IPV6_EXPAND(address: string): string;
+
+/** `IP_ADD` returns the IPv4 or IPv6 address `n` addresses after `address`, or before it if `n` is negative. Unlike the arithmetic on `IP()`, it works for IPv6 too, and going past the last or first address is an error instead of a wrong address. */
+declare function IP_ADD(address: string, n: number): string;
+
+/** MAX_CHANGES makes `push` abort if it would change (create, delete or modify) more than n records of the domain at one DNS provider. The limits of all the domains are checked before any correction runs, so nothing is changed, at any domain. It overrides `push --max-changes` for the domain. See also MAX_DELETES. */
+declare function MAX_CHANGES(n?: number): DomainModifier;
+
+/** MAX_CNAME_CHAIN sets how many CNAMEs (and ALIAS records) in a row `dnscontrol check` accepts before it warns, instead of 3. Each lookup through a chain costs the resolver a round trip, and some resolvers give up on long chains. The chains are followed through the names of all the domains of `dnsconfig.js`; targets outside of it end the chain. */
+declare function MAX_CNAME_CHAIN(n?: number): DomainModifier;
+
+/** MAX_DELETES makes `push` abort if it would delete more than n records of the domain at one DNS provider, for example because a bad refactor of `dnsconfig.js` left most of the zone out. The limits of all the domains are checked before any correction runs, so nothing is changed, at any domain. It overrides `push --max-deletes` for the domain. See also MAX_CHANGES. */
+declare function MAX_DELETES(n?: number): DomainModifier;
+
+/** The options of MTA_STS_BUILDER(). */
+interface MtaStsBuilderOptions {
+    /** The DNS label of the domain the policy is for. (default: '@') */
+    label?: any;
+    /** 'enforce', 'testing' or 'none'. */
+    mode?: 'enforce' | 'testing' | 'none';
+    /** The MX host names the policy allows, or patterns such as '*.example.net'. */
+    mx?: string | string[];
+    /** true to allow the MX hosts of Microsoft 365, *.mail.protection.outlook.com. (optional) */
+    m365?: boolean;
+    /** How long senders cache the policy, in seconds or a duration such as '1w'. (default: '1w') */
+    max_age?: number | string;
+    /** Where the mta-sts host that serves the policy points: a host name (creates a CNAME) or IP addresses (create A and AAAA records). (optional) */
+    host?: string | string[];
+    /** The URIs TLS reports are sent to, or a list of them (creates the _smtp._tls TLSRPT record). Email addresses get mailto:. (optional) */
+    rua?: string | string[];
+    /** The id of the policy. (default: a hash of the policy, which changes when the policy does) */
+    id?: any;
+    /** The time for TTL, integer or string. (default: not defined, using DefaultTTL) */
+    ttl?: number | string;
+}
+/** MTA_STS_BUILDER returns the records that options describe. */
+declare function MTA_STS_BUILDER(options: MtaStsBuilderOptions): DomainModifier[];
+
+declare function MTA_STS_POLICY(mode: string, mx: string[], max_age: number): { policy: string; id: string };
+
+/** MX adds an MX record to the domain. */
+declare function MX(name: string, priority: number, target: string, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** `NAMESERVER()` instructs DNSControl to inform the domain's registrar where to find this zone. For some registrars this will also add NS records to the zone itself. */
+declare function NAMESERVER(name?: string, ...modifiers: any[]): DomainModifier;
+
+/** TTL sets the TTL on the domain apex NS RRs defined by NAMESERVER. */
+declare function NAMESERVER_TTL(v?: number | string): DomainModifier;
+
+/** NAPTR(name,order,preference,flags,service,regexp,target, recordModifiers...) */
+declare function NAPTR(name: string, order: number, preference: number, flags: string, service: string, regexp: string, target: string, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** NOTE says why a record exists, so that whoever reads the zone later knows whether it can go. It is kept in the metadata of the record, as `note`. */
+declare function NOTE(text?: string): RecordModifier;
+
+/** NO_PURGE indicates that records should not be deleted from a domain. Records will be added and updated, but not removed. */
+declare const NO_PURGE: DomainModifier;
+
+/** NS adds a NS record to the domain. The name should be the relative label for the domain. Use `@` for the domain apex, though if you are doing this consider using NAMESERVER() instead. */
+declare function NS(name: string, target: any, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** NewDnsProvider registers a new DNS Service Provider. The name can be any string value you would like to use. The type must match a valid dns provider type identifier (see provider page.) */
+declare function NewDnsProvider(name?: string, type?: string, meta?: object): string;
+
+/** NewRegistrar registers a registrar provider. The name can be any string value you would like to use. The type must match a valid registrar provider type identifier (see provider page.) */
+declare function NewRegistrar(name?: string, type?: string, meta?: object): string;
+
+/** OPENPGPKEY adds an OPENPGPKEY record (RFC 7929) to a domain. The record publishes the OpenPGP public key of a single mailbox. */
+declare function OPENPGPKEY(name: string, target: any, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** `OPENPGPKEY_NAME` returns the label of the OPENPGPKEY record for an email address, as described in RFC 7929: the SHA2-256 hash of the local part of the address, truncated to 28 octets and hex encoded, followed by `._openpgpkey`. For example `OPENPGPKEY_NAME("hugh@example.com")` returns `c93f1e400f26708f98cb19d936620da35eec8f72e57f9eec01c1afd6._openpgpkey`. */
+declare function OPENPGPKEY_NAME(address: string): string;
+
+/** OWNER lets several teams, or dnscontrol and other tools, share one zone safely, like the TXT registry of external-dns. With OWNER, DNSControl only deletes or modifies the record sets it owns, and leaves the others alone. */
+declare function OWNER(name?: string): DomainModifier;
+
+/** `PENDING_VERIFICATION` adds a TXT record that proves control of the domain to a SaaS service (Google Workspace, GitHub, Atlassian, ...). Service is a short lowercase name for the service, such as `"google"`; it only has to be unique within the domain. Token is the TXT value the service asks for. The record is at the apex, unless name is given. */
+declare function PENDING_VERIFICATION(service?: string, token?: string, ...modifiers: any[]): DomainModifier;
+
+/** `POP_DEFAULTS` restores the defaults that the last PUSH_DEFAULTS saved. It is an error if there is no `PUSH_DEFAULTS` to undo. */
+declare function POP_DEFAULTS(): void;
+
+/** PRIORITY_HINT controls the order in which `push` changes the records of a zone. A record hinted `"first"` is created, modified or deleted before all the other changes of its zone, whatever their kind; a record hinted `"last"` after them. Records that `push` deletes aren't in dnsconfig.js, so they take the hint of the records with the same name and type that replace them. The other changes keep the order of the provider, which usually makes all deletions, then all creations, then all modifications. */
+declare function PRIORITY_HINT(v?: 'first' | 'last'): RecordModifier;
+
+/** PROVIDERS sends a record only to some of the DNS providers of its domain, named as in `creds.json`. By default, every record goes to all the DNS providers of the domain. */
+declare function PROVIDERS(...names: string[]): RecordModifier;
+
+/** PTR adds a PTR record to the domain. */
+declare function PTR(name: string, target: any, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** PURGE is the default setting for all domains.  Therefore PURGE is a no-op, unless it comes after a NO_PURGE. That is useful when DEFAULTS sets NO_PURGE for most domains: */
+declare const PURGE: DomainModifier;
+
+/** `PUSH_DEFAULTS` saves the defaults of DEFAULTS, and adds the modifiers to them for the domains that follow, until POP_DEFAULTS restores the saved defaults. It lets a file that is loaded with `require()` give its domains their own defaults. It is an error if a configuration ends with a `PUSH_DEFAULTS` that has no `POP_DEFAULTS`. */
+declare function PUSH_DEFAULTS(...modifiers: any[]): void;
+
+/** R53_ALIAS is a Route53 specific virtual record type that points a record at either another record or an AWS entity (like a Cloudfront distribution, an ELB, etc...). It is analogous to a CNAME, but is usually resolved at request-time and served as an A record. Unlike CNAMEs, ALIAS records can be used at the zone apex (`@`) */
+declare function R53_ALIAS(name: string, type: any, target: string, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** R53_ZONE sets the required Route53 hosted zone id in a R53_ALIAS record. */
+declare function R53_ZONE(zone_id?: string): RecordModifier;
+
+/** REGISTRAR_DS declares a DS record that the registrar of the domain publishes in the parent zone, which makes the DNSSEC signatures of the domain trusted. Use it once per DS record; the parameters are those of the DS record, as the DNS provider that signs the zone shows them. */
+declare function REGISTRAR_DS(keytag?: number, algorithm?: number, digesttype?: number, digest?: string): DomainModifier;
+
+/** REGISTRAR_DS_AUTO makes the registrar of the domain publish the DS records of the keys its DNS providers sign it with. It saves copying DS records from the DNS provider to the registrar by hand when DNSSEC is turned on or a key is rolled over, which is the step of DNSSEC most prone to mistakes. */
+declare const REGISTRAR_DS_AUTO: DomainModifier;
+
+/** REGISTRAR_DS_NONE makes the registrar of the domain remove all its DS records, for example after turning DNSSEC off. Remove them before the DNS provider stops signing the zone: a DS record without signatures makes the domain fail to resolve. */
+declare const REGISTRAR_DS_NONE: DomainModifier;
+
+/** REGISTRAR_LOCK manages the transfer lock of a domain at its registrar, which stops the domain from being transferred to another registrar. `state` is `"on"` to lock the domain, or `"off"` to unlock it, for example before a transfer. */
+declare function REGISTRAR_LOCK(state?: 'on' | 'off'): DomainModifier;
+
+/** REPLICATE_FROM makes a domain a copy of a zone served by another DNS provider. Instead of listing records in `dnsconfig.js`, DNSControl reads the records the source provider currently serves and pushes them to the domain's other DNS providers. */
+declare function REPLICATE_FROM(name?: string): DomainModifier;
+
+/** `REV` returns the reverse lookup domain for an IP network. For example `REV('1.2.3.0/24')` returns `3.2.1.in-addr.arpa.` and `REV('2001:db8:302::/48)` returns `2.0.3.0.8.b.d.0.1.0.0.2.ip6.arpa.`. This is used in `D()` functions to create reverse DNS lookup zones. */
+declare function REV(address: string): string;
+
+/** ROUTING puts a record in a set of a weighted, geo or failover routing policy. The records of a name and type that have the same `set` are one record set of the provider, which answers each query with one of the sets. If one record of a name and type has a ROUTING, all of them must. */
+declare function ROUTING(options?: { set: string; weight?: number; geo?: string; failover?: 'primary' | 'secondary'; health_check?: string }): RecordModifier;
+
+/** `RP` adds a Responsible Person record (RFC 1183) to a domain. It tells who to contact about a name, for organizations that must publish that on their internal or public zones. */
+declare function RP(name: string, mbox: string, txt: string, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** SMIMEA adds an SMIMEA record (RFC 8162) to a domain. The record has the same fields as a TLSA record, but publishes the S/MIME certificate of a single mailbox. */
+declare function SMIMEA(name: string, usage: number, selector: number, matchingtype: number, target: string, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** `SMIMEA_NAME` returns the label of the SMIMEA record for an email address, as described in RFC 8162: the SHA2-256 hash of the local part of the address, truncated to 28 octets and hex encoded, followed by `._smimecert`. For example `SMIMEA_NAME("hugh@example.com")` returns `c93f1e400f26708f98cb19d936620da35eec8f72e57f9eec01c1afd6._smimecert`. */
+declare function SMIMEA_NAME(address: string): string;
+
+/** `SOA` sets the SOA record of a domain. The name must be `"@"`. */
+declare function SOA(name: string, mname: string, rname: string, refresh: number, retry: number, expire: number, minimum: number, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** The options of SPF_BUILDER(). */
+interface SpfBuilderOptions {
+    /** The parts of the SPF record (to be joined with ' '). */
+    parts?: string[];
+    /** The DNS label for the primary SPF record. (default: '@') */
+    label?: any;
+    /** Where (which label) to store an unaltered version of the SPF settings. */
+    raw?: any;
+    /** The time for TTL, integer or string. (default: not defined, using DefaultTTL) */
+    ttl?: number | string;
+    /** The template for additional records to be created, such as '_spf%d'. (default: no splitting) */
+    overflow?: any;
+    /** A list of domains to be flattened. */
+    flatten?: string[];
+    /** Only flatten if the record needs more DNS lookups than this. (default: always flatten) */
+    maxLookups?: number;
+}
+/** SPF_BUILDER returns the records that options describe. */
+declare function SPF_BUILDER(options: SpfBuilderOptions): DomainModifier[];
+
+/** `SRV` adds a `SRV` record to a domain. The name should be the relative label for the record. */
+declare function SRV(name: string, priority: number, weight: number, port: number, target: string, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** SSHFP contains a fingerprint of a SSH server which can be validated before SSH clients are establishing the connection. */
+declare function SSHFP(name: string, algorithm: number, fingerprint: number, value: string, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** The options of SSHFP_BUILDER(). */
+interface SshfpBuilderOptions {
+    /** The DNS label for the SSHFP records. (default: '@') */
+    label?: any;
+    /** An OpenSSH public key file or known_hosts file, or a list of them (creates one record per key). Names starting with '.' are relative to the current file, as for require(). */
+    file?: string | string[];
+    /** Only use the known_hosts entries of this host. (optional) */
+    host?: any;
+    /** The fingerprint type, 1 for SHA-1 and 2 for SHA-256, or a list of them. (default: 2) */
+    type?: number | number[];
+}
+/** SSHFP_BUILDER returns the records that options describe. */
+declare function SSHFP_BUILDER(options: SshfpBuilderOptions): DomainModifier[];
+
+declare function SSHFP_HASH(file: string, host: string, types: number[]): string[][];
+
+/** `TEMPLATE` declares a set of records and modifiers, such as those that a mail or office suite needs, that APPLY_TEMPLATE adds to domains. This declares them once, instead of in each domain that uses them. */
+declare function TEMPLATE(name?: string, ...modifiers: any[]): void;
+
+/** TLSA adds a TLSA record to a domain. The name should be the relative label for the record. */
+declare function TLSA(name: string, usage: number, selector: number, matchingtype: number, target: string, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** The options of TLSA_BUILDER(). */
+interface TlsaBuilderOptions {
+    /** The DNS label for the TLSA records, such as '_443._tcp.www'. (default: '_443._tcp') */
+    label?: any;
+    /** PEM file with the certificate or public key, or a list of them (creates one record for each). Names starting with '.' are relative to the current file, as for require(). */
+    file?: string | string[];
+    /** The certificate usage. (default: 3, DANE-EE) */
+    usage?: number;
+    /** 0 for the full certificate, 1 for the public key. (default: 1) */
+    selector?: number;
+    /** 0 for no hash, 1 for SHA-256, 2 for SHA-512. (default: 1) */
+    matchingtype?: number;
+}
+/** TLSA_BUILDER returns the records that options describe. */
+declare function TLSA_BUILDER(options: TlsaBuilderOptions): DomainModifier[];
+
+declare function TLSA_HASH(file: string, selector: number, matchingtype: number): string;
+
+/** TTL sets the TTL for a single record only. This will take precedence over the domain's DefaultTTL if supplied. */
+declare function TTL(v?: number | string): RecordModifier;
+
+/** `TTL_POLICY` sets an org-wide policy for the TTLs that make failover behave predictably: the TTL of NS records and the negative caching TTL (the SOA minimum). It applies to all domains. Each setting can be an integer or a string; see TTL for examples. Settings that are left out are not checked. */
+declare function TTL_POLICY(policy?: { ns_ttl?: number | string; negative_ttl?: number | string }): void;
+
+/** TXT adds an TXT record To a domain. The name should be the relative label for the record. Use `@` for the domain apex. */
+declare function TXT(name: string, target: string | string[], ...modifiers: RecordModifier[]): DomainModifier;
+
+/** `UNKNOWN` adds a record of a type DNSControl doesn't model, for example a new or experimental type. Type is the rtype number, and rdata the record data in the hex format of RFC 3597 (spaces are ignored). DNSControl compares these records by their rdata only. */
+declare function UNKNOWN(name: string, type: number, rdata: string, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** `URI` adds a URI record (RFC 7553) to a domain. The name should be the relative label for the record, usually `_service._proto` like an SRV record. */
+declare function URI(name: string, priority: number, weight: number, target: string, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** Documentation needed. */
+declare function URL(name: string, target: any, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** Documentation needed. */
+declare function URL301(name: string, target: any, ...modifiers: RecordModifier[]): DomainModifier;
+
+/** `WITH_DEFAULTS` adds the modifiers to the defaults of the domains that the function `fn` declares, as PUSH_DEFAULTS does, and restores the defaults when `fn` returns. Domains after it get the defaults that they had before, even if `fn` called DEFAULTS. Calls of `WITH_DEFAULTS` may be nested: the inner modifiers come after the outer ones, so they win. */
+declare function WITH_DEFAULTS(...modifiers: any[]): void;
+
+declare function ZONE_RECORDS(file: string, origin: string): any[];
+
+/** `require(...)` behaves similarly to its equivalent in node.js. You can use it to split your configuration across multiple files. If the path starts with a `.`, it is calculated relative to the current file. For example: */
+declare function require(path: string): any;